@@ -0,0 +1,32 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"github.com/hajimehoshi/ebiten/v2/internal/accessibility"
+)
+
+// AnnounceText makes screen readers read the given text, e.g., when the selection of a menu item changes.
+//
+// AnnounceText works on macOS, iOS, Android, and browsers. On the other environments, AnnounceText does nothing.
+//
+// On browsers, the text is set to an invisible ARIA live region.
+//
+// On Android, the text is announced only when an accessibility service like TalkBack is enabled.
+//
+// AnnounceText is concurrent-safe.
+func AnnounceText(text string) {
+	accessibility.Announce(text)
+}
@@ -41,6 +41,7 @@ import (
 	"time"
 
 	"github.com/hajimehoshi/ebiten/v2/internal/hooks"
+	"github.com/hajimehoshi/ebiten/v2/internal/logger"
 )
 
 const (
@@ -70,6 +71,8 @@ type Context struct {
 
 	players map[*playerImpl]struct{}
 
+	deviceResetCallback func(err error)
+
 	m         sync.Mutex
 	semaphore chan struct{}
 }
@@ -88,6 +91,9 @@ var (
 // Other values might not work.
 // For example, 22050 causes error on Safari when decoding MP3.
 //
+// On browsers, audio is not available in a Web Worker. When a game runs in a Web Worker, the first use of a player
+// fails with an error, and the game terminates with the error.
+//
 // NewContext panics when an audio context is already created.
 func NewContext(sampleRate int) *Context {
 	theContextLock.Lock()
@@ -123,6 +129,10 @@ func NewContext(sampleRate int) *Context {
 			close(c.inited)
 		})
 
+		if err := c.resetDeviceIfNeeded(); err != nil {
+			return err
+		}
+
 		var err error
 		theContextLock.Lock()
 		if theContext != nil {
@@ -166,6 +176,52 @@ func (c *Context) error() error {
 	return c.playerFactory.error()
 }
 
+// resetDeviceIfNeeded reopens the audio device when the current device gets an error,
+// e.g., when the device is removed, and restarts the playing players from their positions.
+func (c *Context) resetDeviceIfNeeded() error {
+	origErr := c.playerFactory.error()
+	if origErr == nil {
+		return nil
+	}
+	if err := c.playerFactory.reopen(); err != nil {
+		return origErr
+	}
+	logger.Warn("audio device was reset", "error", origErr)
+
+	c.m.Lock()
+	players := make([]*playerImpl, 0, len(c.players))
+	for p := range c.players {
+		players = append(players, p)
+	}
+	f := c.deviceResetCallback
+	c.m.Unlock()
+
+	for _, p := range players {
+		// Play recreates the underlying player with the new device.
+		p.Play()
+	}
+
+	if f != nil {
+		f(origErr)
+	}
+	return nil
+}
+
+// SetDeviceResetCallback sets the callback that is called when the audio device is reset.
+//
+// When the audio device gets an error, e.g., when the output device like a USB headset is removed,
+// the context reopens the default device and the players resume playing from their positions.
+// Players whose sources are not io.Seeker might skip the data that was buffered in the removed device.
+// The callback is called with the error of the removed device after the device is reset.
+//
+// The callback is called from the game's Update goroutine.
+// If the device cannot be reopened, the error is reported as an error of the game instead.
+func (c *Context) SetDeviceResetCallback(callback func(err error)) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.deviceResetCallback = callback
+}
+
 func (c *Context) setReady() {
 	c.m.Lock()
 	c.ready = true
@@ -399,6 +455,31 @@ func (p *Player) SetVolume(volume float64) {
 	p.p.SetVolume(volume)
 }
 
+// NormalizationGainer is an optional interface for a source stream that knows the gain to normalize its loudness.
+//
+// The streams decoded by the vorbis and mp3 packages implement NormalizationGainer with their ReplayGain or R128
+// tags.
+type NormalizationGainer interface {
+	// NormalizationGain returns the gain in dB, and whether the gain is available or not.
+	NormalizationGain() (db float64, ok bool)
+}
+
+// IsNormalizationEnabled reports whether the loudness normalization is enabled.
+func (p *Player) IsNormalizationEnabled() bool {
+	return p.p.IsNormalizationEnabled()
+}
+
+// SetNormalizationEnabled sets whether the loudness normalization is enabled.
+//
+// When the normalization is enabled and the source stream implements NormalizationGainer, the gain is applied
+// in addition to the volume, so that players of streams from different sources have consistent loudness.
+// A positive gain is not applied to avoid clipping. Volume keeps returning the volume set by SetVolume.
+//
+// The normalization is disabled by default.
+func (p *Player) SetNormalizationEnabled(enabled bool) {
+	p.p.SetNormalizationEnabled(enabled)
+}
+
 type hook interface {
 	OnSuspendAudio(f func() error)
 	OnResumeAudio(f func() error)
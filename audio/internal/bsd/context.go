@@ -0,0 +1,123 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build freebsd || openbsd
+// +build freebsd openbsd
+
+// Package bsd provides audio output for the BSDs, where the ALSA driver of Oto is not available.
+// FreeBSD uses OSS and OpenBSD uses sndio.
+package bsd
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/hajimehoshi/ebiten/v2/audio/internal/mixer"
+)
+
+// device is an opened audio output device that accepts signed 16bit little endian samples.
+type device interface {
+	write(buf []byte) error
+	close() error
+}
+
+type Context struct {
+	mixer      *mixer.Mixer
+	device     device
+	channelNum int
+
+	suspended bool
+	cond      *sync.Cond
+	err       atomic.Value
+}
+
+func NewContext(sampleRate, channelNum, bitDepthInBytes int) (*Context, chan struct{}, error) {
+	d, err := openDevice(sampleRate, channelNum)
+	if err != nil {
+		return nil, nil, err
+	}
+	c := &Context{
+		mixer:      mixer.New(sampleRate, channelNum, bitDepthInBytes),
+		device:     d,
+		channelNum: channelNum,
+		cond:       sync.NewCond(&sync.Mutex{}),
+	}
+	go c.loop(sampleRate)
+	ready := make(chan struct{})
+	close(ready)
+	return c, ready, nil
+}
+
+func (c *Context) NewPlayer(src io.Reader) *mixer.Player {
+	return c.mixer.NewPlayer(src)
+}
+
+func (c *Context) Suspend() error {
+	c.cond.L.Lock()
+	defer c.cond.L.Unlock()
+	c.suspended = true
+	return nil
+}
+
+func (c *Context) Resume() error {
+	c.cond.L.Lock()
+	defer c.cond.L.Unlock()
+	c.suspended = false
+	c.cond.Signal()
+	return nil
+}
+
+func (c *Context) Err() error {
+	if err := c.err.Load(); err != nil {
+		return err.(error)
+	}
+	return nil
+}
+
+func (c *Context) waitForResume() {
+	c.cond.L.Lock()
+	defer c.cond.L.Unlock()
+	for c.suspended {
+		c.cond.Wait()
+	}
+}
+
+func (c *Context) loop(sampleRate int) {
+	// Write about 1/60[s] at a time. The device blocks until it can accept more samples.
+	n := sampleRate / 60 * c.channelNum
+	fbuf := make([]float32, n)
+	buf := make([]byte, 2*n)
+	for {
+		c.waitForResume()
+
+		c.mixer.Read(fbuf)
+		for i, f := range fbuf {
+			if f < -1 {
+				f = -1
+			}
+			if f > 1 {
+				f = 1
+			}
+			v := int16(f * (1<<15 - 1))
+			buf[2*i] = byte(v)
+			buf[2*i+1] = byte(v >> 8)
+		}
+		if err := c.device.write(buf); err != nil {
+			c.err.Store(err)
+			_ = c.device.close()
+			return
+		}
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bsd
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+// The values are taken from sys/soundcard.h.
+const (
+	_AFMT_S16_LE = 0x00000010
+
+	_SNDCTL_DSP_SPEED       = 0xc0045002
+	_SNDCTL_DSP_SETFMT      = 0xc0045005
+	_SNDCTL_DSP_CHANNELS    = 0xc0045006
+	_SNDCTL_DSP_SETFRAGMENT = 0xc004500a
+)
+
+type ossDevice struct {
+	fd int
+}
+
+func ioctl(fd int, request uintptr, value *int32) error {
+	_, _, e := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), request, uintptr(unsafe.Pointer(value)))
+	if e != 0 {
+		return e
+	}
+	return nil
+}
+
+func openDevice(sampleRate, channelNum int) (device, error) {
+	fd, err := syscall.Open("/dev/dsp", syscall.O_WRONLY|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, fmt.Errorf("bsd: opening /dev/dsp failed: %w", err)
+	}
+
+	d := &ossDevice{fd: fd}
+	if err := d.setup(sampleRate, channelNum); err != nil {
+		_ = d.close()
+		return nil, err
+	}
+	return d, nil
+}
+
+func (d *ossDevice) setup(sampleRate, channelNum int) error {
+	// The fragment size must be set before the format.
+	// Use 4 fragments of 2^11 bytes to keep the latency low.
+	fragment := int32(4<<16 | 11)
+	if err := ioctl(d.fd, _SNDCTL_DSP_SETFRAGMENT, &fragment); err != nil {
+		return fmt.Errorf("bsd: SNDCTL_DSP_SETFRAGMENT failed: %w", err)
+	}
+
+	format := int32(_AFMT_S16_LE)
+	if err := ioctl(d.fd, _SNDCTL_DSP_SETFMT, &format); err != nil {
+		return fmt.Errorf("bsd: SNDCTL_DSP_SETFMT failed: %w", err)
+	}
+	if format != _AFMT_S16_LE {
+		return fmt.Errorf("bsd: the device doesn't support signed 16bit little endian samples")
+	}
+
+	channels := int32(channelNum)
+	if err := ioctl(d.fd, _SNDCTL_DSP_CHANNELS, &channels); err != nil {
+		return fmt.Errorf("bsd: SNDCTL_DSP_CHANNELS failed: %w", err)
+	}
+	if int(channels) != channelNum {
+		return fmt.Errorf("bsd: the device doesn't support %d channels", channelNum)
+	}
+
+	speed := int32(sampleRate)
+	if err := ioctl(d.fd, _SNDCTL_DSP_SPEED, &speed); err != nil {
+		return fmt.Errorf("bsd: SNDCTL_DSP_SPEED failed: %w", err)
+	}
+	// OSS may choose a slightly different rate. Allow an error of 1%.
+	if diff := int(speed) - sampleRate; diff*100 > sampleRate || -diff*100 > sampleRate {
+		return fmt.Errorf("bsd: the device doesn't support the sample rate %d (got %d)", sampleRate, speed)
+	}
+	return nil
+}
+
+func (d *ossDevice) write(buf []byte) error {
+	for len(buf) > 0 {
+		n, err := syscall.Write(d.fd, buf)
+		if err == syscall.EINTR {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("bsd: writing to /dev/dsp failed: %w", err)
+		}
+		buf = buf[n:]
+	}
+	return nil
+}
+
+func (d *ossDevice) close() error {
+	return syscall.Close(d.fd)
+}
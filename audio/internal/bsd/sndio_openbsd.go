@@ -0,0 +1,76 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bsd
+
+// #cgo LDFLAGS: -lsndio
+//
+// #include <sndio.h>
+//
+// static struct sio_hdl* ebitenOpenSndio(unsigned int rate, unsigned int channels) {
+//   struct sio_hdl* hdl = sio_open(SIO_DEVANY, SIO_PLAY, 0);
+//   if (!hdl) {
+//     return NULL;
+//   }
+//
+//   struct sio_par par;
+//   sio_initpar(&par);
+//   par.bits = 16;
+//   par.sig = 1;
+//   par.le = 1;
+//   par.pchan = channels;
+//   par.rate = rate;
+//   par.appbufsz = rate / 15;
+//
+//   if (!sio_setpar(hdl, &par) || !sio_getpar(hdl, &par) ||
+//       par.bits != 16 || !par.sig || !par.le || par.pchan != channels ||
+//       !sio_start(hdl)) {
+//     sio_close(hdl);
+//     return NULL;
+//   }
+//   return hdl;
+// }
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+type sndioDevice struct {
+	hdl *C.struct_sio_hdl
+}
+
+func openDevice(sampleRate, channelNum int) (device, error) {
+	hdl := C.ebitenOpenSndio(C.uint(sampleRate), C.uint(channelNum))
+	if hdl == nil {
+		return nil, fmt.Errorf("bsd: opening a sndio device for %d[Hz] and %d channels failed", sampleRate, channelNum)
+	}
+	return &sndioDevice{hdl: hdl}, nil
+}
+
+func (d *sndioDevice) write(buf []byte) error {
+	if len(buf) == 0 {
+		return nil
+	}
+	if n := C.sio_write(d.hdl, unsafe.Pointer(&buf[0]), C.size_t(len(buf))); int(n) != len(buf) {
+		return fmt.Errorf("bsd: sio_write failed")
+	}
+	return nil
+}
+
+func (d *sndioDevice) close() error {
+	C.sio_close(d.hdl)
+	return nil
+}
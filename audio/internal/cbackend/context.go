@@ -20,32 +20,26 @@ package cbackend
 import (
 	"io"
 
+	"github.com/hajimehoshi/ebiten/v2/audio/internal/mixer"
 	"github.com/hajimehoshi/ebiten/v2/internal/cbackend"
 )
 
 type Context struct {
-	sampleRate      int
-	channelNum      int
-	bitDepthInBytes int
-
-	players *players
+	mixer *mixer.Mixer
 }
 
 func NewContext(sampleRate, channelNum, bitDepthInBytes int) (*Context, chan struct{}, error) {
 	c := &Context{
-		sampleRate:      sampleRate,
-		channelNum:      channelNum,
-		bitDepthInBytes: bitDepthInBytes,
-		players:         newPlayers(),
+		mixer: mixer.New(sampleRate, channelNum, bitDepthInBytes),
 	}
-	cbackend.OpenAudio(sampleRate, channelNum, c.players.read)
+	cbackend.OpenAudio(sampleRate, channelNum, c.mixer.Read)
 	ready := make(chan struct{})
 	close(ready)
 	return c, ready, nil
 }
 
-func (c *Context) NewPlayer(src io.Reader) *Player {
-	return newPlayer(c, src)
+func (c *Context) NewPlayer(src io.Reader) *mixer.Player {
+	return c.mixer.NewPlayer(src)
 }
 
 func (c *Context) Suspend() error {
@@ -61,7 +55,3 @@ func (c *Context) Resume() error {
 func (c *Context) Err() error {
 	return nil
 }
-
-func (c *Context) bufferSize() int {
-	return c.sampleRate * c.channelNum * c.bitDepthInBytes / 4 // 0.25[s]
-}
@@ -12,13 +12,11 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-//go:build ebitencbackend
-// +build ebitencbackend
-
 // TODO: This implementation is very similar to github.com/hajimehoshi/oto/v2's player_notjs.go
 // Unify them if possible.
 
-package cbackend
+// Package mixer provides a software mixer for audio backends that pull samples from a callback.
+package mixer
 
 import (
 	"io"
@@ -35,6 +33,40 @@ const (
 	playerClosed
 )
 
+// Mixer mixes the sources of its players into float32 samples.
+type Mixer struct {
+	sampleRate      int
+	channelNum      int
+	bitDepthInBytes int
+
+	players *players
+}
+
+// New creates a new Mixer.
+func New(sampleRate, channelNum, bitDepthInBytes int) *Mixer {
+	return &Mixer{
+		sampleRate:      sampleRate,
+		channelNum:      channelNum,
+		bitDepthInBytes: bitDepthInBytes,
+		players:         newPlayers(),
+	}
+}
+
+// NewPlayer creates a new player reading src.
+func (m *Mixer) NewPlayer(src io.Reader) *Player {
+	return newPlayer(m, src)
+}
+
+// Read fills buf with the mixed samples of the playing players.
+// The values are interleaved by channel.
+func (m *Mixer) Read(buf []float32) {
+	m.players.read(buf)
+}
+
+func (m *Mixer) bufferSize() int {
+	return m.sampleRate * m.channelNum * m.bitDepthInBytes / 4 // 0.25[s]
+}
+
 type players struct {
 	players map[*playerImpl]struct{}
 	buf     []float32
@@ -126,24 +158,24 @@ type Player struct {
 }
 
 type playerImpl struct {
-	context *Context
-	src     io.Reader
-	volume  float64
-	err     atomic.Value
-	state   playerState
-	tmpbuf  []byte
-	buf     []byte
-	eof     bool
+	mixer  *Mixer
+	src    io.Reader
+	volume float64
+	err    atomic.Value
+	state  playerState
+	tmpbuf []byte
+	buf    []byte
+	eof    bool
 
 	m sync.Mutex
 }
 
-func newPlayer(context *Context, src io.Reader) *Player {
+func newPlayer(mixer *Mixer, src io.Reader) *Player {
 	p := &Player{
 		p: &playerImpl{
-			context: context,
-			src:     src,
-			volume:  1,
+			mixer:  mixer,
+			src:    src,
+			volume: 1,
 		},
 	}
 	runtime.SetFinalizer(p, (*Player).Close)
@@ -179,7 +211,7 @@ func (p *playerImpl) Play() {
 
 func (p *playerImpl) ensureTmpBuf() []byte {
 	if p.tmpbuf == nil {
-		p.tmpbuf = make([]byte, p.context.bufferSize())
+		p.tmpbuf = make([]byte, p.mixer.bufferSize())
 	}
 	return p.tmpbuf
 }
@@ -194,7 +226,7 @@ func (p *playerImpl) playImpl() {
 
 	if !p.eof {
 		buf := p.ensureTmpBuf()
-		for len(p.buf) < p.context.bufferSize() {
+		for len(p.buf) < p.mixer.bufferSize() {
 			n, err := p.src.Read(buf)
 			if err != nil && err != io.EOF {
 				p.setErrorImpl(err)
@@ -215,7 +247,7 @@ func (p *playerImpl) playImpl() {
 	}
 
 	p.m.Unlock()
-	p.context.players.addPlayer(p)
+	p.mixer.players.addPlayer(p)
 	p.m.Lock()
 }
 
@@ -305,7 +337,7 @@ func (p *playerImpl) Close() error {
 
 func (p *playerImpl) closeImpl() error {
 	p.m.Unlock()
-	p.context.players.removePlayer(p)
+	p.mixer.players.removePlayer(p)
 	p.m.Lock()
 
 	if p.state == playerClosed {
@@ -327,7 +359,7 @@ func (p *playerImpl) readBufferAndAdd(buf []float32) int {
 		return 0
 	}
 
-	bitDepthInBytes := p.context.bitDepthInBytes
+	bitDepthInBytes := p.mixer.bitDepthInBytes
 	n := len(p.buf) / bitDepthInBytes
 	if n > len(buf) {
 		n = len(buf)
@@ -361,7 +393,7 @@ func (p *playerImpl) canReadSourceToBuffer() bool {
 	if p.eof {
 		return false
 	}
-	return len(p.buf) < p.context.bufferSize()
+	return len(p.buf) < p.mixer.bufferSize()
 }
 
 func (p *playerImpl) readSourceToBuffer() {
@@ -375,7 +407,7 @@ func (p *playerImpl) readSourceToBuffer() {
 		return
 	}
 
-	if len(p.buf) >= p.context.bufferSize() {
+	if len(p.buf) >= p.mixer.bufferSize() {
 		return
 	}
 
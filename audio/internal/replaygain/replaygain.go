@@ -0,0 +1,67 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package replaygain provides functions to read loudness normalization gains from tags.
+package replaygain
+
+import (
+	"strconv"
+	"strings"
+)
+
+// r128Offset is the difference between the reference levels of ReplayGain (-18 LUFS) and EBU R128 (-23 LUFS).
+const r128Offset = 5
+
+// Gain returns the gain in dB from the tags, whose keys must be in upper case.
+//
+// The track gain is preferred to the album gain. ReplayGain tags like REPLAYGAIN_TRACK_GAIN=-6.50 dB and
+// R128 tags like R128_TRACK_GAIN=-1664 used by Opus are supported. The returned gain is based on the reference
+// level of ReplayGain.
+func Gain(tags map[string]string) (float64, bool) {
+	for _, kind := range []string{"TRACK", "ALBUM"} {
+		if v, ok := tags["REPLAYGAIN_"+kind+"_GAIN"]; ok {
+			if g, ok := parseReplayGain(v); ok {
+				return g, true
+			}
+		}
+		if v, ok := tags["R128_"+kind+"_GAIN"]; ok {
+			// An R128 gain is a Q7.8 fixed-point number in dB.
+			if g, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+				return float64(g)/256 + r128Offset, true
+			}
+		}
+	}
+	return 0, false
+}
+
+func parseReplayGain(v string) (float64, bool) {
+	v = strings.TrimSpace(v)
+	if len(v) >= 2 && strings.EqualFold(v[len(v)-2:], "dB") {
+		v = strings.TrimSpace(v[:len(v)-2])
+	}
+	g, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, false
+	}
+	return g, true
+}
+
+// AddVorbisComment adds a Vorbis comment like KEY=value to the tags.
+func AddVorbisComment(tags map[string]string, comment string) {
+	i := strings.IndexByte(comment, '=')
+	if i < 0 {
+		return
+	}
+	tags[strings.ToUpper(comment[:i])] = comment[i+1:]
+}
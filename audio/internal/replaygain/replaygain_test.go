@@ -0,0 +1,68 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replaygain_test
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2/audio/internal/replaygain"
+)
+
+func TestGain(t *testing.T) {
+	cases := []struct {
+		Comments []string
+		Gain     float64
+		OK       bool
+	}{
+		{
+			Comments: nil,
+			OK:       false,
+		},
+		{
+			Comments: []string{"TITLE=Foo", "REPLAYGAIN_TRACK_GAIN=-6.50 dB"},
+			Gain:     -6.5,
+			OK:       true,
+		},
+		{
+			Comments: []string{"replaygain_album_gain=+1.25 dB", "replaygain_track_gain=-3 dB"},
+			Gain:     -3,
+			OK:       true,
+		},
+		{
+			Comments: []string{"REPLAYGAIN_ALBUM_GAIN=-2.00 dB"},
+			Gain:     -2,
+			OK:       true,
+		},
+		{
+			Comments: []string{"R128_TRACK_GAIN=-1664"},
+			Gain:     -1664.0/256 + 5,
+			OK:       true,
+		},
+		{
+			Comments: []string{"REPLAYGAIN_TRACK_GAIN=invalid"},
+			OK:       false,
+		},
+	}
+	for _, c := range cases {
+		tags := map[string]string{}
+		for _, comment := range c.Comments {
+			replaygain.AddVorbisComment(tags, comment)
+		}
+		g, ok := replaygain.Gain(tags)
+		if g != c.Gain || ok != c.OK {
+			t.Errorf("Gain(%v): got: (%f, %t), want: (%f, %t)", c.Comments, g, ok, c.Gain, c.OK)
+		}
+	}
+}
@@ -221,3 +221,12 @@ func (i *InfiniteLoop) Seek(offset int64, whence int) (int64, error) {
 	i.pos = next
 	return i.pos, nil
 }
+
+// NormalizationGain is implementation of NormalizationGainer's NormalizationGain.
+// NormalizationGain returns the source stream's gain if the source implements NormalizationGainer.
+func (i *InfiniteLoop) NormalizationGain() (db float64, ok bool) {
+	if g, ok := i.src.(NormalizationGainer); ok {
+		return g.NormalizationGain()
+	}
+	return 0, false
+}
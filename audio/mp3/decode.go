@@ -25,12 +25,15 @@ import (
 
 	"github.com/hajimehoshi/ebiten/v2/audio"
 	"github.com/hajimehoshi/ebiten/v2/audio/internal/convert"
+	"github.com/hajimehoshi/ebiten/v2/audio/internal/replaygain"
 )
 
 // Stream is a decoded stream.
 type Stream struct {
 	orig       *mp3.Decoder
 	resampling *convert.Resampling
+	gain       float64
+	hasGain    bool
 }
 
 // Read is implementation of io.Reader's Read.
@@ -57,6 +60,14 @@ func (s *Stream) Length() int64 {
 	return s.orig.Length()
 }
 
+// NormalizationGain is implementation of audio.NormalizationGainer's NormalizationGain.
+//
+// NormalizationGain returns the gain from the REPLAYGAIN_*_GAIN or R128_*_GAIN user-defined text frames of the ID3v2
+// tag.
+func (s *Stream) NormalizationGain() (db float64, ok bool) {
+	return s.gain, s.hasGain
+}
+
 // DecodeWithSampleRate decodes MP3 source and returns a decoded stream.
 //
 // DecodeWithSampleRate returns error when decoding fails or IO error happens.
@@ -68,6 +79,11 @@ func (s *Stream) Length() int64 {
 // A Stream doesn't close src even if src implements io.Closer.
 // Closing the source is src owner's responsibility.
 func DecodeWithSampleRate(sampleRate int, src io.Reader) (*Stream, error) {
+	src, tags, err := readID3v2Tags(src)
+	if err != nil {
+		return nil, err
+	}
+
 	d, err := mp3.NewDecoder(src)
 	if err != nil {
 		return nil, err
@@ -81,6 +97,7 @@ func DecodeWithSampleRate(sampleRate int, src io.Reader) (*Stream, error) {
 		orig:       d,
 		resampling: r,
 	}
+	s.gain, s.hasGain = replaygain.Gain(tags)
 	return s, nil
 }
 
@@ -0,0 +1,193 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mp3
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"strings"
+	"unicode/utf16"
+)
+
+// readID3v2Tags reads the user-defined text frames (TXXX) of the ID3v2 tag at the head of src.
+//
+// readID3v2Tags returns a reader that reads src from the original position, and the tags whose keys are in upper
+// case. If src is an io.Seeker, the returned reader is src itself.
+func readID3v2Tags(src io.Reader) (io.Reader, map[string]string, error) {
+	tags := map[string]string{}
+
+	seeker, ok := src.(io.Seeker)
+	var pos int64
+	if ok {
+		p, err := seeker.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil, nil, err
+		}
+		pos = p
+	}
+
+	var consumed bytes.Buffer
+	r := io.TeeReader(src, &consumed)
+	if seeker != nil {
+		r = src
+	}
+	rewind := func() (io.Reader, error) {
+		if seeker != nil {
+			if _, err := seeker.Seek(pos, io.SeekStart); err != nil {
+				return nil, err
+			}
+			return src, nil
+		}
+		return io.MultiReader(&consumed, src), nil
+	}
+
+	header := make([]byte, 10)
+	if _, err := io.ReadFull(r, header); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			rr, err := rewind()
+			return rr, tags, err
+		}
+		return nil, nil, err
+	}
+
+	version := header[3]
+	flags := header[5]
+	// Unsynchronisation is not supported.
+	if string(header[:3]) != "ID3" || version < 2 || version > 4 || flags&0x80 != 0 {
+		rr, err := rewind()
+		return rr, tags, err
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(r, int64(syncsafe(header[6:10]))))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Skip the extended header.
+	if flags&0x40 != 0 && version >= 3 && len(body) >= 4 {
+		n := int(binary.BigEndian.Uint32(body))
+		if version == 3 {
+			n += 4
+		} else {
+			n = syncsafe(body[:4])
+		}
+		if n > len(body) {
+			n = len(body)
+		}
+		body = body[n:]
+	}
+
+	idSize, sizeSize, headerSize := 4, 4, 10
+	if version == 2 {
+		idSize, sizeSize, headerSize = 3, 3, 6
+	}
+	for len(body) >= headerSize && body[0] != 0 {
+		id := string(body[:idSize])
+		var size int
+		switch version {
+		case 2:
+			size = int(body[3])<<16 | int(body[4])<<8 | int(body[5])
+		case 3:
+			size = int(binary.BigEndian.Uint32(body[idSize : idSize+sizeSize]))
+		case 4:
+			size = syncsafe(body[idSize : idSize+sizeSize])
+		}
+		body = body[headerSize:]
+		if size > len(body) {
+			break
+		}
+		if id == "TXXX" || id == "TXX" {
+			if k, v, ok := parseTXXX(body[:size]); ok {
+				tags[strings.ToUpper(k)] = v
+			}
+		}
+		body = body[size:]
+	}
+
+	rr, err := rewind()
+	return rr, tags, err
+}
+
+func syncsafe(b []byte) int {
+	return int(b[0]&0x7f)<<21 | int(b[1]&0x7f)<<14 | int(b[2]&0x7f)<<7 | int(b[3]&0x7f)
+}
+
+// parseTXXX parses the content of a TXXX frame, that is the text encoding, the description and the value.
+func parseTXXX(b []byte) (key, value string, ok bool) {
+	if len(b) < 1 {
+		return "", "", false
+	}
+	enc := b[0]
+	b = b[1:]
+
+	// The description is terminated by a null character. The value might be terminated too.
+	var desc, val []byte
+	switch enc {
+	case 0, 3:
+		i := bytes.IndexByte(b, 0)
+		if i < 0 {
+			return "", "", false
+		}
+		desc, val = b[:i], bytes.TrimRight(b[i+1:], "\x00")
+	case 1, 2:
+		i := -1
+		for j := 0; j+1 < len(b); j += 2 {
+			if b[j] == 0 && b[j+1] == 0 {
+				i = j
+				break
+			}
+		}
+		if i < 0 {
+			return "", "", false
+		}
+		desc, val = b[:i], b[i+2:]
+	default:
+		return "", "", false
+	}
+	return decodeID3Text(enc, desc), strings.TrimRight(decodeID3Text(enc, val), "\x00"), true
+}
+
+func decodeID3Text(enc byte, b []byte) string {
+	switch enc {
+	case 0:
+		// ISO-8859-1
+		rs := make([]rune, len(b))
+		for i, c := range b {
+			rs[i] = rune(c)
+		}
+		return string(rs)
+	case 1, 2:
+		// UTF-16 with a byte order mark, or UTF-16BE.
+		var order binary.ByteOrder = binary.BigEndian
+		if enc == 1 && len(b) >= 2 {
+			if b[0] == 0xff && b[1] == 0xfe {
+				order = binary.LittleEndian
+			}
+			if (b[0] == 0xff && b[1] == 0xfe) || (b[0] == 0xfe && b[1] == 0xff) {
+				b = b[2:]
+			}
+		}
+		us := make([]uint16, len(b)/2)
+		for i := range us {
+			us[i] = order.Uint16(b[2*i:])
+		}
+		return string(utf16.Decode(us))
+	default:
+		// UTF-8
+		return string(b)
+	}
+}
@@ -12,8 +12,8 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-//go:build !ebitencbackend
-// +build !ebitencbackend
+//go:build !freebsd && !openbsd && !ebitencbackend
+// +build !freebsd,!openbsd,!ebitencbackend
 
 package audio
 
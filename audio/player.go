@@ -16,9 +16,12 @@ package audio
 
 import (
 	"io"
+	"math"
 	"runtime"
 	"sync"
 	"time"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/tracing"
 )
 
 // player is exactly same as the interface oto.Player.
@@ -46,6 +49,9 @@ type playerFactory struct {
 	context    context
 	sampleRate int
 
+	// generation is incremented whenever the context is recreated.
+	generation int
+
 	m sync.Mutex
 }
 
@@ -63,12 +69,15 @@ func newPlayerFactory(sampleRate int) *playerFactory {
 }
 
 type playerImpl struct {
-	context *Context
-	player  player
-	src     io.Reader
-	stream  *timeStream
-	factory *playerFactory
-	m       sync.Mutex
+	context       *Context
+	player        player
+	generation    int
+	src           io.Reader
+	stream        *timeStream
+	factory       *playerFactory
+	volume        float64
+	normalization bool
+	m             sync.Mutex
 }
 
 func (f *playerFactory) newPlayer(context *Context, src io.Reader) (*playerImpl, error) {
@@ -79,6 +88,7 @@ func (f *playerFactory) newPlayer(context *Context, src io.Reader) (*playerImpl,
 		src:     src,
 		context: context,
 		factory: f,
+		volume:  1,
 	}
 	runtime.SetFinalizer(p, (*playerImpl).Close)
 	return p, nil
@@ -114,6 +124,28 @@ func (f *playerFactory) error() error {
 	return f.context.Err()
 }
 
+// reopen recreates the context after the current context gets an error, e.g., when the audio device is removed.
+//
+// The existing players are recreated lazily with the new context.
+func (f *playerFactory) reopen() error {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	c, _, err := newContext(f.sampleRate, channelNum, bitDepthInBytes)
+	if err != nil {
+		return err
+	}
+	f.context = c
+	f.generation++
+	return nil
+}
+
+func (f *playerFactory) currentGeneration() int {
+	f.m.Lock()
+	defer f.m.Unlock()
+	return f.generation
+}
+
 func (f *playerFactory) initContextIfNeeded() (<-chan struct{}, error) {
 	f.m.Lock()
 	defer f.m.Unlock()
@@ -154,8 +186,40 @@ func (p *playerImpl) ensurePlayer() error {
 		}
 		p.stream = s
 	}
+	if gen := p.factory.currentGeneration(); p.player != nil && p.generation != gen {
+		if err := p.recreatePlayer(); err != nil {
+			return err
+		}
+	}
 	if p.player == nil {
 		p.player = p.factory.context.NewPlayer(p.stream)
+		p.generation = p.factory.currentGeneration()
+	}
+	return nil
+}
+
+// recreatePlayer recreates the underlying player with the current context, keeping the position, the volume and the
+// playing state.
+func (p *playerImpl) recreatePlayer() error {
+	playing := p.player.IsPlaying()
+	current := p.currentWithoutLock()
+
+	// The old player belongs to the context that doesn't work any longer. Ignore the error.
+	_ = p.player.Close()
+	p.player = nil
+
+	// If the source is not seekable, the data buffered in the old player is lost.
+	if _, ok := p.src.(io.Seeker); ok {
+		if err := p.stream.Seek(current); err != nil {
+			return err
+		}
+	}
+
+	p.player = p.factory.context.NewPlayer(p.stream)
+	p.generation = p.factory.currentGeneration()
+	p.player.SetVolume(p.volume * p.normalizationGain())
+	if playing {
+		p.player.Play()
 	}
 	return nil
 }
@@ -208,7 +272,7 @@ func (p *playerImpl) Volume() float64 {
 		p.context.setError(err)
 		return 0
 	}
-	return p.player.Volume()
+	return p.volume
 }
 
 func (p *playerImpl) SetVolume(volume float64) {
@@ -219,7 +283,43 @@ func (p *playerImpl) SetVolume(volume float64) {
 		p.context.setError(err)
 		return
 	}
-	p.player.SetVolume(volume)
+	p.player.SetVolume(volume * p.normalizationGain())
+	p.volume = volume
+}
+
+func (p *playerImpl) IsNormalizationEnabled() bool {
+	p.m.Lock()
+	defer p.m.Unlock()
+	return p.normalization
+}
+
+func (p *playerImpl) SetNormalizationEnabled(enabled bool) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	if err := p.ensurePlayer(); err != nil {
+		p.context.setError(err)
+		return
+	}
+	p.normalization = enabled
+	p.player.SetVolume(p.volume * p.normalizationGain())
+}
+
+// normalizationGain returns the linear gain to apply for the loudness normalization.
+func (p *playerImpl) normalizationGain() float64 {
+	if !p.normalization {
+		return 1
+	}
+	g, ok := p.src.(NormalizationGainer)
+	if !ok {
+		return 1
+	}
+	db, ok := g.NormalizationGain()
+	if !ok {
+		return 1
+	}
+	// Don't amplify the stream to avoid clipping.
+	return math.Min(math.Pow(10, db/20), 1)
 }
 
 func (p *playerImpl) Close() error {
@@ -244,7 +344,10 @@ func (p *playerImpl) Current() time.Duration {
 		p.context.setError(err)
 		return 0
 	}
+	return p.currentWithoutLock()
+}
 
+func (p *playerImpl) currentWithoutLock() time.Duration {
 	sample := (p.stream.Current() - int64(p.player.UnplayedBufferSize())) / bytesPerSample
 	return time.Duration(sample) * time.Second / time.Duration(p.factory.sampleRate)
 }
@@ -311,6 +414,8 @@ func newTimeStream(r io.Reader, sampleRate int) (*timeStream, error) {
 }
 
 func (s *timeStream) Read(buf []byte) (int, error) {
+	defer tracing.StartRegion("ebiten.audio")()
+
 	s.m.Lock()
 	defer s.m.Unlock()
 
@@ -18,6 +18,7 @@
 package audio
 
 import (
+	"errors"
 	"syscall/js"
 
 	"github.com/hajimehoshi/oto/v2"
@@ -26,6 +27,11 @@ import (
 )
 
 func newContext(sampleRate, channelNum, bitDepthInBytes int) (context, chan struct{}, error) {
+	// An AudioContext is available only on the main thread, and nothing bridges a Web Worker to it.
+	if !js.Global().Get("document").Truthy() && js.Global().Get("WorkerGlobalScope").Truthy() {
+		return nil, nil, errors.New("audio: audio is not available in a Web Worker")
+	}
+
 	if js.Global().Get("go2cpp").Truthy() {
 		ready := make(chan struct{})
 		close(ready)
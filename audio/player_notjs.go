@@ -12,8 +12,8 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-//go:build !js && !ebitencbackend
-// +build !js,!ebitencbackend
+//go:build !freebsd && !js && !openbsd && !ebitencbackend
+// +build !freebsd,!js,!openbsd,!ebitencbackend
 
 package audio
 
@@ -0,0 +1,253 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audio
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sync/atomic"
+)
+
+// TimeStretch represents a stream that changes the tempo of a source stream without changing its pitch.
+//
+// TimeStretch uses WSOLA (waveform similarity overlap-add): the source is cut into overlapping windowed frames,
+// and each frame is shifted within a small tolerance to the position where the waveform matches the previous frame
+// best. This works well for music and ambient sounds, but might cause echoes with strong transients at extreme
+// speeds.
+//
+// TimeStretch is not seekable. To stretch a looped stream, give an InfiniteLoop as the source.
+type TimeStretch struct {
+	src   io.Reader
+	speed uint64
+
+	frameSize int
+	hop       int
+	tolerance int
+	window    []float32
+
+	// in is the interleaved stereo input that is not consumed yet.
+	in []float32
+
+	// pos is the ideal position of the next frame in frames relative to in.
+	pos float64
+
+	// natural is the position of the natural continuation of the previous frame in frames relative to in.
+	// natural is -1 before the first frame.
+	natural int
+
+	// acc is the overlap-add accumulator.
+	acc []float32
+
+	out   []byte
+	extra []byte
+	buf   []byte
+	eof   bool
+	done  bool
+}
+
+// NewTimeStretch creates a new time-stretching stream with a source stream and its sample rate.
+//
+// The format of src should be same as noted at NewPlayer. The initial speed is 1.
+func NewTimeStretch(src io.Reader, sampleRate int) *TimeStretch {
+	// A frame of about 40[ms] works well for most music.
+	frameSize := sampleRate / 25 / 2 * 2
+	if frameSize < 16 {
+		frameSize = 16
+	}
+	w := make([]float32, frameSize)
+	for i := range w {
+		// The periodic Hann window. The windows overlapping by half sum to 1.
+		w[i] = float32(0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/float64(frameSize)))
+	}
+	t := &TimeStretch{
+		src:       src,
+		frameSize: frameSize,
+		hop:       frameSize / 2,
+		tolerance: sampleRate / 100,
+		window:    w,
+		natural:   -1,
+		acc:       make([]float32, frameSize*channelNum),
+	}
+	t.SetSpeed(1)
+	return t
+}
+
+// Speed returns the current speed.
+//
+// Speed is concurrent-safe.
+func (t *TimeStretch) Speed() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&t.speed))
+}
+
+// SetSpeed sets the speed. 1 is the original tempo, and 2 is twice as fast.
+// The change is applied from the next frame of about 20[ms].
+//
+// speed must be positive. SetSpeed panics otherwise.
+//
+// SetSpeed is concurrent-safe.
+func (t *TimeStretch) SetSpeed(speed float64) {
+	if speed <= 0 || math.IsNaN(speed) || math.IsInf(speed, 0) {
+		panic(fmt.Sprintf("audio: speed must be positive but %f", speed))
+	}
+	atomic.StoreUint64(&t.speed, math.Float64bits(speed))
+}
+
+// Read is implementation of io.Reader's Read.
+func (t *TimeStretch) Read(b []byte) (int, error) {
+	for len(t.out) < len(b) && !t.done {
+		if err := t.step(); err != nil {
+			return 0, err
+		}
+	}
+	if len(t.out) == 0 && t.done {
+		return 0, io.EOF
+	}
+	n := copy(b, t.out)
+	t.out = t.out[:copy(t.out, t.out[n:])]
+	return n, nil
+}
+
+func (t *TimeStretch) inFrames() int {
+	return len(t.in) / channelNum
+}
+
+// fill reads the source until in has the given number of frames or the source ends.
+func (t *TimeStretch) fill(frames int) error {
+	if t.buf == nil {
+		t.buf = make([]byte, 4096)
+	}
+	for !t.eof && t.inFrames() < frames {
+		n, err := t.src.Read(t.buf)
+		bs := append(t.extra, t.buf[:n]...)
+		m := len(bs) / bytesPerSample * bytesPerSample
+		for i := 0; i < m; i += bitDepthInBytes {
+			t.in = append(t.in, float32(int16(bs[i])|int16(bs[i+1])<<8)/(1<<15))
+		}
+		t.extra = append(t.extra[:0], bs[m:]...)
+		if err == io.EOF {
+			t.eof = true
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sample returns the sample of the channel ch at the frame i, or 0 if the frame is out of the input.
+func (t *TimeStretch) sample(i int, ch int) float32 {
+	if i < 0 || i >= t.inFrames() {
+		return 0
+	}
+	return t.in[i*channelNum+ch]
+}
+
+// similarity returns how similar the frames from s and from the natural continuation are.
+func (t *TimeStretch) similarity(s int) float64 {
+	var dot, energy float64
+	// Skip every other frame as the precision is good enough.
+	for i := 0; i < t.hop; i += 2 {
+		a := float64(t.sample(t.natural+i, 0) + t.sample(t.natural+i, 1))
+		b := float64(t.sample(s+i, 0) + t.sample(s+i, 1))
+		dot += a * b
+		energy += b * b
+	}
+	if energy == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(energy)
+}
+
+func (t *TimeStretch) step() error {
+	ideal := int(t.pos)
+	if err := t.fill(ideal + t.tolerance + t.frameSize); err != nil {
+		return err
+	}
+
+	if t.eof && ideal >= t.inFrames() {
+		// Flush the tail of the last frame.
+		t.emit(t.hop)
+		t.done = true
+		return nil
+	}
+
+	s := ideal
+	if t.natural >= 0 {
+		best := t.similarity(s)
+		for d := -t.tolerance; d <= t.tolerance; d++ {
+			if d == 0 || ideal+d < 0 {
+				continue
+			}
+			if v := t.similarity(ideal + d); v > best {
+				best = v
+				s = ideal + d
+			}
+		}
+	}
+
+	for i := 0; i < t.frameSize; i++ {
+		w := t.window[i]
+		// The first frame doesn't fade in.
+		if t.natural < 0 && i < t.hop {
+			w = 1
+		}
+		for ch := 0; ch < channelNum; ch++ {
+			t.acc[i*channelNum+ch] += w * t.sample(s+i, ch)
+		}
+	}
+	t.emit(t.hop)
+
+	t.natural = s + t.hop
+	t.pos += float64(t.hop) * t.Speed()
+
+	// Drop the input that is no longer needed.
+	drop := int(t.pos) - t.tolerance
+	if drop > t.natural {
+		drop = t.natural
+	}
+	if drop > t.inFrames() {
+		drop = t.inFrames()
+	}
+	if drop > 0 {
+		t.in = t.in[:copy(t.in, t.in[drop*channelNum:])]
+		t.pos -= float64(drop)
+		t.natural -= drop
+	}
+	return nil
+}
+
+// emit appends the first frames of the accumulator to the output, and shifts the accumulator.
+func (t *TimeStretch) emit(frames int) {
+	n := frames * channelNum
+	for _, v := range t.acc[:n] {
+		s := int16(math.Max(-(1 << 15), math.Min((1<<15)-1, math.Round(float64(v)*(1<<15)))))
+		t.out = append(t.out, byte(s), byte(s>>8))
+	}
+	copy(t.acc, t.acc[n:])
+	for i := len(t.acc) - n; i < len(t.acc); i++ {
+		t.acc[i] = 0
+	}
+}
+
+// NormalizationGain is implementation of NormalizationGainer's NormalizationGain.
+// NormalizationGain returns the source stream's gain if the source implements NormalizationGainer.
+func (t *TimeStretch) NormalizationGain() (db float64, ok bool) {
+	if g, ok := t.src.(NormalizationGainer); ok {
+		return g.NormalizationGain()
+	}
+	return 0, false
+}
@@ -0,0 +1,72 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audio_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+)
+
+func sineWave(frames int, freq float64, sampleRate int) []byte {
+	bs := make([]byte, frames*4)
+	for i := 0; i < frames; i++ {
+		v := int16(10000 * math.Sin(2*math.Pi*freq*float64(i)/float64(sampleRate)))
+		bs[4*i] = byte(v)
+		bs[4*i+1] = byte(v >> 8)
+		bs[4*i+2] = byte(v)
+		bs[4*i+3] = byte(v >> 8)
+	}
+	return bs
+}
+
+func TestTimeStretchOriginalSpeed(t *testing.T) {
+	const sampleRate = 44100
+	src := sineWave(sampleRate, 440, sampleRate)
+
+	out, err := ioutil.ReadAll(audio.NewTimeStretch(bytes.NewReader(src), sampleRate))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) < len(src) {
+		t.Fatalf("len(out): got: %d, want: >= %d", len(out), len(src))
+	}
+	if !bytes.Equal(out[:len(src)], src) {
+		t.Errorf("the output must be the same as the source at the original speed")
+	}
+}
+
+func TestTimeStretchLength(t *testing.T) {
+	const sampleRate = 44100
+	src := sineWave(sampleRate, 440, sampleRate)
+
+	for _, speed := range []float64{0.5, 1.5, 2} {
+		s := audio.NewTimeStretch(bytes.NewReader(src), sampleRate)
+		s.SetSpeed(speed)
+		out, err := ioutil.ReadAll(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := float64(len(out)) / float64(len(src))
+		want := 1 / speed
+		// Allow the last frame as an error.
+		if math.Abs(got-want) > 0.05 {
+			t.Errorf("speed: %f, length ratio: got: %f, want: %f", speed, got, want)
+		}
+	}
+}
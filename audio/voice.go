@@ -0,0 +1,291 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audio
+
+import (
+	"sync"
+)
+
+// VoiceStealPolicy represents which voice is stopped when a new voice exceeds the limits of a VoiceManager.
+type VoiceStealPolicy int
+
+const (
+	// VoiceStealOldest stops the voice that started playing first.
+	VoiceStealOldest VoiceStealPolicy = iota
+
+	// VoiceStealQuietest stops the voice with the lowest volume.
+	VoiceStealQuietest
+)
+
+// Sound is a sound played by a VoiceManager.
+type Sound struct {
+	src       []byte
+	maxVoices int
+}
+
+// NewSound creates a new sound with the given bytes.
+//
+// The format of src should be same as noted at NewPlayer. src can be shared by multiple sounds.
+//
+// maxVoices is the maximum number of voices of the sound playing at the same time.
+// If maxVoices is 0 or less, the number is not limited per sound.
+func NewSound(src []byte, maxVoices int) *Sound {
+	return &Sound{
+		src:       src,
+		maxVoices: maxVoices,
+	}
+}
+
+// VoicePlayOptions represents options to play a voice.
+type VoicePlayOptions struct {
+	// Priority is the priority of the voice.
+	// When a limit is reached, a voice with a lower priority is stopped first, and a new voice with a lower
+	// priority than all the candidates to stop doesn't play.
+	Priority int
+
+	// Duck specifies whether the duck targets are ducked while the voice is playing, e.g., for voice lines.
+	Duck bool
+}
+
+type voice struct {
+	player   *Player
+	sound    *Sound
+	priority int
+	duck     bool
+	serial   uint64
+}
+
+// VoiceManager manages voices, which are players of sounds, to avoid overloading the mixer.
+//
+// A VoiceManager limits the number of voices playing at the same time per sound and in total. When a limit is
+// reached, an existing voice is stopped ('stolen') based on the priorities and the VoiceStealPolicy.
+// A VoiceManager can also duck players, e.g., the music, while voices with VoicePlayOptions.Duck are playing.
+//
+// The methods of VoiceManager are concurrent-safe.
+type VoiceManager struct {
+	context     *Context
+	maxVoices   int
+	stealPolicy VoiceStealPolicy
+
+	voices []*voice
+	serial uint64
+
+	duckTargets  []*Player
+	duckVolume   float64
+	duckOrigVols []float64
+	ducking      bool
+
+	m sync.Mutex
+}
+
+// NewVoiceManager creates a new voice manager.
+//
+// maxVoices is the maximum number of voices playing at the same time in total.
+// If maxVoices is 0 or less, the number is not limited in total.
+func (c *Context) NewVoiceManager(maxVoices int) *VoiceManager {
+	return &VoiceManager{
+		context:   c,
+		maxVoices: maxVoices,
+	}
+}
+
+// SetStealPolicy sets the policy to choose a voice to stop among the voices with the lowest priority.
+// The default policy is VoiceStealOldest.
+func (v *VoiceManager) SetStealPolicy(policy VoiceStealPolicy) {
+	v.m.Lock()
+	defer v.m.Unlock()
+	v.stealPolicy = policy
+}
+
+// SetDuckTargets sets the players to be ducked and the volume scale in [0, 1] applied to them while ducking.
+//
+// If the targets are being ducked, their volumes are restored before the targets are replaced.
+func (v *VoiceManager) SetDuckTargets(targets []*Player, volume float64) {
+	v.m.Lock()
+	defer v.m.Unlock()
+
+	if v.ducking {
+		v.setDucking(false)
+	}
+	v.duckTargets = append(v.duckTargets[:0], targets...)
+	v.duckVolume = volume
+	v.updateDucking()
+}
+
+// Play plays the sound at the given volume as a new voice, and returns the player of the voice.
+//
+// Play returns nil when the voice cannot play because of the limits and the priority.
+//
+// The returned player is closed by the VoiceManager when the voice is stolen or after the voice finishes playing.
+// Do not close or replay the returned player.
+func (v *VoiceManager) Play(sound *Sound, volume float64, options *VoicePlayOptions) *Player {
+	if options == nil {
+		options = &VoicePlayOptions{}
+	}
+
+	v.m.Lock()
+	defer v.m.Unlock()
+
+	v.removeFinishedVoices()
+
+	if sound.maxVoices > 0 {
+		if !v.makeRoom(sound, sound.maxVoices, options.Priority) {
+			return nil
+		}
+	}
+	if v.maxVoices > 0 {
+		if !v.makeRoom(nil, v.maxVoices, options.Priority) {
+			return nil
+		}
+	}
+
+	p := v.context.NewPlayerFromBytes(sound.src)
+	p.SetVolume(volume)
+	p.Play()
+
+	v.serial++
+	v.voices = append(v.voices, &voice{
+		player:   p,
+		sound:    sound,
+		priority: options.Priority,
+		duck:     options.Duck,
+		serial:   v.serial,
+	})
+	v.updateDucking()
+	return p
+}
+
+// Update removes the finished voices and restores the volumes of the duck targets when no ducking voices are
+// playing.
+//
+// Update should be called every tick, e.g., from Game's Update.
+func (v *VoiceManager) Update() {
+	v.m.Lock()
+	defer v.m.Unlock()
+
+	v.removeFinishedVoices()
+	v.updateDucking()
+}
+
+// StopAll stops all the voices.
+func (v *VoiceManager) StopAll() {
+	v.m.Lock()
+	defer v.m.Unlock()
+
+	for _, vc := range v.voices {
+		vc.player.Pause()
+		vc.player.Close()
+	}
+	v.voices = v.voices[:0]
+	v.updateDucking()
+}
+
+// PlayingVoiceCount returns the number of the voices playing.
+func (v *VoiceManager) PlayingVoiceCount() int {
+	v.m.Lock()
+	defer v.m.Unlock()
+
+	v.removeFinishedVoices()
+	return len(v.voices)
+}
+
+func (v *VoiceManager) removeFinishedVoices() {
+	n := 0
+	for _, vc := range v.voices {
+		if vc.player.IsPlaying() {
+			v.voices[n] = vc
+			n++
+			continue
+		}
+		vc.player.Close()
+	}
+	for i := n; i < len(v.voices); i++ {
+		v.voices[i] = nil
+	}
+	v.voices = v.voices[:n]
+}
+
+// makeRoom stops voices so that the number of the voices of the sound is less than max.
+// If sound is nil, all the voices are counted.
+// makeRoom returns false when a new voice with the priority cannot play.
+func (v *VoiceManager) makeRoom(sound *Sound, max int, priority int) bool {
+	for {
+		var count int
+		victim := -1
+		for i, vc := range v.voices {
+			if sound != nil && vc.sound != sound {
+				continue
+			}
+			count++
+			if victim == -1 || v.isBetterVictim(vc, v.voices[victim]) {
+				victim = i
+			}
+		}
+		if count < max {
+			return true
+		}
+		if v.voices[victim].priority > priority {
+			return false
+		}
+
+		vc := v.voices[victim]
+		vc.player.Pause()
+		vc.player.Close()
+		copy(v.voices[victim:], v.voices[victim+1:])
+		v.voices[len(v.voices)-1] = nil
+		v.voices = v.voices[:len(v.voices)-1]
+	}
+}
+
+func (v *VoiceManager) isBetterVictim(a, b *voice) bool {
+	if a.priority != b.priority {
+		return a.priority < b.priority
+	}
+	if v.stealPolicy == VoiceStealQuietest {
+		if va, vb := a.player.Volume(), b.player.Volume(); va != vb {
+			return va < vb
+		}
+	}
+	return a.serial < b.serial
+}
+
+func (v *VoiceManager) updateDucking() {
+	var duck bool
+	for _, vc := range v.voices {
+		if vc.duck {
+			duck = true
+			break
+		}
+	}
+	if duck != v.ducking {
+		v.setDucking(duck)
+	}
+}
+
+func (v *VoiceManager) setDucking(duck bool) {
+	if duck {
+		v.duckOrigVols = v.duckOrigVols[:0]
+		for _, p := range v.duckTargets {
+			vol := p.Volume()
+			v.duckOrigVols = append(v.duckOrigVols, vol)
+			p.SetVolume(vol * v.duckVolume)
+		}
+	} else {
+		for i, p := range v.duckTargets {
+			p.SetVolume(v.duckOrigVols[i])
+		}
+	}
+	v.ducking = duck
+}
@@ -0,0 +1,199 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audio
+
+import (
+	"testing"
+)
+
+// addVoiceForTesting adds a voice to v without playing it, so that the voice doesn't finish during a test.
+func (v *VoiceManager) addVoiceForTesting(sound *Sound, volume float64, options VoicePlayOptions) *Player {
+	p := v.context.NewPlayerFromBytes(sound.src)
+	p.SetVolume(volume)
+	v.serial++
+	v.voices = append(v.voices, &voice{
+		player:   p,
+		sound:    sound,
+		priority: options.Priority,
+		duck:     options.Duck,
+		serial:   v.serial,
+	})
+	v.updateDucking()
+	return p
+}
+
+func (v *VoiceManager) hasVoiceForTesting(p *Player) bool {
+	for _, vc := range v.voices {
+		if vc.player == p {
+			return true
+		}
+	}
+	return false
+}
+
+func TestVoiceManagerStealOldest(t *testing.T) {
+	c := NewContext(44100)
+	defer ResetContextForTesting()
+
+	m := c.NewVoiceManager(0)
+	s := NewSound(make([]byte, 4), 2)
+	p0 := m.addVoiceForTesting(s, 1, VoicePlayOptions{})
+	p1 := m.addVoiceForTesting(s, 0.5, VoicePlayOptions{})
+
+	if !m.makeRoom(s, s.maxVoices, 0) {
+		t.Fatalf("makeRoom: got: false, want: true")
+	}
+	if m.hasVoiceForTesting(p0) {
+		t.Errorf("the oldest voice must be stolen")
+	}
+	if !m.hasVoiceForTesting(p1) {
+		t.Errorf("the newer voice must not be stolen")
+	}
+}
+
+func TestVoiceManagerStealQuietest(t *testing.T) {
+	c := NewContext(44100)
+	defer ResetContextForTesting()
+
+	m := c.NewVoiceManager(0)
+	m.SetStealPolicy(VoiceStealQuietest)
+	s := NewSound(make([]byte, 4), 2)
+	p0 := m.addVoiceForTesting(s, 1, VoicePlayOptions{})
+	p1 := m.addVoiceForTesting(s, 0.5, VoicePlayOptions{})
+
+	if !m.makeRoom(s, s.maxVoices, 0) {
+		t.Fatalf("makeRoom: got: false, want: true")
+	}
+	if !m.hasVoiceForTesting(p0) {
+		t.Errorf("the louder voice must not be stolen")
+	}
+	if m.hasVoiceForTesting(p1) {
+		t.Errorf("the quietest voice must be stolen")
+	}
+}
+
+func TestVoiceManagerPriority(t *testing.T) {
+	c := NewContext(44100)
+	defer ResetContextForTesting()
+
+	m := c.NewVoiceManager(0)
+	s := NewSound(make([]byte, 4), 2)
+	p0 := m.addVoiceForTesting(s, 1, VoicePlayOptions{Priority: 1})
+	p1 := m.addVoiceForTesting(s, 1, VoicePlayOptions{Priority: 0})
+
+	// A voice with a lower priority than all the voices cannot play.
+	if m.makeRoom(s, s.maxVoices, -1) {
+		t.Errorf("makeRoom with a lower priority: got: true, want: false")
+	}
+	if len(m.voices) != 2 {
+		t.Errorf("len(voices): got: %d, want: 2", len(m.voices))
+	}
+
+	// The voice with the lowest priority is stolen even if it is newer.
+	if !m.makeRoom(s, s.maxVoices, 0) {
+		t.Fatalf("makeRoom: got: false, want: true")
+	}
+	if !m.hasVoiceForTesting(p0) {
+		t.Errorf("the voice with the higher priority must not be stolen")
+	}
+	if m.hasVoiceForTesting(p1) {
+		t.Errorf("the voice with the lower priority must be stolen")
+	}
+}
+
+func TestVoiceManagerTotalLimit(t *testing.T) {
+	c := NewContext(44100)
+	defer ResetContextForTesting()
+
+	m := c.NewVoiceManager(2)
+	s0 := NewSound(make([]byte, 4), 0)
+	s1 := NewSound(make([]byte, 4), 0)
+	p0 := m.addVoiceForTesting(s0, 1, VoicePlayOptions{})
+	p1 := m.addVoiceForTesting(s1, 1, VoicePlayOptions{})
+	p2 := m.addVoiceForTesting(s1, 1, VoicePlayOptions{})
+
+	// The per-sound limit doesn't count the other sounds.
+	if !m.makeRoom(s0, 2, 0) {
+		t.Fatalf("makeRoom: got: false, want: true")
+	}
+	if len(m.voices) != 3 {
+		t.Errorf("len(voices): got: %d, want: 3", len(m.voices))
+	}
+
+	// The total limit counts all the sounds.
+	if !m.makeRoom(nil, m.maxVoices, 0) {
+		t.Fatalf("makeRoom: got: false, want: true")
+	}
+	if len(m.voices) != 1 {
+		t.Errorf("len(voices): got: %d, want: 1", len(m.voices))
+	}
+	if m.hasVoiceForTesting(p0) || m.hasVoiceForTesting(p1) || !m.hasVoiceForTesting(p2) {
+		t.Errorf("the oldest voices must be stolen")
+	}
+}
+
+func TestVoiceManagerDuck(t *testing.T) {
+	c := NewContext(44100)
+	defer ResetContextForTesting()
+
+	music := c.NewPlayerFromBytes(make([]byte, 4))
+	music.SetVolume(0.8)
+
+	m := c.NewVoiceManager(0)
+	m.SetDuckTargets([]*Player{music}, 0.5)
+	s := NewSound(make([]byte, 4), 0)
+
+	m.addVoiceForTesting(s, 1, VoicePlayOptions{})
+	if got, want := music.Volume(), 0.8; got != want {
+		t.Errorf("Volume() without ducking voices: got: %f, want: %f", got, want)
+	}
+
+	m.addVoiceForTesting(s, 1, VoicePlayOptions{Duck: true})
+	m.addVoiceForTesting(s, 1, VoicePlayOptions{Duck: true})
+	if got, want := music.Volume(), 0.4; got != want {
+		t.Errorf("Volume() with ducking voices: got: %f, want: %f", got, want)
+	}
+
+	m.StopAll()
+	if got, want := music.Volume(), 0.8; got != want {
+		t.Errorf("Volume() after StopAll: got: %f, want: %f", got, want)
+	}
+	if got := m.PlayingVoiceCount(); got != 0 {
+		t.Errorf("PlayingVoiceCount() after StopAll: got: %d, want: 0", got)
+	}
+}
+
+func TestVoiceManagerSetDuckTargetsWhileDucking(t *testing.T) {
+	c := NewContext(44100)
+	defer ResetContextForTesting()
+
+	music0 := c.NewPlayerFromBytes(make([]byte, 4))
+	music1 := c.NewPlayerFromBytes(make([]byte, 4))
+
+	m := c.NewVoiceManager(0)
+	m.SetDuckTargets([]*Player{music0}, 0.5)
+	m.addVoiceForTesting(NewSound(make([]byte, 4), 0), 1, VoicePlayOptions{Duck: true})
+	if got, want := music0.Volume(), 0.5; got != want {
+		t.Errorf("music0.Volume(): got: %f, want: %f", got, want)
+	}
+
+	m.SetDuckTargets([]*Player{music1}, 0.25)
+	if got, want := music0.Volume(), 1.0; got != want {
+		t.Errorf("music0.Volume() after replacing the targets: got: %f, want: %f", got, want)
+	}
+	if got, want := music1.Volume(), 0.25; got != want {
+		t.Errorf("music1.Volume() after replacing the targets: got: %f, want: %f", got, want)
+	}
+}
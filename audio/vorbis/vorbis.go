@@ -23,12 +23,15 @@ import (
 
 	"github.com/hajimehoshi/ebiten/v2/audio"
 	"github.com/hajimehoshi/ebiten/v2/audio/internal/convert"
+	"github.com/hajimehoshi/ebiten/v2/audio/internal/replaygain"
 )
 
 // Stream is a decoded audio stream.
 type Stream struct {
 	decoded io.ReadSeeker
 	size    int64
+	gain    float64
+	hasGain bool
 }
 
 // Read is implementation of io.Reader's Read.
@@ -48,6 +51,13 @@ func (s *Stream) Length() int64 {
 	return s.size
 }
 
+// NormalizationGain is implementation of audio.NormalizationGainer's NormalizationGain.
+//
+// NormalizationGain returns the gain from the REPLAYGAIN_*_GAIN or R128_*_GAIN comments.
+func (s *Stream) NormalizationGain() (db float64, ok bool) {
+	return s.gain, s.hasGain
+}
+
 type decoder interface {
 	Read([]float32) (int, error)
 	SetPosition(int64) error
@@ -61,6 +71,7 @@ type decoded struct {
 	posInBytes int
 	decoder    decoder
 	decoderr   io.Reader
+	tags       map[string]string
 }
 
 func (d *decoded) Read(b []byte) (int, error) {
@@ -129,6 +140,10 @@ func decode(in io.Reader) (*decoded, int, int, error) {
 		totalBytes: int(r.Length()) * r.Channels() * 2, // 2 means 16bit per sample.
 		posInBytes: 0,
 		decoder:    r,
+		tags:       map[string]string{},
+	}
+	for _, c := range r.CommentHeader().Comments {
+		replaygain.AddVorbisComment(d.tags, c)
 	}
 	if _, err := d.Read(make([]byte, 65536)); err != nil && err != io.EOF {
 		return nil, 0, 0, err
@@ -169,6 +184,7 @@ func DecodeWithSampleRate(sampleRate int, src io.Reader) (*Stream, error) {
 		size = r.Length()
 	}
 	stream := &Stream{decoded: s, size: size}
+	stream.gain, stream.hasGain = replaygain.Gain(decoded.tags)
 	return stream, nil
 }
 
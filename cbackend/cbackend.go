@@ -0,0 +1,129 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cbackend offers the interface to port Ebiten games to a platform that Ebiten doesn't support directly,
+// e.g., a game console.
+//
+// This package is used only with the build tag `ebitencbackend`. With the build tag, Ebiten doesn't use any
+// platform-specific functionalities, and instead calls a platform implementation, a 'backend', for the screen,
+// the input devices, and the audio. The graphics is rendered by OpenGL (ES), and the OpenGL functions must be
+// available at link time.
+//
+// There are two ways to provide a backend.
+//
+// The first way is to implement the Backend interface in Go, and call SetBackend before running the game.
+// This is recommended as the backend is type-checked.
+//
+// The second way is to implement these C functions and link them with the game, e.g., as a static library.
+// The C functions are used when SetBackend is not called.
+//
+//	struct Gamepad {
+//	  int id;
+//	  char standard;
+//	  int button_num;
+//	  int axis_num;
+//	  char button_pressed[32];
+//	  float button_values[32];
+//	  float axis_values[16];
+//	};
+//
+//	struct Touch {
+//	  int id;
+//	  int x;
+//	  int y;
+//	};
+//
+//	// UI
+//	void EbitenInitializeGame();
+//	void EbitenGetScreenSize(int* width, int* height);
+//	void EbitenBeginFrame();
+//	void EbitenEndFrame();
+//
+//	// Input
+//	int EbitenGetGamepadNum();
+//	void EbitenGetGamepads(struct Gamepad* gamepads);
+//	int EbitenGetTouchNum();
+//	void EbitenGetTouches(struct Touch* touches);
+//	void EbitenVibrateGamepad(int id, double durationInSeconds, double strongMagnitude, double weakMagnitude);
+//
+//	// Audio
+//	typedef void (*OnReadCallback)(float* buf, size_t length);
+//	void EbitenOpenAudio(int sample_rate, int channel_num, OnReadCallback on_read_callback);
+//	void EbitenCloseAudio();
+//
+// Each C function corresponds to the Backend method of the same name.
+//
+// This package is experimental and the API might be changed in the future.
+package cbackend
+
+import (
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/cbackend"
+)
+
+// Gamepad represents a gamepad state.
+type Gamepad = cbackend.Gamepad
+
+// Touch represents a touch state.
+type Touch = cbackend.Touch
+
+// Backend is a platform implementation.
+//
+// InitializeGame, ScreenSize, BeginFrame, and EndFrame are called from the thread running the game.
+// The other methods might be called from other goroutines.
+type Backend interface {
+	// InitializeGame is called once when the game starts.
+	InitializeGame()
+
+	// ScreenSize returns the screen size in pixels.
+	ScreenSize() (width, height int)
+
+	// BeginFrame is called at the beginning of each frame.
+	// BeginFrame should wait for the next frame, e.g., the vertical sync, and then poll the input devices.
+	BeginFrame()
+
+	// EndFrame is called at the end of each frame after the rendering commands are issued.
+	// EndFrame should present the rendering result, e.g., by swapping the buffers.
+	EndFrame()
+
+	// AppendGamepads appends the current gamepad states to gamepads and returns the extended slice.
+	AppendGamepads(gamepads []Gamepad) []Gamepad
+
+	// AppendTouches appends the current touch states to touches and returns the extended slice.
+	AppendTouches(touches []Touch) []Touch
+
+	// VibrateGamepad vibrates the gamepad of the given ID.
+	// strongMagnitude and weakMagnitude are in between 0 and 1.
+	VibrateGamepad(id int, duration time.Duration, strongMagnitude, weakMagnitude float64)
+
+	// OpenAudio opens the audio device.
+	//
+	// onRead fills the given buffer with interleaved samples in between -1 and 1.
+	// The backend must call onRead whenever the audio device needs more samples.
+	// onRead is concurrent-safe.
+	OpenAudio(sampleRate, channelCount int, onRead func(buf []float32))
+
+	// CloseAudio closes the audio device.
+	CloseAudio()
+}
+
+// SetBackend sets the platform implementation.
+//
+// SetBackend must be called before the game starts, e.g., before ebiten.RunGame is called.
+//
+// SetBackend works only with the build tag `ebitencbackend`. Otherwise, SetBackend does nothing.
+func SetBackend(backend Backend) {
+	cbackend.SetBackend(backend)
+}
@@ -223,6 +223,11 @@ const objcM = `// Code generated by ebitenmobile. DO NOT EDIT.
 #endif
 
   displayLink_ = [CADisplayLink displayLinkWithTarget:self selector:@selector(drawFrame)];
+  // Follow the maximum refresh rate of the screen, e.g., 120 Hz on ProMotion displays.
+  // On iPhone, CADisableMinimumFrameDurationOnPhone must be true in Info.plist to exceed 60 Hz.
+  if (@available(iOS 10.3, *)) {
+    displayLink_.preferredFramesPerSecond = [UIScreen mainScreen].maximumFramesPerSecond;
+  }
   [displayLink_ addToRunLoop:[NSRunLoop currentRunLoop] forMode:NSDefaultRunLoopMode];
   EbitenmobileviewSetRenderRequester(self);
 }
@@ -241,6 +246,19 @@ const objcM = `// Code generated by ebitenmobile. DO NOT EDIT.
   CGRect viewRect = [[self view] frame];
 
   EbitenmobileviewLayout(viewRect.size.width, viewRect.size.height);
+  [self updateSafeAreaInsets];
+}
+
+- (void)viewSafeAreaInsetsDidChange {
+  [super viewSafeAreaInsetsDidChange];
+  [self updateSafeAreaInsets];
+}
+
+- (void)updateSafeAreaInsets {
+  if (@available(iOS 11.0, *)) {
+    UIEdgeInsets insets = [[self view] safeAreaInsets];
+    EbitenmobileviewSetSafeAreaInsets(insets.left, insets.top, insets.right, insets.bottom);
+  }
 }
 
 - (void)didReceiveMemoryWarning {
@@ -303,7 +321,12 @@ const objcM = `// Code generated by ebitenmobile. DO NOT EDIT.
     }
 #endif
     CGPoint location = [touch locationInView:touch.view];
-    EbitenmobileviewUpdateTouchesOnIOS(touch.phase, (uintptr_t)touch, location.x, location.y);
+    // maximumPossibleForce is 0 when 3D Touch is not available.
+    double pressure = 0;
+    if (touch.maximumPossibleForce > 0) {
+      pressure = touch.force / touch.maximumPossibleForce;
+    }
+    EbitenmobileviewUpdateTouchesOnIOS(touch.phase, (uintptr_t)touch, location.x, location.y, pressure, touch.majorRadius);
   }
 }
 
@@ -375,18 +398,24 @@ const viewJava = `// Code generated by ebitenmobile. DO NOT EDIT.
 
 package {{.JavaPkg}}.{{.PrefixLower}};
 
+import android.app.Activity;
+import android.app.Application;
 import android.content.Context;
 import android.hardware.input.InputManager;
+import android.os.Build;
+import android.os.Bundle;
 import android.os.Handler;
 import android.os.Looper;
 import android.util.AttributeSet;
 import android.util.DisplayMetrics;
 import android.util.Log;
 import android.view.Display;
+import android.view.DisplayCutout;
 import android.view.KeyEvent;
 import android.view.InputDevice;
 import android.view.MotionEvent;
 import android.view.ViewGroup;
+import android.view.WindowInsets;
 import android.view.WindowManager;
 
 import {{.JavaPkg}}.ebitenmobileview.Ebitenmobileview;
@@ -426,14 +455,62 @@ public class EbitenView extends ViewGroup implements InputManager.InputDeviceLis
         Ebitenmobileview.layout(widthInDp, heightInDp);
     }
 
+    @Override
+    public WindowInsets onApplyWindowInsets(WindowInsets insets) {
+        int left = insets.getSystemWindowInsetLeft();
+        int top = insets.getSystemWindowInsetTop();
+        int right = insets.getSystemWindowInsetRight();
+        int bottom = insets.getSystemWindowInsetBottom();
+        if (Build.VERSION.SDK_INT >= 28) {
+            DisplayCutout cutout = insets.getDisplayCutout();
+            if (cutout != null) {
+                left = Math.max(left, cutout.getSafeInsetLeft());
+                top = Math.max(top, cutout.getSafeInsetTop());
+                right = Math.max(right, cutout.getSafeInsetRight());
+                bottom = Math.max(bottom, cutout.getSafeInsetBottom());
+            }
+        }
+        Ebitenmobileview.setSafeAreaInsets(pxToDp(left), pxToDp(top), pxToDp(right), pxToDp(bottom));
+        return super.onApplyWindowInsets(insets);
+    }
+
+    // onBackPressed notifies the game of the back button or the back gesture, and reports whether the game consumed
+    // the event. If this returns false, the caller should pass the event through to the system.
+    //
+    // The back button is notified automatically. Call this from your own callback e.g. OnBackInvokedCallback when
+    // the predictive back gesture is enabled.
+    public boolean onBackPressed() {
+        return Ebitenmobileview.onBackPressedOnAndroid();
+    }
+
+    private static boolean isBackKeyEvent(int keyCode, KeyEvent event) {
+        // A gamepad button can be detected as a back key. Treat this as a gamepad button.
+        return keyCode == KeyEvent.KEYCODE_BACK && (event.getSource() & InputDevice.SOURCE_GAMEPAD) != InputDevice.SOURCE_GAMEPAD;
+    }
+
     @Override
     public boolean onKeyDown(int keyCode, KeyEvent event) {
+        if (isBackKeyEvent(keyCode, event)) {
+            if (event.getRepeatCount() == 0) {
+                this.backKeyConsumed = onBackPressed();
+            }
+            if (!this.backKeyConsumed) {
+                return super.onKeyDown(keyCode, event);
+            }
+            return true;
+        }
         Ebitenmobileview.onKeyDownOnAndroid(keyCode, event.getUnicodeChar(), event.getSource(), event.getDeviceId());
         return true;
     }
 
     @Override
     public boolean onKeyUp(int keyCode, KeyEvent event) {
+        if (isBackKeyEvent(keyCode, event)) {
+            if (!this.backKeyConsumed) {
+                return super.onKeyUp(keyCode, event);
+            }
+            return true;
+        }
         Ebitenmobileview.onKeyUpOnAndroid(keyCode, event.getSource(), event.getDeviceId());
         return true;
     }
@@ -444,7 +521,10 @@ public class EbitenView extends ViewGroup implements InputManager.InputDeviceLis
             int id = e.getPointerId(i);
             int x = (int)e.getX(i);
             int y = (int)e.getY(i);
-            Ebitenmobileview.updateTouchesOnAndroid(e.getActionMasked(), id, (int)pxToDp(x), (int)pxToDp(y));
+            // The contact ellipse is approximated with its major and minor axes.
+            double rx = pxToDp(e.getTouchMajor(i)) / 2;
+            double ry = pxToDp(e.getTouchMinor(i)) / 2;
+            Ebitenmobileview.updateTouchesOnAndroid(e.getActionMasked(), id, (int)pxToDp(x), (int)pxToDp(y), e.getPressure(i), rx, ry);
         }
         return true;
     }
@@ -757,6 +837,53 @@ public class EbitenView extends ViewGroup implements InputManager.InputDeviceLis
         }
     }
 
+    // bindToActivity suspends and resumes the game along with the lifecycle of the activity.
+    // This is an alternative to calling suspendGame and resumeGame at Activity's onPause and onResume.
+    public void bindToActivity(final Activity activity) {
+        activity.getApplication().registerActivityLifecycleCallbacks(new Application.ActivityLifecycleCallbacks() {
+            @Override
+            public void onActivityResumed(Activity a) {
+                if (a == activity) {
+                    resumeGame();
+                }
+            }
+
+            @Override
+            public void onActivityPaused(Activity a) {
+                if (a == activity) {
+                    suspendGame();
+                }
+            }
+
+            @Override
+            public void onActivityDestroyed(Activity a) {
+                if (a == activity) {
+                    a.getApplication().unregisterActivityLifecycleCallbacks(this);
+                }
+            }
+
+            @Override
+            public void onActivityCreated(Activity a, Bundle savedInstanceState) {
+                // Do nothing.
+            }
+
+            @Override
+            public void onActivityStarted(Activity a) {
+                // Do nothing.
+            }
+
+            @Override
+            public void onActivityStopped(Activity a) {
+                // Do nothing.
+            }
+
+            @Override
+            public void onActivitySaveInstanceState(Activity a, Bundle outState) {
+                // Do nothing.
+            }
+        });
+    }
+
     // onErrorOnGameUpdate is called on the main thread when an error happens when updating a game.
     // You can define your own error handler, e.g., using Crashlytics, by overriding this method.
     protected void onErrorOnGameUpdate(Exception e) {
@@ -765,6 +892,7 @@ public class EbitenView extends ViewGroup implements InputManager.InputDeviceLis
 
     private EbitenSurfaceView ebitenSurfaceView;
     private InputManager inputManager;
+    private boolean backKeyConsumed = false;
 }
 `
 
@@ -812,6 +940,8 @@ class EbitenSurfaceView extends GLSurfaceView implements RenderRequester {
 
         @Override
         public void onSurfaceCreated(GL10 gl, EGLConfig config) {
+            // onSurfaceCreated is called whenever a new EGL context is created, e.g., when the surface is
+            // recreated by a configuration change or a multi-window transition. All the textures are lost.
             Ebitenmobileview.onContextLost();
         }
 
@@ -833,6 +963,8 @@ class EbitenSurfaceView extends GLSurfaceView implements RenderRequester {
     private void initialize() {
         setEGLContextClientVersion(2);
         setEGLConfigChooser(8, 8, 8, 8, 0, 0);
+        // Keep the EGL context while the view is paused if possible to avoid restoring all the images.
+        setPreserveEGLContextOnPause(true);
         setRenderer(new EbitenRenderer());
         Ebitenmobileview.setRenderRequester(this);
     }
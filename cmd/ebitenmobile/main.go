@@ -16,6 +16,10 @@
 //
 // For the usage, see https://ebiten.org/documents/mobile.html.
 //
+// In addition to gomobile's flags, ebitenmobile bind accepts these flags:
+//
+//	-swiftpm  generates Package.swift for the XCFramework next to it to use it as a Swift package (iOS).
+//
 // gomobile's version is fixed by ebitenmobile.
 // You can specify gomobile's version by EBITENMOBILE_GOMOBILE environment variable.
 package main
@@ -41,7 +45,9 @@ const (
 func init() {
 	flag.Usage = func() {
 		// This message is copied from `gomobile bind -h`
-		fmt.Fprintf(os.Stderr, "%s bind [-target android|ios] [-bootclasspath <path>] [-classpath <path>] [-o output] [build flags] [package]\n", ebitenmobileCommand)
+		fmt.Fprintf(os.Stderr, "%s bind [-target android|ios] [-bootclasspath <path>] [-classpath <path>] [-o output] [-swiftpm] [build flags] [package]\n", ebitenmobileCommand)
+		fmt.Fprintln(os.Stderr, "")
+		fmt.Fprintln(os.Stderr, "  -swiftpm  generate Package.swift for the XCFramework next to it (iOS)")
 		os.Exit(2)
 	}
 	flag.Parse()
@@ -84,6 +90,9 @@ var (
 	bindJavaPkg       string // -javapkg
 	bindClasspath     string // -classpath
 	bindBootClasspath string // -bootclasspath
+
+	// The flags below are ebitenmobile's own flags and are not passed to gomobile.
+	bindSwiftPM bool // -swiftpm
 )
 
 func main() {
@@ -92,6 +101,8 @@ func main() {
 		flag.Usage()
 	}
 
+	args, bindSwiftPM = extractBoolFlag(args, "swiftpm")
+
 	var flagset flag.FlagSet
 	flagset.StringVar(&buildO, "o", "", "")
 	flagset.StringVar(&buildGcflags, "gcflags", "", "")
@@ -276,11 +287,37 @@ func doBind(args []string, flagset *flag.FlagSet, buildOS string) error {
 
 			// TODO: Remove Ebitenmobileview.objc.h?
 		}
+
+		if bindSwiftPM {
+			if err := writeSwiftPackage(buildO); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
 }
 
+// extractBoolFlag removes the boolean flag of the given name from args, and reports whether the flag is enabled.
+func extractBoolFlag(args []string, name string) ([]string, bool) {
+	var newArgs []string
+	var enabled bool
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			switch strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-") {
+			case name, name + "=true":
+				enabled = true
+				continue
+			case name + "=false":
+				enabled = false
+				continue
+			}
+		}
+		newArgs = append(newArgs, arg)
+	}
+	return newArgs, enabled
+}
+
 const objcH = `// Code generated by ebitenmobile. DO NOT EDIT.
 
 #import <UIKit/UIKit.h>
@@ -0,0 +1,75 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+const generatedHeader = "// Code generated by ebitenmobile. DO NOT EDIT."
+
+// writeGeneratedFile writes content to path.
+// writeGeneratedFile fails if a file not generated by ebitenmobile already exists at path.
+func writeGeneratedFile(path string, content []byte) error {
+	if b, err := ioutil.ReadFile(path); err == nil {
+		if !bytes.Contains(b, []byte(generatedHeader)) {
+			return fmt.Errorf("ebitenmobile: %s already exists and is not generated by ebitenmobile", path)
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	return ioutil.WriteFile(path, content, 0644)
+}
+
+// writeSwiftPackage writes Package.swift for the XCFramework at xcframeworkPath to the same directory.
+func writeSwiftPackage(xcframeworkPath string) error {
+	name := filepath.Base(xcframeworkPath)
+	var buf bytes.Buffer
+	if err := swiftPackageTmpl.Execute(&buf, struct {
+		Header string
+		Name   string
+		Path   string
+	}{
+		Header: generatedHeader,
+		Name:   strings.TrimSuffix(name, ".xcframework"),
+		Path:   name,
+	}); err != nil {
+		return err
+	}
+	return writeGeneratedFile(filepath.Join(filepath.Dir(xcframeworkPath), "Package.swift"), buf.Bytes())
+}
+
+// The binary target's name must be the same as the framework's name.
+var swiftPackageTmpl = template.Must(template.New("swiftpackage").Parse(`// swift-tools-version:5.3
+{{.Header}}
+
+import PackageDescription
+
+let package = Package(
+    name: "{{.Name}}",
+    products: [
+        .library(name: "{{.Name}}", targets: ["{{.Name}}"]),
+    ],
+    targets: [
+        .binaryTarget(name: "{{.Name}}", path: "{{.Path}}"),
+    ]
+)
+`))
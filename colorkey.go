@@ -0,0 +1,127 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"fmt"
+	"image/color"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/affine"
+	"github.com/hajimehoshi/ebiten/v2/internal/graphics"
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
+	"github.com/hajimehoshi/ebiten/v2/internal/mipmap"
+)
+
+var (
+	theColorKeyShader     *Shader
+	theColorKeyShaderOnce sync.Once
+)
+
+// colorKeyShader returns a shader to draw the 0th image with its color key pixels transparent.
+//
+// The color key is tested for each texel before filtering so that the key color doesn't bleed into the neighbors
+// with the linear filter.
+func colorKeyShader() *Shader {
+	theColorKeyShaderOnce.Do(func() {
+		s, err := NewShader([]byte(`package main
+
+var ColorKey vec3
+var ColorMBody mat4
+var ColorMTranslation vec4
+var Linear float
+
+func keyedAt(pos vec2) vec4 {
+	c := imageSrc0At(pos)
+	if c.a == 0 {
+		return c
+	}
+	d := abs(c.rgb/c.a - ColorKey)
+	if max(max(d.r, d.g), d.b) < 0.5/255 {
+		return vec4(0)
+	}
+	return c
+}
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	var c vec4
+	if Linear == 0 {
+		c = keyedAt(texCoord)
+	} else {
+		size := imageSrcTextureSize()
+		p := texCoord*size - 0.5
+		rate := fract(p)
+		p0 := (floor(p) + 0.5) / size
+		p1 := p0 + 1/size
+		c0 := keyedAt(p0)
+		c1 := keyedAt(vec2(p1.x, p0.y))
+		c2 := keyedAt(vec2(p0.x, p1.y))
+		c3 := keyedAt(p1)
+		c = mix(mix(c0, c1, rate.x), mix(c2, c3, rate.x), rate.y)
+	}
+
+	// Apply the color matrix in the same way as DrawImage.
+	c.rgb /= c.a + (1 - sign(c.a))
+	c = ColorMBody*c + ColorMTranslation
+	c.rgb *= c.a
+	c *= color
+	c.rgb = min(c.rgb, c.a)
+	return c
+}
+`))
+		if err != nil {
+			panic(fmt.Sprintf("ebiten: compiling the color key shader failed: %v", err))
+		}
+		theColorKeyShader = s
+	})
+	return theColorKeyShader
+}
+
+// drawImageWithColorKey draws img on i with the vertices vs and the indices is,
+// treating the pixels of options.ColorKey's color as transparent.
+func (i *Image) drawImageWithColorKey(img *Image, vs []float32, is []uint16, dstRegion graphicsdriver.Region, options *DrawImageOptions) {
+	if options.DepthTest {
+		panic("ebiten: DepthTest cannot be used with ColorKey")
+	}
+
+	b := img.Bounds()
+	sr := graphicsdriver.Region{
+		X:      float32(b.Min.X),
+		Y:      float32(b.Min.Y),
+		Width:  float32(b.Dx()),
+		Height: float32(b.Dy()),
+	}
+
+	key := color.NRGBAModel.Convert(options.ColorKey).(color.NRGBA)
+	var body [16]float32
+	var translation [4]float32
+	options.ColorM.affineColorM().Elements(&body, &translation)
+	var linear float32
+	if options.Filter == FilterLinear {
+		linear = 1
+	}
+
+	shader := colorKeyShader()
+	us := shader.convertUniforms(map[string]interface{}{
+		"ColorKey":          []float32{float32(key.R) / 0xff, float32(key.G) / 0xff, float32(key.B) / 0xff},
+		"ColorMBody":        body[:],
+		"ColorMTranslation": translation[:],
+		"Linear":            linear,
+	}, nil)
+
+	srcs := [graphics.ShaderImageNum]*mipmap.Mipmap{img.mipmap}
+	mode := graphicsdriver.CompositeMode(options.CompositeMode)
+	i.mipmap.DrawTriangles(srcs, vs, is, affine.ColorMIdentity{}, mode, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dstRegion, sr, [graphics.ShaderImageNum - 1][2]float32{}, shader.shader, us, false, false, canSkipMipmap(options.GeoM, graphicsdriver.FilterNearest))
+}
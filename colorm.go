@@ -17,6 +17,7 @@ package ebiten
 import (
 	"image/color"
 
+	"github.com/hajimehoshi/ebiten/v2/colorspace"
 	"github.com/hajimehoshi/ebiten/v2/internal/affine"
 )
 
@@ -93,6 +94,8 @@ func (c *ColorM) Translate(r, g, b, a float64) {
 
 // RotateHue rotates the hue.
 // theta represents rotating angle in radian.
+//
+// Deprecated: as of v2.3. Use colorm.HueRotate instead.
 func (c *ColorM) RotateHue(theta float64) {
 	c.ChangeHSV(theta, 1, 1)
 }
@@ -103,6 +106,8 @@ func (c *ColorM) RotateHue(theta float64) {
 // valueScale is a value to scale value (a.k.a. brightness).
 //
 // This conversion uses RGB to/from YCrCb conversion.
+//
+// Deprecated: as of v2.3. Use colorm.ChangeHSV instead.
 func (c *ColorM) ChangeHSV(hueTheta float64, saturationScale float64, valueScale float64) {
 	c.impl = affine.ChangeHSV(c.affineColorM(), hueTheta, float32(saturationScale), float32(valueScale))
 }
@@ -128,3 +133,111 @@ func (c *ColorM) IsInvertible() bool {
 func (c *ColorM) Invert() {
 	c.impl = c.affineColorM().Invert()
 }
+
+// LerpIn sets c to the interpolation of c and other by t in the given color space.
+//
+// The result transforms a color to one close to colorspace.Lerp(c.Apply(clr), other.Apply(clr), t, space).
+// As a ColorM is an affine transformation, the interpolation in a non-linear color space like OKLab
+// cannot be represented exactly. In this case, the RGB part is the least-squares fit over opaque sample colors,
+// and the alpha part is interpolated element-wise.
+// With colorspace.SpaceSRGB, all the elements are interpolated element-wise and the result is exact.
+//
+// LerpIn sets c to c itself when t is 0, and to other when t is 1.
+func (c *ColorM) LerpIn(other ColorM, t float64, space colorspace.Space) {
+	if t == 0 {
+		return
+	}
+	if t == 1 {
+		c.impl = other.impl
+		return
+	}
+
+	var es [ColorMDim - 1][ColorMDim]float64
+	for i := 0; i < ColorMDim-1; i++ {
+		for j := 0; j < ColorMDim; j++ {
+			e0 := c.Element(i, j)
+			es[i][j] = e0 + (other.Element(i, j)-e0)*t
+		}
+	}
+
+	if space != colorspace.SpaceSRGB {
+		fitColorMRGB(&es, c, &other, t, space)
+	}
+
+	impl := c.affineColorM()
+	for i := 0; i < ColorMDim-1; i++ {
+		for j := 0; j < ColorMDim; j++ {
+			impl = affine.ColorMSetElement(impl, i, j, float32(es[i][j]))
+		}
+	}
+	c.impl = impl
+}
+
+// fitColorMRGB updates the RGB rows of es with the least-squares fit of the interpolated colors.
+//
+// The sample colors are opaque, and then the alpha column of es is kept as it is.
+func fitColorMRGB(es *[ColorMDim - 1][ColorMDim]float64, c0, c1 *ColorM, t float64, space colorspace.Space) {
+	const n = 5
+
+	// ata is the 4x4 matrix A^T A, and atb is the 4x3 matrix A^T B, where a row of A is (r, g, b, 1)
+	// for a sample color and a row of B is the interpolated color's RGB.
+	var ata [4][4]float64
+	var atb [4][3]float64
+	for ri := 0; ri < n; ri++ {
+		for gi := 0; gi < n; gi++ {
+			for bi := 0; bi < n; bi++ {
+				x := [4]float64{float64(ri) / (n - 1), float64(gi) / (n - 1), float64(bi) / (n - 1), 1}
+				src := color.NRGBA64{
+					R: uint16(x[0] * 0xffff),
+					G: uint16(x[1] * 0xffff),
+					B: uint16(x[2] * 0xffff),
+					A: 0xffff,
+				}
+				dst := color.NRGBA64Model.Convert(colorspace.Lerp(c0.Apply(src), c1.Apply(src), t, space)).(color.NRGBA64)
+				y := [3]float64{float64(dst.R) / 0xffff, float64(dst.G) / 0xffff, float64(dst.B) / 0xffff}
+				for k := 0; k < 3; k++ {
+					// The alpha column's contribution is fixed.
+					y[k] -= es[k][3]
+				}
+				for i := 0; i < 4; i++ {
+					for j := 0; j < 4; j++ {
+						ata[i][j] += x[i] * x[j]
+					}
+					for k := 0; k < 3; k++ {
+						atb[i][k] += x[i] * y[k]
+					}
+				}
+			}
+		}
+	}
+
+	// Solve ata * m = atb by Gaussian elimination. ata is symmetric and positive definite.
+	for i := 0; i < 4; i++ {
+		p := ata[i][i]
+		for j := i; j < 4; j++ {
+			ata[i][j] /= p
+		}
+		for k := 0; k < 3; k++ {
+			atb[i][k] /= p
+		}
+		for i2 := 0; i2 < 4; i2++ {
+			if i2 == i {
+				continue
+			}
+			f := ata[i2][i]
+			for j := i; j < 4; j++ {
+				ata[i2][j] -= f * ata[i][j]
+			}
+			for k := 0; k < 3; k++ {
+				atb[i2][k] -= f * atb[i][k]
+			}
+		}
+	}
+
+	for k := 0; k < 3; k++ {
+		es[k][0] = atb[0][k]
+		es[k][1] = atb[1][k]
+		es[k][2] = atb[2][k]
+		es[k][4] = atb[3][k]
+	}
+}
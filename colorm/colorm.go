@@ -0,0 +1,386 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package colorm provides color matrices to transform colors.
+//
+// Unlike ebiten.ColorM, a ColorM in this package is a plain value.
+// Constructing and concatenating ColorM values doesn't allocate memory.
+package colorm
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+)
+
+// Dim is the dimension of a ColorM.
+const Dim = 5
+
+// ColorM represents an affine transformation of colors.
+//
+// A ColorM is a 4x5 matrix applied to a vector (r, g, b, a, 1)
+// where r, g, b and a are a color's values in straight-alpha format.
+//
+// The zero value is the identity. A ColorM is comparable.
+type ColorM struct {
+	// body is the difference from the identity of the 4x4 left part in column-major order.
+	// Storing the difference makes the zero value the identity.
+	body [16]float32
+
+	// translate is the rightmost column.
+	translate [4]float32
+}
+
+var identityBody = [16]float32{
+	1, 0, 0, 0,
+	0, 1, 0, 0,
+	0, 0, 1, 0,
+	0, 0, 0, 1,
+}
+
+func fromElements(body *[16]float32, translate *[4]float32) ColorM {
+	var c ColorM
+	for i := range body {
+		c.body[i] = body[i] - identityBody[i]
+	}
+	c.translate = *translate
+	return c
+}
+
+// Identity returns the identity matrix.
+func Identity() ColorM {
+	return ColorM{}
+}
+
+// Scale returns a matrix to scale colors by (r, g, b, a).
+func Scale(r, g, b, a float64) ColorM {
+	var c ColorM
+	c.body[0] = float32(r) - 1
+	c.body[5] = float32(g) - 1
+	c.body[10] = float32(b) - 1
+	c.body[15] = float32(a) - 1
+	return c
+}
+
+// Translate returns a matrix to translate colors by (r, g, b, a).
+func Translate(r, g, b, a float64) ColorM {
+	return ColorM{
+		translate: [4]float32{float32(r), float32(g), float32(b), float32(a)},
+	}
+}
+
+// ChannelMixer returns a matrix to mix the RGB channels.
+//
+// The new red value is r[0]*red + r[1]*green + r[2]*blue, and so on. The alpha value is kept.
+func ChannelMixer(r, g, b [3]float64) ColorM {
+	body := identityBody
+	for j := 0; j < 3; j++ {
+		body[0+j*4] = float32(r[j])
+		body[1+j*4] = float32(g[j])
+		body[2+j*4] = float32(b[j])
+	}
+	return fromElements(&body, &[4]float32{})
+}
+
+var (
+	rgbToYCbCr = ChannelMixer(
+		[3]float64{0.2990, 0.5870, 0.1140},
+		[3]float64{-0.1687, -0.3313, 0.5000},
+		[3]float64{0.5000, -0.4187, -0.0813},
+	)
+	yCbCrToRGB = ChannelMixer(
+		[3]float64{1, 0, 1.40200},
+		[3]float64{1, -0.34414, -0.71414},
+		[3]float64{1, 1.77200, 0},
+	)
+)
+
+// ChangeHSV returns a matrix to change HSV (Hue-Saturation-Value) values.
+// hueTheta is a radian value to rotate hue.
+// saturationScale is a value to scale saturation.
+// valueScale is a value to scale value (a.k.a. brightness).
+//
+// This conversion uses RGB to/from YCrCb conversion, and is the same as ebiten.ColorM's ChangeHSV.
+func ChangeHSV(hueTheta float64, saturationScale float64, valueScale float64) ColorM {
+	sin, cos := math.Sincos(hueTheta)
+	rot := ChannelMixer(
+		[3]float64{1, 0, 0},
+		[3]float64{0, cos, -sin},
+		[3]float64{0, sin, cos},
+	)
+	s := saturationScale
+	v := valueScale
+	return rgbToYCbCr.Concat(rot).Concat(Scale(v, s*v, s*v, 1)).Concat(yCbCrToRGB)
+}
+
+// HueRotate returns a matrix to rotate hue by theta in radian.
+func HueRotate(theta float64) ColorM {
+	return ChangeHSV(theta, 1, 1)
+}
+
+// Saturate returns a matrix to scale saturation by s.
+//
+// s = 0 makes colors gray, and s = 1 keeps colors.
+func Saturate(s float64) ColorM {
+	return ChangeHSV(0, s, 1)
+}
+
+// Brightness returns a matrix to add b to the RGB values.
+func Brightness(b float64) ColorM {
+	return Translate(b, b, b, 0)
+}
+
+// Contrast returns a matrix to scale the RGB values around 0.5 by c.
+//
+// c = 0 makes colors 50% gray, and c = 1 keeps colors.
+func Contrast(c float64) ColorM {
+	return Scale(c, c, c, 1).Concat(Translate(0.5-0.5*c, 0.5-0.5*c, 0.5-0.5*c, 0))
+}
+
+// Grayscale returns a matrix to convert colors to gray. amount is in [0, 1].
+//
+// amount = 0 keeps colors, and amount = 1 makes colors completely gray.
+func Grayscale(amount float64) ColorM {
+	return Identity().Lerp(ChannelMixer(
+		[3]float64{0.2990, 0.5870, 0.1140},
+		[3]float64{0.2990, 0.5870, 0.1140},
+		[3]float64{0.2990, 0.5870, 0.1140},
+	), amount)
+}
+
+// Sepia returns a matrix to convert colors to sepia tones. amount is in [0, 1].
+//
+// amount = 0 keeps colors, and amount = 1 makes colors completely sepia.
+func Sepia(amount float64) ColorM {
+	return Identity().Lerp(ChannelMixer(
+		[3]float64{0.393, 0.769, 0.189},
+		[3]float64{0.349, 0.686, 0.168},
+		[3]float64{0.272, 0.534, 0.131},
+	), amount)
+}
+
+// Elements writes the elements of c to body and translate.
+//
+// body is the 4x4 left part in column-major order, and translate is the rightmost column.
+// These can be passed to a shader as mat4 and vec4 uniform variables like body[:] and translate[:].
+func (c ColorM) Elements(body *[16]float32, translate *[4]float32) {
+	for i := range body {
+		body[i] = c.body[i] + identityBody[i]
+	}
+	*translate = c.translate
+}
+
+// Element returns a value of the matrix at (i, j).
+func (c ColorM) Element(i, j int) float64 {
+	if j < Dim-1 {
+		return float64(c.body[i+j*(Dim-1)] + identityBody[i+j*(Dim-1)])
+	}
+	return float64(c.translate[i])
+}
+
+// WithElement returns a copy of c whose element at (i, j) is e.
+func (c ColorM) WithElement(i, j int, e float64) ColorM {
+	if j < Dim-1 {
+		c.body[i+j*(Dim-1)] = float32(e) - identityBody[i+j*(Dim-1)]
+	} else {
+		c.translate[i] = float32(e)
+	}
+	return c
+}
+
+// IsIdentity reports whether c is the identity.
+func (c ColorM) IsIdentity() bool {
+	return c == ColorM{}
+}
+
+func (c ColorM) isScaleOnly() bool {
+	if c.translate != [4]float32{} {
+		return false
+	}
+	for i, v := range c.body {
+		if i%5 != 0 && v != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Concat returns the matrix that applies c and then other.
+// This is same as multiplying the matrix other and the matrix c in this order.
+func (c ColorM) Concat(other ColorM) ColorM {
+	if c.IsIdentity() {
+		return other
+	}
+	if other.IsIdentity() {
+		return c
+	}
+
+	var lb, rb [16]float32
+	var lt, rt [4]float32
+	other.Elements(&lb, &lt)
+	c.Elements(&rb, &rt)
+
+	var body [16]float32
+	var translate [4]float32
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 4; j++ {
+			var v float32
+			for k := 0; k < 4; k++ {
+				v += lb[i+k*4] * rb[k+j*4]
+			}
+			body[i+j*4] = v
+		}
+		v := lt[i]
+		for k := 0; k < 4; k++ {
+			v += lb[i+k*4] * rt[k]
+		}
+		translate[i] = v
+	}
+	return fromElements(&body, &translate)
+}
+
+// Lerp returns the element-wise linear interpolation of c and other by t.
+func (c ColorM) Lerp(other ColorM, t float64) ColorM {
+	tf := float32(t)
+	for i := range c.body {
+		c.body[i] += (other.body[i] - c.body[i]) * tf
+	}
+	for i := range c.translate {
+		c.translate[i] += (other.translate[i] - c.translate[i]) * tf
+	}
+	return c
+}
+
+// Apply pre-multiplies a vector (r, g, b, a, 1) by the matrix
+// where r, g, b, and a are clr's values in straight-alpha format.
+// In other words, Apply calculates ColorM * (r, g, b, a, 1)^T.
+func (c ColorM) Apply(clr color.Color) color.Color {
+	r, g, b, a := clr.RGBA()
+	var v [4]float32
+	// Unmultiply alpha
+	if a > 0 {
+		v = [4]float32{float32(r) / float32(a), float32(g) / float32(a), float32(b) / float32(a), float32(a) / 0xffff}
+	}
+
+	var body [16]float32
+	var translate [4]float32
+	c.Elements(&body, &translate)
+	var out [4]float32
+	for i := 0; i < 4; i++ {
+		o := translate[i]
+		for k := 0; k < 4; k++ {
+			o += body[i+k*4] * v[k]
+		}
+		if o < 0 {
+			o = 0
+		}
+		if o > 1 {
+			o = 1
+		}
+		out[i] = o
+	}
+	return color.NRGBA64{
+		R: uint16(out[0] * 0xffff),
+		G: uint16(out[1] * 0xffff),
+		B: uint16(out[2] * 0xffff),
+		A: uint16(out[3] * 0xffff),
+	}
+}
+
+// IsInvertible reports whether c is invertible.
+func (c ColorM) IsInvertible() bool {
+	_, ok := c.inverse()
+	return ok
+}
+
+// Invert returns the inverse of c.
+// If c is not invertible, Invert panics.
+func (c ColorM) Invert() ColorM {
+	inv, ok := c.inverse()
+	if !ok {
+		panic("colorm: c is not invertible")
+	}
+	return inv
+}
+
+func (c ColorM) inverse() (ColorM, bool) {
+	if c.IsIdentity() {
+		return c, true
+	}
+
+	var body [16]float32
+	var translate [4]float32
+	c.Elements(&body, &translate)
+
+	// Gauss-Jordan elimination of the 4x4 part with partial pivoting.
+	var m, inv [4][4]float64
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 4; j++ {
+			m[i][j] = float64(body[i+j*4])
+		}
+		inv[i][i] = 1
+	}
+	for j := 0; j < 4; j++ {
+		p := j
+		for i := j + 1; i < 4; i++ {
+			if math.Abs(m[i][j]) > math.Abs(m[p][j]) {
+				p = i
+			}
+		}
+		if m[p][j] == 0 {
+			return ColorM{}, false
+		}
+		m[j], m[p] = m[p], m[j]
+		inv[j], inv[p] = inv[p], inv[j]
+		d := m[j][j]
+		for k := 0; k < 4; k++ {
+			m[j][k] /= d
+			inv[j][k] /= d
+		}
+		for i := 0; i < 4; i++ {
+			if i == j {
+				continue
+			}
+			f := m[i][j]
+			for k := 0; k < 4; k++ {
+				m[i][k] -= f * m[j][k]
+				inv[i][k] -= f * inv[j][k]
+			}
+		}
+	}
+
+	var ibody [16]float32
+	var itranslate [4]float32
+	for i := 0; i < 4; i++ {
+		var t float64
+		for k := 0; k < 4; k++ {
+			ibody[i+k*4] = float32(inv[i][k])
+			t -= inv[i][k] * float64(translate[k])
+		}
+		itranslate[i] = float32(t)
+	}
+	return fromElements(&ibody, &itranslate), true
+}
+
+// String returns a string representation of ColorM.
+func (c ColorM) String() string {
+	var b [16]float32
+	var t [4]float32
+	c.Elements(&b, &t)
+	return fmt.Sprintf("[[%f, %f, %f, %f, %f], [%f, %f, %f, %f, %f], [%f, %f, %f, %f, %f], [%f, %f, %f, %f, %f]]",
+		b[0], b[4], b[8], b[12], t[0],
+		b[1], b[5], b[9], b[13], t[1],
+		b[2], b[6], b[10], b[14], t[2],
+		b[3], b[7], b[11], b[15], t[3])
+}
@@ -0,0 +1,153 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package colorm_test
+
+import (
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/colorm"
+)
+
+func TestZeroIsIdentity(t *testing.T) {
+	var c colorm.ColorM
+	if !c.IsIdentity() {
+		t.Errorf("IsIdentity(): got: false, want: true")
+	}
+	for i := 0; i < colorm.Dim-1; i++ {
+		for j := 0; j < colorm.Dim; j++ {
+			want := 0.0
+			if i == j {
+				want = 1
+			}
+			if got := c.Element(i, j); got != want {
+				t.Errorf("Element(%d, %d): got: %f, want: %f", i, j, got, want)
+			}
+		}
+	}
+}
+
+func equalColorM(a, b colorm.ColorM, delta float64) bool {
+	for i := 0; i < colorm.Dim-1; i++ {
+		for j := 0; j < colorm.Dim; j++ {
+			if math.Abs(a.Element(i, j)-b.Element(i, j)) > delta {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestChangeHSVMatchesEbiten(t *testing.T) {
+	for _, v := range [][3]float64{
+		{0, 1, 1},
+		{math.Pi / 3, 1, 1},
+		{1, 0.5, 0.8},
+		{-2, 0, 1.2},
+	} {
+		var m ebiten.ColorM
+		m.ChangeHSV(v[0], v[1], v[2])
+		got := colorm.ChangeHSV(v[0], v[1], v[2])
+		if want := colorm.FromColorM(&m); !equalColorM(got, want, 1e-5) {
+			t.Errorf("ChangeHSV(%v): got: %v, want: %v", v, got, want)
+		}
+	}
+}
+
+func TestConcat(t *testing.T) {
+	c := colorm.Scale(0.5, 1, 1, 1).Concat(colorm.Translate(0.25, 0, 0, 0))
+	if got, want := c.Element(0, 0), 0.5; got != want {
+		t.Errorf("Element(0, 0): got: %f, want: %f", got, want)
+	}
+	if got, want := c.Element(0, 4), 0.25; got != want {
+		t.Errorf("Element(0, 4): got: %f, want: %f", got, want)
+	}
+
+	// Translating first and then scaling scales the translation too.
+	c = colorm.Translate(0.25, 0, 0, 0).Concat(colorm.Scale(0.5, 1, 1, 1))
+	if got, want := c.Element(0, 4), 0.125; got != want {
+		t.Errorf("Element(0, 4): got: %f, want: %f", got, want)
+	}
+}
+
+func TestConcatMatchesEbiten(t *testing.T) {
+	a := colorm.ChangeHSV(1, 0.5, 0.8).Concat(colorm.Translate(0.1, 0.2, 0.3, 0))
+	b := colorm.Sepia(0.7).Concat(colorm.Contrast(1.5))
+
+	ea := a.ToColorM()
+	eb := b.ToColorM()
+	ea.Concat(eb)
+	if got, want := a.Concat(b), colorm.FromColorM(&ea); !equalColorM(got, want, 1e-5) {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+}
+
+func TestInvert(t *testing.T) {
+	c := colorm.ChangeHSV(1, 0.5, 0.8).Concat(colorm.Translate(0.1, 0.2, 0.3, 0))
+	if !c.IsInvertible() {
+		t.Fatalf("IsInvertible(): got: false, want: true")
+	}
+	if got := c.Concat(c.Invert()); !equalColorM(got, colorm.Identity(), 1e-5) {
+		t.Errorf("got: %v, want: identity", got)
+	}
+	if colorm.Saturate(0).IsInvertible() {
+		t.Errorf("Saturate(0).IsInvertible(): got: true, want: false")
+	}
+}
+
+func TestPresets(t *testing.T) {
+	clr := color.NRGBA{0xff, 0x80, 0x40, 0xff}
+	cases := []struct {
+		Name   string
+		ColorM colorm.ColorM
+		Out    color.NRGBA
+	}{
+		{
+			Name:   "Grayscale(0)",
+			ColorM: colorm.Grayscale(0),
+			Out:    clr,
+		},
+		{
+			Name:   "Grayscale(1)",
+			ColorM: colorm.Grayscale(1),
+			Out:    color.NRGBA{0xa0, 0xa0, 0xa0, 0xff},
+		},
+		{
+			Name:   "Contrast(0)",
+			ColorM: colorm.Contrast(0),
+			Out:    color.NRGBA{0x7f, 0x7f, 0x7f, 0xff},
+		},
+		{
+			Name:   "Brightness(-1)",
+			ColorM: colorm.Brightness(-1),
+			Out:    color.NRGBA{0, 0, 0, 0xff},
+		},
+	}
+	for _, c := range cases {
+		got := color.NRGBAModel.Convert(c.ColorM.Apply(clr)).(color.NRGBA)
+		if diff(got.R, c.Out.R) > 1 || diff(got.G, c.Out.G) > 1 || diff(got.B, c.Out.B) > 1 || got.A != c.Out.A {
+			t.Errorf("%s: got: %v, want: %v", c.Name, got, c.Out)
+		}
+	}
+}
+
+func diff(x, y uint8) uint8 {
+	if x < y {
+		return y - x
+	}
+	return x - y
+}
@@ -0,0 +1,75 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package colorm
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// ToColorM returns an ebiten.ColorM with the same elements as c.
+func (c ColorM) ToColorM() ebiten.ColorM {
+	var m ebiten.ColorM
+	if c.IsIdentity() {
+		return m
+	}
+	if c.isScaleOnly() {
+		// A scaling-only matrix is treated specially in ebiten.ColorM and is more likely to be batched.
+		m.Scale(c.Element(0, 0), c.Element(1, 1), c.Element(2, 2), c.Element(3, 3))
+		return m
+	}
+	for i := 0; i < Dim-1; i++ {
+		for j := 0; j < Dim; j++ {
+			m.SetElement(i, j, c.Element(i, j))
+		}
+	}
+	return m
+}
+
+// FromColorM returns a ColorM with the same elements as m.
+func FromColorM(m *ebiten.ColorM) ColorM {
+	var c ColorM
+	for i := 0; i < Dim-1; i++ {
+		for j := 0; j < Dim; j++ {
+			c = c.WithElement(i, j, m.Element(i, j))
+		}
+	}
+	return c
+}
+
+// DrawImage draws src onto dst with the color matrix c.
+//
+// c is applied after op.ColorM. op is not modified. op can be nil.
+func DrawImage(dst, src *ebiten.Image, c ColorM, op *ebiten.DrawImageOptions) {
+	var o ebiten.DrawImageOptions
+	if op != nil {
+		o = *op
+	}
+	cm := c.ToColorM()
+	o.ColorM.Concat(cm)
+	dst.DrawImage(src, &o)
+}
+
+// DrawTriangles draws triangles with the specified vertices and their indices onto dst with the color matrix c.
+//
+// c is applied after op.ColorM. op is not modified. op can be nil.
+func DrawTriangles(dst *ebiten.Image, vertices []ebiten.Vertex, indices []uint16, src *ebiten.Image, c ColorM, op *ebiten.DrawTrianglesOptions) {
+	var o ebiten.DrawTrianglesOptions
+	if op != nil {
+		o = *op
+	}
+	cm := c.ToColorM()
+	o.ColorM.Concat(cm)
+	dst.DrawTriangles(vertices, indices, src, &o)
+}
@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/colorspace"
 )
 
 func TestColorMInit(t *testing.T) {
@@ -244,3 +245,61 @@ func TestColorMConcat(t *testing.T) {
 		t.Errorf("got: %f, want: %f", got, want)
 	}
 }
+
+func TestColorMLerpIn(t *testing.T) {
+	var a, b ebiten.ColorM
+	a.Scale(1, 0, 0, 1)
+	b.Scale(0, 0, 1, 1)
+	b.Translate(0, 0, 0, -0.5)
+
+	for _, s := range []colorspace.Space{colorspace.SpaceSRGB, colorspace.SpaceLinearRGB, colorspace.SpaceOKLab, colorspace.SpaceOKLCH} {
+		m := a
+		m.LerpIn(b, 0, s)
+		for i := 0; i < ebiten.ColorMDim-1; i++ {
+			for j := 0; j < ebiten.ColorMDim; j++ {
+				if got, want := m.Element(i, j), a.Element(i, j); got != want {
+					t.Errorf("space: %d, t: 0, m.Element(%d, %d): got: %f, want: %f", s, i, j, got, want)
+				}
+			}
+		}
+
+		m = a
+		m.LerpIn(b, 1, s)
+		for i := 0; i < ebiten.ColorMDim-1; i++ {
+			for j := 0; j < ebiten.ColorMDim; j++ {
+				if got, want := m.Element(i, j), b.Element(i, j); got != want {
+					t.Errorf("space: %d, t: 1, m.Element(%d, %d): got: %f, want: %f", s, i, j, got, want)
+				}
+			}
+		}
+
+		// The alpha row is always interpolated element-wise.
+		m = a
+		m.LerpIn(b, 0.5, s)
+		if got, want := m.Element(3, 4), -0.25; math.Abs(got-want) > 1e-6 {
+			t.Errorf("space: %d, m.Element(3, 4): got: %f, want: %f", s, got, want)
+		}
+	}
+
+	// In SpaceSRGB, LerpIn is the element-wise interpolation.
+	m := a
+	m.LerpIn(b, 0.25, colorspace.SpaceSRGB)
+	if got, want := m.Element(0, 0), 0.75; math.Abs(got-want) > 1e-6 {
+		t.Errorf("m.Element(0, 0): got: %f, want: %f", got, want)
+	}
+	if got, want := m.Element(2, 2), 0.25; math.Abs(got-want) > 1e-6 {
+		t.Errorf("m.Element(2, 2): got: %f, want: %f", got, want)
+	}
+
+	// In SpaceLinearRGB, the middle of a black and a white is brighter than the plain RGB one.
+	var black, white ebiten.ColorM
+	black.Scale(0, 0, 0, 1)
+	white.Scale(0, 0, 0, 1)
+	white.Translate(1, 1, 1, 0)
+	m = black
+	m.LerpIn(white, 0.5, colorspace.SpaceLinearRGB)
+	r, _, _, _ := m.Apply(color.Black).RGBA()
+	if r <= 0x8000 {
+		t.Errorf("m.Apply(color.Black): got: R=%d, want: R > 0x8000", r)
+	}
+}
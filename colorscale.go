@@ -0,0 +1,114 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"fmt"
+	"image/color"
+)
+
+// A ColorScale represents multipliers for the RGBA components of colors when rendering an image.
+//
+// A ColorScale is applied to the straight alpha color like ColorM's Scale, but is much cheaper than a ColorM
+// since a ColorScale never breaks batching draw calls.
+//
+// The initial value is identity, which doesn't change any color.
+type ColorScale struct {
+	// The actual values minus 1, so that the zero value is identity.
+	r_1 float32
+	g_1 float32
+	b_1 float32
+	a_1 float32
+}
+
+// String returns a string representation of ColorScale.
+func (c *ColorScale) String() string {
+	return fmt.Sprintf("[%f, %f, %f, %f]", c.R(), c.G(), c.B(), c.A())
+}
+
+// Reset resets the ColorScale as identity.
+func (c *ColorScale) Reset() {
+	c.r_1 = 0
+	c.g_1 = 0
+	c.b_1 = 0
+	c.a_1 = 0
+}
+
+// R returns the multiplier for the red component.
+func (c *ColorScale) R() float32 {
+	return c.r_1 + 1
+}
+
+// G returns the multiplier for the green component.
+func (c *ColorScale) G() float32 {
+	return c.g_1 + 1
+}
+
+// B returns the multiplier for the blue component.
+func (c *ColorScale) B() float32 {
+	return c.b_1 + 1
+}
+
+// A returns the multiplier for the alpha component.
+func (c *ColorScale) A() float32 {
+	return c.a_1 + 1
+}
+
+// SetR overwrites the multiplier for the red component.
+func (c *ColorScale) SetR(r float32) {
+	c.r_1 = r - 1
+}
+
+// SetG overwrites the multiplier for the green component.
+func (c *ColorScale) SetG(g float32) {
+	c.g_1 = g - 1
+}
+
+// SetB overwrites the multiplier for the blue component.
+func (c *ColorScale) SetB(b float32) {
+	c.b_1 = b - 1
+}
+
+// SetA overwrites the multiplier for the alpha component.
+func (c *ColorScale) SetA(a float32) {
+	c.a_1 = a - 1
+}
+
+// Scale multiplies the current multipliers by the given values.
+func (c *ColorScale) Scale(r, g, b, a float32) {
+	c.r_1 = c.R()*r - 1
+	c.g_1 = c.G()*g - 1
+	c.b_1 = c.B()*b - 1
+	c.a_1 = c.A()*a - 1
+}
+
+// ScaleAlpha multiplies the current alpha multiplier by the given value.
+func (c *ColorScale) ScaleAlpha(a float32) {
+	c.a_1 = c.A()*a - 1
+}
+
+// ScaleWithColor multiplies the current multipliers by the straight alpha components of the given color.
+func (c *ColorScale) ScaleWithColor(clr color.Color) {
+	cr, cg, cb, ca := clr.RGBA()
+	if ca == 0 {
+		c.Scale(0, 0, 0, 0)
+		return
+	}
+	c.Scale(float32(cr)/float32(ca), float32(cg)/float32(ca), float32(cb)/float32(ca), float32(ca)/0xffff)
+}
+
+func (c *ColorScale) isIdentity() bool {
+	return c.r_1 == 0 && c.g_1 == 0 && c.b_1 == 0 && c.a_1 == 0
+}
@@ -0,0 +1,65 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten_test
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+func TestColorScaleInit(t *testing.T) {
+	var c ebiten.ColorScale
+	if got, want := [4]float32{c.R(), c.G(), c.B(), c.A()}, [4]float32{1, 1, 1, 1}; got != want {
+		t.Errorf("got %v; want %v", got, want)
+	}
+
+	c.Scale(0.5, 0.25, 2, 0)
+	c.Reset()
+	if got, want := [4]float32{c.R(), c.G(), c.B(), c.A()}, [4]float32{1, 1, 1, 1}; got != want {
+		t.Errorf("got %v; want %v", got, want)
+	}
+}
+
+func TestColorScaleScale(t *testing.T) {
+	var c ebiten.ColorScale
+	c.Scale(0.5, 0.25, 2, 1)
+	c.Scale(0.5, 2, 2, 0.5)
+	c.ScaleAlpha(0.5)
+	if got, want := [4]float32{c.R(), c.G(), c.B(), c.A()}, [4]float32{0.25, 0.5, 4, 0.25}; got != want {
+		t.Errorf("got %v; want %v", got, want)
+	}
+
+	c.SetR(1)
+	c.SetA(2)
+	if got, want := [4]float32{c.R(), c.G(), c.B(), c.A()}, [4]float32{1, 0.5, 4, 2}; got != want {
+		t.Errorf("got %v; want %v", got, want)
+	}
+}
+
+func TestColorScaleScaleWithColor(t *testing.T) {
+	var c ebiten.ColorScale
+	c.ScaleWithColor(color.RGBA{0x40, 0x20, 0, 0x80})
+	if got, want := [4]float32{c.R(), c.G(), c.B(), c.A()}, [4]float32{0.5, 0.25, 0, float32(0x8080) / 0xffff}; got != want {
+		t.Errorf("got %v; want %v", got, want)
+	}
+
+	c.Reset()
+	c.ScaleWithColor(color.Transparent)
+	if got, want := [4]float32{c.R(), c.G(), c.B(), c.A()}, [4]float32{0, 0, 0, 0}; got != want {
+		t.Errorf("got %v; want %v", got, want)
+	}
+}
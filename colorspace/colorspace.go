@@ -0,0 +1,281 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package colorspace provides color space conversions and interpolations.
+//
+// Colors in Ebiten are gamma-encoded sRGB values. Interpolating such values directly
+// tends to produce dark and muddy intermediate colors.
+// Interpolating in linear RGB, OKLab or OKLCH gives more natural fades and hue shifts.
+package colorspace
+
+import (
+	"image/color"
+	"math"
+)
+
+// Space represents a color space where colors are interpolated.
+type Space int
+
+const (
+	// SpaceSRGB represents the gamma-encoded sRGB color space.
+	// Interpolating in SpaceSRGB is the same as the plain RGB interpolation.
+	SpaceSRGB Space = iota
+
+	// SpaceLinearRGB represents the linear RGB color space with the sRGB primaries.
+	SpaceLinearRGB
+
+	// SpaceOKLab represents the OKLab color space.
+	SpaceOKLab
+
+	// SpaceOKLCH represents the OKLCH color space, the polar form of OKLab.
+	// The hue is interpolated along the shorter arc.
+	SpaceOKLCH
+)
+
+// SRGBToLinear converts a gamma-encoded sRGB component value to a linear one.
+//
+// v is usually in [0, 1]. A negative value is converted symmetrically.
+func SRGBToLinear(v float64) float64 {
+	if v < 0 {
+		return -SRGBToLinear(-v)
+	}
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+// LinearToSRGB converts a linear component value to a gamma-encoded sRGB one.
+//
+// v is usually in [0, 1]. A negative value is converted symmetrically.
+func LinearToSRGB(v float64) float64 {
+	if v < 0 {
+		return -LinearToSRGB(-v)
+	}
+	if v <= 0.0031308 {
+		return v * 12.92
+	}
+	return 1.055*math.Pow(v, 1/2.4) - 0.055
+}
+
+// OKLab represents a color in the OKLab color space with a straight (non-premultiplied) alpha.
+//
+// L is the perceived lightness in [0, 1]. A and B are the green-red and the blue-yellow axes,
+// usually in [-0.4, 0.4]. Alpha is in [0, 1].
+type OKLab struct {
+	L     float64
+	A     float64
+	B     float64
+	Alpha float64
+}
+
+// RGBA implements color.Color.
+//
+// Colors out of the sRGB gamut are clamped.
+func (c OKLab) RGBA() (r, g, b, a uint32) {
+	lr, lg, lb := okLabToLinearRGB(c.L, c.A, c.B)
+	return nrgbaToRGBA(LinearToSRGB(lr), LinearToSRGB(lg), LinearToSRGB(lb), c.Alpha)
+}
+
+// OKLCH represents a color in the OKLCH color space with a straight (non-premultiplied) alpha.
+//
+// L is the perceived lightness in [0, 1]. C is the chroma, usually in [0, 0.4].
+// H is the hue in radian. Alpha is in [0, 1].
+type OKLCH struct {
+	L     float64
+	C     float64
+	H     float64
+	Alpha float64
+}
+
+// RGBA implements color.Color.
+//
+// Colors out of the sRGB gamut are clamped.
+func (c OKLCH) RGBA() (r, g, b, a uint32) {
+	return c.okLab().RGBA()
+}
+
+func (c OKLCH) okLab() OKLab {
+	return OKLab{
+		L:     c.L,
+		A:     c.C * math.Cos(c.H),
+		B:     c.C * math.Sin(c.H),
+		Alpha: c.Alpha,
+	}
+}
+
+// OKLabModel is the color model for OKLab.
+var OKLabModel color.Model = color.ModelFunc(okLabModel)
+
+// OKLCHModel is the color model for OKLCH.
+var OKLCHModel color.Model = color.ModelFunc(okLCHModel)
+
+func okLabModel(c color.Color) color.Color {
+	if c, ok := c.(OKLab); ok {
+		return c
+	}
+	if c, ok := c.(OKLCH); ok {
+		return c.okLab()
+	}
+	r, g, b, a := colorToNRGBA(c)
+	return toOKLab(r, g, b, a)
+}
+
+func okLCHModel(c color.Color) color.Color {
+	if c, ok := c.(OKLCH); ok {
+		return c
+	}
+	return toOKLCH(okLabModel(c).(OKLab))
+}
+
+func toOKLab(r, g, b, alpha float64) OKLab {
+	l, a, bb := linearRGBToOKLab(SRGBToLinear(r), SRGBToLinear(g), SRGBToLinear(b))
+	return OKLab{
+		L:     l,
+		A:     a,
+		B:     bb,
+		Alpha: alpha,
+	}
+}
+
+func toOKLCH(c OKLab) OKLCH {
+	return OKLCH{
+		L:     c.L,
+		C:     math.Hypot(c.A, c.B),
+		H:     math.Atan2(c.B, c.A),
+		Alpha: c.Alpha,
+	}
+}
+
+// linearRGBToOKLab converts a linear RGB color to OKLab.
+// See https://bottosson.github.io/posts/oklab/.
+func linearRGBToOKLab(r, g, b float64) (float64, float64, float64) {
+	l := math.Cbrt(0.4122214708*r + 0.5363325363*g + 0.0514459929*b)
+	m := math.Cbrt(0.2119034982*r + 0.6806995451*g + 0.1073969566*b)
+	s := math.Cbrt(0.0883024619*r + 0.2817188376*g + 0.6299787005*b)
+	return 0.2104542553*l + 0.7936177850*m - 0.0040720468*s,
+		1.9779984951*l - 2.4285922050*m + 0.4505937099*s,
+		0.0259040371*l + 0.7827717662*m - 0.8086757660*s
+}
+
+// okLabToLinearRGB converts an OKLab color to linear RGB.
+func okLabToLinearRGB(l, a, b float64) (float64, float64, float64) {
+	l2 := l + 0.3963377774*a + 0.2158037573*b
+	m2 := l - 0.1055613458*a - 0.0638541728*b
+	s2 := l - 0.0894841775*a - 1.2914855480*b
+	l3 := l2 * l2 * l2
+	m3 := m2 * m2 * m2
+	s3 := s2 * s2 * s2
+	return 4.0767416621*l3 - 3.3077115913*m3 + 0.2309699292*s3,
+		-1.2684380046*l3 + 2.6097574011*m3 - 0.3413193965*s3,
+		-0.0041960863*l3 - 0.7034186147*m3 + 1.7076147010*s3
+}
+
+// Lerp linearly interpolates the two colors c0 and c1 in the given color space.
+//
+// t is usually in [0, 1]. Lerp returns c0 when t is 0, and c1 when t is 1.
+// The alpha values are interpolated linearly in any color space.
+// When one of the colors is fully transparent, only the alpha value of the other color is interpolated.
+func Lerp(c0, c1 color.Color, t float64, space Space) color.Color {
+	r0, g0, b0, a0 := colorToNRGBA(c0)
+	r1, g1, b1, a1 := colorToNRGBA(c1)
+	if a0 == 0 {
+		r0, g0, b0 = r1, g1, b1
+	}
+	if a1 == 0 {
+		r1, g1, b1 = r0, g0, b0
+	}
+	a := lerp(a0, a1, t)
+
+	switch space {
+	case SpaceSRGB:
+		return toNRGBA64(lerp(r0, r1, t), lerp(g0, g1, t), lerp(b0, b1, t), a)
+	case SpaceLinearRGB:
+		r := lerp(SRGBToLinear(r0), SRGBToLinear(r1), t)
+		g := lerp(SRGBToLinear(g0), SRGBToLinear(g1), t)
+		b := lerp(SRGBToLinear(b0), SRGBToLinear(b1), t)
+		return toNRGBA64(LinearToSRGB(r), LinearToSRGB(g), LinearToSRGB(b), a)
+	case SpaceOKLab:
+		lab0 := toOKLab(r0, g0, b0, a0)
+		lab1 := toOKLab(r1, g1, b1, a1)
+		return toNRGBA64FromOKLab(OKLab{
+			L:     lerp(lab0.L, lab1.L, t),
+			A:     lerp(lab0.A, lab1.A, t),
+			B:     lerp(lab0.B, lab1.B, t),
+			Alpha: a,
+		})
+	case SpaceOKLCH:
+		lch0 := toOKLCH(toOKLab(r0, g0, b0, a0))
+		lch1 := toOKLCH(toOKLab(r1, g1, b1, a1))
+		// An achromatic color doesn't have a meaningful hue. Use the other color's hue.
+		const achromatic = 1e-6
+		if lch0.C < achromatic {
+			lch0.H = lch1.H
+		}
+		if lch1.C < achromatic {
+			lch1.H = lch0.H
+		}
+		dh := math.Remainder(lch1.H-lch0.H, 2*math.Pi)
+		return toNRGBA64FromOKLab(OKLCH{
+			L:     lerp(lch0.L, lch1.L, t),
+			C:     lerp(lch0.C, lch1.C, t),
+			H:     lch0.H + dh*t,
+			Alpha: a,
+		}.okLab())
+	default:
+		panic("colorspace: invalid color space")
+	}
+}
+
+func lerp(x, y, t float64) float64 {
+	return x + (y-x)*t
+}
+
+// colorToNRGBA returns the straight-alpha components of c in [0, 1].
+func colorToNRGBA(c color.Color) (r, g, b, a float64) {
+	cr, cg, cb, ca := c.RGBA()
+	if ca == 0 {
+		return 0, 0, 0, 0
+	}
+	return float64(cr) / float64(ca), float64(cg) / float64(ca), float64(cb) / float64(ca), float64(ca) / 0xffff
+}
+
+func clamp(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
+
+func nrgbaToRGBA(r, g, b, a float64) (uint32, uint32, uint32, uint32) {
+	a = clamp(a)
+	return uint32(clamp(r)*a*0xffff + 0.5), uint32(clamp(g)*a*0xffff + 0.5), uint32(clamp(b)*a*0xffff + 0.5), uint32(a*0xffff + 0.5)
+}
+
+func toNRGBA64(r, g, b, a float64) color.NRGBA64 {
+	return color.NRGBA64{
+		R: uint16(clamp(r)*0xffff + 0.5),
+		G: uint16(clamp(g)*0xffff + 0.5),
+		B: uint16(clamp(b)*0xffff + 0.5),
+		A: uint16(clamp(a)*0xffff + 0.5),
+	}
+}
+
+func toNRGBA64FromOKLab(c OKLab) color.NRGBA64 {
+	r, g, b := okLabToLinearRGB(c.L, c.A, c.B)
+	return toNRGBA64(LinearToSRGB(r), LinearToSRGB(g), LinearToSRGB(b), c.Alpha)
+}
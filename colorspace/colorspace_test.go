@@ -0,0 +1,118 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package colorspace_test
+
+import (
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2/colorspace"
+)
+
+func TestSRGBLinearRoundTrip(t *testing.T) {
+	for i := 0; i <= 255; i++ {
+		v := float64(i) / 255
+		got := colorspace.LinearToSRGB(colorspace.SRGBToLinear(v))
+		if math.Abs(got-v) > 1e-9 {
+			t.Errorf("LinearToSRGB(SRGBToLinear(%f)): got: %f, want: %f", v, got, v)
+		}
+	}
+}
+
+func TestOKLabWhite(t *testing.T) {
+	got := colorspace.OKLabModel.Convert(color.White).(colorspace.OKLab)
+	if math.Abs(got.L-1) > 1e-4 || math.Abs(got.A) > 1e-4 || math.Abs(got.B) > 1e-4 || got.Alpha != 1 {
+		t.Errorf("got: %v, want: {1 0 0 1}", got)
+	}
+}
+
+func TestOKLabRoundTrip(t *testing.T) {
+	for _, clr := range []color.NRGBA{
+		{0, 0, 0, 0xff},
+		{0xff, 0, 0, 0xff},
+		{0, 0xff, 0, 0xff},
+		{0, 0, 0xff, 0xff},
+		{0x12, 0x34, 0x56, 0xff},
+		{0xff, 0x80, 0x40, 0x80},
+	} {
+		for _, m := range []color.Model{colorspace.OKLabModel, colorspace.OKLCHModel} {
+			got := color.NRGBAModel.Convert(m.Convert(clr)).(color.NRGBA)
+			if diff(got.R, clr.R) > 1 || diff(got.G, clr.G) > 1 || diff(got.B, clr.B) > 1 || diff(got.A, clr.A) > 1 {
+				t.Errorf("got: %v, want: %v", got, clr)
+			}
+		}
+	}
+}
+
+func diff(x, y uint8) uint8 {
+	if x < y {
+		return y - x
+	}
+	return x - y
+}
+
+func TestLerpEnds(t *testing.T) {
+	c0 := color.NRGBA{0xff, 0, 0, 0xff}
+	c1 := color.NRGBA{0, 0, 0xff, 0x80}
+	for _, s := range []colorspace.Space{colorspace.SpaceSRGB, colorspace.SpaceLinearRGB, colorspace.SpaceOKLab, colorspace.SpaceOKLCH} {
+		if got := color.NRGBAModel.Convert(colorspace.Lerp(c0, c1, 0, s)).(color.NRGBA); got != c0 {
+			t.Errorf("Lerp(%v, %v, 0, %d): got: %v, want: %v", c0, c1, s, got, c0)
+		}
+		if got := color.NRGBAModel.Convert(colorspace.Lerp(c0, c1, 1, s)).(color.NRGBA); diff(got.R, c1.R) > 1 || diff(got.G, c1.G) > 1 || diff(got.B, c1.B) > 1 || got.A != c1.A {
+			t.Errorf("Lerp(%v, %v, 1, %d): got: %v, want: %v", c0, c1, s, got, c1)
+		}
+	}
+}
+
+func TestLerpMiddle(t *testing.T) {
+	black := color.NRGBA{0, 0, 0, 0xff}
+	white := color.NRGBA{0xff, 0xff, 0xff, 0xff}
+
+	got := colorspace.Lerp(black, white, 0.5, colorspace.SpaceSRGB).(color.NRGBA64)
+	if got.R != 0x8000 {
+		t.Errorf("SpaceSRGB: got: %v, want: R=0x8000", got)
+	}
+
+	// The middle of black and white in linear RGB is brighter than the plain RGB one.
+	got = colorspace.Lerp(black, white, 0.5, colorspace.SpaceLinearRGB).(color.NRGBA64)
+	if got.R <= 0x8000 || got.R != got.G || got.G != got.B {
+		t.Errorf("SpaceLinearRGB: got: %v, want: a gray brighter than 0x8000", got)
+	}
+}
+
+func TestLerpOKLCHHue(t *testing.T) {
+	// Interpolating between red and blue in OKLCH should keep the chroma, unlike plain RGB.
+	red := color.NRGBA{0xff, 0, 0, 0xff}
+	blue := color.NRGBA{0, 0, 0xff, 0xff}
+	c0 := colorspace.OKLCHModel.Convert(red).(colorspace.OKLCH)
+	c1 := colorspace.OKLCHModel.Convert(blue).(colorspace.OKLCH)
+	mid := colorspace.OKLCHModel.Convert(colorspace.Lerp(red, blue, 0.5, colorspace.SpaceOKLCH)).(colorspace.OKLCH)
+	if min := math.Min(c0.C, c1.C); mid.C < min*0.9 {
+		t.Errorf("chroma: got: %f, want: >= %f", mid.C, min*0.9)
+	}
+	rgbMid := colorspace.OKLCHModel.Convert(colorspace.Lerp(red, blue, 0.5, colorspace.SpaceSRGB)).(colorspace.OKLCH)
+	if mid.C <= rgbMid.C {
+		t.Errorf("chroma: got: %f, want: > %f", mid.C, rgbMid.C)
+	}
+}
+
+func TestLerpTransparent(t *testing.T) {
+	red := color.NRGBA{0xff, 0, 0, 0xff}
+	got := colorspace.Lerp(red, color.Transparent, 0.5, colorspace.SpaceOKLab).(color.NRGBA64)
+	if got.R != 0xffff || got.G != 0 || got.B != 0 || got.A != 0x8000 {
+		t.Errorf("got: %v, want: {0xffff 0 0 0x8000}", got)
+	}
+}
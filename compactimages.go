@@ -0,0 +1,34 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"github.com/hajimehoshi/ebiten/v2/internal/atlas"
+)
+
+// CompactImages requests to compact the internal textures shared by multiple small images.
+//
+// After many images are created and disposed, the shared textures might become fragmented and use more GPU memory
+// than needed. CompactImages moves the images on sparse shared textures to the other shared textures, and releases
+// the emptied textures.
+//
+// The compaction is done incrementally in the following frames so that a frame is not paused for long.
+// As moving images costs, CompactImages is intended to be called at a moment when a hiccup is acceptable, e.g., a
+// loading screen. Use ReadImageUsageStats to know how efficiently the shared textures are used.
+//
+// CompactImages is concurrent-safe.
+func CompactImages() {
+	atlas.Compact()
+}
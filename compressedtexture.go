@@ -0,0 +1,136 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
+	"github.com/hajimehoshi/ebiten/v2/internal/mipmap"
+)
+
+// CompressedTextureFormat represents a block-compressed GPU texture format.
+type CompressedTextureFormat int
+
+const (
+	// CompressedTextureFormatBC1 represents BC1 (DXT1). This is available on desktops.
+	CompressedTextureFormatBC1 CompressedTextureFormat = CompressedTextureFormat(graphicsdriver.CompressedTextureFormatBC1)
+
+	// CompressedTextureFormatBC2 represents BC2 (DXT3). This is available on desktops.
+	CompressedTextureFormatBC2 CompressedTextureFormat = CompressedTextureFormat(graphicsdriver.CompressedTextureFormatBC2)
+
+	// CompressedTextureFormatBC3 represents BC3 (DXT5). This is available on desktops.
+	CompressedTextureFormatBC3 CompressedTextureFormat = CompressedTextureFormat(graphicsdriver.CompressedTextureFormatBC3)
+
+	// CompressedTextureFormatBC4 represents BC4 for one channel. This is available on desktops.
+	CompressedTextureFormatBC4 CompressedTextureFormat = CompressedTextureFormat(graphicsdriver.CompressedTextureFormatBC4)
+
+	// CompressedTextureFormatBC5 represents BC5 for two channels. This is available on desktops.
+	CompressedTextureFormatBC5 CompressedTextureFormat = CompressedTextureFormat(graphicsdriver.CompressedTextureFormatBC5)
+
+	// CompressedTextureFormatBC6H represents BC6H for unsigned HDR colors. This is available on desktops.
+	CompressedTextureFormatBC6H CompressedTextureFormat = CompressedTextureFormat(graphicsdriver.CompressedTextureFormatBC6H)
+
+	// CompressedTextureFormatBC7 represents BC7. This is available on desktops.
+	CompressedTextureFormatBC7 CompressedTextureFormat = CompressedTextureFormat(graphicsdriver.CompressedTextureFormatBC7)
+
+	// CompressedTextureFormatETC2RGB8 represents ETC2 for RGB colors. This is available on mobiles.
+	CompressedTextureFormatETC2RGB8 CompressedTextureFormat = CompressedTextureFormat(graphicsdriver.CompressedTextureFormatETC2RGB8)
+
+	// CompressedTextureFormatETC2RGBA8 represents ETC2 for RGBA colors. This is available on mobiles.
+	CompressedTextureFormatETC2RGBA8 CompressedTextureFormat = CompressedTextureFormat(graphicsdriver.CompressedTextureFormatETC2RGBA8)
+
+	// CompressedTextureFormatASTC4x4 represents ASTC with 4x4 blocks. This is available on mobiles.
+	CompressedTextureFormatASTC4x4 CompressedTextureFormat = CompressedTextureFormat(graphicsdriver.CompressedTextureFormatASTC4x4)
+)
+
+func (c CompressedTextureFormat) isValid() bool {
+	return 0 <= c && c <= CompressedTextureFormat(graphicsdriver.CompressedTextureFormatMax)
+}
+
+// String returns a string representing the compressed texture format.
+func (c CompressedTextureFormat) String() string {
+	if !c.isValid() {
+		return fmt.Sprintf("CompressedTextureFormat(%d)", c)
+	}
+	return graphicsdriver.CompressedTextureFormat(c).String()
+}
+
+// IsCompressedTextureFormatSupported reports whether the given compressed texture format is supported
+// by the current environment.
+//
+// Which formats are supported depends on the GPU and the graphics driver.
+// Typically, BC formats are available on desktops, and ETC2 and ASTC formats are available on mobiles.
+//
+// IsCompressedTextureFormatSupported must be called after the game starts, or IsCompressedTextureFormatSupported panics.
+//
+// IsCompressedTextureFormatSupported is concurrent-safe.
+func IsCompressedTextureFormatSupported(format CompressedTextureFormat) bool {
+	if !format.isValid() {
+		return false
+	}
+	return mipmap.IsCompressedTextureFormatSupported(graphicsdriver.CompressedTextureFormat(format))
+}
+
+// NewImageFromCompressedTexture creates a new image from the given block-compressed texture data.
+//
+// data is uploaded to GPU as it is without being decoded, so the GPU memory usage and the loading time are much smaller
+// than an image created from decoded pixels.
+// The colors in data must be pre-multiplied alpha values.
+// len(data) must equal to the size of the blocks covering width x height pixels.
+// Only the top level of mipmaps is used.
+//
+// The image created by NewImageFromCompressedTexture can be used only as a rendering source.
+// Drawing onto the image, ReplacePixels and Set panic.
+// At works but can be very slow since the pixels have to be rendered to another texture to read them.
+//
+// If the given format is not supported by the current environment, the game terminates with an error
+// when the texture is actually created. Use IsCompressedTextureFormatSupported to check the format in advance.
+//
+// If width or height is less than 1 or more than device-dependent maximum size, or len(data) is not appropriate,
+// NewImageFromCompressedTexture panics.
+//
+// NewImageFromCompressedTexture panics if RunGame already finishes.
+func NewImageFromCompressedTexture(data []byte, width, height int, format CompressedTextureFormat) *Image {
+	if isRunGameEnded() {
+		panic(fmt.Sprintf("ebiten: NewImageFromCompressedTexture cannot be called after RunGame finishes"))
+	}
+	if width <= 0 {
+		panic(fmt.Sprintf("ebiten: width at NewImageFromCompressedTexture must be positive but %d", width))
+	}
+	if height <= 0 {
+		panic(fmt.Sprintf("ebiten: height at NewImageFromCompressedTexture must be positive but %d", height))
+	}
+	if !format.isValid() {
+		panic(fmt.Sprintf("ebiten: invalid compressed texture format: %d", format))
+	}
+	f := graphicsdriver.CompressedTextureFormat(format)
+	if l := f.ByteSize(width, height); len(data) != l {
+		panic(fmt.Sprintf("ebiten: len(data) must be %d but %d at NewImageFromCompressedTexture", l, len(data)))
+	}
+
+	// Copy the data as the data might be used after this function returns.
+	d := make([]byte, len(data))
+	copy(d, data)
+
+	i := &Image{
+		mipmap:     mipmap.NewFromCompressedTexture(width, height, f, d),
+		bounds:     image.Rect(0, 0, width, height),
+		compressed: true,
+	}
+	i.addr = i
+	return i
+}
@@ -59,12 +59,42 @@
 // `EBITEN_SCREENSHOT_KEY` environment variable specifies the key
 // to take a screenshot. For example, if you run your game with
 // `EBITEN_SCREENSHOT_KEY=q`, you can take a game screen's screenshot
-// by pressing Q key. This works only on desktops.
+// by pressing Q key. On browsers, the screenshot is downloaded as a file.
+// The key and the file path can also be specified by SetScreenshotKey and SetScreenshotFilenameFunc.
 //
 // `EBITEN_INTERNAL_IMAGES_KEY` environment variable specifies the key
 // to dump all the internal images. This is valid only when the build tag
 // 'ebitendebug' is specified. This works only on desktops.
 //
+// `EBITEN_PPROF_LABELS` environment variable enables pprof labels for the engine internals when the value is 1.
+// The goroutines running Ebiten's Update, Draw, graphics command flushes, waiting for presenting the screen,
+// and reading audio sources have the pprof label "ebiten" with the names like "ebiten.update" during the work.
+// Note that the labels set by the game to these goroutines are removed.
+//
+// Regardless of the environment variable, Ebiten emits runtime/trace tasks for the frames and regions with the same
+// names, so `go tool trace` shows the time spent inside Ebiten.
+//
+// Web Workers
+//
+// On browsers, a game can run in a Web Worker so that heavy Update logic doesn't block the page.
+// In a Web Worker, the game renders to an OffscreenCanvas and the main thread must proxy the page state and input
+// events to the worker by postMessage. Ebiten waits for the canvas message before the game starts.
+//
+//     const offscreen = canvas.transferControlToOffscreen();
+//     worker.postMessage({ebiten: 'canvas', canvas: offscreen, width: w, height: h, devicePixelRatio: r}, [offscreen]);
+//     worker.postMessage({ebiten: 'resize', width: w, height: h, devicePixelRatio: r});
+//     worker.postMessage({ebiten: 'focus', focused: document.hasFocus(), hidden: document.hidden});
+//     worker.postMessage({ebiten: 'event', event: e});
+//
+// width and height are in CSS pixels. e in an event message is a plain object copying the properties of a keyboard,
+// mouse, wheel or touch event, e.g., type, timeStamp, code, keyCode, charCode, repeat, button, clientX, clientY,
+// movementX, movementY, deltaX, deltaY, and targetTouches and changedTouches as arrays of objects with identifier,
+// clientX, clientY and optionally force, radiusX and radiusY.
+// The main thread is responsible for calling preventDefault on the original events. Fullscreen, the cursor mode and
+// the cursor shape are not available in a Web Worker.
+// Audio is not available in a Web Worker either, as an AudioContext exists only on the main thread. Using the audio
+// package in a Web Worker terminates the game with an error.
+//
 // Build tags
 //
 // `ebitendebug` outputs a log of graphics commands. This is useful to know what happens in Ebiten. In general, the
@@ -78,6 +108,8 @@
 // to manage threads yourself. Functions like IsKeyPressed will no longer be concurrent-safe with this build tag.
 // They must be called from the main thread or the same goroutine as the given game's callback functions like Update
 // to RunGame.
+// `ebitensinglethread` is also useful with StartGameWithoutMainLoop when the caller's main loop and Ebiten should
+// share one thread.
 //
 // `ebitenexternaldll` stops embedding DLL file in a Windows executable.
 // `ebitenexternaldll` works only for Windows.
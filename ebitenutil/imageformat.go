@@ -0,0 +1,113 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil
+
+import (
+	"bufio"
+	"image"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// ImageDecoder decodes an image from r.
+//
+// img can be nil when the format doesn't have a CPU-side representation, e.g., GPU-compressed textures.
+type ImageDecoder func(r io.Reader) (eimg *ebiten.Image, img image.Image, err error)
+
+type imageFormat struct {
+	name   string
+	magic  string
+	decode ImageDecoder
+}
+
+var (
+	imageFormatsM      sync.Mutex
+	atomicImageFormats atomic.Value
+)
+
+// RegisterImageFormat registers an image format used by NewImageFromReader, NewImageFromFile and
+// NewImageFromURL.
+//
+// name is the name of the format, like "qoi" or "webp".
+// magic is the magic prefix that identifies the format's encoding. The magic string can contain '?' wildcards
+// that each match any one byte, in the same way as image.RegisterFormat.
+//
+// The formats registered by RegisterImageFormat take precedence over the formats registered to the image package,
+// in the order of the registration. A decoder can create an *ebiten.Image directly, e.g., to upload GPU-compressed
+// data as it is. For a format that only needs an image.Image, image.RegisterFormat works as well.
+//
+// RegisterImageFormat is typically called in an init function of the decoder's package.
+// For example, KTX2 files can be loaded with exp/ktx2 by:
+//
+//	ebitenutil.RegisterImageFormat("ktx2", "\xabKTX 20\xbb\r\n\x1a\n", func(r io.Reader) (*ebiten.Image, image.Image, error) {
+//	    img, err := ktx2.NewImage(r)
+//	    return img, nil, err
+//	})
+//
+// RegisterImageFormat is concurrent-safe.
+func RegisterImageFormat(name, magic string, decode ImageDecoder) {
+	imageFormatsM.Lock()
+	defer imageFormatsM.Unlock()
+
+	formats, _ := atomicImageFormats.Load().([]imageFormat)
+	atomicImageFormats.Store(append(formats[:len(formats):len(formats)], imageFormat{
+		name:   name,
+		magic:  magic,
+		decode: decode,
+	}))
+}
+
+func matchMagic(magic string, b []byte) bool {
+	if len(magic) != len(b) {
+		return false
+	}
+	for i, c := range b {
+		if magic[i] != c && magic[i] != '?' {
+			return false
+		}
+	}
+	return true
+}
+
+// NewImageFromReader loads from the io.Reader and returns ebiten.Image and image.Image.
+//
+// The formats registered by RegisterImageFormat are tried first. Otherwise, image.Decode is used.
+// image.Image can be nil for a format registered by RegisterImageFormat.
+//
+// Image decoders must be imported when using NewImageFromReader. For example,
+// if you want to load a PNG image, you'd need to add `_ "image/png"` to the import section.
+func NewImageFromReader(reader io.Reader) (*ebiten.Image, image.Image, error) {
+	if formats, _ := atomicImageFormats.Load().([]imageFormat); len(formats) > 0 {
+		br := bufio.NewReader(reader)
+		for _, f := range formats {
+			b, err := br.Peek(len(f.magic))
+			if err != nil || !matchMagic(f.magic, b) {
+				continue
+			}
+			return f.decode(br)
+		}
+		reader = br
+	}
+
+	img, _, err := image.Decode(reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	img2 := ebiten.NewImageFromImage(img)
+	return img2, img, err
+}
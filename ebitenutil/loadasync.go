@@ -0,0 +1,189 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// LoadOptions represents options for LoadAsync and LoadImageAsync.
+type LoadOptions struct {
+	// OnProgress is called whenever a chunk of the data is received.
+	// total is the size from the Content-Length header, or -1 if the size is unknown.
+	//
+	// OnProgress is called from the loading goroutine, not from the game's goroutine.
+	OnProgress func(loaded, total int64)
+}
+
+// Loading represents a loading from a URL started by LoadAsync or LoadImageAsync.
+//
+// All the methods of Loading are concurrent-safe, so a game can poll the state in Update.
+type Loading struct {
+	loaded int64
+	total  int64
+	done   bool
+	data   []byte
+	image  *ebiten.Image
+	err    error
+
+	m sync.Mutex
+}
+
+// LoadAsync starts loading the data at url in a new goroutine.
+//
+// On browsers, the data is fetched with the Fetch API. The loading is canceled when ctx is canceled.
+//
+// For audio, decode the loaded bytes with a decoder like mp3.DecodeWithSampleRate and bytes.NewReader.
+func LoadAsync(ctx context.Context, url string, options *LoadOptions) *Loading {
+	l := &Loading{
+		total: -1,
+	}
+	go func() {
+		data, err := l.load(ctx, url, options)
+		l.finish(data, nil, err)
+	}()
+	return l
+}
+
+// LoadImageAsync starts loading and decoding the image at url in a new goroutine.
+//
+// The image is decoded by NewImageFromReader, and the image is created on the loading goroutine,
+// so neither decoding nor uploading blocks the game's Update.
+//
+// LoadImageAsync must be called after the game starts.
+func LoadImageAsync(ctx context.Context, url string, options *LoadOptions) *Loading {
+	l := &Loading{
+		total: -1,
+	}
+	go func() {
+		data, err := l.load(ctx, url, options)
+		if err != nil {
+			l.finish(nil, nil, err)
+			return
+		}
+		img, _, err := NewImageFromReader(bytes.NewReader(data))
+		if err != nil {
+			l.finish(nil, nil, fmt.Errorf("ebitenutil: decoding %s failed: %w", url, err))
+			return
+		}
+		l.finish(data, img, nil)
+	}()
+	return l
+}
+
+func (l *Loading) load(ctx context.Context, url string, options *LoadOptions) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return nil, fmt.Errorf("ebitenutil: loading %s failed: %s", url, res.Status)
+	}
+
+	total := res.ContentLength
+	l.m.Lock()
+	l.total = total
+	l.m.Unlock()
+
+	var buf bytes.Buffer
+	if total > 0 {
+		buf.Grow(int(total))
+	}
+	chunk := make([]byte, 64*1024)
+	for {
+		n, err := res.Body.Read(chunk)
+		if n > 0 {
+			buf.Write(chunk[:n])
+			loaded := int64(buf.Len())
+			l.m.Lock()
+			l.loaded = loaded
+			l.m.Unlock()
+			if options != nil && options.OnProgress != nil {
+				options.OnProgress(loaded, total)
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+func (l *Loading) finish(data []byte, image *ebiten.Image, err error) {
+	l.m.Lock()
+	defer l.m.Unlock()
+	l.done = true
+	l.data = data
+	l.image = image
+	l.err = err
+}
+
+// Progress returns the number of the loaded bytes and the total size.
+// total is -1 if the size is unknown.
+func (l *Loading) Progress() (loaded, total int64) {
+	l.m.Lock()
+	defer l.m.Unlock()
+	return l.loaded, l.total
+}
+
+// IsDone reports whether the loading is finished, successfully or not.
+func (l *Loading) IsDone() bool {
+	l.m.Lock()
+	defer l.m.Unlock()
+	return l.done
+}
+
+// Bytes returns the loaded data.
+//
+// Bytes returns nil if the loading is not finished or failed.
+func (l *Loading) Bytes() []byte {
+	l.m.Lock()
+	defer l.m.Unlock()
+	return l.data
+}
+
+// Image returns the loaded image of LoadImageAsync.
+//
+// Image returns nil if the loading is not finished or failed, or if the loading is started by LoadAsync.
+func (l *Loading) Image() *ebiten.Image {
+	l.m.Lock()
+	defer l.m.Unlock()
+	return l.image
+}
+
+// Err returns the error of the loading, e.g., when the context is canceled.
+//
+// Err returns nil if the loading is not finished or succeeded.
+func (l *Loading) Err() error {
+	l.m.Lock()
+	defer l.m.Unlock()
+	return l.err
+}
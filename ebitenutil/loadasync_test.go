@@ -0,0 +1,192 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil_test
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+func waitLoading(t *testing.T, l *ebitenutil.Loading) {
+	t.Helper()
+	timeout := time.After(10 * time.Second)
+	for !l.IsDone() {
+		select {
+		case <-timeout:
+			t.Fatal("time out")
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
+
+func TestLoadAsync(t *testing.T) {
+	data := bytes.Repeat([]byte("ebiten"), 100000)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		w.Write(data)
+	}))
+	defer s.Close()
+
+	var m sync.Mutex
+	var lastLoaded, lastTotal int64
+	var calls int
+	l := ebitenutil.LoadAsync(context.Background(), s.URL, &ebitenutil.LoadOptions{
+		OnProgress: func(loaded, total int64) {
+			m.Lock()
+			defer m.Unlock()
+			if loaded < lastLoaded {
+				t.Errorf("loaded decreased: %d -> %d", lastLoaded, loaded)
+			}
+			lastLoaded, lastTotal = loaded, total
+			calls++
+		},
+	})
+	waitLoading(t, l)
+
+	if err := l.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(l.Bytes(), data) {
+		t.Errorf("Bytes(): got: %d bytes, want: %d bytes", len(l.Bytes()), len(data))
+	}
+	if l.Image() != nil {
+		t.Errorf("Image(): got: non-nil, want: nil")
+	}
+	if loaded, total := l.Progress(); loaded != int64(len(data)) || total != int64(len(data)) {
+		t.Errorf("Progress(): got: (%d, %d), want: (%d, %d)", loaded, total, len(data), len(data))
+	}
+
+	m.Lock()
+	defer m.Unlock()
+	if calls == 0 {
+		t.Errorf("OnProgress must be called")
+	}
+	if lastLoaded != int64(len(data)) || lastTotal != int64(len(data)) {
+		t.Errorf("OnProgress: got: (%d, %d), want: (%d, %d)", lastLoaded, lastTotal, len(data), len(data))
+	}
+}
+
+func TestLoadAsyncUnknownSize(t *testing.T) {
+	data := []byte("ebiten")
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Flushing before writing the whole body makes the response chunked without Content-Length.
+		w.(http.Flusher).Flush()
+		w.Write(data)
+	}))
+	defer s.Close()
+
+	l := ebitenutil.LoadAsync(context.Background(), s.URL, nil)
+	waitLoading(t, l)
+
+	if err := l.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(l.Bytes(), data) {
+		t.Errorf("Bytes(): got: %q, want: %q", l.Bytes(), data)
+	}
+	if loaded, total := l.Progress(); loaded != int64(len(data)) || total != -1 {
+		t.Errorf("Progress(): got: (%d, %d), want: (%d, -1)", loaded, total, len(data))
+	}
+}
+
+func TestLoadAsyncNotFound(t *testing.T) {
+	s := httptest.NewServer(http.NotFoundHandler())
+	defer s.Close()
+
+	l := ebitenutil.LoadAsync(context.Background(), s.URL, nil)
+	waitLoading(t, l)
+
+	if l.Err() == nil {
+		t.Errorf("Err(): got: nil, want: non-nil")
+	}
+	if l.Bytes() != nil {
+		t.Errorf("Bytes(): got: non-nil, want: nil")
+	}
+}
+
+func TestLoadAsyncCancel(t *testing.T) {
+	unblock := make(chan struct{})
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ebiten"))
+		w.(http.Flusher).Flush()
+		select {
+		case <-r.Context().Done():
+		case <-unblock:
+		}
+	}))
+	defer s.Close()
+	defer close(unblock)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	l := ebitenutil.LoadAsync(ctx, s.URL, nil)
+	cancel()
+	waitLoading(t, l)
+
+	if l.Err() == nil {
+		t.Errorf("Err(): got: nil, want: non-nil")
+	}
+}
+
+func TestLoadImageAsync(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 16, 8))); err != nil {
+		t.Fatal(err)
+	}
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(buf.Bytes())
+	}))
+	defer s.Close()
+
+	l := ebitenutil.LoadImageAsync(context.Background(), s.URL, nil)
+	waitLoading(t, l)
+
+	if err := l.Err(); err != nil {
+		t.Fatal(err)
+	}
+	img := l.Image()
+	if img == nil {
+		t.Fatal("Image(): got: nil, want: non-nil")
+	}
+	if w, h := img.Size(); w != 16 || h != 8 {
+		t.Errorf("Image().Size(): got: (%d, %d), want: (16, 8)", w, h)
+	}
+}
+
+func TestLoadImageAsyncInvalid(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("not an image"))
+	}))
+	defer s.Close()
+
+	l := ebitenutil.LoadImageAsync(context.Background(), s.URL, nil)
+	waitLoading(t, l)
+
+	if l.Err() == nil {
+		t.Errorf("Err(): got: nil, want: non-nil")
+	}
+	if l.Image() != nil {
+		t.Errorf("Image(): got: non-nil, want: nil")
+	}
+}
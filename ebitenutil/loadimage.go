@@ -19,7 +19,6 @@ package ebitenutil
 
 import (
 	"image"
-	"io"
 
 	"github.com/hajimehoshi/ebiten/v2"
 )
@@ -28,6 +27,7 @@ import (
 //
 // Image decoders must be imported when using NewImageFromFile. For example,
 // if you want to load a PNG image, you'd need to add `_ "image/png"` to the import section.
+// The formats registered by RegisterImageFormat are also available.
 //
 // How to solve path depends on your environment. This varies on your desktop or web browser.
 // Note that this doesn't work on mobiles.
@@ -43,16 +43,3 @@ func NewImageFromFile(path string) (*ebiten.Image, image.Image, error) {
 	}()
 	return NewImageFromReader(file)
 }
-
-// NewImageFromReader loads from the io.Reader and returns ebiten.Image and image.Image.
-//
-// Image decoders must be imported when using NewImageFromReader. For example,
-// if you want to load a PNG image, you'd need to add `_ "image/png"` to the import section.
-func NewImageFromReader(reader io.Reader) (*ebiten.Image, image.Image, error) {
-	img, _, err := image.Decode(reader)
-	if err != nil {
-		return nil, nil, err
-	}
-	img2 := ebiten.NewImageFromImage(img)
-	return img2, img, err
-}
@@ -0,0 +1,44 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil
+
+import (
+	"image"
+	"io"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/qoi"
+)
+
+func init() {
+	image.RegisterFormat("qoi", "qoif", qoi.Decode, qoi.DecodeConfig)
+}
+
+// DecodeQOI reads an image in the QOI ("Quite OK Image") format from r.
+//
+// QOI decodes much faster than PNG with a comparable file size, so it suits assets that dominate loading time.
+//
+// Importing ebitenutil registers QOI to the image package, so QOI files can also be loaded by image.Decode,
+// NewImageFromFile and so on.
+func DecodeQOI(r io.Reader) (image.Image, error) {
+	return qoi.Decode(r)
+}
+
+// EncodeQOI writes img to w in the QOI ("Quite OK Image") format.
+//
+// QOI encodes much faster than PNG, e.g., for saving screenshots at runtime.
+// img is converted to non-premultiplied alpha colors as QOI requires.
+func EncodeQOI(w io.Writer, img image.Image) error {
+	return qoi.Encode(w, img)
+}
@@ -0,0 +1,91 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil
+
+import (
+	"image"
+	"image/color"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+var (
+	timingGraphUpdateColor      = color.RGBA{0x40, 0x80, 0xff, 0xff}
+	timingGraphDrawColor        = color.RGBA{0x40, 0xc0, 0x40, 0xff}
+	timingGraphGPUColor         = color.RGBA{0xff, 0xa0, 0x20, 0xff}
+	timingGraphPresentWaitColor = color.RGBA{0x60, 0x60, 0x60, 0xff}
+	timingGraphBackgroundColor  = color.RGBA{0x00, 0x00, 0x00, 0x80}
+	timingGraphBudgetColor      = color.RGBA{0xff, 0x40, 0x40, 0xff}
+)
+
+// DrawTimingGraph draws a graph of the frame timings on dst's bounds.
+//
+// Each frame in history is drawn as a bar from the left (oldest) to the right (newest), stacking
+// the update (blue), draw (green), GPU (orange) and present wait (gray) times from the bottom.
+// The full height of the graph represents 2 frames at the current TPS, and the red line represents 1 frame.
+// A spike over the red line indicates a hitch, such as a GC pause or blocking for vsync.
+//
+// history is typically obtained by ebiten.AppendFrameTimings. To draw the graph at a specific position,
+// use a sub-image of the screen as dst.
+//
+// This function might not be performant.
+func DrawTimingGraph(dst *ebiten.Image, history []ebiten.FrameTiming) {
+	b := dst.Bounds()
+	if b.Empty() {
+		return
+	}
+	// Blend the translucent background with dst. FillRect would replace the pixels.
+	DrawRect(dst, float64(b.Min.X), float64(b.Min.Y), float64(b.Dx()), float64(b.Dy()), timingGraphBackgroundColor)
+
+	tps := ebiten.MaxTPS()
+	if tps == ebiten.SyncWithFPS || tps <= 0 {
+		tps = 60
+	}
+	budget := time.Second / time.Duration(tps)
+	scale := float64(b.Dy()) / float64(2*budget)
+
+	if len(history) > 0 {
+		// If there are more frames than the width, only the newest frames are drawn.
+		if len(history) > b.Dx() {
+			history = history[len(history)-b.Dx():]
+		}
+		barWidth := b.Dx() / len(history)
+		x := b.Max.X - barWidth*len(history)
+		for _, t := range history {
+			y := b.Max.Y
+			for _, p := range []struct {
+				d   time.Duration
+				clr color.Color
+			}{
+				{t.Update, timingGraphUpdateColor},
+				{t.Draw, timingGraphDrawColor},
+				{t.GPU, timingGraphGPUColor},
+				{t.PresentWait, timingGraphPresentWaitColor},
+			} {
+				h := int(float64(p.d) * scale)
+				if h <= 0 {
+					continue
+				}
+				dst.FillRect(image.Rect(x, y-h, x+barWidth, y), p.clr)
+				y -= h
+			}
+			x += barWidth
+		}
+	}
+
+	y := b.Max.Y - int(float64(budget)*scale)
+	dst.FillRect(image.Rect(b.Min.X, y, b.Max.X, y+1), timingGraphBudgetColor)
+}
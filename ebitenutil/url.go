@@ -15,7 +15,6 @@
 package ebitenutil
 
 import (
-	"image"
 	"net/http"
 
 	"github.com/hajimehoshi/ebiten/v2"
@@ -25,6 +24,7 @@ import (
 //
 // Image decoders must be imported when using NewImageFromURL. For example,
 // if you want to load a PNG image, you'd need to add `_ "image/png"` to the import section.
+// The formats registered by RegisterImageFormat are also available.
 func NewImageFromURL(url string) (*ebiten.Image, error) {
 	res, err := http.Get(url)
 	if err != nil {
@@ -32,11 +32,9 @@ func NewImageFromURL(url string) (*ebiten.Image, error) {
 	}
 	defer res.Body.Close()
 
-	img, _, err := image.Decode(res.Body)
+	eimg, _, err := NewImageFromReader(res.Body)
 	if err != nil {
 		return nil, err
 	}
-
-	eimg := ebiten.NewImageFromImage(img)
 	return eimg, nil
 }
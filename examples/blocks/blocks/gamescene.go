@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/colorm"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/examples/resources/images"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
@@ -152,18 +153,7 @@ var (
 )
 
 func init() {
-	id := ebiten.ColorM{}
-
-	mono := ebiten.ColorM{}
-	mono.ChangeHSV(0, 0, 1)
-
-	for j := 0; j < ebiten.ColorMDim-1; j++ {
-		for i := 0; i < ebiten.ColorMDim-1; i++ {
-			lightGray.SetElement(i, j, mono.Element(i, j)*0.7+id.Element(i, j)*0.3)
-		}
-	}
-
-	lightGray.Translate(0.3, 0.3, 0.3, 0)
+	lightGray = colorm.Identity().Lerp(colorm.Saturate(0), 0.7).Concat(colorm.Brightness(0.3)).ToColorM()
 }
 
 func (s *GameScene) drawBackground(r *ebiten.Image) {
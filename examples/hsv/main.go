@@ -26,6 +26,7 @@ import (
 	"math"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/colorm"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/examples/resources/images"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
@@ -112,15 +113,14 @@ func (g *Game) Draw(screen *ebiten.Image) {
 	hue := float64(g.hue128) * 2 * math.Pi / 128
 	saturation := float64(g.saturation128) / 128
 	value := float64(g.value128) / 128
-	op.ColorM.ChangeHSV(hue, saturation, value)
+	c := colorm.ChangeHSV(hue, saturation, value)
 
 	// Invert the color.
 	if g.inverted {
-		op.ColorM.Scale(-1, -1, -1, 1)
-		op.ColorM.Translate(1, 1, 1, 0)
+		c = c.Concat(colorm.Scale(-1, -1, -1, 1)).Concat(colorm.Translate(1, 1, 1, 0))
 	}
 
-	screen.DrawImage(gophersImage, op)
+	colorm.DrawImage(screen, gophersImage, c, op)
 
 	// Draw the text of the current status.
 	msgInverted := "false"
@@ -25,6 +25,7 @@ import (
 	"math"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/colorm"
 	"github.com/hajimehoshi/ebiten/v2/examples/resources/images"
 )
 
@@ -55,9 +56,9 @@ func (g *Game) Draw(screen *ebiten.Image) {
 	op.GeoM.Translate(float64(screenWidth)/2, float64(screenHeight)/2)
 
 	// Rotate the hue.
-	op.ColorM.RotateHue(float64(g.count%360) * 2 * math.Pi / 360)
+	c := colorm.HueRotate(float64(g.count%360) * 2 * math.Pi / 360)
 
-	screen.DrawImage(gophersImage, op)
+	colorm.DrawImage(screen, gophersImage, c, op)
 }
 
 func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
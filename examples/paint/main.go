@@ -25,6 +25,7 @@ import (
 	"math"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/colorm"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 )
 
@@ -99,11 +100,10 @@ func (g *Game) paint(canvas *ebiten.Image, x, y int) {
 	op := &ebiten.DrawImageOptions{}
 	op.GeoM.Translate(float64(x), float64(y))
 	// Scale the color and rotate the hue so that colors vary on each frame.
-	op.ColorM.Scale(1.0, 0.50, 0.125, 1.0)
 	tps := ebiten.MaxTPS()
 	theta := 2.0 * math.Pi * float64(g.count%tps) / float64(tps)
-	op.ColorM.RotateHue(theta)
-	canvas.DrawImage(brushImage, op)
+	c := colorm.Scale(1.0, 0.50, 0.125, 1.0).Concat(colorm.HueRotate(theta))
+	colorm.DrawImage(canvas, brushImage, c, op)
 }
 
 func (g *Game) Draw(screen *ebiten.Image) {
@@ -0,0 +1,258 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package anim offers animated images decoded from animated GIF and APNG files.
+//
+// The frames of an animation are packed into as few images as possible, so drawing an animation rarely switches textures.
+//
+// This package is experimental and the API might be changed in the future.
+package anim
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"io"
+	"io/ioutil"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// defaultDelay is used for a frame whose delay is not positive.
+const defaultDelay = 100 * time.Millisecond
+
+// maxSheetSize is the maximum width and height of an image packing frames.
+// An image of this size can be created with any graphics library.
+const maxSheetSize = 4096
+
+// checkFrameSize returns an error if a frame of the given size doesn't fit in an image packing frames.
+func checkFrameSize(width, height int) error {
+	if width < 0 || height < 0 {
+		return fmt.Errorf("anim: invalid frame size: %d, %d", width, height)
+	}
+	if width > maxSheetSize || height > maxSheetSize {
+		return fmt.Errorf("anim: the frame size %dx%d must be at most %dx%d", width, height, maxSheetSize, maxSheetSize)
+	}
+	return nil
+}
+
+// layoutFrames lays out count frames of the given size in sheets whose width and height are at most maxSize.
+//
+// layoutFrames returns the size of each sheet, and the sheet index and the region in the sheet of each frame.
+func layoutFrames(width, height, count, maxSize int) (sheets []image.Point, indices []int, rects []image.Rectangle) {
+	maxCols := maxSize / width
+	maxRows := maxSize / height
+	perSheet := maxCols * maxRows
+
+	indices = make([]int, 0, count)
+	rects = make([]image.Rectangle, 0, count)
+	for start := 0; start < count; start += perSheet {
+		n := count - start
+		if n > perSheet {
+			n = perSheet
+		}
+
+		// Lay out the frames in a grid as square as possible.
+		cols := int(math.Ceil(math.Sqrt(float64(n) * float64(height) / float64(width))))
+		if min := (n + maxRows - 1) / maxRows; cols < min {
+			cols = min
+		}
+		if cols > maxCols {
+			cols = maxCols
+		}
+		if cols > n {
+			cols = n
+		}
+		rows := (n + cols - 1) / cols
+
+		for i := 0; i < n; i++ {
+			x := (i % cols) * width
+			y := (i / cols) * height
+			indices = append(indices, len(sheets))
+			rects = append(rects, image.Rect(x, y, x+width, y+height))
+		}
+		sheets = append(sheets, image.Pt(cols*width, rows*height))
+	}
+	return sheets, indices, rects
+}
+
+// Anim represents an animated image.
+type Anim struct {
+	sheets []*ebiten.Image
+	frames []*ebiten.Image
+	delays []time.Duration
+
+	// ends is the end time of each frame from the start of a loop.
+	ends []time.Duration
+
+	width     int
+	height    int
+	loopCount int
+}
+
+// New creates an animation from frames.
+//
+// All the frames must have the same size. delays is the display duration of each frame,
+// and must have the same length as frames. A delay 0 or less is treated as 100 milliseconds.
+//
+// loopCount is the number of times the animation is played. 0 means that the animation loops forever.
+//
+// New panics if frames is empty, the arguments are inconsistent, or the frame size is larger than 4096x4096.
+func New(frames []image.Image, delays []time.Duration, loopCount int) *Anim {
+	if len(frames) == 0 {
+		panic("anim: frames must not be empty")
+	}
+	if len(frames) != len(delays) {
+		panic(fmt.Sprintf("anim: len(frames) (%d) and len(delays) (%d) must be the same", len(frames), len(delays)))
+	}
+	if loopCount < 0 {
+		panic(fmt.Sprintf("anim: loopCount must be 0 or positive but %d", loopCount))
+	}
+	size := frames[0].Bounds().Size()
+	for _, f := range frames {
+		if f.Bounds().Size() != size {
+			panic("anim: all the frames must have the same size")
+		}
+	}
+	if size.X == 0 || size.Y == 0 {
+		panic("anim: the frame size must not be empty")
+	}
+	if err := checkFrameSize(size.X, size.Y); err != nil {
+		panic(err.Error())
+	}
+
+	sheetSizes, indices, rects := layoutFrames(size.X, size.Y, len(frames), maxSheetSize)
+	sheets := make([]*ebiten.Image, len(sheetSizes))
+	for i, s := range sheetSizes {
+		pix := image.NewRGBA(image.Rect(0, 0, s.X, s.Y))
+		for j, f := range frames {
+			if indices[j] != i {
+				continue
+			}
+			draw.Draw(pix, rects[j], f, f.Bounds().Min, draw.Src)
+		}
+		sheets[i] = ebiten.NewImageFromImage(pix)
+	}
+
+	a := &Anim{
+		sheets:    sheets,
+		frames:    make([]*ebiten.Image, len(frames)),
+		delays:    make([]time.Duration, len(frames)),
+		ends:      make([]time.Duration, len(frames)),
+		width:     size.X,
+		height:    size.Y,
+		loopCount: loopCount,
+	}
+	var t time.Duration
+	for i, d := range delays {
+		if d <= 0 {
+			d = defaultDelay
+		}
+		t += d
+		a.frames[i] = sheets[indices[i]].SubImage(rects[i]).(*ebiten.Image)
+		a.delays[i] = d
+		a.ends[i] = t
+	}
+	return a
+}
+
+// Decode reads an animated GIF or APNG file from r and creates an animation.
+//
+// A non-animated GIF or PNG file is decoded as an animation with one frame.
+//
+// Decode returns an error if the frame size is larger than 4096x4096.
+func Decode(r io.Reader) (*Anim, error) {
+	bs, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case bytes.HasPrefix(bs, []byte("GIF8")):
+		return DecodeGIF(bytes.NewReader(bs))
+	case bytes.HasPrefix(bs, pngSignature):
+		return DecodeAPNG(bytes.NewReader(bs))
+	default:
+		return nil, fmt.Errorf("anim: unknown format")
+	}
+}
+
+// Size returns the size of the animation's frames.
+func (a *Anim) Size() (width, height int) {
+	return a.width, a.height
+}
+
+// FrameCount returns the number of the frames.
+func (a *Anim) FrameCount() int {
+	return len(a.frames)
+}
+
+// Frame returns the i-th frame image.
+//
+// The returned image is a sub-image of an image packing the frames.
+// The returned image must not be modified.
+func (a *Anim) Frame(i int) *ebiten.Image {
+	return a.frames[i]
+}
+
+// Delay returns the display duration of the i-th frame.
+func (a *Anim) Delay(i int) time.Duration {
+	return a.delays[i]
+}
+
+// Duration returns the duration of one loop of the animation.
+func (a *Anim) Duration() time.Duration {
+	return a.ends[len(a.ends)-1]
+}
+
+// LoopCount returns the number of times the animation is played. 0 means that the animation loops forever.
+func (a *Anim) LoopCount() int {
+	return a.loopCount
+}
+
+// FrameIndexAt returns the index of the frame shown at the time t from the start of the animation.
+//
+// If t is negative, FrameIndexAt returns 0. After all the loops end, FrameIndexAt returns the last frame's index.
+func (a *Anim) FrameIndexAt(t time.Duration) int {
+	if t < 0 {
+		return 0
+	}
+	d := a.Duration()
+	if a.loopCount > 0 && t/d >= time.Duration(a.loopCount) {
+		return len(a.frames) - 1
+	}
+	t %= d
+	return sort.Search(len(a.ends), func(i int) bool {
+		return t < a.ends[i]
+	})
+}
+
+// Draw draws the frame shown at the time t from the start of the animation onto dst.
+//
+// options is used as the options of DrawImage. options can be nil.
+func (a *Anim) Draw(dst *ebiten.Image, t time.Duration, options *ebiten.DrawImageOptions) {
+	dst.DrawImage(a.frames[a.FrameIndexAt(t)], options)
+}
+
+// Dispose disposes the images packing the frames.
+//
+// The animation and its frame images must not be used after Dispose is called.
+func (a *Anim) Dispose() {
+	for _, s := range a.sheets {
+		s.Dispose()
+	}
+}
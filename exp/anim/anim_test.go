@@ -0,0 +1,435 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anim
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"testing"
+	"time"
+)
+
+func sameColors(c0, c1 color.Color, delta int) bool {
+	r0, g0, b0, a0 := c0.RGBA()
+	r1, g1, b1, a1 := c1.RGBA()
+	abs := func(x int) int {
+		if x < 0 {
+			return -x
+		}
+		return x
+	}
+	d := delta * 0x101
+	return abs(int(r0)-int(r1)) <= d && abs(int(g0)-int(g1)) <= d && abs(int(b0)-int(b1)) <= d && abs(int(a0)-int(a1)) <= d
+}
+
+func checkFrames(t *testing.T, got []image.Image, want [][]color.Color) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("frame count: got: %d, want: %d", len(got), len(want))
+	}
+	for i, img := range got {
+		for x, w := range want[i] {
+			b := img.Bounds()
+			if c := img.At(b.Min.X+x, b.Min.Y); !sameColors(c, w, 1) {
+				t.Errorf("frame %d: (%d, 0): got: %v, want: %v", i, x, c, w)
+			}
+		}
+	}
+}
+
+type testAPNGFrame struct {
+	img       image.Image
+	x, y      int
+	delayNum  uint16
+	delayDen  uint16
+	disposeOp byte
+	blendOp   byte
+}
+
+// pngChunks returns the IHDR data and the concatenated IDAT data of img encoded as PNG.
+func pngChunks(t *testing.T, img image.Image) (ihdr, idat []byte) {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	bs := buf.Bytes()[len(pngSignature):]
+	for len(bs) > 0 {
+		n := binary.BigEndian.Uint32(bs)
+		typ := string(bs[4:8])
+		data := bs[8 : 8+n]
+		switch typ {
+		case "IHDR":
+			ihdr = data
+		case "IDAT":
+			idat = append(idat, data...)
+		}
+		bs = bs[12+n:]
+	}
+	return ihdr, idat
+}
+
+// encodeAPNG creates an APNG file. The first frame is also the default image.
+//
+// All the frames must have the same PNG color type, e.g., all the frames must include non-opaque pixels.
+func encodeAPNG(t *testing.T, width, height int, loopCount int, frames []testAPNGFrame) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	buf.Write(pngSignature)
+	be := binary.BigEndian
+
+	ihdr, _ := pngChunks(t, frames[0].img)
+	h := make([]byte, len(ihdr))
+	copy(h, ihdr)
+	be.PutUint32(h[0:], uint32(width))
+	be.PutUint32(h[4:], uint32(height))
+	writePNGChunk(&buf, "IHDR", h)
+
+	actl := make([]byte, 8)
+	be.PutUint32(actl[0:], uint32(len(frames)))
+	be.PutUint32(actl[4:], uint32(loopCount))
+	writePNGChunk(&buf, "acTL", actl)
+
+	var seq uint32
+	for i, f := range frames {
+		_, idat := pngChunks(t, f.img)
+		b := f.img.Bounds()
+
+		fctl := make([]byte, 26)
+		be.PutUint32(fctl[0:], seq)
+		seq++
+		be.PutUint32(fctl[4:], uint32(b.Dx()))
+		be.PutUint32(fctl[8:], uint32(b.Dy()))
+		be.PutUint32(fctl[12:], uint32(f.x))
+		be.PutUint32(fctl[16:], uint32(f.y))
+		be.PutUint16(fctl[20:], f.delayNum)
+		be.PutUint16(fctl[22:], f.delayDen)
+		fctl[24] = f.disposeOp
+		fctl[25] = f.blendOp
+		writePNGChunk(&buf, "fcTL", fctl)
+
+		if i == 0 {
+			writePNGChunk(&buf, "IDAT", idat)
+			continue
+		}
+		fdat := make([]byte, 4+len(idat))
+		be.PutUint32(fdat[0:], seq)
+		seq++
+		copy(fdat[4:], idat)
+		writePNGChunk(&buf, "fdAT", fdat)
+	}
+	writePNGChunk(&buf, "IEND", nil)
+	return buf.Bytes()
+}
+
+func newNRGBA(clrs ...color.NRGBA) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, len(clrs), 1))
+	for i, c := range clrs {
+		img.SetNRGBA(i, 0, c)
+	}
+	return img
+}
+
+var (
+	transparent = color.RGBA{}
+	red         = color.RGBA{0xff, 0, 0, 0xff}
+	green       = color.RGBA{0, 0xff, 0, 0xff}
+	blue        = color.RGBA{0, 0, 0xff, 0xff}
+)
+
+func TestDecodeAPNG(t *testing.T) {
+	frames := []testAPNGFrame{
+		{
+			img:       newNRGBA(color.NRGBA{0xff, 0, 0, 0xff}, color.NRGBA{0, 0xff, 0, 0xff}, color.NRGBA{}),
+			delayNum:  1,
+			delayDen:  10,
+			disposeOp: apngDisposeOpNone,
+			blendOp:   apngBlendOpSource,
+		},
+		{
+			// A half-transparent blue is blended over the green.
+			img:       newNRGBA(color.NRGBA{0, 0, 0xff, 0x80}),
+			x:         1,
+			delayNum:  20,
+			delayDen:  0, // 0 means 100.
+			disposeOp: apngDisposeOpBackground,
+			blendOp:   apngBlendOpOver,
+		},
+		{
+			// A half-transparent white replaces the red, and then the canvas is restored.
+			img:       newNRGBA(color.NRGBA{0xff, 0xff, 0xff, 0x80}),
+			delayNum:  0,
+			delayDen:  100,
+			disposeOp: apngDisposeOpPrevious,
+			blendOp:   apngBlendOpSource,
+		},
+		{
+			img:       newNRGBA(color.NRGBA{0, 0xff, 0, 0x80}),
+			x:         2,
+			delayNum:  3,
+			delayDen:  100,
+			disposeOp: apngDisposeOpNone,
+			blendOp:   apngBlendOpOver,
+		},
+	}
+	imgs, delays, loopCount, err := decodeAPNG(encodeAPNG(t, 3, 1, 2, frames))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkFrames(t, imgs, [][]color.Color{
+		{red, green, transparent},
+		{red, color.RGBA{0, 0x7f, 0x80, 0xff}, transparent},
+		// The second pixel was disposed to the background after the second frame.
+		{color.RGBA{0x80, 0x80, 0x80, 0x80}, transparent, transparent},
+		// The canvas was restored to the one before the third frame.
+		{red, transparent, color.RGBA{0, 0x80, 0, 0x80}},
+	})
+
+	wantDelays := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 0, 30 * time.Millisecond}
+	for i := range wantDelays {
+		if delays[i] != wantDelays[i] {
+			t.Errorf("delay %d: got: %v, want: %v", i, delays[i], wantDelays[i])
+		}
+	}
+	if got, want := loopCount, 2; got != want {
+		t.Errorf("loop count: got: %d, want: %d", got, want)
+	}
+}
+
+func TestDecodeAPNGNotAnimated(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, newNRGBA(color.NRGBA{0xff, 0, 0, 0xff}, color.NRGBA{0, 0, 0xff, 0xff})); err != nil {
+		t.Fatal(err)
+	}
+	imgs, _, loopCount, err := decodeAPNG(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkFrames(t, imgs, [][]color.Color{{red, blue}})
+	if got, want := loopCount, 0; got != want {
+		t.Errorf("loop count: got: %d, want: %d", got, want)
+	}
+}
+
+func TestDecodeAPNGErrors(t *testing.T) {
+	frame := testAPNGFrame{
+		img:      newNRGBA(color.NRGBA{0xff, 0, 0, 0xff}, color.NRGBA{}),
+		delayNum: 1,
+		delayDen: 10,
+	}
+	valid := encodeAPNG(t, 2, 1, 0, []testAPNGFrame{frame})
+
+	// The IHDR chunk starts after the signature. The acTL chunk follows it.
+	ihdrEnd := len(pngSignature) + 12 + 13
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{
+			name: "not PNG",
+			data: []byte("GIF89a"),
+		},
+		{
+			name: "truncated chunk header",
+			data: valid[:ihdrEnd+4],
+		},
+		{
+			name: "truncated chunk data",
+			data: valid[:ihdrEnd+12],
+		},
+		{
+			name: "fdAT without fcTL",
+			data: func() []byte {
+				var buf bytes.Buffer
+				buf.Write(valid[:ihdrEnd])
+				actl := make([]byte, 8)
+				binary.BigEndian.PutUint32(actl, 1)
+				writePNGChunk(&buf, "acTL", actl)
+				writePNGChunk(&buf, "fdAT", make([]byte, 8))
+				writePNGChunk(&buf, "IEND", nil)
+				return buf.Bytes()
+			}(),
+		},
+		{
+			name: "frame out of the image",
+			data: func() []byte {
+				f := frame
+				f.x = 1
+				return encodeAPNG(t, 2, 1, 0, []testAPNGFrame{f})
+			}(),
+		},
+		{
+			// The width exceeds the limit. This must fail before allocating the frames.
+			name: "too big",
+			data: encodeAPNG(t, 4097, 1, 0, []testAPNGFrame{frame, frame, frame}),
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if _, _, _, err := decodeAPNG(tc.data); err == nil {
+				t.Errorf("decodeAPNG must fail")
+			}
+		})
+	}
+}
+
+func TestDecodeGIF(t *testing.T) {
+	palette := color.Palette{transparent, red, green, blue}
+	newPaletted := func(x int, indices ...uint8) *image.Paletted {
+		p := image.NewPaletted(image.Rect(x, 0, x+len(indices), 1), palette)
+		copy(p.Pix, indices)
+		return p
+	}
+
+	g := &gif.GIF{
+		Image: []*image.Paletted{
+			newPaletted(0, 1, 2, 3),
+			newPaletted(1, 1),
+			newPaletted(0, 2),
+			newPaletted(2, 0),
+		},
+		Delay: []int{0, 1, 5, 10},
+		Disposal: []byte{
+			gif.DisposalNone,
+			gif.DisposalBackground,
+			gif.DisposalPrevious,
+			gif.DisposalNone,
+		},
+		LoopCount: 2,
+		Config: image.Config{
+			ColorModel: palette,
+			Width:      3,
+			Height:     1,
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, g); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	imgs, delays, loopCount, err := decodeGIF(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkFrames(t, imgs, [][]color.Color{
+		{red, green, blue},
+		{red, red, blue},
+		// The second pixel was disposed to the background after the second frame.
+		{green, transparent, blue},
+		// The canvas was restored to the one before the third frame. A transparent pixel doesn't change the canvas.
+		{red, transparent, blue},
+	})
+
+	// A delay 0 or 1 is treated as the default delay later.
+	wantDelays := []time.Duration{0, 0, 50 * time.Millisecond, 100 * time.Millisecond}
+	for i := range wantDelays {
+		if delays[i] != wantDelays[i] {
+			t.Errorf("delay %d: got: %v, want: %v", i, delays[i], wantDelays[i])
+		}
+	}
+	// LoopCount 2 in image/gif means playing 3 times.
+	if got, want := loopCount, 3; got != want {
+		t.Errorf("loop count: got: %d, want: %d", got, want)
+	}
+}
+
+func TestDecodeGIFTooBig(t *testing.T) {
+	palette := color.Palette{transparent, red}
+	g := &gif.GIF{
+		Image: []*image.Paletted{
+			image.NewPaletted(image.Rect(0, 0, 1, 1), palette),
+		},
+		Delay: []int{0},
+		Config: image.Config{
+			ColorModel: palette,
+			Width:      8192,
+			Height:     4096,
+		},
+	}
+	if _, _, _, err := decodeGIF(g); err == nil {
+		t.Errorf("decodeGIF must fail")
+	}
+}
+
+func TestCheckFrameSize(t *testing.T) {
+	if err := checkFrameSize(4096, 4096); err != nil {
+		t.Error(err)
+	}
+	if err := checkFrameSize(4097, 1); err == nil {
+		t.Errorf("checkFrameSize must fail with a 4097x1 frame")
+	}
+	if err := checkFrameSize(1, 4097); err == nil {
+		t.Errorf("checkFrameSize must fail with a 1x4097 frame")
+	}
+}
+
+func TestLayoutFrames(t *testing.T) {
+	tests := []struct {
+		width   int
+		height  int
+		count   int
+		maxSize int
+		sheets  int
+	}{
+		{width: 3, height: 2, count: 1, maxSize: 7, sheets: 1},
+		{width: 3, height: 2, count: 6, maxSize: 7, sheets: 1},
+		{width: 3, height: 2, count: 7, maxSize: 7, sheets: 2},
+		{width: 3, height: 2, count: 13, maxSize: 7, sheets: 3},
+		{width: 7, height: 7, count: 3, maxSize: 7, sheets: 3},
+		// Frames in a strip must not exceed the maximum height.
+		{width: 1, height: 16, count: 300, maxSize: 64, sheets: 2},
+		{width: 64, height: 64, count: 200, maxSize: 4096, sheets: 1},
+	}
+	for _, tc := range tests {
+		sheets, indices, rects := layoutFrames(tc.width, tc.height, tc.count, tc.maxSize)
+		if got, want := len(sheets), tc.sheets; got != want {
+			t.Errorf("%v: sheet count: got: %d, want: %d", tc, got, want)
+		}
+		for _, s := range sheets {
+			if s.X > tc.maxSize || s.Y > tc.maxSize {
+				t.Errorf("%v: sheet size %v exceeds %d", tc, s, tc.maxSize)
+			}
+		}
+		if got, want := len(rects), tc.count; got != want {
+			t.Fatalf("%v: frame count: got: %d, want: %d", tc, got, want)
+		}
+		for i, r := range rects {
+			if r.Dx() != tc.width || r.Dy() != tc.height {
+				t.Errorf("%v: frame %d: size: got: %v, want: %dx%d", tc, i, r.Size(), tc.width, tc.height)
+			}
+			if !r.In(image.Rectangle{Max: sheets[indices[i]]}) {
+				t.Errorf("%v: frame %d: %v is out of the sheet %v", tc, i, r, sheets[indices[i]])
+			}
+			for j := 0; j < i; j++ {
+				if indices[i] == indices[j] && r.Overlaps(rects[j]) {
+					t.Errorf("%v: frames %d and %d overlap", tc, i, j)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,238 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anim
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/draw"
+	"image/png"
+	"io"
+	"io/ioutil"
+	"time"
+)
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+const (
+	apngDisposeOpNone       = 0
+	apngDisposeOpBackground = 1
+	apngDisposeOpPrevious   = 2
+
+	apngBlendOpSource = 0
+	apngBlendOpOver   = 1
+)
+
+type apngFrame struct {
+	rect      image.Rectangle
+	delay     time.Duration
+	disposeOp byte
+	blendOp   byte
+	data      []byte
+}
+
+// DecodeAPNG reads an APNG file from r and creates an animation.
+//
+// A PNG file without animation is decoded as an animation with one frame.
+// The default image is not included in the animation if it is not the first frame.
+//
+// DecodeAPNG returns an error if the frame size is larger than 4096x4096.
+func DecodeAPNG(r io.Reader) (*Anim, error) {
+	bs, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	imgs, delays, loopCount, err := decodeAPNG(bs)
+	if err != nil {
+		return nil, err
+	}
+	return New(imgs, delays, loopCount), nil
+}
+
+// decodeAPNG decodes the frames of the APNG file bs, composited on the canvas.
+func decodeAPNG(bs []byte) ([]image.Image, []time.Duration, int, error) {
+	if !bytes.HasPrefix(bs, pngSignature) {
+		return nil, nil, 0, fmt.Errorf("anim: not a PNG file")
+	}
+
+	var (
+		ihdr      []byte
+		commons   [][]byte
+		animated  bool
+		loopCount int
+		frames    []*apngFrame
+		seenIDAT  bool
+	)
+
+	be := binary.BigEndian
+	for rest := bs[len(pngSignature):]; len(rest) > 0; {
+		if len(rest) < 12 {
+			return nil, nil, 0, fmt.Errorf("anim: unexpected EOF in a chunk")
+		}
+		n := be.Uint32(rest)
+		if uint64(n) > uint64(len(rest)-12) {
+			return nil, nil, 0, fmt.Errorf("anim: unexpected EOF in a chunk")
+		}
+		chunk := rest[:12+n]
+		typ := string(chunk[4:8])
+		data := chunk[8 : 8+n]
+		rest = rest[12+n:]
+
+		switch typ {
+		case "IHDR":
+			if n != 13 {
+				return nil, nil, 0, fmt.Errorf("anim: invalid IHDR length: %d", n)
+			}
+			ihdr = data
+		case "acTL":
+			if n != 8 {
+				return nil, nil, 0, fmt.Errorf("anim: invalid acTL length: %d", n)
+			}
+			animated = true
+			loopCount = int(be.Uint32(data[4:]))
+		case "fcTL":
+			if n != 26 {
+				return nil, nil, 0, fmt.Errorf("anim: invalid fcTL length: %d", n)
+			}
+			w, h := int(be.Uint32(data[4:])), int(be.Uint32(data[8:]))
+			x, y := int(be.Uint32(data[12:])), int(be.Uint32(data[16:]))
+			num, den := be.Uint16(data[20:]), be.Uint16(data[22:])
+			if den == 0 {
+				den = 100
+			}
+			frames = append(frames, &apngFrame{
+				rect:      image.Rect(x, y, x+w, y+h),
+				delay:     time.Duration(num) * time.Second / time.Duration(den),
+				disposeOp: data[24],
+				blendOp:   data[25],
+			})
+		case "IDAT":
+			seenIDAT = true
+			// The default image is the first frame only when fcTL precedes IDAT.
+			if len(frames) == 1 {
+				frames[0].data = append(frames[0].data, data...)
+			}
+		case "fdAT":
+			if len(frames) == 0 {
+				return nil, nil, 0, fmt.Errorf("anim: fdAT without fcTL")
+			}
+			if n < 4 {
+				return nil, nil, 0, fmt.Errorf("anim: invalid fdAT length: %d", n)
+			}
+			f := frames[len(frames)-1]
+			f.data = append(f.data, data[4:]...)
+		case "IEND":
+			rest = nil
+		default:
+			// Chunks before IDAT like PLTE and tRNS are shared by all the frames.
+			if !seenIDAT {
+				commons = append(commons, chunk)
+			}
+		}
+	}
+	if ihdr == nil {
+		return nil, nil, 0, fmt.Errorf("anim: IHDR not found")
+	}
+
+	if !animated || len(frames) == 0 {
+		if err := checkFrameSize(int(be.Uint32(ihdr[0:])), int(be.Uint32(ihdr[4:]))); err != nil {
+			return nil, nil, 0, err
+		}
+		img, err := png.Decode(bytes.NewReader(bs))
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		return []image.Image{img}, []time.Duration{0}, 0, nil
+	}
+
+	// Check the size before decoding the frames, as each frame is allocated with the canvas size.
+	if err := checkFrameSize(int(be.Uint32(ihdr[0:])), int(be.Uint32(ihdr[4:]))); err != nil {
+		return nil, nil, 0, err
+	}
+	bounds := image.Rect(0, 0, int(be.Uint32(ihdr[0:])), int(be.Uint32(ihdr[4:])))
+	canvas := image.NewRGBA(bounds)
+	var prev *image.RGBA
+	imgs := make([]image.Image, 0, len(frames))
+	delays := make([]time.Duration, 0, len(frames))
+	for i, f := range frames {
+		if !f.rect.In(bounds) || f.rect.Empty() {
+			return nil, nil, 0, fmt.Errorf("anim: frame %d is out of the image: %v", i, f.rect)
+		}
+		img, err := png.Decode(bytes.NewReader(apngFrameToPNG(ihdr, commons, f)))
+		if err != nil {
+			return nil, nil, 0, fmt.Errorf("anim: frame %d: %w", i, err)
+		}
+
+		disposeOp := f.disposeOp
+		if i == 0 && disposeOp == apngDisposeOpPrevious {
+			disposeOp = apngDisposeOpBackground
+		}
+		if disposeOp == apngDisposeOpPrevious {
+			prev = cloneRGBA(canvas)
+		}
+
+		op := draw.Over
+		if f.blendOp == apngBlendOpSource {
+			op = draw.Src
+		}
+		draw.Draw(canvas, f.rect, img, img.Bounds().Min, op)
+		imgs = append(imgs, cloneRGBA(canvas))
+		delays = append(delays, f.delay)
+
+		switch disposeOp {
+		case apngDisposeOpBackground:
+			draw.Draw(canvas, f.rect, image.Transparent, image.Point{}, draw.Src)
+		case apngDisposeOpPrevious:
+			canvas = prev
+		}
+	}
+	return imgs, delays, loopCount, nil
+}
+
+// apngFrameToPNG creates a PNG file of the frame f.
+func apngFrameToPNG(ihdr []byte, commons [][]byte, f *apngFrame) []byte {
+	var buf bytes.Buffer
+	buf.Write(pngSignature)
+
+	h := make([]byte, len(ihdr))
+	copy(h, ihdr)
+	binary.BigEndian.PutUint32(h[0:], uint32(f.rect.Dx()))
+	binary.BigEndian.PutUint32(h[4:], uint32(f.rect.Dy()))
+	writePNGChunk(&buf, "IHDR", h)
+
+	for _, c := range commons {
+		buf.Write(c)
+	}
+	writePNGChunk(&buf, "IDAT", f.data)
+	writePNGChunk(&buf, "IEND", nil)
+	return buf.Bytes()
+}
+
+func writePNGChunk(buf *bytes.Buffer, typ string, data []byte) {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], uint32(len(data)))
+	buf.Write(b[:])
+
+	crc := crc32.NewIEEE()
+	crc.Write([]byte(typ))
+	crc.Write(data)
+	buf.WriteString(typ)
+	buf.Write(data)
+	binary.BigEndian.PutUint32(b[:], crc.Sum32())
+	buf.Write(b[:])
+}
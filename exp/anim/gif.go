@@ -0,0 +1,105 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anim
+
+import (
+	"image"
+	"image/draw"
+	"image/gif"
+	"io"
+	"time"
+)
+
+// DecodeGIF reads a GIF file from r and creates an animation.
+//
+// A frame delay 0 or 1 hundredth of a second is treated as 100 milliseconds, as web browsers do.
+//
+// DecodeGIF returns an error if the frame size is larger than 4096x4096.
+func DecodeGIF(r io.Reader) (*Anim, error) {
+	g, err := gif.DecodeAll(r)
+	if err != nil {
+		return nil, err
+	}
+	frames, delays, loopCount, err := decodeGIF(g)
+	if err != nil {
+		return nil, err
+	}
+	return New(frames, delays, loopCount), nil
+}
+
+// decodeGIF returns the frames of g, composited on the canvas.
+func decodeGIF(g *gif.GIF) ([]image.Image, []time.Duration, int, error) {
+	bounds := image.Rect(0, 0, g.Config.Width, g.Config.Height)
+	if bounds.Empty() {
+		for _, p := range g.Image {
+			bounds = bounds.Union(p.Bounds())
+		}
+	}
+
+	// Check the size before compositing the frames, as each frame is allocated with the canvas size.
+	if err := checkFrameSize(bounds.Dx(), bounds.Dy()); err != nil {
+		return nil, nil, 0, err
+	}
+
+	canvas := image.NewRGBA(bounds)
+	var prev *image.RGBA
+	frames := make([]image.Image, 0, len(g.Image))
+	delays := make([]time.Duration, 0, len(g.Image))
+	for i, p := range g.Image {
+		var disposal byte
+		if i < len(g.Disposal) {
+			disposal = g.Disposal[i]
+		}
+		if disposal == gif.DisposalPrevious {
+			prev = cloneRGBA(canvas)
+		}
+
+		draw.Draw(canvas, p.Bounds(), p, p.Bounds().Min, draw.Over)
+		frames = append(frames, cloneRGBA(canvas))
+
+		var d time.Duration
+		if i < len(g.Delay) && g.Delay[i] > 1 {
+			d = time.Duration(g.Delay[i]) * 10 * time.Millisecond
+		}
+		delays = append(delays, d)
+
+		switch disposal {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, p.Bounds(), image.Transparent, image.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			canvas = prev
+		}
+	}
+
+	// In image/gif, LoopCount 0 means looping forever, -1 means playing once, and n means playing n+1 times.
+	loopCount := 0
+	if g.LoopCount != 0 {
+		loopCount = g.LoopCount + 1
+		if loopCount < 1 {
+			loopCount = 1
+		}
+	}
+	return frames, delays, loopCount, nil
+}
+
+func cloneRGBA(img *image.RGBA) *image.RGBA {
+	c := &image.RGBA{
+		Pix:    make([]byte, len(img.Pix)),
+		Stride: img.Stride,
+		Rect:   img.Rect,
+	}
+	copy(c.Pix, img.Pix)
+	return c
+}
@@ -0,0 +1,317 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bundle provides a packed asset container that bundles many files like images, audio and shaders
+// into one file with an index.
+//
+// A bundle is created by Writer, typically by a tool at build time, and read by Open or New.
+// On desktops and mobiles, Open maps the file into memory, so only the entries actually used are read
+// from the disk. On browsers, fetch the bundle as one file and pass its bytes to New.
+//
+// Entries can be encrypted or otherwise transformed: transform the data before Writer.Add and specify the
+// inverse function as Options.Transform.
+//
+// This package is experimental and the API might be changed in the future.
+package bundle
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+)
+
+// The format of a bundle is:
+//
+//	header:  magic (8 bytes) and version (uint32)
+//	entries: the data of the entries
+//	index:   for each entry, the name length (uint16), the name, the offset (uint64) and the size (uint64)
+//	trailer: the index offset (uint64), the number of the entries (uint32) and magic (8 bytes)
+//
+// All the integers are little endian.
+const (
+	magic   = "EBBUNDLE"
+	version = 1
+
+	headerSize  = len(magic) + 4
+	trailerSize = 8 + 4 + len(magic)
+)
+
+var errClosed = errors.New("bundle: the bundle is already closed")
+
+// Options represents options to read a bundle.
+type Options struct {
+	// Transform is called with the entry's name and its raw data when an entry is read, e.g., to decrypt the data.
+	// The result is returned to the caller instead of the raw data.
+	//
+	// data is read-only and might be invalidated after the Bundle is closed, so Transform must not modify or
+	// keep it.
+	//
+	// Transform might be called from multiple goroutines.
+	//
+	// If Transform is nil, the raw data is used as it is.
+	Transform func(name string, data []byte) ([]byte, error)
+}
+
+type entry struct {
+	offset uint64
+	size   uint64
+}
+
+// Bundle is a packed asset container.
+type Bundle struct {
+	data      []byte
+	entries   map[string]entry
+	transform func(name string, data []byte) ([]byte, error)
+	unmap     func() error
+
+	m sync.RWMutex
+}
+
+// Open opens the bundle file at path.
+//
+// The file is memory-mapped where possible. Call Close to release it.
+func Open(path string, options *Options) (*Bundle, error) {
+	data, unmap, err := mapFile(path)
+	if err != nil {
+		return nil, err
+	}
+	b, err := newBundle(data, options)
+	if err != nil {
+		_ = unmap()
+		return nil, err
+	}
+	b.unmap = unmap
+	return b, nil
+}
+
+// New creates a bundle from the bytes of a bundle file, e.g., fetched on browsers or embedded into the binary.
+//
+// data must not be modified while the Bundle is used.
+func New(data []byte, options *Options) (*Bundle, error) {
+	return newBundle(data, options)
+}
+
+func newBundle(data []byte, options *Options) (*Bundle, error) {
+	if len(data) < headerSize+trailerSize {
+		return nil, errors.New("bundle: the data is too short")
+	}
+	if string(data[:len(magic)]) != magic || string(data[len(data)-len(magic):]) != magic {
+		return nil, errors.New("bundle: invalid magic")
+	}
+	if v := binary.LittleEndian.Uint32(data[len(magic):]); v != version {
+		return nil, fmt.Errorf("bundle: unsupported version: %d", v)
+	}
+
+	trailer := data[len(data)-trailerSize:]
+	indexOffset := binary.LittleEndian.Uint64(trailer)
+	n := int(binary.LittleEndian.Uint32(trailer[8:]))
+	indexEnd := uint64(len(data) - trailerSize)
+	if indexOffset < uint64(headerSize) || indexOffset > indexEnd {
+		return nil, errors.New("bundle: invalid index offset")
+	}
+
+	b := &Bundle{
+		data:    data,
+		entries: map[string]entry{},
+	}
+	if options != nil {
+		b.transform = options.Transform
+	}
+
+	index := data[indexOffset:indexEnd]
+	for i := 0; i < n; i++ {
+		if len(index) < 2 {
+			return nil, errors.New("bundle: unexpected end of the index")
+		}
+		l := int(binary.LittleEndian.Uint16(index))
+		index = index[2:]
+		if len(index) < l+16 {
+			return nil, errors.New("bundle: unexpected end of the index")
+		}
+		name := string(index[:l])
+		e := entry{
+			offset: binary.LittleEndian.Uint64(index[l:]),
+			size:   binary.LittleEndian.Uint64(index[l+8:]),
+		}
+		index = index[l+16:]
+		if e.offset < uint64(headerSize) || e.offset > indexOffset || e.size > indexOffset-e.offset {
+			return nil, fmt.Errorf("bundle: invalid range of the entry %q", name)
+		}
+		if _, ok := b.entries[name]; ok {
+			return nil, fmt.Errorf("bundle: duplicated entry %q", name)
+		}
+		b.entries[name] = e
+	}
+	return b, nil
+}
+
+// Close closes the bundle.
+//
+// After Close, Bytes and Reader return an error, and the readers returned by Reader and Audio fail to read.
+// The byte slices returned by Bytes are still valid after Close.
+func (b *Bundle) Close() error {
+	// Close waits for reading the bundle's memory finishes as reading is done with the read lock.
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	b.data = nil
+	b.entries = nil
+	if b.unmap == nil {
+		return nil
+	}
+	err := b.unmap()
+	b.unmap = nil
+	return err
+}
+
+// AppendNames appends the names of the entries in sorted order to names and returns the extended buffer.
+//
+// AppendNames is concurrent-safe.
+func (b *Bundle) AppendNames(names []string) []string {
+	b.m.RLock()
+	defer b.m.RUnlock()
+
+	n := len(names)
+	for name := range b.entries {
+		names = append(names, name)
+	}
+	sort.Strings(names[n:])
+	return names
+}
+
+// Bytes returns the data of the entry with the given name.
+//
+// If the bundle is created by New and Options.Transform is nil, the returned slice refers to the given data
+// directly and must not be modified. If the bundle is opened by Open, the returned slice is a copy of the
+// memory-mapped data, as the memory is unmapped at Close.
+//
+// If the entry doesn't exist, Bytes returns an error that satisfies errors.Is(err, os.ErrNotExist).
+//
+// Bytes is concurrent-safe.
+func (b *Bundle) Bytes(name string) ([]byte, error) {
+	b.m.RLock()
+	defer b.m.RUnlock()
+
+	e, err := b.entry(name)
+	if err != nil {
+		return nil, err
+	}
+	data := b.data[e.offset : e.offset+e.size : e.offset+e.size]
+	if b.transform == nil {
+		if b.unmap != nil {
+			data = append([]byte(nil), data...)
+		}
+		return data, nil
+	}
+	data, err = b.transform(name, data)
+	if err != nil {
+		return nil, fmt.Errorf("bundle: transforming entry %q failed: %w", name, err)
+	}
+	return data, nil
+}
+
+// entry returns the entry with the given name.
+//
+// entry must be called with the lock.
+func (b *Bundle) entry(name string) (entry, error) {
+	if b.entries == nil {
+		return entry{}, errClosed
+	}
+	e, ok := b.entries[name]
+	if !ok {
+		return entry{}, fmt.Errorf("bundle: entry %q: %w", name, os.ErrNotExist)
+	}
+	return e, nil
+}
+
+// Reader returns a reader for the entry with the given name.
+//
+// If Options.Transform is nil, the reader reads the bundle's memory directly without copying the whole entry,
+// and fails to read after Close. Otherwise, the reader reads the transformed data.
+//
+// Reader is concurrent-safe.
+func (b *Bundle) Reader(name string) (io.ReadSeeker, error) {
+	if b.transform != nil {
+		data, err := b.Bytes(name)
+		if err != nil {
+			return nil, err
+		}
+		return bytes.NewReader(data), nil
+	}
+
+	b.m.RLock()
+	defer b.m.RUnlock()
+
+	e, err := b.entry(name)
+	if err != nil {
+		return nil, err
+	}
+	return &entryReader{
+		bundle: b,
+		offset: int64(e.offset),
+		size:   int64(e.size),
+	}, nil
+}
+
+// entryReader is a reader for an entry.
+//
+// entryReader reads the bundle's memory with the read lock, so reading after Close fails instead of accessing
+// the unmapped memory.
+type entryReader struct {
+	bundle *Bundle
+	offset int64
+	size   int64
+	pos    int64
+}
+
+// Read implements io.Reader.
+func (r *entryReader) Read(buf []byte) (int, error) {
+	r.bundle.m.RLock()
+	defer r.bundle.m.RUnlock()
+
+	if r.bundle.data == nil {
+		return 0, errClosed
+	}
+	if r.pos >= r.size {
+		return 0, io.EOF
+	}
+	n := copy(buf, r.bundle.data[r.offset+r.pos:r.offset+r.size])
+	r.pos += int64(n)
+	return n, nil
+}
+
+// Seek implements io.Seeker.
+func (r *entryReader) Seek(offset int64, whence int) (int64, error) {
+	var pos int64
+	switch whence {
+	case io.SeekStart:
+		pos = offset
+	case io.SeekCurrent:
+		pos = r.pos + offset
+	case io.SeekEnd:
+		pos = r.size + offset
+	default:
+		return 0, errors.New("bundle: invalid whence")
+	}
+	if pos < 0 {
+		return 0, errors.New("bundle: negative position")
+	}
+	r.pos = pos
+	return pos, nil
+}
@@ -0,0 +1,171 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundle_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2/exp/bundle"
+)
+
+var testEntries = []struct {
+	name string
+	data []byte
+}{
+	{"b.txt", []byte("bar")},
+	{"a.txt", []byte("foo")},
+	{"empty", []byte{}},
+}
+
+func writeTestBundle(t *testing.T) []byte {
+	var buf bytes.Buffer
+	w := bundle.NewWriter(&buf)
+	for _, e := range testEntries {
+		if err := w.Add(e.name, e.data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestOpen(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bundle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "test.bundle")
+	if err := ioutil.WriteFile(path, writeTestBundle(t), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := bundle.Open(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := b.AppendNames(nil), []string{"a.txt", "b.txt", "empty"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("AppendNames: got %v, want %v", got, want)
+	}
+
+	for _, e := range testEntries {
+		got, err := b.Bytes(e.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, e.data) {
+			t.Errorf("Bytes(%q): got %q, want %q", e.name, got, e.data)
+		}
+	}
+
+	if _, err := b.Bytes("missing"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Bytes for a missing entry: got %v, want os.ErrNotExist", err)
+	}
+
+	data, err := b.Bytes("a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := b.Reader("b.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Seek(1, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []byte("ar"); !bytes.Equal(got, want) {
+		t.Errorf("Reader: got %q, want %q", got, want)
+	}
+
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A slice returned by Bytes is still valid after Close.
+	if want := []byte("foo"); !bytes.Equal(data, want) {
+		t.Errorf("Bytes after Close: got %q, want %q", data, want)
+	}
+
+	// A reader fails to read after Close instead of accessing the released memory.
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Read(make([]byte, 1)); err == nil || err == io.EOF {
+		t.Errorf("Read after Close: got %v, want an error", err)
+	}
+	if _, err := b.Bytes("a.txt"); err == nil {
+		t.Errorf("Bytes after Close must return an error but not")
+	}
+}
+
+func TestNewWithTransform(t *testing.T) {
+	b, err := bundle.New(writeTestBundle(t), &bundle.Options{
+		Transform: func(name string, data []byte) ([]byte, error) {
+			return bytes.ToUpper(data), nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+
+	got, err := b.Bytes("a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []byte("FOO"); !bytes.Equal(got, want) {
+		t.Errorf("Bytes: got %q, want %q", got, want)
+	}
+
+	r, err := b.Reader("b.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err = ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []byte("BAR"); !bytes.Equal(got, want) {
+		t.Errorf("Reader: got %q, want %q", got, want)
+	}
+}
+
+func TestNewInvalid(t *testing.T) {
+	data := writeTestBundle(t)
+	for _, d := range [][]byte{
+		nil,
+		data[:len(data)-1],
+		append([]byte("XXXXXXXX"), data[8:]...),
+	} {
+		if _, err := bundle.New(d, nil); err == nil {
+			t.Errorf("New(%q) must return an error but not", d)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundle
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/audio"
+	"github.com/hajimehoshi/ebiten/v2/audio/mp3"
+	"github.com/hajimehoshi/ebiten/v2/audio/vorbis"
+	"github.com/hajimehoshi/ebiten/v2/audio/wav"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+// Image decodes the entry with the given name as an image and creates an *ebiten.Image from it.
+//
+// The image is decoded by ebitenutil.NewImageFromReader. The image format's decoder must be registered with
+// ebitenutil.RegisterImageFormat or the image package, e.g., by importing image/png.
+func (b *Bundle) Image(name string) (*ebiten.Image, error) {
+	r, err := b.Reader(name)
+	if err != nil {
+		return nil, err
+	}
+	img, _, err := ebitenutil.NewImageFromReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("bundle: decoding entry %q failed: %w", name, err)
+	}
+	return img, nil
+}
+
+// Shader compiles the entry with the given name as a Kage shader.
+func (b *Bundle) Shader(name string) (*ebiten.Shader, error) {
+	src, err := b.Bytes(name)
+	if err != nil {
+		return nil, err
+	}
+	s, err := ebiten.NewShader(src)
+	if err != nil {
+		return nil, fmt.Errorf("bundle: compiling entry %q failed: %w", name, err)
+	}
+	return s, nil
+}
+
+// Audio decodes the entry with the given name as an audio stream for the context.
+//
+// The format is determined by the name's extension: .wav, .mp3 or .ogg (Ogg/Vorbis).
+// The returned stream reads the bundle's memory directly, so it fails to read after Close.
+func (b *Bundle) Audio(context *audio.Context, name string) (io.ReadSeeker, error) {
+	r, err := b.Reader(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var s io.ReadSeeker
+	switch ext := strings.ToLower(path.Ext(name)); ext {
+	case ".wav":
+		s, err = wav.DecodeWithSampleRate(context.SampleRate(), r)
+	case ".mp3":
+		s, err = mp3.DecodeWithSampleRate(context.SampleRate(), r)
+	case ".ogg":
+		s, err = vorbis.DecodeWithSampleRate(context.SampleRate(), r)
+	default:
+		return nil, fmt.Errorf("bundle: unsupported audio format: %q", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("bundle: decoding entry %q failed: %w", name, err)
+	}
+	return s, nil
+}
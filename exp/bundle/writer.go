@@ -0,0 +1,121 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundle
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// Writer writes a bundle.
+type Writer struct {
+	w      io.Writer
+	offset uint64
+	names  []string
+	index  map[string]entry
+	err    error
+}
+
+// NewWriter creates a new Writer writing a bundle to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{
+		w:     w,
+		index: map[string]entry{},
+	}
+}
+
+func (w *Writer) write(data []byte) error {
+	if w.err != nil {
+		return w.err
+	}
+	n, err := w.w.Write(data)
+	w.offset += uint64(n)
+	if err != nil {
+		w.err = err
+	}
+	return err
+}
+
+func (w *Writer) writeHeader() error {
+	if w.offset > 0 {
+		return nil
+	}
+	var buf [headerSize]byte
+	copy(buf[:], magic)
+	binary.LittleEndian.PutUint32(buf[len(magic):], version)
+	return w.write(buf[:])
+}
+
+// Add adds an entry with the given name and data.
+//
+// To encrypt entries, pass the encrypted data to Add and the decrypting function as Options.Transform when reading.
+func (w *Writer) Add(name string, data []byte) error {
+	if name == "" {
+		return errors.New("bundle: name must not be empty")
+	}
+	if len(name) > math.MaxUint16 {
+		return fmt.Errorf("bundle: name is too long: %q", name)
+	}
+	if _, ok := w.index[name]; ok {
+		return fmt.Errorf("bundle: duplicated entry %q", name)
+	}
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	e := entry{
+		offset: w.offset,
+		size:   uint64(len(data)),
+	}
+	if err := w.write(data); err != nil {
+		return err
+	}
+	w.names = append(w.names, name)
+	w.index[name] = e
+	return nil
+}
+
+// Close writes the index of the bundle.
+//
+// Close doesn't close the underlying writer.
+func (w *Writer) Close() error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	indexOffset := w.offset
+	for _, name := range w.names {
+		e := w.index[name]
+		buf := make([]byte, 2+len(name)+16)
+		binary.LittleEndian.PutUint16(buf, uint16(len(name)))
+		copy(buf[2:], name)
+		binary.LittleEndian.PutUint64(buf[2+len(name):], e.offset)
+		binary.LittleEndian.PutUint64(buf[2+len(name)+8:], e.size)
+		if err := w.write(buf); err != nil {
+			return err
+		}
+	}
+
+	var trailer [trailerSize]byte
+	binary.LittleEndian.PutUint64(trailer[:], indexOffset)
+	binary.LittleEndian.PutUint32(trailer[8:], uint32(len(w.names)))
+	copy(trailer[12:], magic)
+	if err := w.write(trailer[:]); err != nil {
+		return err
+	}
+	w.err = errors.New("bundle: the writer is already closed")
+	return nil
+}
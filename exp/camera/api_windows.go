@@ -0,0 +1,441 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !ebitencbackend
+// +build !ebitencbackend
+
+package camera
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// The values and the types are taken from mfapi.h, mfidl.h, mfobjects.h and mfreadwrite.h.
+
+const (
+	_MF_VERSION     = 0x00020070
+	_MFSTARTUP_FULL = 0
+
+	_MF_SOURCE_READER_FIRST_VIDEO_STREAM = 0xfffffffc
+
+	_MF_SOURCE_READERF_ERROR                   = 0x00000001
+	_MF_SOURCE_READERF_ENDOFSTREAM             = 0x00000002
+	_MF_SOURCE_READERF_CURRENTMEDIATYPECHANGED = 0x00000020
+)
+
+var (
+	_MF_DEVSOURCE_ATTRIBUTE_SOURCE_TYPE                      = windows.GUID{Data1: 0xc60ac5fe, Data2: 0x252a, Data3: 0x478f, Data4: [...]byte{0xa0, 0xef, 0xbc, 0x8f, 0xa5, 0xf7, 0xca, 0xd3}}
+	_MF_DEVSOURCE_ATTRIBUTE_SOURCE_TYPE_VIDCAP_GUID          = windows.GUID{Data1: 0x8ac3587a, Data2: 0x4ae7, Data3: 0x42d8, Data4: [...]byte{0x99, 0xe0, 0x0a, 0x60, 0x13, 0xee, 0xf9, 0x0f}}
+	_MF_DEVSOURCE_ATTRIBUTE_FRIENDLY_NAME                    = windows.GUID{Data1: 0x60d0e559, Data2: 0x52f8, Data3: 0x4fa2, Data4: [...]byte{0xbb, 0xce, 0xac, 0xdb, 0x34, 0xa8, 0xec, 0x01}}
+	_MF_DEVSOURCE_ATTRIBUTE_SOURCE_TYPE_VIDCAP_SYMBOLIC_LINK = windows.GUID{Data1: 0x58f0aad8, Data2: 0x22bf, Data3: 0x4f8a, Data4: [...]byte{0xbb, 0x3d, 0xd2, 0xc4, 0x97, 0x8c, 0x6e, 0x2f}}
+	_MF_SOURCE_READER_ENABLE_VIDEO_PROCESSING                = windows.GUID{Data1: 0xfb394f3d, Data2: 0xccf1, Data3: 0x42ee, Data4: [...]byte{0xbb, 0xb3, 0xf9, 0xb8, 0x45, 0xd5, 0x68, 0x1d}}
+	_MF_MT_MAJOR_TYPE                                        = windows.GUID{Data1: 0x48eba18e, Data2: 0xf8c9, Data3: 0x4687, Data4: [...]byte{0xbf, 0x11, 0x0a, 0x74, 0xc9, 0xf9, 0x6a, 0x8f}}
+	_MF_MT_SUBTYPE                                           = windows.GUID{Data1: 0xf7e34c9a, Data2: 0x42e8, Data3: 0x4714, Data4: [...]byte{0xb7, 0x4b, 0xcb, 0x29, 0xd7, 0x2c, 0x35, 0xe5}}
+	_MF_MT_FRAME_SIZE                                        = windows.GUID{Data1: 0x1652c33d, Data2: 0xd6b2, Data3: 0x4012, Data4: [...]byte{0xb8, 0x34, 0x72, 0x03, 0x08, 0x49, 0xa3, 0x7d}}
+	_MF_MT_DEFAULT_STRIDE                                    = windows.GUID{Data1: 0x644b4e48, Data2: 0x1e02, Data3: 0x4516, Data4: [...]byte{0xb0, 0xeb, 0xc0, 0x1c, 0xa9, 0xd4, 0x9a, 0xc6}}
+	_MFMediaType_Video                                       = windows.GUID{Data1: 0x73646976, Data2: 0x0000, Data3: 0x0010, Data4: [...]byte{0x80, 0x00, 0x00, 0xaa, 0x00, 0x38, 0x9b, 0x71}}
+	_MFVideoFormat_RGB32                                     = windows.GUID{Data1: 0x00000016, Data2: 0x0000, Data3: 0x0010, Data4: [...]byte{0x80, 0x00, 0x00, 0xaa, 0x00, 0x38, 0x9b, 0x71}}
+
+	_IID_IMFMediaSource = windows.GUID{Data1: 0x279a808d, Data2: 0xaec7, Data3: 0x40c8, Data4: [...]byte{0x9c, 0x6b, 0xa6, 0xb4, 0x92, 0xc7, 0x8a, 0x66}}
+)
+
+var (
+	mfplat      = windows.NewLazySystemDLL("mfplat.dll")
+	mf          = windows.NewLazySystemDLL("mf.dll")
+	mfreadwrite = windows.NewLazySystemDLL("mfreadwrite.dll")
+
+	procMFStartup          = mfplat.NewProc("MFStartup")
+	procMFCreateAttributes = mfplat.NewProc("MFCreateAttributes")
+	procMFCreateMediaType  = mfplat.NewProc("MFCreateMediaType")
+
+	procMFEnumDeviceSources = mf.NewProc("MFEnumDeviceSources")
+
+	procMFCreateSourceReaderFromMediaSource = mfreadwrite.NewProc("MFCreateSourceReaderFromMediaSource")
+)
+
+type hresultError uint32
+
+func (h hresultError) Error() string {
+	return fmt.Sprintf("HRESULT: 0x%08x", uint32(h))
+}
+
+func failed(r uintptr) bool {
+	return int32(r) < 0
+}
+
+func _MFStartup() error {
+	if err := procMFStartup.Find(); err != nil {
+		return err
+	}
+	r, _, _ := procMFStartup.Call(_MF_VERSION, _MFSTARTUP_FULL)
+	if failed(r) {
+		return fmt.Errorf("camera: MFStartup failed: %w", hresultError(r))
+	}
+	return nil
+}
+
+func _MFCreateAttributes(initialSize uint32) (*iMFAttributes, error) {
+	var attr *iMFAttributes
+	r, _, _ := procMFCreateAttributes.Call(uintptr(unsafe.Pointer(&attr)), uintptr(initialSize))
+	if failed(r) {
+		return nil, fmt.Errorf("camera: MFCreateAttributes failed: %w", hresultError(r))
+	}
+	return attr, nil
+}
+
+func _MFCreateMediaType() (*iMFAttributes, error) {
+	var t *iMFAttributes
+	r, _, _ := procMFCreateMediaType.Call(uintptr(unsafe.Pointer(&t)))
+	if failed(r) {
+		return nil, fmt.Errorf("camera: MFCreateMediaType failed: %w", hresultError(r))
+	}
+	return t, nil
+}
+
+// _MFEnumDeviceSources returns the activation objects of the devices. The returned slice must be freed with
+// freeActivates.
+func _MFEnumDeviceSources(attr *iMFAttributes) ([]*iMFActivate, error) {
+	var ptr **iMFActivate
+	var count uint32
+	r, _, _ := procMFEnumDeviceSources.Call(uintptr(unsafe.Pointer(attr)), uintptr(unsafe.Pointer(&ptr)), uintptr(unsafe.Pointer(&count)))
+	if failed(r) {
+		return nil, fmt.Errorf("camera: MFEnumDeviceSources failed: %w", hresultError(r))
+	}
+	if count == 0 {
+		if ptr != nil {
+			windows.CoTaskMemFree(unsafe.Pointer(ptr))
+		}
+		return nil, nil
+	}
+	return (*[1 << 20]*iMFActivate)(unsafe.Pointer(ptr))[:count:count], nil
+}
+
+func freeActivates(activates []*iMFActivate) {
+	if len(activates) == 0 {
+		return
+	}
+	for _, a := range activates {
+		a.Release()
+	}
+	windows.CoTaskMemFree(unsafe.Pointer(&activates[0]))
+}
+
+func _MFCreateSourceReaderFromMediaSource(source *iMFMediaSource, attr *iMFAttributes) (*iMFSourceReader, error) {
+	var reader *iMFSourceReader
+	r, _, _ := procMFCreateSourceReaderFromMediaSource.Call(uintptr(unsafe.Pointer(source)), uintptr(unsafe.Pointer(attr)), uintptr(unsafe.Pointer(&reader)))
+	if failed(r) {
+		return nil, fmt.Errorf("camera: MFCreateSourceReaderFromMediaSource failed: %w", hresultError(r))
+	}
+	return reader, nil
+}
+
+type iMFAttributes struct {
+	vtbl *iMFAttributes_Vtbl
+}
+
+type iMFAttributes_Vtbl struct {
+	QueryInterface uintptr
+	AddRef         uintptr
+	Release        uintptr
+
+	GetItem            uintptr
+	GetItemType        uintptr
+	CompareItem        uintptr
+	Compare            uintptr
+	GetUINT32          uintptr
+	GetUINT64          uintptr
+	GetDouble          uintptr
+	GetGUID            uintptr
+	GetStringLength    uintptr
+	GetString          uintptr
+	GetAllocatedString uintptr
+	GetBlobSize        uintptr
+	GetBlob            uintptr
+	GetAllocatedBlob   uintptr
+	GetUnknown         uintptr
+	SetItem            uintptr
+	DeleteItem         uintptr
+	DeleteAllItems     uintptr
+	SetUINT32          uintptr
+	SetUINT64          uintptr
+	SetDouble          uintptr
+	SetGUID            uintptr
+	SetString          uintptr
+	SetBlob            uintptr
+	SetUnknown         uintptr
+	LockStore          uintptr
+	UnlockStore        uintptr
+	GetCount           uintptr
+	GetItemByIndex     uintptr
+	CopyAllItems       uintptr
+}
+
+func (i *iMFAttributes) Release() uint32 {
+	r, _, _ := syscall.Syscall(i.vtbl.Release, 1, uintptr(unsafe.Pointer(i)), 0, 0)
+	return uint32(r)
+}
+
+func (i *iMFAttributes) GetUINT32(key *windows.GUID) (uint32, error) {
+	var v uint32
+	r, _, _ := syscall.Syscall(i.vtbl.GetUINT32, 3, uintptr(unsafe.Pointer(i)), uintptr(unsafe.Pointer(key)), uintptr(unsafe.Pointer(&v)))
+	if failed(r) {
+		return 0, fmt.Errorf("camera: IMFAttributes::GetUINT32 failed: %w", hresultError(r))
+	}
+	return v, nil
+}
+
+func (i *iMFAttributes) GetUINT64(key *windows.GUID) (uint64, error) {
+	var v uint64
+	r, _, _ := syscall.Syscall(i.vtbl.GetUINT64, 3, uintptr(unsafe.Pointer(i)), uintptr(unsafe.Pointer(key)), uintptr(unsafe.Pointer(&v)))
+	if failed(r) {
+		return 0, fmt.Errorf("camera: IMFAttributes::GetUINT64 failed: %w", hresultError(r))
+	}
+	return v, nil
+}
+
+// GetAllocatedString returns the string value of the key.
+func (i *iMFAttributes) GetAllocatedString(key *windows.GUID) (string, error) {
+	var p *uint16
+	var l uint32
+	r, _, _ := syscall.Syscall6(i.vtbl.GetAllocatedString, 4, uintptr(unsafe.Pointer(i)), uintptr(unsafe.Pointer(key)), uintptr(unsafe.Pointer(&p)), uintptr(unsafe.Pointer(&l)), 0, 0)
+	if failed(r) {
+		return "", fmt.Errorf("camera: IMFAttributes::GetAllocatedString failed: %w", hresultError(r))
+	}
+	defer windows.CoTaskMemFree(unsafe.Pointer(p))
+	return windows.UTF16PtrToString(p), nil
+}
+
+func (i *iMFAttributes) SetUINT32(key *windows.GUID, value uint32) error {
+	r, _, _ := syscall.Syscall(i.vtbl.SetUINT32, 3, uintptr(unsafe.Pointer(i)), uintptr(unsafe.Pointer(key)), uintptr(value))
+	if failed(r) {
+		return fmt.Errorf("camera: IMFAttributes::SetUINT32 failed: %w", hresultError(r))
+	}
+	return nil
+}
+
+func (i *iMFAttributes) SetUINT64(key *windows.GUID, value uint64) error {
+	var r uintptr
+	if unsafe.Sizeof(uintptr(0)) == 4 {
+		// A 64-bit value is passed as two 32-bit values on 32-bit machines.
+		r, _, _ = syscall.Syscall6(i.vtbl.SetUINT64, 4, uintptr(unsafe.Pointer(i)), uintptr(unsafe.Pointer(key)), uintptr(uint32(value)), uintptr(uint32(value>>32)), 0, 0)
+	} else {
+		r, _, _ = syscall.Syscall(i.vtbl.SetUINT64, 3, uintptr(unsafe.Pointer(i)), uintptr(unsafe.Pointer(key)), uintptr(value))
+	}
+	if failed(r) {
+		return fmt.Errorf("camera: IMFAttributes::SetUINT64 failed: %w", hresultError(r))
+	}
+	return nil
+}
+
+func (i *iMFAttributes) SetGUID(key *windows.GUID, value *windows.GUID) error {
+	r, _, _ := syscall.Syscall(i.vtbl.SetGUID, 3, uintptr(unsafe.Pointer(i)), uintptr(unsafe.Pointer(key)), uintptr(unsafe.Pointer(value)))
+	if failed(r) {
+		return fmt.Errorf("camera: IMFAttributes::SetGUID failed: %w", hresultError(r))
+	}
+	return nil
+}
+
+// iMFActivate is IMFActivate, which inherits IMFAttributes.
+type iMFActivate struct {
+	vtbl *iMFActivate_Vtbl
+}
+
+type iMFActivate_Vtbl struct {
+	iMFAttributes_Vtbl
+
+	ActivateObject uintptr
+	ShutdownObject uintptr
+	DetachObject   uintptr
+}
+
+func (i *iMFActivate) attributes() *iMFAttributes {
+	return (*iMFAttributes)(unsafe.Pointer(i))
+}
+
+func (i *iMFActivate) Release() uint32 {
+	return i.attributes().Release()
+}
+
+func (i *iMFActivate) ActivateObject(riid *windows.GUID) (unsafe.Pointer, error) {
+	var obj unsafe.Pointer
+	r, _, _ := syscall.Syscall(i.vtbl.ActivateObject, 3, uintptr(unsafe.Pointer(i)), uintptr(unsafe.Pointer(riid)), uintptr(unsafe.Pointer(&obj)))
+	if failed(r) {
+		return nil, fmt.Errorf("camera: IMFActivate::ActivateObject failed: %w", hresultError(r))
+	}
+	return obj, nil
+}
+
+type iMFMediaSource struct {
+	vtbl *iMFMediaSource_Vtbl
+}
+
+type iMFMediaSource_Vtbl struct {
+	QueryInterface uintptr
+	AddRef         uintptr
+	Release        uintptr
+
+	GetEvent      uintptr
+	BeginGetEvent uintptr
+	EndGetEvent   uintptr
+	QueueEvent    uintptr
+
+	GetCharacteristics           uintptr
+	CreatePresentationDescriptor uintptr
+	Start                        uintptr
+	Stop                         uintptr
+	Pause                        uintptr
+	Shutdown                     uintptr
+}
+
+func (i *iMFMediaSource) Release() uint32 {
+	r, _, _ := syscall.Syscall(i.vtbl.Release, 1, uintptr(unsafe.Pointer(i)), 0, 0)
+	return uint32(r)
+}
+
+func (i *iMFMediaSource) Shutdown() {
+	syscall.Syscall(i.vtbl.Shutdown, 1, uintptr(unsafe.Pointer(i)), 0, 0)
+}
+
+type iMFSourceReader struct {
+	vtbl *iMFSourceReader_Vtbl
+}
+
+type iMFSourceReader_Vtbl struct {
+	QueryInterface uintptr
+	AddRef         uintptr
+	Release        uintptr
+
+	GetStreamSelection       uintptr
+	SetStreamSelection       uintptr
+	GetNativeMediaType       uintptr
+	GetCurrentMediaType      uintptr
+	SetCurrentMediaType      uintptr
+	SetCurrentPosition       uintptr
+	ReadSample               uintptr
+	Flush                    uintptr
+	GetServiceForStream      uintptr
+	GetPresentationAttribute uintptr
+}
+
+func (i *iMFSourceReader) Release() uint32 {
+	r, _, _ := syscall.Syscall(i.vtbl.Release, 1, uintptr(unsafe.Pointer(i)), 0, 0)
+	return uint32(r)
+}
+
+func (i *iMFSourceReader) GetCurrentMediaType(streamIndex uint32) (*iMFAttributes, error) {
+	var t *iMFAttributes
+	r, _, _ := syscall.Syscall(i.vtbl.GetCurrentMediaType, 3, uintptr(unsafe.Pointer(i)), uintptr(streamIndex), uintptr(unsafe.Pointer(&t)))
+	if failed(r) {
+		return nil, fmt.Errorf("camera: IMFSourceReader::GetCurrentMediaType failed: %w", hresultError(r))
+	}
+	return t, nil
+}
+
+func (i *iMFSourceReader) SetCurrentMediaType(streamIndex uint32, mediaType *iMFAttributes) error {
+	r, _, _ := syscall.Syscall6(i.vtbl.SetCurrentMediaType, 4, uintptr(unsafe.Pointer(i)), uintptr(streamIndex), 0, uintptr(unsafe.Pointer(mediaType)), 0, 0)
+	if failed(r) {
+		return fmt.Errorf("camera: IMFSourceReader::SetCurrentMediaType failed: %w", hresultError(r))
+	}
+	return nil
+}
+
+func (i *iMFSourceReader) ReadSample(streamIndex uint32) (flags uint32, sample *iMFSample, err error) {
+	var actualStreamIndex uint32
+	var timestamp int64
+	r, _, _ := syscall.Syscall9(i.vtbl.ReadSample, 7, uintptr(unsafe.Pointer(i)), uintptr(streamIndex), 0,
+		uintptr(unsafe.Pointer(&actualStreamIndex)), uintptr(unsafe.Pointer(&flags)), uintptr(unsafe.Pointer(&timestamp)), uintptr(unsafe.Pointer(&sample)),
+		0, 0)
+	if failed(r) {
+		return 0, nil, fmt.Errorf("camera: IMFSourceReader::ReadSample failed: %w", hresultError(r))
+	}
+	return flags, sample, nil
+}
+
+// iMFSample is IMFSample, which inherits IMFAttributes.
+type iMFSample struct {
+	vtbl *iMFSample_Vtbl
+}
+
+type iMFSample_Vtbl struct {
+	iMFAttributes_Vtbl
+
+	GetSampleFlags            uintptr
+	SetSampleFlags            uintptr
+	GetSampleTime             uintptr
+	SetSampleTime             uintptr
+	GetSampleDuration         uintptr
+	SetSampleDuration         uintptr
+	GetBufferCount            uintptr
+	GetBufferByIndex          uintptr
+	ConvertToContiguousBuffer uintptr
+	AddBuffer                 uintptr
+	RemoveBufferByIndex       uintptr
+	RemoveAllBuffers          uintptr
+	GetTotalLength            uintptr
+	CopyToBuffer              uintptr
+}
+
+func (i *iMFSample) Release() uint32 {
+	r, _, _ := syscall.Syscall(i.vtbl.Release, 1, uintptr(unsafe.Pointer(i)), 0, 0)
+	return uint32(r)
+}
+
+func (i *iMFSample) ConvertToContiguousBuffer() (*iMFMediaBuffer, error) {
+	var b *iMFMediaBuffer
+	r, _, _ := syscall.Syscall(i.vtbl.ConvertToContiguousBuffer, 2, uintptr(unsafe.Pointer(i)), uintptr(unsafe.Pointer(&b)), 0)
+	if failed(r) {
+		return nil, fmt.Errorf("camera: IMFSample::ConvertToContiguousBuffer failed: %w", hresultError(r))
+	}
+	return b, nil
+}
+
+type iMFMediaBuffer struct {
+	vtbl *iMFMediaBuffer_Vtbl
+}
+
+type iMFMediaBuffer_Vtbl struct {
+	QueryInterface uintptr
+	AddRef         uintptr
+	Release        uintptr
+
+	Lock             uintptr
+	Unlock           uintptr
+	GetCurrentLength uintptr
+	SetCurrentLength uintptr
+	GetMaxLength     uintptr
+}
+
+func (i *iMFMediaBuffer) Release() uint32 {
+	r, _, _ := syscall.Syscall(i.vtbl.Release, 1, uintptr(unsafe.Pointer(i)), 0, 0)
+	return uint32(r)
+}
+
+// Lock returns the pointer to the buffer and the length of the valid data in bytes.
+func (i *iMFMediaBuffer) Lock() (unsafe.Pointer, uint32, error) {
+	var ptr unsafe.Pointer
+	var maxLength, currentLength uint32
+	r, _, _ := syscall.Syscall6(i.vtbl.Lock, 4, uintptr(unsafe.Pointer(i)), uintptr(unsafe.Pointer(&ptr)), uintptr(unsafe.Pointer(&maxLength)), uintptr(unsafe.Pointer(&currentLength)), 0, 0)
+	if failed(r) {
+		return nil, 0, fmt.Errorf("camera: IMFMediaBuffer::Lock failed: %w", hresultError(r))
+	}
+	return ptr, currentLength, nil
+}
+
+func (i *iMFMediaBuffer) Unlock() {
+	syscall.Syscall(i.vtbl.Unlock, 1, uintptr(unsafe.Pointer(i)), 0, 0)
+}
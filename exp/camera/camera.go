@@ -0,0 +1,291 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package camera offers video frames captured from cameras, e.g., webcams, as images.
+//
+// Cameras are available on Windows (Media Foundation), macOS and iOS (AVFoundation), Linux (V4L2), and browsers
+// supporting getUserMedia. On the other environments, no camera is available.
+//
+// The captured frames are uploaded to the images with ReplacePixels.
+//
+// On macOS, iOS and browsers, using a camera might require a permission from the user.
+// On macOS and iOS, NSCameraUsageDescription must be specified in Info.plist.
+//
+// This package is experimental and the API might be changed in the future.
+package camera
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Device represents a camera device.
+type Device struct {
+	// ID is an identifier of the device to open. The format of ID depends on the environment.
+	ID string
+
+	// Name is the human-readable name of the device.
+	Name string
+}
+
+// AppendDevices appends the available camera devices to devices, and returns the extended buffer.
+//
+// In browsers, the device names might be empty until a permission is granted.
+//
+// AppendDevices is concurrent-safe.
+func AppendDevices(devices []Device) ([]Device, error) {
+	return appendDevices(devices)
+}
+
+// Options represents options to open a camera.
+type Options struct {
+	// DeviceID is the ID of the device to open.
+	//
+	// If DeviceID is empty, the default device is used.
+	DeviceID string
+
+	// Width and Height are the preferred size of the frames.
+	// The actual size might be different from the specified size.
+	//
+	// If Width or Height is 0, the default size of the device is used.
+	Width  int
+	Height int
+}
+
+// ErrNoDevice is returned by Open when no camera is available.
+var ErrNoDevice = errors.New("camera: no camera device is available")
+
+// Camera represents a camera capturing frames.
+type Camera struct {
+	driver driver
+
+	image *ebiten.Image
+
+	// frame is the latest frame in RGBA, not uploaded to the image yet.
+	frame  []byte
+	width  int
+	height int
+
+	// spareFrame is a buffer already uploaded to the image, reused for a following frame.
+	spareFrame []byte
+
+	err    error
+	closed bool
+
+	m sync.Mutex
+}
+
+// driver represents a platform-specific capturing session.
+type driver interface {
+	close() error
+}
+
+// Open opens a camera and starts capturing.
+//
+// Open returns ErrNoDevice when no camera is available.
+//
+// Open is concurrent-safe.
+func Open(options *Options) (*Camera, error) {
+	if options == nil {
+		options = &Options{}
+	}
+	c := &Camera{}
+	d, err := openDriver(c, options)
+	if err != nil {
+		return nil, err
+	}
+	c.driver = d
+	return c, nil
+}
+
+// Image returns the image of the latest captured frame.
+//
+// The returned image is updated by the following calls of Image when new frames are captured.
+// The image might be a different one when the frame size is changed.
+// Do not dispose the returned image.
+//
+// Image returns nil when no frame is captured yet.
+//
+// Image must be called from Update or Draw of ebiten.Game.
+func (c *Camera) Image() *ebiten.Image {
+	c.m.Lock()
+	frame, w, h := c.frame, c.width, c.height
+	c.frame = nil
+	c.m.Unlock()
+
+	if frame == nil {
+		return c.image
+	}
+
+	if c.image != nil {
+		if iw, ih := c.image.Size(); iw != w || ih != h {
+			c.image.Dispose()
+			c.image = nil
+		}
+	}
+	if c.image == nil {
+		c.image = ebiten.NewImage(w, h)
+	}
+	c.image.ReplacePixels(frame)
+
+	// ReplacePixels doesn't retain the pixels, so the buffer can be reused for the next frames.
+	c.m.Lock()
+	c.spareFrame = frame
+	c.m.Unlock()
+
+	return c.image
+}
+
+// Size returns the size of the latest captured frame.
+//
+// Size returns 0s when no frame is captured yet.
+//
+// Size is concurrent-safe.
+func (c *Camera) Size() (width, height int) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.width, c.height
+}
+
+// Err returns an error that happened during capturing.
+//
+// After an error happens, no more frames are captured.
+//
+// Err is concurrent-safe.
+func (c *Camera) Err() error {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.err
+}
+
+// Close stops capturing and releases the device.
+//
+// The image returned by Image is still available after Close.
+//
+// Close is concurrent-safe.
+func (c *Camera) Close() error {
+	c.m.Lock()
+	if c.closed {
+		c.m.Unlock()
+		return nil
+	}
+	c.closed = true
+	c.m.Unlock()
+	return c.driver.close()
+}
+
+// nextFrame returns a buffer to write the next frame of the given size in RGBA.
+// The buffer must be passed to pushFrame after being filled.
+func (c *Camera) nextFrame(width, height int) []byte {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	// Reuse the latest frame if it is not consumed yet.
+	if f := c.frame; f != nil && len(f) == 4*width*height {
+		c.frame = nil
+		return f
+	}
+	if f := c.spareFrame; f != nil {
+		c.spareFrame = nil
+		if len(f) == 4*width*height {
+			return f
+		}
+	}
+	return make([]byte, 4*width*height)
+}
+
+// pushFrame sets the latest frame in RGBA.
+func (c *Camera) pushFrame(frame []byte, width, height int) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if c.closed {
+		return
+	}
+	c.frame = frame
+	c.width = width
+	c.height = height
+}
+
+// setError sets an error happened during capturing.
+func (c *Camera) setError(err error) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if c.err == nil {
+		c.err = err
+	}
+}
+
+func clamp8(v int) byte {
+	if v < 0 {
+		return 0
+	}
+	if v > 255 {
+		return 255
+	}
+	return byte(v)
+}
+
+// yuvToRGB converts a BT.601 limited-range YUV color to RGB.
+func yuvToRGB(y, u, v byte) (byte, byte, byte) {
+	c := 298 * (int(y) - 16)
+	d := int(u) - 128
+	e := int(v) - 128
+	r := clamp8((c + 409*e + 128) >> 8)
+	g := clamp8((c - 100*d - 208*e + 128) >> 8)
+	b := clamp8((c + 516*d + 128) >> 8)
+	return r, g, b
+}
+
+// convertYUYV converts YUYV (YUY2) pixels to opaque RGBA pixels.
+func convertYUYV(dst []byte, src []byte, width, height, stride int) {
+	for j := 0; j < height; j++ {
+		s := src[j*stride:]
+		d := dst[4*j*width:]
+		for i := 0; i+1 < width; i += 2 {
+			y0, u, y1, v := s[2*i], s[2*i+1], s[2*i+2], s[2*i+3]
+			d[4*i], d[4*i+1], d[4*i+2] = yuvToRGB(y0, u, v)
+			d[4*i+3] = 0xff
+			d[4*i+4], d[4*i+5], d[4*i+6] = yuvToRGB(y1, u, v)
+			d[4*i+7] = 0xff
+		}
+	}
+}
+
+// convertBGRX converts BGRX or BGRA pixels to opaque RGBA pixels.
+// If stride is negative, the rows in src are bottom-up.
+func convertBGRX(dst []byte, src []byte, width, height, stride int) {
+	for j := 0; j < height; j++ {
+		row := j
+		if stride < 0 {
+			row = height - 1 - j
+		}
+		s := src[row*abs(stride):]
+		d := dst[4*j*width:]
+		for i := 0; i < width; i++ {
+			d[4*i] = s[4*i+2]
+			d[4*i+1] = s[4*i+1]
+			d[4*i+2] = s[4*i]
+			d[4*i+3] = 0xff
+		}
+	}
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,342 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !ebitencbackend
+// +build !ebitencbackend
+
+package camera
+
+// #cgo CFLAGS: -x objective-c
+// #cgo LDFLAGS: -framework AVFoundation -framework CoreMedia -framework CoreVideo -framework Foundation
+//
+// #import <AVFoundation/AVFoundation.h>
+// #include <stdlib.h>
+// #include <string.h>
+//
+// @interface EbitenCameraDelegate : NSObject<AVCaptureVideoDataOutputSampleBufferDelegate>
+// @end
+//
+// @implementation EbitenCameraDelegate {
+//   CVPixelBufferRef latest_;
+//   dispatch_semaphore_t semaphore_;
+// }
+//
+// - (id)init {
+//   self = [super init];
+//   if (self) {
+//     semaphore_ = dispatch_semaphore_create(0);
+//   }
+//   return self;
+// }
+//
+// - (void)dealloc {
+//   if (latest_) {
+//     CVPixelBufferRelease(latest_);
+//   }
+//   dispatch_release(semaphore_);
+//   [super dealloc];
+// }
+//
+// - (void)captureOutput:(AVCaptureOutput*)output
+//   didOutputSampleBuffer:(CMSampleBufferRef)sampleBuffer
+//          fromConnection:(AVCaptureConnection*)connection {
+//   CVPixelBufferRef buffer = CMSampleBufferGetImageBuffer(sampleBuffer);
+//   if (!buffer) {
+//     return;
+//   }
+//   CVPixelBufferRetain(buffer);
+//   @synchronized(self) {
+//     // Drop the previous frame if it is not taken yet.
+//     if (latest_) {
+//       CVPixelBufferRelease(latest_);
+//     } else {
+//       dispatch_semaphore_signal(semaphore_);
+//     }
+//     latest_ = buffer;
+//   }
+// }
+//
+// // takeFrame waits for a frame and returns it with the ownership. takeFrame returns NULL at timeout.
+// - (CVPixelBufferRef)takeFrame:(int64_t)timeoutInMilliseconds {
+//   if (dispatch_semaphore_wait(semaphore_, dispatch_time(DISPATCH_TIME_NOW, timeoutInMilliseconds * NSEC_PER_MSEC))) {
+//     return NULL;
+//   }
+//   @synchronized(self) {
+//     CVPixelBufferRef buffer = latest_;
+//     latest_ = NULL;
+//     return buffer;
+//   }
+// }
+// @end
+//
+// typedef struct {
+//   AVCaptureSession* session;
+//   EbitenCameraDelegate* delegate;
+//   dispatch_queue_t queue;
+// } EbitenCameraSession;
+//
+// static NSArray* videoDevices(void) {
+// #pragma clang diagnostic push
+// #pragma clang diagnostic ignored "-Wdeprecated-declarations"
+//   return [AVCaptureDevice devicesWithMediaType:AVMediaTypeVideo];
+// #pragma clang diagnostic pop
+// }
+//
+// // copyDevices returns the number of the video devices, and allocates their IDs and names.
+// // The caller must free the strings and the arrays.
+// static int copyDevices(char*** ids, char*** names) {
+//   @autoreleasepool {
+//     NSArray* devices = videoDevices();
+//     int n = (int)[devices count];
+//     *ids = malloc(sizeof(char*) * (n > 0 ? n : 1));
+//     *names = malloc(sizeof(char*) * (n > 0 ? n : 1));
+//     for (int i = 0; i < n; i++) {
+//       AVCaptureDevice* device = [devices objectAtIndex:i];
+//       (*ids)[i] = strdup([[device uniqueID] UTF8String]);
+//       (*names)[i] = strdup([[device localizedName] UTF8String]);
+//     }
+//     return n;
+//   }
+// }
+//
+// // requestAccess returns 1 if the access to cameras is authorized.
+// static int requestAccess(void) {
+//   if (@available(macOS 10.14, iOS 7.0, *)) {
+//     switch ([AVCaptureDevice authorizationStatusForMediaType:AVMediaTypeVideo]) {
+//     case AVAuthorizationStatusAuthorized:
+//       return 1;
+//     case AVAuthorizationStatusNotDetermined: {
+//       __block int granted = 0;
+//       dispatch_semaphore_t s = dispatch_semaphore_create(0);
+//       [AVCaptureDevice requestAccessForMediaType:AVMediaTypeVideo completionHandler:^(BOOL g) {
+//         granted = g ? 1 : 0;
+//         dispatch_semaphore_signal(s);
+//       }];
+//       dispatch_semaphore_wait(s, DISPATCH_TIME_FOREVER);
+//       dispatch_release(s);
+//       return granted;
+//     }
+//     default:
+//       return 0;
+//     }
+//   }
+//   return 1;
+// }
+//
+// // openSession opens a capturing session. An ID can be NULL to use the default device.
+// // openSession returns 0 for success, 1 when no device is found, or 2 when the session cannot be created.
+// static int openSession(const char* id, int width, int height, EbitenCameraSession* out) {
+//   @autoreleasepool {
+//     AVCaptureDevice* device = nil;
+//     if (id) {
+//       device = [AVCaptureDevice deviceWithUniqueID:[NSString stringWithUTF8String:id]];
+//     } else {
+//       device = [AVCaptureDevice defaultDeviceWithMediaType:AVMediaTypeVideo];
+//     }
+//     if (!device) {
+//       return 1;
+//     }
+//
+//     NSError* error = nil;
+//     AVCaptureDeviceInput* input = [AVCaptureDeviceInput deviceInputWithDevice:device error:&error];
+//     if (!input) {
+//       return 2;
+//     }
+//
+//     AVCaptureSession* session = [[AVCaptureSession alloc] init];
+//     if (![session canAddInput:input]) {
+//       [session release];
+//       return 2;
+//     }
+//     [session addInput:input];
+//
+//     AVCaptureVideoDataOutput* output = [[[AVCaptureVideoDataOutput alloc] init] autorelease];
+//     NSMutableDictionary* settings = [NSMutableDictionary dictionary];
+//     [settings setObject:[NSNumber numberWithUnsignedInt:kCVPixelFormatType_32BGRA]
+//                  forKey:(id)kCVPixelBufferPixelFormatTypeKey];
+// #if TARGET_OS_OSX
+//     // Scaling by the output settings is available only on macOS.
+//     if (width > 0 && height > 0) {
+//       [settings setObject:[NSNumber numberWithInt:width] forKey:(id)kCVPixelBufferWidthKey];
+//       [settings setObject:[NSNumber numberWithInt:height] forKey:(id)kCVPixelBufferHeightKey];
+//     }
+// #endif
+//     output.videoSettings = settings;
+//     output.alwaysDiscardsLateVideoFrames = YES;
+//
+//     EbitenCameraDelegate* delegate = [[EbitenCameraDelegate alloc] init];
+//     dispatch_queue_t queue = dispatch_queue_create("org.ebitengine.camera", DISPATCH_QUEUE_SERIAL);
+//     [output setSampleBufferDelegate:delegate queue:queue];
+//     if (![session canAddOutput:output]) {
+//       [delegate release];
+//       dispatch_release(queue);
+//       [session release];
+//       return 2;
+//     }
+//     [session addOutput:output];
+//     [session startRunning];
+//
+//     out->session = session;
+//     out->delegate = delegate;
+//     out->queue = queue;
+//     return 0;
+//   }
+// }
+//
+// static void closeSession(EbitenCameraSession* s) {
+//   @autoreleasepool {
+//     [s->session stopRunning];
+//     [s->session release];
+//     // Wait for the delegate calls in the queue.
+//     dispatch_sync(s->queue, ^{});
+//     dispatch_release(s->queue);
+//     [s->delegate release];
+//   }
+// }
+//
+// // takeFrame waits for a frame and returns it. takeFrame returns NULL at timeout.
+// // The returned frame must be released by releaseFrame.
+// static void* takeFrame(EbitenCameraSession* s, int64_t timeoutInMilliseconds) {
+//   return [s->delegate takeFrame:timeoutInMilliseconds];
+// }
+//
+// // lockFrame locks the pixels of the frame in BGRA and returns 1 for success.
+// static int lockFrame(void* frame, int* width, int* height, int* stride, void** pixels) {
+//   CVPixelBufferRef buffer = (CVPixelBufferRef)frame;
+//   if (CVPixelBufferLockBaseAddress(buffer, kCVPixelBufferLock_ReadOnly) != kCVReturnSuccess) {
+//     return 0;
+//   }
+//   *width = (int)CVPixelBufferGetWidth(buffer);
+//   *height = (int)CVPixelBufferGetHeight(buffer);
+//   *stride = (int)CVPixelBufferGetBytesPerRow(buffer);
+//   *pixels = CVPixelBufferGetBaseAddress(buffer);
+//   return 1;
+// }
+//
+// static void unlockFrame(void* frame) {
+//   CVPixelBufferUnlockBaseAddress((CVPixelBufferRef)frame, kCVPixelBufferLock_ReadOnly);
+// }
+//
+// static void releaseFrame(void* frame) {
+//   CVPixelBufferRelease((CVPixelBufferRef)frame);
+// }
+import "C"
+
+import (
+	"errors"
+	"sync"
+	"unsafe"
+)
+
+func appendDevices(devices []Device) ([]Device, error) {
+	var ids, names **C.char
+	n := int(C.copyDevices(&ids, &names))
+	defer C.free(unsafe.Pointer(ids))
+	defer C.free(unsafe.Pointer(names))
+
+	if n == 0 {
+		return devices, nil
+	}
+	cids := (*[1 << 30]*C.char)(unsafe.Pointer(ids))[:n:n]
+	cnames := (*[1 << 30]*C.char)(unsafe.Pointer(names))[:n:n]
+	for i := 0; i < n; i++ {
+		devices = append(devices, Device{
+			ID:   C.GoString(cids[i]),
+			Name: C.GoString(cnames[i]),
+		})
+		C.free(unsafe.Pointer(cids[i]))
+		C.free(unsafe.Pointer(cnames[i]))
+	}
+	return devices, nil
+}
+
+type avfDriver struct {
+	session C.EbitenCameraSession
+	done    chan struct{}
+	wg      sync.WaitGroup
+}
+
+func openDriver(c *Camera, options *Options) (driver, error) {
+	if C.requestAccess() == 0 {
+		return nil, errors.New("camera: the access to cameras is not authorized")
+	}
+
+	var id *C.char
+	if options.DeviceID != "" {
+		id = C.CString(options.DeviceID)
+		defer C.free(unsafe.Pointer(id))
+	}
+
+	d := &avfDriver{
+		done: make(chan struct{}),
+	}
+	switch C.openSession(id, C.int(options.Width), C.int(options.Height), &d.session) {
+	case 0:
+	case 1:
+		return nil, ErrNoDevice
+	default:
+		return nil, errors.New("camera: creating a capturing session failed")
+	}
+
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		d.loop(c)
+	}()
+	return d, nil
+}
+
+func (d *avfDriver) loop(c *Camera) {
+	for {
+		select {
+		case <-d.done:
+			return
+		default:
+		}
+
+		frame := C.takeFrame(&d.session, 100)
+		if frame == nil {
+			continue
+		}
+		readFrame(c, frame)
+		C.releaseFrame(frame)
+	}
+}
+
+func readFrame(c *Camera, frame unsafe.Pointer) {
+	var width, height, stride C.int
+	var pixels unsafe.Pointer
+	if C.lockFrame(frame, &width, &height, &stride, &pixels) == 0 {
+		return
+	}
+	defer C.unlockFrame(frame)
+
+	if pixels == nil || width == 0 || height == 0 {
+		return
+	}
+
+	w, h := int(width), int(height)
+	l := int(stride) * h
+	src := (*[1 << 30]byte)(pixels)[:l:l]
+	dst := c.nextFrame(w, h)
+	convertBGRX(dst, src, w, h, int(stride))
+	c.pushFrame(dst, w, h)
+}
+
+func (d *avfDriver) close() error {
+	close(d.done)
+	d.wg.Wait()
+	C.closeSession(&d.session)
+	return nil
+}
@@ -0,0 +1,199 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package camera
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"syscall/js"
+	"time"
+)
+
+// await waits for the promise and returns the result.
+// await must not be called from JavaScript callbacks.
+func await(promise js.Value) (js.Value, error) {
+	type result struct {
+		value js.Value
+		err   error
+	}
+	ch := make(chan result, 1)
+	then := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		ch <- result{value: args[0]}
+		return nil
+	})
+	defer then.Release()
+	catch := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		ch <- result{err: errors.New(args[0].Call("toString").String())}
+		return nil
+	})
+	defer catch.Release()
+	promise.Call("then", then).Call("catch", catch)
+	r := <-ch
+	return r.value, r.err
+}
+
+func mediaDevices() js.Value {
+	navigator := js.Global().Get("navigator")
+	if !navigator.Truthy() {
+		return js.Undefined()
+	}
+	return navigator.Get("mediaDevices")
+}
+
+func appendDevices(devices []Device) ([]Device, error) {
+	md := mediaDevices()
+	if !md.Truthy() || md.Get("enumerateDevices").Type() != js.TypeFunction {
+		return devices, nil
+	}
+	infos, err := await(md.Call("enumerateDevices"))
+	if err != nil {
+		return nil, fmt.Errorf("camera: enumerating devices failed: %w", err)
+	}
+	for i := 0; i < infos.Length(); i++ {
+		info := infos.Index(i)
+		if info.Get("kind").String() != "videoinput" {
+			continue
+		}
+		devices = append(devices, Device{
+			ID:   info.Get("deviceId").String(),
+			Name: info.Get("label").String(),
+		})
+	}
+	return devices, nil
+}
+
+type mediaStreamDriver struct {
+	stream js.Value
+	video  js.Value
+	done   chan struct{}
+	wg     sync.WaitGroup
+}
+
+func openDriver(c *Camera, options *Options) (driver, error) {
+	md := mediaDevices()
+	if !md.Truthy() || md.Get("getUserMedia").Type() != js.TypeFunction {
+		return nil, ErrNoDevice
+	}
+
+	video := map[string]interface{}{}
+	if options.DeviceID != "" {
+		video["deviceId"] = map[string]interface{}{"exact": options.DeviceID}
+	}
+	if options.Width > 0 {
+		video["width"] = map[string]interface{}{"ideal": options.Width}
+	}
+	if options.Height > 0 {
+		video["height"] = map[string]interface{}{"ideal": options.Height}
+	}
+	var constraints interface{} = true
+	if len(video) > 0 {
+		constraints = video
+	}
+	stream, err := await(md.Call("getUserMedia", map[string]interface{}{
+		"video": constraints,
+		"audio": false,
+	}))
+	if err != nil {
+		// NotFoundError is thrown when no device satisfies the constraints.
+		if strings.HasPrefix(err.Error(), "NotFoundError") {
+			return nil, ErrNoDevice
+		}
+		return nil, fmt.Errorf("camera: getUserMedia failed: %w", err)
+	}
+
+	document := js.Global().Get("document")
+	v := document.Call("createElement", "video")
+	v.Set("muted", true)
+	v.Set("playsInline", true)
+	v.Set("srcObject", stream)
+	if _, err := await(v.Call("play")); err != nil {
+		stopTracks(stream)
+		return nil, fmt.Errorf("camera: playing the video failed: %w", err)
+	}
+
+	d := &mediaStreamDriver{
+		stream: stream,
+		video:  v,
+		done:   make(chan struct{}),
+	}
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		d.loop(c)
+	}()
+	return d, nil
+}
+
+func (d *mediaStreamDriver) loop(c *Camera) {
+	canvas := js.Global().Get("document").Call("createElement", "canvas")
+	ctx := canvas.Call("getContext", "2d")
+	uint8Array := js.Global().Get("Uint8Array")
+
+	t := time.NewTicker(time.Second / 60)
+	defer t.Stop()
+
+	lastTime := -1.0
+	for {
+		select {
+		case <-d.done:
+			return
+		case <-t.C:
+		}
+
+		// HAVE_CURRENT_DATA is 2.
+		if d.video.Get("readyState").Int() < 2 {
+			continue
+		}
+		// Skip when the frame is not updated.
+		ct := d.video.Get("currentTime").Float()
+		if ct == lastTime {
+			continue
+		}
+		lastTime = ct
+
+		w := d.video.Get("videoWidth").Int()
+		h := d.video.Get("videoHeight").Int()
+		if w == 0 || h == 0 {
+			continue
+		}
+		if canvas.Get("width").Int() != w || canvas.Get("height").Int() != h {
+			canvas.Set("width", w)
+			canvas.Set("height", h)
+		}
+		ctx.Call("drawImage", d.video, 0, 0)
+		data := ctx.Call("getImageData", 0, 0, w, h).Get("data")
+
+		frame := c.nextFrame(w, h)
+		js.CopyBytesToGo(frame, uint8Array.New(data.Get("buffer"), data.Get("byteOffset"), data.Get("byteLength")))
+		c.pushFrame(frame, w, h)
+	}
+}
+
+func stopTracks(stream js.Value) {
+	tracks := stream.Call("getTracks")
+	for i := 0; i < tracks.Length(); i++ {
+		tracks.Index(i).Call("stop")
+	}
+}
+
+func (d *mediaStreamDriver) close() error {
+	close(d.done)
+	d.wg.Wait()
+	stopTracks(d.stream)
+	d.video.Set("srcObject", nil)
+	return nil
+}
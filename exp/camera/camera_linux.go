@@ -0,0 +1,412 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !android && !ebitencbackend
+// +build !android,!ebitencbackend
+
+package camera
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// The values and the types are taken from linux/videodev2.h.
+
+const (
+	_V4L2_BUF_TYPE_VIDEO_CAPTURE = 1
+
+	_V4L2_CAP_VIDEO_CAPTURE = 0x00000001
+	_V4L2_CAP_STREAMING     = 0x04000000
+	_V4L2_CAP_DEVICE_CAPS   = 0x80000000
+
+	_V4L2_FIELD_ANY = 0
+
+	_V4L2_MEMORY_MMAP = 1
+
+	_V4L2_PIX_FMT_YUYV  = 'Y' | 'U'<<8 | 'Y'<<16 | 'V'<<24
+	_V4L2_PIX_FMT_MJPEG = 'M' | 'J'<<8 | 'P'<<16 | 'G'<<24
+)
+
+type v4l2Capability struct {
+	driver       [16]byte
+	card         [32]byte
+	busInfo      [32]byte
+	version      uint32
+	capabilities uint32
+	deviceCaps   uint32
+	reserved     [3]uint32
+}
+
+type v4l2PixFormat struct {
+	width        uint32
+	height       uint32
+	pixelformat  uint32
+	field        uint32
+	bytesperline uint32
+	sizeimage    uint32
+	colorspace   uint32
+	priv         uint32
+	flags        uint32
+	ycbcrEnc     uint32
+	quantization uint32
+	xferFunc     uint32
+}
+
+type v4l2Format struct {
+	typ uint32
+	fmt struct {
+		// The union in v4l2_format includes pointers and is aligned as a pointer.
+		_   [0]uintptr
+		pix v4l2PixFormat
+		_   [200 - unsafe.Sizeof(v4l2PixFormat{})]byte
+	}
+}
+
+type v4l2RequestBuffers struct {
+	count    uint32
+	typ      uint32
+	memory   uint32
+	reserved [2]uint32
+}
+
+type v4l2Timecode struct {
+	typ      uint32
+	flags    uint32
+	frames   uint8
+	seconds  uint8
+	minutes  uint8
+	hours    uint8
+	userbits [4]uint8
+}
+
+type v4l2Buffer struct {
+	index     uint32
+	typ       uint32
+	bytesused uint32
+	flags     uint32
+	field     uint32
+	timestamp unix.Timeval
+	timecode  v4l2Timecode
+	sequence  uint32
+	memory    uint32
+	m         uintptr // The union of offset, userptr, planes and fd. offset is used with _V4L2_MEMORY_MMAP.
+	length    uint32
+	reserved2 uint32
+	requestFD uint32
+}
+
+func ioc(dir, nr, size uintptr) uintptr {
+	const (
+		nrShift   = 0
+		typeShift = 8
+		sizeShift = 16
+		dirShift  = 30
+	)
+	return dir<<dirShift | size<<sizeShift | 'V'<<typeShift | nr<<nrShift
+}
+
+const (
+	iocWrite = 1
+	iocRead  = 2
+)
+
+var (
+	_VIDIOC_QUERYCAP  = ioc(iocRead, 0, unsafe.Sizeof(v4l2Capability{}))
+	_VIDIOC_S_FMT     = ioc(iocRead|iocWrite, 5, unsafe.Sizeof(v4l2Format{}))
+	_VIDIOC_REQBUFS   = ioc(iocRead|iocWrite, 8, unsafe.Sizeof(v4l2RequestBuffers{}))
+	_VIDIOC_QUERYBUF  = ioc(iocRead|iocWrite, 9, unsafe.Sizeof(v4l2Buffer{}))
+	_VIDIOC_QBUF      = ioc(iocRead|iocWrite, 15, unsafe.Sizeof(v4l2Buffer{}))
+	_VIDIOC_DQBUF     = ioc(iocRead|iocWrite, 17, unsafe.Sizeof(v4l2Buffer{}))
+	_VIDIOC_STREAMON  = ioc(iocWrite, 18, unsafe.Sizeof(int32(0)))
+	_VIDIOC_STREAMOFF = ioc(iocWrite, 19, unsafe.Sizeof(int32(0)))
+)
+
+func ioctl(fd int, req uintptr, arg unsafe.Pointer) error {
+	for {
+		_, _, errno := unix.Syscall(unix.SYS_IOCTL, uintptr(fd), req, uintptr(arg))
+		if errno == unix.EINTR {
+			continue
+		}
+		if errno != 0 {
+			return errno
+		}
+		return nil
+	}
+}
+
+func cString(b []byte) string {
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		b = b[:i]
+	}
+	return string(b)
+}
+
+// queryCapture returns the name of the device if the device can capture video with streaming.
+func queryCapture(fd int) (string, bool) {
+	var cap v4l2Capability
+	if err := ioctl(fd, _VIDIOC_QUERYCAP, unsafe.Pointer(&cap)); err != nil {
+		return "", false
+	}
+	caps := cap.capabilities
+	if caps&_V4L2_CAP_DEVICE_CAPS != 0 {
+		caps = cap.deviceCaps
+	}
+	if caps&_V4L2_CAP_VIDEO_CAPTURE == 0 || caps&_V4L2_CAP_STREAMING == 0 {
+		// Metadata nodes of UVC cameras don't have the capture capability.
+		return "", false
+	}
+	return cString(cap.card[:]), true
+}
+
+func videoDevicePaths() []string {
+	paths, _ := filepath.Glob("/dev/video*")
+	sort.Slice(paths, func(i, j int) bool {
+		// Sort /dev/video2 before /dev/video10.
+		if len(paths[i]) != len(paths[j]) {
+			return len(paths[i]) < len(paths[j])
+		}
+		return paths[i] < paths[j]
+	})
+	return paths
+}
+
+func appendDevices(devices []Device) ([]Device, error) {
+	for _, path := range videoDevicePaths() {
+		fd, err := unix.Open(path, unix.O_RDWR|unix.O_CLOEXEC, 0)
+		if err != nil {
+			// The device might not be accessible due to the permission.
+			continue
+		}
+		name, ok := queryCapture(fd)
+		unix.Close(fd)
+		if !ok {
+			continue
+		}
+		devices = append(devices, Device{
+			ID:   path,
+			Name: strings.TrimSpace(name),
+		})
+	}
+	return devices, nil
+}
+
+type v4l2Driver struct {
+	fd      int
+	buffers [][]byte
+	done    chan struct{}
+	wg      sync.WaitGroup
+}
+
+func openDriver(c *Camera, options *Options) (driver, error) {
+	path := options.DeviceID
+	if path == "" {
+		ds, err := appendDevices(nil)
+		if err != nil {
+			return nil, err
+		}
+		if len(ds) == 0 {
+			return nil, ErrNoDevice
+		}
+		path = ds[0].ID
+	}
+
+	fd, err := unix.Open(path, unix.O_RDWR|unix.O_NONBLOCK|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, fmt.Errorf("camera: opening %s failed: %w", path, err)
+	}
+	d := &v4l2Driver{
+		fd:   fd,
+		done: make(chan struct{}),
+	}
+	f, err := d.init(options)
+	if err != nil {
+		d.release()
+		return nil, fmt.Errorf("camera: initializing %s failed: %w", path, err)
+	}
+
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		if err := d.loop(c, f); err != nil {
+			c.setError(err)
+		}
+	}()
+	return d, nil
+}
+
+func (d *v4l2Driver) init(options *Options) (v4l2PixFormat, error) {
+	if _, ok := queryCapture(d.fd); !ok {
+		return v4l2PixFormat{}, fmt.Errorf("the device cannot capture video")
+	}
+
+	// Prefer YUYV, which doesn't require decoding. Many webcams offer MJPEG for larger sizes.
+	var f v4l2Format
+	for _, pf := range []uint32{_V4L2_PIX_FMT_YUYV, _V4L2_PIX_FMT_MJPEG} {
+		f = v4l2Format{
+			typ: _V4L2_BUF_TYPE_VIDEO_CAPTURE,
+		}
+		f.fmt.pix.width = uint32(options.Width)
+		f.fmt.pix.height = uint32(options.Height)
+		if options.Width == 0 || options.Height == 0 {
+			f.fmt.pix.width = 640
+			f.fmt.pix.height = 480
+		}
+		f.fmt.pix.pixelformat = pf
+		f.fmt.pix.field = _V4L2_FIELD_ANY
+		if err := ioctl(d.fd, _VIDIOC_S_FMT, unsafe.Pointer(&f)); err != nil {
+			return v4l2PixFormat{}, fmt.Errorf("VIDIOC_S_FMT failed: %w", err)
+		}
+		// The driver might choose another format.
+		if f.fmt.pix.pixelformat == pf {
+			break
+		}
+	}
+	switch f.fmt.pix.pixelformat {
+	case _V4L2_PIX_FMT_YUYV, _V4L2_PIX_FMT_MJPEG:
+	default:
+		return v4l2PixFormat{}, fmt.Errorf("the pixel format 0x%08x is not supported", f.fmt.pix.pixelformat)
+	}
+
+	req := v4l2RequestBuffers{
+		count:  4,
+		typ:    _V4L2_BUF_TYPE_VIDEO_CAPTURE,
+		memory: _V4L2_MEMORY_MMAP,
+	}
+	if err := ioctl(d.fd, _VIDIOC_REQBUFS, unsafe.Pointer(&req)); err != nil {
+		return v4l2PixFormat{}, fmt.Errorf("VIDIOC_REQBUFS failed: %w", err)
+	}
+	for i := 0; i < int(req.count); i++ {
+		b := v4l2Buffer{
+			index:  uint32(i),
+			typ:    _V4L2_BUF_TYPE_VIDEO_CAPTURE,
+			memory: _V4L2_MEMORY_MMAP,
+		}
+		if err := ioctl(d.fd, _VIDIOC_QUERYBUF, unsafe.Pointer(&b)); err != nil {
+			return v4l2PixFormat{}, fmt.Errorf("VIDIOC_QUERYBUF failed: %w", err)
+		}
+		m, err := unix.Mmap(d.fd, int64(uint32(b.m)), int(b.length), unix.PROT_READ|unix.PROT_WRITE, unix.MAP_SHARED)
+		if err != nil {
+			return v4l2PixFormat{}, fmt.Errorf("mmap failed: %w", err)
+		}
+		d.buffers = append(d.buffers, m)
+		if err := ioctl(d.fd, _VIDIOC_QBUF, unsafe.Pointer(&b)); err != nil {
+			return v4l2PixFormat{}, fmt.Errorf("VIDIOC_QBUF failed: %w", err)
+		}
+	}
+
+	typ := int32(_V4L2_BUF_TYPE_VIDEO_CAPTURE)
+	if err := ioctl(d.fd, _VIDIOC_STREAMON, unsafe.Pointer(&typ)); err != nil {
+		return v4l2PixFormat{}, fmt.Errorf("VIDIOC_STREAMON failed: %w", err)
+	}
+	return f.fmt.pix, nil
+}
+
+func (d *v4l2Driver) loop(c *Camera, f v4l2PixFormat) error {
+	w, h := int(f.width), int(f.height)
+	stride := int(f.bytesperline)
+	if stride == 0 {
+		stride = 2 * w
+	}
+
+	for {
+		select {
+		case <-d.done:
+			return nil
+		default:
+		}
+
+		// Wait with a timeout so that close can stop the loop.
+		fds := []unix.PollFd{{Fd: int32(d.fd), Events: unix.POLLIN}}
+		n, err := unix.Poll(fds, 100)
+		if err != nil {
+			if err == unix.EINTR {
+				continue
+			}
+			return fmt.Errorf("camera: poll failed: %w", err)
+		}
+		if n == 0 {
+			continue
+		}
+
+		b := v4l2Buffer{
+			typ:    _V4L2_BUF_TYPE_VIDEO_CAPTURE,
+			memory: _V4L2_MEMORY_MMAP,
+		}
+		if err := ioctl(d.fd, _VIDIOC_DQBUF, unsafe.Pointer(&b)); err != nil {
+			if err == unix.EAGAIN {
+				continue
+			}
+			return fmt.Errorf("camera: VIDIOC_DQBUF failed: %w", err)
+		}
+
+		src := d.buffers[b.index][:b.bytesused]
+		switch f.pixelformat {
+		case _V4L2_PIX_FMT_YUYV:
+			if len(src) >= stride*h {
+				dst := c.nextFrame(w, h)
+				convertYUYV(dst, src, w, h, stride)
+				c.pushFrame(dst, w, h)
+			}
+		case _V4L2_PIX_FMT_MJPEG:
+			// Broken frames can be sent especially just after starting. Skip them.
+			if img, err := jpeg.Decode(bytes.NewReader(src)); err == nil {
+				bounds := img.Bounds()
+				dst := c.nextFrame(bounds.Dx(), bounds.Dy())
+				rgba := &image.RGBA{
+					Pix:    dst,
+					Stride: 4 * bounds.Dx(),
+					Rect:   image.Rect(0, 0, bounds.Dx(), bounds.Dy()),
+				}
+				draw.Draw(rgba, rgba.Rect, img, bounds.Min, draw.Src)
+				c.pushFrame(dst, bounds.Dx(), bounds.Dy())
+			}
+		}
+
+		if err := ioctl(d.fd, _VIDIOC_QBUF, unsafe.Pointer(&b)); err != nil {
+			return fmt.Errorf("camera: VIDIOC_QBUF failed: %w", err)
+		}
+	}
+}
+
+func (d *v4l2Driver) release() {
+	for _, b := range d.buffers {
+		unix.Munmap(b)
+	}
+	d.buffers = nil
+	unix.Close(d.fd)
+}
+
+func (d *v4l2Driver) close() error {
+	close(d.done)
+	d.wg.Wait()
+
+	typ := int32(_V4L2_BUF_TYPE_VIDEO_CAPTURE)
+	err := ioctl(d.fd, _VIDIOC_STREAMOFF, unsafe.Pointer(&typ))
+	d.release()
+	if err != nil {
+		return fmt.Errorf("camera: VIDIOC_STREAMOFF failed: %w", err)
+	}
+	return nil
+}
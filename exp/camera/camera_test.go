@@ -0,0 +1,136 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package camera
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestYUVToRGB(t *testing.T) {
+	tests := []struct {
+		name    string
+		y, u, v byte
+		r, g, b byte
+	}{
+		{name: "black", y: 16, u: 128, v: 128, r: 0, g: 0, b: 0},
+		{name: "white", y: 235, u: 128, v: 128, r: 255, g: 255, b: 255},
+		{name: "gray", y: 126, u: 128, v: 128, r: 128, g: 128, b: 128},
+		{name: "red", y: 81, u: 90, v: 240, r: 255, g: 0, b: 0},
+		{name: "green", y: 145, u: 54, v: 34, r: 0, g: 255, b: 0},
+		{name: "blue", y: 41, u: 240, v: 110, r: 0, g: 0, b: 255},
+		{name: "below the range", y: 0, u: 128, v: 128, r: 0, g: 0, b: 0},
+		{name: "above the range", y: 255, u: 128, v: 128, r: 255, g: 255, b: 255},
+	}
+	near := func(a, b byte) bool {
+		d := int(a) - int(b)
+		return -1 <= d && d <= 1
+	}
+	for _, tc := range tests {
+		r, g, b := yuvToRGB(tc.y, tc.u, tc.v)
+		if !near(r, tc.r) || !near(g, tc.g) || !near(b, tc.b) {
+			t.Errorf("%s: yuvToRGB(%d, %d, %d): got: (%d, %d, %d), want: (%d, %d, %d)", tc.name, tc.y, tc.u, tc.v, r, g, b, tc.r, tc.g, tc.b)
+		}
+	}
+}
+
+func TestConvertYUYV(t *testing.T) {
+	const w, h = 2, 2
+	// Each row has 2 bytes of padding.
+	const stride = 2*w + 2
+	src := []byte{
+		16, 128, 235, 128, 0xee, 0xee,
+		126, 128, 16, 128, 0xee, 0xee,
+	}
+	dst := make([]byte, 4*w*h)
+	convertYUYV(dst, src, w, h, stride)
+
+	want := []byte{
+		0, 0, 0, 0xff, 255, 255, 255, 0xff,
+		128, 128, 128, 0xff, 0, 0, 0, 0xff,
+	}
+	if !bytes.Equal(dst, want) {
+		t.Errorf("convertYUYV: got: %v, want: %v", dst, want)
+	}
+}
+
+func TestConvertBGRX(t *testing.T) {
+	const w, h = 2, 2
+	src := []byte{
+		1, 2, 3, 0, 4, 5, 6, 0,
+		7, 8, 9, 0, 10, 11, 12, 0,
+	}
+
+	dst := make([]byte, 4*w*h)
+	convertBGRX(dst, src, w, h, 4*w)
+	want := []byte{
+		3, 2, 1, 0xff, 6, 5, 4, 0xff,
+		9, 8, 7, 0xff, 12, 11, 10, 0xff,
+	}
+	if !bytes.Equal(dst, want) {
+		t.Errorf("convertBGRX: got: %v, want: %v", dst, want)
+	}
+
+	// A negative stride means bottom-up rows.
+	convertBGRX(dst, src, w, h, -4*w)
+	want = []byte{
+		9, 8, 7, 0xff, 12, 11, 10, 0xff,
+		3, 2, 1, 0xff, 6, 5, 4, 0xff,
+	}
+	if !bytes.Equal(dst, want) {
+		t.Errorf("convertBGRX with a negative stride: got: %v, want: %v", dst, want)
+	}
+}
+
+func TestNextFrameReusesBuffers(t *testing.T) {
+	c := &Camera{}
+
+	f0 := c.nextFrame(2, 2)
+	if got, want := len(f0), 16; got != want {
+		t.Fatalf("len(nextFrame(2, 2)): got: %d, want: %d", got, want)
+	}
+
+	// A frame not consumed yet is reused.
+	c.pushFrame(f0, 2, 2)
+	if f := c.nextFrame(2, 2); &f[0] != &f0[0] {
+		t.Errorf("nextFrame must reuse the frame not consumed yet")
+	}
+
+	// A spare frame is reused.
+	c.spareFrame = f0
+	if f := c.nextFrame(2, 2); &f[0] != &f0[0] {
+		t.Errorf("nextFrame must reuse the spare frame")
+	}
+	if c.spareFrame != nil {
+		t.Errorf("spareFrame must be taken by nextFrame")
+	}
+
+	// A spare frame of a different size is discarded.
+	c.spareFrame = f0
+	if f := c.nextFrame(4, 4); len(f) != 64 {
+		t.Errorf("len(nextFrame(4, 4)): got: %d, want: 64", len(f))
+	}
+	if c.spareFrame != nil {
+		t.Errorf("spareFrame of a different size must be discarded")
+	}
+}
+
+func TestPushFrameAfterClose(t *testing.T) {
+	c := &Camera{closed: true}
+	c.pushFrame(make([]byte, 4), 1, 1)
+	if w, h := c.Size(); w != 0 || h != 0 {
+		t.Errorf("Size after pushFrame on a closed camera: got: (%d, %d), want: (0, 0)", w, h)
+	}
+}
@@ -0,0 +1,335 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !ebitencbackend
+// +build !ebitencbackend
+
+package camera
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"syscall"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	mfStartupOnce sync.Once
+	mfStartupErr  error
+)
+
+// initializeMF initializes COM for the current thread, and Media Foundation once.
+// The current thread must be locked. CoUninitialize must be called when initializeMF succeeds.
+func initializeMF() error {
+	if err := windows.CoInitializeEx(0, windows.COINIT_MULTITHREADED); err != nil {
+		// S_FALSE means that COM is already initialized on the thread.
+		if e, ok := err.(syscall.Errno); !ok || e != 1 {
+			return fmt.Errorf("camera: CoInitializeEx failed: %w", err)
+		}
+	}
+	mfStartupOnce.Do(func() {
+		mfStartupErr = _MFStartup()
+	})
+	if mfStartupErr != nil {
+		windows.CoUninitialize()
+		return mfStartupErr
+	}
+	return nil
+}
+
+// enumVideoDevices calls f with the video capture devices.
+func enumVideoDevices(f func(activate *iMFActivate) bool) error {
+	attr, err := _MFCreateAttributes(1)
+	if err != nil {
+		return err
+	}
+	defer attr.Release()
+	if err := attr.SetGUID(&_MF_DEVSOURCE_ATTRIBUTE_SOURCE_TYPE, &_MF_DEVSOURCE_ATTRIBUTE_SOURCE_TYPE_VIDCAP_GUID); err != nil {
+		return err
+	}
+	activates, err := _MFEnumDeviceSources(attr)
+	if err != nil {
+		return err
+	}
+	defer freeActivates(activates)
+	for _, a := range activates {
+		if !f(a) {
+			break
+		}
+	}
+	return nil
+}
+
+func appendDevices(devices []Device) ([]Device, error) {
+	var err error
+	ch := make(chan struct{})
+	// Run on a dedicated thread to initialize COM without affecting the caller's thread.
+	go func() {
+		defer close(ch)
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+
+		if err = initializeMF(); err != nil {
+			return
+		}
+		defer windows.CoUninitialize()
+
+		err = enumVideoDevices(func(activate *iMFActivate) bool {
+			id, e := activate.attributes().GetAllocatedString(&_MF_DEVSOURCE_ATTRIBUTE_SOURCE_TYPE_VIDCAP_SYMBOLIC_LINK)
+			if e != nil {
+				return true
+			}
+			name, _ := activate.attributes().GetAllocatedString(&_MF_DEVSOURCE_ATTRIBUTE_FRIENDLY_NAME)
+			devices = append(devices, Device{
+				ID:   id,
+				Name: name,
+			})
+			return true
+		})
+	}()
+	<-ch
+	return devices, err
+}
+
+type mfDriver struct {
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+func openDriver(c *Camera, options *Options) (driver, error) {
+	d := &mfDriver{
+		done: make(chan struct{}),
+	}
+	errCh := make(chan error)
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+
+		if err := initializeMF(); err != nil {
+			errCh <- err
+			return
+		}
+		defer windows.CoUninitialize()
+
+		source, reader, err := openReader(options)
+		if err != nil {
+			errCh <- err
+			return
+		}
+		defer func() {
+			reader.Release()
+			source.Shutdown()
+			source.Release()
+		}()
+
+		w, h, stride, err := currentFrameFormat(reader)
+		if err != nil {
+			errCh <- err
+			return
+		}
+		errCh <- nil
+
+		if err := d.loop(c, reader, w, h, stride); err != nil {
+			c.setError(err)
+		}
+	}()
+
+	if err := <-errCh; err != nil {
+		d.wg.Wait()
+		return nil, err
+	}
+	return d, nil
+}
+
+// openReader opens the device and creates a source reader to output RGB32 frames.
+func openReader(options *Options) (*iMFMediaSource, *iMFSourceReader, error) {
+	var source *iMFMediaSource
+	var found bool
+	var activateErr error
+	if err := enumVideoDevices(func(activate *iMFActivate) bool {
+		if options.DeviceID != "" {
+			id, err := activate.attributes().GetAllocatedString(&_MF_DEVSOURCE_ATTRIBUTE_SOURCE_TYPE_VIDCAP_SYMBOLIC_LINK)
+			if err != nil || id != options.DeviceID {
+				return true
+			}
+		}
+		found = true
+		obj, err := activate.ActivateObject(&_IID_IMFMediaSource)
+		if err != nil {
+			activateErr = err
+			return false
+		}
+		source = (*iMFMediaSource)(obj)
+		return false
+	}); err != nil {
+		return nil, nil, err
+	}
+	if !found {
+		return nil, nil, ErrNoDevice
+	}
+	if activateErr != nil {
+		return nil, nil, activateErr
+	}
+
+	attr, err := _MFCreateAttributes(1)
+	if err != nil {
+		source.Shutdown()
+		source.Release()
+		return nil, nil, err
+	}
+	defer attr.Release()
+	// Let the source reader convert the native formats like NV12 and MJPEG to RGB32.
+	if err := attr.SetUINT32(&_MF_SOURCE_READER_ENABLE_VIDEO_PROCESSING, 1); err != nil {
+		source.Shutdown()
+		source.Release()
+		return nil, nil, err
+	}
+	reader, err := _MFCreateSourceReaderFromMediaSource(source, attr)
+	if err != nil {
+		source.Shutdown()
+		source.Release()
+		return nil, nil, err
+	}
+
+	if err := setOutputType(reader, options.Width, options.Height); err != nil {
+		// The size might not be supported. Try the default size.
+		if options.Width == 0 || options.Height == 0 {
+			reader.Release()
+			source.Shutdown()
+			source.Release()
+			return nil, nil, err
+		}
+		if err := setOutputType(reader, 0, 0); err != nil {
+			reader.Release()
+			source.Shutdown()
+			source.Release()
+			return nil, nil, err
+		}
+	}
+	return source, reader, nil
+}
+
+func setOutputType(reader *iMFSourceReader, width, height int) error {
+	t, err := _MFCreateMediaType()
+	if err != nil {
+		return err
+	}
+	defer t.Release()
+	if err := t.SetGUID(&_MF_MT_MAJOR_TYPE, &_MFMediaType_Video); err != nil {
+		return err
+	}
+	if err := t.SetGUID(&_MF_MT_SUBTYPE, &_MFVideoFormat_RGB32); err != nil {
+		return err
+	}
+	if width > 0 && height > 0 {
+		if err := t.SetUINT64(&_MF_MT_FRAME_SIZE, uint64(width)<<32|uint64(height)); err != nil {
+			return err
+		}
+	}
+	return reader.SetCurrentMediaType(_MF_SOURCE_READER_FIRST_VIDEO_STREAM, t)
+}
+
+// currentFrameFormat returns the size and the stride in bytes of the output frames.
+func currentFrameFormat(reader *iMFSourceReader) (width, height, stride int, err error) {
+	t, err := reader.GetCurrentMediaType(_MF_SOURCE_READER_FIRST_VIDEO_STREAM)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	defer t.Release()
+	size, err := t.GetUINT64(&_MF_MT_FRAME_SIZE)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	width = int(size >> 32)
+	height = int(uint32(size))
+	// A negative stride means bottom-up rows.
+	stride = 4 * width
+	if s, err := t.GetUINT32(&_MF_MT_DEFAULT_STRIDE); err == nil {
+		stride = int(int32(s))
+	}
+	return width, height, stride, nil
+}
+
+func (d *mfDriver) loop(c *Camera, reader *iMFSourceReader, width, height, stride int) error {
+	for {
+		select {
+		case <-d.done:
+			return nil
+		default:
+		}
+
+		flags, sample, err := reader.ReadSample(_MF_SOURCE_READER_FIRST_VIDEO_STREAM)
+		if err != nil {
+			return err
+		}
+		if flags&_MF_SOURCE_READERF_ERROR != 0 {
+			return fmt.Errorf("camera: an error happened in the source reader")
+		}
+		if flags&_MF_SOURCE_READERF_ENDOFSTREAM != 0 {
+			return nil
+		}
+		if flags&_MF_SOURCE_READERF_CURRENTMEDIATYPECHANGED != 0 {
+			w, h, s, err := currentFrameFormat(reader)
+			if err != nil {
+				if sample != nil {
+					sample.Release()
+				}
+				return err
+			}
+			width, height, stride = w, h, s
+		}
+		if sample == nil {
+			continue
+		}
+		if err := readSample(c, sample, width, height, stride); err != nil {
+			sample.Release()
+			return err
+		}
+		sample.Release()
+	}
+}
+
+func readSample(c *Camera, sample *iMFSample, width, height, stride int) error {
+	b, err := sample.ConvertToContiguousBuffer()
+	if err != nil {
+		return err
+	}
+	defer b.Release()
+
+	ptr, l, err := b.Lock()
+	if err != nil {
+		return err
+	}
+	defer b.Unlock()
+
+	if int(l) < abs(stride)*height {
+		return nil
+	}
+	src := (*[1 << 30]byte)(ptr)[:l:l]
+	dst := c.nextFrame(width, height)
+	convertBGRX(dst, src, width, height, stride)
+	c.pushFrame(dst, width, height)
+	return nil
+}
+
+func (d *mfDriver) close() error {
+	close(d.done)
+	// ReadSample returns at the next frame.
+	d.wg.Wait()
+	return nil
+}
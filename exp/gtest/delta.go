@@ -0,0 +1,51 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gtest
+
+import (
+	"image/color"
+	"math"
+)
+
+// maxYIQDelta is the maximum possible value of the YIQ delta among all the color pairs.
+const maxYIQDelta = 35215
+
+// colorDelta returns the perceptual difference of the two colors in [0, 1].
+//
+// The difference is measured in the YIQ color space, weighting the luminance more than the chrominance.
+// The colors are blended with white in advance so that the differences of transparent pixels are small.
+func colorDelta(c0, c1 color.RGBA) float64 {
+	if c0 == c1 {
+		return 0
+	}
+	y0, i0, q0 := yiq(blendWithWhite(c0))
+	y1, i1, q1 := yiq(blendWithWhite(c1))
+	dy, di, dq := y0-y1, i0-i1, q0-q1
+	d := 0.5053*dy*dy + 0.299*di*di + 0.1957*dq*dq
+	return math.Min(d/maxYIQDelta, 1)
+}
+
+func blendWithWhite(c color.RGBA) (r, g, b float64) {
+	// The color is premultiplied.
+	a := float64(c.A)
+	return float64(c.R) + 255 - a, float64(c.G) + 255 - a, float64(c.B) + 255 - a
+}
+
+func yiq(r, g, b float64) (y, i, q float64) {
+	y = 0.29889531*r + 0.58662247*g + 0.11448223*b
+	i = 0.59597799*r - 0.27417610*g - 0.32180189*b
+	q = 0.21147017*r - 0.52261711*g + 0.31114694*b
+	return
+}
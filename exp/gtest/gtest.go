@@ -0,0 +1,314 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gtest provides utilities to test rendering results of games against golden images.
+//
+// gtest runs a game for a fixed number of frames with fake inputs, and compares the rendering results with
+// golden PNG files. Each frame advances exactly one tick regardless of the actual time, so the results are
+// deterministic as long as the game depends only on ticks.
+//
+// While Run is running, the clock is fixed: ebiten.CurrentTick starts from 0 and advances by one per frame,
+// ebiten.CurrentTPS and ebiten.CurrentFPS return the maximum TPS (or 60 if TPS is synced with FPS),
+// and ebiten.TickProgress returns 0.
+//
+// The rendering is done by the actual graphics driver. Tests using gtest must run in the Ebiten main loop.
+// Use MainWithRunLoop in TestMain.
+//
+// If the environment variable EBITEN_GTEST_UPDATE is set to 1, the golden files are updated with the
+// rendering results instead of being compared.
+//
+// This package is experimental and the API might be changed in the future.
+package gtest
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/internal/clock"
+	"github.com/hajimehoshi/ebiten/v2/internal/fakeinput"
+	"github.com/hajimehoshi/ebiten/v2/internal/hooks"
+	"github.com/hajimehoshi/ebiten/v2/internal/ui"
+)
+
+var regularTermination = errors.New("regular termination")
+
+type mainGame struct {
+	m    *testing.M
+	code int
+}
+
+func (g *mainGame) Update() error {
+	g.code = g.m.Run()
+	return regularTermination
+}
+
+func (*mainGame) Draw(*ebiten.Image) {
+}
+
+func (*mainGame) Layout(int, int) (int, int) {
+	return 320, 240
+}
+
+// MainWithRunLoop runs the tests in the Ebiten main loop, and then exits the process.
+//
+// MainWithRunLoop must be called from TestMain.
+func MainWithRunLoop(m *testing.M) {
+	g := &mainGame{
+		m: m,
+	}
+	if err := ebiten.RunGame(g); err != nil && err != regularTermination {
+		panic(err)
+	}
+	os.Exit(g.code)
+}
+
+// Input represents fake input states for one frame.
+type Input struct {
+	// Keys is the set of the pressed keys.
+	Keys []ebiten.Key
+
+	// MouseButtons is the set of the pressed mouse buttons.
+	MouseButtons []ebiten.MouseButton
+
+	// CursorX and CursorY are the cursor position.
+	CursorX int
+	CursorY int
+}
+
+// Options represents options for Run.
+type Options struct {
+	// Frames is the number of the frames to run.
+	Frames int
+
+	// OutsideWidth and OutsideHeight are the outside size passed to the game's Layout.
+	// The default (zero) values are 640 and 480.
+	OutsideWidth  int
+	OutsideHeight int
+
+	// Input returns the fake input states for the given frame, starting from 0.
+	// If Input is nil, nothing is pressed in any frame.
+	Input func(frame int) Input
+
+	// Goldens maps a frame, starting from 0, to the path of the golden PNG file.
+	// The rendering result of the frame is compared with the golden file.
+	Goldens map[int]string
+
+	// Threshold is the perceptual color difference threshold in [0, 1] to regard two pixels as different.
+	// The default (zero) value is 0.1.
+	// To compare the pixels exactly, specify a negative value, e.g., -1.
+	Threshold float64
+
+	// MaxDiffRatio is the maximum ratio of the different pixels in [0, 1] to regard two images as the same.
+	// The default (zero) value is 0, which means all the pixels must be the same within Threshold.
+	MaxDiffRatio float64
+}
+
+// Run runs the game with the given options, and reports errors to t when the rendering results differ from
+// the golden files.
+//
+// Run must be called in the Ebiten main loop. See also MainWithRunLoop.
+func Run(t testing.TB, game ebiten.Game, options *Options) {
+	t.Helper()
+
+	if options == nil {
+		options = &Options{}
+	}
+	ow, oh := options.OutsideWidth, options.OutsideHeight
+	if ow == 0 {
+		ow = 640
+	}
+	if oh == 0 {
+		oh = 480
+	}
+	threshold := options.threshold()
+	update := os.Getenv("EBITEN_GTEST_UPDATE") == "1"
+
+	fakeinput.Enable()
+	defer fakeinput.Disable()
+
+	tick := clock.CurrentTick()
+	clock.SetTick(0)
+	clock.SetFixed(fixedTPS())
+	defer func() {
+		clock.SetFixed(0)
+		clock.SetTick(tick)
+	}()
+
+	var screen *ebiten.Image
+	defer func() {
+		if screen != nil {
+			screen.Dispose()
+		}
+	}()
+
+	for frame := 0; frame < options.Frames; frame++ {
+		var in Input
+		if options.Input != nil {
+			in = options.Input(frame)
+		}
+		setInput(in)
+
+		if err := hooks.RunBeforeUpdateHooks(); err != nil {
+			t.Fatalf("gtest: frame %d: %v", frame, err)
+		}
+		if err := game.Update(); err != nil {
+			t.Fatalf("gtest: frame %d: Update failed: %v", frame, err)
+		}
+		clock.AdvanceTick()
+
+		sw, sh := game.Layout(ow, oh)
+		if sw <= 0 || sh <= 0 {
+			t.Fatalf("gtest: frame %d: Layout must return positive numbers but got %d, %d", frame, sw, sh)
+		}
+		if screen != nil {
+			if w, h := screen.Size(); w != sw || h != sh {
+				screen.Dispose()
+				screen = nil
+			}
+		}
+		if screen == nil {
+			screen = ebiten.NewImage(sw, sh)
+		}
+		screen.Clear()
+		game.Draw(screen)
+
+		path, ok := options.Goldens[frame]
+		if !ok {
+			continue
+		}
+		got := toRGBA(screen)
+		if update {
+			if err := writePNG(path, got); err != nil {
+				t.Fatalf("gtest: frame %d: %v", frame, err)
+			}
+			continue
+		}
+		want, err := readPNG(path)
+		if err != nil {
+			t.Fatalf("gtest: frame %d: %v", frame, err)
+		}
+		if err := compare(got, want, threshold, options.MaxDiffRatio); err != nil {
+			t.Errorf("gtest: frame %d: %s: %v", frame, path, err)
+		}
+	}
+}
+
+func (o *Options) threshold() float64 {
+	switch {
+	case o.Threshold < 0:
+		return 0
+	case o.Threshold == 0:
+		return 0.1
+	default:
+		return o.Threshold
+	}
+}
+
+// fixedTPS returns TPS that the fixed clock reports.
+func fixedTPS() float64 {
+	num, den := ebiten.MaxTPSRational()
+	if num <= 0 {
+		return 60
+	}
+	return float64(num) / float64(den)
+}
+
+func setInput(in Input) {
+	keys := make([]ui.Key, 0, len(in.Keys))
+	for _, k := range in.Keys {
+		switch k {
+		case ebiten.KeyAlt:
+			keys = append(keys, ui.KeyAltLeft)
+		case ebiten.KeyControl:
+			keys = append(keys, ui.KeyControlLeft)
+		case ebiten.KeyShift:
+			keys = append(keys, ui.KeyShiftLeft)
+		case ebiten.KeyMeta:
+			keys = append(keys, ui.KeyMetaLeft)
+		default:
+			keys = append(keys, ui.Key(k))
+		}
+	}
+	fakeinput.Set(keys, in.MouseButtons, in.CursorX, in.CursorY)
+}
+
+func toRGBA(img image.Image) *image.RGBA {
+	b := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	for j := 0; j < b.Dy(); j++ {
+		for i := 0; i < b.Dx(); i++ {
+			dst.Set(i, j, img.At(b.Min.X+i, b.Min.Y+j))
+		}
+	}
+	return dst
+}
+
+func readPNG(path string) (*image.RGBA, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	img, err := png.Decode(f)
+	if err != nil {
+		return nil, err
+	}
+	return toRGBA(img), nil
+}
+
+func writePNG(path string, img image.Image) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return png.Encode(f, img)
+}
+
+func compare(got, want *image.RGBA, threshold float64, maxDiffRatio float64) error {
+	if got.Bounds().Size() != want.Bounds().Size() {
+		return fmt.Errorf("size mismatch: got: %v, want: %v", got.Bounds().Size(), want.Bounds().Size())
+	}
+
+	w, h := got.Bounds().Dx(), got.Bounds().Dy()
+	var n int
+	var first image.Point
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			if colorDelta(got.RGBAAt(i, j), want.RGBAAt(i, j)) <= threshold {
+				continue
+			}
+			if n == 0 {
+				first = image.Pt(i, j)
+			}
+			n++
+		}
+	}
+	if ratio := float64(n) / float64(w*h); ratio > maxDiffRatio {
+		return fmt.Errorf("%d pixels (%.2f%%) differ; the first different pixel is at %v: got: %v, want: %v",
+			n, ratio*100, first, got.RGBAAt(first.X, first.Y), want.RGBAAt(first.X, first.Y))
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gtest
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+func TestColorDelta(t *testing.T) {
+	tests := []struct {
+		c0   color.RGBA
+		c1   color.RGBA
+		want float64
+	}{
+		{color.RGBA{0x12, 0x34, 0x56, 0xff}, color.RGBA{0x12, 0x34, 0x56, 0xff}, 0},
+		// Black and white differ only in the luminance, and the delta is not the maximum.
+		{color.RGBA{0, 0, 0, 0xff}, color.RGBA{0xff, 0xff, 0xff, 0xff}, 0.933},
+		// A transparent pixel is regarded as white.
+		{color.RGBA{0, 0, 0, 0}, color.RGBA{0xff, 0xff, 0xff, 0xff}, 0},
+	}
+	for _, tc := range tests {
+		if got := colorDelta(tc.c0, tc.c1); math.Abs(got-tc.want) > 1e-3 {
+			t.Errorf("colorDelta(%v, %v): got: %f, want: %f", tc.c0, tc.c1, got, tc.want)
+		}
+		if got := colorDelta(tc.c1, tc.c0); math.Abs(got-tc.want) > 1e-3 {
+			t.Errorf("colorDelta(%v, %v): got: %f, want: %f", tc.c1, tc.c0, got, tc.want)
+		}
+	}
+
+	// The luminance is weighted more than the chrominance.
+	gray := color.RGBA{0x80, 0x80, 0x80, 0xff}
+	if dy, dc := colorDelta(gray, color.RGBA{0x90, 0x90, 0x90, 0xff}), colorDelta(gray, color.RGBA{0x80, 0x80, 0x90, 0xff}); dy <= dc {
+		t.Errorf("a luminance delta (%f) must be bigger than a blue delta (%f)", dy, dc)
+	}
+}
+
+func newUniformRGBA(w, h int, clr color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			img.SetRGBA(i, j, clr)
+		}
+	}
+	return img
+}
+
+func TestCompare(t *testing.T) {
+	black := color.RGBA{0, 0, 0, 0xff}
+	white := color.RGBA{0xff, 0xff, 0xff, 0xff}
+	nearBlack := color.RGBA{1, 1, 1, 0xff}
+
+	want := newUniformRGBA(10, 10, black)
+
+	if err := compare(newUniformRGBA(10, 10, black), want, 0, 0); err != nil {
+		t.Errorf("the same images: %v", err)
+	}
+	if err := compare(newUniformRGBA(10, 5, black), want, 0.1, 0); err == nil {
+		t.Errorf("images with different sizes must differ")
+	}
+	if err := compare(newUniformRGBA(10, 10, nearBlack), want, 0.1, 0); err != nil {
+		t.Errorf("images within the threshold: %v", err)
+	}
+	if err := compare(newUniformRGBA(10, 10, nearBlack), want, 0, 0); err == nil {
+		t.Errorf("images must differ with the zero threshold")
+	}
+
+	// 5 pixels out of 100 differ.
+	got := newUniformRGBA(10, 10, black)
+	for i := 0; i < 5; i++ {
+		got.SetRGBA(i, 0, white)
+	}
+	if err := compare(got, want, 0.1, 0.05); err != nil {
+		t.Errorf("images within the max diff ratio: %v", err)
+	}
+	if err := compare(got, want, 0.1, 0.04); err == nil {
+		t.Errorf("images must differ with the max diff ratio 0.04")
+	}
+}
+
+func TestThreshold(t *testing.T) {
+	tests := []struct {
+		threshold float64
+		want      float64
+	}{
+		{0, 0.1},
+		{0.3, 0.3},
+		{-1, 0},
+	}
+	for _, tc := range tests {
+		op := &Options{
+			Threshold: tc.threshold,
+		}
+		if got := op.threshold(); got != tc.want {
+			t.Errorf("threshold with %f: got: %f, want: %f", tc.threshold, got, tc.want)
+		}
+	}
+}
+
+func TestFixedTPS(t *testing.T) {
+	num, den := ebiten.MaxTPSRational()
+	defer ebiten.SetMaxTPSRational(num, den)
+
+	ebiten.SetMaxTPSRational(60000, 1001)
+	if got, want := fixedTPS(), 60000.0/1001.0; got != want {
+		t.Errorf("fixedTPS(): got: %f, want: %f", got, want)
+	}
+	ebiten.SetMaxTPS(ebiten.SyncWithFPS)
+	if got, want := fixedTPS(), 60.0; got != want {
+		t.Errorf("fixedTPS(): got: %f, want: %f", got, want)
+	}
+}
@@ -0,0 +1,261 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package imguibackend offers a renderer and input bridge between Ebiten and Dear ImGui.
+//
+// This package doesn't depend on a specific Go binding of Dear ImGui. Instead, a game copies the binding's draw
+// data to DrawData, and gives the binding's IO the values of Input. DrawVert has the same memory layout as
+// Dear ImGui's ImDrawVert with the default configuration, so a vertex buffer can be converted without copying.
+//
+// A typical usage is like this:
+//
+//	func (g *Game) Update() error {
+//		in := g.backend.NewFrame(screenWidth, screenHeight)
+//		// Give in to the binding's IO, and call the binding's NewFrame.
+//		// Declare the UI.
+//		// Call the binding's Render.
+//		return nil
+//	}
+//
+//	func (g *Game) Draw(screen *ebiten.Image) {
+//		// Copy the binding's draw data to g.drawData.
+//		g.backend.Draw(screen, &g.drawData)
+//	}
+//
+// The font atlas must be registered by CreateTexture, and its ID must be set to the binding's font atlas.
+//
+// This package is experimental and the API might be changed in the future.
+package imguibackend
+
+import (
+	"image"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// TextureID represents a texture, corresponding to Dear ImGui's ImTextureID.
+type TextureID uintptr
+
+// DrawVert is a vertex of Dear ImGui, corresponding to ImDrawVert.
+type DrawVert struct {
+	PosX float32
+	PosY float32
+	U    float32
+	V    float32
+
+	// Col is a non-premultiplied color in the form of 0xAABBGGRR.
+	Col uint32
+}
+
+// DrawCmd is a draw command of Dear ImGui, corresponding to ImDrawCmd.
+type DrawCmd struct {
+	// ClipRect is the clipping rectangle (x1, y1, x2, y2) in the display coordinates.
+	ClipRect [4]float32
+
+	TextureID TextureID
+
+	// VtxOffset is the offset of the vertices. The indices are relative to VtxOffset.
+	VtxOffset int
+
+	// IdxOffset is the offset of the indices.
+	IdxOffset int
+
+	// ElemCount is the number of the indices.
+	ElemCount int
+}
+
+// DrawList is a list of draw commands of Dear ImGui, corresponding to ImDrawList.
+type DrawList struct {
+	Vertices []DrawVert
+	Indices  []uint16
+	Commands []DrawCmd
+}
+
+// DrawData is the draw data of Dear ImGui, corresponding to ImDrawData.
+type DrawData struct {
+	// DisplayPosX and DisplayPosY are the top-left position of the display, which is usually (0, 0).
+	DisplayPosX float32
+	DisplayPosY float32
+
+	// FramebufferScaleX and FramebufferScaleY are the scale to convert the display coordinates to the screen's.
+	// If they are 0, 1 is used.
+	FramebufferScaleX float32
+	FramebufferScaleY float32
+
+	Lists []DrawList
+}
+
+// Backend is a bridge between Ebiten and Dear ImGui.
+type Backend struct {
+	textures      map[TextureID]*ebiten.Image
+	ownedTextures map[TextureID]struct{}
+	nextTextureID TextureID
+
+	vertices []ebiten.Vertex
+	indices  []uint16
+
+	input input
+}
+
+// New creates a new backend.
+func New() *Backend {
+	return &Backend{
+		textures:      map[TextureID]*ebiten.Image{},
+		ownedTextures: map[TextureID]struct{}{},
+		nextTextureID: 1,
+	}
+}
+
+// CreateTexture creates a texture from the non-premultiplied RGBA pixels, e.g., the font atlas given by
+// ImFontAtlas's GetTexDataAsRGBA32, and returns its ID.
+//
+// The texture is disposed by DeleteTexture.
+func (b *Backend) CreateTexture(pixels []byte, width, height int) TextureID {
+	if len(pixels) != 4*width*height {
+		panic("imguibackend: len(pixels) must be 4*width*height")
+	}
+
+	// Ebiten's images are premultiplied.
+	p := make([]byte, len(pixels))
+	for i := 0; i < len(p); i += 4 {
+		a := uint32(pixels[i+3])
+		p[i] = byte(uint32(pixels[i]) * a / 0xff)
+		p[i+1] = byte(uint32(pixels[i+1]) * a / 0xff)
+		p[i+2] = byte(uint32(pixels[i+2]) * a / 0xff)
+		p[i+3] = byte(a)
+	}
+	img := ebiten.NewImage(width, height)
+	img.ReplacePixels(p)
+
+	id := b.RegisterTexture(img)
+	b.ownedTextures[id] = struct{}{}
+	return id
+}
+
+// RegisterTexture registers the image as a texture and returns its ID.
+// The ID can be used with Dear ImGui's functions like Image.
+//
+// The image is not disposed by DeleteTexture.
+func (b *Backend) RegisterTexture(img *ebiten.Image) TextureID {
+	id := b.nextTextureID
+	b.nextTextureID++
+	b.textures[id] = img
+	return id
+}
+
+// DeleteTexture unregisters the texture. If the texture is created by CreateTexture, the texture is disposed.
+func (b *Backend) DeleteTexture(id TextureID) {
+	img, ok := b.textures[id]
+	if !ok {
+		return
+	}
+	if _, ok := b.ownedTextures[id]; ok {
+		img.Dispose()
+		delete(b.ownedTextures, id)
+	}
+	delete(b.textures, id)
+}
+
+// Draw draws the draw data to screen.
+//
+// Each draw command is clipped by its clipping rectangle with a sub-image of screen.
+// The draw commands with unknown texture IDs are skipped.
+func (b *Backend) Draw(screen *ebiten.Image, data *DrawData) {
+	sx, sy := data.FramebufferScaleX, data.FramebufferScaleY
+	if sx == 0 {
+		sx = 1
+	}
+	if sy == 0 {
+		sy = 1
+	}
+
+	op := &ebiten.DrawTrianglesOptions{
+		Filter: ebiten.FilterLinear,
+	}
+	bounds := screen.Bounds()
+	for _, l := range data.Lists {
+		for _, cmd := range l.Commands {
+			if cmd.ElemCount == 0 {
+				continue
+			}
+			tex, ok := b.textures[cmd.TextureID]
+			if !ok {
+				continue
+			}
+			if cmd.IdxOffset < 0 || cmd.IdxOffset+cmd.ElemCount > len(l.Indices) || cmd.VtxOffset < 0 {
+				continue
+			}
+
+			clip := image.Rect(
+				bounds.Min.X+int(math.Floor(float64((cmd.ClipRect[0]-data.DisplayPosX)*sx))),
+				bounds.Min.Y+int(math.Floor(float64((cmd.ClipRect[1]-data.DisplayPosY)*sy))),
+				bounds.Min.X+int(math.Ceil(float64((cmd.ClipRect[2]-data.DisplayPosX)*sx))),
+				bounds.Min.Y+int(math.Ceil(float64((cmd.ClipRect[3]-data.DisplayPosY)*sy))),
+			).Intersect(bounds)
+			if clip.Empty() {
+				continue
+			}
+
+			if !b.appendTriangles(&l, &cmd, tex, data, sx, sy, float32(bounds.Min.X), float32(bounds.Min.Y)) {
+				continue
+			}
+			screen.SubImage(clip).(*ebiten.Image).DrawTriangles(b.vertices, b.indices, tex, op)
+		}
+	}
+}
+
+// appendTriangles converts the vertices and the indices used by the command to Ebiten's, and reports whether
+// the command is valid.
+func (b *Backend) appendTriangles(l *DrawList, cmd *DrawCmd, tex *ebiten.Image, data *DrawData, sx, sy float32, ox, oy float32) bool {
+	indices := l.Indices[cmd.IdxOffset : cmd.IdxOffset+cmd.ElemCount]
+
+	// Convert only the vertices used by the command.
+	min, max := uint16(math.MaxUint16), uint16(0)
+	for _, idx := range indices {
+		if min > idx {
+			min = idx
+		}
+		if max < idx {
+			max = idx
+		}
+	}
+	if cmd.VtxOffset+int(max) >= len(l.Vertices) {
+		return false
+	}
+
+	tb := tex.Bounds()
+	tw, th := float32(tb.Dx()), float32(tb.Dy())
+	tx, ty := float32(tb.Min.X), float32(tb.Min.Y)
+
+	b.vertices = b.vertices[:0]
+	for _, v := range l.Vertices[cmd.VtxOffset+int(min) : cmd.VtxOffset+int(max)+1] {
+		b.vertices = append(b.vertices, ebiten.Vertex{
+			DstX:   ox + (v.PosX-data.DisplayPosX)*sx,
+			DstY:   oy + (v.PosY-data.DisplayPosY)*sy,
+			SrcX:   tx + v.U*tw,
+			SrcY:   ty + v.V*th,
+			ColorR: float32(v.Col&0xff) / 0xff,
+			ColorG: float32((v.Col>>8)&0xff) / 0xff,
+			ColorB: float32((v.Col>>16)&0xff) / 0xff,
+			ColorA: float32((v.Col>>24)&0xff) / 0xff,
+		})
+	}
+
+	b.indices = b.indices[:0]
+	for _, idx := range indices {
+		b.indices = append(b.indices, idx-min)
+	}
+	return true
+}
@@ -0,0 +1,120 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imguibackend
+
+import (
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Input is the input state for a frame of Dear ImGui, corresponding to the input part of ImGuiIO.
+type Input struct {
+	DisplayWidth  float32
+	DisplayHeight float32
+
+	// DeltaTime is the time since the previous frame in seconds.
+	DeltaTime float32
+
+	MouseX float32
+	MouseY float32
+
+	// MouseDown reports whether the left, right, and middle mouse buttons are pressed.
+	MouseDown [3]bool
+
+	MouseWheelX float32
+	MouseWheelY float32
+
+	KeyCtrl  bool
+	KeyShift bool
+	KeyAlt   bool
+	KeySuper bool
+
+	// Chars is the characters input in the frame.
+	Chars []rune
+
+	// KeyEvents is the keys that are pressed or released in the frame.
+	// The game maps the keys to the binding's key enumeration like ImGuiKey.
+	KeyEvents []KeyEvent
+}
+
+// KeyEvent is a key event.
+type KeyEvent struct {
+	Key  ebiten.Key
+	Down bool
+}
+
+type input struct {
+	in       Input
+	prevTime time.Time
+}
+
+// NewFrame returns the input state for a new frame of Dear ImGui.
+//
+// NewFrame must be called once in every Update, before the binding's NewFrame.
+// displayWidth and displayHeight are the size of the display, which is usually the screen size returned by
+// Layout.
+//
+// The slices in the returned value are valid until the next call of NewFrame.
+func (b *Backend) NewFrame(displayWidth, displayHeight float32) Input {
+	in := &b.input.in
+
+	now := time.Now()
+	in.DeltaTime = 1.0 / 60
+	if !b.input.prevTime.IsZero() {
+		if dt := float32(now.Sub(b.input.prevTime).Seconds()); dt > 0 {
+			in.DeltaTime = dt
+		}
+	}
+	b.input.prevTime = now
+
+	in.DisplayWidth = displayWidth
+	in.DisplayHeight = displayHeight
+
+	x, y := ebiten.CursorPosition()
+	in.MouseX, in.MouseY = float32(x), float32(y)
+	for i, button := range []ebiten.MouseButton{ebiten.MouseButtonLeft, ebiten.MouseButtonRight, ebiten.MouseButtonMiddle} {
+		in.MouseDown[i] = ebiten.IsMouseButtonPressed(button)
+	}
+	// Treat the first touch as the left mouse button.
+	if ids := ebiten.AppendTouchIDs(nil); len(ids) > 0 {
+		x, y := ebiten.TouchPosition(ids[0])
+		in.MouseX, in.MouseY = float32(x), float32(y)
+		in.MouseDown[0] = true
+	}
+
+	wx, wy := ebiten.Wheel()
+	in.MouseWheelX, in.MouseWheelY = float32(wx), float32(wy)
+
+	in.KeyCtrl = ebiten.IsKeyPressed(ebiten.KeyControl)
+	in.KeyShift = ebiten.IsKeyPressed(ebiten.KeyShift)
+	in.KeyAlt = ebiten.IsKeyPressed(ebiten.KeyAlt)
+	in.KeySuper = ebiten.IsKeyPressed(ebiten.KeyMeta)
+
+	in.Chars = ebiten.AppendInputChars(in.Chars[:0])
+
+	in.KeyEvents = in.KeyEvents[:0]
+	for k := ebiten.Key(0); k <= ebiten.KeyMax; k++ {
+		switch {
+		case inpututil.IsKeyJustPressed(k):
+			in.KeyEvents = append(in.KeyEvents, KeyEvent{Key: k, Down: true})
+		case inpututil.IsKeyJustReleased(k):
+			in.KeyEvents = append(in.KeyEvents, KeyEvent{Key: k, Down: false})
+		}
+	}
+
+	return *in
+}
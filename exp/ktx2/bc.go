@@ -0,0 +1,148 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ktx2
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// decodePixels decodes the block-compressed data of t into RGBA pixels on CPU.
+func decodePixels(t *Texture) ([]byte, error) {
+	var decodeBlock func(dst *[16][4]byte, block []byte)
+	var blockByteSize int
+	switch t.Format {
+	case ebiten.CompressedTextureFormatBC1:
+		decodeBlock = decodeBC1Block
+		blockByteSize = 8
+	case ebiten.CompressedTextureFormatBC2:
+		decodeBlock = decodeBC2Block
+		blockByteSize = 16
+	case ebiten.CompressedTextureFormatBC3:
+		decodeBlock = decodeBC3Block
+		blockByteSize = 16
+	default:
+		return nil, fmt.Errorf("%w: %s cannot be decoded on CPU", ErrUnsupported, t.Format)
+	}
+
+	pix := make([]byte, 4*t.Width*t.Height)
+	bw := (t.Width + 3) / 4
+	bh := (t.Height + 3) / 4
+	var texels [16][4]byte
+	for by := 0; by < bh; by++ {
+		for bx := 0; bx < bw; bx++ {
+			offset := (by*bw + bx) * blockByteSize
+			decodeBlock(&texels, t.Data[offset:offset+blockByteSize])
+			for j := 0; j < 4; j++ {
+				y := by*4 + j
+				if y >= t.Height {
+					break
+				}
+				for i := 0; i < 4; i++ {
+					x := bx*4 + i
+					if x >= t.Width {
+						break
+					}
+					copy(pix[4*(y*t.Width+x):], texels[j*4+i][:])
+				}
+			}
+		}
+	}
+	return pix, nil
+}
+
+func rgb565(c uint16) [4]byte {
+	r := byte(c >> 11 & 0x1f)
+	g := byte(c >> 5 & 0x3f)
+	b := byte(c & 0x1f)
+	return [4]byte{r<<3 | r>>2, g<<2 | g>>4, b<<3 | b>>2, 0xff}
+}
+
+func mixColors(c0, c1 [4]byte, w0, w1 int) [4]byte {
+	var c [4]byte
+	for i := range c {
+		c[i] = byte((int(c0[i])*w0 + int(c1[i])*w1) / (w0 + w1))
+	}
+	return c
+}
+
+// decodeColorBlock decodes the color part of a BC1, BC2 or BC3 block.
+//
+// When alpha is false, BC1's 3-color mode with a transparent black is available.
+func decodeColorBlock(dst *[16][4]byte, block []byte, alpha bool) {
+	c0 := binary.LittleEndian.Uint16(block[0:])
+	c1 := binary.LittleEndian.Uint16(block[2:])
+	indices := binary.LittleEndian.Uint32(block[4:])
+
+	var palette [4][4]byte
+	palette[0] = rgb565(c0)
+	palette[1] = rgb565(c1)
+	if c0 > c1 || alpha {
+		palette[2] = mixColors(palette[0], palette[1], 2, 1)
+		palette[3] = mixColors(palette[0], palette[1], 1, 2)
+	} else {
+		palette[2] = mixColors(palette[0], palette[1], 1, 1)
+		palette[3] = [4]byte{}
+	}
+
+	for i := 0; i < 16; i++ {
+		dst[i] = palette[indices>>(2*i)&0x3]
+	}
+}
+
+func decodeBC1Block(dst *[16][4]byte, block []byte) {
+	decodeColorBlock(dst, block, false)
+}
+
+func decodeBC2Block(dst *[16][4]byte, block []byte) {
+	decodeColorBlock(dst, block[8:], true)
+	alphas := binary.LittleEndian.Uint64(block[0:])
+	for i := 0; i < 16; i++ {
+		a := byte(alphas >> (4 * i) & 0xf)
+		dst[i][3] = a<<4 | a
+	}
+}
+
+func decodeBC3Block(dst *[16][4]byte, block []byte) {
+	decodeColorBlock(dst, block[8:], true)
+
+	a0 := int(block[0])
+	a1 := int(block[1])
+	var palette [8]byte
+	palette[0] = byte(a0)
+	palette[1] = byte(a1)
+	if a0 > a1 {
+		for i := 1; i < 7; i++ {
+			palette[i+1] = byte(((7-i)*a0 + i*a1) / 7)
+		}
+	} else {
+		for i := 1; i < 5; i++ {
+			palette[i+1] = byte(((5-i)*a0 + i*a1) / 5)
+		}
+		palette[6] = 0
+		palette[7] = 0xff
+	}
+
+	// The alpha indices are 3-bit values packed in 6 bytes.
+	var indices uint64
+	for i := 0; i < 6; i++ {
+		indices |= uint64(block[2+i]) << (8 * i)
+	}
+	for i := 0; i < 16; i++ {
+		dst[i][3] = palette[indices>>(3*i)&0x7]
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ktx2 provides a loader for textures in the KTX2 container format.
+//
+// A KTX2 file holding block-compressed data (BC1-7, ETC2 or ASTC 4x4) is uploaded to GPU as it is
+// when the format is supported by the current environment.
+// Otherwise, the texture is decoded to regular pixels on CPU if possible.
+// So far, BC1, BC2 and BC3 can be decoded on CPU.
+//
+// Only the base mipmap level of a 2D texture is used. Cube maps, texture arrays and 3D textures are not supported.
+// Supported supercompression schemes are none and zlib.
+// Basis Universal payloads (BasisLZ/ETC1S and UASTC) and Zstandard supercompression are not supported yet.
+//
+// sRGB formats are treated as their linear counterparts since Ebiten doesn't convert colors.
+// The color values must be pre-multiplied alpha values.
+//
+// This package is experimental and the API might be changed in the future.
+package ktx2
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
+)
+
+// ErrUnsupported is returned when the texture in a KTX2 file is valid but not supported by this package
+// or the current environment.
+var ErrUnsupported = errors.New("ktx2: unsupported texture")
+
+var identifier = []byte{0xab, 'K', 'T', 'X', ' ', '2', '0', 0xbb, '\r', '\n', 0x1a, '\n'}
+
+const (
+	supercompressionNone    = 0
+	supercompressionBasisLZ = 1
+	supercompressionZstd    = 2
+	supercompressionZlib    = 3
+)
+
+// vkFormatToFormat maps Vulkan format values used in KTX2 to compressed texture formats.
+var vkFormatToFormat = map[uint32]ebiten.CompressedTextureFormat{
+	131: ebiten.CompressedTextureFormatBC1,       // VK_FORMAT_BC1_RGB_UNORM_BLOCK
+	132: ebiten.CompressedTextureFormatBC1,       // VK_FORMAT_BC1_RGB_SRGB_BLOCK
+	133: ebiten.CompressedTextureFormatBC1,       // VK_FORMAT_BC1_RGBA_UNORM_BLOCK
+	134: ebiten.CompressedTextureFormatBC1,       // VK_FORMAT_BC1_RGBA_SRGB_BLOCK
+	135: ebiten.CompressedTextureFormatBC2,       // VK_FORMAT_BC2_UNORM_BLOCK
+	136: ebiten.CompressedTextureFormatBC2,       // VK_FORMAT_BC2_SRGB_BLOCK
+	137: ebiten.CompressedTextureFormatBC3,       // VK_FORMAT_BC3_UNORM_BLOCK
+	138: ebiten.CompressedTextureFormatBC3,       // VK_FORMAT_BC3_SRGB_BLOCK
+	139: ebiten.CompressedTextureFormatBC4,       // VK_FORMAT_BC4_UNORM_BLOCK
+	141: ebiten.CompressedTextureFormatBC5,       // VK_FORMAT_BC5_UNORM_BLOCK
+	143: ebiten.CompressedTextureFormatBC6H,      // VK_FORMAT_BC6H_UFLOAT_BLOCK
+	145: ebiten.CompressedTextureFormatBC7,       // VK_FORMAT_BC7_UNORM_BLOCK
+	146: ebiten.CompressedTextureFormatBC7,       // VK_FORMAT_BC7_SRGB_BLOCK
+	147: ebiten.CompressedTextureFormatETC2RGB8,  // VK_FORMAT_ETC2_R8G8B8_UNORM_BLOCK
+	148: ebiten.CompressedTextureFormatETC2RGB8,  // VK_FORMAT_ETC2_R8G8B8_SRGB_BLOCK
+	151: ebiten.CompressedTextureFormatETC2RGBA8, // VK_FORMAT_ETC2_R8G8B8A8_UNORM_BLOCK
+	152: ebiten.CompressedTextureFormatETC2RGBA8, // VK_FORMAT_ETC2_R8G8B8A8_SRGB_BLOCK
+	157: ebiten.CompressedTextureFormatASTC4x4,   // VK_FORMAT_ASTC_4x4_UNORM_BLOCK
+	158: ebiten.CompressedTextureFormatASTC4x4,   // VK_FORMAT_ASTC_4x4_SRGB_BLOCK
+}
+
+// Texture represents the base level of a texture in a KTX2 file.
+type Texture struct {
+	// Width is the width of the texture in pixels.
+	Width int
+
+	// Height is the height of the texture in pixels.
+	Height int
+
+	// Format is the compressed texture format of Data.
+	Format ebiten.CompressedTextureFormat
+
+	// Data is the block-compressed data of the base level.
+	Data []byte
+}
+
+// Decode reads a KTX2 file from r and returns its base level.
+//
+// If the file is valid but the texture is not supported by this package, Decode returns an error wrapping ErrUnsupported.
+func Decode(r io.Reader) (*Texture, error) {
+	bs, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	const (
+		headerSize     = 12 + 9*4 + 4*4 + 2*8
+		levelIndexSize = 3 * 8
+	)
+	if len(bs) < headerSize || !bytes.Equal(bs[:len(identifier)], identifier) {
+		return nil, fmt.Errorf("ktx2: not a KTX2 file")
+	}
+
+	le := binary.LittleEndian
+	hd := bs[len(identifier):]
+	vkFormat := le.Uint32(hd[0:])
+	width := le.Uint32(hd[8:])
+	height := le.Uint32(hd[12:])
+	depth := le.Uint32(hd[16:])
+	layerCount := le.Uint32(hd[20:])
+	faceCount := le.Uint32(hd[24:])
+	scheme := le.Uint32(hd[32:])
+
+	if width == 0 || height == 0 || depth != 0 || layerCount > 1 || faceCount != 1 {
+		return nil, fmt.Errorf("%w: only 2D textures are supported", ErrUnsupported)
+	}
+
+	switch scheme {
+	case supercompressionNone, supercompressionZlib:
+	case supercompressionBasisLZ:
+		return nil, fmt.Errorf("%w: BasisLZ supercompression is not supported", ErrUnsupported)
+	case supercompressionZstd:
+		return nil, fmt.Errorf("%w: Zstandard supercompression is not supported", ErrUnsupported)
+	default:
+		return nil, fmt.Errorf("ktx2: invalid supercompression scheme: %d", scheme)
+	}
+
+	if vkFormat == 0 {
+		// VK_FORMAT_UNDEFINED is used for Basis Universal UASTC payloads.
+		return nil, fmt.Errorf("%w: Basis Universal UASTC is not supported", ErrUnsupported)
+	}
+	format, ok := vkFormatToFormat[vkFormat]
+	if !ok {
+		return nil, fmt.Errorf("%w: Vulkan format %d is not supported", ErrUnsupported, vkFormat)
+	}
+
+	// A level count 0 means that only the base level exists and mipmaps should be generated.
+	// Anyway, there is at least one item in the level index.
+	if len(bs) < headerSize+levelIndexSize {
+		return nil, fmt.Errorf("ktx2: unexpected EOF at the level index")
+	}
+
+	// The first item of the level index is for the base level.
+	l := bs[headerSize:]
+	offset := le.Uint64(l[0:])
+	length := le.Uint64(l[8:])
+	uncompressedLength := le.Uint64(l[16:])
+	if offset > uint64(len(bs)) || length > uint64(len(bs))-offset {
+		return nil, fmt.Errorf("ktx2: the base level is out of the file")
+	}
+	data := bs[offset : offset+length]
+
+	if scheme == supercompressionZlib {
+		zr, err := zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		d, err := ioutil.ReadAll(zr)
+		if err != nil {
+			return nil, err
+		}
+		if uint64(len(d)) != uncompressedLength {
+			return nil, fmt.Errorf("ktx2: the uncompressed size of the base level must be %d but %d", uncompressedLength, len(d))
+		}
+		data = d
+	}
+
+	w, h := int(width), int(height)
+	if n := graphicsdriver.CompressedTextureFormat(format).ByteSize(w, h); len(data) != n {
+		return nil, fmt.Errorf("ktx2: the size of the base level must be %d but %d", n, len(data))
+	}
+
+	return &Texture{
+		Width:  w,
+		Height: h,
+		Format: format,
+		Data:   data,
+	}, nil
+}
+
+// NewImage reads a KTX2 file from r and creates an image from it.
+//
+// If the texture format is supported by the current environment, the image is created with
+// ebiten.NewImageFromCompressedTexture, which can be used only as a rendering source.
+// Otherwise, the texture is decoded on CPU and a regular image is created.
+// If neither is possible, NewImage returns an error wrapping ErrUnsupported.
+//
+// NewImage must be called after the game starts, or NewImage panics.
+func NewImage(r io.Reader) (*ebiten.Image, error) {
+	t, err := Decode(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if ebiten.IsCompressedTextureFormatSupported(t.Format) {
+		return ebiten.NewImageFromCompressedTexture(t.Data, t.Width, t.Height, t.Format), nil
+	}
+
+	pix, err := decodePixels(t)
+	if err != nil {
+		return nil, err
+	}
+	img := ebiten.NewImage(t.Width, t.Height)
+	img.ReplacePixels(pix)
+	return img, nil
+}
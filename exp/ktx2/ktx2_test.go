@@ -0,0 +1,240 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ktx2_test
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/exp/ktx2"
+)
+
+const (
+	vkFormatBC1RGBAUNorm = 133
+	vkFormatBC3UNorm     = 137
+	vkFormatR8G8B8A8     = 37
+
+	supercompressionNone = 0
+	supercompressionZstd = 2
+	supercompressionZlib = 3
+)
+
+type ktx2File struct {
+	vkFormat           uint32
+	width              uint32
+	height             uint32
+	depth              uint32
+	layerCount         uint32
+	faceCount          uint32
+	scheme             uint32
+	data               []byte
+	uncompressedLength int
+}
+
+// bytes returns the KTX2 file with one level.
+func (f *ktx2File) bytes() []byte {
+	const headerSize = 12 + 9*4 + 4*4 + 2*8
+	const levelIndexSize = 3 * 8
+
+	var buf bytes.Buffer
+	buf.Write([]byte{0xab, 'K', 'T', 'X', ' ', '2', '0', 0xbb, '\r', '\n', 0x1a, '\n'})
+	le := binary.LittleEndian
+	for _, v := range []uint32{f.vkFormat, 1, f.width, f.height, f.depth, f.layerCount, f.faceCount, 1, f.scheme} {
+		binary.Write(&buf, le, v)
+	}
+	// The data format descriptor and the key/value data.
+	for i := 0; i < 4; i++ {
+		binary.Write(&buf, le, uint32(0))
+	}
+	// The supercompression global data.
+	for i := 0; i < 2; i++ {
+		binary.Write(&buf, le, uint64(0))
+	}
+	// The level index.
+	binary.Write(&buf, le, uint64(headerSize+levelIndexSize))
+	binary.Write(&buf, le, uint64(len(f.data)))
+	binary.Write(&buf, le, uint64(f.uncompressedLength))
+	buf.Write(f.data)
+	return buf.Bytes()
+}
+
+// newBC1File returns a KTX2 file holding a BC1 texture of the given size with the given data.
+func newBC1File(width, height int, data []byte) *ktx2File {
+	return &ktx2File{
+		vkFormat:           vkFormatBC1RGBAUNorm,
+		width:              uint32(width),
+		height:             uint32(height),
+		faceCount:          1,
+		scheme:             supercompressionNone,
+		data:               data,
+		uncompressedLength: len(data),
+	}
+}
+
+// bc1Data returns BC1 blocks for a texture of the given size. A BC1 block is 8 bytes for 4x4 pixels.
+func bc1Data(width, height int) []byte {
+	n := ((width + 3) / 4) * ((height + 3) / 4) * 8
+	data := make([]byte, n)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	return data
+}
+
+func TestDecode(t *testing.T) {
+	data := bc1Data(8, 6)
+	tex, err := ktx2.Decode(bytes.NewReader(newBC1File(8, 6, data).bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := tex.Width, 8; got != want {
+		t.Errorf("Width: got: %d, want: %d", got, want)
+	}
+	if got, want := tex.Height, 6; got != want {
+		t.Errorf("Height: got: %d, want: %d", got, want)
+	}
+	if got, want := tex.Format, ebiten.CompressedTextureFormatBC1; got != want {
+		t.Errorf("Format: got: %v, want: %v", got, want)
+	}
+	if !bytes.Equal(tex.Data, data) {
+		t.Errorf("Data: got: %v, want: %v", tex.Data, data)
+	}
+}
+
+func TestDecodeZlib(t *testing.T) {
+	data := bc1Data(16, 16)
+
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f := newBC1File(16, 16, buf.Bytes())
+	f.scheme = supercompressionZlib
+	f.uncompressedLength = len(data)
+	tex, err := ktx2.Decode(bytes.NewReader(f.bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(tex.Data, data) {
+		t.Errorf("Data: got: %v, want: %v", tex.Data, data)
+	}
+
+	// The uncompressed length in the level index must match.
+	f.uncompressedLength = len(data) + 1
+	if _, err := ktx2.Decode(bytes.NewReader(f.bytes())); err == nil {
+		t.Errorf("Decode must fail with a wrong uncompressed length")
+	}
+}
+
+func TestDecodeTruncated(t *testing.T) {
+	bs := newBC1File(8, 8, bc1Data(8, 8)).bytes()
+	// Truncate the file in the header, in the level index, and in the data.
+	for _, n := range []int{0, 11, 12, 79, 80, 103, 104, len(bs) - 1} {
+		if _, err := ktx2.Decode(bytes.NewReader(bs[:n])); err == nil {
+			t.Errorf("Decode with %d bytes out of %d must fail", n, len(bs))
+		}
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	bs := newBC1File(8, 8, bc1Data(8, 8)).bytes()
+	bs[1] = 'k'
+	if _, err := ktx2.Decode(bytes.NewReader(bs)); err == nil || errors.Is(err, ktx2.ErrUnsupported) {
+		t.Errorf("Decode with a wrong identifier must fail without ErrUnsupported but: %v", err)
+	}
+
+	// The data size must match the format and the size.
+	f := newBC1File(8, 8, bc1Data(8, 4))
+	if _, err := ktx2.Decode(bytes.NewReader(f.bytes())); err == nil || errors.Is(err, ktx2.ErrUnsupported) {
+		t.Errorf("Decode with a wrong data size must fail without ErrUnsupported but: %v", err)
+	}
+}
+
+func TestDecodeUnsupported(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(f *ktx2File)
+	}{
+		{
+			name: "uncompressed format",
+			modify: func(f *ktx2File) {
+				f.vkFormat = vkFormatR8G8B8A8
+			},
+		},
+		{
+			name: "UASTC",
+			modify: func(f *ktx2File) {
+				f.vkFormat = 0
+			},
+		},
+		{
+			name: "Zstandard",
+			modify: func(f *ktx2File) {
+				f.scheme = supercompressionZstd
+			},
+		},
+		{
+			name: "3D texture",
+			modify: func(f *ktx2File) {
+				f.depth = 2
+			},
+		},
+		{
+			name: "texture array",
+			modify: func(f *ktx2File) {
+				f.layerCount = 2
+			},
+		},
+		{
+			name: "cube map",
+			modify: func(f *ktx2File) {
+				f.faceCount = 6
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			f := newBC1File(8, 8, bc1Data(8, 8))
+			tc.modify(f)
+			if _, err := ktx2.Decode(bytes.NewReader(f.bytes())); !errors.Is(err, ktx2.ErrUnsupported) {
+				t.Errorf("got: %v, want: %v", err, ktx2.ErrUnsupported)
+			}
+		})
+	}
+}
+
+func TestDecodeBC3(t *testing.T) {
+	// A BC3 block is 16 bytes for 4x4 pixels.
+	data := make([]byte, 2*2*16)
+	f := newBC1File(5, 8, data)
+	f.vkFormat = vkFormatBC3UNorm
+	tex, err := ktx2.Decode(bytes.NewReader(f.bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := tex.Format, ebiten.CompressedTextureFormatBC3; got != want {
+		t.Errorf("Format: got: %v, want: %v", got, want)
+	}
+}
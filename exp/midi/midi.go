@@ -0,0 +1,341 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package midi offers MIDI input from the attached devices, e.g., for rhythm games and music toys.
+//
+// MIDI is available on Windows (WinMM), macOS and iOS (CoreMIDI), Linux (ALSA raw MIDI devices), and browsers
+// supporting Web MIDI. On the other environments, no device is available.
+//
+// The devices are started to be watched when any function in this package is called for the first time.
+// Devices attached after that are detected automatically.
+//
+// In browsers, Web MIDI might require a permission from the user.
+//
+// This package is experimental and the API might be changed in the future.
+package midi
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// DeviceID represents a MIDI input device.
+type DeviceID int
+
+// EventType represents a type of a MIDI event.
+type EventType int
+
+// EventTypes
+const (
+	// EventTypeNoteOn is a note-on message. A note-on message with velocity 0 is treated as EventTypeNoteOff.
+	EventTypeNoteOn EventType = iota
+
+	// EventTypeNoteOff is a note-off message.
+	EventTypeNoteOff
+
+	// EventTypeControlChange is a control change message.
+	EventTypeControlChange
+)
+
+// Event represents a MIDI event.
+type Event struct {
+	// Type is the type of the event.
+	Type EventType
+
+	// DeviceID is the device that sent the event.
+	DeviceID DeviceID
+
+	// Channel is the MIDI channel in [0, 15].
+	Channel int
+
+	// Note is the note number in [0, 127] for EventTypeNoteOn and EventTypeNoteOff.
+	Note int
+
+	// Velocity is the velocity in [0, 127] for EventTypeNoteOn and EventTypeNoteOff.
+	Velocity int
+
+	// Controller is the controller number in [0, 127] for EventTypeControlChange.
+	Controller int
+
+	// Value is the controller value in [0, 127] for EventTypeControlChange.
+	Value int
+
+	// Time is the time when the event was received, measured by the clock set by SetClock.
+	Time time.Duration
+}
+
+// maxEventCount is the maximum number of queued events. The oldest events are discarded beyond this.
+const maxEventCount = 4096
+
+// AppendDevices appends the attached MIDI input devices to devices, and returns the extended buffer.
+//
+// AppendDevices is concurrent-safe.
+func AppendDevices(devices []DeviceID) []DeviceID {
+	theState.start()
+
+	theState.m.Lock()
+	defer theState.m.Unlock()
+	n := len(devices)
+	for _, d := range theState.devices {
+		devices = append(devices, d.id)
+	}
+	sort.Slice(devices[n:], func(i, j int) bool {
+		return devices[n+i] < devices[n+j]
+	})
+	return devices
+}
+
+// DeviceName returns the name of the device.
+//
+// DeviceName returns an empty string when the device is not attached.
+//
+// DeviceName is concurrent-safe.
+func DeviceName(id DeviceID) string {
+	theState.start()
+
+	theState.m.Lock()
+	defer theState.m.Unlock()
+	if d, ok := theState.devices[id]; ok {
+		return d.name
+	}
+	return ""
+}
+
+// AppendEvents appends the events received since the previous call of AppendEvents to events,
+// and returns the extended buffer.
+//
+// The received events are queued until AppendEvents is called.
+// If too many events are queued, the oldest events are discarded.
+//
+// AppendEvents is concurrent-safe.
+func AppendEvents(events []Event) []Event {
+	theState.start()
+	update()
+
+	theState.m.Lock()
+	defer theState.m.Unlock()
+	return theState.events.appendAndClear(events)
+}
+
+// SetClock sets the clock to timestamp the events.
+//
+// For example, specifying an audio player's Current method as the clock timestamps the events against the audio
+// position, which is useful for rhythm games.
+// clock must be concurrent-safe as the events can be received on another goroutine.
+//
+// If clock is nil, the default clock is used. The default clock returns the duration since the package is
+// initialized.
+//
+// SetClock is concurrent-safe.
+func SetClock(clock func() time.Duration) {
+	theState.m.Lock()
+	defer theState.m.Unlock()
+	theState.clock = clock
+}
+
+type device struct {
+	id     DeviceID
+	name   string
+	parser parser
+}
+
+type state struct {
+	devices      map[DeviceID]*device
+	nextDeviceID DeviceID
+	events       eventQueue
+	clock        func() time.Duration
+
+	once sync.Once
+	m    sync.Mutex
+}
+
+var (
+	theState = state{
+		devices: map[DeviceID]*device{},
+	}
+	startTime = time.Now()
+)
+
+func (s *state) start() {
+	s.once.Do(start)
+}
+
+// now returns the current time of the clock.
+func (s *state) now() time.Duration {
+	s.m.Lock()
+	c := s.clock
+	s.m.Unlock()
+
+	// Call the clock out of the lock, as the clock might take another lock.
+	if c != nil {
+		return c()
+	}
+	return time.Since(startTime)
+}
+
+// newDeviceID returns a new device ID. The device is not available until addDevice is called.
+func (s *state) newDeviceID() DeviceID {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	id := s.nextDeviceID
+	s.nextDeviceID++
+	return id
+}
+
+func (s *state) addDevice(id DeviceID, name string) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.devices[id] = &device{
+		id:   id,
+		name: name,
+	}
+}
+
+func (s *state) removeDevice(id DeviceID) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	delete(s.devices, id)
+}
+
+// receive parses the raw MIDI bytes from the device and queues the events.
+func (s *state) receive(id DeviceID, data []byte, t time.Duration) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	d, ok := s.devices[id]
+	if !ok {
+		return
+	}
+	for _, b := range data {
+		e, ok := d.parser.feed(b)
+		if !ok {
+			continue
+		}
+		e.DeviceID = id
+		e.Time = t
+		s.events.push(e)
+	}
+}
+
+// eventQueue is a ring buffer of events.
+// When the queue is full, the oldest event is overwritten.
+type eventQueue struct {
+	events []Event
+	head   int
+	n      int
+}
+
+func (q *eventQueue) push(e Event) {
+	if q.events == nil {
+		q.events = make([]Event, maxEventCount)
+	}
+	idx := (q.head + q.n) % len(q.events)
+	q.events[idx] = e
+	if q.n < len(q.events) {
+		q.n++
+		return
+	}
+	q.head = (q.head + 1) % len(q.events)
+}
+
+// appendAndClear appends the queued events in order to events, clears the queue, and returns the extended buffer.
+func (q *eventQueue) appendAndClear(events []Event) []Event {
+	if q.n == 0 {
+		return events
+	}
+	if end := q.head + q.n; end <= len(q.events) {
+		events = append(events, q.events[q.head:end]...)
+	} else {
+		events = append(events, q.events[q.head:]...)
+		events = append(events, q.events[:end-len(q.events)]...)
+	}
+	q.head = 0
+	q.n = 0
+	return events
+}
+
+// parser parses a MIDI byte stream including running status.
+type parser struct {
+	status byte
+	data   [2]byte
+	n      int
+}
+
+// feed feeds a byte and returns an event when a note or control change message is completed.
+func (p *parser) feed(b byte) (Event, bool) {
+	switch {
+	case b >= 0xf8:
+		// System real-time messages can appear anywhere and don't affect the running status.
+		return Event{}, false
+	case b >= 0xf0:
+		// System common messages and system exclusive messages cancel the running status.
+		// Their data bytes are ignored.
+		p.status = 0
+		p.n = 0
+		return Event{}, false
+	case b >= 0x80:
+		p.status = b
+		p.n = 0
+		return Event{}, false
+	}
+
+	if p.status == 0 {
+		return Event{}, false
+	}
+
+	size := 2
+	switch p.status & 0xf0 {
+	case 0xc0, 0xd0:
+		size = 1
+	}
+	p.data[p.n] = b
+	p.n++
+	if p.n < size {
+		return Event{}, false
+	}
+	p.n = 0
+
+	ch := int(p.status & 0x0f)
+	switch p.status & 0xf0 {
+	case 0x80:
+		return Event{
+			Type:     EventTypeNoteOff,
+			Channel:  ch,
+			Note:     int(p.data[0]),
+			Velocity: int(p.data[1]),
+		}, true
+	case 0x90:
+		t := EventTypeNoteOn
+		if p.data[1] == 0 {
+			t = EventTypeNoteOff
+		}
+		return Event{
+			Type:     t,
+			Channel:  ch,
+			Note:     int(p.data[0]),
+			Velocity: int(p.data[1]),
+		}, true
+	case 0xb0:
+		return Event{
+			Type:       EventTypeControlChange,
+			Channel:    ch,
+			Controller: int(p.data[0]),
+			Value:      int(p.data[1]),
+		}, true
+	}
+	return Event{}, false
+}
@@ -0,0 +1,232 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !ebitencbackend
+// +build !ebitencbackend
+
+package midi
+
+// #cgo LDFLAGS: -framework CoreMIDI -framework CoreFoundation
+//
+// #include <CoreMIDI/CoreMIDI.h>
+// #include <mach/mach_time.h>
+// #include <pthread.h>
+// #include <string.h>
+//
+// // The received packets are stored in the buffer as a sequence of a header and data bytes,
+// // and read from Go periodically.
+// typedef struct {
+//   uint64_t timestamp;
+//   int32_t device;
+//   int32_t length;
+// } packetHeader;
+//
+// #define BUFFER_SIZE 65536
+//
+// static pthread_mutex_t mutex = PTHREAD_MUTEX_INITIALIZER;
+// static uint8_t buffer[BUFFER_SIZE];
+// static size_t bufferLength;
+// static MIDIClientRef client;
+// static MIDIPortRef port;
+//
+// static void readProc(const MIDIPacketList* list, void* readProcRefCon, void* srcConnRefCon) {
+//   pthread_mutex_lock(&mutex);
+//   const MIDIPacket* packet = &list->packet[0];
+//   for (UInt32 i = 0; i < list->numPackets; i++) {
+//     size_t size = sizeof(packetHeader) + packet->length;
+//     // Discard the packet if the buffer is full.
+//     if (bufferLength + size <= BUFFER_SIZE) {
+//       packetHeader h;
+//       // A timestamp 0 means now.
+//       h.timestamp = packet->timeStamp ? packet->timeStamp : mach_absolute_time();
+//       h.device = (int32_t)(intptr_t)srcConnRefCon;
+//       h.length = packet->length;
+//       memcpy(buffer + bufferLength, &h, sizeof(h));
+//       memcpy(buffer + bufferLength + sizeof(h), packet->data, packet->length);
+//       bufferLength += size;
+//     }
+//     packet = MIDIPacketNext(packet);
+//   }
+//   pthread_mutex_unlock(&mutex);
+// }
+//
+// static int startMIDI(void) {
+//   if (MIDIClientCreate(CFSTR("Ebiten"), NULL, NULL, &client) != noErr) {
+//     return 0;
+//   }
+//   if (MIDIInputPortCreate(client, CFSTR("Ebiten Input"), readProc, NULL, &port) != noErr) {
+//     return 0;
+//   }
+//   return 1;
+// }
+//
+// static int sourceCount(void) {
+//   return (int)MIDIGetNumberOfSources();
+// }
+//
+// static int sourceUniqueID(int index, int32_t* uniqueID) {
+//   MIDIEndpointRef source = MIDIGetSource(index);
+//   if (!source) {
+//     return 0;
+//   }
+//   return MIDIObjectGetIntegerProperty(source, kMIDIPropertyUniqueID, uniqueID) == noErr;
+// }
+//
+// static void sourceName(int index, char* name, int length) {
+//   name[0] = '\0';
+//   MIDIEndpointRef source = MIDIGetSource(index);
+//   if (!source) {
+//     return;
+//   }
+//   CFStringRef str = NULL;
+//   if (MIDIObjectGetStringProperty(source, kMIDIPropertyDisplayName, &str) != noErr || !str) {
+//     return;
+//   }
+//   CFStringGetCString(str, name, length, kCFStringEncodingUTF8);
+//   CFRelease(str);
+// }
+//
+// static int connectSource(int index, int32_t device) {
+//   MIDIEndpointRef source = MIDIGetSource(index);
+//   if (!source) {
+//     return 0;
+//   }
+//   return MIDIPortConnectSource(port, source, (void*)(intptr_t)device) == noErr;
+// }
+//
+// static size_t readBuffer(uint8_t* dst, size_t length) {
+//   pthread_mutex_lock(&mutex);
+//   size_t n = bufferLength;
+//   if (n > length) {
+//     n = length;
+//   }
+//   memcpy(dst, buffer, n);
+//   // Copy only whole packets.
+//   size_t offset = 0;
+//   while (offset + sizeof(packetHeader) <= n) {
+//     packetHeader h;
+//     memcpy(&h, buffer + offset, sizeof(h));
+//     if (offset + sizeof(h) + h.length > n) {
+//       break;
+//     }
+//     offset += sizeof(h) + h.length;
+//   }
+//   memmove(buffer, buffer + offset, bufferLength - offset);
+//   bufferLength -= offset;
+//   pthread_mutex_unlock(&mutex);
+//   return offset;
+// }
+//
+// static int64_t nanosecondsSince(uint64_t t) {
+//   static mach_timebase_info_data_t info;
+//   if (info.denom == 0) {
+//     mach_timebase_info(&info);
+//   }
+//   uint64_t now = mach_absolute_time();
+//   if (now < t) {
+//     return 0;
+//   }
+//   return (int64_t)((now - t) * info.numer / info.denom);
+// }
+import "C"
+
+import (
+	"encoding/binary"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+const packetHeaderSize = 16
+
+var (
+	readBuf   = make([]byte, 65536)
+	readBufM  sync.Mutex
+	startedOK bool
+)
+
+func start() {
+	if C.startMIDI() == 0 {
+		return
+	}
+	startedOK = true
+	go watch()
+}
+
+func update() {
+	if !startedOK {
+		return
+	}
+
+	readBufM.Lock()
+	defer readBufM.Unlock()
+
+	n := int(C.readBuffer((*C.uint8_t)(unsafe.Pointer(&readBuf[0])), C.size_t(len(readBuf))))
+	now := theState.now()
+	buf := readBuf[:n]
+	for len(buf) >= packetHeaderSize {
+		ts := binary.LittleEndian.Uint64(buf)
+		id := DeviceID(int32(binary.LittleEndian.Uint32(buf[8:])))
+		l := int(int32(binary.LittleEndian.Uint32(buf[12:])))
+		buf = buf[packetHeaderSize:]
+
+		// Adjust the time by the delay from when the packet was received.
+		t := now - time.Duration(C.nanosecondsSince(C.uint64_t(ts)))
+		theState.receive(id, buf[:l], t)
+		buf = buf[l:]
+	}
+}
+
+// watch enumerates the MIDI sources periodically, and connects new sources.
+func watch() {
+	ids := map[int32]DeviceID{}
+	name := make([]byte, 256)
+	for {
+		current := map[int32]struct{}{}
+		for i := 0; i < int(C.sourceCount()); i++ {
+			var uid C.int32_t
+			if C.sourceUniqueID(C.int(i), &uid) == 0 {
+				continue
+			}
+			current[int32(uid)] = struct{}{}
+			if _, ok := ids[int32(uid)]; ok {
+				continue
+			}
+
+			// Add the device before connecting it, or the first packets would be dropped as from an unknown device.
+			id := theState.newDeviceID()
+			C.sourceName(C.int(i), (*C.char)(unsafe.Pointer(&name[0])), C.int(len(name)))
+			theState.addDevice(id, C.GoString((*C.char)(unsafe.Pointer(&name[0]))))
+			if C.connectSource(C.int(i), C.int32_t(id)) == 0 {
+				theState.removeDevice(id)
+				continue
+			}
+			ids[int32(uid)] = id
+		}
+
+		// A removed source is disconnected automatically.
+		for uid, id := range ids {
+			if _, ok := current[uid]; ok {
+				continue
+			}
+			theState.removeDevice(id)
+			delete(ids, uid)
+		}
+
+		// Read the buffer even when the user doesn't read the events, so that the buffer doesn't overflow.
+		update()
+
+		time.Sleep(time.Second)
+	}
+}
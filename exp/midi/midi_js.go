@@ -0,0 +1,107 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package midi
+
+import (
+	"syscall/js"
+	"time"
+)
+
+func update() {
+	// The events are queued by the event handlers.
+}
+
+func start() {
+	navigator := js.Global().Get("navigator")
+	performance := js.Global().Get("performance")
+	if !navigator.Truthy() || navigator.Get("requestMIDIAccess").Type() != js.TypeFunction {
+		// Web MIDI is not supported.
+		return
+	}
+
+	then := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		access := args[0]
+		ids := map[string]DeviceID{}
+		inputs := map[string]js.Value{}
+		handlers := map[string]js.Func{}
+
+		update := func() {
+			current := map[string]struct{}{}
+			f := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+				input := args[0]
+				if input.Get("state").String() != "connected" {
+					return nil
+				}
+				key := input.Get("id").String()
+				current[key] = struct{}{}
+				if _, ok := ids[key]; ok {
+					return nil
+				}
+
+				id := theState.newDeviceID()
+				theState.addDevice(id, input.Get("name").String())
+				h := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+					e := args[0]
+					data := e.Get("data")
+					buf := make([]byte, data.Get("length").Int())
+					js.CopyBytesToGo(buf, data)
+
+					// timeStamp is based on performance.now(). Adjust the time by the delay of the event.
+					t := theState.now()
+					if ts := e.Get("timeStamp"); ts.Type() == js.TypeNumber {
+						if d := time.Duration((performance.Call("now").Float() - ts.Float()) * float64(time.Millisecond)); d > 0 {
+							t -= d
+						}
+					}
+					theState.receive(id, buf, t)
+					return nil
+				})
+				input.Set("onmidimessage", h)
+				ids[key] = id
+				inputs[key] = input
+				handlers[key] = h
+				return nil
+			})
+			access.Get("inputs").Call("forEach", f)
+			f.Release()
+
+			for key, id := range ids {
+				if _, ok := current[key]; ok {
+					continue
+				}
+				theState.removeDevice(id)
+				inputs[key].Set("onmidimessage", nil)
+				handlers[key].Release()
+				delete(ids, key)
+				delete(inputs, key)
+				delete(handlers, key)
+			}
+		}
+
+		update()
+		access.Set("onstatechange", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			update()
+			return nil
+		}))
+		return nil
+	})
+
+	catch := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		js.Global().Get("console").Call("warn", "midi: requesting MIDI access failed:", args[0])
+		return nil
+	})
+
+	navigator.Call("requestMIDIAccess").Call("then", then).Call("catch", catch)
+}
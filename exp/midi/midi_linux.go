@@ -0,0 +1,113 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !android && !ebitencbackend
+// +build !android,!ebitencbackend
+
+package midi
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+func update() {
+	// The events are queued by the goroutines reading the devices.
+}
+
+func start() {
+	go watch()
+}
+
+// watch scans the ALSA raw MIDI devices periodically, and starts reading new devices.
+func watch() {
+	opened := map[string]struct{}{}
+	closed := make(chan string)
+	for {
+		// Read the notifications of the closed devices.
+	loop:
+		for {
+			select {
+			case path := <-closed:
+				delete(opened, path)
+			default:
+				break loop
+			}
+		}
+
+		paths, _ := filepath.Glob("/dev/snd/midiC*D*")
+		for _, path := range paths {
+			if _, ok := opened[path]; ok {
+				continue
+			}
+			f, err := os.Open(path)
+			if err != nil {
+				// The device might not be accessible due to the permission.
+				continue
+			}
+			opened[path] = struct{}{}
+			id := theState.newDeviceID()
+			theState.addDevice(id, deviceName(path))
+			go func(path string) {
+				read(f, id)
+				f.Close()
+				theState.removeDevice(id)
+				closed <- path
+			}(path)
+		}
+
+		time.Sleep(time.Second)
+	}
+}
+
+func read(f *os.File, id DeviceID) {
+	buf := make([]byte, 256)
+	for {
+		n, err := f.Read(buf)
+		if n > 0 {
+			theState.receive(id, buf[:n], theState.now())
+		}
+		if err != nil {
+			// The device was removed.
+			return
+		}
+	}
+}
+
+// deviceName returns the name of the raw MIDI device at path like /dev/snd/midiC1D0.
+func deviceName(path string) string {
+	var card, dev int
+	if _, err := fmt.Sscanf(filepath.Base(path), "midiC%dD%d", &card, &dev); err != nil {
+		return filepath.Base(path)
+	}
+
+	// The first line of /proc/asound/cardX/midiY is the name of the device.
+	f, err := os.Open(fmt.Sprintf("/proc/asound/card%d/midi%d", card, dev))
+	if err != nil {
+		return filepath.Base(path)
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	if s.Scan() {
+		if name := strings.TrimSpace(s.Text()); name != "" {
+			return name
+		}
+	}
+	return filepath.Base(path)
+}
@@ -0,0 +1,154 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package midi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParserFeed(t *testing.T) {
+	cases := []struct {
+		Name  string
+		Input []byte
+		Want  []Event
+	}{
+		{
+			Name:  "note on",
+			Input: []byte{0x91, 60, 100},
+			Want: []Event{
+				{Type: EventTypeNoteOn, Channel: 1, Note: 60, Velocity: 100},
+			},
+		},
+		{
+			Name:  "note on with velocity 0",
+			Input: []byte{0x90, 60, 0},
+			Want: []Event{
+				{Type: EventTypeNoteOff, Channel: 0, Note: 60, Velocity: 0},
+			},
+		},
+		{
+			Name:  "note off",
+			Input: []byte{0x8f, 61, 64},
+			Want: []Event{
+				{Type: EventTypeNoteOff, Channel: 15, Note: 61, Velocity: 64},
+			},
+		},
+		{
+			Name:  "control change",
+			Input: []byte{0xb2, 7, 127},
+			Want: []Event{
+				{Type: EventTypeControlChange, Channel: 2, Controller: 7, Value: 127},
+			},
+		},
+		{
+			Name:  "running status",
+			Input: []byte{0x90, 60, 100, 62, 100, 64, 0},
+			Want: []Event{
+				{Type: EventTypeNoteOn, Note: 60, Velocity: 100},
+				{Type: EventTypeNoteOn, Note: 62, Velocity: 100},
+				{Type: EventTypeNoteOff, Note: 64, Velocity: 0},
+			},
+		},
+		{
+			Name:  "real-time message in a message",
+			Input: []byte{0x90, 60, 0xf8, 100, 0xfe, 62, 100},
+			Want: []Event{
+				{Type: EventTypeNoteOn, Note: 60, Velocity: 100},
+				{Type: EventTypeNoteOn, Note: 62, Velocity: 100},
+			},
+		},
+		{
+			Name:  "system exclusive cancels running status",
+			Input: []byte{0x90, 60, 100, 0xf0, 1, 2, 3, 0xf7, 62, 100},
+			Want: []Event{
+				{Type: EventTypeNoteOn, Note: 60, Velocity: 100},
+			},
+		},
+		{
+			Name:  "program change with running status",
+			Input: []byte{0xc0, 1, 2, 0x90, 60, 100},
+			Want: []Event{
+				{Type: EventTypeNoteOn, Note: 60, Velocity: 100},
+			},
+		},
+		{
+			Name:  "data bytes without status",
+			Input: []byte{60, 100, 0x90, 60, 100},
+			Want: []Event{
+				{Type: EventTypeNoteOn, Note: 60, Velocity: 100},
+			},
+		},
+		{
+			Name:  "incomplete message interrupted by a new status",
+			Input: []byte{0x90, 60, 0xb0, 7, 127},
+			Want: []Event{
+				{Type: EventTypeControlChange, Controller: 7, Value: 127},
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			var p parser
+			var got []Event
+			for _, b := range c.Input {
+				if e, ok := p.feed(b); ok {
+					got = append(got, e)
+				}
+			}
+			if !reflect.DeepEqual(got, c.Want) {
+				t.Errorf("got %v, want %v", got, c.Want)
+			}
+		})
+	}
+}
+
+func TestEventQueue(t *testing.T) {
+	var q eventQueue
+	if got := q.appendAndClear(nil); len(got) != 0 {
+		t.Errorf("got %v, want empty", got)
+	}
+
+	for i := 0; i < 3; i++ {
+		q.push(Event{Note: i})
+	}
+	got := q.appendAndClear(nil)
+	if len(got) != 3 {
+		t.Fatalf("len(got): got %d, want 3", len(got))
+	}
+	for i, e := range got {
+		if e.Note != i {
+			t.Errorf("got[%d].Note: got %d, want %d", i, e.Note, i)
+		}
+	}
+
+	// When the queue is full, the oldest events are discarded.
+	const extra = 10
+	for i := 0; i < maxEventCount+extra; i++ {
+		q.push(Event{Note: i})
+	}
+	got = q.appendAndClear(got[:0])
+	if len(got) != maxEventCount {
+		t.Fatalf("len(got): got %d, want %d", len(got), maxEventCount)
+	}
+	for i, e := range got {
+		if want := i + extra; e.Note != want {
+			t.Fatalf("got[%d].Note: got %d, want %d", i, e.Note, want)
+		}
+	}
+	if got := q.appendAndClear(nil); len(got) != 0 {
+		t.Errorf("got %v, want empty", got)
+	}
+}
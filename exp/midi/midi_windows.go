@@ -0,0 +1,174 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !ebitencbackend
+// +build !ebitencbackend
+
+package midi
+
+import (
+	"fmt"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+const (
+	_CALLBACK_FUNCTION = 0x00030000
+
+	_MAXPNAMELEN = 32
+
+	_MIM_DATA = 0x3c3
+
+	_MMSYSERR_NOERROR = 0
+)
+
+type _MIDIINCAPSW struct {
+	wMid           uint16
+	wPid           uint16
+	vDriverVersion uint32
+	szPname        [_MAXPNAMELEN]uint16
+	dwSupport      uint32
+}
+
+var (
+	winmm = windows.NewLazySystemDLL("winmm.dll")
+
+	procMidiInClose       = winmm.NewProc("midiInClose")
+	procMidiInGetDevCapsW = winmm.NewProc("midiInGetDevCapsW")
+	procMidiInGetNumDevs  = winmm.NewProc("midiInGetNumDevs")
+	procMidiInOpen        = winmm.NewProc("midiInOpen")
+	procMidiInReset       = winmm.NewProc("midiInReset")
+	procMidiInStart       = winmm.NewProc("midiInStart")
+)
+
+func _midiInClose(hmi windows.Handle) {
+	procMidiInClose.Call(uintptr(hmi))
+}
+
+func _midiInGetDevCaps(uDeviceID int) (_MIDIINCAPSW, error) {
+	var caps _MIDIINCAPSW
+	r, _, _ := procMidiInGetDevCapsW.Call(uintptr(uDeviceID), uintptr(unsafe.Pointer(&caps)), unsafe.Sizeof(caps))
+	if r != _MMSYSERR_NOERROR {
+		return _MIDIINCAPSW{}, fmt.Errorf("midi: midiInGetDevCapsW failed: %d", r)
+	}
+	return caps, nil
+}
+
+func _midiInGetNumDevs() int {
+	r, _, _ := procMidiInGetNumDevs.Call()
+	return int(r)
+}
+
+func _midiInOpen(uDeviceID int, dwCallback uintptr, dwInstance uintptr, fdwOpen uint32) (windows.Handle, error) {
+	var hmi windows.Handle
+	r, _, _ := procMidiInOpen.Call(uintptr(unsafe.Pointer(&hmi)), uintptr(uDeviceID), dwCallback, dwInstance, uintptr(fdwOpen))
+	if r != _MMSYSERR_NOERROR {
+		return 0, fmt.Errorf("midi: midiInOpen failed: %d", r)
+	}
+	return hmi, nil
+}
+
+func _midiInReset(hmi windows.Handle) {
+	procMidiInReset.Call(uintptr(hmi))
+}
+
+func _midiInStart(hmi windows.Handle) error {
+	r, _, _ := procMidiInStart.Call(uintptr(hmi))
+	if r != _MMSYSERR_NOERROR {
+		return fmt.Errorf("midi: midiInStart failed: %d", r)
+	}
+	return nil
+}
+
+var midiInProc = windows.NewCallback(func(hMidiIn windows.Handle, wMsg uint32, dwInstance uintptr, dwParam1 uintptr, dwParam2 uintptr) uintptr {
+	if wMsg != _MIM_DATA {
+		return 0
+	}
+	// dwParam1 is a packed short message. The unused bytes are ignored as the parser knows the message size.
+	data := []byte{byte(dwParam1), byte(dwParam1 >> 8), byte(dwParam1 >> 16)}
+	theState.receive(DeviceID(dwInstance), data, theState.now())
+	return 0
+})
+
+type winmmDevice struct {
+	handle windows.Handle
+	id     DeviceID
+}
+
+func update() {
+	// The events are queued by the event handlers.
+}
+
+func start() {
+	go watch()
+}
+
+// watch enumerates the MIDI input devices periodically, and opens new devices.
+//
+// WinMM identifies the devices by their indices, which change when a device is removed.
+// Then, a device is also identified by its name.
+func watch() {
+	opened := map[string]winmmDevice{}
+	for {
+		current := map[string]int{}
+		for i := 0; i < _midiInGetNumDevs(); i++ {
+			caps, err := _midiInGetDevCaps(i)
+			if err != nil {
+				continue
+			}
+			name := windows.UTF16ToString(caps.szPname[:])
+			current[fmt.Sprintf("%d:%s", i, name)] = i
+		}
+
+		for key, d := range opened {
+			if _, ok := current[key]; ok {
+				continue
+			}
+			_midiInReset(d.handle)
+			_midiInClose(d.handle)
+			theState.removeDevice(d.id)
+			delete(opened, key)
+		}
+
+		for key, index := range current {
+			if _, ok := opened[key]; ok {
+				continue
+			}
+			caps, err := _midiInGetDevCaps(index)
+			if err != nil {
+				continue
+			}
+			id := theState.newDeviceID()
+			h, err := _midiInOpen(index, midiInProc, uintptr(id), _CALLBACK_FUNCTION)
+			if err != nil {
+				// The device might be used by another application.
+				continue
+			}
+			theState.addDevice(id, windows.UTF16ToString(caps.szPname[:]))
+			if err := _midiInStart(h); err != nil {
+				_midiInClose(h)
+				theState.removeDevice(id)
+				continue
+			}
+			opened[key] = winmmDevice{
+				handle: h,
+				id:     id,
+			}
+		}
+
+		time.Sleep(time.Second)
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package native offers the native handles of the window and the graphics device to interoperate with other
+// libraries, e.g., overlays, native menus, or rendering by other graphics code.
+//
+// The handles are owned by Ebiten. Do not destroy or release them.
+//
+// This package is experimental and the API might be changed in the future.
+package native
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicscommand"
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
+	"github.com/hajimehoshi/ebiten/v2/internal/hooks"
+	"github.com/hajimehoshi/ebiten/v2/internal/ui"
+)
+
+// WindowKind represents the kind of a native window.
+type WindowKind int
+
+const (
+	// WindowKindNone represents that there is no native window, e.g., on browsers and mobiles.
+	WindowKindNone WindowKind = WindowKind(ui.NativeWindowKindNone)
+
+	// WindowKindWin32 represents a Win32 window on Windows.
+	WindowKindWin32 WindowKind = WindowKind(ui.NativeWindowKindWin32)
+
+	// WindowKindCocoa represents a Cocoa window on macOS.
+	WindowKindCocoa WindowKind = WindowKind(ui.NativeWindowKindCocoa)
+
+	// WindowKindX11 represents an X11 window on Linux and BSDs.
+	// On Wayland sessions, the window is an XWayland window.
+	WindowKindX11 WindowKind = WindowKind(ui.NativeWindowKindX11)
+)
+
+// Window represents the handles of a native window.
+type Window struct {
+	// Kind is the kind of the window.
+	Kind WindowKind
+
+	// Handle is HWND for Win32, NSWindow* for Cocoa, and Window for X11.
+	Handle uintptr
+
+	// Display is Display* for X11, and 0 for the others.
+	Display uintptr
+}
+
+// CurrentWindow returns the handles of the window of the game.
+//
+// CurrentWindow returns false as ok when the window is not created yet or there is no native window.
+//
+// A Cocoa window must be used on the main thread. Use RunWithGraphics or the native API for
+// dispatching to the main thread.
+//
+// CurrentWindow is concurrent-safe.
+func CurrentWindow() (window Window, ok bool) {
+	w := ui.Get().NativeWindow()
+	if w.Kind == ui.NativeWindowKindNone {
+		return Window{}, false
+	}
+	return Window{
+		Kind:    WindowKind(w.Kind),
+		Handle:  w.Window,
+		Display: w.Display,
+	}, true
+}
+
+// GraphicsDevice represents the handles of the native graphics device.
+type GraphicsDevice struct {
+	// Library is the graphics library in use.
+	Library ebiten.GraphicsLibrary
+
+	// Device is id<MTLDevice> for Metal, and 0 for the others.
+	Device uintptr
+
+	// CommandQueue is id<MTLCommandQueue> for Metal, and 0 for the others.
+	CommandQueue uintptr
+}
+
+// CurrentGraphicsDevice returns the handles of the graphics device.
+//
+// OpenGL doesn't have handles. Use RunWithGraphics to call OpenGL functions with Ebiten's context.
+//
+// CurrentGraphicsDevice must be called from Update or Draw of ebiten.Game.
+func CurrentGraphicsDevice() GraphicsDevice {
+	d, q := graphicscommand.NativeGraphicsHandles()
+	return GraphicsDevice{
+		Library:      ebiten.CurrentGraphicsLibrary(),
+		Device:       d,
+		CommandQueue: q,
+	}
+}
+
+// RunWithGraphics calls f at a synchronization point with Ebiten's rendering.
+//
+// All the rendering requested so far, e.g., DrawImage calls, is submitted before f is called.
+// f is called on the thread where Ebiten renders, which is the main thread on desktops.
+//
+// With Metal, Ebiten's command buffer is already committed to the command queue when f is called.
+// Command buffers committed to the same queue in f are executed after Ebiten's rendering.
+//
+// With OpenGL, Ebiten's OpenGL context is current in f. Ebiten rebinds its objects after f, and
+// f can change bindings like textures, framebuffers, programs, and buffers freely.
+// f must not delete Ebiten's objects.
+//
+// RunWithGraphics must be called from Update or Draw of ebiten.Game.
+func RunWithGraphics(f func()) error {
+	return graphicscommand.RunWithNativeGraphics(f)
+}
+
+// TextureKind represents the kind of a native texture.
+type TextureKind int
+
+const (
+	// TextureKindOpenGL represents an OpenGL texture name of GL_TEXTURE_2D with the RGBA format.
+	// This is available with OpenGL and OpenGL ES, but not with WebGL.
+	TextureKindOpenGL TextureKind = TextureKind(graphicsdriver.NativeTextureKindOpenGL)
+
+	// TextureKindMetal represents id<MTLTexture>.
+	// This is available with Metal.
+	TextureKindMetal TextureKind = TextureKind(graphicsdriver.NativeTextureKindMetal)
+
+	// TextureKindIOSurface represents IOSurfaceRef with the BGRA8 format, e.g., kCVPixelFormatType_32BGRA.
+	// This is available with Metal.
+	TextureKindIOSurface TextureKind = TextureKind(graphicsdriver.NativeTextureKindIOSurface)
+)
+
+// Texture represents a handle of a texture created by a native graphics API.
+type Texture struct {
+	// Kind is the kind of the texture.
+	Kind TextureKind
+
+	// Handle is GLuint for OpenGL, id<MTLTexture> for Metal, and IOSurfaceRef for IOSurface.
+	Handle uintptr
+}
+
+func (t Texture) isAvailable(library ebiten.GraphicsLibrary) bool {
+	switch t.Kind {
+	case TextureKindOpenGL:
+		return library == ebiten.GraphicsLibraryOpenGL || library == ebiten.GraphicsLibraryOpenGLES
+	case TextureKindMetal, TextureKindIOSurface:
+		return library == ebiten.GraphicsLibraryMetal
+	default:
+		return false
+	}
+}
+
+// NewImageFromNativeTexture creates a new image wrapping the given native texture, without copying the pixels
+// through CPU.
+//
+// This is useful to draw frames from video decoders or camera capturing pipelines.
+// The texture is shared with Ebiten: updates of the texture content are reflected on the image.
+// Update the texture and draw the image in a consistent order, e.g., by updating the texture in RunWithGraphics.
+// The colors of the texture must be pre-multiplied alpha values.
+//
+// The texture is still owned by the caller. Ebiten never deletes the texture, and the caller must not delete the
+// texture until the image is disposed. An OpenGL texture's filter and wrap parameters are overwritten.
+//
+// The image created by NewImageFromNativeTexture can be used only as a rendering source.
+// Drawing onto the image, ReplacePixels and Set panic.
+// At works but can be very slow since the pixels have to be rendered to another texture to read them.
+//
+// When the graphics context is lost, e.g., on Android, the image becomes cleared.
+// Create the image again with a new texture in this case.
+//
+// NewImageFromNativeTexture returns an error when the texture kind is not available with the current graphics
+// library, the graphics library is not determined yet, or the texture is invalid, e.g., it is not an OpenGL texture
+// name, or a Metal texture is not 2D or smaller than the given size.
+//
+// NewImageFromNativeTexture must be called from Update or Draw of ebiten.Game.
+func NewImageFromNativeTexture(texture Texture, width, height int) (*ebiten.Image, error) {
+	l := ebiten.CurrentGraphicsLibrary()
+	if l == ebiten.GraphicsLibraryUnknown {
+		return nil, fmt.Errorf("native: the graphics library is not determined yet")
+	}
+	if !texture.isAvailable(l) {
+		return nil, fmt.Errorf("native: the texture kind %d is not available with %s", texture.Kind, l)
+	}
+	t := graphicsdriver.NativeTexture{
+		Kind:   graphicsdriver.NativeTextureKind(texture.Kind),
+		Handle: texture.Handle,
+	}
+	if err := graphicscommand.ValidateNativeTexture(width, height, t); err != nil {
+		return nil, err
+	}
+	return hooks.NewImageFromNativeTexture(t, width, height).(*ebiten.Image), nil
+}
@@ -0,0 +1,92 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !android && !ios && !js && !ebitencbackend
+// +build !android,!ios,!js,!ebitencbackend
+
+package native_test
+
+import (
+	"bytes"
+	"image/color"
+	"testing"
+	"unsafe"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/exp/native"
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver/opengl/gl"
+	t "github.com/hajimehoshi/ebiten/v2/internal/testing"
+)
+
+func TestMain(m *testing.M) {
+	t.MainWithRunLoop(m)
+}
+
+func TestRunWithGraphicsForeignBuffers(t *testing.T) {
+	if ebiten.CurrentGraphicsLibrary() != ebiten.GraphicsLibraryOpenGL {
+		t.Skip("the graphics library is not OpenGL")
+	}
+
+	const w, h = 16, 16
+
+	src := ebiten.NewImage(w, h)
+	src.Fill(color.White)
+	dst := ebiten.NewImage(w, h)
+	dst.DrawImage(src, nil)
+	// Flush the commands so that Ebiten's buffers are allocated and bound.
+	_ = dst.At(0, 0)
+
+	want := bytes.Repeat([]byte{0xab}, 256)
+	var buffers [2]uint32
+	if err := native.RunWithGraphics(func() {
+		gl.GenBuffers(int32(len(buffers)), &buffers[0])
+		gl.BindBuffer(gl.ARRAY_BUFFER, buffers[0])
+		gl.BufferData(gl.ARRAY_BUFFER, len(want), unsafe.Pointer(&want[0]), gl.DYNAMIC_DRAW)
+		gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, buffers[1])
+		gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, len(want), unsafe.Pointer(&want[0]), gl.DYNAMIC_DRAW)
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The foreign buffers are still bound. Ebiten's next vertices must not be uploaded into them.
+	red := ebiten.NewImage(w, h)
+	red.Fill(color.RGBA{0xff, 0, 0, 0xff})
+	dst.DrawImage(red, nil)
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			if got, want := dst.At(i, j), (color.RGBA{0xff, 0, 0, 0xff}); got != want {
+				t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+
+	var gotArray, gotElementArray []byte
+	if err := native.RunWithGraphics(func() {
+		gotArray = make([]byte, len(want))
+		gl.BindBuffer(gl.ARRAY_BUFFER, buffers[0])
+		gl.GetBufferSubData(gl.ARRAY_BUFFER, 0, len(gotArray), unsafe.Pointer(&gotArray[0]))
+		gotElementArray = make([]byte, len(want))
+		gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, buffers[1])
+		gl.GetBufferSubData(gl.ELEMENT_ARRAY_BUFFER, 0, len(gotElementArray), unsafe.Pointer(&gotElementArray[0]))
+		gl.DeleteBuffers(int32(len(buffers)), &buffers[0])
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(gotArray, want) {
+		t.Errorf("the foreign array buffer was overwritten")
+	}
+	if !bytes.Equal(gotElementArray, want) {
+		t.Errorf("the foreign element array buffer was overwritten")
+	}
+}
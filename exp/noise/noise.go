@@ -0,0 +1,260 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package noise offers deterministic hash and gradient noise functions.
+//
+// The functions have the Kage builtin equivalents: hash2, hash3, perlin2, simplex2 and simplex3.
+// The Kage functions perform the same float32 operations in the same order,
+// so CPU-side game logic and GPU-side visuals can share the same noise fields.
+//
+// The hash functions use only integer-valued operations exactly representable with float32,
+// and their results exactly match the Kage builtins'.
+// The noise functions' results match up to floating-point rounding, as GPUs might fuse or reorder some operations.
+//
+// All the functions have a period of 289 units along each axis.
+//
+// This package is experimental and the API might be changed in the future.
+package noise
+
+import (
+	"math"
+)
+
+func floor(x float32) float32 {
+	return float32(math.Floor(float64(x)))
+}
+
+// mod289 returns x mod 289 for an integer-valued x.
+// The results are corrected so that they are exact even if x*(1/289) is not rounded as expected.
+func mod289(x float32) float32 {
+	r := x - floor(x*(1.0/289.0))*289
+	if r < 0 {
+		r += 289
+	}
+	if r >= 289 {
+		r -= 289
+	}
+	return r
+}
+
+// permute is a permutation polynomial of integers in [0, 289).
+func permute(x float32) float32 {
+	return mod289((x*34 + 1) * x)
+}
+
+func cell2(i, j float32) float32 {
+	return permute(mod289(permute(i) + j))
+}
+
+func cell3(i, j, k float32) float32 {
+	return permute(mod289(cell2(i, j) + k))
+}
+
+// Hash2 returns a pseudo-random value in [0, 1) for the integer cell containing (x, y).
+//
+// Hash2 is the same as the Kage builtin function hash2.
+func Hash2(x, y float32) float32 {
+	i := mod289(floor(x))
+	j := mod289(floor(y))
+	a := cell2(i, j)
+	b := cell2(mod289(j+113), i)
+	return (a*289 + b) * (1.0 / 83521.0)
+}
+
+// Hash3 returns a pseudo-random value in [0, 1) for the integer cell containing (x, y, z).
+//
+// Hash3 is the same as the Kage builtin function hash3.
+func Hash3(x, y, z float32) float32 {
+	i := mod289(floor(x))
+	j := mod289(floor(y))
+	k := mod289(floor(z))
+	a := cell3(i, j, k)
+	b := cell3(mod289(k+113), i, j)
+	return (a*289 + b) * (1.0 / 83521.0)
+}
+
+// grad2 returns the dot product of (x, y) and one of the gradients (±1, ±1) selected by h.
+func grad2(h, x, y float32) float32 {
+	k := h - floor(h*0.25)*4
+	if k >= 2 {
+		x = -x
+	}
+	if k-floor(k*0.5)*2 >= 1 {
+		y = -y
+	}
+	return x + y
+}
+
+// grad3 returns the dot product of (x, y, z) and one of the 12 edge gradients of a cube selected by h.
+func grad3(h, x, y, z float32) float32 {
+	k := h - floor(h*0.0625)*16
+	u := y
+	if k < 8 {
+		u = x
+	}
+	v := z
+	if k < 4 {
+		v = y
+	} else if k == 12 || k == 14 {
+		v = x
+	}
+	if k-floor(k*0.5)*2 >= 1 {
+		u = -u
+	}
+	if k2 := floor(k * 0.5); k2-floor(k2*0.5)*2 >= 1 {
+		v = -v
+	}
+	return u + v
+}
+
+func fade(t float32) float32 {
+	return t * t * t * (t*(t*6-15) + 10)
+}
+
+// Perlin2 returns the 2D Perlin (gradient) noise value at (x, y) in [-1, 1].
+//
+// Perlin2 is the same as the Kage builtin function perlin2.
+func Perlin2(x, y float32) float32 {
+	fx := floor(x)
+	fy := floor(y)
+	dx := x - fx
+	dy := y - fy
+	i0 := mod289(fx)
+	i1 := mod289(fx + 1)
+	j0 := mod289(fy)
+	j1 := mod289(fy + 1)
+
+	n00 := grad2(cell2(i0, j0), dx, dy)
+	n10 := grad2(cell2(i1, j0), dx-1, dy)
+	n01 := grad2(cell2(i0, j1), dx, dy-1)
+	n11 := grad2(cell2(i1, j1), dx-1, dy-1)
+
+	u := fade(dx)
+	v := fade(dy)
+	nx0 := n00 + (n10-n00)*u
+	nx1 := n01 + (n11-n01)*u
+	return nx0 + (nx1-nx0)*v
+}
+
+const (
+	// f2 and g2 are the skewing and unskewing factors for 2D, (sqrt(3)-1)/2 and (3-sqrt(3))/6.
+	f2 = 0.36602540
+	g2 = 0.21132487
+
+	// f3 and g3 are the skewing and unskewing factors for 3D, 1/3 and 1/6.
+	f3 = 0.33333333
+	g3 = 0.16666667
+)
+
+// Simplex2 returns the 2D simplex noise value at (x, y) in [-1, 1].
+//
+// Simplex2 is the same as the Kage builtin function simplex2.
+func Simplex2(x, y float32) float32 {
+	s := (x + y) * f2
+	i := floor(x + s)
+	j := floor(y + s)
+	t := (i + j) * g2
+	x0 := x - (i - t)
+	y0 := y - (j - t)
+
+	var i1, j1 float32
+	if x0 > y0 {
+		i1 = 1
+	} else {
+		j1 = 1
+	}
+
+	x1 := x0 - i1 + g2
+	y1 := y0 - j1 + g2
+	x2 := x0 - 1 + 2*g2
+	y2 := y0 - 1 + 2*g2
+
+	ii := mod289(i)
+	jj := mod289(j)
+
+	var n float32
+	if t0 := 0.5 - x0*x0 - y0*y0; t0 > 0 {
+		n += t0 * t0 * t0 * t0 * grad2(cell2(ii, jj), x0, y0)
+	}
+	if t1 := 0.5 - x1*x1 - y1*y1; t1 > 0 {
+		n += t1 * t1 * t1 * t1 * grad2(cell2(mod289(ii+i1), mod289(jj+j1)), x1, y1)
+	}
+	if t2 := 0.5 - x2*x2 - y2*y2; t2 > 0 {
+		n += t2 * t2 * t2 * t2 * grad2(cell2(mod289(ii+1), mod289(jj+1)), x2, y2)
+	}
+	return n * 70
+}
+
+// Simplex3 returns the 3D simplex noise value at (x, y, z) in [-1, 1].
+//
+// Simplex3 is the same as the Kage builtin function simplex3.
+func Simplex3(x, y, z float32) float32 {
+	s := (x + y + z) * f3
+	i := floor(x + s)
+	j := floor(y + s)
+	k := floor(z + s)
+	t := (i + j + k) * g3
+	x0 := x - (i - t)
+	y0 := y - (j - t)
+	z0 := z - (k - t)
+
+	// Determine the simplex containing the point.
+	var i1, j1, k1, i2, j2, k2 float32
+	if x0 >= y0 {
+		if y0 >= z0 {
+			i1, i2, j2 = 1, 1, 1
+		} else if x0 >= z0 {
+			i1, i2, k2 = 1, 1, 1
+		} else {
+			k1, i2, k2 = 1, 1, 1
+		}
+	} else {
+		if y0 < z0 {
+			k1, j2, k2 = 1, 1, 1
+		} else if x0 < z0 {
+			j1, j2, k2 = 1, 1, 1
+		} else {
+			j1, i2, j2 = 1, 1, 1
+		}
+	}
+
+	x1 := x0 - i1 + g3
+	y1 := y0 - j1 + g3
+	z1 := z0 - k1 + g3
+	x2 := x0 - i2 + 2*g3
+	y2 := y0 - j2 + 2*g3
+	z2 := z0 - k2 + 2*g3
+	x3 := x0 - 1 + 3*g3
+	y3 := y0 - 1 + 3*g3
+	z3 := z0 - 1 + 3*g3
+
+	ii := mod289(i)
+	jj := mod289(j)
+	kk := mod289(k)
+
+	var n float32
+	if t0 := 0.6 - x0*x0 - y0*y0 - z0*z0; t0 > 0 {
+		n += t0 * t0 * t0 * t0 * grad3(cell3(ii, jj, kk), x0, y0, z0)
+	}
+	if t1 := 0.6 - x1*x1 - y1*y1 - z1*z1; t1 > 0 {
+		n += t1 * t1 * t1 * t1 * grad3(cell3(mod289(ii+i1), mod289(jj+j1), mod289(kk+k1)), x1, y1, z1)
+	}
+	if t2 := 0.6 - x2*x2 - y2*y2 - z2*z2; t2 > 0 {
+		n += t2 * t2 * t2 * t2 * grad3(cell3(mod289(ii+i2), mod289(jj+j2), mod289(kk+k2)), x2, y2, z2)
+	}
+	if t3 := 0.6 - x3*x3 - y3*y3 - z3*z3; t3 > 0 {
+		n += t3 * t3 * t3 * t3 * grad3(cell3(mod289(ii+1), mod289(jj+1), mod289(kk+1)), x3, y3, z3)
+	}
+	return n * 32
+}
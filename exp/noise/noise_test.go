@@ -0,0 +1,148 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package noise_test
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2/exp/noise"
+)
+
+func TestHashRange(t *testing.T) {
+	for j := -300; j < 300; j += 7 {
+		for i := -300; i < 300; i += 3 {
+			x, y := float32(i), float32(j)
+			if got := noise.Hash2(x, y); got < 0 || got >= 1 {
+				t.Errorf("Hash2(%v, %v): got: %v, want: [0, 1)", x, y, got)
+			}
+			if got := noise.Hash3(x, y, x+y); got < 0 || got >= 1 {
+				t.Errorf("Hash3(%v, %v, %v): got: %v, want: [0, 1)", x, y, x+y, got)
+			}
+		}
+	}
+}
+
+func TestHashCell(t *testing.T) {
+	// The hash value must be the same in a cell.
+	for _, d := range []float32{0.25, 0.5, 0.999} {
+		if got, want := noise.Hash2(3+d, 5+d), noise.Hash2(3, 5); got != want {
+			t.Errorf("Hash2(%v, %v): got: %v, want: %v", 3+d, 5+d, got, want)
+		}
+		if got, want := noise.Hash3(-3+d, 5+d, 7+d), noise.Hash3(-3, 5, 7); got != want {
+			t.Errorf("Hash3(%v, %v, %v): got: %v, want: %v", -3+d, 5+d, 7+d, got, want)
+		}
+	}
+}
+
+func TestHashDistribution(t *testing.T) {
+	const n = 64
+	var buckets [10]int
+	for j := 0; j < n; j++ {
+		for i := 0; i < n; i++ {
+			buckets[int(noise.Hash2(float32(i), float32(j))*10)]++
+		}
+	}
+	// Each bucket should have roughly n*n/10 values.
+	for i, c := range buckets {
+		if c < n*n/20 || c > n*n/5 {
+			t.Errorf("bucket %d: got: %d values out of %d", i, c, n*n)
+		}
+	}
+}
+
+func TestNoiseRange(t *testing.T) {
+	tests := []struct {
+		name string
+		f    func(x, y float32) float32
+	}{
+		{
+			name: "Perlin2",
+			f:    noise.Perlin2,
+		},
+		{
+			name: "Simplex2",
+			f:    noise.Simplex2,
+		},
+		{
+			name: "Simplex3",
+			f: func(x, y float32) float32 {
+				return noise.Simplex3(x, y, x*0.5-y)
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var min, max float32
+			for j := -200; j < 200; j++ {
+				for i := -200; i < 200; i++ {
+					x, y := float32(i)*0.137, float32(j)*0.173
+					v := tc.f(x, y)
+					if v < -1 || v > 1 {
+						t.Fatalf("%s(%v, %v): got: %v, want: [-1, 1]", tc.name, x, y, v)
+					}
+					if v < min {
+						min = v
+					}
+					if v > max {
+						max = v
+					}
+				}
+			}
+			// The noise should not be flat.
+			if min > -0.3 || max < 0.3 {
+				t.Errorf("%s: range: got: [%v, %v], want: a wider range", tc.name, min, max)
+			}
+		})
+	}
+}
+
+func TestPerlin2Lattice(t *testing.T) {
+	// Perlin noise is zero at integer lattice points.
+	for j := -5; j <= 5; j++ {
+		for i := -5; i <= 5; i++ {
+			if got := noise.Perlin2(float32(i), float32(j)); got != 0 {
+				t.Errorf("Perlin2(%d, %d): got: %v, want: 0", i, j, got)
+			}
+		}
+	}
+}
+
+func TestPeriod(t *testing.T) {
+	const p = 289
+	for _, xy := range [][2]float32{{0, 0}, {3, 7}, {-10, 42}, {100, -200}} {
+		x, y := xy[0], xy[1]
+		if got, want := noise.Hash2(x+p, y-p), noise.Hash2(x, y); got != want {
+			t.Errorf("Hash2(%v, %v): got: %v, want: %v", x+p, y-p, got, want)
+		}
+		if got, want := noise.Hash3(x, y+p, x-p), noise.Hash3(x, y, x); got != want {
+			t.Errorf("Hash3(%v, %v, %v): got: %v, want: %v", x, y+p, x-p, got, want)
+		}
+
+		// Use offsets exactly representable so that the fractions are the same.
+		x += 0.25
+		y += 0.75
+		if got, want := noise.Perlin2(x+p, y+p), noise.Perlin2(x, y); absDiff(got, want) > 1e-4 {
+			t.Errorf("Perlin2(%v, %v): got: %v, want: %v", x+p, y+p, got, want)
+		}
+	}
+}
+
+func absDiff(a, b float32) float32 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
@@ -0,0 +1,175 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sensors offers the device's motion sensors like an accelerometer and a gyroscope, e.g., for tilt controls.
+//
+// The sensors are available on Android, iOS, and browsers supporting DeviceMotionEvent and DeviceOrientationEvent.
+// On the other environments, the functions always return false as ok.
+//
+// The sensors are started when any function in this package is called for the first time.
+// Then, it might take a little time until the first values are available.
+//
+// On iOS Safari, the sensors require a permission. The permission is requested at the first user gesture like a touch
+// after the sensors are started.
+//
+// This package is experimental and the API might be changed in the future.
+package sensors
+
+import (
+	"math"
+	"sync"
+)
+
+// Acceleration returns the acceleration of the device including the gravity in m/s².
+//
+// The axes are based on the device in its natural orientation regardless of the screen orientation:
+// x points to the right, y points to the top, and z points to the front of the screen.
+// For example, z is about 9.8 when the device is lying on a table with the screen facing up.
+//
+// Acceleration returns false as ok when the accelerometer is not available or no value is available yet.
+//
+// Acceleration is concurrent-safe.
+func Acceleration() (x, y, z float64, ok bool) {
+	theState.start()
+	update()
+
+	theState.m.Lock()
+	defer theState.m.Unlock()
+	v := theState.acceleration
+	return v.x, v.y, v.z, v.ok
+}
+
+// RotationRate returns the rotation rate of the device around the x, y, and z axes in radians per second.
+//
+// The axes are the same as Acceleration's. A positive value means a counterclockwise rotation when seen from the
+// positive side of the axis.
+//
+// RotationRate returns false as ok when the gyroscope is not available or no value is available yet.
+//
+// RotationRate is concurrent-safe.
+func RotationRate() (x, y, z float64, ok bool) {
+	theState.start()
+	update()
+
+	theState.m.Lock()
+	defer theState.m.Unlock()
+	v := theState.rotationRate
+	return v.x, v.y, v.z, v.ok
+}
+
+// Orientation returns the orientation of the device in radians, in the same way as DeviceOrientationEvent in
+// the Web.
+//
+// alpha is the rotation around the z axis in [0, 2π), beta is the rotation around the x axis in [-π, π), and
+// gamma is the rotation around the y axis in [-π/2, π/2). alpha is based on the magnetic north when a compass is
+// available, and on an arbitrary direction otherwise.
+//
+// Orientation returns false as ok when the orientation is not available or no value is available yet.
+//
+// Orientation is concurrent-safe.
+func Orientation() (alpha, beta, gamma float64, ok bool) {
+	theState.start()
+	update()
+
+	theState.m.Lock()
+	defer theState.m.Unlock()
+	v := theState.orientation
+	return v.x, v.y, v.z, v.ok
+}
+
+type vector struct {
+	x, y, z float64
+	ok      bool
+}
+
+type state struct {
+	acceleration vector
+	rotationRate vector
+	orientation  vector
+
+	once sync.Once
+	m    sync.Mutex
+}
+
+var theState state
+
+func (s *state) start() {
+	s.once.Do(start)
+}
+
+func (s *state) setAcceleration(x, y, z float64) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.acceleration = vector{x: x, y: y, z: z, ok: true}
+}
+
+func (s *state) setRotationRate(x, y, z float64) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.rotationRate = vector{x: x, y: y, z: z, ok: true}
+}
+
+func (s *state) setOrientation(alpha, beta, gamma float64) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.orientation = vector{x: alpha, y: beta, z: gamma, ok: true}
+}
+
+// orientationFromRotationMatrix converts a rotation matrix from the device coordinate to the world coordinate
+// (east, north, and up) to the angles in the same way as DeviceOrientationEvent.
+// This is the same as the conversion in Chromium.
+func orientationFromRotationMatrix(r [9]float64) (alpha, beta, gamma float64) {
+	switch {
+	case r[8] > 0:
+		alpha = math.Atan2(-r[1], r[4])
+		beta = math.Asin(r[7])
+		gamma = math.Atan2(-r[6], r[8])
+	case r[8] < 0:
+		alpha = math.Atan2(r[1], -r[4])
+		beta = -math.Asin(r[7])
+		if beta >= 0 {
+			beta -= math.Pi
+		} else {
+			beta += math.Pi
+		}
+		gamma = math.Atan2(r[6], -r[8])
+	default:
+		if r[6] > 0 {
+			alpha = math.Atan2(-r[1], r[4])
+			beta = math.Asin(r[7])
+			gamma = -math.Pi / 2
+		} else if r[6] < 0 {
+			alpha = math.Atan2(r[1], -r[4])
+			beta = -math.Asin(r[7])
+			if beta >= 0 {
+				beta -= math.Pi
+			} else {
+				beta += math.Pi
+			}
+			gamma = -math.Pi / 2
+		} else {
+			alpha = math.Atan2(r[3], r[0])
+			if r[7] > 0 {
+				beta = math.Pi / 2
+			} else {
+				beta = -math.Pi / 2
+			}
+			gamma = 0
+		}
+	}
+	if alpha < 0 {
+		alpha += 2 * math.Pi
+	}
+	return
+}
@@ -0,0 +1,142 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !ebitencbackend
+// +build !ebitencbackend
+
+package sensors
+
+// #cgo LDFLAGS: -landroid
+//
+// #include <android/looper.h>
+// #include <android/sensor.h>
+//
+// // ASENSOR_TYPE_ROTATION_VECTOR might not be defined in old NDKs.
+// #define ROTATION_VECTOR 11
+//
+// static ASensorEventQueue* queue;
+// static const ASensor* accelerometer;
+// static const ASensor* gyroscope;
+// static const ASensor* rotationVector;
+//
+// static void enableSensor(const ASensor* sensor) {
+//   if (!sensor) {
+//     return;
+//   }
+//   ASensorEventQueue_enableSensor(queue, sensor);
+//   // Request about 60 events per second.
+//   ASensorEventQueue_setEventRate(queue, sensor, 1000000 / 60);
+// }
+//
+// static void startSensors(void) {
+//   ASensorManager* manager = ASensorManager_getInstance();
+//   if (!manager) {
+//     return;
+//   }
+//   ALooper* looper = ALooper_prepare(ALOOPER_PREPARE_ALLOW_NON_CALLBACKS);
+//   queue = ASensorManager_createEventQueue(manager, looper, 0, NULL, NULL);
+//   if (!queue) {
+//     return;
+//   }
+//   accelerometer = ASensorManager_getDefaultSensor(manager, ASENSOR_TYPE_ACCELEROMETER);
+//   gyroscope = ASensorManager_getDefaultSensor(manager, ASENSOR_TYPE_GYROSCOPE);
+//   rotationVector = ASensorManager_getDefaultSensor(manager, ROTATION_VECTOR);
+//   enableSensor(accelerometer);
+//   enableSensor(gyroscope);
+//   enableSensor(rotationVector);
+// }
+//
+// // pollSensors reads the queued events and stores the latest values.
+// // The values' availability is stored as bits of the returned value.
+// static int pollSensors(float* acceleration, float* rotationRate, float* rotationVector) {
+//   if (!queue) {
+//     return 0;
+//   }
+//   int result = 0;
+//   ASensorEvent events[16];
+//   ssize_t n;
+//   while ((n = ASensorEventQueue_getEvents(queue, events, sizeof(events) / sizeof(events[0]))) > 0) {
+//     for (ssize_t i = 0; i < n; i++) {
+//       const ASensorEvent* e = &events[i];
+//       switch (e->type) {
+//       case ASENSOR_TYPE_ACCELEROMETER:
+//         acceleration[0] = e->data[0];
+//         acceleration[1] = e->data[1];
+//         acceleration[2] = e->data[2];
+//         result |= 1;
+//         break;
+//       case ASENSOR_TYPE_GYROSCOPE:
+//         rotationRate[0] = e->data[0];
+//         rotationRate[1] = e->data[1];
+//         rotationRate[2] = e->data[2];
+//         result |= 2;
+//         break;
+//       case ROTATION_VECTOR:
+//         rotationVector[0] = e->data[0];
+//         rotationVector[1] = e->data[1];
+//         rotationVector[2] = e->data[2];
+//         result |= 4;
+//         break;
+//       }
+//     }
+//   }
+//   return result;
+// }
+import "C"
+
+import (
+	"math"
+	"sync"
+)
+
+// pollM protects the event queue.
+var pollM sync.Mutex
+
+func start() {
+	C.startSensors()
+}
+
+func update() {
+	pollM.Lock()
+	defer pollM.Unlock()
+
+	var acc, rot, vec [3]C.float
+	r := C.pollSensors(&acc[0], &rot[0], &vec[0])
+	if r&1 != 0 {
+		theState.setAcceleration(float64(acc[0]), float64(acc[1]), float64(acc[2]))
+	}
+	if r&2 != 0 {
+		theState.setRotationRate(float64(rot[0]), float64(rot[1]), float64(rot[2]))
+	}
+	if r&4 != 0 {
+		theState.setOrientation(orientationFromRotationMatrix(rotationMatrixFromVector(float64(vec[0]), float64(vec[1]), float64(vec[2]))))
+	}
+}
+
+// rotationMatrixFromVector returns a rotation matrix from a rotation vector in the same way as
+// SensorManager.getRotationMatrixFromVector.
+func rotationMatrixFromVector(x, y, z float64) [9]float64 {
+	w := 1 - x*x - y*y - z*z
+	if w > 0 {
+		w = math.Sqrt(w)
+	} else {
+		w = 0
+	}
+
+	return [9]float64{
+		1 - 2*y*y - 2*z*z, 2*x*y - 2*z*w, 2*x*z + 2*y*w,
+		2*x*y + 2*z*w, 1 - 2*x*x - 2*z*z, 2*y*z - 2*x*w,
+		2*x*z - 2*y*w, 2*y*z + 2*x*w, 1 - 2*x*x - 2*y*y,
+	}
+}
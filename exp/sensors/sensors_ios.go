@@ -0,0 +1,110 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build ios && !ebitencbackend
+// +build ios,!ebitencbackend
+
+package sensors
+
+// #cgo CFLAGS: -x objective-c
+// #cgo LDFLAGS: -framework CoreMotion -framework Foundation
+//
+// #import <CoreMotion/CoreMotion.h>
+//
+// static CMMotionManager* manager;
+//
+// static void startSensors(void) {
+//   manager = [[CMMotionManager alloc] init];
+//   if (manager.deviceMotionAvailable) {
+//     manager.deviceMotionUpdateInterval = 1.0 / 60.0;
+//     if ([CMMotionManager availableAttitudeReferenceFrames] & CMAttitudeReferenceFrameXMagneticNorthZVertical) {
+//       [manager startDeviceMotionUpdatesUsingReferenceFrame:CMAttitudeReferenceFrameXMagneticNorthZVertical];
+//     } else {
+//       [manager startDeviceMotionUpdates];
+//     }
+//     return;
+//   }
+//   if (manager.accelerometerAvailable) {
+//     manager.accelerometerUpdateInterval = 1.0 / 60.0;
+//     [manager startAccelerometerUpdates];
+//   }
+// }
+//
+// // pollSensors stores the latest values.
+// // The values' availability is stored as bits of the returned value.
+// static int pollSensors(double* acceleration, double* rotationRate, double* rotationMatrix) {
+//   if (!manager) {
+//     return 0;
+//   }
+//   @autoreleasepool {
+//     CMDeviceMotion* motion = manager.deviceMotion;
+//     if (motion) {
+//       // The values are in G and the gravity is the opposite direction of Android's.
+//       acceleration[0] = motion.gravity.x + motion.userAcceleration.x;
+//       acceleration[1] = motion.gravity.y + motion.userAcceleration.y;
+//       acceleration[2] = motion.gravity.z + motion.userAcceleration.z;
+//       rotationRate[0] = motion.rotationRate.x;
+//       rotationRate[1] = motion.rotationRate.y;
+//       rotationRate[2] = motion.rotationRate.z;
+//       // CMRotationMatrix converts the reference frame to the device's frame.
+//       // Transpose this to convert the device's frame to the reference frame.
+//       CMRotationMatrix m = motion.attitude.rotationMatrix;
+//       rotationMatrix[0] = m.m11;
+//       rotationMatrix[1] = m.m21;
+//       rotationMatrix[2] = m.m31;
+//       rotationMatrix[3] = m.m12;
+//       rotationMatrix[4] = m.m22;
+//       rotationMatrix[5] = m.m32;
+//       rotationMatrix[6] = m.m13;
+//       rotationMatrix[7] = m.m23;
+//       rotationMatrix[8] = m.m33;
+//       return 1 | 2 | 4;
+//     }
+//     CMAccelerometerData* data = manager.accelerometerData;
+//     if (data) {
+//       acceleration[0] = data.acceleration.x;
+//       acceleration[1] = data.acceleration.y;
+//       acceleration[2] = data.acceleration.z;
+//       return 1;
+//     }
+//   }
+//   return 0;
+// }
+import "C"
+
+// standardGravity is the standard gravity in m/s².
+const standardGravity = 9.80665
+
+func start() {
+	C.startSensors()
+}
+
+func update() {
+	var acc, rot [3]C.double
+	var mat [9]C.double
+	r := C.pollSensors(&acc[0], &rot[0], &mat[0])
+	if r&1 != 0 {
+		theState.setAcceleration(-float64(acc[0])*standardGravity, -float64(acc[1])*standardGravity, -float64(acc[2])*standardGravity)
+	}
+	if r&2 != 0 {
+		theState.setRotationRate(float64(rot[0]), float64(rot[1]), float64(rot[2]))
+	}
+	if r&4 != 0 {
+		var m [9]float64
+		for i := range m {
+			m[i] = float64(mat[i])
+		}
+		theState.setOrientation(orientationFromRotationMatrix(m))
+	}
+}
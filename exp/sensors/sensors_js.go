@@ -0,0 +1,97 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sensors
+
+import (
+	"math"
+	"syscall/js"
+)
+
+func update() {
+	// The values are updated by the event handlers.
+}
+
+func start() {
+	window := js.Global().Get("window")
+	if !window.Truthy() {
+		// Web Workers cannot receive the sensor events.
+		return
+	}
+
+	window.Call("addEventListener", "devicemotion", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		e := args[0]
+		if a := e.Get("accelerationIncludingGravity"); a.Truthy() && isNumber(a.Get("x")) {
+			theState.setAcceleration(a.Get("x").Float(), a.Get("y").Float(), a.Get("z").Float())
+		}
+		if r := e.Get("rotationRate"); r.Truthy() && isNumber(r.Get("alpha")) {
+			// rotationRate is in degrees per second. alpha, beta, and gamma are around z, x, and y axes respectively.
+			theState.setRotationRate(degToRad(r.Get("beta").Float()), degToRad(r.Get("gamma").Float()), degToRad(r.Get("alpha").Float()))
+		}
+		return nil
+	}))
+
+	window.Call("addEventListener", "deviceorientation", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		e := args[0]
+		if !isNumber(e.Get("alpha")) {
+			return nil
+		}
+		theState.setOrientation(degToRad(e.Get("alpha").Float()), degToRad(e.Get("beta").Float()), degToRad(e.Get("gamma").Float()))
+		return nil
+	}))
+
+	requestPermissionIfNeeded(window)
+}
+
+// requestPermissionIfNeeded requests the permissions to receive the sensor events at the next user gesture.
+// This is required on iOS Safari.
+func requestPermissionIfNeeded(window js.Value) {
+	var classes []js.Value
+	for _, name := range []string{"DeviceMotionEvent", "DeviceOrientationEvent"} {
+		c := js.Global().Get(name)
+		if !c.Truthy() || c.Get("requestPermission").Type() != js.TypeFunction {
+			continue
+		}
+		classes = append(classes, c)
+	}
+	if len(classes) == 0 {
+		return
+	}
+
+	var f js.Func
+	f = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		for _, c := range classes {
+			c.Call("requestPermission").Call("catch", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+				js.Global().Get("console").Call("warn", "sensors: requesting a permission failed:", args[0])
+				return nil
+			}))
+		}
+		for _, t := range []string{"touchend", "click", "keyup"} {
+			window.Call("removeEventListener", t, f, true)
+		}
+		f.Release()
+		return nil
+	})
+	for _, t := range []string{"touchend", "click", "keyup"} {
+		window.Call("addEventListener", t, f, true)
+	}
+}
+
+func isNumber(v js.Value) bool {
+	return v.Type() == js.TypeNumber
+}
+
+func degToRad(x float64) float64 {
+	return x * math.Pi / 180
+}
@@ -0,0 +1,34 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build (!android && !ios && !js) || ebitencbackend
+// +build !android,!ios,!js ebitencbackend
+
+package sensors
+
+import (
+	"testing"
+)
+
+func TestNotAvailable(t *testing.T) {
+	if _, _, _, ok := Acceleration(); ok {
+		t.Errorf("Acceleration: ok must be false without the sensors")
+	}
+	if _, _, _, ok := RotationRate(); ok {
+		t.Errorf("RotationRate: ok must be false without the sensors")
+	}
+	if _, _, _, ok := Orientation(); ok {
+		t.Errorf("Orientation: ok must be false without the sensors")
+	}
+}
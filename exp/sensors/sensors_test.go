@@ -0,0 +1,115 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sensors
+
+import (
+	"math"
+	"testing"
+)
+
+// rotationMatrix returns the rotation matrix for the angles, defined as Rz(alpha) * Rx(beta) * Ry(gamma) by
+// DeviceOrientationEvent.
+func rotationMatrix(alpha, beta, gamma float64) [9]float64 {
+	ca, sa := math.Cos(alpha), math.Sin(alpha)
+	cb, sb := math.Cos(beta), math.Sin(beta)
+	cg, sg := math.Cos(gamma), math.Sin(gamma)
+	return [9]float64{
+		ca*cg - sa*sb*sg, -sa * cb, ca*sg + sa*sb*cg,
+		sa*cg + ca*sb*sg, ca * cb, sa*sg - ca*sb*cg,
+		-cb * sg, sb, cb * cg,
+	}
+}
+
+func TestOrientationFromRotationMatrix(t *testing.T) {
+	tests := []struct {
+		alpha, beta, gamma float64
+	}{
+		{0, 0, 0},
+		{math.Pi / 2, 0, 0},
+		{3 * math.Pi / 2, 0, 0},
+		{0, math.Pi / 4, 0},
+		{0, 0, -math.Pi / 3},
+		{1, 0.5, -0.25},
+		{5, -1.2, 1.4},
+		// The screen faces down.
+		{0, 2.5, 0},
+		{2, -2.5, 0.5},
+	}
+	const delta = 1e-9
+	for _, tc := range tests {
+		alpha, beta, gamma := orientationFromRotationMatrix(rotationMatrix(tc.alpha, tc.beta, tc.gamma))
+		if math.Abs(alpha-tc.alpha) > delta || math.Abs(beta-tc.beta) > delta || math.Abs(gamma-tc.gamma) > delta {
+			t.Errorf("orientationFromRotationMatrix for (%f, %f, %f): got: (%f, %f, %f)", tc.alpha, tc.beta, tc.gamma, alpha, beta, gamma)
+		}
+	}
+}
+
+func TestOrientationFromRotationMatrixVertical(t *testing.T) {
+	// The device stands upright, i.e., beta is pi/2 and the matrix is gimbal locked.
+	r := rotationMatrix(0, math.Pi/2, 0)
+	_, beta, gamma := orientationFromRotationMatrix(r)
+	if math.Abs(beta-math.Pi/2) > 1e-9 {
+		t.Errorf("beta: got: %f, want: %f", beta, math.Pi/2)
+	}
+	if math.Abs(gamma) > 1e-9 {
+		t.Errorf("gamma: got: %f, want: 0", gamma)
+	}
+}
+
+func TestOrientationRange(t *testing.T) {
+	for a := -10.0; a <= 10; a += 0.7 {
+		for b := -10.0; b <= 10; b += 0.9 {
+			for g := -10.0; g <= 10; g += 1.1 {
+				alpha, beta, gamma := orientationFromRotationMatrix(rotationMatrix(a, b, g))
+				if alpha < 0 || alpha >= 2*math.Pi {
+					t.Errorf("alpha for (%f, %f, %f): got: %f, want: [0, 2π)", a, b, g, alpha)
+				}
+				if beta < -math.Pi || beta > math.Pi {
+					t.Errorf("beta for (%f, %f, %f): got: %f, want: [-π, π)", a, b, g, beta)
+				}
+				if gamma < -math.Pi/2 || gamma > math.Pi/2 {
+					t.Errorf("gamma for (%f, %f, %f): got: %f, want: [-π/2, π/2)", a, b, g, gamma)
+				}
+
+				// The angles must represent the same rotation.
+				r0 := rotationMatrix(a, b, g)
+				r1 := rotationMatrix(alpha, beta, gamma)
+				for i := range r0 {
+					if math.Abs(r0[i]-r1[i]) > 1e-9 {
+						t.Errorf("rotation for (%f, %f, %f): got: %v, want: %v", a, b, g, r1, r0)
+						break
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestState(t *testing.T) {
+	var s state
+	s.setAcceleration(1, 2, 3)
+	s.setRotationRate(4, 5, 6)
+	s.setOrientation(7, 8, 9)
+
+	if got, want := s.acceleration, (vector{x: 1, y: 2, z: 3, ok: true}); got != want {
+		t.Errorf("acceleration: got: %v, want: %v", got, want)
+	}
+	if got, want := s.rotationRate, (vector{x: 4, y: 5, z: 6, ok: true}); got != want {
+		t.Errorf("rotationRate: got: %v, want: %v", got, want)
+	}
+	if got, want := s.orientation, (vector{x: 7, y: 8, z: 9, ok: true}); got != want {
+		t.Errorf("orientation: got: %v, want: %v", got, want)
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+/*
+
+#include <jni.h>
+#include <stdlib.h>
+#include <string.h>
+
+// Basically same as:
+//
+//     String path = context.getFilesDir().getAbsolutePath();
+//
+// filesDir returns NULL on failure. The returned string must be freed by the caller.
+static char* filesDir(uintptr_t java_vm, uintptr_t jni_env, uintptr_t ctx) {
+  JavaVM* vm = (JavaVM*)java_vm;
+  JNIEnv* env = (JNIEnv*)jni_env;
+  jobject context = (jobject)ctx;
+
+  const jclass android_content_Context =
+      (*env)->FindClass(env, "android/content/Context");
+  const jclass java_io_File =
+      (*env)->FindClass(env, "java/io/File");
+
+  const jobject file =
+      (*env)->CallObjectMethod(
+          env, context,
+          (*env)->GetMethodID(env, android_content_Context, "getFilesDir", "()Ljava/io/File;"));
+  char* result = NULL;
+  if (file) {
+    const jstring path =
+        (jstring)(*env)->CallObjectMethod(
+            env, file,
+            (*env)->GetMethodID(env, java_io_File, "getAbsolutePath", "()Ljava/lang/String;"));
+    if (path) {
+      const char* str = (*env)->GetStringUTFChars(env, path, NULL);
+      result = strdup(str);
+      (*env)->ReleaseStringUTFChars(env, path, str);
+      (*env)->DeleteLocalRef(env, path);
+    }
+    (*env)->DeleteLocalRef(env, file);
+  }
+
+  (*env)->DeleteLocalRef(env, android_content_Context);
+  (*env)->DeleteLocalRef(env, java_io_File);
+
+  return result;
+}
+
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"unsafe"
+
+	"golang.org/x/mobile/app"
+)
+
+var (
+	filesDir     string
+	filesDirErr  error
+	filesDirOnce sync.Once
+)
+
+func dir() (string, error) {
+	filesDirOnce.Do(func() {
+		if err := app.RunOnJVM(func(vm, env, ctx uintptr) error {
+			p := C.filesDir(C.uintptr_t(vm), C.uintptr_t(env), C.uintptr_t(ctx))
+			if p == nil {
+				return errors.New("Context#getFilesDir failed")
+			}
+			defer C.free(unsafe.Pointer(p))
+			filesDir = C.GoString(p)
+			return nil
+		}); err != nil {
+			filesDirErr = fmt.Errorf("storage: app.RunOnJVM failed: %w", err)
+		}
+	})
+	if filesDirErr != nil {
+		return "", filesDirErr
+	}
+	return filepath.Join(filesDir, "ebitenstorage"), nil
+}
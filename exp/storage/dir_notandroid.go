@@ -0,0 +1,46 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !android && !js
+// +build !android,!js
+
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func dir() (string, error) {
+	d, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("storage: os.UserConfigDir failed: %w", err)
+	}
+	name, err := appName()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(d, name, "storage"), nil
+}
+
+// appName returns a name to separate the storage of the application from the others.
+func appName() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("storage: os.Executable failed: %w", err)
+	}
+	name := filepath.Base(exe)
+	return name[:len(name)-len(filepath.Ext(name))], nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package storage offers a persistent storage to save and load byte data by keys, e.g., for save games.
+//
+// The data is stored in IndexedDB on browsers, in files under os.UserConfigDir on desktops and iOS, and in the
+// application's files directory on Android.
+//
+// This package is experimental and the API might be changed in the future.
+package storage
+
+import (
+	"errors"
+)
+
+// Save saves data with the given key.
+// If data with the same key already exists, the data is overwritten.
+//
+// key must not be empty.
+//
+// On browsers, Save must not be called from a JavaScript callback directly as Save waits for IndexedDB.
+//
+// Save is concurrent-safe.
+func Save(key string, data []byte) error {
+	if key == "" {
+		return errors.New("storage: key must not be empty")
+	}
+	return save(key, data)
+}
+
+// Load loads the data saved with the given key.
+//
+// If the data doesn't exist, Load returns an error that satisfies errors.Is(err, os.ErrNotExist).
+//
+// On browsers, Load must not be called from a JavaScript callback directly as Load waits for IndexedDB.
+//
+// Load is concurrent-safe.
+func Load(key string) ([]byte, error) {
+	if key == "" {
+		return nil, errors.New("storage: key must not be empty")
+	}
+	return load(key)
+}
+
+// Delete deletes the data saved with the given key.
+// Delete does nothing if the data doesn't exist.
+//
+// On browsers, Delete must not be called from a JavaScript callback directly as Delete waits for IndexedDB.
+//
+// Delete is concurrent-safe.
+func Delete(key string) error {
+	if key == "" {
+		return errors.New("storage: key must not be empty")
+	}
+	return remove(key)
+}
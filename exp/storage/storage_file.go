@@ -0,0 +1,87 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+// +build !js
+
+package storage
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+func path(key string) (string, error) {
+	dir, err := dir()
+	if err != nil {
+		return "", err
+	}
+	// Encode the key in order to use any strings as file names, even on case-insensitive file systems.
+	return filepath.Join(dir, hex.EncodeToString([]byte(key))), nil
+}
+
+func save(key string, data []byte) error {
+	p, err := path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return fmt.Errorf("storage: os.MkdirAll failed: %w", err)
+	}
+
+	// Write the data to a temporary file and rename it so that the existing data is not broken by a failure.
+	f, err := ioutil.TempFile(filepath.Dir(p), "tmp-")
+	if err != nil {
+		return fmt.Errorf("storage: ioutil.TempFile failed: %w", err)
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("storage: writing data failed: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("storage: closing a file failed: %w", err)
+	}
+	if err := os.Rename(f.Name(), p); err != nil {
+		return fmt.Errorf("storage: os.Rename failed: %w", err)
+	}
+	return nil
+}
+
+func load(key string) ([]byte, error) {
+	p, err := path(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(p)
+	if err != nil {
+		return nil, fmt.Errorf("storage: loading %q failed: %w", key, err)
+	}
+	return data, nil
+}
+
+func remove(key string) error {
+	p, err := path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("storage: deleting %q failed: %w", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"syscall/js"
+)
+
+const (
+	dbName    = "ebiten"
+	storeName = "storage"
+)
+
+var (
+	db      js.Value
+	dbErr   error
+	dbOnce  sync.Once
+	uint8js = js.Global().Get("Uint8Array")
+)
+
+func jsError(v js.Value) error {
+	if !v.Truthy() {
+		return errors.New("storage: unknown error")
+	}
+	return fmt.Errorf("storage: %s", v.Call("toString").String())
+}
+
+// wait waits for IndexedDB's events on target.
+// wait returns nil when the event succeeded and an error otherwise.
+func wait(target js.Value, success string, failures ...string) error {
+	ch := make(chan error, 1)
+
+	// Only the first event is reported.
+	// Another event can follow, e.g. onabort after onerror, so the send must not block the JavaScript callback.
+	send := func(err error) {
+		select {
+		case ch <- err:
+		default:
+		}
+	}
+
+	names := append([]string{success}, failures...)
+	fs := make([]js.Func, 0, len(names))
+	defer func() {
+		// Remove the handlers before releasing them so that a later event doesn't call a released function.
+		for i, f := range fs {
+			target.Set(names[i], js.Null())
+			f.Release()
+		}
+	}()
+
+	f := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		send(nil)
+		return nil
+	})
+	fs = append(fs, f)
+	target.Set(success, f)
+
+	for _, name := range failures {
+		f := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			send(jsError(target.Get("error")))
+			return nil
+		})
+		fs = append(fs, f)
+		target.Set(name, f)
+	}
+
+	return <-ch
+}
+
+func openDB() (js.Value, error) {
+	dbOnce.Do(func() {
+		idb := js.Global().Get("indexedDB")
+		if !idb.Truthy() {
+			dbErr = errors.New("storage: IndexedDB is not available")
+			return
+		}
+
+		req := idb.Call("open", dbName, 1)
+		upgrade := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			req.Get("result").Call("createObjectStore", storeName)
+			return nil
+		})
+		defer func() {
+			req.Set("onupgradeneeded", js.Null())
+			upgrade.Release()
+		}()
+		req.Set("onupgradeneeded", upgrade)
+
+		if err := wait(req, "onsuccess", "onerror"); err != nil {
+			dbErr = err
+			return
+		}
+		db = req.Get("result")
+	})
+	return db, dbErr
+}
+
+func objectStore(mode string) (tx js.Value, store js.Value, err error) {
+	db, err := openDB()
+	if err != nil {
+		return js.Undefined(), js.Undefined(), err
+	}
+	tx = db.Call("transaction", storeName, mode)
+	return tx, tx.Call("objectStore", storeName), nil
+}
+
+func save(key string, data []byte) error {
+	tx, store, err := objectStore("readwrite")
+	if err != nil {
+		return err
+	}
+	v := uint8js.New(len(data))
+	js.CopyBytesToJS(v, data)
+	store.Call("put", v, key)
+	return wait(tx, "oncomplete", "onerror", "onabort")
+}
+
+func load(key string) ([]byte, error) {
+	_, store, err := objectStore("readonly")
+	if err != nil {
+		return nil, err
+	}
+	req := store.Call("get", key)
+	if err := wait(req, "onsuccess", "onerror"); err != nil {
+		return nil, err
+	}
+	v := req.Get("result")
+	if v.IsUndefined() {
+		return nil, fmt.Errorf("storage: loading %q failed: %w", key, os.ErrNotExist)
+	}
+	data := make([]byte, v.Get("byteLength").Int())
+	js.CopyBytesToGo(data, v)
+	return data, nil
+}
+
+func remove(key string) error {
+	tx, store, err := objectStore("readwrite")
+	if err != nil {
+		return err
+	}
+	store.Call("delete", key)
+	return wait(tx, "oncomplete", "onerror", "onabort")
+}
@@ -0,0 +1,158 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2/exp/storage"
+)
+
+func TestMain(m *testing.M) {
+	// Keep the test data out of the user's configuration directory on desktops.
+	// On browsers, the data is stored in IndexedDB and the environment variables are not used.
+	dir, err := ioutil.TempDir("", "ebitenstorage-")
+	if err != nil {
+		panic(err)
+	}
+	for _, name := range []string{"XDG_CONFIG_HOME", "HOME", "AppData"} {
+		if err := os.Setenv(name, dir); err != nil {
+			panic(err)
+		}
+	}
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func TestSaveLoad(t *testing.T) {
+	const key = "TestSaveLoad"
+	defer storage.Delete(key)
+
+	want := []byte("save data")
+	if err := storage.Save(key, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := storage.Load(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("got: %q, want: %q", got, want)
+	}
+}
+
+func TestSaveOverwrite(t *testing.T) {
+	const key = "TestSaveOverwrite"
+	defer storage.Delete(key)
+
+	if err := storage.Save(key, []byte("old data which is longer")); err != nil {
+		t.Fatal(err)
+	}
+	want := []byte("new data")
+	if err := storage.Save(key, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := storage.Load(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("got: %q, want: %q", got, want)
+	}
+}
+
+func TestSaveEmptyData(t *testing.T) {
+	const key = "TestSaveEmptyData"
+	defer storage.Delete(key)
+
+	if err := storage.Save(key, nil); err != nil {
+		t.Fatal(err)
+	}
+	got, err := storage.Load(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("got: %q, want: empty", got)
+	}
+}
+
+func TestKeys(t *testing.T) {
+	// Keys that differ only in case or contain path separators must not conflict.
+	keys := []string{"a", "A", "a/b", "../a", "日本語", "a b\\c:d"}
+	defer func() {
+		for _, key := range keys {
+			storage.Delete(key)
+		}
+	}()
+
+	for i, key := range keys {
+		if err := storage.Save(key, []byte{byte(i)}); err != nil {
+			t.Fatalf("storage.Save(%q): %v", key, err)
+		}
+	}
+	for i, key := range keys {
+		got, err := storage.Load(key)
+		if err != nil {
+			t.Fatalf("storage.Load(%q): %v", key, err)
+		}
+		if want := []byte{byte(i)}; !bytes.Equal(got, want) {
+			t.Errorf("storage.Load(%q): got: %v, want: %v", key, got, want)
+		}
+	}
+}
+
+func TestLoadNotExist(t *testing.T) {
+	if _, err := storage.Load("TestLoadNotExist"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("got: %v, want: %v", err, os.ErrNotExist)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	const key = "TestDelete"
+
+	if err := storage.Save(key, []byte("data")); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.Delete(key); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := storage.Load(key); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("got: %v, want: %v", err, os.ErrNotExist)
+	}
+
+	// Deleting data that doesn't exist is not an error.
+	if err := storage.Delete(key); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestEmptyKey(t *testing.T) {
+	if err := storage.Save("", []byte("data")); err == nil {
+		t.Errorf("storage.Save with an empty key must return an error")
+	}
+	if _, err := storage.Load(""); err == nil {
+		t.Errorf("storage.Load with an empty key must return an error")
+	}
+	if err := storage.Delete(""); err == nil {
+		t.Errorf("storage.Delete with an empty key must return an error")
+	}
+}
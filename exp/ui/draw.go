@@ -0,0 +1,127 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+)
+
+var (
+	emptyImage    = ebiten.NewImage(3, 3)
+	emptySubImage = emptyImage.SubImage(image.Rect(1, 1, 2, 2)).(*ebiten.Image)
+)
+
+func init() {
+	emptyImage.Fill(color.White)
+}
+
+// command is a drawing command, which is a filled rectangle or a text.
+type command struct {
+	rect image.Rectangle
+	clr  color.Color
+
+	text string
+	x    int
+	y    int
+}
+
+func (c *Context) fillRect(r image.Rectangle, clr color.Color) {
+	c.commands = append(c.commands, command{
+		rect: r,
+		clr:  clr,
+	})
+}
+
+// drawText draws the text in the region r, vertically centered.
+func (c *Context) drawText(str string, r image.Rectangle, center bool, clr color.Color) {
+	if str == "" {
+		return
+	}
+	m := c.face.Metrics()
+	x := r.Min.X + c.style.Padding
+	if center {
+		x = r.Min.X + (r.Dx()-c.textWidth(str))/2
+	}
+	y := r.Min.Y + (r.Dy()-c.lineHeight())/2 + m.Ascent.Ceil()
+	c.commands = append(c.commands, command{
+		clr:  clr,
+		text: str,
+		x:    x,
+		y:    y,
+	})
+}
+
+// Draw draws the widgets declared in the last tick.
+//
+// The consecutive rectangles are drawn by one DrawTriangles call.
+func (c *Context) Draw(screen *ebiten.Image) {
+	for _, cmd := range c.commands {
+		if cmd.text != "" {
+			c.flush(screen)
+			text.Draw(screen, cmd.text, c.face, cmd.x, cmd.y, cmd.clr)
+			continue
+		}
+		c.appendRect(screen, cmd.rect, cmd.clr)
+	}
+	c.flush(screen)
+}
+
+func (c *Context) appendRect(screen *ebiten.Image, r image.Rectangle, clr color.Color) {
+	if r.Empty() {
+		return
+	}
+	cr, cg, cb, ca := clr.RGBA()
+	if ca == 0 {
+		return
+	}
+	// The vertex colors are not premultiplied.
+	rf := float32(cr) / float32(ca)
+	gf := float32(cg) / float32(ca)
+	bf := float32(cb) / float32(ca)
+	af := float32(ca) / 0xffff
+
+	// Flush the rectangles before the indices overflow.
+	if len(c.vertices)+4 > 1<<16 {
+		c.flush(screen)
+	}
+
+	idx := uint16(len(c.vertices))
+	for _, p := range []image.Point{r.Min, {X: r.Max.X, Y: r.Min.Y}, {X: r.Min.X, Y: r.Max.Y}, r.Max} {
+		c.vertices = append(c.vertices, ebiten.Vertex{
+			DstX:   float32(p.X),
+			DstY:   float32(p.Y),
+			SrcX:   1,
+			SrcY:   1,
+			ColorR: rf,
+			ColorG: gf,
+			ColorB: bf,
+			ColorA: af,
+		})
+	}
+	c.indices = append(c.indices, idx, idx+1, idx+2, idx+1, idx+3, idx+2)
+}
+
+func (c *Context) flush(screen *ebiten.Image) {
+	if len(c.indices) == 0 {
+		return
+	}
+	screen.DrawTriangles(c.vertices, c.indices, emptySubImage, nil)
+	c.vertices = c.vertices[:0]
+	c.indices = c.indices[:0]
+}
@@ -0,0 +1,141 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"image"
+)
+
+type panel struct {
+	title string
+	x     int
+	y     int
+	width int
+
+	// cursorY is the y position of the next widget.
+	cursorY int
+
+	// backgroundIndex is the index of the command to draw the panel's background.
+	backgroundIndex int
+
+	columns int
+	column  int
+}
+
+// BeginPanel starts a panel at (x, y) with the width. The widgets are laid out vertically in the panel.
+//
+// If title is not empty, the title is shown at the top of the panel. The title must be unique among the panels.
+//
+// BeginPanel must be called between Begin and End. Panels cannot be nested.
+func (c *Context) BeginPanel(title string, x, y, width int) {
+	if !c.inFrame {
+		panic("ui: BeginPanel must be called between Begin and End")
+	}
+	if len(c.panels) > 0 {
+		panic("ui: panels cannot be nested")
+	}
+
+	p := &panel{
+		title:           title,
+		x:               x,
+		y:               y,
+		width:           width,
+		cursorY:         y + c.style.Padding,
+		backgroundIndex: len(c.commands),
+	}
+	c.panels = append(c.panels, p)
+	c.fillRect(image.Rectangle{}, c.style.PanelColor)
+
+	if title != "" {
+		h := c.widgetHeight()
+		r := image.Rect(x, y, x+width, y+h)
+		c.fillRect(r, c.style.TitleColor)
+		c.drawText(displayLabel(title), r, false, c.style.TextColor)
+		p.cursorY = y + h + c.style.Padding
+	}
+}
+
+// EndPanel finishes the current panel.
+func (c *Context) EndPanel() {
+	p := c.currentPanel()
+	if p.columns > 0 {
+		panic("ui: EndRow is not called")
+	}
+	c.panels = c.panels[:len(c.panels)-1]
+
+	r := image.Rect(p.x, p.y, p.x+p.width, p.cursorY)
+	c.commands[p.backgroundIndex].rect = r
+	if c.cursor.In(r) {
+		c.hovered = true
+	}
+}
+
+// BeginRow starts a row in the current panel. The next widgets are laid out horizontally in the row with the
+// same width, and the row has the given number of columns. If more widgets than columns are declared, the
+// widgets continue on the next line.
+func (c *Context) BeginRow(columns int) {
+	p := c.currentPanel()
+	if p.columns > 0 {
+		panic("ui: rows cannot be nested")
+	}
+	if columns <= 0 {
+		panic("ui: columns must be positive")
+	}
+	p.columns = columns
+	p.column = 0
+}
+
+// EndRow finishes the current row.
+func (c *Context) EndRow() {
+	p := c.currentPanel()
+	if p.columns == 0 {
+		panic("ui: EndRow is called without BeginRow")
+	}
+	if p.column > 0 {
+		p.cursorY += c.widgetHeight() + c.style.Padding
+	}
+	p.columns = 0
+	p.column = 0
+}
+
+func (c *Context) currentPanel() *panel {
+	if len(c.panels) == 0 {
+		panic("ui: widgets must be declared between BeginPanel and EndPanel")
+	}
+	return c.panels[len(c.panels)-1]
+}
+
+// allocate returns the region of the next widget.
+func (c *Context) allocate() image.Rectangle {
+	p := c.currentPanel()
+	h := c.widgetHeight()
+	pad := c.style.Padding
+
+	if p.columns > 0 {
+		w := (p.width - pad*(p.columns+1)) / p.columns
+		x := p.x + pad + p.column*(w+pad)
+		r := image.Rect(x, p.cursorY, x+w, p.cursorY+h)
+		p.column++
+		if p.column == p.columns {
+			p.column = 0
+			p.cursorY += h + pad
+		}
+		return r
+	}
+
+	r := image.Rect(p.x+pad, p.cursorY, p.x+p.width-pad, p.cursorY+h)
+	p.cursorY += h + pad
+	return r
+}
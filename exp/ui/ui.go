@@ -0,0 +1,215 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ui offers a minimal immediate-mode UI toolkit, e.g., for debug tools and options menus.
+//
+// The widgets are declared between Begin and End in every Update, and the functions of the widgets report the
+// user's interactions immediately. The declared widgets are rendered by Draw, which should be called in Draw.
+//
+//	func (g *Game) Update() error {
+//		g.ui.Begin()
+//		g.ui.BeginPanel("Options", 10, 10, 200)
+//		if g.ui.Button("Start") {
+//			// Start the game.
+//		}
+//		g.ui.Slider("Volume", &g.volume, 0, 1)
+//		g.ui.EndPanel()
+//		g.ui.End()
+//		return nil
+//	}
+//
+//	func (g *Game) Draw(screen *ebiten.Image) {
+//		g.ui.Draw(screen)
+//	}
+//
+// A widget is identified by its panel's title and its label. To have multiple widgets with the same label in a
+// panel, add a suffix starting with "##" to the labels, like "OK##1" and "OK##2". The suffixes are not shown.
+//
+// This package is experimental and the API might be changed in the future.
+package ui
+
+import (
+	"image"
+	"image/color"
+	"strings"
+
+	"golang.org/x/image/font"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Style represents the appearance of the widgets.
+type Style struct {
+	// Padding is the space between the widgets and inside the widgets in pixels.
+	Padding int
+
+	TextColor        color.Color
+	PlaceholderColor color.Color
+	PanelColor       color.Color
+	TitleColor       color.Color
+	WidgetColor      color.Color
+	HoveredColor     color.Color
+	ActiveColor      color.Color
+	AccentColor      color.Color
+}
+
+// DefaultStyle returns the default style.
+func DefaultStyle() Style {
+	return Style{
+		Padding:          4,
+		TextColor:        color.White,
+		PlaceholderColor: color.Gray{Y: 0x80},
+		PanelColor:       color.RGBA{R: 0x20, G: 0x20, B: 0x20, A: 0xe0},
+		TitleColor:       color.RGBA{R: 0x10, G: 0x30, B: 0x60, A: 0xff},
+		WidgetColor:      color.RGBA{R: 0x40, G: 0x40, B: 0x40, A: 0xff},
+		HoveredColor:     color.RGBA{R: 0x50, G: 0x50, B: 0x50, A: 0xff},
+		ActiveColor:      color.RGBA{R: 0x30, G: 0x30, B: 0x30, A: 0xff},
+		AccentColor:      color.RGBA{R: 0x30, G: 0x70, B: 0xd0, A: 0xff},
+	}
+}
+
+// Context holds the states of the widgets.
+type Context struct {
+	face  font.Face
+	style Style
+
+	cursor   image.Point
+	touching bool
+	down     bool
+	prevDown bool
+	pressed  bool
+	released bool
+	chars    []rune
+
+	// active is the ID of the widget being pressed.
+	active string
+
+	// focus is the ID of the text input having the keyboard focus.
+	focus        string
+	focusClaimed bool
+
+	inFrame   bool
+	panels    []*panel
+	hovered   bool
+	capturing bool
+
+	commands []command
+	vertices []ebiten.Vertex
+	indices  []uint16
+}
+
+// NewContext creates a new context with the font face for the texts.
+func NewContext(face font.Face) *Context {
+	return &Context{
+		face:  face,
+		style: DefaultStyle(),
+	}
+}
+
+// Style returns the current style.
+func (c *Context) Style() Style {
+	return c.style
+}
+
+// SetStyle sets the style.
+func (c *Context) SetStyle(style Style) {
+	c.style = style
+}
+
+// Begin starts declaring the widgets for the current tick, and reads the input states.
+//
+// Begin must be called in Update.
+func (c *Context) Begin() {
+	if c.inFrame {
+		panic("ui: Begin is called twice without End")
+	}
+	c.inFrame = true
+	c.commands = c.commands[:0]
+	c.hovered = false
+	c.focusClaimed = false
+
+	c.prevDown = c.down
+	if ids := ebiten.AppendTouchIDs(nil); len(ids) > 0 {
+		c.cursor = image.Pt(ebiten.TouchPosition(ids[0]))
+		c.touching = true
+		c.down = true
+	} else {
+		// Keep the last cursor position when a touch is released, as a touch doesn't have a position after
+		// the release.
+		if !c.touching {
+			c.cursor = image.Pt(ebiten.CursorPosition())
+		}
+		c.touching = false
+		c.down = ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft)
+	}
+	c.pressed = c.down && !c.prevDown
+	c.released = !c.down && c.prevDown
+	c.chars = ebiten.AppendInputChars(c.chars[:0])
+}
+
+// End finishes declaring the widgets for the current tick.
+//
+// End must be called in Update.
+func (c *Context) End() {
+	if !c.inFrame {
+		panic("ui: End is called without Begin")
+	}
+	if len(c.panels) > 0 {
+		panic("ui: EndPanel is not called")
+	}
+	c.inFrame = false
+
+	if c.released {
+		c.active = ""
+	}
+	// Clicking outside of the focused text input removes the focus.
+	if c.pressed && !c.focusClaimed {
+		c.focus = ""
+	}
+	c.capturing = c.hovered || c.active != "" || c.focus != ""
+}
+
+// IsCapturingInput reports whether the UI uses the input in the current tick, i.e., the cursor is on a panel,
+// a widget is being pressed, or a text input has the keyboard focus.
+//
+// The game should ignore the mouse and keyboard input when IsCapturingInput returns true.
+// IsCapturingInput reports the state as of the last End call.
+func (c *Context) IsCapturingInput() bool {
+	return c.capturing
+}
+
+func (c *Context) lineHeight() int {
+	return c.face.Metrics().Height.Ceil()
+}
+
+func (c *Context) widgetHeight() int {
+	return c.lineHeight() + 2*c.style.Padding
+}
+
+func (c *Context) textWidth(text string) int {
+	return font.MeasureString(c.face, text).Ceil()
+}
+
+// id returns the widget ID for the label in the current panel.
+func (c *Context) id(label string) string {
+	return c.currentPanel().title + "\x00" + label
+}
+
+// displayLabel returns the label without the ID suffix.
+func displayLabel(label string) string {
+	if i := strings.Index(label, "##"); i >= 0 {
+		return label[:i]
+	}
+	return label
+}
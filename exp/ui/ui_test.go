@@ -0,0 +1,345 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui_test
+
+import (
+	"testing"
+
+	"golang.org/x/image/font/basicfont"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/exp/ui"
+	"github.com/hajimehoshi/ebiten/v2/internal/fakeinput"
+)
+
+// The first widget in a panel at (0, 0) with the width 200 is at (4, 4)-(196, 25) with the default style and
+// basicfont.Face7x13.
+const (
+	widgetX = 10
+	widgetY = 10
+)
+
+// frame declares the widgets by f in a panel with the given cursor state.
+func frame(c *ui.Context, x, y int, pressed bool, chars []rune, f func()) {
+	s := &fakeinput.State{
+		CursorX: x,
+		CursorY: y,
+		Chars:   chars,
+	}
+	if pressed {
+		s.MouseButtons = []ebiten.MouseButton{ebiten.MouseButtonLeft}
+	}
+	fakeinput.SetState(s)
+
+	c.Begin()
+	c.BeginPanel("", 0, 0, 200)
+	f()
+	c.EndPanel()
+	c.End()
+}
+
+func TestButton(t *testing.T) {
+	fakeinput.Enable()
+	defer fakeinput.Disable()
+
+	c := ui.NewContext(basicfont.Face7x13)
+	var clicked bool
+	button := func() {
+		clicked = c.Button("OK")
+	}
+
+	frame(c, widgetX, widgetY, true, nil, button)
+	if clicked {
+		t.Errorf("Button must not be clicked while pressed")
+	}
+	frame(c, widgetX, widgetY, false, nil, button)
+	if !clicked {
+		t.Errorf("Button must be clicked when released")
+	}
+	frame(c, widgetX, widgetY, false, nil, button)
+	if clicked {
+		t.Errorf("Button must not be clicked after released")
+	}
+
+	// Releasing outside of the button cancels the click.
+	frame(c, widgetX, widgetY, true, nil, button)
+	frame(c, 300, 300, false, nil, button)
+	if clicked {
+		t.Errorf("Button must not be clicked when released outside")
+	}
+
+	// Pressing outside and releasing on the button is not a click.
+	frame(c, 300, 300, true, nil, button)
+	frame(c, widgetX, widgetY, false, nil, button)
+	if clicked {
+		t.Errorf("Button must not be clicked when pressed outside")
+	}
+}
+
+func TestButtonTouch(t *testing.T) {
+	fakeinput.Enable()
+	defer fakeinput.Disable()
+
+	c := ui.NewContext(basicfont.Face7x13)
+	var clicked bool
+	button := func() {
+		clicked = c.Button("OK")
+	}
+
+	fakeinput.SetState(&fakeinput.State{
+		CursorX: 300,
+		CursorY: 300,
+		Touches: []fakeinput.Touch{{ID: 1, X: widgetX, Y: widgetY}},
+	})
+	c.Begin()
+	c.BeginPanel("", 0, 0, 200)
+	button()
+	c.EndPanel()
+	c.End()
+	if clicked {
+		t.Errorf("Button must not be clicked while touched")
+	}
+
+	// The touch position is kept after the touch is released, regardless of the cursor position.
+	frame(c, 300, 300, false, nil, button)
+	if !clicked {
+		t.Errorf("Button must be clicked when the touch is released")
+	}
+}
+
+func TestCheckbox(t *testing.T) {
+	fakeinput.Enable()
+	defer fakeinput.Disable()
+
+	c := ui.NewContext(basicfont.Face7x13)
+	var value, changed bool
+	checkbox := func() {
+		changed = c.Checkbox("Fullscreen", &value)
+	}
+
+	frame(c, widgetX, widgetY, true, nil, checkbox)
+	frame(c, widgetX, widgetY, false, nil, checkbox)
+	if !changed || !value {
+		t.Errorf("Checkbox: got: (%t, %t), want: (true, true)", changed, value)
+	}
+	frame(c, widgetX, widgetY, true, nil, checkbox)
+	frame(c, widgetX, widgetY, false, nil, checkbox)
+	if !changed || value {
+		t.Errorf("Checkbox: got: (%t, %t), want: (true, false)", changed, value)
+	}
+}
+
+func TestSlider(t *testing.T) {
+	fakeinput.Enable()
+	defer fakeinput.Disable()
+
+	c := ui.NewContext(basicfont.Face7x13)
+	var changed bool
+	value := 0.25
+	slider := func() {
+		changed = c.Slider("Volume", &value, 0, 1)
+	}
+
+	frame(c, 100, widgetY, false, nil, slider)
+	if changed || value != 0.25 {
+		t.Errorf("Slider without pressing: got: (%t, %f), want: (false, 0.25)", changed, value)
+	}
+
+	frame(c, 100, widgetY, true, nil, slider)
+	if !changed || value != 0.5 {
+		t.Errorf("Slider: got: (%t, %f), want: (true, 0.5)", changed, value)
+	}
+
+	// Dragging keeps changing the value even outside of the slider.
+	frame(c, 1000, 1000, true, nil, slider)
+	if !changed || value != 1 {
+		t.Errorf("Slider dragged outside: got: (%t, %f), want: (true, 1)", changed, value)
+	}
+	frame(c, -1000, widgetY, true, nil, slider)
+	if !changed || value != 0 {
+		t.Errorf("Slider dragged outside: got: (%t, %f), want: (true, 0)", changed, value)
+	}
+}
+
+func TestTextInput(t *testing.T) {
+	fakeinput.Enable()
+	defer fakeinput.Disable()
+
+	c := ui.NewContext(basicfont.Face7x13)
+	var text string
+	var changed bool
+	input := func() {
+		changed = c.TextInput("Name", &text)
+	}
+
+	frame(c, widgetX, widgetY, false, []rune("a"), input)
+	if changed || text != "" {
+		t.Errorf("TextInput without the focus: got: (%t, %q), want: (false, %q)", changed, text, "")
+	}
+
+	frame(c, widgetX, widgetY, true, nil, input)
+	frame(c, widgetX, widgetY, false, nil, input)
+	if !c.IsCapturingInput() {
+		t.Errorf("IsCapturingInput with the focus: got: false, want: true")
+	}
+
+	frame(c, 300, 300, false, []rune("あb"), input)
+	if !changed || text != "あb" {
+		t.Errorf("TextInput with the focus: got: (%t, %q), want: (true, %q)", changed, text, "あb")
+	}
+
+	// Clicking outside removes the focus.
+	frame(c, 300, 300, true, nil, input)
+	frame(c, 300, 300, false, []rune("c"), input)
+	if changed || text != "あb" {
+		t.Errorf("TextInput after losing the focus: got: (%t, %q), want: (false, %q)", changed, text, "あb")
+	}
+	if c.IsCapturingInput() {
+		t.Errorf("IsCapturingInput without the focus: got: true, want: false")
+	}
+}
+
+func TestIsCapturingInput(t *testing.T) {
+	fakeinput.Enable()
+	defer fakeinput.Disable()
+
+	c := ui.NewContext(basicfont.Face7x13)
+	label := func() {
+		c.Label("Hello")
+	}
+
+	frame(c, widgetX, widgetY, false, nil, label)
+	if !c.IsCapturingInput() {
+		t.Errorf("IsCapturingInput on the panel: got: false, want: true")
+	}
+	frame(c, 300, 300, false, nil, label)
+	if c.IsCapturingInput() {
+		t.Errorf("IsCapturingInput outside of the panel: got: true, want: false")
+	}
+}
+
+func TestSameLabels(t *testing.T) {
+	fakeinput.Enable()
+	defer fakeinput.Disable()
+
+	c := ui.NewContext(basicfont.Face7x13)
+	var clicked0, clicked1 bool
+	buttons := func() {
+		clicked0 = c.Button("OK##0")
+		clicked1 = c.Button("OK##1")
+	}
+
+	frame(c, widgetX, widgetY, true, nil, buttons)
+	frame(c, widgetX, widgetY, false, nil, buttons)
+	if !clicked0 || clicked1 {
+		t.Errorf("Button: got: (%t, %t), want: (true, false)", clicked0, clicked1)
+	}
+}
+
+func TestRow(t *testing.T) {
+	fakeinput.Enable()
+	defer fakeinput.Disable()
+
+	c := ui.NewContext(basicfont.Face7x13)
+	var clicked [3]bool
+	buttons := func() {
+		c.BeginRow(2)
+		clicked[0] = c.Button("A")
+		clicked[1] = c.Button("B")
+		clicked[2] = c.Button("C")
+		c.EndRow()
+	}
+
+	// The second column starts at x = 4 + 94 + 4 = 102.
+	frame(c, 150, widgetY, true, nil, buttons)
+	frame(c, 150, widgetY, false, nil, buttons)
+	if clicked != [3]bool{false, true, false} {
+		t.Errorf("Button: got: %v, want: %v", clicked, [3]bool{false, true, false})
+	}
+
+	// The third button is on the next line.
+	frame(c, widgetX, 40, true, nil, buttons)
+	frame(c, widgetX, 40, false, nil, buttons)
+	if clicked != [3]bool{false, false, true} {
+		t.Errorf("Button: got: %v, want: %v", clicked, [3]bool{false, false, true})
+	}
+}
+
+func TestInvalidCalls(t *testing.T) {
+	fakeinput.Enable()
+	defer fakeinput.Disable()
+
+	tests := []struct {
+		name string
+		f    func(c *ui.Context)
+	}{
+		{
+			name: "End without Begin",
+			f: func(c *ui.Context) {
+				c.End()
+			},
+		},
+		{
+			name: "Begin twice",
+			f: func(c *ui.Context) {
+				c.Begin()
+				c.Begin()
+			},
+		},
+		{
+			name: "widget without panel",
+			f: func(c *ui.Context) {
+				c.Begin()
+				c.Button("OK")
+			},
+		},
+		{
+			name: "nested panels",
+			f: func(c *ui.Context) {
+				c.Begin()
+				c.BeginPanel("A", 0, 0, 100)
+				c.BeginPanel("B", 0, 0, 100)
+			},
+		},
+		{
+			name: "End without EndPanel",
+			f: func(c *ui.Context) {
+				c.Begin()
+				c.BeginPanel("A", 0, 0, 100)
+				c.End()
+			},
+		},
+		{
+			name: "EndPanel without EndRow",
+			f: func(c *ui.Context) {
+				c.Begin()
+				c.BeginPanel("A", 0, 0, 100)
+				c.BeginRow(2)
+				c.EndPanel()
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("must panic")
+				}
+			}()
+			tc.f(ui.NewContext(basicfont.Face7x13))
+		})
+	}
+}
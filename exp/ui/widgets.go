@@ -0,0 +1,172 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"unicode/utf8"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Label shows the text.
+func (c *Context) Label(text string) {
+	r := c.allocate()
+	c.drawText(displayLabel(text), r, false, c.style.TextColor)
+}
+
+// Button shows a button with the label, and reports whether the button is clicked.
+func (c *Context) Button(label string) bool {
+	r := c.allocate()
+	id := c.id(label)
+	clicked := c.handlePress(id, r) && c.released && c.cursor.In(r)
+
+	c.fillRect(r, c.widgetColor(id, r))
+	c.drawText(displayLabel(label), r, true, c.style.TextColor)
+	return clicked
+}
+
+// Checkbox shows a checkbox with the label for value, and reports whether value is changed.
+func (c *Context) Checkbox(label string, value *bool) bool {
+	r := c.allocate()
+	id := c.id(label)
+	changed := false
+	if c.handlePress(id, r) && c.released && c.cursor.In(r) {
+		*value = !*value
+		changed = true
+	}
+
+	pad := c.style.Padding
+	box := image.Rect(r.Min.X, r.Min.Y, r.Min.X+r.Dy(), r.Max.Y)
+	c.fillRect(box, c.widgetColor(id, r))
+	if *value {
+		c.fillRect(box.Inset(pad), c.style.AccentColor)
+	}
+	c.drawText(displayLabel(label), image.Rect(box.Max.X, r.Min.Y, r.Max.X, r.Max.Y), false, c.style.TextColor)
+	return changed
+}
+
+// Slider shows a slider with the label for value in [min, max], and reports whether value is changed.
+func (c *Context) Slider(label string, value *float64, min, max float64) bool {
+	r := c.allocate()
+	id := c.id(label)
+	changed := false
+	if c.handlePress(id, r) && r.Dx() > 0 {
+		rate := float64(c.cursor.X-r.Min.X) / float64(r.Dx())
+		if rate < 0 {
+			rate = 0
+		}
+		if rate > 1 {
+			rate = 1
+		}
+		if v := min + (max-min)*rate; v != *value {
+			*value = v
+			changed = true
+		}
+	}
+
+	c.fillRect(r, c.widgetColor(id, r))
+	if max > min {
+		rate := (*value - min) / (max - min)
+		if rate < 0 {
+			rate = 0
+		}
+		if rate > 1 {
+			rate = 1
+		}
+		c.fillRect(image.Rect(r.Min.X, r.Min.Y, r.Min.X+int(float64(r.Dx())*rate), r.Max.Y), c.style.AccentColor)
+	}
+	c.drawText(fmt.Sprintf("%s: %.2f", displayLabel(label), *value), r, true, c.style.TextColor)
+	return changed
+}
+
+// TextInput shows a single-line text input for text, and reports whether text is changed.
+//
+// The label is shown when text is empty and the text input doesn't have the focus. Clicking the text input gives
+// it the keyboard focus, and pressing Enter or clicking outside of it removes the focus.
+//
+// The characters are read by ebiten.AppendInputChars, so the texts composed by input methods are also available.
+func (c *Context) TextInput(label string, text *string) bool {
+	r := c.allocate()
+	id := c.id(label)
+	if c.pressed && c.cursor.In(r) {
+		c.focus = id
+		c.focusClaimed = true
+	}
+
+	changed := false
+	focused := c.focus == id
+	if focused {
+		if len(c.chars) > 0 {
+			*text += string(c.chars)
+			changed = true
+		}
+		if d := inpututil.KeyPressDuration(ebiten.KeyBackspace); d == 1 || (d >= 30 && d%3 == 0) {
+			if _, size := utf8.DecodeLastRuneInString(*text); size > 0 {
+				*text = (*text)[:len(*text)-size]
+				changed = true
+			}
+		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyKPEnter) {
+			c.focus = ""
+		}
+	}
+
+	clr := c.style.WidgetColor
+	if focused {
+		clr = c.style.ActiveColor
+	}
+	c.fillRect(r, clr)
+
+	if *text == "" && !focused {
+		c.drawText(displayLabel(label), r, false, c.style.PlaceholderColor)
+		return changed
+	}
+
+	// Show the tail of the text that fits in the text input.
+	pad := c.style.Padding
+	t := *text
+	for t != "" && c.textWidth(t)+pad*3 > r.Dx() {
+		_, size := utf8.DecodeRuneInString(t)
+		t = t[size:]
+	}
+	c.drawText(t, r, false, c.style.TextColor)
+	if focused {
+		x := r.Min.X + pad + c.textWidth(t)
+		c.fillRect(image.Rect(x, r.Min.Y+pad, x+1, r.Max.Y-pad), c.style.TextColor)
+	}
+	return changed
+}
+
+// handlePress updates the active widget and reports whether the widget (id) is active.
+func (c *Context) handlePress(id string, r image.Rectangle) bool {
+	if c.pressed && c.cursor.In(r) {
+		c.active = id
+	}
+	return c.active == id
+}
+
+func (c *Context) widgetColor(id string, r image.Rectangle) color.Color {
+	switch {
+	case c.active == id:
+		return c.style.ActiveColor
+	case c.active == "" && c.cursor.In(r):
+		return c.style.HoveredColor
+	}
+	return c.style.WidgetColor
+}
@@ -0,0 +1,189 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package viewport offers sub-viewports of the screen, e.g., for split-screen games.
+//
+// Each viewport has its own logical screen size determined by its Layouter, in the same way as ebiten.Game's Layout.
+// A game draws each viewport's content to the viewport's own screen image, and the viewport scales and composites
+// the image into its region of the actual screen.
+// The viewport also converts positions on the actual screen, like cursor positions, to its logical coordinates.
+//
+// This package is experimental and the API might be changed in the future.
+package viewport
+
+import (
+	"image"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Layouter determines the logical screen size of a viewport.
+type Layouter interface {
+	// Layout accepts the size of the viewport's region on the actual screen, and returns the logical screen size.
+	Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int)
+}
+
+// LayoutFunc is a function type that implements Layouter.
+type LayoutFunc func(outsideWidth, outsideHeight int) (screenWidth, screenHeight int)
+
+// Layout implements Layouter.
+func (f LayoutFunc) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
+	return f(outsideWidth, outsideHeight)
+}
+
+// Viewport is a region of the actual screen with its own logical screen.
+type Viewport struct {
+	layouter Layouter
+	bounds   image.Rectangle
+	screen   *ebiten.Image
+}
+
+// New creates a new viewport with the given layouter.
+//
+// The bounds of the viewport is empty until SetBounds is called.
+func New(layouter Layouter) *Viewport {
+	return &Viewport{
+		layouter: layouter,
+	}
+}
+
+// Bounds returns the region of the viewport on the actual screen.
+func (v *Viewport) Bounds() image.Rectangle {
+	return v.bounds
+}
+
+// SetBounds sets the region of the viewport on the actual screen.
+//
+// bounds is in the coordinates of the image given to Draw, which is usually the screen image given to the game's Draw.
+func (v *Viewport) SetBounds(bounds image.Rectangle) {
+	v.bounds = bounds.Canon()
+}
+
+func (v *Viewport) screenSize() (int, int) {
+	if v.bounds.Empty() {
+		return 0, 0
+	}
+	w, h := v.layouter.Layout(v.bounds.Dx(), v.bounds.Dy())
+	if w <= 0 || h <= 0 {
+		panic("viewport: Layout must return positive numbers")
+	}
+	return w, h
+}
+
+// Screen returns the logical screen image of the viewport.
+// The size of the image is the size returned by the layouter.
+//
+// The image is cleared at every Draw call. Draw the viewport's content to the image before calling Draw.
+//
+// Screen returns nil when the bounds is empty.
+func (v *Viewport) Screen() *ebiten.Image {
+	w, h := v.screenSize()
+	if w == 0 || h == 0 {
+		return nil
+	}
+	if v.screen != nil {
+		if sw, sh := v.screen.Size(); sw != w || sh != h {
+			v.screen.Dispose()
+			v.screen = nil
+		}
+	}
+	if v.screen == nil {
+		v.screen = ebiten.NewImage(w, h)
+	}
+	return v.screen
+}
+
+// scaleAndOffsets returns the scale and the offsets to fit the logical screen into the bounds with keeping
+// the aspect ratio.
+func (v *Viewport) scaleAndOffsets() (scale, offsetX, offsetY float64) {
+	w, h := v.screenSize()
+	if w == 0 || h == 0 {
+		return 0, 0, 0
+	}
+	bw, bh := float64(v.bounds.Dx()), float64(v.bounds.Dy())
+	scale = math.Min(bw/float64(w), bh/float64(h))
+	offsetX = float64(v.bounds.Min.X) + (bw-float64(w)*scale)/2
+	offsetY = float64(v.bounds.Min.Y) + (bh-float64(h)*scale)/2
+	return
+}
+
+// Draw scales and draws the logical screen image into the viewport's bounds on dst, and clears the logical screen.
+//
+// The logical screen is centered in the bounds with keeping its aspect ratio, and the dst pixels around it are
+// not changed.
+func (v *Viewport) Draw(dst *ebiten.Image) {
+	if v.screen == nil {
+		return
+	}
+	s, ox, oy := v.scaleAndOffsets()
+	if s == 0 {
+		return
+	}
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(s, s)
+	op.GeoM.Translate(ox, oy)
+	if math.Floor(s) != s {
+		op.Filter = ebiten.FilterLinear
+	}
+	dst.SubImage(v.bounds).(*ebiten.Image).DrawImage(v.screen, op)
+
+	v.screen.Clear()
+}
+
+// ToLocal converts the position (x, y) on the actual screen to the position on the viewport's logical screen.
+//
+// ok is false when the position is outside of the logical screen in the bounds.
+func (v *Viewport) ToLocal(x, y float64) (localX, localY float64, ok bool) {
+	s, ox, oy := v.scaleAndOffsets()
+	if s == 0 {
+		return 0, 0, false
+	}
+	w, h := v.screenSize()
+	localX = (x - ox) / s
+	localY = (y - oy) / s
+	ok = 0 <= localX && localX < float64(w) && 0 <= localY && localY < float64(h)
+	return
+}
+
+// CursorPosition returns the cursor position on the viewport's logical screen.
+//
+// ok is false when the cursor is outside of the viewport.
+func (v *Viewport) CursorPosition() (x, y int, ok bool) {
+	cx, cy := ebiten.CursorPosition()
+	lx, ly, ok := v.ToLocal(float64(cx), float64(cy))
+	return int(math.Floor(lx)), int(math.Floor(ly)), ok
+}
+
+// TouchPosition returns the position of the touch on the viewport's logical screen.
+//
+// ok is false when the touch is outside of the viewport or the touch doesn't exist.
+func (v *Viewport) TouchPosition(id ebiten.TouchID) (x, y int, ok bool) {
+	if !touchExists(id) {
+		return 0, 0, false
+	}
+	tx, ty := ebiten.TouchPosition(id)
+	lx, ly, ok := v.ToLocal(float64(tx), float64(ty))
+	return int(math.Floor(lx)), int(math.Floor(ly)), ok
+}
+
+func touchExists(id ebiten.TouchID) bool {
+	for _, t := range ebiten.AppendTouchIDs(nil) {
+		if t == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,171 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package viewport_test
+
+import (
+	"image"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2/exp/viewport"
+)
+
+func fixedLayout(width, height int) viewport.LayoutFunc {
+	return func(outsideWidth, outsideHeight int) (int, int) {
+		return width, height
+	}
+}
+
+func TestBounds(t *testing.T) {
+	v := viewport.New(fixedLayout(320, 240))
+	if got := v.Bounds(); !got.Empty() {
+		t.Errorf("Bounds(): got: %v, want: empty", got)
+	}
+	v.SetBounds(image.Rect(640, 480, 0, 0))
+	if got, want := v.Bounds(), image.Rect(0, 0, 640, 480); got != want {
+		t.Errorf("Bounds(): got: %v, want: %v", got, want)
+	}
+}
+
+func TestScreen(t *testing.T) {
+	v := viewport.New(viewport.LayoutFunc(func(outsideWidth, outsideHeight int) (int, int) {
+		return outsideWidth / 2, outsideHeight / 2
+	}))
+	if got := v.Screen(); got != nil {
+		t.Errorf("Screen() with empty bounds: got: %v, want: nil", got)
+	}
+
+	v.SetBounds(image.Rect(0, 0, 640, 480))
+	s := v.Screen()
+	if w, h := s.Size(); w != 320 || h != 240 {
+		t.Errorf("Screen().Size(): got: (%d, %d), want: (320, 240)", w, h)
+	}
+	if got := v.Screen(); got != s {
+		t.Errorf("Screen() must return the same image when the size is not changed")
+	}
+
+	v.SetBounds(image.Rect(0, 0, 200, 100))
+	if w, h := v.Screen().Size(); w != 100 || h != 50 {
+		t.Errorf("Screen().Size(): got: (%d, %d), want: (100, 50)", w, h)
+	}
+}
+
+func TestInvalidLayout(t *testing.T) {
+	v := viewport.New(fixedLayout(0, 240))
+	v.SetBounds(image.Rect(0, 0, 640, 480))
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Screen() with an invalid layout must panic")
+		}
+	}()
+	v.Screen()
+}
+
+func TestToLocal(t *testing.T) {
+	tests := []struct {
+		name   string
+		width  int
+		height int
+		bounds image.Rectangle
+		x, y   float64
+		localX float64
+		localY float64
+		ok     bool
+	}{
+		{
+			name:   "same size",
+			width:  320,
+			height: 240,
+			bounds: image.Rect(0, 0, 320, 240),
+			x:      10,
+			y:      20,
+			localX: 10,
+			localY: 20,
+			ok:     true,
+		},
+		{
+			name:   "scaled",
+			width:  320,
+			height: 240,
+			bounds: image.Rect(320, 0, 960, 480),
+			x:      340,
+			y:      100,
+			localX: 10,
+			localY: 50,
+			ok:     true,
+		},
+		{
+			name:   "letterbox",
+			width:  100,
+			height: 100,
+			bounds: image.Rect(0, 0, 400, 200),
+			x:      150,
+			y:      50,
+			localX: 25,
+			localY: 25,
+			ok:     true,
+		},
+		{
+			name:   "in the letterbox",
+			width:  100,
+			height: 100,
+			bounds: image.Rect(0, 0, 400, 200),
+			x:      50,
+			y:      50,
+			localX: -25,
+			localY: 25,
+			ok:     false,
+		},
+		{
+			name:   "outside",
+			width:  320,
+			height: 240,
+			bounds: image.Rect(0, 240, 320, 480),
+			x:      10,
+			y:      10,
+			localX: 10,
+			localY: -230,
+			ok:     false,
+		},
+		{
+			name:   "right edge",
+			width:  320,
+			height: 240,
+			bounds: image.Rect(0, 0, 320, 240),
+			x:      320,
+			y:      0,
+			localX: 320,
+			localY: 0,
+			ok:     false,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			v := viewport.New(fixedLayout(tc.width, tc.height))
+			v.SetBounds(tc.bounds)
+			x, y, ok := v.ToLocal(tc.x, tc.y)
+			if x != tc.localX || y != tc.localY || ok != tc.ok {
+				t.Errorf("ToLocal(%f, %f): got: (%f, %f, %t), want: (%f, %f, %t)", tc.x, tc.y, x, y, ok, tc.localX, tc.localY, tc.ok)
+			}
+		})
+	}
+}
+
+func TestToLocalEmptyBounds(t *testing.T) {
+	v := viewport.New(fixedLayout(320, 240))
+	if _, _, ok := v.ToLocal(0, 0); ok {
+		t.Errorf("ToLocal with empty bounds: got: true, want: false")
+	}
+}
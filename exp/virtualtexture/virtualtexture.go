@@ -0,0 +1,385 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package virtualtexture offers a huge virtual texture whose tiles are streamed on demand,
+// e.g., for the world map of an open-world game that doesn't fit into GPU memory.
+//
+// A Texture is divided into square tiles. Only the tiles needed for drawing are loaded by a user-provided loader on
+// background goroutines and kept resident as images. The least recently drawn tiles are evicted when the number of
+// the resident tiles exceeds the limit, and their images are reused for the tiles loaded later.
+//
+// The tiles are regular images and hardware sparse textures are not used.
+// As each tile is drawn separately, drawing with FilterLinear might show seams between the tiles.
+//
+// This package is experimental and the API might be changed in the future.
+package virtualtexture
+
+import (
+	"image"
+	"image/draw"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// LoadFunc loads the pixels of the tile at the tile coordinate (x, y).
+//
+// The returned image's bounds are treated as the tile's region from the upper-left corner of the tile.
+// If the returned image is nil, the tile is treated as transparent.
+// The returned image must not be modified after LoadFunc returns.
+//
+// LoadFunc is called on background goroutines.
+type LoadFunc func(x, y int) (image.Image, error)
+
+// Options represents options of a Texture.
+type Options struct {
+	// TileSize is the width and the height of a tile in pixels.
+	//
+	// If TileSize is 0, 256 is used.
+	TileSize int
+
+	// MaxResidentTiles is the maximum number of the tiles kept as images.
+	// The GPU memory usage is roughly 4 * TileSize * TileSize * MaxResidentTiles bytes.
+	//
+	// If MaxResidentTiles is 0, 256 is used.
+	MaxResidentTiles int
+
+	// MaxUploadsPerFrame is the maximum number of the loaded tiles uploaded to images in one Draw call.
+	//
+	// If MaxUploadsPerFrame is 0, 8 is used.
+	MaxUploadsPerFrame int
+
+	// Workers is the number of the goroutines calling the loader.
+	//
+	// If Workers is 0, 2 is used.
+	Workers int
+}
+
+type tile struct {
+	image    *ebiten.Image
+	lastUsed uint64
+}
+
+type loadedTile struct {
+	pos image.Point
+	pix []byte
+}
+
+// Texture represents a virtual texture streamed by tiles.
+type Texture struct {
+	width    int
+	height   int
+	tileSize int
+
+	maxResidentTiles   int
+	maxUploadsPerFrame int
+
+	load LoadFunc
+
+	// tiles is the resident tiles.
+	tiles map[image.Point]*tile
+
+	// freeImages is the images of evicted tiles to be reused.
+	freeImages []*ebiten.Image
+
+	tick uint64
+
+	// requested is the tiles being loaded.
+	requested map[image.Point]struct{}
+	requests  chan image.Point
+	loaded    []loadedTile
+	err       error
+	closed    bool
+	m         sync.Mutex
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New creates a virtual texture of the given size in pixels.
+//
+// New starts goroutines to load tiles. Call Close when the texture is no longer used.
+func New(width, height int, load LoadFunc, options *Options) *Texture {
+	if width <= 0 || height <= 0 {
+		panic("virtualtexture: width and height must be positive")
+	}
+	if load == nil {
+		panic("virtualtexture: load must not be nil")
+	}
+	if options == nil {
+		options = &Options{}
+	}
+
+	t := &Texture{
+		width:              width,
+		height:             height,
+		tileSize:           options.TileSize,
+		maxResidentTiles:   options.MaxResidentTiles,
+		maxUploadsPerFrame: options.MaxUploadsPerFrame,
+		load:               load,
+		tiles:              map[image.Point]*tile{},
+		requested:          map[image.Point]struct{}{},
+		done:               make(chan struct{}),
+	}
+	if t.tileSize <= 0 {
+		t.tileSize = 256
+	}
+	if t.maxResidentTiles <= 0 {
+		t.maxResidentTiles = 256
+	}
+	if t.maxUploadsPerFrame <= 0 {
+		t.maxUploadsPerFrame = 8
+	}
+	workers := options.Workers
+	if workers <= 0 {
+		workers = 2
+	}
+
+	t.requests = make(chan image.Point, t.maxResidentTiles)
+	for i := 0; i < workers; i++ {
+		t.wg.Add(1)
+		go func() {
+			defer t.wg.Done()
+			t.loop()
+		}()
+	}
+	return t
+}
+
+// Size returns the size of the virtual texture in pixels.
+func (t *Texture) Size() (width, height int) {
+	return t.width, t.height
+}
+
+// TileSize returns the size of a tile in pixels.
+func (t *Texture) TileSize() int {
+	return t.tileSize
+}
+
+// Err returns the first error returned by the loader.
+//
+// Err is concurrent-safe.
+func (t *Texture) Err() error {
+	t.m.Lock()
+	defer t.m.Unlock()
+	return t.err
+}
+
+// Close stops loading tiles and disposes the resident tiles.
+//
+// Close must be called from Update or Draw of ebiten.Game.
+func (t *Texture) Close() {
+	t.m.Lock()
+	if t.closed {
+		t.m.Unlock()
+		return
+	}
+	t.closed = true
+	t.m.Unlock()
+
+	close(t.done)
+	t.wg.Wait()
+
+	for _, tl := range t.tiles {
+		if tl.image != nil {
+			tl.image.Dispose()
+		}
+	}
+	for _, img := range t.freeImages {
+		img.Dispose()
+	}
+	t.tiles = nil
+	t.freeImages = nil
+}
+
+func (t *Texture) loop() {
+	for {
+		select {
+		case <-t.done:
+			return
+		case pos := <-t.requests:
+			img, err := t.load(pos.X, pos.Y)
+			var pix []byte
+			if err == nil && img != nil {
+				pix = t.tilePixels(img)
+			}
+
+			t.m.Lock()
+			if err != nil {
+				if t.err == nil {
+					t.err = err
+				}
+				delete(t.requested, pos)
+			} else {
+				t.loaded = append(t.loaded, loadedTile{pos: pos, pix: pix})
+			}
+			t.m.Unlock()
+		}
+	}
+}
+
+// tilePixels returns the RGBA pixels of the image in the tile size.
+func (t *Texture) tilePixels(img image.Image) []byte {
+	s := t.tileSize
+	if rgba, ok := img.(*image.RGBA); ok && rgba.Rect == image.Rect(0, 0, s, s) && rgba.Stride == 4*s {
+		return rgba.Pix
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, s, s))
+	b := img.Bounds()
+	draw.Draw(dst, image.Rect(0, 0, b.Dx(), b.Dy()), img, b.Min, draw.Src)
+	return dst.Pix
+}
+
+// Prefetch requests to load the tiles intersecting with the region r of the virtual texture without drawing them.
+//
+// Prefetch must be called from Update or Draw of ebiten.Game.
+func (t *Texture) Prefetch(r image.Rectangle) {
+	if t.closed {
+		return
+	}
+	t.forEachTile(r, func(pos image.Point) {
+		if _, ok := t.tiles[pos]; !ok {
+			t.request(pos)
+		}
+	})
+}
+
+// Draw draws the region src of the virtual texture to dst with the given options.
+//
+// Draw works like drawing src as a sub-image of a huge image with dst.DrawImage.
+// The tiles not loaded yet are requested to be loaded and are skipped.
+//
+// Draw must be called from Update or Draw of ebiten.Game.
+func (t *Texture) Draw(dst *ebiten.Image, src image.Rectangle, options *ebiten.DrawImageOptions) {
+	if t.closed {
+		return
+	}
+	if options == nil {
+		options = &ebiten.DrawImageOptions{}
+	}
+
+	t.upload()
+
+	t.tick++
+	t.forEachTile(src, func(pos image.Point) {
+		tl, ok := t.tiles[pos]
+		if !ok {
+			t.request(pos)
+			return
+		}
+		tl.lastUsed = t.tick
+		if tl.image == nil {
+			// The tile is transparent.
+			return
+		}
+
+		r := image.Rect(pos.X*t.tileSize, pos.Y*t.tileSize, (pos.X+1)*t.tileSize, (pos.Y+1)*t.tileSize)
+		in := r.Intersect(src)
+
+		op := *options
+		op.GeoM.Reset()
+		op.GeoM.Translate(float64(in.Min.X-src.Min.X), float64(in.Min.Y-src.Min.Y))
+		op.GeoM.Concat(options.GeoM)
+		dst.DrawImage(tl.image.SubImage(in.Sub(r.Min)).(*ebiten.Image), &op)
+	})
+}
+
+func (t *Texture) forEachTile(r image.Rectangle, f func(pos image.Point)) {
+	r = r.Intersect(image.Rect(0, 0, t.width, t.height))
+	if r.Empty() {
+		return
+	}
+	s := t.tileSize
+	for y := r.Min.Y / s; y <= (r.Max.Y-1)/s; y++ {
+		for x := r.Min.X / s; x <= (r.Max.X-1)/s; x++ {
+			f(image.Pt(x, y))
+		}
+	}
+}
+
+func (t *Texture) request(pos image.Point) {
+	t.m.Lock()
+	defer t.m.Unlock()
+	if _, ok := t.requested[pos]; ok {
+		return
+	}
+	select {
+	case t.requests <- pos:
+		t.requested[pos] = struct{}{}
+	default:
+		// The queue is full. The tile will be requested again at the next drawing.
+	}
+}
+
+// upload makes the loaded tiles resident.
+func (t *Texture) upload() {
+	t.m.Lock()
+	n := len(t.loaded)
+	if n > t.maxUploadsPerFrame {
+		n = t.maxUploadsPerFrame
+	}
+	loaded := make([]loadedTile, n)
+	copy(loaded, t.loaded)
+	t.loaded = t.loaded[:copy(t.loaded, t.loaded[n:])]
+	for _, l := range loaded {
+		delete(t.requested, l.pos)
+	}
+	t.m.Unlock()
+
+	for _, l := range loaded {
+		if _, ok := t.tiles[l.pos]; ok {
+			continue
+		}
+		t.evictIfNeeded()
+
+		tl := &tile{
+			lastUsed: t.tick,
+		}
+		if l.pix != nil {
+			tl.image = t.newTileImage()
+			tl.image.ReplacePixels(l.pix)
+		}
+		t.tiles[l.pos] = tl
+	}
+}
+
+func (t *Texture) newTileImage() *ebiten.Image {
+	if n := len(t.freeImages); n > 0 {
+		img := t.freeImages[n-1]
+		t.freeImages[n-1] = nil
+		t.freeImages = t.freeImages[:n-1]
+		return img
+	}
+	return ebiten.NewImage(t.tileSize, t.tileSize)
+}
+
+// evictIfNeeded evicts the least recently drawn tile if the number of the resident tiles reaches the limit.
+func (t *Texture) evictIfNeeded() {
+	if len(t.tiles) < t.maxResidentTiles {
+		return
+	}
+
+	var oldestPos image.Point
+	var oldest *tile
+	for pos, tl := range t.tiles {
+		if oldest == nil || tl.lastUsed < oldest.lastUsed {
+			oldestPos = pos
+			oldest = tl
+		}
+	}
+	delete(t.tiles, oldestPos)
+	if oldest.image != nil {
+		t.freeImages = append(t.freeImages, oldest.image)
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package virtualtexture
+
+import (
+	"image"
+	"image/color"
+	"sync"
+	"testing"
+	"time"
+)
+
+// The tests use transparent tiles, which have no images, so that no GPU is needed.
+
+func transparentLoader(x, y int) (image.Image, error) {
+	return nil, nil
+}
+
+// waitLoaded waits until n tiles are loaded and are not uploaded yet.
+func waitLoaded(t *testing.T, tex *Texture, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		tex.m.Lock()
+		l := len(tex.loaded)
+		tex.m.Unlock()
+		if l == n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("loaded tiles: got: %d, want: %d", l, n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestForEachTile(t *testing.T) {
+	tex := New(600, 300, transparentLoader, &Options{TileSize: 256})
+	defer tex.Close()
+
+	cases := []struct {
+		r    image.Rectangle
+		want []image.Point
+	}{
+		{
+			r:    image.Rect(0, 0, 256, 256),
+			want: []image.Point{{0, 0}},
+		},
+		{
+			r:    image.Rect(250, 250, 260, 260),
+			want: []image.Point{{0, 0}, {1, 0}, {0, 1}, {1, 1}},
+		},
+		// The region is clipped by the texture's bounds.
+		{
+			r:    image.Rect(-100, -100, 10, 10),
+			want: []image.Point{{0, 0}},
+		},
+		{
+			r:    image.Rect(590, 290, 1000, 1000),
+			want: []image.Point{{2, 1}},
+		},
+		{
+			r:    image.Rect(600, 0, 700, 100),
+			want: nil,
+		},
+	}
+	for _, c := range cases {
+		var got []image.Point
+		tex.forEachTile(c.r, func(pos image.Point) {
+			got = append(got, pos)
+		})
+		if len(got) != len(c.want) {
+			t.Errorf("forEachTile(%v): got: %v, want: %v", c.r, got, c.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != c.want[i] {
+				t.Errorf("forEachTile(%v): got: %v, want: %v", c.r, got, c.want)
+				break
+			}
+		}
+	}
+}
+
+func TestTilePixelsEdgeTile(t *testing.T) {
+	const tileSize = 16
+	tex := New(40, 40, transparentLoader, &Options{TileSize: tileSize})
+	defer tex.Close()
+
+	// An edge tile can be smaller than the tile size.
+	src := image.NewRGBA(image.Rect(8, 8, 16, 12))
+	for j := 8; j < 12; j++ {
+		for i := 8; i < 16; i++ {
+			src.Set(i, j, color.RGBA{0xff, 0, 0, 0xff})
+		}
+	}
+
+	pix := tex.tilePixels(src)
+	if got, want := len(pix), 4*tileSize*tileSize; got != want {
+		t.Fatalf("len(pix): got: %d, want: %d", got, want)
+	}
+	for j := 0; j < tileSize; j++ {
+		for i := 0; i < tileSize; i++ {
+			idx := 4 * (i + j*tileSize)
+			got := color.RGBA{pix[idx], pix[idx+1], pix[idx+2], pix[idx+3]}
+			var want color.RGBA
+			if i < 8 && j < 4 {
+				want = color.RGBA{0xff, 0, 0, 0xff}
+			}
+			if got != want {
+				t.Errorf("(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestRequestDeduplication(t *testing.T) {
+	var m sync.Mutex
+	calls := map[image.Point]int{}
+	load := func(x, y int) (image.Image, error) {
+		m.Lock()
+		defer m.Unlock()
+		calls[image.Pt(x, y)]++
+		return nil, nil
+	}
+
+	tex := New(64, 64, load, &Options{TileSize: 16, Workers: 4})
+	defer tex.Close()
+
+	r := image.Rect(0, 0, 32, 32)
+	for i := 0; i < 4; i++ {
+		tex.Prefetch(r)
+	}
+	waitLoaded(t, tex, 4)
+
+	// The tiles loaded but not uploaded yet are not requested again.
+	tex.Prefetch(r)
+	tex.upload()
+
+	// The resident tiles are not requested again.
+	tex.Prefetch(r)
+	tex.m.Lock()
+	if got := len(tex.requested); got != 0 {
+		t.Errorf("len(requested): got: %d, want: 0", got)
+	}
+	tex.m.Unlock()
+
+	m.Lock()
+	defer m.Unlock()
+	if got, want := len(calls), 4; got != want {
+		t.Errorf("len(calls): got: %d, want: %d", got, want)
+	}
+	for pos, n := range calls {
+		if n != 1 {
+			t.Errorf("calls[%v]: got: %d, want: 1", pos, n)
+		}
+	}
+}
+
+func TestMaxUploadsPerFrame(t *testing.T) {
+	tex := New(80, 16, transparentLoader, &Options{TileSize: 16, MaxUploadsPerFrame: 2})
+	defer tex.Close()
+
+	tex.Prefetch(image.Rect(0, 0, 80, 16))
+	waitLoaded(t, tex, 5)
+
+	for _, want := range []int{2, 4, 5, 5} {
+		// Draw uploads the loaded tiles. dst is not used as all the tiles are transparent.
+		tex.Draw(nil, image.Rectangle{}, nil)
+		if got := len(tex.tiles); got != want {
+			t.Errorf("len(tiles): got: %d, want: %d", got, want)
+		}
+	}
+}
+
+func TestEviction(t *testing.T) {
+	tex := New(64, 16, transparentLoader, &Options{TileSize: 16, MaxResidentTiles: 2})
+	defer tex.Close()
+
+	tex.Prefetch(image.Rect(0, 0, 32, 16))
+	waitLoaded(t, tex, 2)
+	tex.Draw(nil, image.Rectangle{}, nil)
+
+	// Draw the tile (0, 0) so that the tile (1, 0) becomes the least recently drawn tile.
+	tex.Draw(nil, image.Rect(0, 0, 16, 16), nil)
+
+	tex.Prefetch(image.Rect(32, 0, 48, 16))
+	waitLoaded(t, tex, 1)
+	tex.Draw(nil, image.Rectangle{}, nil)
+
+	if got, want := len(tex.tiles), 2; got != want {
+		t.Fatalf("len(tiles): got: %d, want: %d", got, want)
+	}
+	for _, pos := range []image.Point{{0, 0}, {2, 0}} {
+		if _, ok := tex.tiles[pos]; !ok {
+			t.Errorf("tile %v must be resident", pos)
+		}
+	}
+	if _, ok := tex.tiles[image.Pt(1, 0)]; ok {
+		t.Errorf("tile (1, 0) must be evicted")
+	}
+}
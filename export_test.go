@@ -21,3 +21,13 @@ var (
 func PanicOnErrorAtImageAt() {
 	panicOnErrorAtImageAt = true
 }
+
+// NewGameForUIForTesting wraps game in the same way as RunGame.
+func NewGameForUIForTesting(game Game) interface {
+	RefreshRateChanged(hz float64)
+	KeyboardLayoutChanged()
+} {
+	return &gameForUI{
+		game: &imageDumperGame{game: game},
+	}
+}
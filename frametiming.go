@@ -0,0 +1,155 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/frametiming"
+)
+
+// FrameTiming represents the time spent in each phase of a frame.
+//
+// The values are measured on CPU. The actual GPU execution time is not measured.
+type FrameTiming struct {
+	// Update is the time spent in the game's Update calls in the frame, excluding GPU.
+	Update time.Duration
+
+	// Draw is the time spent in the game's Draw and composing the screen, excluding GPU.
+	Draw time.Duration
+
+	// GPU is the time spent in sending the graphics commands to the graphics driver.
+	//
+	// GPU includes the time spent in Update or Draw to flush the commands, e.g. when At is called in Draw.
+	// Such time is not counted in Update or Draw, so the phases don't overlap.
+	// On Metal, GPU includes waiting for the next drawable, which blocks with vsync.
+	GPU time.Duration
+
+	// PresentWait is the time spent in waiting for presenting the screen, e.g. blocking for vsync.
+	//
+	// PresentWait is zero on the environments where presenting is out of Ebiten's control, like browsers and mobiles.
+	PresentWait time.Duration
+
+	// Interval is the time from the beginning of the frame to the beginning of the next frame,
+	// including the time to present the frame. The beginning of a frame is when Ebiten starts processing it.
+	// Interval is the reciprocal of the instant FPS. Interval is zero for the first frame.
+	Interval time.Duration
+
+	// UpdateCount is the number of the game's Update calls in the frame.
+	UpdateCount int
+
+	// Events is the engine events happened in the frame.
+	Events FrameEvents
+}
+
+// FrameEvents is a bit set of engine events that can take time in a frame.
+type FrameEvents int
+
+const (
+	// FrameEventAtlasGrowth indicates that an internal texture atlas is created or extended.
+	FrameEventAtlasGrowth FrameEvents = FrameEvents(frametiming.EventAtlasGrowth)
+
+	// FrameEventShaderCompilation indicates that a shader program is compiled by the graphics driver.
+	FrameEventShaderCompilation FrameEvents = FrameEvents(frametiming.EventShaderCompilation)
+)
+
+// FrameTimingHistorySize is the maximum number of the frames that AppendFrameTimings appends.
+const FrameTimingHistorySize = frametiming.HistorySize
+
+// AppendFrameTimings appends the timings of the recent frames to dst from the oldest, and returns the extended slice.
+//
+// At most FrameTimingHistorySize frames are kept. The frame being processed is not included.
+//
+// Reusing the slice like AppendFrameTimings(timings[:0]) avoids allocations.
+//
+// AppendFrameTimings is concurrent-safe.
+func AppendFrameTimings(dst []FrameTiming) []FrameTiming {
+	var buf [frametiming.HistorySize]frametiming.Frame
+	for _, f := range frametiming.AppendFrames(buf[:0]) {
+		dst = append(dst, toFrameTiming(f))
+	}
+	return dst
+}
+
+func toFrameTiming(f frametiming.Frame) FrameTiming {
+	return FrameTiming{
+		Update:      f.Timing[frametiming.PhaseUpdate],
+		Draw:        f.Timing[frametiming.PhaseDraw],
+		GPU:         f.Timing[frametiming.PhaseGPU],
+		PresentWait: f.Timing[frametiming.PhasePresentWait],
+		Interval:    f.Interval,
+		UpdateCount: f.UpdateCount,
+		Events:      FrameEvents(f.Events),
+	}
+}
+
+// FrameStats represents statistics of the intervals of the recent frames.
+//
+// While CurrentFPS reports the average, the percentiles of the intervals tell how stable the frames are.
+// For example, a large P99 with a normal P50 means occasional stutters.
+type FrameStats struct {
+	// Count is the number of the frames used for the statistics.
+	Count int
+
+	// Mean is the mean of the intervals.
+	Mean time.Duration
+
+	// P50, P90, and P99 are the 50th, 90th, and 99th percentiles of the intervals.
+	P50 time.Duration
+	P90 time.Duration
+	P99 time.Duration
+
+	// Max is the longest interval.
+	Max time.Duration
+}
+
+// RecentFrameStats returns the statistics of the intervals of the recent frames.
+//
+// At most FrameTimingHistorySize frames are used. The frame being processed is not included.
+// If there is no frame yet, RecentFrameStats returns the zero value.
+//
+// RecentFrameStats is concurrent-safe.
+func RecentFrameStats() FrameStats {
+	var buf [frametiming.HistorySize]frametiming.Frame
+	s := frametiming.CalcStats(frametiming.AppendFrames(buf[:0]))
+	return FrameStats{
+		Count: s.Count,
+		Mean:  s.Mean,
+		P50:   s.P50,
+		P90:   s.P90,
+		P99:   s.P99,
+		Max:   s.Max,
+	}
+}
+
+// SetFrameSpikeHandler sets the function called when a frame's interval exceeds threshold.
+//
+// f is called with the timing of the slow frame at the beginning of the next frame,
+// on the same goroutine as Update and before Update is called.
+// Images can be used in f as in Update.
+// The timing's Events tells engine events that might cause the spike, which is useful to log stutters in the field.
+//
+// If f is nil or threshold is not positive, the handler is removed.
+//
+// SetFrameSpikeHandler is concurrent-safe.
+func SetFrameSpikeHandler(threshold time.Duration, f func(timing FrameTiming)) {
+	if f == nil || threshold <= 0 {
+		frametiming.SetSpikeHandler(0, nil)
+		return
+	}
+	frametiming.SetSpikeHandler(threshold, func(spike frametiming.Spike) {
+		f(toFrameTiming(spike.Frame))
+	})
+}
@@ -16,8 +16,10 @@ package ebiten
 
 import (
 	"fmt"
+	"image"
 	"math"
 
+	"github.com/hajimehoshi/ebiten/v2/internal/clock"
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
 )
 
@@ -25,16 +27,35 @@ type gameForUI struct {
 	game      Game
 	offscreen *Image
 	screen    *Image
+
+	// lastScreen is a copy of the offscreen after the last Draw.
+	// lastScreen is copied only when LastScreen is called.
+	lastScreen *Image
+
+	// lastScreenUpToDate reports whether lastScreen holds the result of the last Draw.
+	lastScreenUpToDate bool
+
+	// lastScreenUsed reports whether LastScreen is called after the last Draw.
+	lastScreenUsed bool
+
+	// lastScreenUsedInLastFrame reports whether LastScreen was called in the last frame.
+	lastScreenUsedInLastFrame bool
+
+	inDraw bool
 }
 
 func newGameForUI(game Game) *gameForUI {
-	return &gameForUI{
+	g := &gameForUI{
 		game: game,
 	}
+	return g
 }
 
-func (c *gameForUI) Layout(outsideWidth, outsideHeight float64, deviceScaleFactor float64) (int, int) {
-	ow, oh := c.game.Layout(int(outsideWidth), int(outsideHeight))
+func (c *gameForUI) Layout(outsideWidth, outsideHeight float64, deviceScaleFactor float64) (int, int, error) {
+	ow, oh, err := layoutGame(c.game, int(outsideWidth), int(outsideHeight))
+	if err != nil {
+		return 0, 0, err
+	}
 	if ow <= 0 || oh <= 0 {
 		panic("ebiten: Layout must return positive numbers")
 	}
@@ -66,23 +87,99 @@ func (c *gameForUI) Layout(outsideWidth, outsideHeight float64, deviceScaleFacto
 		c.offscreen.mipmap.SetIndependent(true)
 	}
 
-	return ow, oh
+	if c.lastScreen != nil {
+		if w, h := c.lastScreen.Size(); w != ow || h != oh {
+			c.lastScreen.Dispose()
+			c.lastScreen = nil
+			c.lastScreenUpToDate = false
+		}
+	}
+
+	return ow, oh, nil
+}
+
+func (c *gameForUI) lastScreenImage() *Image {
+	if c.offscreen == nil {
+		return nil
+	}
+	c.lastScreenUsed = true
+	// While drawing, the offscreen no longer holds the result of the last Draw.
+	if !c.inDraw {
+		c.updateLastScreen()
+	}
+	if c.lastScreen == nil {
+		c.lastScreen = NewImage(c.offscreen.Size())
+	}
+	return c.lastScreen
+}
+
+// updateLastScreen copies the offscreen to lastScreen if needed.
+// updateLastScreen must be called when the offscreen holds the result of the last Draw.
+func (c *gameForUI) updateLastScreen() {
+	if c.lastScreen == nil {
+		c.lastScreen = NewImage(c.offscreen.Size())
+		c.lastScreenUpToDate = false
+	}
+	if c.lastScreenUpToDate {
+		return
+	}
+	c.lastScreen.CopyFrom(c.offscreen, image.Point{}, c.offscreen.Bounds())
+	c.lastScreenUpToDate = true
 }
 
 func (c *gameForUI) Update() error {
+	defer clock.AdvanceTick()
 	return c.game.Update()
 }
 
+func (c *gameForUI) Suspend() {
+	if s, ok := c.game.(Suspender); ok {
+		s.OnSuspend()
+	}
+}
+
+func (c *gameForUI) Resume() {
+	if r, ok := c.game.(Resumer); ok {
+		r.OnResume()
+	}
+}
+
+func (c *gameForUI) RefreshRateChanged(hz float64) {
+	if o, ok := c.game.(RefreshRateObserver); ok {
+		o.OnRefreshRateChange(hz)
+	}
+}
+
+func (c *gameForUI) KeyboardLayoutChanged() {
+	if o, ok := c.game.(KeyboardLayoutObserver); ok {
+		o.OnKeyboardLayoutChange()
+	}
+}
+
 func (c *gameForUI) Draw(screenScale float64, offsetX, offsetY float64, needsClearingScreen bool, framebufferYDirection graphicsdriver.YDirection, clearScreenEveryFrame, filterEnabled bool) error {
 	c.offscreen.mipmap.SetVolatile(clearScreenEveryFrame)
 
+	// If LastScreen was used in the last frame, it is likely to be used in Draw again.
+	// Copy the offscreen before it is cleared or drawn.
+	if c.lastScreenUsedInLastFrame {
+		c.updateLastScreen()
+	}
+
 	// Even though updateCount == 0, the offscreen is cleared and Draw is called.
 	// Draw should not update the game state and then the screen should not be updated without Update, but
 	// users might want to process something at Draw with the time intervals of FPS.
 	if clearScreenEveryFrame {
 		c.offscreen.Clear()
 	}
-	c.game.Draw(c.offscreen)
+	c.inDraw = true
+	err := drawGame(c.game, c.offscreen)
+	c.inDraw = false
+	c.lastScreenUpToDate = false
+	c.lastScreenUsedInLastFrame = c.lastScreenUsed
+	c.lastScreenUsed = false
+	if err != nil {
+		return err
+	}
 
 	if needsClearingScreen {
 		// This clear is needed for fullscreen mode or some mobile platforms (#622).
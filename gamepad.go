@@ -87,6 +87,17 @@ const (
 	StandardGamepadButtonMax              StandardGamepadButton = StandardGamepadButtonCenterCenter
 )
 
+// GamepadFamilyType represents a family of gamepads that share the same button labels.
+type GamepadFamilyType = gamepad.Family
+
+// GamepadFamilyTypes
+const (
+	GamepadFamilyGeneric     GamepadFamilyType = gamepad.FamilyGeneric
+	GamepadFamilyXbox        GamepadFamilyType = gamepad.FamilyXbox
+	GamepadFamilyPlayStation GamepadFamilyType = gamepad.FamilyPlayStation
+	GamepadFamilyNintendo    GamepadFamilyType = gamepad.FamilyNintendo
+)
+
 // StandardGamepadAxis represents a gamepad axis in the standard layout.
 //
 // The layout and the button values are based on the web standard.
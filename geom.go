@@ -169,6 +169,21 @@ func (g *GeoM) det2x2() float64 {
 	return (g.a_1+1)*(g.d_1+1) - g.b*g.c
 }
 
+// integerTranslation returns the translation if g is only a translation by integers.
+func (g *GeoM) integerTranslation() (x, y int, ok bool) {
+	if g.a_1 != 0 || g.b != 0 || g.c != 0 || g.d_1 != 0 {
+		return 0, 0, false
+	}
+	if g.tx != math.Trunc(g.tx) || g.ty != math.Trunc(g.ty) {
+		return 0, 0, false
+	}
+	// Avoid overflows in the conversion.
+	if math.Abs(g.tx) > math.MaxInt32 || math.Abs(g.ty) > math.MaxInt32 {
+		return 0, 0, false
+	}
+	return int(g.tx), int(g.ty), true
+}
+
 // IsInvertible returns a boolean value indicating
 // whether the matrix g is invertible or not.
 func (g *GeoM) IsInvertible() bool {
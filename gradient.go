@@ -0,0 +1,278 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"fmt"
+	"image/color"
+	"sort"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/graphics"
+)
+
+// MaxGradientStops is the maximum number of stops in a Gradient.
+const MaxGradientStops = 8
+
+// GradientType represents the shape of a gradient.
+type GradientType int
+
+const (
+	// GradientTypeLinear represents a gradient along the line from (X0, Y0) to (X1, Y1).
+	GradientTypeLinear GradientType = iota
+
+	// GradientTypeRadial represents a gradient from the center (X0, Y0) to the circle with the radius Radius.
+	GradientTypeRadial
+)
+
+// GradientStop represents a color at a position of a gradient.
+type GradientStop struct {
+	// Offset is the position of the stop in [0, 1].
+	Offset float32
+
+	// Color is the color at the stop.
+	Color color.Color
+}
+
+// Gradient represents a linear or radial color gradient.
+//
+// The coordinates are in the destination image's bounds.
+// Colors before the first stop and after the last stop are the colors of the first and the last stops.
+type Gradient struct {
+	// Type is the shape of the gradient.
+	// The default (zero) value is GradientTypeLinear.
+	Type GradientType
+
+	// X0 and Y0 are the start point of a linear gradient, or the center of a radial gradient.
+	X0, Y0 float32
+
+	// X1 and Y1 are the end point of a linear gradient.
+	// X1 and Y1 are ignored for a radial gradient.
+	X1, Y1 float32
+
+	// Radius is the radius of a radial gradient.
+	// Radius is ignored for a linear gradient.
+	Radius float32
+
+	// Stops is the color stops of the gradient.
+	// The stops don't have to be sorted by their offsets.
+	//
+	// The number of the stops must be between 1 and MaxGradientStops.
+	Stops []GradientStop
+}
+
+const gradientShaderSrc = `package main
+
+var Radial float
+var P0 vec2
+var P1 vec2
+var Radius float
+var StopCount float
+var StopOffsets [8]float
+var StopColors [8]vec4
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	// The local position is passed as the vertex color since position might include an offset on an atlas.
+	pos := color.xy
+
+	t := 0.0
+	if Radial > 0 {
+		if Radius > 0 {
+			t = distance(pos, P0) / Radius
+		}
+	} else {
+		d := P1 - P0
+		if l := dot(d, d); l > 0 {
+			t = dot(pos-P0, d) / l
+		}
+	}
+
+	clr := StopColors[0]
+	n := 1.0
+	for i := 0; i < 7; i++ {
+		if n < StopCount && t >= StopOffsets[i] {
+			o0 := StopOffsets[i]
+			o1 := StopOffsets[i+1]
+			if o1 > o0 {
+				clr = mix(StopColors[i], StopColors[i+1], clamp((t-o0)/(o1-o0), 0, 1))
+			} else {
+				clr = StopColors[i+1]
+			}
+		}
+		n += 1
+	}
+	return clr
+}
+`
+
+var (
+	gradientShader     *Shader
+	gradientShaderOnce sync.Once
+)
+
+func ensureGradientShader() *Shader {
+	gradientShaderOnce.Do(func() {
+		s, err := NewShader([]byte(gradientShaderSrc))
+		if err != nil {
+			panic(fmt.Sprintf("ebiten: compiling the gradient shader failed: %v", err))
+		}
+		gradientShader = s
+	})
+	return gradientShader
+}
+
+func (g *Gradient) uniforms() map[string]interface{} {
+	if len(g.Stops) == 0 {
+		panic("ebiten: len(g.Stops) must be more than 0")
+	}
+	if len(g.Stops) > MaxGradientStops {
+		panic(fmt.Sprintf("ebiten: len(g.Stops) must be <= %d but %d", MaxGradientStops, len(g.Stops)))
+	}
+
+	stops := make([]GradientStop, len(g.Stops))
+	copy(stops, g.Stops)
+	sort.SliceStable(stops, func(i, j int) bool {
+		return stops[i].Offset < stops[j].Offset
+	})
+
+	offsets := make([]float32, MaxGradientStops)
+	colors := make([]float32, 4*MaxGradientStops)
+	for i, s := range stops {
+		offsets[i] = s.Offset
+		// Interpolate the colors in premultiplied alpha so that a transparent stop doesn't darken its neighbors.
+		r, g, b, a := s.Color.RGBA()
+		colors[4*i] = float32(r) / 0xffff
+		colors[4*i+1] = float32(g) / 0xffff
+		colors[4*i+2] = float32(b) / 0xffff
+		colors[4*i+3] = float32(a) / 0xffff
+	}
+
+	var radial float32
+	if g.Type == GradientTypeRadial {
+		radial = 1
+	}
+	return map[string]interface{}{
+		"Radial":      radial,
+		"P0":          []float32{g.X0, g.Y0},
+		"P1":          []float32{g.X1, g.Y1},
+		"Radius":      g.Radius,
+		"StopCount":   float32(len(stops)),
+		"StopOffsets": offsets,
+		"StopColors":  colors,
+	}
+}
+
+// DrawTrianglesGradientOptions represents options for DrawTrianglesGradient.
+type DrawTrianglesGradientOptions struct {
+	// CompositeMode is a composite mode to draw.
+	// The default (zero) value is regular alpha blending.
+	CompositeMode CompositeMode
+
+	// FillRule indicates the rule how an overlapped region is rendered.
+	//
+	// The default (zero) value is FillAll.
+	FillRule FillRule
+}
+
+// DrawTrianglesGradient draws triangles with the specified vertices and their indices filled with the gradient g.
+//
+// The source positions and the colors of the vertices are ignored.
+// The gradient is evaluated at the destination positions of the vertices on GPU,
+// so no gradient texture is allocated.
+//
+// If len(indices) is not multiple of 3, DrawTrianglesGradient panics.
+//
+// If len(indices) is more than MaxIndicesNum, DrawTrianglesGradient panics.
+//
+// If the number of g's stops is 0 or more than MaxGradientStops, DrawTrianglesGradient panics.
+//
+// DrawTrianglesGradient doesn't retain vertices, indices and g.
+//
+// When the image i is disposed, DrawTrianglesGradient does nothing.
+func (i *Image) DrawTrianglesGradient(vertices []Vertex, indices []uint16, g *Gradient, options *DrawTrianglesGradientOptions) {
+	i.copyCheck()
+
+	if i.isDisposed() {
+		return
+	}
+
+	if len(indices)%3 != 0 {
+		panic("ebiten: len(indices) % 3 must be 0")
+	}
+	if len(indices) > MaxIndicesNum {
+		panic("ebiten: len(indices) must be <= MaxIndicesNum")
+	}
+
+	vs := graphics.Vertices(len(vertices))
+	for i, v := range vertices {
+		setGradientVertex(vs, i, v.DstX, v.DstY)
+	}
+	is := graphics.Indices(len(indices))
+	copy(is, indices)
+
+	i.drawTrianglesGradient(vs, is, g, options)
+}
+
+// setGradientVertex sets the idx-th vertex in vs at the destination position (x, y).
+func setGradientVertex(vs []float32, idx int, x, y float32) {
+	v := vs[idx*graphics.VertexFloatNum : (idx+1)*graphics.VertexFloatNum]
+	v[0] = x
+	v[1] = y
+	v[2] = 0
+	v[3] = 0
+	// The local position is passed as the vertex color. See gradientShaderSrc.
+	v[4] = x
+	v[5] = y
+	v[6] = 0
+	v[7] = 0
+	v[8] = 0
+}
+
+func (i *Image) drawTrianglesGradient(vs []float32, is []uint16, g *Gradient, options *DrawTrianglesGradientOptions) {
+	if options == nil {
+		options = &DrawTrianglesGradientOptions{}
+	}
+	op := &DrawTrianglesShaderOptions{
+		CompositeMode: options.CompositeMode,
+		Uniforms:      g.uniforms(),
+		FillRule:      options.FillRule,
+	}
+	i.drawTrianglesShader(vs, is, ensureGradientShader(), op)
+}
+
+// FillGradient fills the image with the gradient g.
+//
+// If the number of g's stops is 0 or more than MaxGradientStops, FillGradient panics.
+//
+// When the image is disposed, FillGradient does nothing.
+func (i *Image) FillGradient(g *Gradient) {
+	i.copyCheck()
+
+	if i.isDisposed() {
+		return
+	}
+
+	b := i.Bounds()
+	x0, y0 := float32(b.Min.X), float32(b.Min.Y)
+	x1, y1 := float32(b.Max.X), float32(b.Max.Y)
+	vs := graphics.Vertices(4)
+	setGradientVertex(vs, 0, x0, y0)
+	setGradientVertex(vs, 1, x1, y0)
+	setGradientVertex(vs, 2, x0, y1)
+	setGradientVertex(vs, 3, x1, y1)
+	i.drawTrianglesGradient(vs, graphics.QuadIndices(), g, &DrawTrianglesGradientOptions{
+		CompositeMode: CompositeModeCopy,
+	})
+}
@@ -15,6 +15,9 @@
 package ebiten
 
 import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicscommand"
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
 )
 
@@ -87,3 +90,58 @@ const (
 	// c_out = c_src * c_dst
 	CompositeModeMultiply CompositeMode = CompositeMode(graphicsdriver.CompositeModeMultiply)
 )
+
+// GraphicsLibrary represents a graphics library that Ebiten uses.
+type GraphicsLibrary int
+
+const (
+	// GraphicsLibraryUnknown represents that the graphics library is not determined yet.
+	GraphicsLibraryUnknown GraphicsLibrary = GraphicsLibrary(graphicsdriver.GraphicsLibraryUnknown)
+
+	// GraphicsLibraryOpenGL represents OpenGL on desktops.
+	GraphicsLibraryOpenGL GraphicsLibrary = GraphicsLibrary(graphicsdriver.GraphicsLibraryOpenGL)
+
+	// GraphicsLibraryOpenGLES represents OpenGL ES on mobiles.
+	GraphicsLibraryOpenGLES GraphicsLibrary = GraphicsLibrary(graphicsdriver.GraphicsLibraryOpenGLES)
+
+	// GraphicsLibraryWebGL1 represents WebGL 1 on browsers.
+	GraphicsLibraryWebGL1 GraphicsLibrary = GraphicsLibrary(graphicsdriver.GraphicsLibraryWebGL1)
+
+	// GraphicsLibraryWebGL2 represents WebGL 2 on browsers.
+	GraphicsLibraryWebGL2 GraphicsLibrary = GraphicsLibrary(graphicsdriver.GraphicsLibraryWebGL2)
+
+	// GraphicsLibraryMetal represents Metal on macOS and iOS.
+	GraphicsLibraryMetal GraphicsLibrary = GraphicsLibrary(graphicsdriver.GraphicsLibraryMetal)
+)
+
+// String returns a string representing the graphics library.
+func (g GraphicsLibrary) String() string {
+	switch g {
+	case GraphicsLibraryUnknown:
+		return "Unknown"
+	case GraphicsLibraryOpenGL:
+		return "OpenGL"
+	case GraphicsLibraryOpenGLES:
+		return "OpenGL ES"
+	case GraphicsLibraryWebGL1:
+		return "WebGL 1"
+	case GraphicsLibraryWebGL2:
+		return "WebGL 2"
+	case GraphicsLibraryMetal:
+		return "Metal"
+	}
+	return fmt.Sprintf("GraphicsLibrary(%d)", g)
+}
+
+// CurrentGraphicsLibrary returns the graphics library that Ebiten uses.
+//
+// On browsers, Ebiten prefers WebGL 2 and falls back to WebGL 1 when WebGL 2 is not available.
+// Some shader features might be limited on WebGL 1.
+//
+// CurrentGraphicsLibrary might return GraphicsLibraryUnknown until the graphics library is initialized, i.e.,
+// before the game's first Update is called.
+//
+// CurrentGraphicsLibrary is concurrent-safe.
+func CurrentGraphicsLibrary() GraphicsLibrary {
+	return GraphicsLibrary(graphicscommand.GraphicsLibrary())
+}
@@ -18,6 +18,8 @@ import (
 	"fmt"
 	"image"
 	"image/color"
+	"io"
+	"sync"
 
 	"github.com/hajimehoshi/ebiten/v2/internal/affine"
 	"github.com/hajimehoshi/ebiten/v2/internal/graphics"
@@ -40,9 +42,15 @@ type Image struct {
 
 	mipmap *mipmap.Mipmap
 
-	bounds   image.Rectangle
-	original *Image
-	screen   bool
+	bounds     image.Rectangle
+	original   *Image
+	screen     bool
+	compressed bool
+	native     bool
+
+	// prevDst is a copy of the image's pixels for imagePrevDstAt in shaders.
+	// prevDst is held only by an original image, and is reused as long as the size is the same.
+	prevDst *Image
 }
 
 func (i *Image) copyCheck() {
@@ -72,6 +80,35 @@ func (i *Image) Clear() {
 	i.Fill(color.Transparent)
 }
 
+// ClearDepth resets the depth buffer of the image to the farthest value.
+//
+// A depth buffer is allocated for the image at the first draw call with DepthTest.
+// A fragment drawn with DepthTest is rendered only when its depth is smaller than or equal to the depth buffer's value,
+// and then the depth buffer is updated with the fragment's depth.
+// This allows drawing sprites in any order without sorting them by their depths.
+// With the default shaders, fully transparent pixels neither are rendered nor update the depth buffer.
+// With a custom shader, all the pixels in the triangles update the depth buffer, as Kage cannot discard pixels.
+//
+// The content of the depth buffer is not preserved when the image is moved internally.
+// The depth buffer might not be restored with the image's content unless ClearDepth is called before drawing with DepthTest.
+// Call ClearDepth before drawing with DepthTest every frame.
+//
+// The depth buffer belongs to the whole image, so ClearDepth on a sub-image clears the depth buffer of the original image.
+// The depth buffer is only for depth testing, and shaders cannot read it.
+//
+// When the image is disposed, ClearDepth does nothing.
+func (i *Image) ClearDepth() {
+	i.copyCheck()
+
+	if i.isDisposed() {
+		return
+	}
+	if i.compressed || i.native {
+		return
+	}
+	i.mipmap.ClearDepth()
+}
+
 var (
 	emptyImage    = NewImage(3, 3)
 	emptySubImage = emptyImage.SubImage(image.Rect(1, 1, 2, 2)).(*Image)
@@ -83,7 +120,7 @@ func init() {
 	for i := range pix {
 		pix[i] = 0xff
 	}
-	// As emptyImage is used at Fill, use ReplacePixels instead.
+	// As emptyImage is used at FillRect, use ReplacePixels instead.
 	emptyImage.ReplacePixels(pix)
 }
 
@@ -91,20 +128,98 @@ func init() {
 //
 // When the image is disposed, Fill does nothing.
 func (i *Image) Fill(clr color.Color) {
-	// Use the original size to cover the entire region (#1691).
-	// DrawImage automatically clips the rendering region.
-	orig := i
-	if i.isSubImage() {
-		orig = i.original
+	i.FillRect(i.Bounds(), clr)
+}
+
+// FillRect fills the region rect of the image with a solid color.
+//
+// rect is in the coordinates of the image's bounds. The region outside the bounds is ignored.
+//
+// Unlike Fill on a sub-image, FillRect clears the region on GPU without a draw call where the graphics driver
+// supports it, so filling many regions is cheap.
+//
+// When the image is disposed, FillRect does nothing.
+func (i *Image) FillRect(rect image.Rectangle, clr color.Color) {
+	i.copyCheck()
+
+	if i.isDisposed() {
+		return
 	}
-	w, h := orig.Size()
 
-	op := &DrawImageOptions{}
-	op.GeoM.Scale(float64(w), float64(h))
-	op.ColorM.ScaleWithColor(clr)
-	op.CompositeMode = CompositeModeCopy
+	r := rect.Intersect(i.Bounds())
+	if r.Empty() {
+		return
+	}
+
+	// The screen has no texture to clear. Draw a quad instead.
+	if i.isScreen() || i.compressed || i.native {
+		op := &DrawImageOptions{}
+		op.GeoM.Scale(float64(r.Dx()), float64(r.Dy()))
+		op.GeoM.Translate(float64(r.Min.X), float64(r.Min.Y))
+		op.ColorScale.ScaleWithColor(clr)
+		op.CompositeMode = CompositeModeCopy
+		i.DrawImage(emptySubImage, op)
+		return
+	}
 
-	i.DrawImage(emptySubImage, op)
+	cr, cg, cb, ca := clr.RGBA()
+	i.mipmap.FillRect(r.Min.X, r.Min.Y, r.Dx(), r.Dy(), float32(cr)/0xffff, float32(cg)/0xffff, float32(cb)/0xffff, float32(ca)/0xffff)
+}
+
+// PrewarmOptions represents options for Prewarm.
+type PrewarmOptions struct {
+	// Shaders is a set of the shaders to warm up for the image.
+	// The rendering pipelines for the shaders are created for all the composite modes.
+	Shaders []*Shader
+}
+
+// Prewarm allocates the internal texture for the image and warms up the rendering pipelines
+// so that the first drawing to the image doesn't cause a hitch.
+// This is useful to move such costs to a loading screen.
+//
+// Prewarm doesn't change the pixels of the image.
+//
+// If Prewarm is called before the game starts, the actual allocation is delayed until the game starts.
+//
+// When the image is disposed, Prewarm does nothing.
+// When the image is created from a compressed texture or a native texture, Prewarm does nothing.
+func (i *Image) Prewarm(options *PrewarmOptions) {
+	i.copyCheck()
+
+	if i.isDisposed() {
+		return
+	}
+	if i.compressed || i.native {
+		return
+	}
+
+	if options == nil {
+		options = &PrewarmOptions{}
+	}
+
+	// A degenerate triangle renders no fragments but goes through the whole rendering pipeline.
+	vs := make([]Vertex, 3)
+	b := i.Bounds()
+	for j := range vs {
+		vs[j].DstX = float32(b.Min.X)
+		vs[j].DstY = float32(b.Min.Y)
+		vs[j].SrcX = 1
+		vs[j].SrcY = 1
+	}
+	is := []uint16{0, 1, 2}
+
+	// Built-in rendering pipelines are created at initialization. A drawing with one composite mode is
+	// enough to allocate the texture.
+	i.DrawTriangles(vs, is, emptySubImage, nil)
+
+	for _, s := range options.Shaders {
+		for c := CompositeModeSourceOver; c <= CompositeModeMultiply; c++ {
+			op := &DrawTrianglesShaderOptions{
+				CompositeMode: c,
+			}
+			i.DrawTrianglesShader(vs, is, s, op)
+		}
+	}
 }
 
 func canSkipMipmap(geom GeoM, filter graphicsdriver.Filter) bool {
@@ -124,6 +239,13 @@ type DrawImageOptions struct {
 	// The default (zero) value is identity, which doesn't change any color.
 	ColorM ColorM
 
+	// ColorScale is a scale of the RGBA components applied after ColorM.
+	// The default (zero) value is identity, which doesn't change any color.
+	//
+	// ColorScale is applied as vertex colors, so different ColorScale values never break batching draw calls.
+	// Prefer ColorScale to ColorM for simple tinting or fading.
+	ColorScale ColorScale
+
 	// CompositeMode is a composite mode to draw.
 	// The default (zero) value is regular alpha blending.
 	CompositeMode CompositeMode
@@ -131,6 +253,42 @@ type DrawImageOptions struct {
 	// Filter is a type of texture filter.
 	// The default (zero) value is FilterNearest.
 	Filter Filter
+
+	// PixelSnap indicates whether the corners of the drawn image are rounded to the nearest pixels of
+	// the destination image after GeoM is applied.
+	// Pixel snapping prevents sprites from shimmering when a pixel-art game moves its camera smoothly.
+	//
+	// When the destination is the screen, the pixels are the pixels of the screen image, not of the display.
+	//
+	// The default (zero) value is false. If SetPixelSnapping is enabled, the corners are rounded regardless of
+	// PixelSnap.
+	PixelSnap bool
+
+	// Z is the depth of the drawn image in [0, 1]. A smaller value is nearer.
+	// Z is used only when DepthTest is true.
+	//
+	// The default (zero) value is 0.
+	Z float32
+
+	// DepthTest indicates whether the depth test is enabled.
+	// See the document of (*Image).ClearDepth for details.
+	//
+	// The default (zero) value is false.
+	DepthTest bool
+
+	// ColorKey is a color treated as transparent in the source image.
+	// The source pixels whose RGB values equal ColorKey's RGB values in 8 bits are not rendered.
+	// ColorKey's alpha value is ignored.
+	//
+	// ColorKey is useful to draw legacy assets using a specific color like magenta as transparent
+	// without converting them beforehand.
+	// The color key is tested before the filter is applied, so the key color doesn't bleed with FilterLinear.
+	//
+	// A draw with ColorKey uses a special shader and is not batched with draws without ColorKey.
+	// ColorKey cannot be used with DepthTest.
+	//
+	// The default (zero) value is nil, which means no color key.
+	ColorKey color.Color
 }
 
 // DrawImage draws the given image on the image i.
@@ -147,6 +305,9 @@ type DrawImageOptions struct {
 //
 // When the given image is as same as i, DrawImage panics.
 //
+// When the image i is created from a compressed texture or a native texture, DrawImage panics.
+// See NewImageFromCompressedTexture.
+//
 // DrawImage works more efficiently as batches
 // when the successive calls of DrawImages satisfy the below conditions:
 //
@@ -159,6 +320,11 @@ type DrawImageOptions struct {
 //   * All CompositeMode values are same
 //   * All Filter values are same
 //
+// ColorScale values don't matter for batching.
+//
+// When CompositeMode is CompositeModeCopy, GeoM is a translation by integers, and neither ColorM nor ColorScale
+// changes colors, DrawImage copies the texels without a draw call. Such a copy is not batched with draws.
+//
 // Even when all the above conditions are satisfied, multiple draw commands can
 // be used in really rare cases. Ebiten images usually share an internal
 // automatic texture atlas, but when you consume the atlas, or you create a huge
@@ -177,6 +343,12 @@ func (i *Image) DrawImage(img *Image, options *DrawImageOptions) {
 	if i.isDisposed() {
 		return
 	}
+	if i.compressed {
+		panic("ebiten: an image created from a compressed texture cannot be a rendering destination (DrawImage)")
+	}
+	if i.native {
+		panic("ebiten: an image created from a native texture cannot be a rendering destination (DrawImage)")
+	}
 
 	dstBounds := i.Bounds()
 	dstRegion := graphicsdriver.Region{
@@ -202,12 +374,92 @@ func (i *Image) DrawImage(img *Image, options *DrawImageOptions) {
 	sy0 := float32(bounds.Min.Y)
 	sx1 := float32(bounds.Max.X)
 	sy1 := float32(bounds.Max.Y)
-	vs := graphics.QuadVertices(sx0, sy0, sx1, sy1, a, b, c, d, tx, ty, 1, 1, 1, 1)
+	cs := &options.ColorScale
+	vs := graphics.QuadVertices(sx0, sy0, sx1, sy1, a, b, c, d, tx, ty, cs.R(), cs.G(), cs.B(), cs.A())
+	if options.PixelSnap || IsPixelSnapping() {
+		snapVertices(vs)
+	}
+	if options.DepthTest {
+		for i := 0; i < 4; i++ {
+			vs[i*graphics.VertexFloatNum+graphics.VertexDepthIndex] = options.Z
+		}
+	}
 	is := graphics.QuadIndices()
 
+	if options.ColorKey != nil {
+		i.drawImageWithColorKey(img, vs, is, dstRegion, options)
+		return
+	}
+
+	// Copying the image by an integer translation without changing colors is a texture copy without a draw call.
+	if mode == graphicsdriver.CompositeModeCopy && !options.DepthTest && canCopyPixels(i, img) && options.ColorScale.isIdentity() && options.ColorM.affineColorM().IsIdentity() {
+		if x, y, ok := options.GeoM.integerTranslation(); ok {
+			i.CopyFrom(img, image.Pt(x, y), bounds)
+			return
+		}
+	}
+
 	srcs := [graphics.ShaderImageNum]*mipmap.Mipmap{img.mipmap}
 
-	i.mipmap.DrawTriangles(srcs, vs, is, options.ColorM.affineColorM(), mode, filter, graphicsdriver.AddressUnsafe, dstRegion, graphicsdriver.Region{}, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, false, canSkipMipmap(options.GeoM, filter))
+	i.mipmap.DrawTriangles(srcs, vs, is, options.ColorM.affineColorM(), mode, filter, graphicsdriver.AddressUnsafe, dstRegion, graphicsdriver.Region{}, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, false, options.DepthTest, canSkipMipmap(options.GeoM, filter))
+}
+
+// CopyFrom copies the pixels of the region srcRect of the given image src to the image i.
+// The upper-left corner of srcRect is copied to dstPt.
+//
+// Unlike DrawImage, CopyFrom preserves the exact pixel values: no filtering, blending, or color matrix is applied,
+// and the destination pixels are replaced even with transparent source pixels.
+// This is useful to build texture atlases or palette textures.
+//
+// srcRect and dstPt are in the coordinates of the images' bounds. The regions outside the bounds are ignored.
+//
+// When the image i is disposed, CopyFrom does nothing.
+// When the given image src is disposed, CopyFrom panics.
+//
+// When the given image is as same as i, CopyFrom panics.
+func (i *Image) CopyFrom(src *Image, dstPt image.Point, srcRect image.Rectangle) {
+	i.copyCheck()
+
+	if src.isDisposed() {
+		panic("ebiten: the given image to CopyFrom must not be disposed")
+	}
+	if i.isDisposed() {
+		return
+	}
+
+	srcRect = srcRect.Intersect(src.Bounds())
+	dstRect := srcRect.Add(dstPt.Sub(srcRect.Min)).Intersect(i.Bounds())
+	if dstRect.Empty() {
+		return
+	}
+	srcRect = dstRect.Add(srcRect.Min.Sub(dstPt))
+
+	// A texture copy cannot convert pixel formats and the screen has no texture to copy to or from.
+	// In these cases, use a draw call with the copy mode, the nearest filter, and an integer translation, which is
+	// also pixel-exact.
+	if !canCopyPixels(i, src) {
+		op := &DrawImageOptions{}
+		op.GeoM.Translate(float64(dstRect.Min.X), float64(dstRect.Min.Y))
+		op.CompositeMode = CompositeModeCopy
+		op.Filter = FilterNearest
+		i.DrawImage(src.SubImage(srcRect).(*Image), op)
+		return
+	}
+
+	i.mipmap.CopyPixels(src.mipmap, dstRect.Min.X, dstRect.Min.Y, srcRect.Min.X, srcRect.Min.Y, dstRect.Dx(), dstRect.Dy())
+}
+
+// canCopyPixels reports whether the texels of src can be copied to dst without a draw call.
+func canCopyPixels(dst, src *Image) bool {
+	return !dst.isScreen() && !dst.compressed && !dst.native && !src.isScreen() && !src.compressed && !src.native
+}
+
+// isScreen reports whether the image is the screen or its sub-image.
+func (i *Image) isScreen() bool {
+	if i.isSubImage() {
+		return i.original.screen
+	}
+	return i.screen
 }
 
 // Vertex represents a vertex passed to DrawTriangles.
@@ -234,6 +486,59 @@ type Vertex struct {
 	ColorG float32
 	ColorB float32
 	ColorA float32
+
+	// DstZ represents the depth of the vertex in [0, 1]. A smaller value is nearer.
+	// DstZ is used only when the depth test is enabled.
+	DstZ float32
+}
+
+// AppendVerticesForImage appends the four vertices of a quad to draw the image img with the geometry matrix geoM to dst,
+// and returns the extended slice.
+//
+// The vertices are in the order of the upper-left, the upper-right, the lower-left and the lower-right.
+// Use AppendQuadIndices for the indices.
+//
+// The color values are applied to the vertices. For DrawTriangles, (1, 1, 1, 1) doesn't change the colors.
+//
+// Reusing dst like AppendVerticesForImage(vertices[:0], ...) avoids allocations.
+func AppendVerticesForImage(dst []Vertex, img *Image, geoM GeoM, colorR, colorG, colorB, colorA float32) []Vertex {
+	b := img.Bounds()
+	sx0, sy0 := float32(b.Min.X), float32(b.Min.Y)
+	sx1, sy1 := float32(b.Max.X), float32(b.Max.Y)
+	w, h := float64(b.Dx()), float64(b.Dy())
+
+	for _, p := range [...]struct {
+		x, y   float64
+		sx, sy float32
+	}{
+		{0, 0, sx0, sy0},
+		{w, 0, sx1, sy0},
+		{0, h, sx0, sy1},
+		{w, h, sx1, sy1},
+	} {
+		dx, dy := geoM.Apply(p.x, p.y)
+		dst = append(dst, Vertex{
+			DstX:   float32(dx),
+			DstY:   float32(dy),
+			SrcX:   p.sx,
+			SrcY:   p.sy,
+			ColorR: colorR,
+			ColorG: colorG,
+			ColorB: colorB,
+			ColorA: colorA,
+		})
+	}
+	return dst
+}
+
+// AppendQuadIndices appends the six indices of a quad made by AppendVerticesForImage to dst, and returns the extended slice.
+//
+// base is the index of the first vertex of the quad. For example, base for the n-th quad is 4*n when the quads'
+// vertices are appended to the same slice.
+//
+// Reusing dst like AppendQuadIndices(indices[:0], ...) avoids allocations.
+func AppendQuadIndices(dst []uint16, base uint16) []uint16 {
+	return append(dst, base, base+1, base+2, base+1, base+2, base+3)
 }
 
 // Address represents a sampler address mode.
@@ -271,6 +576,19 @@ type DrawTrianglesOptions struct {
 	// If Shader is not nil, ColorM is ignored.
 	ColorM ColorM
 
+	// GeoM is a geometry matrix applied to the destination positions of the vertices.
+	// The default (zero) value is identity, which doesn't change the positions.
+	//
+	// GeoM is applied on GPU, which is cheaper than transforming the vertices by yourself before calling
+	// DrawTriangles. On the other hand, draw calls with different GeoM values are not batched.
+	GeoM GeoM
+
+	// ColorScale is a scale applied to the vertex colors.
+	// The default (zero) value is identity, which doesn't change any color.
+	//
+	// Like GeoM, ColorScale is applied on GPU, and draw calls with different ColorScale values are not batched.
+	ColorScale ColorScale
+
 	// CompositeMode is a composite mode to draw.
 	// The default (zero) value is regular alpha blending.
 	CompositeMode CompositeMode
@@ -291,6 +609,13 @@ type DrawTrianglesOptions struct {
 	//
 	// The default (zero) value is FillAll.
 	FillRule FillRule
+
+	// DepthTest indicates whether the depth test is enabled with the vertices' DstZ values.
+	// DepthTest cannot be used with EvenOdd.
+	// See the document of (*Image).ClearDepth for details.
+	//
+	// The default (zero) value is false.
+	DepthTest bool
 }
 
 // MaxIndicesNum is the maximum number of indices for DrawTriangles.
@@ -306,6 +631,9 @@ const MaxIndicesNum = graphics.IndicesNum
 //
 // The rule in which DrawTriangles works effectively is same as DrawImage's.
 //
+// DrawTriangles doesn't retain vertices and indices. They can be modified or reused right after DrawTriangles returns,
+// so pooling the slices with AppendVerticesForImage and AppendQuadIndices avoids allocations.
+//
 // When the given image is disposed, DrawTriangles panics.
 //
 // When the image i is disposed, DrawTriangles does nothing.
@@ -318,6 +646,12 @@ func (i *Image) DrawTriangles(vertices []Vertex, indices []uint16, img *Image, o
 	if i.isDisposed() {
 		return
 	}
+	if i.compressed {
+		panic("ebiten: an image created from a compressed texture cannot be a rendering destination (DrawTriangles)")
+	}
+	if i.native {
+		panic("ebiten: an image created from a native texture cannot be a rendering destination (DrawTriangles)")
+	}
 
 	if len(indices)%3 != 0 {
 		panic("ebiten: len(indices) % 3 must be 0")
@@ -338,6 +672,9 @@ func (i *Image) DrawTriangles(vertices []Vertex, indices []uint16, img *Image, o
 	if options == nil {
 		options = &DrawTrianglesOptions{}
 	}
+	if options.DepthTest && options.FillRule == EvenOdd {
+		panic("ebiten: DepthTest cannot be used with EvenOdd")
+	}
 
 	mode := graphicsdriver.CompositeMode(options.CompositeMode)
 
@@ -355,6 +692,21 @@ func (i *Image) DrawTriangles(vertices []Vertex, indices []uint16, img *Image, o
 
 	filter := graphicsdriver.Filter(options.Filter)
 
+	// GeoM and ColorScale are passed to the default shader as uniform variables.
+	// When both are identity, no uniform variables are passed so that the draw call can be merged with other ones.
+	var uniforms []graphicsdriver.Uniform
+	if options.GeoM != (GeoM{}) || !options.ColorScale.isIdentity() {
+		a, b, c, d, tx, ty := options.GeoM.elements32()
+		uniforms = make([]graphicsdriver.Uniform, graphics.DefaultShaderUniformVariablesNum)
+		uniforms[graphics.DefaultShaderGeoMUniformVariableIndex].Float32s = []float32{a, b, c, d, tx, ty}
+		uniforms[graphics.DefaultShaderColorScaleUniformVariableIndex].Float32s = []float32{
+			options.ColorScale.R(),
+			options.ColorScale.G(),
+			options.ColorScale.B(),
+			options.ColorScale.A(),
+		}
+	}
+
 	vs := graphics.Vertices(len(vertices))
 	for i, v := range vertices {
 		vs[i*graphics.VertexFloatNum] = v.DstX
@@ -365,13 +717,14 @@ func (i *Image) DrawTriangles(vertices []Vertex, indices []uint16, img *Image, o
 		vs[i*graphics.VertexFloatNum+5] = v.ColorG
 		vs[i*graphics.VertexFloatNum+6] = v.ColorB
 		vs[i*graphics.VertexFloatNum+7] = v.ColorA
+		vs[i*graphics.VertexFloatNum+8] = v.DstZ
 	}
-	is := make([]uint16, len(indices))
+	is := graphics.Indices(len(indices))
 	copy(is, indices)
 
 	srcs := [graphics.ShaderImageNum]*mipmap.Mipmap{img.mipmap}
 
-	i.mipmap.DrawTriangles(srcs, vs, is, options.ColorM.affineColorM(), mode, filter, address, dstRegion, sr, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, options.FillRule == EvenOdd, false)
+	i.mipmap.DrawTriangles(srcs, vs, is, options.ColorM.affineColorM(), mode, filter, address, dstRegion, sr, [graphics.ShaderImageNum - 1][2]float32{}, nil, uniforms, options.FillRule == EvenOdd, options.DepthTest, false)
 }
 
 // DrawTrianglesShaderOptions represents options for DrawTrianglesShader.
@@ -402,6 +755,13 @@ type DrawTrianglesShaderOptions struct {
 	//
 	// The default (zero) value is FillAll.
 	FillRule FillRule
+
+	// DepthTest indicates whether the depth test is enabled with the vertices' DstZ values.
+	// DepthTest cannot be used with EvenOdd.
+	// See the document of (*Image).ClearDepth for details.
+	//
+	// The default (zero) value is false.
+	DepthTest bool
 }
 
 func init() {
@@ -421,10 +781,16 @@ func init() {
 //
 // If len(indices) is more than MaxIndicesNum, DrawTrianglesShader panics.
 //
+// DrawTrianglesShader doesn't retain vertices and indices. They can be modified or reused right after
+// DrawTrianglesShader returns.
+//
 // When a specified image is non-nil and is disposed, DrawTrianglesShader panics.
 //
 // When the image i is disposed, DrawTrianglesShader does nothing.
 //
+// Successive DrawTrianglesShader calls are merged into one draw command when the render targets, the shaders,
+// the source images, the uniform values, and the composite modes are the same.
+//
 // This API is experimental.
 func (i *Image) DrawTrianglesShader(vertices []Vertex, indices []uint16, shader *Shader, options *DrawTrianglesShaderOptions) {
 	i.copyCheck()
@@ -441,6 +807,36 @@ func (i *Image) DrawTrianglesShader(vertices []Vertex, indices []uint16, shader
 	}
 	// TODO: Check the maximum value of indices and len(vertices)?
 
+	vs := graphics.Vertices(len(vertices))
+	for i, v := range vertices {
+		vs[i*graphics.VertexFloatNum] = v.DstX
+		vs[i*graphics.VertexFloatNum+1] = v.DstY
+		vs[i*graphics.VertexFloatNum+2] = v.SrcX
+		vs[i*graphics.VertexFloatNum+3] = v.SrcY
+		vs[i*graphics.VertexFloatNum+4] = v.ColorR
+		vs[i*graphics.VertexFloatNum+5] = v.ColorG
+		vs[i*graphics.VertexFloatNum+6] = v.ColorB
+		vs[i*graphics.VertexFloatNum+7] = v.ColorA
+		vs[i*graphics.VertexFloatNum+8] = v.DstZ
+	}
+	is := graphics.Indices(len(indices))
+	copy(is, indices)
+
+	i.drawTrianglesShader(vs, is, shader, options)
+}
+
+// drawTrianglesShader draws triangles with the vertices vs and the indices is with the specified shader.
+//
+// vs and is must be the slices returned by graphics.Vertices and graphics.Indices, or ones not to be modified
+// until the end of the frame.
+func (i *Image) drawTrianglesShader(vs []float32, is []uint16, shader *Shader, options *DrawTrianglesShaderOptions) {
+	if i.compressed {
+		panic("ebiten: an image created from a compressed texture cannot be a rendering destination (DrawTrianglesShader)")
+	}
+	if i.native {
+		panic("ebiten: an image created from a native texture cannot be a rendering destination (DrawTrianglesShader)")
+	}
+
 	dstBounds := i.Bounds()
 	dstRegion := graphicsdriver.Region{
 		X:      float32(dstBounds.Min.X),
@@ -452,22 +848,15 @@ func (i *Image) DrawTrianglesShader(vertices []Vertex, indices []uint16, shader
 	if options == nil {
 		options = &DrawTrianglesShaderOptions{}
 	}
+	if options.DepthTest && options.FillRule == EvenOdd {
+		panic("ebiten: DepthTest cannot be used with EvenOdd")
+	}
 
 	mode := graphicsdriver.CompositeMode(options.CompositeMode)
 
-	vs := graphics.Vertices(len(vertices))
-	for i, v := range vertices {
-		vs[i*graphics.VertexFloatNum] = v.DstX
-		vs[i*graphics.VertexFloatNum+1] = v.DstY
-		vs[i*graphics.VertexFloatNum+2] = v.SrcX
-		vs[i*graphics.VertexFloatNum+3] = v.SrcY
-		vs[i*graphics.VertexFloatNum+4] = v.ColorR
-		vs[i*graphics.VertexFloatNum+5] = v.ColorG
-		vs[i*graphics.VertexFloatNum+6] = v.ColorB
-		vs[i*graphics.VertexFloatNum+7] = v.ColorA
+	if shader.usesPrevDst && options.Images[prevDstImageIndex] != nil {
+		panic(fmt.Sprintf("ebiten: Images[%d] must be nil when the shader uses %s", prevDstImageIndex, prevDstFuncName))
 	}
-	is := make([]uint16, len(indices))
-	copy(is, indices)
 
 	var imgs [graphics.ShaderImageNum]*mipmap.Mipmap
 	var imgw, imgh int
@@ -517,9 +906,15 @@ func (i *Image) DrawTrianglesShader(vertices []Vertex, indices []uint16, shader
 		offsets[i][1] = -sy + float32(b.Min.Y)
 	}
 
-	us := shader.convertUniforms(options.Uniforms)
+	if shader.usesPrevDst {
+		imgs[prevDstImageIndex] = i.copyForPrevDst().mipmap
+		offsets[prevDstImageIndex-1][0] = -sx
+		offsets[prevDstImageIndex-1][1] = -sy
+	}
+
+	us := shader.convertUniforms(options.Uniforms, nil)
 
-	i.mipmap.DrawTriangles(imgs, vs, is, affine.ColorMIdentity{}, mode, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dstRegion, sr, offsets, shader.shader, us, options.FillRule == EvenOdd, false)
+	i.mipmap.DrawTriangles(imgs, vs, is, affine.ColorMIdentity{}, mode, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dstRegion, sr, offsets, shader.shader, us, options.FillRule == EvenOdd, options.DepthTest, false)
 }
 
 // DrawRectShaderOptions represents options for DrawRectShader.
@@ -543,8 +938,29 @@ type DrawRectShaderOptions struct {
 	Uniforms map[string]interface{}
 
 	// Images is a set of the source images.
-	// All the image must be the same size with the rectangle.
+	// The images can have different sizes from the rectangle and from each other.
 	Images [4]*Image
+
+	// SourceOffsets is a set of the positions of the source images corresponding to the rectangle's upper-left corner.
+	// The positions are relative to the upper-left corners of the images' bounds.
+	// For example, if SourceOffsets[1] is (10, 20), the pixel (0, 0) of the rectangle reads the pixel (10, 20) of Images[1].
+	//
+	// As the positions are calculated based on Images[0], SourceOffsets must be zero values when Images[0] is nil,
+	// or DrawRectShader panics.
+	//
+	// The default (zero) value is (0, 0) for all the images.
+	SourceOffsets [4]image.Point
+
+	// SourceAddresses is a set of the address modes for the source images.
+	// The address mode specifies the result of imageSrcNAt when the position is out of the image.
+	// With AddressUnsafe or AddressClampToZero, imageSrcNAt returns 0 (transparent) for out-of-range positions.
+	// With AddressRepeat, the positions wrap to the other side of the image.
+	// imageSrcNUnsafeAt is not affected by the address modes.
+	//
+	// SourceAddresses must be zero values when Images[0] is nil, or DrawRectShader panics.
+	//
+	// The default (zero) value is AddressUnsafe for all the images.
+	SourceAddresses [4]Address
 }
 
 func init() {
@@ -562,6 +978,9 @@ func init() {
 //
 // When the image i is disposed, DrawRectShader does nothing.
 //
+// Successive DrawRectShader calls are merged into one draw command when the render targets, the shaders,
+// the source images, the uniform values, and the composite modes are the same.
+//
 // This API is experimental.
 func (i *Image) DrawRectShader(width, height int, shader *Shader, options *DrawRectShaderOptions) {
 	i.copyCheck()
@@ -569,6 +988,12 @@ func (i *Image) DrawRectShader(width, height int, shader *Shader, options *DrawR
 	if i.isDisposed() {
 		return
 	}
+	if i.compressed {
+		panic("ebiten: an image created from a compressed texture cannot be a rendering destination (DrawRectShader)")
+	}
+	if i.native {
+		panic("ebiten: an image created from a native texture cannot be a rendering destination (DrawRectShader)")
+	}
 
 	dstBounds := i.Bounds()
 	dstRegion := graphicsdriver.Region{
@@ -584,6 +1009,17 @@ func (i *Image) DrawRectShader(width, height int, shader *Shader, options *DrawR
 
 	mode := graphicsdriver.CompositeMode(options.CompositeMode)
 
+	if shader.usesPrevDst && options.Images[prevDstImageIndex] != nil {
+		panic(fmt.Sprintf("ebiten: Images[%d] must be nil when the shader uses %s", prevDstImageIndex, prevDstFuncName))
+	}
+	if options.Images[0] == nil {
+		for i := range options.Images {
+			if options.SourceOffsets[i] != (image.Point{}) || options.SourceAddresses[i] != AddressUnsafe {
+				panic("ebiten: SourceOffsets and SourceAddresses must be zero values when Images[0] is nil")
+			}
+		}
+	}
+
 	var imgs [graphics.ShaderImageNum]*mipmap.Mipmap
 	for i, img := range options.Images {
 		if img == nil {
@@ -592,17 +1028,18 @@ func (i *Image) DrawRectShader(width, height int, shader *Shader, options *DrawR
 		if img.isDisposed() {
 			panic("ebiten: the given image to DrawRectShader must not be disposed")
 		}
-		if w, h := img.Size(); width != w || height != h {
-			panic("ebiten: all the source images must be the same size with the rectangle")
-		}
 		imgs[i] = img.mipmap
 	}
 
-	var sx, sy float32
+	// (bx, by) is the upper-left corner of the 0th image, and (sx, sy) is the position corresponding to the rectangle's
+	// upper-left corner.
+	var bx, by, sx, sy float32
 	if options.Images[0] != nil {
 		b := options.Images[0].Bounds()
-		sx = float32(b.Min.X)
-		sy = float32(b.Min.Y)
+		bx = float32(b.Min.X)
+		by = float32(b.Min.Y)
+		sx = bx + float32(options.SourceOffsets[0].X)
+		sy = by + float32(options.SourceOffsets[0].Y)
 	}
 
 	a, b, c, d, tx, ty := options.GeoM.elements32()
@@ -626,12 +1063,93 @@ func (i *Image) DrawRectShader(width, height int, shader *Shader, options *DrawR
 			continue
 		}
 		b := img.Bounds()
-		offsets[i][0] = -sx + float32(b.Min.X)
-		offsets[i][1] = -sy + float32(b.Min.Y)
+		o := options.SourceOffsets[i+1]
+		offsets[i][0] = -sx + float32(b.Min.X+o.X)
+		offsets[i][1] = -sy + float32(b.Min.Y+o.Y)
+	}
+
+	// The regions of the source images are calculated in the 0th image's texels.
+	// Use the shader variant considering the source regions only when the regions differ from the 0th image's.
+	var sources *sourceUniforms
+	if needsSourceRegions(options) {
+		sources = &sourceUniforms{}
+		o0 := options.SourceOffsets[0]
+		for i, img := range options.Images {
+			if img == nil {
+				continue
+			}
+			w, h := img.Size()
+			sources.sizes[2*i] = float32(w)
+			sources.sizes[2*i+1] = float32(h)
+			sources.addresses[i] = float32(options.SourceAddresses[i])
+			if i >= 1 {
+				o := options.SourceOffsets[i]
+				sources.shifts[2*(i-1)] = float32(o.X - o0.X)
+				sources.shifts[2*(i-1)+1] = float32(o.Y - o0.Y)
+			}
+		}
+		shader = shader.sourceRegionsVariant()
 	}
 
-	us := shader.convertUniforms(options.Uniforms)
-	i.mipmap.DrawTriangles(imgs, vs, is, affine.ColorMIdentity{}, mode, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dstRegion, sr, offsets, shader.shader, us, false, canSkipMipmap(options.GeoM, graphicsdriver.FilterNearest))
+	if shader.usesPrevDst {
+		imgs[prevDstImageIndex] = i.copyForPrevDst().mipmap
+		offsets[prevDstImageIndex-1][0] = -bx
+		offsets[prevDstImageIndex-1][1] = -by
+	}
+
+	us := shader.convertUniforms(options.Uniforms, sources)
+	i.mipmap.DrawTriangles(imgs, vs, is, affine.ColorMIdentity{}, mode, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dstRegion, sr, offsets, shader.shader, us, false, false, canSkipMipmap(options.GeoM, graphicsdriver.FilterNearest))
+}
+
+// needsSourceRegions reports whether the source images' regions differ from the 0th image's region in the
+// 0th image's texels, or AddressRepeat is used.
+// If not, imageSrcNAt can use the 0th image's region for all the images.
+func needsSourceRegions(options *DrawRectShaderOptions) bool {
+	img0 := options.Images[0]
+	if img0 == nil {
+		return false
+	}
+	w0, h0 := img0.Size()
+	o0 := options.SourceOffsets[0]
+	for i, img := range options.Images {
+		if img == nil {
+			continue
+		}
+		if options.SourceAddresses[i] == AddressRepeat {
+			return true
+		}
+		if w, h := img.Size(); w != w0 || h != h0 {
+			return true
+		}
+		if options.SourceOffsets[i] != o0 {
+			return true
+		}
+	}
+	return false
+}
+
+// DumpHistory writes the internal draw history of the image in a human-readable format to w.
+//
+// The history is a list of draw commands (source image IDs, geometry, color matrices, composite modes and so on)
+// that Ebiten keeps to restore the image when the graphics context is lost.
+// DumpHistory is useful to debug why an offscreen image has unexpected contents.
+//
+// The history is recorded only in environments where restoring is needed, like browsers and Android,
+// and only while the history can reproduce the pixels.
+// Otherwise, DumpHistory writes the reason why the history is not available.
+//
+// The output format is for debugging and might be changed in the future.
+//
+// If the image is disposed, DumpHistory does nothing.
+//
+// DumpHistory can't be called outside the main loop (ebiten.Run's updating function) starts.
+func (i *Image) DumpHistory(w io.Writer) error {
+	i.copyCheck()
+
+	if i.isDisposed() {
+		return nil
+	}
+	return i.mipmap.DumpHistory(w)
 }
 
 // SubImage returns an image representing the portion of the image p visible through r.
@@ -663,9 +1181,11 @@ func (i *Image) SubImage(r image.Rectangle) image.Image {
 	}
 
 	img := &Image{
-		mipmap:   i.mipmap,
-		bounds:   r,
-		original: orig,
+		mipmap:     i.mipmap,
+		bounds:     r,
+		original:   orig,
+		compressed: i.compressed,
+		native:     i.native,
 	}
 	img.addr = img
 
@@ -741,11 +1261,18 @@ func (i *Image) at(x, y int) (r, g, b, a uint8) {
 // In the current implementation, successive calls of Set invokes loading pixels at most once, so this is efficient.
 //
 // If the image is disposed, Set does nothing.
+// If the image is created from a compressed texture or a native texture, Set panics.
 func (i *Image) Set(x, y int, clr color.Color) {
 	i.copyCheck()
 	if i.isDisposed() {
 		return
 	}
+	if i.compressed {
+		panic("ebiten: Set cannot be called on an image created from a compressed texture")
+	}
+	if i.native {
+		panic("ebiten: Set cannot be called on an image created from a native texture")
+	}
 	if !image.Pt(x, y).In(i.Bounds()) {
 		return
 	}
@@ -780,6 +1307,32 @@ func (i *Image) Dispose() {
 	}
 	i.mipmap.MarkDisposed()
 	i.mipmap = nil
+	if i.prevDst != nil {
+		i.prevDst.Dispose()
+		i.prevDst = nil
+	}
+}
+
+// copyForPrevDst copies the current pixels of the image i for imagePrevDstAt, and returns the copy.
+// The whole image i is copied at every draw call with such a shader, even if only a part of i is drawn.
+func (i *Image) copyForPrevDst() *Image {
+	orig := i
+	if i.isSubImage() {
+		orig = i.original
+	}
+
+	w, h := i.Size()
+	if orig.prevDst != nil {
+		if pw, ph := orig.prevDst.Size(); pw != w || ph != h {
+			orig.prevDst.Dispose()
+			orig.prevDst = nil
+		}
+	}
+	if orig.prevDst == nil {
+		orig.prevDst = NewImage(w, h)
+	}
+	orig.prevDst.CopyFrom(i, image.Point{}, i.Bounds())
+	return orig.prevDst
 }
 
 // ReplacePixels replaces the pixels of the image with p.
@@ -792,12 +1345,19 @@ func (i *Image) Dispose() {
 // When len(pix) is not appropriate, ReplacePixels panics.
 //
 // When the image is disposed, ReplacePixels does nothing.
+// When the image is created from a compressed texture or a native texture, ReplacePixels panics.
 func (i *Image) ReplacePixels(pixels []byte) {
 	i.copyCheck()
 
 	if i.isDisposed() {
 		return
 	}
+	if i.compressed {
+		panic("ebiten: ReplacePixels cannot be called on an image created from a compressed texture")
+	}
+	if i.native {
+		panic("ebiten: ReplacePixels cannot be called on an image created from a native texture")
+	}
 	r := i.Bounds()
 
 	// Do not need to copy pixels here.
@@ -845,10 +1405,47 @@ func NewImage(width, height int) *Image {
 //
 // NewImageFromImage panics if RunGame already finishes.
 func NewImageFromImage(source image.Image) *Image {
+	return NewImageFromImageWithOptions(source, nil)
+}
+
+// NewImageFromImageOptions represents options for NewImageFromImageWithOptions.
+type NewImageFromImageOptions struct {
+	// SourceUnpremultiplied represents whether the source's stored color values are not premultiplied by alpha.
+	//
+	// By default, the source's colors are converted as the source's color model reports them.
+	// For example, *image.RGBA is regarded as premultiplied and *image.NRGBA as non-premultiplied.
+	// Some image pipelines, however, store non-premultiplied values in *image.RGBA, and such images look
+	// darker at semi-transparent pixels when they are multiplied twice.
+	//
+	// If SourceUnpremultiplied is true, the stored color values of *image.RGBA and *image.NRGBA are
+	// uploaded as they are and premultiplied by alpha on GPU. For other image types, the colors are
+	// converted to non-premultiplied colors by color.NRGBAModel first.
+	//
+	// The default (zero) value is false.
+	SourceUnpremultiplied bool
+}
+
+// NewImageFromImageWithOptions creates a new image with the given image (source) with the given options.
+//
+// If options is nil, NewImageFromImageWithOptions behaves exactly same as NewImageFromImage.
+//
+// If source's width or height is less than 1 or more than device-dependent maximum size,
+// NewImageFromImageWithOptions panics.
+//
+// NewImageFromImageWithOptions should be called only when necessary.
+// For example, you should avoid to call NewImageFromImageWithOptions every Update or Draw call.
+// Reusing the same image by Clear is much more efficient than creating a new image.
+//
+// NewImageFromImageWithOptions panics if RunGame already finishes.
+func NewImageFromImageWithOptions(source image.Image, options *NewImageFromImageOptions) *Image {
 	if isRunGameEnded() {
 		panic(fmt.Sprintf("ebiten: NewImage cannot be called after RunGame finishes"))
 	}
 
+	if options == nil {
+		options = &NewImageFromImageOptions{}
+	}
+
 	size := source.Bounds().Size()
 	width, height := size.X, size.Y
 	if width <= 0 {
@@ -864,10 +1461,45 @@ func NewImageFromImage(source image.Image) *Image {
 	}
 	i.addr = i
 
-	i.ReplacePixels(imageToBytes(source))
+	if !options.SourceUnpremultiplied {
+		i.ReplacePixels(imageToBytes(source))
+		return i
+	}
+
+	// Upload the non-premultiplied values to a temporary image and premultiply them on GPU.
+	tmp := NewImage(width, height)
+	tmp.ReplacePixels(imageToUnpremultipliedBytes(source))
+	op := &DrawRectShaderOptions{}
+	op.CompositeMode = CompositeModeCopy
+	op.Images[0] = tmp
+	i.DrawRectShader(width, height, premultiplyShader(), op)
+	tmp.Dispose()
 	return i
 }
 
+var (
+	thePremultiplyShader     *Shader
+	thePremultiplyShaderOnce sync.Once
+)
+
+// premultiplyShader returns a shader to multiply the color values of the source image by its alpha values.
+func premultiplyShader() *Shader {
+	thePremultiplyShaderOnce.Do(func() {
+		s, err := NewShader([]byte(`package main
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	c := imageSrc0UnsafeAt(texCoord)
+	return vec4(c.rgb*c.a, c.a)
+}
+`))
+		if err != nil {
+			panic(fmt.Sprintf("ebiten: compiling the premultiply shader failed: %v", err))
+		}
+		thePremultiplyShader = s
+	})
+	return thePremultiplyShader
+}
+
 func newScreenFramebufferImage(width, height int) *Image {
 	i := &Image{
 		mipmap: mipmap.NewScreenFramebufferMipmap(width, height),
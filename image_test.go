@@ -16,6 +16,8 @@ package ebiten_test
 
 import (
 	"bytes"
+	"compress/zlib"
+	"encoding/binary"
 	"fmt"
 	"image"
 	"image/color"
@@ -393,6 +395,39 @@ func TestNewImageFromSubImage(t *testing.T) {
 	}
 }
 
+func TestNewImageFromImageWithOptionsSourceUnpremultiplied(t *testing.T) {
+	const w, h = 16, 16
+	// Fill an *image.RGBA with non-premultiplied values by mistake.
+	src := image.NewRGBA(image.Rect(0, 0, w, h))
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			src.Pix[4*(i+j*w)] = uint8(16 * i)
+			src.Pix[4*(i+j*w)+1] = uint8(16 * j)
+			src.Pix[4*(i+j*w)+2] = 0xff
+			src.Pix[4*(i+j*w)+3] = uint8(16*i + j)
+		}
+	}
+
+	op := &ebiten.NewImageFromImageOptions{
+		SourceUnpremultiplied: true,
+	}
+	img := ebiten.NewImageFromImageWithOptions(src, op)
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := img.At(i, j).(color.RGBA)
+			want := color.RGBAModel.Convert(color.NRGBA{
+				R: src.Pix[4*(i+j*w)],
+				G: src.Pix[4*(i+j*w)+1],
+				B: src.Pix[4*(i+j*w)+2],
+				A: src.Pix[4*(i+j*w)+3],
+			}).(color.RGBA)
+			if !sameColors(got, want, 1) {
+				t.Errorf("img.At(%d, %d): got %v; want %v", i, j, got, want)
+			}
+		}
+	}
+}
+
 type mutableRGBA struct {
 	r, g, b, a uint8
 }
@@ -2752,3 +2787,743 @@ func TestTooManyVertices(t *testing.T) {
 	// Confirm this doesn't freeze.
 	dst.At(0, 0)
 }
+
+func TestLargeImageDrawAcrossTiles(t *testing.T) {
+	const w, h = 3000, 100
+	l := ebiten.NewLargeImage(w, h)
+
+	src := ebiten.NewImage(64, 64)
+	src.Fill(color.RGBA{0xff, 0, 0, 0xff})
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(2020, 10)
+	l.DrawImage(src, op)
+
+	for _, p := range []image.Point{{2020, 10}, {2047, 40}, {2048, 40}, {2083, 73}} {
+		got := l.At(p.X, p.Y)
+		want := color.RGBA{0xff, 0, 0, 0xff}
+		if got != want {
+			t.Errorf("l.At(%d, %d): got %v; want %v", p.X, p.Y, got, want)
+		}
+	}
+	for _, p := range []image.Point{{2019, 10}, {2084, 40}, {2048, 74}} {
+		got := l.At(p.X, p.Y)
+		want := color.RGBA{}
+		if got != want {
+			t.Errorf("l.At(%d, %d): got %v; want %v", p.X, p.Y, got, want)
+		}
+	}
+
+	dst := ebiten.NewImage(100, 100)
+	op = &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(-2000, 0)
+	l.DrawTo(dst, op)
+	for _, p := range []image.Point{{20, 10}, {47, 40}, {48, 40}, {83, 73}} {
+		got := dst.At(p.X, p.Y)
+		want := color.RGBA{0xff, 0, 0, 0xff}
+		if got != want {
+			t.Errorf("dst.At(%d, %d): got %v; want %v", p.X, p.Y, got, want)
+		}
+	}
+}
+
+func TestLargeImageDrawSubImage(t *testing.T) {
+	const w, h = 3000, 100
+	l := ebiten.NewLargeImage(w, h)
+
+	// The sub-image's bounds are far from the origin, but the sub-image is rendered at the origin.
+	src := ebiten.NewImage(2600, 64)
+	src.Fill(color.RGBA{0xff, 0, 0, 0xff})
+	sub := src.SubImage(image.Rect(2500, 0, 2564, 64)).(*ebiten.Image)
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(10, 10)
+	l.DrawImage(sub, op)
+
+	for _, p := range []image.Point{{10, 10}, {73, 73}} {
+		got := l.At(p.X, p.Y)
+		want := color.RGBA{0xff, 0, 0, 0xff}
+		if got != want {
+			t.Errorf("l.At(%d, %d): got %v; want %v", p.X, p.Y, got, want)
+		}
+	}
+	for _, p := range []image.Point{{74, 40}, {2510, 40}} {
+		got := l.At(p.X, p.Y)
+		want := color.RGBA{}
+		if got != want {
+			t.Errorf("l.At(%d, %d): got %v; want %v", p.X, p.Y, got, want)
+		}
+	}
+}
+
+func TestImageCopyFrom(t *testing.T) {
+	const w, h = 16, 16
+	pix := make([]byte, 4*w*h)
+	for i := range pix {
+		pix[i] = byte(i)
+	}
+	src := ebiten.NewImage(w, h)
+	src.ReplacePixels(pix)
+
+	dst := ebiten.NewImage(w, h)
+	dst.Fill(color.RGBA{0xff, 0xff, 0xff, 0xff})
+	dst.CopyFrom(src, image.Pt(8, 4), image.Rect(2, 2, 14, 6))
+
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := dst.At(i, j)
+			want := color.RGBA{0xff, 0xff, 0xff, 0xff}
+			// The copied region is clipped by the destination bounds.
+			if 8 <= i && 4 <= j && j < 8 {
+				idx := 4 * ((j-2)*w + (i - 6))
+				want = color.RGBA{pix[idx], pix[idx+1], pix[idx+2], pix[idx+3]}
+			}
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got %v; want %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestImageCopyFromSubImage(t *testing.T) {
+	const w, h = 16, 16
+	src := ebiten.NewImage(w, h)
+	src.Fill(color.RGBA{0, 0, 0xff, 0xff})
+	src.SubImage(image.Rect(4, 4, 8, 8)).(*ebiten.Image).Fill(color.RGBA{0xff, 0, 0, 0xff})
+
+	dst := ebiten.NewImage(w, h)
+	sub := dst.SubImage(image.Rect(8, 8, 12, 12)).(*ebiten.Image)
+	// The source region starts at the sub-image's upper-left corner, and the destination point is in the
+	// coordinates of the sub-image's bounds.
+	sub.CopyFrom(src.SubImage(image.Rect(4, 4, 16, 16)).(*ebiten.Image), image.Pt(8, 8), image.Rect(4, 4, 16, 16))
+
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := dst.At(i, j)
+			var want color.RGBA
+			if 8 <= i && i < 12 && 8 <= j && j < 12 {
+				want = color.RGBA{0xff, 0, 0, 0xff}
+			}
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got %v; want %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestImageDrawImageCopyModeTranslation(t *testing.T) {
+	const w, h = 16, 16
+	src := ebiten.NewImage(w, h)
+	src.Fill(color.RGBA{0xff, 0, 0, 0xff})
+	src.SubImage(image.Rect(6, 6, 8, 8)).(*ebiten.Image).Clear()
+
+	dst := ebiten.NewImage(w, h)
+	dst.Fill(color.RGBA{0, 0, 0xff, 0xff})
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(2, 3)
+	op.CompositeMode = ebiten.CompositeModeCopy
+	// The transparent pixels of the source must replace the destination pixels.
+	dst.DrawImage(src.SubImage(image.Rect(4, 4, 8, 8)).(*ebiten.Image), op)
+
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := dst.At(i, j)
+			want := color.RGBA{0, 0, 0xff, 0xff}
+			if 2 <= i && i < 6 && 3 <= j && j < 7 {
+				want = color.RGBA{0xff, 0, 0, 0xff}
+			}
+			if 4 <= i && i < 6 && 5 <= j && j < 7 {
+				want = color.RGBA{}
+			}
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestImageFillRect(t *testing.T) {
+	const w, h = 16, 16
+	img := ebiten.NewImage(w, h)
+	img.Fill(color.RGBA{0, 0, 0xff, 0xff})
+	img.FillRect(image.Rect(4, 4, 8, 20), color.RGBA{0xff, 0, 0, 0xff})
+	img.FillRect(image.Rect(10, 2, 12, 3), color.RGBA{0, 0xff, 0, 0xff})
+
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := img.At(i, j)
+			want := color.RGBA{0, 0, 0xff, 0xff}
+			switch {
+			case 4 <= i && i < 8 && 4 <= j:
+				want = color.RGBA{0xff, 0, 0, 0xff}
+			case 10 <= i && i < 12 && j == 2:
+				want = color.RGBA{0, 0xff, 0, 0xff}
+			}
+			if got != want {
+				t.Errorf("img.At(%d, %d): got %v; want %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestImageDrawPalettedImage(t *testing.T) {
+	const w, h = 4, 4
+	p0 := color.Palette{
+		color.RGBA{0, 0, 0, 0},
+		color.RGBA{0xff, 0, 0, 0xff},
+		color.RGBA{0, 0xff, 0, 0xff},
+	}
+	p1 := color.Palette{
+		color.RGBA{0, 0, 0, 0},
+		color.RGBA{0, 0, 0xff, 0xff},
+		color.RGBA{0x80, 0x80, 0x80, 0x80},
+	}
+	src := image.NewPaletted(image.Rect(0, 0, w, h), p0)
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			src.SetColorIndex(i, j, uint8((i+j)%len(p0)))
+		}
+	}
+	indices := ebiten.NewIndexImage(src)
+
+	for _, p := range []color.Palette{p0, p1} {
+		dst := ebiten.NewImage(w, h)
+		dst.DrawPalettedImage(indices, ebiten.NewPaletteImage(p), nil)
+		for j := 0; j < h; j++ {
+			for i := 0; i < w; i++ {
+				got := dst.At(i, j)
+				want := p[(i+j)%len(p)]
+				if got != want {
+					t.Errorf("dst.At(%d, %d): got %v; want %v", i, j, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestImagePrewarm(t *testing.T) {
+	const w, h = 16, 16
+	s, err := ebiten.NewShader([]byte(`package main
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	return vec4(1, 0, 0, 1)
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img := ebiten.NewImage(w, h)
+	img.Fill(color.RGBA{0, 0, 0xff, 0xff})
+	img.Prewarm(&ebiten.PrewarmOptions{
+		Shaders: []*ebiten.Shader{s},
+	})
+
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := img.At(i, j)
+			want := color.RGBA{0, 0, 0xff, 0xff}
+			if got != want {
+				t.Errorf("img.At(%d, %d): got %v; want %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestImageWriteToAndReadImageFrom(t *testing.T) {
+	const w, h = 16, 16
+	pix := make([]byte, 4*w*h)
+	for i := 0; i < w*h; i++ {
+		a := byte(rand.Intn(0x100))
+		pix[4*i] = byte(rand.Intn(int(a) + 1))
+		pix[4*i+1] = byte(rand.Intn(int(a) + 1))
+		pix[4*i+2] = byte(rand.Intn(int(a) + 1))
+		pix[4*i+3] = a
+	}
+	img := ebiten.NewImage(w, h)
+	img.ReplacePixels(pix)
+
+	sub := img.SubImage(image.Rect(4, 5, 12, 14)).(*ebiten.Image)
+	var buf bytes.Buffer
+	n, err := sub.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := n, int64(buf.Len()); got != want {
+		t.Errorf("n: got %d; want %d", got, want)
+	}
+
+	img2, err := ebiten.ReadImageFrom(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := img2.Bounds(), image.Rect(0, 0, 8, 9); got != want {
+		t.Errorf("img2.Bounds(): got %v; want %v", got, want)
+	}
+	for j := 0; j < 9; j++ {
+		for i := 0; i < 8; i++ {
+			got := img2.At(i, j)
+			want := img.At(i+4, j+5)
+			if got != want {
+				t.Errorf("img2.At(%d, %d): got %v; want %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestReadImageFromBrokenSize(t *testing.T) {
+	img := ebiten.NewImage(1, 1)
+	var buf bytes.Buffer
+	if _, err := img.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	// Overwrite the size in the header with a huge size. The pixel data is too short for the size.
+	data := buf.Bytes()
+	const headerOffset = len("EBTNIMG") + 1
+	binary.LittleEndian.PutUint32(data[headerOffset:], 16384)
+	binary.LittleEndian.PutUint32(data[headerOffset+4:], 16384)
+
+	if _, err := ebiten.ReadImageFrom(bytes.NewReader(data)); err == nil {
+		t.Errorf("ReadImageFrom must return an error but not")
+	}
+}
+
+func TestReadImageFromTooWide(t *testing.T) {
+	// The total size is small enough, but the width exceeds the maximum image size.
+	const w, h = 1 << 20, 1
+
+	var buf bytes.Buffer
+	buf.WriteString("EBTNIMG")
+	var header [9]byte
+	header[0] = 1
+	binary.LittleEndian.PutUint32(header[1:], w)
+	binary.LittleEndian.PutUint32(header[5:], h)
+	buf.Write(header[:])
+	zw := zlib.NewWriter(&buf)
+	if _, err := zw.Write(make([]byte, 4*w*h)); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ebiten.ReadImageFrom(&buf); err == nil {
+		t.Errorf("ReadImageFrom must return an error but not")
+	}
+}
+
+func TestImageDrawTrianglesReuseSlices(t *testing.T) {
+	const w, h = 16, 16
+	src := ebiten.NewImage(w, h)
+	src.Fill(color.RGBA{0xff, 0, 0, 0xff})
+	dst := ebiten.NewImage(w*2, h)
+
+	var vs []ebiten.Vertex
+	var is []uint16
+	for i := 0; i < 2; i++ {
+		var geoM ebiten.GeoM
+		geoM.Translate(float64(w*i), 0)
+		vs = ebiten.AppendVerticesForImage(vs[:0], src, geoM, 1, 1, 1, 1)
+		is = ebiten.AppendQuadIndices(is[:0], 0)
+		dst.DrawTriangles(vs, is, src, nil)
+
+		// Modifying the slices after DrawTriangles must not affect the result.
+		for j := range vs {
+			vs[j] = ebiten.Vertex{}
+		}
+		for j := range is {
+			is[j] = 0
+		}
+	}
+
+	for j := 0; j < h; j++ {
+		for i := 0; i < w*2; i++ {
+			got := dst.At(i, j)
+			want := color.RGBA{0xff, 0, 0, 0xff}
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got %v; want %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestImageDrawTrianglesWithGeoMAndColorScale(t *testing.T) {
+	const w, h = 16, 16
+	src := ebiten.NewImage(w, h)
+	src.Fill(color.White)
+	dst := ebiten.NewImage(w*2, h*2)
+
+	vs := ebiten.AppendVerticesForImage(nil, src, ebiten.GeoM{}, 1, 1, 1, 1)
+	is := ebiten.AppendQuadIndices(nil, 0)
+	op := &ebiten.DrawTrianglesOptions{}
+	op.GeoM.Translate(w, h)
+	op.ColorScale.Scale(1, 0.5, 0, 1)
+	dst.DrawTriangles(vs, is, src, op)
+
+	for j := 0; j < h*2; j++ {
+		for i := 0; i < w*2; i++ {
+			got := dst.At(i, j).(color.RGBA)
+			var want color.RGBA
+			if i >= w && j >= h {
+				want = color.RGBA{0xff, 0x80, 0, 0xff}
+			}
+			if !sameColors(got, want, 1) {
+				t.Errorf("dst.At(%d, %d): got %v; want %v", i, j, got, want)
+			}
+		}
+	}
+
+	if got, want := vs[0].DstX, float32(0); got != want {
+		t.Errorf("vs[0].DstX: got %f; want %f", got, want)
+	}
+}
+
+func TestImageDrawTrianglesWithDifferentGeoMs(t *testing.T) {
+	const w, h = 16, 16
+	src := ebiten.NewImage(w, h)
+	src.Fill(color.White)
+	dst := ebiten.NewImage(w*2, h*2)
+
+	// The same vertices with different GeoM values must not be merged into one draw call with either GeoM.
+	vs := ebiten.AppendVerticesForImage(nil, src, ebiten.GeoM{}, 1, 1, 1, 1)
+	is := ebiten.AppendQuadIndices(nil, 0)
+	for _, p := range []image.Point{{0, 0}, {w, h}} {
+		op := &ebiten.DrawTrianglesOptions{}
+		op.GeoM.Scale(0.5, 0.5)
+		op.GeoM.Translate(float64(p.X), float64(p.Y))
+		dst.DrawTriangles(vs, is, src, op)
+	}
+
+	for j := 0; j < h*2; j++ {
+		for i := 0; i < w*2; i++ {
+			got := dst.At(i, j).(color.RGBA)
+			var want color.RGBA
+			if (i < w/2 && j < h/2) || (w <= i && i < w+w/2 && h <= j && j < h+h/2) {
+				want = color.RGBA{0xff, 0xff, 0xff, 0xff}
+			}
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got %v; want %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestImageDrawImageWithColorScale(t *testing.T) {
+	const w, h = 16, 16
+	src := ebiten.NewImage(w, h)
+	src.Fill(color.RGBA{0x80, 0x80, 0x80, 0xff})
+	dst := ebiten.NewImage(w, h)
+
+	op := &ebiten.DrawImageOptions{}
+	op.ColorM.Scale(2, 1, 1, 1)
+	op.ColorScale.Scale(1, 0.5, 0, 1)
+	dst.DrawImage(src, op)
+
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := dst.At(i, j).(color.RGBA)
+			want := color.RGBA{0xff, 0x40, 0, 0xff}
+			if !sameColors(got, want, 1) {
+				t.Errorf("dst.At(%d, %d): got %v; want %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestImageFillGradient(t *testing.T) {
+	const w, h = 256, 16
+	img := ebiten.NewImage(w, h)
+	img.FillGradient(&ebiten.Gradient{
+		X0: 0,
+		Y0: 0,
+		X1: w,
+		Y1: 0,
+		Stops: []ebiten.GradientStop{
+			{Offset: 1, Color: color.RGBA{0, 0, 0xff, 0xff}},
+			{Offset: 0, Color: color.RGBA{0xff, 0, 0, 0xff}},
+		},
+	})
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := img.At(i, j).(color.RGBA)
+			want := color.RGBA{byte(0xff - i), 0, byte(i), 0xff}
+			if !sameColors(got, want, 2) {
+				t.Errorf("img.At(%d, %d): got %v; want %v", i, j, got, want)
+			}
+		}
+	}
+
+	img.FillGradient(&ebiten.Gradient{
+		Type:   ebiten.GradientTypeRadial,
+		X0:     w / 2,
+		Y0:     h / 2,
+		Radius: 8,
+		Stops: []ebiten.GradientStop{
+			{Offset: 0, Color: color.White},
+			{Offset: 1, Color: color.Transparent},
+		},
+	})
+	if got, want := img.At(0, 0).(color.RGBA), (color.RGBA{}); got != want {
+		t.Errorf("img.At(0, 0): got %v; want %v", got, want)
+	}
+	if got, want := img.At(w/2, h/2).(color.RGBA), (color.RGBA{0xff, 0xff, 0xff, 0xff}); !sameColors(got, want, 0x20) {
+		t.Errorf("img.At(%d, %d): got %v; want %v", w/2, h/2, got, want)
+	}
+}
+
+func TestImageDrawTrianglesGradient(t *testing.T) {
+	const w, h = 16, 16
+	dst := ebiten.NewImage(w, h)
+	g := &ebiten.Gradient{
+		Stops: []ebiten.GradientStop{
+			{Offset: 0, Color: color.RGBA{0xff, 0, 0, 0xff}},
+		},
+	}
+
+	// The source positions and the colors of the vertices are ignored.
+	vs := []ebiten.Vertex{
+		{DstX: 0, DstY: 0, SrcX: 3, ColorR: 0.5, ColorA: 0.5},
+		{DstX: w, DstY: 0, SrcX: 3, ColorR: 0.5, ColorA: 0.5},
+		{DstX: 0, DstY: h / 2, SrcX: 3, ColorR: 0.5, ColorA: 0.5},
+		{DstX: w, DstY: h / 2, SrcX: 3, ColorR: 0.5, ColorA: 0.5},
+	}
+	is := []uint16{0, 1, 2, 1, 2, 3}
+	dst.DrawTrianglesGradient(vs, is, g, nil)
+
+	// DrawTrianglesGradient doesn't retain vertices.
+	for i := range vs {
+		vs[i].DstY += h / 2
+	}
+
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := dst.At(i, j).(color.RGBA)
+			want := color.RGBA{0xff, 0, 0, 0xff}
+			if j >= h/2 {
+				want = color.RGBA{}
+			}
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got %v; want %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestImageGradientCopy(t *testing.T) {
+	g := &ebiten.Gradient{
+		Stops: []ebiten.GradientStop{
+			{Offset: 0, Color: color.White},
+		},
+	}
+	for _, f := range []func(img *ebiten.Image){
+		func(img *ebiten.Image) {
+			img.FillGradient(g)
+		},
+		func(img *ebiten.Image) {
+			img.DrawTrianglesGradient(nil, nil, g, nil)
+		},
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("copying image and using it must panic")
+				}
+			}()
+			img0 := ebiten.NewImage(16, 16)
+			img1 := *img0
+			f(&img1)
+		}()
+	}
+}
+
+func TestImageDrawImagePixelSnap(t *testing.T) {
+	src := ebiten.NewImage(1, 1)
+	src.Fill(color.White)
+	dst := ebiten.NewImage(3, 1)
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(0.5, 0)
+	op.PixelSnap = true
+	dst.DrawImage(src, op)
+
+	for i := 0; i < 3; i++ {
+		got := dst.At(i, 0)
+		want := color.RGBA{}
+		if i == 1 {
+			want = color.RGBA{0xff, 0xff, 0xff, 0xff}
+		}
+		if got != want {
+			t.Errorf("dst.At(%d, 0): got %v; want %v", i, got, want)
+		}
+	}
+}
+
+func TestImageDrawImageColorKey(t *testing.T) {
+	const w, h = 4, 4
+
+	src := ebiten.NewImage(w, h)
+	pix := make([]byte, 4*w*h)
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			idx := 4 * (i + j*w)
+			if (i+j)%2 == 0 {
+				pix[idx], pix[idx+1], pix[idx+2], pix[idx+3] = 0xff, 0, 0xff, 0xff
+			} else {
+				pix[idx], pix[idx+1], pix[idx+2], pix[idx+3] = 0, 0xff, 0, 0xff
+			}
+		}
+	}
+	src.ReplacePixels(pix)
+
+	dst := ebiten.NewImage(w, h)
+	dst.Fill(color.RGBA{0, 0, 0xff, 0xff})
+	op := &ebiten.DrawImageOptions{}
+	op.ColorKey = color.RGBA{0xff, 0, 0xff, 0xff}
+	dst.DrawImage(src, op)
+
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := dst.At(i, j).(color.RGBA)
+			want := color.RGBA{0, 0xff, 0, 0xff}
+			if (i+j)%2 == 0 {
+				want = color.RGBA{0, 0, 0xff, 0xff}
+			}
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestImageDrawImageDepthTest(t *testing.T) {
+	const w, h = 4, 4
+
+	red := ebiten.NewImage(w, h)
+	red.Fill(color.RGBA{0xff, 0, 0, 0xff})
+	green := ebiten.NewImage(w, h)
+	green.Fill(color.RGBA{0, 0xff, 0, 0xff})
+
+	dst := ebiten.NewImage(w, h)
+
+	// The green image is drawn later but is farther, so the red image should remain.
+	op := &ebiten.DrawImageOptions{}
+	op.DepthTest = true
+	op.Z = 0.25
+	dst.DrawImage(red, op)
+	op.Z = 0.5
+	dst.DrawImage(green, op)
+
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := dst.At(i, j).(color.RGBA)
+			want := color.RGBA{0xff, 0, 0, 0xff}
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got %v; want %v", i, j, got, want)
+			}
+		}
+	}
+
+	// After ClearDepth, the green image should be rendered.
+	dst.ClearDepth()
+	dst.DrawImage(green, op)
+
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := dst.At(i, j).(color.RGBA)
+			want := color.RGBA{0, 0xff, 0, 0xff}
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got %v; want %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestImageDrawImageDepthTestTransparent(t *testing.T) {
+	const w, h = 4, 4
+
+	// The left half of src is transparent.
+	src := ebiten.NewImage(w, h)
+	src.SubImage(image.Rect(w/2, 0, w, h)).(*ebiten.Image).Fill(color.RGBA{0xff, 0, 0, 0xff})
+	green := ebiten.NewImage(w, h)
+	green.Fill(color.RGBA{0, 0xff, 0, 0xff})
+
+	dst := ebiten.NewImage(w, h)
+
+	op := &ebiten.DrawImageOptions{}
+	op.DepthTest = true
+	op.Z = 0.25
+	dst.DrawImage(src, op)
+	op.Z = 0.5
+	dst.DrawImage(green, op)
+
+	// Fully transparent pixels don't update the depth buffer.
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := dst.At(i, j).(color.RGBA)
+			want := color.RGBA{0xff, 0, 0, 0xff}
+			if i < w/2 {
+				want = color.RGBA{0, 0xff, 0, 0xff}
+			}
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got %v; want %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestImageDrawTrianglesDepthTest(t *testing.T) {
+	const w, h = 16, 16
+
+	src := ebiten.NewImage(w, h)
+	src.Fill(color.White)
+
+	dst := ebiten.NewImage(w, h)
+
+	quad := func(z float32, r, g, b float32) []ebiten.Vertex {
+		vs := []ebiten.Vertex{
+			{DstX: 0, DstY: 0, SrcX: 0, SrcY: 0},
+			{DstX: w, DstY: 0, SrcX: w, SrcY: 0},
+			{DstX: 0, DstY: h, SrcX: 0, SrcY: h},
+			{DstX: w, DstY: h, SrcX: w, SrcY: h},
+		}
+		for i := range vs {
+			vs[i].DstZ = z
+			vs[i].ColorR = r
+			vs[i].ColorG = g
+			vs[i].ColorB = b
+			vs[i].ColorA = 1
+		}
+		return vs
+	}
+	is := []uint16{0, 1, 2, 1, 2, 3}
+
+	op := &ebiten.DrawTrianglesOptions{}
+	op.DepthTest = true
+	dst.DrawTriangles(quad(0.5, 1, 0, 0), is, src, op)
+	dst.DrawTriangles(quad(0.75, 0, 1, 0), is, src, op)
+	dst.DrawTriangles(quad(0.25, 0, 0, 1), is, src, op)
+
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := dst.At(i, j).(color.RGBA)
+			want := color.RGBA{0, 0, 0xff, 0xff}
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got %v; want %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestImageDrawTrianglesDepthTestWithEvenOdd(t *testing.T) {
+	src := ebiten.NewImage(1, 1)
+	dst := ebiten.NewImage(1, 1)
+
+	defer func() {
+		if e := recover(); e == nil {
+			t.Errorf("DrawTriangles must panic with DepthTest and EvenOdd but not")
+		}
+	}()
+	op := &ebiten.DrawTrianglesOptions{}
+	op.DepthTest = true
+	op.FillRule = ebiten.EvenOdd
+	dst.DrawTriangles(nil, nil, src, op)
+}
@@ -0,0 +1,240 @@
+// Copyright 2018 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/qoi"
+)
+
+var (
+	theScreenshotKey          Key
+	theHasScreenshotKey       bool
+	theScreenshotKeyFromEnv   bool
+	theScreenshotFilenameFunc func(t time.Time) string
+	theScreenshotM            sync.Mutex
+)
+
+// SetScreenshotKey sets the key to take a screenshot of the game screen.
+//
+// When the key is pressed, the screen rendered by the game's Draw is saved as a PNG file,
+// or as a QOI file if the file name specified by SetScreenshotFilenameFunc ends with ".qoi".
+// On browsers, the PNG file is downloaded instead.
+//
+// The default key is specified by the environment variable EBITEN_SCREENSHOT_KEY like 'q'.
+// If neither SetScreenshotKey nor EBITEN_SCREENSHOT_KEY is used, no key takes a screenshot.
+//
+// SetScreenshotKey is concurrent-safe.
+func SetScreenshotKey(key Key) {
+	theScreenshotM.Lock()
+	defer theScreenshotM.Unlock()
+	theScreenshotKey = key
+	theHasScreenshotKey = true
+}
+
+// SetScreenshotFilenameFunc sets the function to determine the file path of a screenshot.
+// The function is called with the current time whenever a screenshot is taken.
+//
+// If f is nil, the default function is used. The default function returns 'screenshot_<datetime>.png'
+// in the current directory.
+// On browsers, the returned value is used as a file name to download.
+// On mobiles, the current directory might not be writable, so it is recommended to specify f.
+//
+// SetScreenshotFilenameFunc is concurrent-safe.
+func SetScreenshotFilenameFunc(f func(t time.Time) string) {
+	theScreenshotM.Lock()
+	defer theScreenshotM.Unlock()
+	theScreenshotFilenameFunc = f
+}
+
+func screenshotKey() (Key, bool) {
+	theScreenshotM.Lock()
+	defer theScreenshotM.Unlock()
+
+	if theHasScreenshotKey {
+		return theScreenshotKey, true
+	}
+	if !theScreenshotKeyFromEnv {
+		theScreenshotKeyFromEnv = true
+		if keyname := os.Getenv("EBITEN_SCREENSHOT_KEY"); keyname != "" {
+			if key, ok := keyNameToKeyCode(keyname); ok {
+				theScreenshotKey = key
+				theHasScreenshotKey = true
+				return key, true
+			}
+		}
+	}
+	return 0, false
+}
+
+func screenshotFilename(now time.Time) string {
+	theScreenshotM.Lock()
+	f := theScreenshotFilenameFunc
+	theScreenshotM.Unlock()
+
+	if f != nil {
+		return f(now)
+	}
+	return availableFilename("screenshot_", ".png", now)
+}
+
+// availableFilename returns a filename that is valid as a new file or directory.
+func availableFilename(prefix, postfix string, now time.Time) string {
+	const datetimeFormat = "20060102030405"
+
+	name := fmt.Sprintf("%s%s%s", prefix, now.Format(datetimeFormat), postfix)
+	for i := 1; ; i++ {
+		// Treat any error as a free name. For example, os.Stat always fails with ENOSYS on browsers,
+		// and the actual error, if any, is reported when the file is written.
+		if _, err := os.Stat(name); err != nil {
+			break
+		}
+		name = fmt.Sprintf("%s%s_%d%s", prefix, now.Format(datetimeFormat), i, postfix)
+	}
+	return name
+}
+
+func takeScreenshot(screen *Image) error {
+	name := screenshotFilename(time.Now())
+
+	w, h := screen.Size()
+	pix, err := screen.mipmap.Pixels(0, 0, w, h)
+	if err != nil {
+		return err
+	}
+
+	if !IsScreenTransparent() {
+		for i := 0; i < len(pix)/4; i++ {
+			pix[4*i+3] = 0xff
+		}
+	}
+
+	img := &image.RGBA{
+		Pix:    pix,
+		Stride: 4 * w,
+		Rect:   image.Rect(0, 0, w, h),
+	}
+
+	// Use QOI when the file name requests it, as QOI encoding is much faster than PNG.
+	var buf bytes.Buffer
+	mimeType := "image/png"
+	if strings.EqualFold(filepath.Ext(name), ".qoi") {
+		if err := qoi.Encode(&buf, img); err != nil {
+			return err
+		}
+		mimeType = "image/qoi"
+	} else {
+		if err := png.Encode(&buf, img); err != nil {
+			return err
+		}
+	}
+
+	return saveScreenshot(name, buf.Bytes(), mimeType)
+}
+
+type imageDumper struct {
+	g Game
+
+	keyState map[Key]int
+
+	toTakeScreenshot bool
+
+	hasDumpInternalImagesKey bool
+	dumpInternalImagesKey    Key
+	toDumpInternalImages     bool
+
+	err error
+}
+
+func (i *imageDumper) update() error {
+	if i.err != nil {
+		return i.err
+	}
+
+	const envInternalImagesKey = "EBITEN_INTERNAL_IMAGES_KEY"
+
+	if err := i.g.Update(); err != nil {
+		return err
+	}
+
+	// If keyState is nil, all values are not initialized.
+	if i.keyState == nil {
+		i.keyState = map[Key]int{}
+
+		if keyname := os.Getenv(envInternalImagesKey); keyname != "" {
+			if isDebug() && canDumpInternalImages {
+				if key, ok := keyNameToKeyCode(keyname); ok {
+					i.hasDumpInternalImagesKey = true
+					i.dumpInternalImagesKey = key
+				}
+			} else {
+				fmt.Fprintf(os.Stderr, "%s is disabled. Specify a build tag 'ebitendebug' to enable it on desktops.\n", envInternalImagesKey)
+			}
+		}
+	}
+
+	keys := map[Key]struct{}{}
+	screenshotKey, hasScreenshotKey := screenshotKey()
+	if hasScreenshotKey {
+		keys[screenshotKey] = struct{}{}
+	}
+	if i.hasDumpInternalImagesKey {
+		keys[i.dumpInternalImagesKey] = struct{}{}
+	}
+
+	for key := range keys {
+		if IsKeyPressed(key) {
+			i.keyState[key]++
+			if i.keyState[key] == 1 {
+				if hasScreenshotKey && key == screenshotKey {
+					i.toTakeScreenshot = true
+				}
+				if i.hasDumpInternalImagesKey && key == i.dumpInternalImagesKey {
+					i.toDumpInternalImages = true
+				}
+			}
+		} else {
+			i.keyState[key] = 0
+		}
+	}
+	return nil
+}
+
+func (i *imageDumper) dump(screen *Image) error {
+	if i.toTakeScreenshot {
+		i.toTakeScreenshot = false
+		if err := takeScreenshot(screen); err != nil {
+			return err
+		}
+	}
+
+	if i.toDumpInternalImages {
+		i.toDumpInternalImages = false
+		if err := dumpInternalImages(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/atlas"
+)
+
+// snapshotMagic is the magic number at the head of an image snapshot.
+const snapshotMagic = "EBTNIMG"
+
+const snapshotVersion = 1
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// WriteTo writes a snapshot of the image's pixels to w.
+// WriteTo implements io.WriterTo.
+//
+// A snapshot is independent from GPUs and graphics drivers, and can be restored by ReadImageFrom even in
+// another environment.
+// This is useful to implement save states that include dynamically generated images.
+//
+// The pixels in the image's bounds are read from GPU at once. This is much more efficient than calling At
+// for each pixel.
+// For a sub-image, only the region of the sub-image is written.
+//
+// WriteTo returns an error when the image is disposed.
+//
+// WriteTo can't be called outside the main loop (ebiten.Run's updating function) starts.
+func (i *Image) WriteTo(w io.Writer) (int64, error) {
+	i.copyCheck()
+
+	if i.isDisposed() {
+		return 0, errors.New("ebiten: WriteTo cannot be called on a disposed image")
+	}
+
+	b := i.Bounds()
+	pix, err := i.mipmap.Pixels(b.Min.X, b.Min.Y, b.Dx(), b.Dy())
+	if err != nil {
+		return 0, err
+	}
+
+	cw := &countingWriter{w: w}
+	if _, err := io.WriteString(cw, snapshotMagic); err != nil {
+		return cw.n, err
+	}
+	var header [9]byte
+	header[0] = snapshotVersion
+	binary.LittleEndian.PutUint32(header[1:], uint32(b.Dx()))
+	binary.LittleEndian.PutUint32(header[5:], uint32(b.Dy()))
+	if _, err := cw.Write(header[:]); err != nil {
+		return cw.n, err
+	}
+
+	// Prefer the speed to the size since a snapshot can be taken every frame.
+	zw, err := zlib.NewWriterLevel(cw, zlib.BestSpeed)
+	if err != nil {
+		return cw.n, err
+	}
+	if _, err := zw.Write(pix); err != nil {
+		return cw.n, err
+	}
+	if err := zw.Close(); err != nil {
+		return cw.n, err
+	}
+	return cw.n, nil
+}
+
+// ReadImageFrom reads a snapshot written by (*Image).WriteTo from r and creates a new image from it.
+//
+// The created image's bounds start at (0, 0) even if the snapshot is taken from a sub-image.
+//
+// ReadImageFrom reads only the snapshot from r. If r is not an io.ByteReader, ReadImageFrom might read
+// extra bytes after the snapshot.
+//
+// If the snapshot's width or height is more than device-dependent maximum size, ReadImageFrom returns an error.
+// As the maximum size is unknown before the game starts, ReadImageFrom doesn't check it in this case, and the
+// created image panics when it is used.
+//
+// ReadImageFrom panics if RunGame already finishes.
+func ReadImageFrom(r io.Reader) (*Image, error) {
+	br, ok := r.(interface {
+		io.Reader
+		io.ByteReader
+	})
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+
+	var header [len(snapshotMagic) + 9]byte
+	if _, err := io.ReadFull(br, header[:]); err != nil {
+		return nil, err
+	}
+	if string(header[:len(snapshotMagic)]) != snapshotMagic {
+		return nil, errors.New("ebiten: not an image snapshot")
+	}
+	h := header[len(snapshotMagic):]
+	if h[0] != snapshotVersion {
+		return nil, fmt.Errorf("ebiten: unsupported image snapshot version: %d", h[0])
+	}
+	width := int64(binary.LittleEndian.Uint32(h[1:]))
+	height := int64(binary.LittleEndian.Uint32(h[5:]))
+	if width == 0 || height == 0 || 4*width*height > math.MaxInt32 {
+		return nil, fmt.Errorf("ebiten: invalid image snapshot size: (%d, %d)", width, height)
+	}
+	if max := int64(atlas.MaxImageSize()); max > 0 && (width > max || height > max) {
+		return nil, fmt.Errorf("ebiten: image snapshot size (%d, %d) exceeds the maximum image size %d", width, height, max)
+	}
+
+	zr, err := zlib.NewReader(br)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	// Don't allocate the pixels with the size in the header before the data is confirmed to exist,
+	// as the header might be broken.
+	var buf bytes.Buffer
+	if _, err := io.CopyN(&buf, zr, 4*width*height); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	pix := buf.Bytes()
+
+	img := NewImage(int(width), int(height))
+	img.ReplacePixels(pix)
+	return img, nil
+}
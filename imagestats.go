@@ -0,0 +1,62 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"github.com/hajimehoshi/ebiten/v2/internal/atlas"
+)
+
+// ImageUsageStats represents statistics of the internal textures used for images.
+//
+// The values are estimations and the actual memory usage depends on the graphics driver.
+type ImageUsageStats struct {
+	// TextureCount is the number of the internal textures, except for the screen.
+	TextureCount int
+
+	// TextureBytes is the estimated GPU memory usage of the internal textures in bytes.
+	TextureBytes int64
+
+	// AtlasCount is the number of the internal textures shared by multiple small images.
+	AtlasCount int
+
+	// AtlasPixels is the total area of the shared textures in pixels.
+	AtlasPixels int64
+
+	// AtlasUsedPixels is the total area of the regions allocated on the shared textures in pixels.
+	//
+	// AtlasUsedPixels / AtlasPixels indicates how efficiently the shared textures are used.
+	AtlasUsedPixels int64
+
+	// AllocatedTexturesInLastFrame is the number of the internal textures newly allocated in the last frame.
+	AllocatedTexturesInLastFrame int
+}
+
+// ReadImageUsageStats returns the statistics of the internal textures.
+//
+// The statistics are updated at the end of every frame.
+// Before the first frame ends, ReadImageUsageStats returns a zero value.
+//
+// ReadImageUsageStats is concurrent-safe.
+func ReadImageUsageStats() ImageUsageStats {
+	s := atlas.ReadStats()
+	return ImageUsageStats{
+		TextureCount:                 s.TextureCount,
+		TextureBytes:                 s.TextureBytes,
+		AtlasCount:                   s.AtlasCount,
+		AtlasPixels:                  s.AtlasPixels,
+		AtlasUsedPixels:              s.AtlasUsedPixels,
+		AllocatedTexturesInLastFrame: s.AllocatedTexturesInLastFrame,
+	}
+}
@@ -88,3 +88,48 @@ func imageToBytesSlow(img image.Image) []byte {
 	draw.Draw(dstImg, image.Rect(0, 0, w, h), img, img.Bounds().Min, draw.Src)
 	return bs
 }
+
+// imageToUnpremultipliedBytes gets non-premultiplied RGBA bytes from img.
+//
+// If img is *image.RGBA or *image.NRGBA, the stored color values are returned as they are,
+// regardless of whether the color model is premultiplied or not.
+// Otherwise, the colors are converted by color.NRGBAModel.
+func imageToUnpremultipliedBytes(img image.Image) []byte {
+	var pix []byte
+	var stride int
+	switch img := img.(type) {
+	case *image.RGBA:
+		pix, stride = img.Pix, img.Stride
+	case *image.NRGBA:
+		pix, stride = img.Pix, img.Stride
+	default:
+		return imageToUnpremultipliedBytesSlow(img)
+	}
+
+	size := img.Bounds().Size()
+	w, h := size.X, size.Y
+	if len(pix) == 4*w*h {
+		return pix
+	}
+
+	// Even img is a subimage of another image, Pix starts with 0-th index.
+	bs := make([]byte, 4*w*h)
+	for j := 0; j < h; j++ {
+		copy(bs[4*w*j:4*w*(j+1)], pix[stride*j:stride*j+4*w])
+	}
+	return bs
+}
+
+func imageToUnpremultipliedBytesSlow(img image.Image) []byte {
+	size := img.Bounds().Size()
+	w, h := size.X, size.Y
+	bs := make([]byte, 4*w*h)
+
+	dstImg := &image.NRGBA{
+		Pix:    bs,
+		Stride: 4 * w,
+		Rect:   image.Rect(0, 0, w, h),
+	}
+	draw.Draw(dstImg, image.Rect(0, 0, w, h), img, img.Bounds().Min, draw.Src)
+	return bs
+}
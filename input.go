@@ -15,6 +15,7 @@
 package ebiten
 
 import (
+	"github.com/hajimehoshi/ebiten/v2/internal/fakeinput"
 	"github.com/hajimehoshi/ebiten/v2/internal/gamepad"
 	"github.com/hajimehoshi/ebiten/v2/internal/gamepaddb"
 	"github.com/hajimehoshi/ebiten/v2/internal/ui"
@@ -29,12 +30,19 @@ import (
 //
 // "Control" and modifier keys should be handled with IsKeyPressed.
 //
+// Dead keys and compose sequences are resolved by the OS or the browser: a dead key itself doesn't append a rune,
+// and the composed character is appended when the sequence completes.
+// To be notified when the keyboard layout is changed, implement KeyboardLayoutObserver.
+//
 // AppendInputChars is concurrent-safe.
 //
 // On Android (ebitenmobile), EbitenView must be focusable to enable to handle keyboard keys.
 //
 // Keyboards don't work on iOS yet (#1090).
 func AppendInputChars(runes []rune) []rune {
+	if fakeinput.IsEnabled() {
+		return fakeinput.AppendInputChars(runes)
+	}
 	return ui.Get().Input().AppendInputChars(runes)
 }
 
@@ -82,13 +90,20 @@ func IsKeyPressed(key Key) bool {
 		keys = []ui.Key{ui.Key(key)}
 	}
 	for _, k := range keys {
-		if ui.Get().Input().IsKeyPressed(k) {
+		if isUIKeyPressed(k) {
 			return true
 		}
 	}
 	return false
 }
 
+func isUIKeyPressed(key ui.Key) bool {
+	if fakeinput.IsEnabled() {
+		return fakeinput.IsKeyPressed(key)
+	}
+	return ui.Get().Input().IsKeyPressed(key)
+}
+
 // CursorPosition returns a position of a mouse cursor relative to the game screen (window). The cursor position is
 // 'logical' position and this considers the scale of the screen.
 //
@@ -98,14 +113,35 @@ func IsKeyPressed(key Key) bool {
 //
 // CursorPosition is concurrent-safe.
 func CursorPosition() (x, y int) {
+	if fakeinput.IsEnabled() {
+		return fakeinput.CursorPosition()
+	}
 	return ui.Get().Input().CursorPosition()
 }
 
+// SetCursorPosition moves the mouse cursor to the specified position relative to the game screen (window).
+// x and y are in the 'logical' position like CursorPosition.
+//
+// SetCursorPosition is useful, e.g., to implement custom cursor constraints or to recenter the cursor for aiming
+// when CursorModeCaptured is not suitable. In CursorModeCaptured, SetCursorPosition moves the virtual cursor
+// position, except on Windows on ARM64, where SetCursorPosition does nothing in CursorModeCaptured.
+//
+// SetCursorPosition does nothing before the main loop, on browsers and on mobiles, as these environments don't
+// allow to move the cursor.
+//
+// SetCursorPosition is concurrent-safe.
+func SetCursorPosition(x, y int) {
+	ui.Get().Input().SetCursorPosition(x, y)
+}
+
 // Wheel returns x and y offsets of the mouse wheel or touchpad scroll.
 // It returns 0 if the wheel isn't being rolled.
 //
 // Wheel is concurrent-safe.
 func Wheel() (xoff, yoff float64) {
+	if fakeinput.IsEnabled() {
+		return fakeinput.Wheel()
+	}
 	return ui.Get().Input().Wheel()
 }
 
@@ -116,6 +152,9 @@ func Wheel() (xoff, yoff float64) {
 //
 // IsMouseButtonPressed is concurrent-safe.
 func IsMouseButtonPressed(mouseButton MouseButton) bool {
+	if fakeinput.IsEnabled() {
+		return fakeinput.IsMouseButtonPressed(mouseButton)
+	}
 	return ui.Get().Input().IsMouseButtonPressed(mouseButton)
 }
 
@@ -136,6 +175,20 @@ func GamepadSDLID(id GamepadID) string {
 	return g.SDLID()
 }
 
+// GamepadVendorProductID returns the USB vendor ID and product ID of the gamepad (id).
+//
+// ok is false when the IDs are not available, e.g., for XInput devices on Windows or when the browser doesn't
+// expose them.
+//
+// GamepadVendorProductID is concurrent-safe.
+func GamepadVendorProductID(id GamepadID) (vendor, product uint16, ok bool) {
+	g := gamepad.Get(id)
+	if g == nil {
+		return 0, 0, false
+	}
+	return g.VendorProductID()
+}
+
 // GamepadName returns a string with the name.
 // This function may vary in how it returns descriptions for the same device across platforms.
 // for example the following drivers/platforms see a Xbox One controller as the following:
@@ -158,6 +211,9 @@ func GamepadName(id GamepadID) string {
 //
 // AppendGamepadIDs is concurrent-safe.
 func AppendGamepadIDs(gamepadIDs []GamepadID) []GamepadID {
+	if fakeinput.IsEnabled() {
+		return fakeinput.AppendGamepadIDs(gamepadIDs)
+	}
 	return gamepad.AppendGamepadIDs(gamepadIDs)
 }
 
@@ -168,10 +224,45 @@ func GamepadIDs() []GamepadID {
 	return AppendGamepadIDs(nil)
 }
 
+// GamepadFamily returns the family of the gamepad (id), which is useful to show the right button glyphs.
+//
+// The family is detected from the USB vendor ID, or from the name when the vendor ID is not available.
+// GamepadFamily returns GamepadFamilyGeneric when the family is unknown or the gamepad is not present.
+//
+// GamepadFamily is concurrent-safe.
+func GamepadFamily(id GamepadID) GamepadFamilyType {
+	g := gamepad.Get(id)
+	if g == nil {
+		return GamepadFamilyGeneric
+	}
+	return g.Family()
+}
+
+// StandardGamepadButtonLabel returns the label printed on the standard button (button) of the gamepads of the
+// family, e.g., "A" for StandardGamepadButtonRightBottom of GamepadFamilyXbox.
+//
+// As the standard layout is based on the button positions, the labels of the face buttons depend on the family.
+// For example, StandardGamepadButtonRightBottom is labeled "B" on GamepadFamilyNintendo. The directional buttons
+// don't have labels.
+//
+// StandardGamepadButtonLabel returns an empty string when the label is unknown, e.g., for GamepadFamilyGeneric.
+//
+// StandardGamepadButtonLabel is concurrent-safe.
+func StandardGamepadButtonLabel(family GamepadFamilyType, button StandardGamepadButton) string {
+	return gamepad.StandardButtonLabel(family, button)
+}
+
 // GamepadAxisNum returns the number of axes of the gamepad (id).
 //
 // GamepadAxisNum is concurrent-safe.
 func GamepadAxisNum(id GamepadID) int {
+	if fakeinput.IsEnabled() {
+		if g := fakeinput.GetGamepad(id); g != nil {
+			return len(g.Axes)
+		}
+		return 0
+	}
+
 	g := gamepad.Get(id)
 	if g == nil {
 		return 0
@@ -183,6 +274,13 @@ func GamepadAxisNum(id GamepadID) int {
 //
 // GamepadAxisValue is concurrent-safe.
 func GamepadAxisValue(id GamepadID, axis int) float64 {
+	if fakeinput.IsEnabled() {
+		if g := fakeinput.GetGamepad(id); g != nil {
+			return g.Axis(axis)
+		}
+		return 0
+	}
+
 	g := gamepad.Get(id)
 	if g == nil {
 		return 0
@@ -201,6 +299,13 @@ func GamepadAxis(id GamepadID, axis int) float64 {
 //
 // GamepadButtonNum is concurrent-safe.
 func GamepadButtonNum(id GamepadID) int {
+	if fakeinput.IsEnabled() {
+		if g := fakeinput.GetGamepad(id); g != nil {
+			return len(g.Buttons)
+		}
+		return 0
+	}
+
 	g := gamepad.Get(id)
 	if g == nil {
 		return 0
@@ -220,6 +325,13 @@ func GamepadButtonNum(id GamepadID) int {
 // The relationships between physical buttons and buttion IDs depend on environments.
 // There can be differences even between Chrome and Firefox.
 func IsGamepadButtonPressed(id GamepadID, button GamepadButton) bool {
+	if fakeinput.IsEnabled() {
+		if g := fakeinput.GetGamepad(id); g != nil {
+			return g.IsButtonPressed(int(button))
+		}
+		return false
+	}
+
 	g := gamepad.Get(id)
 	if g == nil {
 		return false
@@ -245,6 +357,13 @@ func IsGamepadButtonPressed(id GamepadID, button GamepadButton) bool {
 //
 // StandardGamepadAxisValue is concurrent safe.
 func StandardGamepadAxisValue(id GamepadID, axis StandardGamepadAxis) float64 {
+	if fakeinput.IsEnabled() {
+		if g := fakeinput.GetGamepad(id); g != nil {
+			return g.StandardAxisValue(axis)
+		}
+		return 0
+	}
+
 	g := gamepad.Get(id)
 	if g == nil {
 		return 0
@@ -258,6 +377,13 @@ func StandardGamepadAxisValue(id GamepadID, axis StandardGamepadAxis) float64 {
 //
 // StandardGamepadButtonValue is concurrent safe.
 func StandardGamepadButtonValue(id GamepadID, button StandardGamepadButton) float64 {
+	if fakeinput.IsEnabled() {
+		if g := fakeinput.GetGamepad(id); g != nil {
+			return g.StandardButtonValue(button)
+		}
+		return 0
+	}
+
 	g := gamepad.Get(id)
 	if g == nil {
 		return 0
@@ -271,6 +397,13 @@ func StandardGamepadButtonValue(id GamepadID, button StandardGamepadButton) floa
 //
 // IsStandardGamepadButtonPressed is concurrent safe.
 func IsStandardGamepadButtonPressed(id GamepadID, button StandardGamepadButton) bool {
+	if fakeinput.IsEnabled() {
+		if g := fakeinput.GetGamepad(id); g != nil {
+			return g.IsStandardButtonPressed(button)
+		}
+		return false
+	}
+
 	g := gamepad.Get(id)
 	if g == nil {
 		return false
@@ -282,6 +415,13 @@ func IsStandardGamepadButtonPressed(id GamepadID, button StandardGamepadButton)
 //
 // IsStandardGamepadLayoutAvailable is concurrent-safe.
 func IsStandardGamepadLayoutAvailable(id GamepadID) bool {
+	if fakeinput.IsEnabled() {
+		if g := fakeinput.GetGamepad(id); g != nil {
+			return g.StandardLayoutAvailable
+		}
+		return false
+	}
+
 	g := gamepad.Get(id)
 	if g == nil {
 		return false
@@ -336,6 +476,9 @@ type TouchID = ui.TouchID
 //
 // AppendTouchIDs is concurrent-safe.
 func AppendTouchIDs(touches []TouchID) []TouchID {
+	if fakeinput.IsEnabled() {
+		return fakeinput.AppendTouchIDs(touches)
+	}
 	return ui.Get().Input().AppendTouchIDs(touches)
 }
 
@@ -352,5 +495,38 @@ func TouchIDs() []TouchID {
 //
 // TouchPosition is cuncurrent-safe.
 func TouchPosition(id TouchID) (int, int) {
+	if fakeinput.IsEnabled() {
+		return fakeinput.TouchPosition(id)
+	}
 	return ui.Get().Input().TouchPosition(id)
 }
+
+// TouchPressure returns the normalized pressure of the touch of the specified ID in [0, 1].
+//
+// TouchPressure returns 0 when the pressure is not available, e.g., on devices without pressure sensors, on iOS
+// devices without 3D Touch, or on browsers not supporting Touch.force.
+// If the touch of the specified ID is not present, TouchPressure returns 0.
+//
+// TouchPressure is concurrent-safe.
+func TouchPressure(id TouchID) float64 {
+	if fakeinput.IsEnabled() {
+		return 0
+	}
+	return ui.Get().Input().TouchPressure(id)
+}
+
+// TouchRadius returns the radii of the contact ellipse of the touch of the specified ID in the logical screen pixels.
+//
+// rx is the horizontal radius and ry is the vertical radius. On Android, the radii are the halves of the major and
+// the minor axes of the contact ellipse, ignoring its orientation. On iOS, rx and ry are the same major radius.
+//
+// TouchRadius returns (0, 0) when the contact size is not available.
+// If the touch of the specified ID is not present, TouchRadius returns (0, 0).
+//
+// TouchRadius is concurrent-safe.
+func TouchRadius(id TouchID) (rx, ry float64) {
+	if fakeinput.IsEnabled() {
+		return 0, 0
+	}
+	return ui.Get().Input().TouchRadius(id)
+}
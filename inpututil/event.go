@@ -0,0 +1,124 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inpututil
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/internal/ui"
+)
+
+// InputEventType represents a type of an input event.
+type InputEventType int
+
+const (
+	// InputEventTypeKeyPress represents that a key is pressed.
+	InputEventTypeKeyPress InputEventType = InputEventType(ui.InputEventTypeKeyDown)
+
+	// InputEventTypeKeyRelease represents that a key is released.
+	InputEventTypeKeyRelease InputEventType = InputEventType(ui.InputEventTypeKeyUp)
+
+	// InputEventTypeMouseButtonPress represents that a mouse button is pressed.
+	InputEventTypeMouseButtonPress InputEventType = InputEventType(ui.InputEventTypeMouseButtonDown)
+
+	// InputEventTypeMouseButtonRelease represents that a mouse button is released.
+	InputEventTypeMouseButtonRelease InputEventType = InputEventType(ui.InputEventTypeMouseButtonUp)
+
+	// InputEventTypeTouchPress represents that a touch starts.
+	InputEventTypeTouchPress InputEventType = InputEventType(ui.InputEventTypeTouchDown)
+
+	// InputEventTypeTouchRelease represents that a touch ends.
+	InputEventTypeTouchRelease InputEventType = InputEventType(ui.InputEventTypeTouchUp)
+
+	// InputEventTypeGamepadButtonPress represents that a gamepad button is pressed.
+	InputEventTypeGamepadButtonPress InputEventType = InputEventType(ui.InputEventTypeGamepadButtonDown)
+
+	// InputEventTypeGamepadButtonRelease represents that a gamepad button is released.
+	InputEventTypeGamepadButtonRelease InputEventType = InputEventType(ui.InputEventTypeGamepadButtonUp)
+)
+
+// InputEvent represents an input event with the time when the event happened.
+type InputEvent struct {
+	// Type is the type of the event.
+	Type InputEventType
+
+	// Key is the key of the event.
+	// Key is valid only when Type is InputEventTypeKeyPress or InputEventTypeKeyRelease.
+	Key ebiten.Key
+
+	// MouseButton is the mouse button of the event.
+	// MouseButton is valid only when Type is InputEventTypeMouseButtonPress or InputEventTypeMouseButtonRelease.
+	MouseButton ebiten.MouseButton
+
+	// TouchID is the touch ID of the event.
+	// TouchID is valid only when Type is InputEventTypeTouchPress or InputEventTypeTouchRelease.
+	TouchID ebiten.TouchID
+
+	// GamepadID is the gamepad ID of the event.
+	// GamepadID is valid only when Type is InputEventTypeGamepadButtonPress or InputEventTypeGamepadButtonRelease.
+	GamepadID ebiten.GamepadID
+
+	// GamepadButton is the gamepad button of the event.
+	// GamepadButton is valid only when Type is InputEventTypeGamepadButtonPress or
+	// InputEventTypeGamepadButtonRelease.
+	GamepadButton ebiten.GamepadButton
+
+	// Timestamp is the time when the event happened.
+	// Timestamp is a monotonic time from an unspecified origin, and is meaningful only to compare with other
+	// events' timestamps. On browsers, Timestamp is based on performance.now().
+	Timestamp time.Duration
+}
+
+var (
+	inputEventsBuf []ui.InputEvent
+	inputEventsM   sync.Mutex
+)
+
+// AppendInputEvents appends the input events that happened since the previous tick to events, and returns the
+// extended buffer.
+// Giving a slice that already has enough capacity works efficiently.
+//
+// The events are in chronological order. The timestamps are useful to know when the inputs happened between ticks,
+// e.g., for rhythm games.
+//
+// Gamepads are polled once per frame, so the timestamps of gamepad events are the times of the polling and a gamepad
+// button pressed and released between two pollings is not reported. On mobiles, key and touch events are also
+// detected by comparing the states between the platform's updates.
+//
+// AppendInputEvents doesn't report any events on the C backend yet.
+//
+// AppendInputEvents must be called from Update. Otherwise, some events might be missed.
+//
+// AppendInputEvents is concurrent-safe.
+func AppendInputEvents(events []InputEvent) []InputEvent {
+	inputEventsM.Lock()
+	defer inputEventsM.Unlock()
+
+	inputEventsBuf = ui.Get().Input().AppendInputEvents(inputEventsBuf[:0])
+	for _, e := range inputEventsBuf {
+		events = append(events, InputEvent{
+			Type:          InputEventType(e.Type),
+			Key:           ebiten.Key(e.Key),
+			MouseButton:   e.MouseButton,
+			TouchID:       e.TouchID,
+			GamepadID:     e.GamepadID,
+			GamepadButton: e.GamepadButton,
+			Timestamp:     e.Timestamp,
+		})
+	}
+	return events
+}
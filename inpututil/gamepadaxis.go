@@ -0,0 +1,129 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inpututil
+
+import (
+	"math"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// GamepadAxisOptions represents options to process gamepad stick values.
+//
+// The processing is radial: the deadzones and the response curve are applied to the magnitude of the stick vector,
+// and the direction of the stick is kept. This avoids the square-shaped deadzones and the distorted diagonals that
+// processing each axis independently would cause.
+type GamepadAxisOptions struct {
+	// Deadzone is the radius of the inner deadzone in [0, 1).
+	// A stick whose magnitude is less than or equal to Deadzone is treated as centered.
+	Deadzone float64
+
+	// OuterDeadzone is the width of the outer deadzone in [0, 1).
+	// A stick whose magnitude is greater than or equal to 1 - OuterDeadzone is treated as fully tilted.
+	OuterDeadzone float64
+
+	// AntiDeadzone is the minimum output magnitude in [0, 1) when the stick is out of the inner deadzone.
+	// AntiDeadzone is useful to cancel a deadzone that a game engine or a game itself applies in addition.
+	AntiDeadzone float64
+
+	// Exponent is the exponent of the response curve applied to the magnitude.
+	// 1 means a linear response, and a value greater than 1 gives a finer control around the center.
+	// The zero value is treated as 1.
+	Exponent float64
+}
+
+var defaultGamepadAxisOptions = GamepadAxisOptions{
+	Deadzone: 0.15,
+	Exponent: 1,
+}
+
+var (
+	gamepadAxisOptions  = defaultGamepadAxisOptions
+	gamepadAxisOptionsM sync.RWMutex
+)
+
+// SetGamepadAxisOptions sets the options used by GamepadAxisProcessed.
+// If options is nil, the default options are used: Deadzone is 0.15, Exponent is 1, and the others are 0.
+//
+// SetGamepadAxisOptions is concurrent-safe.
+func SetGamepadAxisOptions(options *GamepadAxisOptions) {
+	gamepadAxisOptionsM.Lock()
+	defer gamepadAxisOptionsM.Unlock()
+
+	if options == nil {
+		gamepadAxisOptions = defaultGamepadAxisOptions
+		return
+	}
+	gamepadAxisOptions = *options
+}
+
+// GamepadAxisProcessed returns a float value [-1.0 - 1.0] of the given gamepad (id)'s standard axis (axis),
+// processed with the options set by SetGamepadAxisOptions.
+//
+// The other axis of the same stick is also read to process the stick as a two-dimensional vector.
+// For example, the value of StandardGamepadAxisLeftStickHorizontal depends on
+// StandardGamepadAxisLeftStickVertical too.
+//
+// GamepadAxisProcessed returns 0 when the gamepad doesn't have a standard gamepad layout mapping.
+//
+// GamepadAxisProcessed is concurrent-safe.
+func GamepadAxisProcessed(id ebiten.GamepadID, axis ebiten.StandardGamepadAxis) float64 {
+	gamepadAxisOptionsM.RLock()
+	op := gamepadAxisOptions
+	gamepadAxisOptionsM.RUnlock()
+
+	var h, v ebiten.StandardGamepadAxis
+	switch axis {
+	case ebiten.StandardGamepadAxisLeftStickHorizontal, ebiten.StandardGamepadAxisLeftStickVertical:
+		h, v = ebiten.StandardGamepadAxisLeftStickHorizontal, ebiten.StandardGamepadAxisLeftStickVertical
+	case ebiten.StandardGamepadAxisRightStickHorizontal, ebiten.StandardGamepadAxisRightStickVertical:
+		h, v = ebiten.StandardGamepadAxisRightStickHorizontal, ebiten.StandardGamepadAxisRightStickVertical
+	default:
+		return 0
+	}
+
+	x, y := processStick(ebiten.StandardGamepadAxisValue(id, h), ebiten.StandardGamepadAxisValue(id, v), &op)
+	if axis == h {
+		return x
+	}
+	return y
+}
+
+// processStick applies the radial deadzones and the response curve to the stick vector (x, y).
+func processStick(x, y float64, op *GamepadAxisOptions) (float64, float64) {
+	m := math.Hypot(x, y)
+	if m == 0 || m <= op.Deadzone {
+		return 0, 0
+	}
+
+	outer := 1 - op.OuterDeadzone
+	if outer <= op.Deadzone {
+		// The range is empty. Treat the stick as fully tilted.
+		return clampAxis(x / m), clampAxis(y / m)
+	}
+
+	t := (math.Min(m, outer) - op.Deadzone) / (outer - op.Deadzone)
+	if op.Exponent != 0 && op.Exponent != 1 {
+		t = math.Pow(t, op.Exponent)
+	}
+	t = op.AntiDeadzone + (1-op.AntiDeadzone)*t
+
+	return clampAxis(x / m * t), clampAxis(y / m * t)
+}
+
+func clampAxis(v float64) float64 {
+	return math.Min(math.Max(v, -1), 1)
+}
@@ -0,0 +1,140 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inpututil
+
+import (
+	"math"
+	"testing"
+)
+
+func TestProcessStick(t *testing.T) {
+	const d = 1 / math.Sqrt2
+	tests := []struct {
+		name string
+		x, y float64
+		op   GamepadAxisOptions
+		outX float64
+		outY float64
+	}{
+		{
+			name: "centered",
+			op:   GamepadAxisOptions{Deadzone: 0.2},
+		},
+		{
+			name: "in the deadzone",
+			x:    0.1,
+			y:    -0.1,
+			op:   GamepadAxisOptions{Deadzone: 0.2},
+		},
+		{
+			name: "on the deadzone",
+			x:    0.2,
+			op:   GamepadAxisOptions{Deadzone: 0.2},
+		},
+		{
+			name: "linear",
+			x:    0.6,
+			op:   GamepadAxisOptions{Deadzone: 0.2},
+			outX: 0.5,
+		},
+		{
+			name: "no options",
+			x:    0.3,
+			y:    -0.4,
+			outX: 0.3,
+			outY: -0.4,
+		},
+		{
+			name: "fully tilted",
+			y:    -1,
+			op:   GamepadAxisOptions{Deadzone: 0.2},
+			outY: -1,
+		},
+		{
+			name: "outer deadzone",
+			x:    0.9,
+			op:   GamepadAxisOptions{Deadzone: 0.2, OuterDeadzone: 0.2},
+			outX: 1,
+		},
+		{
+			name: "anti-deadzone",
+			x:    0.2,
+			op:   GamepadAxisOptions{AntiDeadzone: 0.5},
+			outX: 0.6,
+		},
+		{
+			name: "exponent",
+			x:    -0.5,
+			op:   GamepadAxisOptions{Exponent: 2},
+			outX: -0.25,
+		},
+		{
+			name: "diagonal keeps the direction",
+			x:    0.6 * d,
+			y:    0.6 * d,
+			op:   GamepadAxisOptions{Deadzone: 0.2},
+			outX: 0.5 * d,
+			outY: 0.5 * d,
+		},
+		{
+			name: "diagonal is out of the deadzone",
+			x:    0.15,
+			y:    0.15,
+			op:   GamepadAxisOptions{Deadzone: 0.2},
+			outX: (math.Hypot(0.15, 0.15) - 0.2) / 0.8 * d,
+			outY: (math.Hypot(0.15, 0.15) - 0.2) / 0.8 * d,
+		},
+		{
+			name: "beyond the unit circle",
+			x:    1,
+			y:    1,
+			outX: d,
+			outY: d,
+		},
+		{
+			name: "empty range",
+			x:    0.3,
+			y:    0.4,
+			op:   GamepadAxisOptions{Deadzone: 0.2, OuterDeadzone: 0.9},
+			outX: 0.6,
+			outY: 0.8,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			x, y := processStick(tc.x, tc.y, &tc.op)
+			if math.Abs(x-tc.outX) > 1e-9 || math.Abs(y-tc.outY) > 1e-9 {
+				t.Errorf("processStick(%f, %f): got: (%f, %f), want: (%f, %f)", tc.x, tc.y, x, y, tc.outX, tc.outY)
+			}
+		})
+	}
+}
+
+func TestSetGamepadAxisOptions(t *testing.T) {
+	defer SetGamepadAxisOptions(nil)
+
+	op := GamepadAxisOptions{Deadzone: 0.3, Exponent: 2}
+	SetGamepadAxisOptions(&op)
+	op.Deadzone = 0.5
+	if got, want := gamepadAxisOptions, (GamepadAxisOptions{Deadzone: 0.3, Exponent: 2}); got != want {
+		t.Errorf("gamepadAxisOptions: got: %v, want: %v", got, want)
+	}
+
+	SetGamepadAxisOptions(nil)
+	if got, want := gamepadAxisOptions, defaultGamepadAxisOptions; got != want {
+		t.Errorf("gamepadAxisOptions: got: %v, want: %v", got, want)
+	}
+}
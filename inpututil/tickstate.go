@@ -0,0 +1,149 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inpututil
+
+import (
+	"bytes"
+	"encoding/gob"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/internal/hooks"
+)
+
+// inputStateSnapshot is a serializable copy of inputState.
+type inputStateSnapshot struct {
+	KeyDurations     []int
+	PrevKeyDurations []int
+
+	MouseButtonDurations     map[ebiten.MouseButton]int
+	PrevMouseButtonDurations map[ebiten.MouseButton]int
+
+	GamepadIDs     []ebiten.GamepadID
+	PrevGamepadIDs []ebiten.GamepadID
+
+	GamepadButtonDurations     map[ebiten.GamepadID][]int
+	PrevGamepadButtonDurations map[ebiten.GamepadID][]int
+
+	StandardGamepadButtonDurations     map[ebiten.GamepadID][]int
+	PrevStandardGamepadButtonDurations map[ebiten.GamepadID][]int
+
+	TouchIDs           []ebiten.TouchID
+	TouchDurations     map[ebiten.TouchID]int
+	PrevTouchDurations map[ebiten.TouchID]int
+}
+
+func init() {
+	// Save and restore the durations so that the functions like IsKeyJustPressed work when the ticks are
+	// resimulated by ebiten.Resimulate.
+	hooks.AppendTickStateHook(hooks.TickStateHook{
+		Save:    theInputState.save,
+		Restore: theInputState.restore,
+	})
+}
+
+func (i *inputState) save() ([]byte, error) {
+	i.m.RLock()
+	s := inputStateSnapshot{
+		KeyDurations:                       append([]int{}, i.keyDurations...),
+		PrevKeyDurations:                   append([]int{}, i.prevKeyDurations...),
+		MouseButtonDurations:               i.mouseButtonDurations,
+		PrevMouseButtonDurations:           i.prevMouseButtonDurations,
+		GamepadButtonDurations:             i.gamepadButtonDurations,
+		PrevGamepadButtonDurations:         i.prevGamepadButtonDurations,
+		StandardGamepadButtonDurations:     i.standardGamepadButtonDurations,
+		PrevStandardGamepadButtonDurations: i.prevStandardGamepadButtonDurations,
+		TouchDurations:                     i.touchDurations,
+		PrevTouchDurations:                 i.prevTouchDurations,
+	}
+	for id := range i.gamepadIDs {
+		s.GamepadIDs = append(s.GamepadIDs, id)
+	}
+	for id := range i.prevGamepadIDs {
+		s.PrevGamepadIDs = append(s.PrevGamepadIDs, id)
+	}
+	for id := range i.touchIDs {
+		s.TouchIDs = append(s.TouchIDs, id)
+	}
+
+	// Encode the state while the lock is held, as the maps are shared.
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(&s)
+	i.m.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (i *inputState) restore(state []byte) error {
+	var s inputStateSnapshot
+	if err := gob.NewDecoder(bytes.NewReader(state)).Decode(&s); err != nil {
+		return err
+	}
+
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	copy(i.keyDurations, s.KeyDurations)
+	copy(i.prevKeyDurations, s.PrevKeyDurations)
+
+	i.mouseButtonDurations = nonNilMouseButtonMap(s.MouseButtonDurations)
+	i.prevMouseButtonDurations = nonNilMouseButtonMap(s.PrevMouseButtonDurations)
+
+	i.gamepadIDs = map[ebiten.GamepadID]struct{}{}
+	for _, id := range s.GamepadIDs {
+		i.gamepadIDs[id] = struct{}{}
+	}
+	i.prevGamepadIDs = map[ebiten.GamepadID]struct{}{}
+	for _, id := range s.PrevGamepadIDs {
+		i.prevGamepadIDs[id] = struct{}{}
+	}
+
+	i.gamepadButtonDurations = nonNilGamepadMap(s.GamepadButtonDurations)
+	i.prevGamepadButtonDurations = nonNilGamepadMap(s.PrevGamepadButtonDurations)
+	i.standardGamepadButtonDurations = nonNilGamepadMap(s.StandardGamepadButtonDurations)
+	i.prevStandardGamepadButtonDurations = nonNilGamepadMap(s.PrevStandardGamepadButtonDurations)
+
+	i.touchIDs = map[ebiten.TouchID]struct{}{}
+	for _, id := range s.TouchIDs {
+		i.touchIDs[id] = struct{}{}
+	}
+	i.touchDurations = nonNilTouchMap(s.TouchDurations)
+	i.prevTouchDurations = nonNilTouchMap(s.PrevTouchDurations)
+	return nil
+}
+
+// gob decodes an empty map as nil. Replace nil maps with empty maps so that the maps are writable.
+
+func nonNilMouseButtonMap(m map[ebiten.MouseButton]int) map[ebiten.MouseButton]int {
+	if m == nil {
+		return map[ebiten.MouseButton]int{}
+	}
+	return m
+}
+
+func nonNilGamepadMap(m map[ebiten.GamepadID][]int) map[ebiten.GamepadID][]int {
+	if m == nil {
+		return map[ebiten.GamepadID][]int{}
+	}
+	return m
+}
+
+func nonNilTouchMap(m map[ebiten.TouchID]int) map[ebiten.TouchID]int {
+	if m == nil {
+		return map[ebiten.TouchID]int{}
+	}
+	return m
+}
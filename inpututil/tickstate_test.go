@@ -0,0 +1,101 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inpututil
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+func TestInputStateSaveAndRestore(t *testing.T) {
+	i := &inputState{
+		keyDurations:     make([]int, ebiten.KeyMax+1),
+		prevKeyDurations: make([]int, ebiten.KeyMax+1),
+
+		mouseButtonDurations:     map[ebiten.MouseButton]int{ebiten.MouseButtonLeft: 2},
+		prevMouseButtonDurations: map[ebiten.MouseButton]int{ebiten.MouseButtonLeft: 1},
+
+		gamepadIDs:     map[ebiten.GamepadID]struct{}{1: {}},
+		prevGamepadIDs: map[ebiten.GamepadID]struct{}{},
+
+		gamepadButtonDurations:     map[ebiten.GamepadID][]int{1: {0, 4}},
+		prevGamepadButtonDurations: map[ebiten.GamepadID][]int{},
+
+		standardGamepadButtonDurations:     map[ebiten.GamepadID][]int{},
+		prevStandardGamepadButtonDurations: map[ebiten.GamepadID][]int{},
+
+		touchIDs:           map[ebiten.TouchID]struct{}{5: {}},
+		touchDurations:     map[ebiten.TouchID]int{5: 7},
+		prevTouchDurations: map[ebiten.TouchID]int{},
+	}
+	i.keyDurations[ebiten.KeyA] = 3
+	i.prevKeyDurations[ebiten.KeyA] = 2
+
+	state, err := i.save()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Advance the states.
+	i.keyDurations[ebiten.KeyA] = 0
+	i.keyDurations[ebiten.KeyB] = 1
+	i.mouseButtonDurations = map[ebiten.MouseButton]int{}
+	i.gamepadIDs = map[ebiten.GamepadID]struct{}{}
+	i.gamepadButtonDurations = map[ebiten.GamepadID][]int{}
+	i.touchIDs = map[ebiten.TouchID]struct{}{6: {}}
+	i.touchDurations = map[ebiten.TouchID]int{6: 1}
+
+	if err := i.restore(state); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := i.keyDurations[ebiten.KeyA], 3; got != want {
+		t.Errorf("keyDurations[KeyA]: got: %d, want: %d", got, want)
+	}
+	if got, want := i.keyDurations[ebiten.KeyB], 0; got != want {
+		t.Errorf("keyDurations[KeyB]: got: %d, want: %d", got, want)
+	}
+	if got, want := i.prevKeyDurations[ebiten.KeyA], 2; got != want {
+		t.Errorf("prevKeyDurations[KeyA]: got: %d, want: %d", got, want)
+	}
+	if got, want := i.mouseButtonDurations[ebiten.MouseButtonLeft], 2; got != want {
+		t.Errorf("mouseButtonDurations[MouseButtonLeft]: got: %d, want: %d", got, want)
+	}
+	if _, ok := i.gamepadIDs[1]; !ok || len(i.gamepadIDs) != 1 {
+		t.Errorf("gamepadIDs: got: %v, want: map[1:{}]", i.gamepadIDs)
+	}
+	if got := i.gamepadButtonDurations[1]; len(got) != 2 || got[1] != 4 {
+		t.Errorf("gamepadButtonDurations[1]: got: %v, want: [0 4]", got)
+	}
+	if _, ok := i.touchIDs[5]; !ok || len(i.touchIDs) != 1 {
+		t.Errorf("touchIDs: got: %v, want: map[5:{}]", i.touchIDs)
+	}
+	if got, want := i.touchDurations[5], 7; got != want || len(i.touchDurations) != 1 {
+		t.Errorf("touchDurations: got: %v, want: map[5:%d]", i.touchDurations, want)
+	}
+
+	// The empty maps must be writable after restoring.
+	i.prevGamepadButtonDurations[2] = nil
+	i.standardGamepadButtonDurations[2] = nil
+	i.prevTouchDurations[2] = 0
+}
+
+func TestInputStateRestoreInvalid(t *testing.T) {
+	i := &inputState{}
+	if err := i.restore([]byte("invalid")); err == nil {
+		t.Errorf("restore with invalid data must return an error")
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !ebitencbackend
+// +build !ebitencbackend
+
+package accessibility
+
+import (
+	"unsafe"
+
+	"golang.org/x/mobile/app"
+)
+
+/*
+#include <jni.h>
+#include <stdlib.h>
+#include <stdint.h>
+
+// Basically same as:
+//
+//     AccessibilityManager m = (AccessibilityManager)getSystemService(Context.ACCESSIBILITY_SERVICE);
+//     if (m.isEnabled()) {
+//       AccessibilityEvent e = AccessibilityEvent.obtain(AccessibilityEvent.TYPE_ANNOUNCEMENT);
+//       e.setPackageName(getPackageName());
+//       e.getText().add(text);
+//       m.sendAccessibilityEvent(e);
+//     }
+//
+static void announce(uintptr_t java_vm, uintptr_t jni_env, uintptr_t ctx, const char* text) {
+  JavaVM* vm = (JavaVM*)java_vm;
+  JNIEnv* env = (JNIEnv*)jni_env;
+  jobject context = (jobject)ctx;
+
+  const jclass android_content_Context = (*env)->FindClass(env, "android/content/Context");
+  const jclass android_view_accessibility_AccessibilityManager = (*env)->FindClass(env, "android/view/accessibility/AccessibilityManager");
+  const jclass android_view_accessibility_AccessibilityEvent = (*env)->FindClass(env, "android/view/accessibility/AccessibilityEvent");
+  const jclass java_util_List = (*env)->FindClass(env, "java/util/List");
+
+  const jobject android_context_Context_ACCESSIBILITY_SERVICE =
+      (*env)->GetStaticObjectField(
+          env, android_content_Context,
+          (*env)->GetStaticFieldID(env, android_content_Context, "ACCESSIBILITY_SERVICE", "Ljava/lang/String;"));
+
+  const jobject manager =
+      (*env)->CallObjectMethod(
+          env, context,
+          (*env)->GetMethodID(env, android_content_Context, "getSystemService", "(Ljava/lang/String;)Ljava/lang/Object;"),
+          android_context_Context_ACCESSIBILITY_SERVICE);
+
+  const jboolean enabled =
+      (*env)->CallBooleanMethod(
+          env, manager,
+          (*env)->GetMethodID(env, android_view_accessibility_AccessibilityManager, "isEnabled", "()Z"));
+
+  if (enabled) {
+    const jint TYPE_ANNOUNCEMENT = 0x00004000;
+    const jobject event =
+        (*env)->CallStaticObjectMethod(
+            env, android_view_accessibility_AccessibilityEvent,
+            (*env)->GetStaticMethodID(env, android_view_accessibility_AccessibilityEvent, "obtain", "(I)Landroid/view/accessibility/AccessibilityEvent;"),
+            TYPE_ANNOUNCEMENT);
+
+    const jobject packageName =
+        (*env)->CallObjectMethod(
+            env, context,
+            (*env)->GetMethodID(env, android_content_Context, "getPackageName", "()Ljava/lang/String;"));
+    (*env)->CallVoidMethod(
+        env, event,
+        (*env)->GetMethodID(env, android_view_accessibility_AccessibilityEvent, "setPackageName", "(Ljava/lang/CharSequence;)V"),
+        packageName);
+
+    const jobject textList =
+        (*env)->CallObjectMethod(
+            env, event,
+            (*env)->GetMethodID(env, android_view_accessibility_AccessibilityEvent, "getText", "()Ljava/util/List;"));
+    const jstring jtext = (*env)->NewStringUTF(env, text);
+    (*env)->CallBooleanMethod(
+        env, textList,
+        (*env)->GetMethodID(env, java_util_List, "add", "(Ljava/lang/Object;)Z"),
+        jtext);
+
+    (*env)->CallVoidMethod(
+        env, manager,
+        (*env)->GetMethodID(env, android_view_accessibility_AccessibilityManager, "sendAccessibilityEvent", "(Landroid/view/accessibility/AccessibilityEvent;)V"),
+        event);
+
+    (*env)->DeleteLocalRef(env, event);
+    (*env)->DeleteLocalRef(env, packageName);
+    (*env)->DeleteLocalRef(env, textList);
+    (*env)->DeleteLocalRef(env, jtext);
+  }
+
+  (*env)->DeleteLocalRef(env, android_content_Context);
+  (*env)->DeleteLocalRef(env, android_view_accessibility_AccessibilityManager);
+  (*env)->DeleteLocalRef(env, android_view_accessibility_AccessibilityEvent);
+  (*env)->DeleteLocalRef(env, java_util_List);
+
+  (*env)->DeleteLocalRef(env, android_context_Context_ACCESSIBILITY_SERVICE);
+  (*env)->DeleteLocalRef(env, manager);
+}
+*/
+import "C"
+
+func Announce(text string) {
+	go func() {
+		_ = app.RunOnJVM(func(vm, env, ctx uintptr) error {
+			ctext := C.CString(text)
+			defer C.free(unsafe.Pointer(ctext))
+			C.announce(C.uintptr_t(vm), C.uintptr_t(env), C.uintptr_t(ctx), ctext)
+			return nil
+		})
+	}()
+}
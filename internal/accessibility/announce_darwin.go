@@ -0,0 +1,50 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build darwin && !ios && !ebitencbackend
+// +build darwin,!ios,!ebitencbackend
+
+package accessibility
+
+// #cgo CFLAGS: -x objective-c
+// #cgo LDFLAGS: -framework AppKit
+//
+// #import <AppKit/AppKit.h>
+// #include <stdlib.h>
+//
+// // announce takes the ownership of text, which must be allocated by malloc.
+// static void announce(char* text) {
+//   dispatch_async(dispatch_get_main_queue(), ^{
+//     @autoreleasepool {
+//       NSString* str = [NSString stringWithUTF8String:text];
+//       free(text);
+//       if (!str) {
+//         return;
+//       }
+//       id element = [NSApp mainWindow];
+//       if (!element) {
+//         element = NSApp;
+//       }
+//       NSAccessibilityPostNotificationWithUserInfo(element, NSAccessibilityAnnouncementRequestedNotification, @{
+//         NSAccessibilityAnnouncementKey: str,
+//         NSAccessibilityPriorityKey: @(NSAccessibilityPriorityHigh),
+//       });
+//     }
+//   });
+// }
+import "C"
+
+func Announce(text string) {
+	C.announce(C.CString(text))
+}
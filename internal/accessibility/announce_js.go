@@ -0,0 +1,53 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accessibility
+
+import (
+	"syscall/js"
+)
+
+// liveRegion is an invisible ARIA live region to make screen readers read text.
+var liveRegion js.Value
+
+func Announce(text string) {
+	document := js.Global().Get("document")
+	if !document.Truthy() {
+		return
+	}
+
+	if !liveRegion.Truthy() {
+		liveRegion = document.Call("createElement", "div")
+		liveRegion.Call("setAttribute", "aria-live", "assertive")
+		liveRegion.Call("setAttribute", "aria-atomic", "true")
+		style := liveRegion.Get("style")
+		style.Set("position", "absolute")
+		style.Set("width", "1px")
+		style.Set("height", "1px")
+		style.Set("overflow", "hidden")
+		style.Set("clip", "rect(0 0 0 0)")
+		style.Set("whiteSpace", "nowrap")
+		document.Get("body").Call("appendChild", liveRegion)
+	}
+
+	// Clear the content once so that the same text is announced again.
+	liveRegion.Set("textContent", "")
+	var f js.Func
+	f = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		liveRegion.Set("textContent", text)
+		f.Release()
+		return nil
+	})
+	js.Global().Call("setTimeout", f, 100)
+}
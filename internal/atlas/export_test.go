@@ -55,3 +55,21 @@ func (i *Image) EnsureIsolatedForTesting() {
 func ResolveDeferredForTesting() {
 	resolveDeferred()
 }
+
+func (i *Image) MoveToAnotherAtlasForTesting() error {
+	backendsM.Lock()
+	defer backendsM.Unlock()
+	return i.moveToAnotherAtlas()
+}
+
+func CompactForTesting() error {
+	backendsM.Lock()
+	defer backendsM.Unlock()
+	requestCompaction()
+	for len(backendsToCompact) > 0 {
+		if err := compactAtlases(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
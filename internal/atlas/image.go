@@ -17,10 +17,13 @@ package atlas
 import (
 	"fmt"
 	"image"
+	"io"
 	"runtime"
+	"sort"
 	"sync"
 
 	"github.com/hajimehoshi/ebiten/v2/internal/affine"
+	"github.com/hajimehoshi/ebiten/v2/internal/frametiming"
 	"github.com/hajimehoshi/ebiten/v2/internal/graphics"
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
 	"github.com/hajimehoshi/ebiten/v2/internal/packing"
@@ -136,6 +139,80 @@ func putImagesOnAtlas() error {
 	return nil
 }
 
+// compactionUsedRatioThreshold is the used ratio of an atlas under which the atlas is compacted.
+const compactionUsedRatioThreshold = 0.5
+
+// Compact requests to compact the atlases in order to reduce their fragmentation.
+//
+// The sparse atlases are compacted one by one in the following frames, by moving their images to the other atlases.
+// An atlas is disposed when all of its images are moved.
+//
+// Compact is concurrent-safe.
+func Compact() {
+	deferredM.Lock()
+	deferred = append(deferred, requestCompaction)
+	deferredM.Unlock()
+}
+
+// requestCompaction must be called with backendsM locked.
+func requestCompaction() {
+	backendsToCompact = backendsToCompact[:0]
+	for _, b := range theBackends {
+		if b.usedRatio() >= compactionUsedRatioThreshold {
+			continue
+		}
+		// A sole atlas of the minimum size cannot become smaller.
+		if len(theBackends) == 1 && b.page.Size() <= minSize {
+			continue
+		}
+		backendsToCompact = append(backendsToCompact, b)
+	}
+	// Compact sparser atlases first, as their images are more likely to fit into the other atlases.
+	sort.SliceStable(backendsToCompact, func(a, b int) bool {
+		return backendsToCompact[a].usedRatio() < backendsToCompact[b].usedRatio()
+	})
+}
+
+// compactAtlases compacts at most one atlas requested by Compact.
+func compactAtlases() error {
+	for len(backendsToCompact) > 0 {
+		b := backendsToCompact[0]
+		backendsToCompact = backendsToCompact[1:]
+
+		// The atlas might be disposed or used more since the request.
+		if !b.isAlive() || b.usedRatio() >= compactionUsedRatioThreshold {
+			continue
+		}
+
+		imgs := make([]*Image, 0, len(b.images))
+		for img := range b.images {
+			imgs = append(imgs, img)
+		}
+		// Move bigger images first for better packing.
+		sort.Slice(imgs, func(j, k int) bool {
+			ia, ib := imgs[j], imgs[k]
+			if sa, sb := ia.width*ia.height, ib.width*ib.height; sa != sb {
+				return sa > sb
+			}
+			xa, ya, _, _ := ia.node.Region()
+			xb, yb, _, _ := ib.node.Region()
+			if ya != yb {
+				return ya < yb
+			}
+			return xa < xb
+		})
+		for _, img := range imgs {
+			if err := img.moveToAnotherAtlas(); err != nil {
+				return err
+			}
+		}
+
+		// Compact only one atlas in one frame to avoid a long pause.
+		return nil
+	}
+	return nil
+}
+
 type backend struct {
 	// restorable is an atlas on which there might be multiple images.
 	restorable *restorable.Image
@@ -143,6 +220,25 @@ type backend struct {
 	// page is an atlas map. Each part is called a node.
 	// If page is nil, the backend's image is isolated and not on an atlas.
 	page *packing.Page
+
+	// images is a set of the images on the atlas.
+	// images is nil if page is nil.
+	images map[*Image]struct{}
+}
+
+// usedRatio returns how much the atlas is used in [0, 1].
+func (b *backend) usedRatio() float64 {
+	s := b.page.Size()
+	return float64(b.page.UsedArea()) / float64(s*s)
+}
+
+func (b *backend) isAlive() bool {
+	for _, bb := range theBackends {
+		if bb == b {
+			return true
+		}
+	}
+	return false
 }
 
 func (b *backend) tryAlloc(width, height int) (*packing.Node, bool) {
@@ -169,6 +265,7 @@ func (b *backend) tryAlloc(width, height int) (*packing.Node, bool) {
 
 	s := b.page.Size()
 	b.restorable = b.restorable.Extend(s, s)
+	frametiming.AddEvent(frametiming.EventAtlasGrowth)
 
 	if n == nil {
 		panic("atlas: Alloc result must not be nil at TryAlloc")
@@ -187,6 +284,9 @@ var (
 
 	imagesToPutOnAtlas = map[*Image]struct{}{}
 
+	// backendsToCompact is a queue of the atlases to be compacted.
+	backendsToCompact []*backend
+
 	deferred []func()
 
 	// deferredM is a mutext for the slice operations. This must not be used for other usages.
@@ -214,6 +314,19 @@ type Image struct {
 	volatile    bool
 	screen      bool
 
+	// compressed indicates whether the image is created from a compressed texture.
+	// A compressed image is never on an atlas and doesn't have its padding.
+	compressed       bool
+	compressedFormat graphicsdriver.CompressedTextureFormat
+
+	// compressedData is the compressed texture data until the image is allocated.
+	compressedData []byte
+
+	// native indicates whether the image wraps a texture created outside of Ebiten.
+	// A native image is never on an atlas and doesn't have its padding.
+	native        bool
+	nativeTexture graphicsdriver.NativeTexture
+
 	backend *backend
 
 	node *packing.Node
@@ -242,6 +355,11 @@ func (i *Image) moveTo(dst *Image) {
 	dst.dispose(false)
 	*dst = *i
 
+	if dst.isOnAtlas() {
+		delete(dst.backend.images, i)
+		dst.backend.images[dst] = struct{}{}
+	}
+
 	// i is no longer available but Dispose must not be called
 	// since i and dst have the same values like node.
 	runtime.SetFinalizer(i, nil)
@@ -280,10 +398,10 @@ func (i *Image) ensureIsolated() {
 	newImg := restorable.NewImage(w, h)
 	newImg.SetVolatile(i.volatile)
 	vs := []float32{
-		dx0, dy0, sx0, sy0, 1, 1, 1, 1,
-		dx1, dy0, sx1, sy0, 1, 1, 1, 1,
-		dx0, dy1, sx0, sy1, 1, 1, 1, 1,
-		dx1, dy1, sx1, sy1, 1, 1, 1, 1,
+		dx0, dy0, sx0, sy0, 1, 1, 1, 1, 0,
+		dx1, dy0, sx1, sy0, 1, 1, 1, 1, 0,
+		dx0, dy1, sx0, sy1, 1, 1, 1, 1, 0,
+		dx1, dy1, sx1, sy1, 1, 1, 1, 1, 0,
 	}
 	is := graphics.QuadIndices()
 	srcs := [graphics.ShaderImageNum]*restorable.Image{i.backend.restorable}
@@ -294,7 +412,7 @@ func (i *Image) ensureIsolated() {
 		Width:  float32(w - 2*paddingSize),
 		Height: float32(h - 2*paddingSize),
 	}
-	newImg.DrawTriangles(srcs, offsets, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dstRegion, graphicsdriver.Region{}, nil, nil, false)
+	newImg.DrawTriangles(srcs, offsets, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dstRegion, graphicsdriver.Region{}, nil, nil, false, false)
 
 	i.dispose(false)
 	i.backend = &backend{
@@ -320,28 +438,60 @@ func (i *Image) putOnAtlas() error {
 
 	newI := NewImage(i.width, i.height)
 	newI.SetVolatile(i.volatile)
+	if err := newI.copyFrom(i); err != nil {
+		return err
+	}
+
+	newI.moveTo(i)
+	i.usedAsSourceCount = 0
+	return nil
+}
+
+// moveToAnotherAtlas moves the image on an atlas to another atlas.
+func (i *Image) moveToAnotherAtlas() error {
+	if !i.isOnAtlas() {
+		panic("atlas: moveToAnotherAtlas cannot be called on an image not on an atlas")
+	}
+
+	newI := NewImage(i.width, i.height)
+	runtime.SetFinalizer(newI, (*Image).MarkDisposed)
+	newI.allocateOnAtlas(i.backend)
+	if err := newI.copyFrom(i); err != nil {
+		newI.dispose(true)
+		return err
+	}
 
+	isolatedCount := i.isolatedCount
+	newI.moveTo(i)
+	i.isolatedCount = isolatedCount
+	return nil
+}
+
+// copyFrom copies the content of src to i. i must be a new image of the same size as src.
+//
+// If i is not allocated yet, i is allocated on an atlas.
+func (i *Image) copyFrom(src *Image) error {
 	if restorable.NeedsRestoring() {
 		// If the underlying graphics driver requires restoring from the context lost, the pixel data is
 		// needed. An image on an atlas must have its complete pixel data in this case.
-		pixels := make([]byte, 4*i.width*i.height)
-		for y := 0; y < i.height; y++ {
-			for x := 0; x < i.width; x++ {
-				r, g, b, a, err := i.at(x+paddingSize, y+paddingSize)
+		pixels := make([]byte, 4*src.width*src.height)
+		for y := 0; y < src.height; y++ {
+			for x := 0; x < src.width; x++ {
+				r, g, b, a, err := src.at(x+paddingSize, y+paddingSize)
 				if err != nil {
 					return err
 				}
-				pixels[4*(i.width*y+x)] = r
-				pixels[4*(i.width*y+x)+1] = g
-				pixels[4*(i.width*y+x)+2] = b
-				pixels[4*(i.width*y+x)+3] = a
+				pixels[4*(src.width*y+x)] = r
+				pixels[4*(src.width*y+x)+1] = g
+				pixels[4*(src.width*y+x)+2] = b
+				pixels[4*(src.width*y+x)+3] = a
 			}
 		}
-		newI.replacePixels(pixels)
+		i.replacePixels(pixels)
 	} else {
 		// If the underlying graphics driver doesn't require restoring from the context lost, just a regular
 		// rendering works.
-		w, h := float32(i.width), float32(i.height)
+		w, h := float32(src.width), float32(src.height)
 		vs := graphics.QuadVertices(0, 0, w, h, 1, 0, 0, 1, 0, 0, 1, 1, 1, 1)
 		is := graphics.QuadIndices()
 		dr := graphicsdriver.Region{
@@ -350,20 +500,26 @@ func (i *Image) putOnAtlas() error {
 			Width:  w,
 			Height: h,
 		}
-		newI.drawTriangles([graphics.ShaderImageNum]*Image{i}, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, false, true)
+		i.drawTriangles([graphics.ShaderImageNum]*Image{src}, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, false, false, true)
 	}
 
-	newI.moveTo(i)
-	i.usedAsSourceCount = 0
 	return nil
 }
 
+// padding returns the size of the padding around the image.
+func (i *Image) padding() int {
+	if i.compressed || i.native {
+		return 0
+	}
+	return paddingSize
+}
+
 func (i *Image) regionWithPadding() (x, y, width, height int) {
 	if i.backend == nil {
 		panic("atlas: backend must not be nil: not allocated yet?")
 	}
 	if !i.isOnAtlas() {
-		return 0, 0, i.width + 2*paddingSize, i.height + 2*paddingSize
+		return 0, 0, i.width + 2*i.padding(), i.height + 2*i.padding()
 	}
 	return i.node.Region()
 }
@@ -398,16 +554,23 @@ func (i *Image) processSrc(src *Image) {
 //   5: Color G
 //   6: Color B
 //   7: Color Y
-func (i *Image) DrawTriangles(srcs [graphics.ShaderImageNum]*Image, vertices []float32, indices []uint16, colorm affine.ColorM, mode graphicsdriver.CompositeMode, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, subimageOffsets [graphics.ShaderImageNum - 1][2]float32, shader *Shader, uniforms []graphicsdriver.Uniform, evenOdd bool) {
+//   8: Depth [0.0-1.0] (used only when depthTest is true)
+func (i *Image) DrawTriangles(srcs [graphics.ShaderImageNum]*Image, vertices []float32, indices []uint16, colorm affine.ColorM, mode graphicsdriver.CompositeMode, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, subimageOffsets [graphics.ShaderImageNum - 1][2]float32, shader *Shader, uniforms []graphicsdriver.Uniform, evenOdd bool, depthTest bool) {
 	backendsM.Lock()
 	defer backendsM.Unlock()
-	i.drawTriangles(srcs, vertices, indices, colorm, mode, filter, address, dstRegion, srcRegion, subimageOffsets, shader, uniforms, evenOdd, false)
+	i.drawTriangles(srcs, vertices, indices, colorm, mode, filter, address, dstRegion, srcRegion, subimageOffsets, shader, uniforms, evenOdd, depthTest, false)
 }
 
-func (i *Image) drawTriangles(srcs [graphics.ShaderImageNum]*Image, vertices []float32, indices []uint16, colorm affine.ColorM, mode graphicsdriver.CompositeMode, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, subimageOffsets [graphics.ShaderImageNum - 1][2]float32, shader *Shader, uniforms []graphicsdriver.Uniform, evenOdd bool, keepOnAtlas bool) {
+func (i *Image) drawTriangles(srcs [graphics.ShaderImageNum]*Image, vertices []float32, indices []uint16, colorm affine.ColorM, mode graphicsdriver.CompositeMode, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, subimageOffsets [graphics.ShaderImageNum - 1][2]float32, shader *Shader, uniforms []graphicsdriver.Uniform, evenOdd bool, depthTest bool, keepOnAtlas bool) {
 	if i.disposed {
 		panic("atlas: the drawing target image must not be disposed (DrawTriangles)")
 	}
+	if i.compressed {
+		panic("atlas: a compressed image cannot be a rendering destination (DrawTriangles)")
+	}
+	if i.native {
+		panic("atlas: a native image cannot be a rendering destination (DrawTriangles)")
+	}
 	if keepOnAtlas {
 		if i.backend == nil {
 			i.allocate(true)
@@ -465,11 +628,22 @@ func (i *Image) drawTriangles(srcs [graphics.ShaderImageNum]*Image, vertices []f
 	dstRegion.X += dx
 	dstRegion.Y += dy
 
+	// The default shader with the uniform variables transforms the destination positions on GPU.
+	// Add the offset to the translation instead of the vertices, or the offset would be transformed too.
+	if shader == nil && len(uniforms) > 0 && (dx != 0 || dy != 0) {
+		us := make([]graphicsdriver.Uniform, len(uniforms))
+		copy(us, uniforms)
+		m := uniforms[graphics.DefaultShaderGeoMUniformVariableIndex].Float32s
+		us[graphics.DefaultShaderGeoMUniformVariableIndex].Float32s = []float32{m[0], m[1], m[2], m[3], m[4] + dx, m[5] + dy}
+		uniforms = us
+		dx, dy = 0, 0
+	}
+
 	var oxf, oyf float32
 	if srcs[0] != nil {
 		ox, oy, _, _ := srcs[0].regionWithPadding()
-		ox += paddingSize
-		oy += paddingSize
+		ox += srcs[0].padding()
+		oy += srcs[0].padding()
 		oxf, oyf = float32(ox), float32(oy)
 		n := len(vertices)
 		for i := 0; i < n; i += graphics.VertexFloatNum {
@@ -513,8 +687,8 @@ func (i *Image) drawTriangles(srcs [graphics.ShaderImageNum]*Image, vertices []f
 				continue
 			}
 			ox, oy, _, _ := src.regionWithPadding()
-			offsets[i][0] = float32(ox) + paddingSize - oxf + subimageOffset[0]
-			offsets[i][1] = float32(oy) + paddingSize - oyf + subimageOffset[1]
+			offsets[i][0] = float32(ox+src.padding()) - oxf + subimageOffset[0]
+			offsets[i][1] = float32(oy+src.padding()) - oyf + subimageOffset[1]
 		}
 		s = shader.shader
 		for i, src := range srcs {
@@ -525,7 +699,7 @@ func (i *Image) drawTriangles(srcs [graphics.ShaderImageNum]*Image, vertices []f
 		}
 	}
 
-	i.backend.restorable.DrawTriangles(imgs, offsets, vertices, indices, colorm, mode, filter, address, dstRegion, srcRegion, s, uniforms, evenOdd)
+	i.backend.restorable.DrawTriangles(imgs, offsets, vertices, indices, colorm, mode, filter, address, dstRegion, srcRegion, s, uniforms, evenOdd, depthTest)
 
 	for _, src := range srcs {
 		if src == nil {
@@ -538,6 +712,80 @@ func (i *Image) drawTriangles(srcs [graphics.ShaderImageNum]*Image, vertices []f
 	}
 }
 
+// CopyPixels copies the region (srcX, srcY)-(srcX+width, srcY+height) of src to (dstX, dstY) of the image as it is.
+//
+// The region must be in the bounds of both images.
+func (i *Image) CopyPixels(src *Image, dstX, dstY, srcX, srcY, width, height int) {
+	backendsM.Lock()
+	defer backendsM.Unlock()
+
+	if i.disposed {
+		panic("atlas: the copying target image must not be disposed (CopyPixels)")
+	}
+	if i.compressed || i.native || i.screen {
+		panic("atlas: a compressed, native, or screen image cannot be a copying destination (CopyPixels)")
+	}
+	if src.compressed || src.native || src.screen {
+		panic("atlas: a compressed, native, or screen image cannot be a copying source (CopyPixels)")
+	}
+	i.ensureIsolated()
+	i.processSrc(src)
+
+	dx, dy, _, _ := i.regionWithPadding()
+	sx, sy, _, _ := src.regionWithPadding()
+	dx += i.padding()
+	dy += i.padding()
+	sx += src.padding()
+	sy += src.padding()
+	i.backend.restorable.CopyPixels(src.backend.restorable, dx+dstX, dy+dstY, sx+srcX, sy+srcY, width, height)
+
+	if !src.isOnAtlas() && src.canBePutOnAtlas() {
+		// src might already registered, but assiging it again is not harmful.
+		imagesToPutOnAtlas[src] = struct{}{}
+	}
+}
+
+// FillRect replaces the pixels of the region (x, y)-(x+width, y+height) of the image with the given premultiplied
+// color.
+//
+// The region must be in the bounds of the image.
+func (i *Image) FillRect(x, y, width, height int, red, green, blue, alpha float32) {
+	backendsM.Lock()
+	defer backendsM.Unlock()
+
+	if i.disposed {
+		panic("atlas: the filling target image must not be disposed (FillRect)")
+	}
+	if i.compressed || i.native || i.screen {
+		panic("atlas: a compressed, native, or screen image cannot be filled (FillRect)")
+	}
+	i.ensureIsolated()
+
+	dx, dy, _, _ := i.regionWithPadding()
+	dx += i.padding()
+	dy += i.padding()
+	i.backend.restorable.FillRect(dx+x, dy+y, width, height, red, green, blue, alpha)
+}
+
+// ClearDepth resets the depth buffer of the image.
+//
+// An image with a depth buffer is always isolated from atlases, so ClearDepth does nothing for an image on an atlas.
+func (i *Image) ClearDepth() {
+	backendsM.Lock()
+	defer backendsM.Unlock()
+
+	if i.disposed {
+		panic("atlas: the image must not be disposed at ClearDepth")
+	}
+	if i.backend == nil || i.isOnAtlas() {
+		return
+	}
+	if i.compressed || i.native {
+		return
+	}
+	i.backend.restorable.ClearDepth()
+}
+
 func (i *Image) ReplacePixels(pix []byte) {
 	backendsM.Lock()
 	defer backendsM.Unlock()
@@ -548,6 +796,12 @@ func (i *Image) replacePixels(pix []byte) {
 	if i.disposed {
 		panic("atlas: the image must not be disposed at replacePixels")
 	}
+	if i.compressed {
+		panic("atlas: the pixels of a compressed image cannot be replaced")
+	}
+	if i.native {
+		panic("atlas: the pixels of a native image cannot be replaced")
+	}
 
 	i.resetUsedAsSourceCount()
 
@@ -602,8 +856,8 @@ func (img *Image) Pixels(x, y, width, height int) ([]byte, error) {
 	backendsM.Lock()
 	defer backendsM.Unlock()
 
-	x += paddingSize
-	y += paddingSize
+	x += img.padding()
+	y += img.padding()
 
 	bs := make([]byte, 4*width*height)
 	idx := 0
@@ -677,6 +931,7 @@ func (i *Image) dispose(markDisposed bool) {
 		return
 	}
 
+	delete(i.backend.images, i)
 	i.backend.page.Free(i.node)
 	if !i.backend.page.IsEmpty() {
 		// As this part can be reused, this should be cleared explicitly.
@@ -734,6 +989,12 @@ func (i *Image) canBePutOnAtlas() bool {
 	if i.screen {
 		return false
 	}
+	if i.compressed {
+		return false
+	}
+	if i.native {
+		return false
+	}
 	return i.width+2*paddingSize <= maxSize && i.height+2*paddingSize <= maxSize
 }
 
@@ -752,6 +1013,23 @@ func (i *Image) allocate(putOnAtlas bool) {
 		return
 	}
 
+	if i.compressed {
+		// A compressed image doesn't have a padding either.
+		i.backend = &backend{
+			restorable: restorable.NewCompressedImage(i.width, i.height, i.compressedFormat, i.compressedData),
+		}
+		i.compressedData = nil
+		return
+	}
+
+	if i.native {
+		// A native image doesn't have a padding either.
+		i.backend = &backend{
+			restorable: restorable.NewNativeImage(i.width, i.height, i.nativeTexture),
+		}
+		return
+	}
+
 	if !putOnAtlas || !i.canBePutOnAtlas() {
 		i.backend = &backend{
 			restorable: restorable.NewImage(i.width+2*paddingSize, i.height+2*paddingSize),
@@ -760,10 +1038,19 @@ func (i *Image) allocate(putOnAtlas bool) {
 		return
 	}
 
+	i.allocateOnAtlas(nil)
+}
+
+// allocateOnAtlas allocates the image on an atlas except for exclude.
+func (i *Image) allocateOnAtlas(exclude *backend) {
 	for _, b := range theBackends {
+		if b == exclude {
+			continue
+		}
 		if n, ok := b.tryAlloc(i.width+2*paddingSize, i.height+2*paddingSize); ok {
 			i.backend = b
 			i.node = n
+			b.images[i] = struct{}{}
 			return
 		}
 	}
@@ -778,9 +1065,11 @@ func (i *Image) allocate(putOnAtlas bool) {
 	b := &backend{
 		restorable: restorable.NewImage(size, size),
 		page:       packing.NewPage(size, maxSize),
+		images:     map[*Image]struct{}{},
 	}
 	b.restorable.SetVolatile(i.volatile)
 	theBackends = append(theBackends, b)
+	frametiming.AddEvent(frametiming.EventAtlasGrowth)
 
 	n := b.page.Alloc(i.width+2*paddingSize, i.height+2*paddingSize)
 	if n == nil {
@@ -788,13 +1077,53 @@ func (i *Image) allocate(putOnAtlas bool) {
 	}
 	i.backend = b
 	i.node = n
+	b.images[i] = struct{}{}
 }
 
-func (i *Image) DumpScreenshot(path string, blackbg bool) error {
+// DumpHistory writes the draw-triangles history of the backend of the image to w.
+func (i *Image) DumpHistory(w io.Writer) error {
 	backendsM.Lock()
 	defer backendsM.Unlock()
 
-	return i.backend.restorable.Dump(path, blackbg, image.Rect(paddingSize, paddingSize, paddingSize+i.width, paddingSize+i.height))
+	if i.backend == nil {
+		_, err := fmt.Fprintln(w, "the image is not allocated yet")
+		return err
+	}
+	x, y, width, height := i.regionWithPadding()
+	p := i.padding()
+	return i.backend.restorable.DumpHistory(w, image.Rect(x+p, y+p, x+width-p, y+height-p))
+}
+
+// NewCompressedImage creates an image from the given compressed texture data.
+//
+// A compressed image can be used only as a rendering source.
+func NewCompressedImage(width, height int, format graphicsdriver.CompressedTextureFormat, data []byte) *Image {
+	// Actual allocation is done lazily.
+	return &Image{
+		width:            width,
+		height:           height,
+		compressed:       true,
+		compressedFormat: format,
+		compressedData:   data,
+	}
+}
+
+// NewNativeImage creates an image wrapping the given native texture.
+//
+// A native image can be used only as a rendering source.
+func NewNativeImage(width, height int, texture graphicsdriver.NativeTexture) *Image {
+	// Actual allocation is done lazily.
+	return &Image{
+		width:         width,
+		height:        height,
+		native:        true,
+		nativeTexture: texture,
+	}
+}
+
+// IsCompressedTextureFormatSupported reports whether the given compressed texture format is supported.
+func IsCompressedTextureFormatSupported(format graphicsdriver.CompressedTextureFormat) bool {
+	return restorable.IsCompressedTextureFormatSupported(format)
 }
 
 func NewScreenFramebufferImage(width, height int) *Image {
@@ -807,10 +1136,67 @@ func NewScreenFramebufferImage(width, height int) *Image {
 	return i
 }
 
+// Stats represents statistics of the internal textures.
+type Stats struct {
+	// TextureCount is the number of the textures except for the screen.
+	TextureCount int
+
+	// TextureBytes is the estimated memory usage of the textures in bytes.
+	TextureBytes int64
+
+	// AtlasCount is the number of the textures used as atlases.
+	AtlasCount int
+
+	// AtlasPixels is the total area of the atlases in pixels.
+	AtlasPixels int64
+
+	// AtlasUsedPixels is the total area of the allocated regions on the atlases in pixels, including paddings.
+	AtlasUsedPixels int64
+
+	// AllocatedTexturesInLastFrame is the number of the textures allocated in the last frame.
+	AllocatedTexturesInLastFrame int
+}
+
+var (
+	theStats  Stats
+	theStatsM sync.Mutex
+
+	allocatedTextureCountAtFrameEnd int
+)
+
+// ReadStats returns the statistics of the internal textures at the end of the last frame.
+//
+// ReadStats is concurrent-safe.
+func ReadStats() Stats {
+	theStatsM.Lock()
+	defer theStatsM.Unlock()
+	return theStats
+}
+
+// updateStats must be called with backendsM locked.
+func updateStats() {
+	var s Stats
+	var allocated int
+	s.TextureCount, s.TextureBytes, allocated = restorable.TextureStats()
+	for _, b := range theBackends {
+		size := int64(b.page.Size())
+		s.AtlasCount++
+		s.AtlasPixels += size * size
+		s.AtlasUsedPixels += int64(b.page.UsedArea())
+	}
+	s.AllocatedTexturesInLastFrame = allocated - allocatedTextureCountAtFrameEnd
+	allocatedTextureCountAtFrameEnd = allocated
+
+	theStatsM.Lock()
+	theStats = s
+	theStatsM.Unlock()
+}
+
 func EndFrame() error {
 	backendsM.Lock()
 
 	theTemporaryPixels.resetAtFrameEnd()
+	updateStats()
 
 	return restorable.ResolveStaleImages()
 }
@@ -838,10 +1224,26 @@ func BeginFrame() error {
 	if err := putImagesOnAtlas(); err != nil {
 		return err
 	}
+	if err := compactAtlases(); err != nil {
+		return err
+	}
 
 	return restorable.RestoreIfNeeded()
 }
 
+// MaxImageSize returns the maximum width and height of an image.
+//
+// MaxImageSize returns 0 before the graphics driver is initialized at the first BeginFrame.
+func MaxImageSize() int {
+	backendsM.Lock()
+	defer backendsM.Unlock()
+
+	if maxSize == 0 {
+		return 0
+	}
+	return maxSize - 2*paddingSize
+}
+
 func DumpImages(dir string) error {
 	backendsM.Lock()
 	defer backendsM.Unlock()
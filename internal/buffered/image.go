@@ -17,6 +17,7 @@ package buffered
 import (
 	"fmt"
 	"image"
+	"io"
 
 	"github.com/hajimehoshi/ebiten/v2/internal/affine"
 	"github.com/hajimehoshi/ebiten/v2/internal/atlas"
@@ -30,6 +31,9 @@ type Image struct {
 	width  int
 	height int
 
+	// native indicates whether the image wraps a native texture, whose content can be changed outside of Ebiten.
+	native bool
+
 	pixels               []byte
 	needsToResolvePixels bool
 }
@@ -110,6 +114,59 @@ func (i *Image) initializeAsScreenFramebuffer(width, height int) {
 	i.height = height
 }
 
+// NewCompressedImage creates an image from the given compressed texture data.
+func NewCompressedImage(width, height int, format graphicsdriver.CompressedTextureFormat, data []byte) *Image {
+	i := &Image{}
+	i.initializeAsCompressed(width, height, format, data)
+	return i
+}
+
+func (i *Image) initializeAsCompressed(width, height int, format graphicsdriver.CompressedTextureFormat, data []byte) {
+	if maybeCanAddDelayedCommand() {
+		if tryAddDelayedCommand(func() error {
+			i.initializeAsCompressed(width, height, format, data)
+			return nil
+		}) {
+			return
+		}
+	}
+
+	i.img = atlas.NewCompressedImage(width, height, format, data)
+	i.width = width
+	i.height = height
+}
+
+// NewNativeImage creates an image wrapping the given native texture.
+func NewNativeImage(width, height int, texture graphicsdriver.NativeTexture) *Image {
+	i := &Image{}
+	i.initializeAsNative(width, height, texture)
+	return i
+}
+
+func (i *Image) initializeAsNative(width, height int, texture graphicsdriver.NativeTexture) {
+	if maybeCanAddDelayedCommand() {
+		if tryAddDelayedCommand(func() error {
+			i.initializeAsNative(width, height, texture)
+			return nil
+		}) {
+			return
+		}
+	}
+
+	i.img = atlas.NewNativeImage(width, height, texture)
+	i.width = width
+	i.height = height
+	i.native = true
+}
+
+// IsCompressedTextureFormatSupported reports whether the given compressed texture format is supported.
+//
+// IsCompressedTextureFormatSupported is available only after the graphics driver is initialized.
+func IsCompressedTextureFormatSupported(format graphicsdriver.CompressedTextureFormat) bool {
+	checkDelayedCommandsFlushed("IsCompressedTextureFormatSupported")
+	return atlas.IsCompressedTextureFormatSupported(format)
+}
+
 func (i *Image) invalidatePendingPixels() {
 	i.pixels = nil
 	i.needsToResolvePixels = false
@@ -147,7 +204,8 @@ func (img *Image) Pixels(x, y, width, height int) (pix []byte, err error) {
 
 	pix = make([]byte, 4*width*height)
 
-	if img.pixels == nil {
+	// The pixels of a native image cannot be cached as they might be changed outside of Ebiten.
+	if img.pixels == nil || img.native {
 		pix, err := img.img.Pixels(0, 0, img.width, img.height)
 		if err != nil {
 			return nil, err
@@ -161,9 +219,9 @@ func (img *Image) Pixels(x, y, width, height int) (pix []byte, err error) {
 	return pix, nil
 }
 
-func (i *Image) DumpScreenshot(name string, blackbg bool) error {
-	checkDelayedCommandsFlushed("Dump")
-	return i.img.DumpScreenshot(name, blackbg)
+func (i *Image) DumpHistory(w io.Writer) error {
+	checkDelayedCommandsFlushed("DumpHistory")
+	return i.img.DumpHistory(w)
 }
 
 func (i *Image) ReplacePixels(pix []byte, x, y, width, height int) error {
@@ -214,7 +272,7 @@ func (i *Image) replacePendingPixels(pix []byte, x, y, width, height int) {
 // DrawTriangles draws the src image with the given vertices.
 //
 // Copying vertices and indices is the caller's responsibility.
-func (i *Image) DrawTriangles(srcs [graphics.ShaderImageNum]*Image, vertices []float32, indices []uint16, colorm affine.ColorM, mode graphicsdriver.CompositeMode, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, subimageOffsets [graphics.ShaderImageNum - 1][2]float32, shader *Shader, uniforms []graphicsdriver.Uniform, evenOdd bool) {
+func (i *Image) DrawTriangles(srcs [graphics.ShaderImageNum]*Image, vertices []float32, indices []uint16, colorm affine.ColorM, mode graphicsdriver.CompositeMode, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, subimageOffsets [graphics.ShaderImageNum - 1][2]float32, shader *Shader, uniforms []graphicsdriver.Uniform, evenOdd bool, depthTest bool) {
 	for _, src := range srcs {
 		if i == src {
 			panic("buffered: Image.DrawTriangles: source images must be different from the receiver")
@@ -224,7 +282,7 @@ func (i *Image) DrawTriangles(srcs [graphics.ShaderImageNum]*Image, vertices []f
 	if maybeCanAddDelayedCommand() {
 		if tryAddDelayedCommand(func() error {
 			// Arguments are not copied. Copying is the caller's responsibility.
-			i.DrawTriangles(srcs, vertices, indices, colorm, mode, filter, address, dstRegion, srcRegion, subimageOffsets, shader, uniforms, evenOdd)
+			i.DrawTriangles(srcs, vertices, indices, colorm, mode, filter, address, dstRegion, srcRegion, subimageOffsets, shader, uniforms, evenOdd, depthTest)
 			return nil
 		}) {
 			return
@@ -250,10 +308,64 @@ func (i *Image) DrawTriangles(srcs [graphics.ShaderImageNum]*Image, vertices []f
 	}
 	i.resolvePendingPixels(false)
 
-	i.img.DrawTriangles(imgs, vertices, indices, colorm, mode, filter, address, dstRegion, srcRegion, subimageOffsets, s, uniforms, evenOdd)
+	i.img.DrawTriangles(imgs, vertices, indices, colorm, mode, filter, address, dstRegion, srcRegion, subimageOffsets, s, uniforms, evenOdd, depthTest)
+	i.invalidatePendingPixels()
+}
+
+// CopyPixels copies the region (srcX, srcY)-(srcX+width, srcY+height) of src to (dstX, dstY) of the image as it is.
+func (i *Image) CopyPixels(src *Image, dstX, dstY, srcX, srcY, width, height int) {
+	if i == src {
+		panic("buffered: Image.CopyPixels: the source image must be different from the receiver")
+	}
+
+	if maybeCanAddDelayedCommand() {
+		if tryAddDelayedCommand(func() error {
+			i.CopyPixels(src, dstX, dstY, srcX, srcY, width, height)
+			return nil
+		}) {
+			return
+		}
+	}
+
+	src.resolvePendingPixels(true)
+	i.resolvePendingPixels(false)
+
+	i.img.CopyPixels(src.img, dstX, dstY, srcX, srcY, width, height)
 	i.invalidatePendingPixels()
 }
 
+// FillRect replaces the pixels of the region (x, y)-(x+width, y+height) of the image with the given premultiplied
+// color.
+func (i *Image) FillRect(x, y, width, height int, red, green, blue, alpha float32) {
+	if maybeCanAddDelayedCommand() {
+		if tryAddDelayedCommand(func() error {
+			i.FillRect(x, y, width, height, red, green, blue, alpha)
+			return nil
+		}) {
+			return
+		}
+	}
+
+	i.resolvePendingPixels(false)
+
+	i.img.FillRect(x, y, width, height, red, green, blue, alpha)
+	i.invalidatePendingPixels()
+}
+
+// ClearDepth resets the depth buffer of the image.
+func (i *Image) ClearDepth() {
+	if maybeCanAddDelayedCommand() {
+		if tryAddDelayedCommand(func() error {
+			i.ClearDepth()
+			return nil
+		}) {
+			return
+		}
+	}
+
+	i.img.ClearDepth()
+}
+
 type Shader struct {
 	shader *atlas.Shader
 }
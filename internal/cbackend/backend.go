@@ -0,0 +1,76 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cbackend
+
+import (
+	"time"
+)
+
+// Gamepad represents a gamepad state.
+type Gamepad struct {
+	// ID is the gamepad's identifier. ID must be unique among the connected gamepads.
+	ID int
+
+	// Standard reports whether the gamepad has the standard layout.
+	// See https://www.w3.org/TR/gamepad/#remapping.
+	Standard bool
+
+	// ButtonCount is the number of the buttons. ButtonCount must be 32 or less.
+	ButtonCount int
+
+	// AxisCount is the number of the axes. AxisCount must be 16 or less.
+	AxisCount int
+
+	// ButtonPressed represents whether each button is pressed.
+	ButtonPressed [32]bool
+
+	// ButtonValues represents the value of each button in between 0 and 1.
+	ButtonValues [32]float64
+
+	// AxisValues represents the value of each axis in between -1 and 1.
+	AxisValues [16]float64
+}
+
+// Touch represents a touch state.
+type Touch struct {
+	// ID is the touch's identifier. ID must be unique among the current touches.
+	ID int
+
+	// X and Y are the position in pixels.
+	X int
+	Y int
+}
+
+// Backend is the interface of a platform implementation.
+// See the public package cbackend for the documentation.
+type Backend interface {
+	InitializeGame()
+	ScreenSize() (width, height int)
+	BeginFrame()
+	EndFrame()
+	AppendGamepads(gamepads []Gamepad) []Gamepad
+	AppendTouches(touches []Touch) []Touch
+	VibrateGamepad(id int, duration time.Duration, strongMagnitude, weakMagnitude float64)
+	OpenAudio(sampleRate, channelCount int, onRead func(buf []float32))
+	CloseAudio()
+}
+
+// theBackend is the backend implemented in Go.
+// If theBackend is nil, the C functions are used.
+var theBackend Backend
+
+func SetBackend(backend Backend) {
+	theBackend = backend
+}
@@ -68,43 +68,46 @@ import (
 	"unsafe"
 )
 
-type Gamepad struct {
-	ID            int
-	Standard      bool
-	ButtonCount   int
-	AxisCount     int
-	ButtonPressed [32]bool
-	ButtonValues  [32]float64
-	AxisValues    [16]float64
-}
-
-type Touch struct {
-	ID int
-	X  int
-	Y  int
-}
-
 func InitializeGame() {
+	if theBackend != nil {
+		theBackend.InitializeGame()
+		return
+	}
 	C.EbitenInitializeGame()
 }
 
 func ScreenSize() (int, int) {
+	if theBackend != nil {
+		return theBackend.ScreenSize()
+	}
 	var width, height C.int
 	C.EbitenGetScreenSize(&width, &height)
 	return int(width), int(height)
 }
 
 func BeginFrame() {
+	if theBackend != nil {
+		theBackend.BeginFrame()
+		return
+	}
 	C.EbitenBeginFrame()
 }
 
 func EndFrame() {
+	if theBackend != nil {
+		theBackend.EndFrame()
+		return
+	}
 	C.EbitenEndFrame()
 }
 
 var cGamepads []C.struct_Gamepad
 
 func AppendGamepads(gamepads []Gamepad) []Gamepad {
+	if theBackend != nil {
+		return theBackend.AppendGamepads(gamepads)
+	}
+
 	n := int(C.EbitenGetGamepadNum())
 	if cap(cGamepads) < n {
 		cGamepads = append(cGamepads, make([]C.struct_Gamepad, n)...)
@@ -138,6 +141,10 @@ func AppendGamepads(gamepads []Gamepad) []Gamepad {
 var cTouches []C.struct_Touch
 
 func AppendTouches(touches []Touch) []Touch {
+	if theBackend != nil {
+		return theBackend.AppendTouches(touches)
+	}
+
 	n := int(C.EbitenGetTouchNum())
 	cTouches = cTouches[:0]
 	if cap(cTouches) < n {
@@ -160,17 +167,29 @@ func AppendTouches(touches []Touch) []Touch {
 }
 
 func VibrateGamepad(id int, duration time.Duration, strongMagnitude float64, weakMagnitude float64) {
+	if theBackend != nil {
+		theBackend.VibrateGamepad(id, duration, strongMagnitude, weakMagnitude)
+		return
+	}
 	C.EbitenVibrateGamepad(C.int(id), C.double(float64(duration)/float64(time.Second)), C.double(strongMagnitude), C.double(weakMagnitude))
 }
 
 var onReadCallback func(buf []float32)
 
 func OpenAudio(sampleRate, channelNum int, onRead func(buf []float32)) {
+	if theBackend != nil {
+		theBackend.OpenAudio(sampleRate, channelNum, onRead)
+		return
+	}
 	C.EbitenOpenAudioProxy(C.int(sampleRate), C.int(channelNum))
 	onReadCallback = onRead
 }
 
 func CloseAudio() {
+	if theBackend != nil {
+		theBackend.CloseAudio()
+		return
+	}
 	C.EbitenCloseAudio()
 }
 
@@ -16,6 +16,8 @@
 package clock
 
 import (
+	"fmt"
+	"math"
 	"sync"
 	"time"
 )
@@ -27,11 +29,15 @@ var (
 	// lastSystemTime indicates the logical time in the game, so this can be bigger than the curren time.
 	lastSystemTime int64
 
-	currentFPS  float64
-	currentTPS  float64
-	lastUpdated int64
-	fpsCount    = 0
-	tpsCount    = 0
+	currentFPS   float64
+	currentTPS   float64
+	tickProgress float64
+	lastUpdated  int64
+	fpsCount     = 0
+	tpsCount     = 0
+
+	// fixedTPS is TPS reported by the fixed clock. If fixedTPS is 0, the clock is not fixed.
+	fixedTPS float64
 
 	m sync.Mutex
 )
@@ -46,6 +52,9 @@ func init() {
 func CurrentFPS() float64 {
 	m.Lock()
 	v := currentFPS
+	if fixedTPS != 0 {
+		v = fixedTPS
+	}
 	m.Unlock()
 	return v
 }
@@ -53,10 +62,37 @@ func CurrentFPS() float64 {
 func CurrentTPS() float64 {
 	m.Lock()
 	v := currentTPS
+	if fixedTPS != 0 {
+		v = fixedTPS
+	}
 	m.Unlock()
 	return v
 }
 
+// TickProgress returns the progress of the next tick at the last Update, in [0, 1).
+func TickProgress() float64 {
+	m.Lock()
+	v := tickProgress
+	if fixedTPS != 0 {
+		v = 0
+	}
+	m.Unlock()
+	return v
+}
+
+// SetFixed fixes the clock for deterministic testing.
+// While the clock is fixed, CurrentFPS and CurrentTPS return tps, and TickProgress returns 0.
+//
+// If tps is 0, the clock is no longer fixed.
+func SetFixed(tps float64) {
+	if tps < 0 {
+		panic(fmt.Sprintf("clock: tps must be positive or 0 but %f", tps))
+	}
+	m.Lock()
+	fixedTPS = tps
+	m.Unlock()
+}
+
 func max(a, b int64) int64 {
 	if a < b {
 		return b
@@ -64,12 +100,110 @@ func max(a, b int64) int64 {
 	return a
 }
 
-func calcCountFromTPS(tps int64, now int64) int {
-	if tps == 0 {
-		return 0
+const (
+	// MaxTPSNumerator is the maximum numerator of a rational TPS.
+	MaxTPSNumerator = 1000000000
+
+	// MaxTPSDenominator is the maximum denominator of a rational TPS.
+	// With these limits, the calculation in nanoseconds doesn't overflow int64.
+	MaxTPSDenominator = 1000000
+)
+
+// RationalTPS returns the rational number closest to tps within the limits of the numerator and the denominator.
+func RationalTPS(tps float64) (num, den int64) {
+	if tps <= 0 || math.IsNaN(tps) {
+		return 0, 1
 	}
-	if tps < 0 {
-		panic("clock: tps must >= 0")
+	if tps >= MaxTPSNumerator {
+		return MaxTPSNumerator, 1
+	}
+
+	// Find the best rational approximation with continued fractions.
+	// (p0/q0) and (p1/q1) are the last two convergents.
+	p0, q0, p1, q1 := int64(0), int64(1), int64(1), int64(0)
+	x := tps
+	for {
+		a := int64(math.Floor(x))
+		p2, q2 := a*p1+p0, a*q1+q0
+		if q2 > MaxTPSDenominator || p2 > MaxTPSNumerator {
+			// The best approximation is either the last convergent or the semiconvergent
+			// (p0+k*p1)/(q0+k*q1) with the largest k within the limits.
+			k := (MaxTPSDenominator - q0) / q1
+			if p1 > 0 {
+				if kp := (MaxTPSNumerator - p0) / p1; kp < k {
+					k = kp
+				}
+			}
+			if k > 0 {
+				ps, qs := p0+k*p1, q0+k*q1
+				if math.Abs(float64(ps)/float64(qs)-tps) < math.Abs(float64(p1)/float64(q1)-tps) {
+					return ps, qs
+				}
+			}
+			break
+		}
+		p0, q0, p1, q1 = p1, q1, p2, q2
+		f := x - float64(a)
+		if f < 1e-12 {
+			break
+		}
+		x = 1 / f
+	}
+	if p1 == 0 {
+		// tps is too small to be represented.
+		return 1, MaxTPSDenominator
+	}
+	return p1, q1
+}
+
+func gcd(a, b int64) int64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// ReduceTPS reduces the rational TPS num/den.
+// ReduceTPS panics if num/den is not representable within the limits.
+func ReduceTPS(num, den int64) (int64, int64) {
+	if den <= 0 {
+		panic("clock: the denominator of TPS must be > 0")
+	}
+	if num < 0 {
+		panic("clock: the numerator of TPS must be >= 0")
+	}
+	if num == 0 {
+		return 0, 1
+	}
+	g := gcd(num, den)
+	num /= g
+	den /= g
+	if num > MaxTPSNumerator || den > MaxTPSDenominator {
+		panic(fmt.Sprintf("clock: TPS %d/%d exceeds the limits: the numerator must be <= %d and the denominator must be <= %d", num, den, MaxTPSNumerator, MaxTPSDenominator))
+	}
+	return num, den
+}
+
+var (
+	// lastTPSNum and lastTPSDen are the TPS at the last Update.
+	lastTPSNum int64
+	lastTPSDen int64
+
+	// lastSystemTimeRem is the fractional part of lastSystemTime in the unit of 1/lastTPSNum nanoseconds.
+	// Keeping this avoids drift of the logical time when a tick is not an integer nanoseconds.
+	lastSystemTimeRem int64
+)
+
+// calcCountFromTPS returns the number of ticks for the TPS num/den.
+// num and den must be reduced and positive.
+//
+// If maxCount is positive, the count is capped at maxCount.
+// The excess ticks are carried over to the next calls unless dropExcess is true.
+func calcCountFromTPS(num, den int64, now int64, maxCount int, dropExcess bool) int {
+	if num != lastTPSNum || den != lastTPSDen {
+		lastTPSNum = num
+		lastTPSDen = den
+		lastSystemTimeRem = 0
 	}
 
 	diff := now - lastSystemTime
@@ -77,38 +211,69 @@ func calcCountFromTPS(tps int64, now int64) int {
 		return 0
 	}
 
+	// A tick is (tickQuo + tickRem/num) nanoseconds.
+	tickQuo := int64(time.Second) * den / num
+	tickRem := int64(time.Second) * den % num
+
 	count := 0
 	syncWithSystemClock := false
 
 	// Detect whether the previous time is too old.
 	// Use either 5 ticks or 5/60 sec in the case when TPS is too big like 300 (#1444).
-	if diff > max(int64(time.Second)*5/tps, int64(time.Second)*5/60) {
+	if diff > max(int64(time.Second)*5*den/num, int64(time.Second)*5/60) {
 		// The previous time is too old.
 		// Let's force to sync the game time with the system clock.
 		syncWithSystemClock = true
 	} else {
-		count = int(diff * tps / int64(time.Second))
+		count = int((diff*num - lastSystemTimeRem) / (int64(time.Second) * den))
 	}
 
 	// Stabilize the count.
 	// Without this adjustment, count can be unstable like 0, 2, 0, 2, ...
 	// TODO: Brush up this logic so that this will work with any FPS. Now this works only when FPS = TPS.
-	if count == 0 && (int64(time.Second)/tps/2) < diff {
+	if count == 0 && tickQuo/2 < diff {
 		count = 1
 	}
-	if count == 2 && (int64(time.Second)/tps*3/2) > diff {
+	if count == 2 && tickQuo*3/2 > diff {
 		count = 1
 	}
 
+	if maxCount > 0 && count > maxCount {
+		count = maxCount
+		if dropExcess {
+			// Let the game time fall behind the system time instead of catching up.
+			syncWithSystemClock = true
+		}
+	}
+
 	if syncWithSystemClock {
 		lastSystemTime = now
+		lastSystemTimeRem = 0
 	} else {
-		lastSystemTime += int64(count) * int64(time.Second) / tps
+		lastSystemTimeRem += int64(count) * tickRem
+		lastSystemTime += int64(count)*tickQuo + lastSystemTimeRem/num
+		lastSystemTimeRem %= num
 	}
 
 	return count
 }
 
+// calcTickProgress returns the elapsed ratio of the next tick to the logical game time.
+// calcTickProgress must be called after calcCountFromTPS.
+func calcTickProgress(num, den int64, now int64) float64 {
+	diff := now - lastSystemTime
+	if diff <= 0 {
+		return 0
+	}
+	p := (float64(diff)*float64(num) - float64(lastSystemTimeRem)) / (float64(time.Second) * float64(den))
+	if p >= 1 {
+		// The logical time can be behind the system time by more than one tick
+		// since the count is stabilized. Just round this down.
+		return math.Nextafter(1, 0)
+	}
+	return p
+}
+
 func updateFPSAndTPS(now int64, count int) {
 	fpsCount++
 	tpsCount += count
@@ -128,13 +293,17 @@ func updateFPSAndTPS(now int64, count int) {
 const SyncWithFPS = -1
 
 // Update updates the inner clock state and returns an integer value
-// indicating how many times the game should update based on given tps.
-// tps represents TPS (ticks per second).
-// If tps is SyncWithFPS, Update always returns 1.
-// If tps <= 0 and not SyncWithFPS, Update always returns 0.
+// indicating how many times the game should update based on given TPS.
+// tpsNum/tpsDen represents TPS (ticks per second) as a rational number reduced by ReduceTPS.
+// If tpsNum is SyncWithFPS, Update always returns 1.
+// If tpsNum <= 0 and not SyncWithFPS, Update always returns 0.
+//
+// If maxCount is positive, Update returns at most maxCount.
+// When the count is capped, the excess ticks are carried over to the following frames if dropExcess is false,
+// or discarded if dropExcess is true.
 //
 // Update is expected to be called per frame.
-func Update(tps int) int {
+func Update(tpsNum, tpsDen int64, maxCount int, dropExcess bool) int {
 	m.Lock()
 	defer m.Unlock()
 
@@ -146,10 +315,12 @@ func Update(tps int) int {
 	lastNow = n
 
 	c := 0
-	if tps == SyncWithFPS {
+	tickProgress = 0
+	if tpsNum == SyncWithFPS {
 		c = 1
-	} else if tps > 0 {
-		c = calcCountFromTPS(int64(tps), n)
+	} else if tpsNum > 0 {
+		c = calcCountFromTPS(tpsNum, tpsDen, n, maxCount, dropExcess)
+		tickProgress = calcTickProgress(tpsNum, tpsDen, n)
 	}
 	updateFPSAndTPS(n, c)
 
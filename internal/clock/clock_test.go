@@ -0,0 +1,317 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clock
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// bestRational returns the rational number closest to x within the limits by brute force.
+func bestRational(x float64) (int64, int64) {
+	bestNum, bestDen := int64(0), int64(1)
+	bestDiff := math.Inf(1)
+	for den := int64(1); den <= MaxTPSDenominator; den++ {
+		num := int64(math.Round(x * float64(den)))
+		if num < 1 || num > MaxTPSNumerator {
+			continue
+		}
+		if d := math.Abs(float64(num)/float64(den) - x); d < bestDiff {
+			bestNum, bestDen, bestDiff = num, den, d
+		}
+	}
+	g := gcd(bestNum, bestDen)
+	return bestNum / g, bestDen / g
+}
+
+func TestRationalTPS(t *testing.T) {
+	tests := []struct {
+		tps float64
+		num int64
+		den int64
+	}{
+		{tps: 60, num: 60, den: 1},
+		{tps: 59.94, num: 2997, den: 50},
+		{tps: 60000.0 / 1001.0, num: 60000, den: 1001},
+		{tps: 1.0 / 3.0, num: 1, den: 3},
+		{tps: math.Pi, num: 3126535, den: 995207},
+		{tps: 0, num: 0, den: 1},
+		{tps: -1, num: 0, den: 1},
+		{tps: math.NaN(), num: 0, den: 1},
+		{tps: math.Inf(1), num: MaxTPSNumerator, den: 1},
+		{tps: 1e10, num: MaxTPSNumerator, den: 1},
+		{tps: 1e-9, num: 1, den: MaxTPSDenominator},
+	}
+	for _, tc := range tests {
+		num, den := RationalTPS(tc.tps)
+		if num != tc.num || den != tc.den {
+			t.Errorf("RationalTPS(%v): got: %d/%d, want: %d/%d", tc.tps, num, den, tc.num, tc.den)
+		}
+	}
+}
+
+func TestRationalTPSClosest(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping the brute-force search in short mode")
+	}
+	for _, tps := range []float64{math.Pi, math.E, 120.000004798, 29.97002997, 0.7071067811865476, 144.0001} {
+		num, den := RationalTPS(tps)
+		wantNum, wantDen := bestRational(tps)
+		if num != wantNum || den != wantDen {
+			t.Errorf("RationalTPS(%v): got: %d/%d, want: %d/%d", tps, num, den, wantNum, wantDen)
+		}
+	}
+}
+
+func TestReduceTPS(t *testing.T) {
+	tests := []struct {
+		num     int64
+		den     int64
+		wantNum int64
+		wantDen int64
+	}{
+		{num: 60, den: 1, wantNum: 60, wantDen: 1},
+		{num: 120, den: 2, wantNum: 60, wantDen: 1},
+		{num: 0, den: 7, wantNum: 0, wantDen: 1},
+		{num: 2 * MaxTPSNumerator, den: 2, wantNum: MaxTPSNumerator, wantDen: 1},
+	}
+	for _, tc := range tests {
+		num, den := ReduceTPS(tc.num, tc.den)
+		if num != tc.wantNum || den != tc.wantDen {
+			t.Errorf("ReduceTPS(%d, %d): got: %d/%d, want: %d/%d", tc.num, tc.den, num, den, tc.wantNum, tc.wantDen)
+		}
+	}
+}
+
+func TestReduceTPSPanics(t *testing.T) {
+	tests := []struct {
+		num int64
+		den int64
+	}{
+		{num: 60, den: 0},
+		{num: -1, den: 1},
+		{num: MaxTPSNumerator + 1, den: 1},
+		{num: 1, den: MaxTPSDenominator + 1},
+	}
+	for _, tc := range tests {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("ReduceTPS(%d, %d) must panic", tc.num, tc.den)
+				}
+			}()
+			ReduceTPS(tc.num, tc.den)
+		}()
+	}
+}
+
+// resetClock resets the logical game time to start.
+func resetClock(start int64) {
+	lastSystemTime = start
+	lastSystemTimeRem = 0
+	lastTPSNum = 0
+	lastTPSDen = 0
+}
+
+func TestCalcCountFromTPS(t *testing.T) {
+	const sec = int64(time.Second)
+	tests := []struct {
+		name string
+		num  int64
+		den  int64
+		// elapsed is the elapsed nanoseconds of each frame.
+		elapsed []int64
+		want    []int
+	}{
+		{
+			name:    "60 TPS at 60 FPS",
+			num:     60,
+			den:     1,
+			elapsed: []int64{sec / 60, sec / 60, sec / 60},
+			want:    []int{1, 1, 1},
+		},
+		{
+			name:    "60 TPS at 30 FPS",
+			num:     60,
+			den:     1,
+			elapsed: []int64{sec/30 + 1, sec/30 + 1, sec/30 + 1},
+			want:    []int{2, 2, 2},
+		},
+		{
+			name:    "30 TPS at 60 FPS",
+			num:     30,
+			den:     1,
+			elapsed: []int64{sec / 60, sec / 60, sec / 60, sec / 60},
+			want:    []int{0, 1, 0, 1},
+		},
+		{
+			name:    "no time elapsed",
+			num:     60,
+			den:     1,
+			elapsed: []int64{0},
+			want:    []int{0},
+		},
+		{
+			// The game time is synced with the system clock, and the count is stabilized to 1.
+			name:    "too old",
+			num:     60,
+			den:     1,
+			elapsed: []int64{sec, sec/60 + 1},
+			want:    []int{1, 1},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			resetClock(0)
+			var now int64
+			for i, e := range tc.elapsed {
+				now += e
+				if got := calcCountFromTPS(tc.num, tc.den, now, 0, false); got != tc.want[i] {
+					t.Errorf("frame %d: got: %d, want: %d", i, got, tc.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCalcCountFromRationalTPSDoesNotDrift(t *testing.T) {
+	// 60000/1001 TPS: a tick is 16683333.33... nanoseconds.
+	const num, den = 60000, 1001
+	const frames = 60000
+
+	resetClock(0)
+	var total int
+	for i := 1; i <= frames; i++ {
+		// Call at the exact tick times rounded up to nanoseconds.
+		now := (int64(i)*int64(time.Second)*den + num - 1) / num
+		total += calcCountFromTPS(num, den, now, 0, false)
+	}
+	if total != frames {
+		t.Errorf("total count: got: %d, want: %d", total, frames)
+	}
+
+	// After 60000 ticks, exactly 1001 seconds must have passed in the logical game time.
+	if got, want := lastSystemTime, 1001*int64(time.Second); got != want || lastSystemTimeRem != 0 {
+		t.Errorf("lastSystemTime: got: %d (+%d/%d), want: %d", got, lastSystemTimeRem, num, want)
+	}
+}
+
+func TestCalcTickProgress(t *testing.T) {
+	const sec = int64(time.Second)
+	resetClock(0)
+	calcCountFromTPS(60, 1, sec/60, 0, false)
+
+	if got, want := calcTickProgress(60, 1, sec/60), 0.0; got != want {
+		t.Errorf("calcTickProgress at the tick: got: %v, want: %v", got, want)
+	}
+	if got, want := calcTickProgress(60, 1, sec/60+sec/120), 0.5; math.Abs(got-want) > 1e-6 {
+		t.Errorf("calcTickProgress at the half tick: got: %v, want: %v", got, want)
+	}
+	if got := calcTickProgress(60, 1, sec); got >= 1 {
+		t.Errorf("calcTickProgress long after the tick: got: %v, want: < 1", got)
+	}
+}
+
+func TestCalcCountFromTPSMaxCount(t *testing.T) {
+	const sec = int64(time.Second)
+	tests := []struct {
+		name       string
+		dropExcess bool
+		want       []int
+	}{
+		{
+			// The excess ticks are carried over to the following frames.
+			name:       "carry over",
+			dropExcess: false,
+			want:       []int{2, 2, 1, 1},
+		},
+		{
+			// The excess ticks are discarded.
+			name:       "drop excess",
+			dropExcess: true,
+			want:       []int{2, 1, 1, 1},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			resetClock(0)
+			// A slow frame taking 3 ticks followed by frames taking 1 tick, with at most 2 updates per frame.
+			elapsed := []int64{sec/20 + 1, sec / 60, sec / 60, sec / 60}
+			var now int64
+			for i, e := range elapsed {
+				now += e
+				if got := calcCountFromTPS(60, 1, now, 2, tc.dropExcess); got != tc.want[i] {
+					t.Errorf("frame %d: got: %d, want: %d", i, got, tc.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCalcCountFromTPSMaxCountNotReached(t *testing.T) {
+	const sec = int64(time.Second)
+	for _, dropExcess := range []bool{false, true} {
+		resetClock(0)
+		if got, want := calcCountFromTPS(60, 1, sec/30+1, 3, dropExcess), 2; got != want {
+			t.Errorf("dropExcess: %v: got: %d, want: %d", dropExcess, got, want)
+		}
+		// The logical game time must advance by the ticks, not synced with the system clock.
+		if got, want := lastSystemTime, 2*sec/60; got != want {
+			t.Errorf("dropExcess: %v: lastSystemTime: got: %d, want: %d", dropExcess, got, want)
+		}
+	}
+}
+
+func TestSetFixed(t *testing.T) {
+	m.Lock()
+	currentFPS, currentTPS, tickProgress = 30, 29, 0.5
+	m.Unlock()
+
+	SetFixed(60)
+	if got, want := CurrentFPS(), 60.0; got != want {
+		t.Errorf("CurrentFPS(): got: %f, want: %f", got, want)
+	}
+	if got, want := CurrentTPS(), 60.0; got != want {
+		t.Errorf("CurrentTPS(): got: %f, want: %f", got, want)
+	}
+	if got, want := TickProgress(), 0.0; got != want {
+		t.Errorf("TickProgress(): got: %f, want: %f", got, want)
+	}
+
+	SetFixed(0)
+	if got, want := CurrentFPS(), 30.0; got != want {
+		t.Errorf("CurrentFPS(): got: %f, want: %f", got, want)
+	}
+	if got, want := CurrentTPS(), 29.0; got != want {
+		t.Errorf("CurrentTPS(): got: %f, want: %f", got, want)
+	}
+	if got, want := TickProgress(), 0.5; got != want {
+		t.Errorf("TickProgress(): got: %f, want: %f", got, want)
+	}
+}
+
+func TestTick(t *testing.T) {
+	defer SetTick(CurrentTick())
+
+	SetTick(10)
+	AdvanceTick()
+	AdvanceTick()
+	if got, want := CurrentTick(), int64(12); got != want {
+		t.Errorf("CurrentTick(): got: %d, want: %d", got, want)
+	}
+}
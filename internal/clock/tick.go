@@ -0,0 +1,37 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clock
+
+import (
+	"sync/atomic"
+)
+
+// tick is the number of the Update calls. tick must be accessed atomically.
+var tick int64
+
+// CurrentTick returns the number of the Update calls before the current Update.
+func CurrentTick() int64 {
+	return atomic.LoadInt64(&tick)
+}
+
+// AdvanceTick increments the tick number.
+func AdvanceTick() {
+	atomic.AddInt64(&tick, 1)
+}
+
+// SetTick sets the tick number, e.g., to resimulate past ticks.
+func SetTick(t int64) {
+	atomic.StoreInt64(&tick, t)
+}
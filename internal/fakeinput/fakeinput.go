@@ -0,0 +1,250 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fakeinput provides fake input states that override the actual input devices.
+//
+// This is used for testing and replaying input states.
+package fakeinput
+
+import (
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/gamepad"
+	"github.com/hajimehoshi/ebiten/v2/internal/gamepaddb"
+	"github.com/hajimehoshi/ebiten/v2/internal/ui"
+)
+
+// State represents fake input states.
+type State struct {
+	Keys         []ui.Key
+	MouseButtons []ui.MouseButton
+	CursorX      int
+	CursorY      int
+	WheelX       float64
+	WheelY       float64
+	Chars        []rune
+	Touches      []Touch
+	Gamepads     []Gamepad
+}
+
+// Touch represents a fake touch.
+type Touch struct {
+	ID ui.TouchID
+	X  int
+	Y  int
+}
+
+// Gamepad represents a fake gamepad.
+type Gamepad struct {
+	ID      gamepad.ID
+	Axes    []float64
+	Buttons []bool
+
+	StandardLayoutAvailable bool
+
+	// StandardAxes and StandardButtonValues are indexed by gamepaddb.StandardAxis and gamepaddb.StandardButton.
+	StandardAxes           []float64
+	StandardButtonValues   []float64
+	StandardButtonsPressed []bool
+}
+
+var (
+	enabled      bool
+	state        State
+	keys         map[ui.Key]struct{}
+	mouseButtons map[ui.MouseButton]struct{}
+	m            sync.Mutex
+)
+
+// Enable enables the fake input states. The states are reset to have no pressed keys and buttons.
+func Enable() {
+	m.Lock()
+	defer m.Unlock()
+	enabled = true
+	setState(&State{})
+}
+
+// Disable disables the fake input states. The actual input devices are used again.
+func Disable() {
+	m.Lock()
+	defer m.Unlock()
+	enabled = false
+	setState(&State{})
+}
+
+// IsEnabled reports whether the fake input states are enabled.
+func IsEnabled() bool {
+	m.Lock()
+	defer m.Unlock()
+	return enabled
+}
+
+// Set sets the fake input states of the keys, the mouse buttons, and the cursor position.
+// The other states are reset.
+func Set(pressedKeys []ui.Key, pressedMouseButtons []ui.MouseButton, x, y int) {
+	SetState(&State{
+		Keys:         pressedKeys,
+		MouseButtons: pressedMouseButtons,
+		CursorX:      x,
+		CursorY:      y,
+	})
+}
+
+// SetState sets the fake input states.
+func SetState(s *State) {
+	m.Lock()
+	defer m.Unlock()
+	setState(s)
+}
+
+func setState(s *State) {
+	state = *s
+	keys = map[ui.Key]struct{}{}
+	for _, k := range s.Keys {
+		keys[k] = struct{}{}
+	}
+	mouseButtons = map[ui.MouseButton]struct{}{}
+	for _, b := range s.MouseButtons {
+		mouseButtons[b] = struct{}{}
+	}
+}
+
+// CurrentState returns the current fake input states.
+func CurrentState() State {
+	m.Lock()
+	defer m.Unlock()
+	return state
+}
+
+// IsKeyPressed reports whether the key is pressed in the fake input states.
+func IsKeyPressed(key ui.Key) bool {
+	m.Lock()
+	defer m.Unlock()
+	_, ok := keys[key]
+	return ok
+}
+
+// IsMouseButtonPressed reports whether the mouse button is pressed in the fake input states.
+func IsMouseButtonPressed(button ui.MouseButton) bool {
+	m.Lock()
+	defer m.Unlock()
+	_, ok := mouseButtons[button]
+	return ok
+}
+
+// CursorPosition returns the cursor position in the fake input states.
+func CursorPosition() (x, y int) {
+	m.Lock()
+	defer m.Unlock()
+	return state.CursorX, state.CursorY
+}
+
+// Wheel returns the wheel offsets in the fake input states.
+func Wheel() (xoff, yoff float64) {
+	m.Lock()
+	defer m.Unlock()
+	return state.WheelX, state.WheelY
+}
+
+// AppendInputChars appends the input characters in the fake input states.
+func AppendInputChars(runes []rune) []rune {
+	m.Lock()
+	defer m.Unlock()
+	return append(runes, state.Chars...)
+}
+
+// AppendTouchIDs appends the touch IDs in the fake input states.
+func AppendTouchIDs(touches []ui.TouchID) []ui.TouchID {
+	m.Lock()
+	defer m.Unlock()
+	for _, t := range state.Touches {
+		touches = append(touches, t.ID)
+	}
+	return touches
+}
+
+// TouchPosition returns the position of the touch in the fake input states.
+func TouchPosition(id ui.TouchID) (x, y int) {
+	m.Lock()
+	defer m.Unlock()
+	for _, t := range state.Touches {
+		if t.ID == id {
+			return t.X, t.Y
+		}
+	}
+	return 0, 0
+}
+
+// AppendGamepadIDs appends the gamepad IDs in the fake input states.
+func AppendGamepadIDs(ids []gamepad.ID) []gamepad.ID {
+	m.Lock()
+	defer m.Unlock()
+	for _, g := range state.Gamepads {
+		ids = append(ids, g.ID)
+	}
+	return ids
+}
+
+// GetGamepad returns the gamepad in the fake input states, or nil if the gamepad is not present.
+func GetGamepad(id gamepad.ID) *Gamepad {
+	m.Lock()
+	defer m.Unlock()
+	for i := range state.Gamepads {
+		if state.Gamepads[i].ID == id {
+			g := state.Gamepads[i]
+			return &g
+		}
+	}
+	return nil
+}
+
+// Axis returns the axis value, or 0 if axis is out of range.
+func (g *Gamepad) Axis(axis int) float64 {
+	if axis < 0 || axis >= len(g.Axes) {
+		return 0
+	}
+	return g.Axes[axis]
+}
+
+// IsButtonPressed reports whether the button is pressed. Hats are included in the buttons.
+func (g *Gamepad) IsButtonPressed(button int) bool {
+	if button < 0 || button >= len(g.Buttons) {
+		return false
+	}
+	return g.Buttons[button]
+}
+
+// StandardAxisValue returns the standard axis value, or 0 if the standard layout is not available.
+func (g *Gamepad) StandardAxisValue(axis gamepaddb.StandardAxis) float64 {
+	if int(axis) < 0 || int(axis) >= len(g.StandardAxes) {
+		return 0
+	}
+	return g.StandardAxes[axis]
+}
+
+// StandardButtonValue returns the standard button value, or 0 if the standard layout is not available.
+func (g *Gamepad) StandardButtonValue(button gamepaddb.StandardButton) float64 {
+	if int(button) < 0 || int(button) >= len(g.StandardButtonValues) {
+		return 0
+	}
+	return g.StandardButtonValues[button]
+}
+
+// IsStandardButtonPressed reports whether the standard button is pressed.
+func (g *Gamepad) IsStandardButtonPressed(button gamepaddb.StandardButton) bool {
+	if int(button) < 0 || int(button) >= len(g.StandardButtonsPressed) {
+		return false
+	}
+	return g.StandardButtonsPressed[button]
+}
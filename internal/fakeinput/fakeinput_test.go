@@ -0,0 +1,145 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fakeinput_test
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/fakeinput"
+	"github.com/hajimehoshi/ebiten/v2/internal/gamepaddb"
+	"github.com/hajimehoshi/ebiten/v2/internal/ui"
+)
+
+func TestEnableAndDisable(t *testing.T) {
+	fakeinput.Enable()
+	if !fakeinput.IsEnabled() {
+		t.Errorf("IsEnabled after Enable: got: false, want: true")
+	}
+	fakeinput.Set([]ui.Key{ui.KeyA}, nil, 1, 2)
+
+	fakeinput.Disable()
+	if fakeinput.IsEnabled() {
+		t.Errorf("IsEnabled after Disable: got: true, want: false")
+	}
+	if fakeinput.IsKeyPressed(ui.KeyA) {
+		t.Errorf("IsKeyPressed(KeyA) after Disable: got: true, want: false")
+	}
+
+	// Enable resets the states.
+	fakeinput.Set([]ui.Key{ui.KeyA}, nil, 1, 2)
+	fakeinput.Enable()
+	defer fakeinput.Disable()
+	if fakeinput.IsKeyPressed(ui.KeyA) {
+		t.Errorf("IsKeyPressed(KeyA) after Enable: got: true, want: false")
+	}
+}
+
+func TestSetState(t *testing.T) {
+	fakeinput.Enable()
+	defer fakeinput.Disable()
+
+	fakeinput.SetState(&fakeinput.State{
+		Keys:         []ui.Key{ui.KeyA, ui.KeySpace},
+		MouseButtons: []ui.MouseButton{ui.MouseButtonRight},
+		CursorX:      10,
+		CursorY:      20,
+		WheelX:       0.5,
+		WheelY:       -1,
+		Chars:        []rune("ab"),
+		Touches: []fakeinput.Touch{
+			{ID: 3, X: 30, Y: 40},
+		},
+	})
+
+	if !fakeinput.IsKeyPressed(ui.KeyA) || !fakeinput.IsKeyPressed(ui.KeySpace) {
+		t.Errorf("IsKeyPressed(KeyA), IsKeyPressed(KeySpace): got: false, want: true")
+	}
+	if fakeinput.IsKeyPressed(ui.KeyB) {
+		t.Errorf("IsKeyPressed(KeyB): got: true, want: false")
+	}
+	if !fakeinput.IsMouseButtonPressed(ui.MouseButtonRight) || fakeinput.IsMouseButtonPressed(ui.MouseButtonLeft) {
+		t.Errorf("IsMouseButtonPressed: got: unexpected result")
+	}
+	if x, y := fakeinput.CursorPosition(); x != 10 || y != 20 {
+		t.Errorf("CursorPosition: got: (%d, %d), want: (10, 20)", x, y)
+	}
+	if x, y := fakeinput.Wheel(); x != 0.5 || y != -1 {
+		t.Errorf("Wheel: got: (%v, %v), want: (0.5, -1)", x, y)
+	}
+	if got := string(fakeinput.AppendInputChars(nil)); got != "ab" {
+		t.Errorf("AppendInputChars: got: %q, want: %q", got, "ab")
+	}
+	if got := fakeinput.AppendTouchIDs(nil); len(got) != 1 || got[0] != 3 {
+		t.Errorf("AppendTouchIDs: got: %v, want: [3]", got)
+	}
+	if x, y := fakeinput.TouchPosition(3); x != 30 || y != 40 {
+		t.Errorf("TouchPosition(3): got: (%d, %d), want: (30, 40)", x, y)
+	}
+	if x, y := fakeinput.TouchPosition(4); x != 0 || y != 0 {
+		t.Errorf("TouchPosition(4): got: (%d, %d), want: (0, 0)", x, y)
+	}
+
+	// Set resets the other states.
+	fakeinput.Set([]ui.Key{ui.KeyB}, nil, 0, 0)
+	if fakeinput.IsKeyPressed(ui.KeyA) || !fakeinput.IsKeyPressed(ui.KeyB) {
+		t.Errorf("IsKeyPressed after Set: got: unexpected result")
+	}
+	if got := fakeinput.AppendTouchIDs(nil); len(got) != 0 {
+		t.Errorf("AppendTouchIDs after Set: got: %v, want: []", got)
+	}
+}
+
+func TestGamepad(t *testing.T) {
+	fakeinput.Enable()
+	defer fakeinput.Disable()
+
+	fakeinput.SetState(&fakeinput.State{
+		Gamepads: []fakeinput.Gamepad{
+			{
+				ID:                      1,
+				Axes:                    []float64{0.25, -0.5},
+				Buttons:                 []bool{false, true},
+				StandardLayoutAvailable: true,
+				StandardAxes:            make([]float64, gamepaddb.StandardAxisMax+1),
+				StandardButtonValues:    make([]float64, gamepaddb.StandardButtonMax+1),
+				StandardButtonsPressed:  make([]bool, gamepaddb.StandardButtonMax+1),
+			},
+		},
+	})
+
+	if got := fakeinput.AppendGamepadIDs(nil); len(got) != 1 || got[0] != 1 {
+		t.Fatalf("AppendGamepadIDs: got: %v, want: [1]", got)
+	}
+	if g := fakeinput.GetGamepad(2); g != nil {
+		t.Errorf("GetGamepad(2): got: %v, want: nil", g)
+	}
+
+	g := fakeinput.GetGamepad(1)
+	if g == nil {
+		t.Fatal("GetGamepad(1): got: nil, want: non-nil")
+	}
+	if got := g.Axis(1); got != -0.5 {
+		t.Errorf("Axis(1): got: %v, want: -0.5", got)
+	}
+	if got := g.Axis(2); got != 0 {
+		t.Errorf("Axis(2): got: %v, want: 0", got)
+	}
+	if !g.IsButtonPressed(1) || g.IsButtonPressed(0) || g.IsButtonPressed(-1) || g.IsButtonPressed(2) {
+		t.Errorf("IsButtonPressed: got: unexpected result")
+	}
+	if got := g.StandardAxisValue(gamepaddb.StandardAxisMax + 1); got != 0 {
+		t.Errorf("StandardAxisValue out of range: got: %v, want: 0", got)
+	}
+}
@@ -0,0 +1,337 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package frametiming records the time spent in each phase of recent frames.
+package frametiming
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Phase represents a phase of a frame.
+type Phase int
+
+const (
+	// PhaseUpdate is the time spent in the game's Update, excluding PhaseGPU.
+	PhaseUpdate Phase = iota
+
+	// PhaseDraw is the time spent in the game's Draw and composing the screen on CPU, excluding PhaseGPU.
+	PhaseDraw
+
+	// PhaseGPU is the time spent in sending the graphics commands to the graphics driver.
+	PhaseGPU
+
+	// PhasePresentWait is the time spent in waiting for presenting the screen, e.g. for vsync.
+	PhasePresentWait
+
+	PhaseNum
+)
+
+// Timing represents the time spent in each phase of a frame.
+type Timing [PhaseNum]time.Duration
+
+// Event is a bit set of engine events that can cause a frame spike.
+type Event int
+
+const (
+	// EventAtlasGrowth indicates that a texture atlas is created or extended.
+	EventAtlasGrowth Event = 1 << iota
+
+	// EventShaderCompilation indicates that a shader program is compiled by the graphics driver.
+	EventShaderCompilation
+)
+
+// Frame represents a committed frame.
+type Frame struct {
+	Timing Timing
+
+	// Interval is the time from the previous frame's commit to this frame's commit. Interval is zero for the first frame.
+	Interval time.Duration
+
+	// UpdateCount is the number of the game's Update calls in the frame.
+	UpdateCount int
+
+	// Events is the engine events happened in the frame.
+	Events Event
+}
+
+// Spike represents a frame whose interval exceeds the threshold.
+type Spike struct {
+	Frame Frame
+}
+
+// HistorySize is the maximum number of the frames kept in the history.
+const HistorySize = 256
+
+var theHistory history
+
+type history struct {
+	// timings is a ring buffer of the committed frames.
+	timings [HistorySize]Timing
+
+	// head is the index of the next item to be written in timings.
+	head int
+
+	// num is the number of the valid items in timings.
+	num int
+
+	// intervals, updateCounts, and events are ring buffers of the committed frames along with timings.
+	intervals    [HistorySize]time.Duration
+	updateCounts [HistorySize]int
+	events       [HistorySize]Event
+
+	// current is the timing of the frame not committed yet.
+	current Timing
+
+	currentUpdateCount int
+	currentEvents      Event
+
+	// lastCommitTime is the time of the last commit. lastCommitTime is zero before the first commit.
+	lastCommitTime time.Time
+
+	spikeThreshold time.Duration
+	spikeHandler   func(spike Spike)
+
+	// pendingSpike is the spike detected at the last commit, not notified to the handler yet.
+	pendingSpike        Spike
+	pendingSpikeHandler func(spike Spike)
+
+	m sync.Mutex
+}
+
+func (h *history) add(phase Phase, d time.Duration) {
+	h.m.Lock()
+	defer h.m.Unlock()
+	h.current[phase] += d
+}
+
+func (h *history) currentDuration(phase Phase) time.Duration {
+	h.m.Lock()
+	defer h.m.Unlock()
+	return h.current[phase]
+}
+
+func (h *history) addUpdateCount(n int) {
+	h.m.Lock()
+	defer h.m.Unlock()
+	h.currentUpdateCount += n
+}
+
+func (h *history) addEvent(event Event) {
+	h.m.Lock()
+	defer h.m.Unlock()
+	h.currentEvents |= event
+}
+
+func (h *history) commit() {
+	h.commitAt(time.Now())
+}
+
+func (h *history) commitAt(now time.Time) {
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	var interval time.Duration
+	if !h.lastCommitTime.IsZero() {
+		interval = now.Sub(h.lastCommitTime)
+	}
+	h.lastCommitTime = now
+
+	f := Frame{
+		Timing:      h.current,
+		Interval:    interval,
+		UpdateCount: h.currentUpdateCount,
+		Events:      h.currentEvents,
+	}
+	h.timings[h.head] = f.Timing
+	h.intervals[h.head] = f.Interval
+	h.updateCounts[h.head] = f.UpdateCount
+	h.events[h.head] = f.Events
+	h.head = (h.head + 1) % HistorySize
+	if h.num < HistorySize {
+		h.num++
+	}
+	h.current = Timing{}
+	h.currentUpdateCount = 0
+	h.currentEvents = 0
+
+	if h.spikeHandler != nil && h.spikeThreshold > 0 && f.Interval > h.spikeThreshold {
+		h.pendingSpike = Spike{Frame: f}
+		h.pendingSpikeHandler = h.spikeHandler
+	}
+}
+
+func (h *history) notifySpike() {
+	h.m.Lock()
+	handler := h.pendingSpikeHandler
+	spike := h.pendingSpike
+	h.pendingSpike = Spike{}
+	h.pendingSpikeHandler = nil
+	h.m.Unlock()
+
+	// Call the handler without the lock so that the handler can call the functions of this package.
+	if handler != nil {
+		handler(spike)
+	}
+}
+
+func (h *history) setSpikeHandler(threshold time.Duration, handler func(spike Spike)) {
+	h.m.Lock()
+	defer h.m.Unlock()
+	h.spikeThreshold = threshold
+	h.spikeHandler = handler
+}
+
+func (h *history) append(dst []Timing) []Timing {
+	h.m.Lock()
+	defer h.m.Unlock()
+	start := (h.head - h.num + HistorySize) % HistorySize
+	for i := 0; i < h.num; i++ {
+		dst = append(dst, h.timings[(start+i)%HistorySize])
+	}
+	return dst
+}
+
+func (h *history) appendFrames(dst []Frame) []Frame {
+	h.m.Lock()
+	defer h.m.Unlock()
+	start := (h.head - h.num + HistorySize) % HistorySize
+	for i := 0; i < h.num; i++ {
+		j := (start + i) % HistorySize
+		dst = append(dst, Frame{
+			Timing:      h.timings[j],
+			Interval:    h.intervals[j],
+			UpdateCount: h.updateCounts[j],
+			Events:      h.events[j],
+		})
+	}
+	return dst
+}
+
+// Stats represents statistics of the intervals of frames.
+type Stats struct {
+	Count int
+	Mean  time.Duration
+	P50   time.Duration
+	P90   time.Duration
+	P99   time.Duration
+	Max   time.Duration
+}
+
+// CalcStats calculates the statistics of the intervals of the given frames.
+// The frames with zero intervals are ignored.
+func CalcStats(frames []Frame) Stats {
+	intervals := make([]time.Duration, 0, len(frames))
+	var sum time.Duration
+	for _, f := range frames {
+		if f.Interval == 0 {
+			continue
+		}
+		intervals = append(intervals, f.Interval)
+		sum += f.Interval
+	}
+	if len(intervals) == 0 {
+		return Stats{}
+	}
+	sort.Slice(intervals, func(i, j int) bool {
+		return intervals[i] < intervals[j]
+	})
+
+	// percentile returns the value by the nearest-rank method.
+	percentile := func(p int) time.Duration {
+		n := (len(intervals)*p + 99) / 100
+		if n < 1 {
+			n = 1
+		}
+		return intervals[n-1]
+	}
+	return Stats{
+		Count: len(intervals),
+		Mean:  sum / time.Duration(len(intervals)),
+		P50:   percentile(50),
+		P90:   percentile(90),
+		P99:   percentile(99),
+		Max:   intervals[len(intervals)-1],
+	}
+}
+
+// Add adds the given duration to the phase of the current frame.
+//
+// Add is concurrent-safe.
+func Add(phase Phase, d time.Duration) {
+	theHistory.add(phase, d)
+}
+
+// Current returns the duration of the phase in the current frame so far.
+//
+// Current is useful to exclude the time of a nested phase, e.g. PhaseGPU for flushing commands in Draw.
+//
+// Current is concurrent-safe.
+func Current(phase Phase) time.Duration {
+	return theHistory.currentDuration(phase)
+}
+
+// AddUpdateCount adds n to the number of the game's Update calls in the current frame.
+//
+// AddUpdateCount is concurrent-safe.
+func AddUpdateCount(n int) {
+	theHistory.addUpdateCount(n)
+}
+
+// AddEvent records the engine event to the current frame.
+//
+// AddEvent is concurrent-safe.
+func AddEvent(event Event) {
+	theHistory.addEvent(event)
+}
+
+// Commit finishes the current frame and records it to the history.
+// If the frame's interval exceeds the threshold specified by SetSpikeHandler, the spike is notified at the next
+// NotifySpike call.
+//
+// Commit is concurrent-safe.
+func Commit() {
+	theHistory.commit()
+}
+
+// NotifySpike calls the handler specified by SetSpikeHandler if the frame committed last time is a spike.
+//
+// NotifySpike is concurrent-safe.
+func NotifySpike() {
+	theHistory.notifySpike()
+}
+
+// SetSpikeHandler sets the function called at NotifySpike when a frame's interval exceeds threshold.
+// If handler is nil or threshold is not positive, no function is called.
+//
+// SetSpikeHandler is concurrent-safe.
+func SetSpikeHandler(threshold time.Duration, handler func(spike Spike)) {
+	theHistory.setSpikeHandler(threshold, handler)
+}
+
+// Append appends the timings of the recent frames to dst from the oldest, and returns the extended slice.
+//
+// Append is concurrent-safe.
+func Append(dst []Timing) []Timing {
+	return theHistory.append(dst)
+}
+
+// AppendFrames appends the recent frames to dst from the oldest, and returns the extended slice.
+//
+// AppendFrames is concurrent-safe.
+func AppendFrames(dst []Frame) []Frame {
+	return theHistory.appendFrames(dst)
+}
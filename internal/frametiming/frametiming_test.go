@@ -0,0 +1,168 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package frametiming
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHistory(t *testing.T) {
+	var h history
+	if got := h.append(nil); len(got) != 0 {
+		t.Errorf("len(h.append(nil)): got %d; want 0", len(got))
+	}
+
+	const n = HistorySize + 10
+	for i := 0; i < n; i++ {
+		h.add(PhaseUpdate, time.Duration(i))
+		h.add(PhaseDraw, time.Duration(i))
+		h.add(PhaseDraw, time.Duration(i))
+		h.commit()
+	}
+
+	got := h.append(nil)
+	if len(got) != HistorySize {
+		t.Fatalf("len(h.append(nil)): got %d; want %d", len(got), HistorySize)
+	}
+	for i, timing := range got {
+		j := n - HistorySize + i
+		if got, want := timing[PhaseUpdate], time.Duration(j); got != want {
+			t.Errorf("got[%d][PhaseUpdate]: got %v; want %v", i, got, want)
+		}
+		if got, want := timing[PhaseDraw], time.Duration(2*j); got != want {
+			t.Errorf("got[%d][PhaseDraw]: got %v; want %v", i, got, want)
+		}
+		if got, want := timing[PhaseGPU], time.Duration(0); got != want {
+			t.Errorf("got[%d][PhaseGPU]: got %v; want %v", i, got, want)
+		}
+	}
+}
+
+func TestCurrent(t *testing.T) {
+	var h history
+	h.add(PhaseGPU, 3)
+	h.add(PhaseGPU, 4)
+	if got, want := h.currentDuration(PhaseGPU), time.Duration(7); got != want {
+		t.Errorf("h.currentDuration(PhaseGPU): got %v; want %v", got, want)
+	}
+	h.commit()
+	if got, want := h.currentDuration(PhaseGPU), time.Duration(0); got != want {
+		t.Errorf("h.currentDuration(PhaseGPU) after commit: got %v; want %v", got, want)
+	}
+}
+
+func TestFrames(t *testing.T) {
+	var h history
+
+	var spikes []Spike
+	h.setSpikeHandler(20*time.Millisecond, func(spike Spike) {
+		spikes = append(spikes, spike)
+	})
+
+	now := time.Unix(0, 0)
+	intervals := []time.Duration{0, 16 * time.Millisecond, 50 * time.Millisecond, 17 * time.Millisecond}
+	for i, d := range intervals {
+		now = now.Add(d)
+		h.addUpdateCount(i)
+		if i == 2 {
+			h.addEvent(EventAtlasGrowth)
+			h.addEvent(EventShaderCompilation)
+		}
+		h.commitAt(now)
+		h.notifySpike()
+	}
+
+	got := h.appendFrames(nil)
+	if len(got) != len(intervals) {
+		t.Fatalf("len(h.appendFrames(nil)): got %d; want %d", len(got), len(intervals))
+	}
+	for i, f := range got {
+		if got, want := f.Interval, intervals[i]; got != want {
+			t.Errorf("got[%d].Interval: got %v; want %v", i, got, want)
+		}
+		if got, want := f.UpdateCount, i; got != want {
+			t.Errorf("got[%d].UpdateCount: got %d; want %d", i, got, want)
+		}
+		var want Event
+		if i == 2 {
+			want = EventAtlasGrowth | EventShaderCompilation
+		}
+		if got := f.Events; got != want {
+			t.Errorf("got[%d].Events: got %d; want %d", i, got, want)
+		}
+	}
+
+	if len(spikes) != 1 {
+		t.Fatalf("len(spikes): got %d; want 1", len(spikes))
+	}
+	if got, want := spikes[0].Frame.Interval, 50*time.Millisecond; got != want {
+		t.Errorf("spikes[0].Frame.Interval: got %v; want %v", got, want)
+	}
+	if got, want := spikes[0].Frame.Events, EventAtlasGrowth|EventShaderCompilation; got != want {
+		t.Errorf("spikes[0].Frame.Events: got %d; want %d", got, want)
+	}
+}
+
+func TestCalcStats(t *testing.T) {
+	if got, want := CalcStats(nil), (Stats{}); got != want {
+		t.Errorf("CalcStats(nil): got %v; want %v", got, want)
+	}
+
+	// The first frame without an interval is ignored.
+	frames := []Frame{{}}
+	for i := 1; i <= 100; i++ {
+		frames = append(frames, Frame{Interval: time.Duration(i) * time.Millisecond})
+	}
+	got := CalcStats(frames)
+	want := Stats{
+		Count: 100,
+		Mean:  50500 * time.Microsecond,
+		P50:   50 * time.Millisecond,
+		P90:   90 * time.Millisecond,
+		P99:   99 * time.Millisecond,
+		Max:   100 * time.Millisecond,
+	}
+	if got != want {
+		t.Errorf("CalcStats(frames): got %v; want %v", got, want)
+	}
+}
+
+func TestSpikeNotifiedAfterCommit(t *testing.T) {
+	var h history
+
+	var spikes []Spike
+	h.setSpikeHandler(20*time.Millisecond, func(spike Spike) {
+		spikes = append(spikes, spike)
+	})
+
+	now := time.Unix(0, 0)
+	h.commitAt(now)
+	h.commitAt(now.Add(50 * time.Millisecond))
+	if len(spikes) != 0 {
+		t.Errorf("len(spikes) before notifySpike: got %d; want 0", len(spikes))
+	}
+
+	h.notifySpike()
+	if len(spikes) != 1 {
+		t.Errorf("len(spikes) after notifySpike: got %d; want 1", len(spikes))
+	}
+
+	// A spike is notified only once.
+	h.notifySpike()
+	if len(spikes) != 1 {
+		t.Errorf("len(spikes) after the second notifySpike: got %d; want 1", len(spikes))
+	}
+}
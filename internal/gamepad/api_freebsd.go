@@ -0,0 +1,81 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !ebitencbackend
+// +build !ebitencbackend
+
+package gamepad
+
+import (
+	"runtime"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// usb_gen_descriptor is struct usb_gen_descriptor in dev/usb/usb_ioctl.h.
+type usb_gen_descriptor struct {
+	ugd_data         unsafe.Pointer
+	ugd_lang_id      uint16
+	ugd_maxlen       uint16
+	ugd_actlen       uint16
+	ugd_offset       uint16
+	ugd_config_index uint8
+	ugd_string_index uint8
+	ugd_iface_index  uint8
+	ugd_altif_index  uint8
+	ugd_endpt_index  uint8
+	ugd_report_type  uint8
+	reserved         [8]uint8
+}
+
+const (
+	_IOC_OUT   = 0x40000000
+	_IOC_IN    = 0x80000000
+	_IOC_INOUT = _IOC_IN | _IOC_OUT
+
+	_IOCPARM_MASK = 0x1fff
+)
+
+func _IOC(inout uint, group uint, num uint, len uintptr) uint {
+	return inout | (uint(len)&_IOCPARM_MASK)<<16 | group<<8 | num
+}
+
+var (
+	_USB_GET_REPORT_DESC = _IOC(_IOC_INOUT, 'U', 21, unsafe.Sizeof(usb_gen_descriptor{}))
+	_USB_GET_REPORT_ID   = _IOC(_IOC_OUT, 'U', 25, unsafe.Sizeof(int32(0)))
+)
+
+const maxReportDescriptorSize = 4096
+
+func getReportDescriptor(fd int) ([]byte, error) {
+	buf := make([]byte, maxReportDescriptorSize)
+	d := usb_gen_descriptor{
+		ugd_data:   unsafe.Pointer(&buf[0]),
+		ugd_maxlen: uint16(len(buf)),
+	}
+	if _, _, e := unix.Syscall(unix.SYS_IOCTL, uintptr(fd), uintptr(_USB_GET_REPORT_DESC), uintptr(unsafe.Pointer(&d))); e != 0 {
+		return nil, e
+	}
+	runtime.KeepAlive(buf)
+	return buf[:d.ugd_actlen], nil
+}
+
+func getReportID(fd int) (uint8, error) {
+	id, err := unix.IoctlGetInt(fd, _USB_GET_REPORT_ID)
+	if err != nil {
+		return 0, err
+	}
+	return uint8(id), nil
+}
@@ -0,0 +1,242 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !ebitencbackend
+// +build !ebitencbackend
+
+package gamepad
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+// The values and the types are taken from GameInput.h of the Microsoft Game Development Kit.
+
+const (
+	_GameInputKindGamepad = 0x00040000
+
+	_GameInputDeviceConnected = 0x00000001
+
+	_GameInputBlockingEnumeration = 2
+
+	_GameInputGamepadMenu            = 0x00000001
+	_GameInputGamepadView            = 0x00000002
+	_GameInputGamepadA               = 0x00000004
+	_GameInputGamepadB               = 0x00000008
+	_GameInputGamepadX               = 0x00000010
+	_GameInputGamepadY               = 0x00000020
+	_GameInputGamepadDPadUp          = 0x00000040
+	_GameInputGamepadDPadDown        = 0x00000080
+	_GameInputGamepadDPadLeft        = 0x00000100
+	_GameInputGamepadDPadRight       = 0x00000200
+	_GameInputGamepadLeftShoulder    = 0x00000400
+	_GameInputGamepadRightShoulder   = 0x00000800
+	_GameInputGamepadLeftThumbstick  = 0x00001000
+	_GameInputGamepadRightThumbstick = 0x00002000
+
+	_GameInputRumbleLowFrequency  = 0x00000001
+	_GameInputRumbleHighFrequency = 0x00000002
+	_GameInputRumbleLeftTrigger   = 0x00000004
+	_GameInputRumbleRightTrigger  = 0x00000008
+)
+
+type gameInputError uint32
+
+func (g gameInputError) Error() string {
+	return fmt.Sprintf("GameInput error: 0x%08x", uint32(g))
+}
+
+type _GameInputGamepadState struct {
+	buttons          uint32
+	leftTrigger      float32
+	rightTrigger     float32
+	leftThumbstickX  float32
+	leftThumbstickY  float32
+	rightThumbstickX float32
+	rightThumbstickY float32
+}
+
+type _GameInputRumbleParams struct {
+	lowFrequency  float32
+	highFrequency float32
+	leftTrigger   float32
+	rightTrigger  float32
+}
+
+// _GameInputDeviceInfo is the beginning part of GameInputDeviceInfo. Only the members used in Ebiten are declared.
+type _GameInputDeviceInfo struct {
+	infoSize              uint32
+	vendorId              uint16
+	productId             uint16
+	revisionNumber        uint16
+	interfaceNumber       uint8
+	collectionNumber      uint8
+	usage                 [2]uint16
+	hardwareVersion       [4]uint16
+	firmwareVersion       [4]uint16
+	deviceId              [32]byte
+	deviceRootId          [32]byte
+	deviceFamily          int32
+	capabilities          uint32
+	supportedInput        uint32
+	supportedRumbleMotors uint32
+}
+
+type iGameInput struct {
+	vtbl *iGameInput_Vtbl
+}
+
+type iGameInput_Vtbl struct {
+	QueryInterface uintptr
+	AddRef         uintptr
+	Release        uintptr
+
+	GetCurrentTimestamp            uintptr
+	GetCurrentReading              uintptr
+	GetNextReading                 uintptr
+	GetPreviousReading             uintptr
+	GetTemporalReading             uintptr
+	RegisterReadingCallback        uintptr
+	RegisterDeviceCallback         uintptr
+	RegisterGuideButtonCallback    uintptr
+	RegisterKeyboardLayoutCallback uintptr
+	StopCallback                   uintptr
+	UnregisterCallback             uintptr
+	CreateDispatcher               uintptr
+	CreateAggregateDevice          uintptr
+	FindDeviceFromId               uintptr
+	FindDeviceFromObject           uintptr
+	FindDeviceFromPlatformHandle   uintptr
+	FindDeviceFromPlatformString   uintptr
+	EnableOemDeviceSupport         uintptr
+	SetFocusPolicy                 uintptr
+}
+
+func (i *iGameInput) GetCurrentReading(inputKind uint32, device *iGameInputDevice, reading **iGameInputReading) error {
+	r, _, _ := syscall.Syscall6(i.vtbl.GetCurrentReading, 4,
+		uintptr(unsafe.Pointer(i)),
+		uintptr(inputKind), uintptr(unsafe.Pointer(device)), uintptr(unsafe.Pointer(reading)),
+		0, 0)
+	if uint32(r) != 0 {
+		return fmt.Errorf("gamepad: IGameInput::GetCurrentReading failed: %w", gameInputError(r))
+	}
+	return nil
+}
+
+func (i *iGameInput) RegisterDeviceCallback(device *iGameInputDevice, inputKind uint32, statusFilter uint32, enumerationKind uint32, context unsafe.Pointer, callbackFunc uintptr, callbackToken *uint64) error {
+	r, _, _ := syscall.Syscall9(i.vtbl.RegisterDeviceCallback, 8,
+		uintptr(unsafe.Pointer(i)),
+		uintptr(unsafe.Pointer(device)), uintptr(inputKind), uintptr(statusFilter), uintptr(enumerationKind),
+		uintptr(context), callbackFunc, uintptr(unsafe.Pointer(callbackToken)),
+		0)
+	if uint32(r) != 0 {
+		return fmt.Errorf("gamepad: IGameInput::RegisterDeviceCallback failed: %w", gameInputError(r))
+	}
+	return nil
+}
+
+type iGameInputReading struct {
+	vtbl *iGameInputReading_Vtbl
+}
+
+type iGameInputReading_Vtbl struct {
+	QueryInterface uintptr
+	AddRef         uintptr
+	Release        uintptr
+
+	GetInputKind             uintptr
+	GetSequenceNumber        uintptr
+	GetTimestamp             uintptr
+	GetDevice                uintptr
+	GetRawReport             uintptr
+	GetControllerAxisCount   uintptr
+	GetControllerAxisState   uintptr
+	GetControllerButtonCount uintptr
+	GetControllerButtonState uintptr
+	GetControllerSwitchCount uintptr
+	GetControllerSwitchState uintptr
+	GetKeyCount              uintptr
+	GetKeyState              uintptr
+	GetMouseState            uintptr
+	GetTouchCount            uintptr
+	GetTouchState            uintptr
+	GetMotionState           uintptr
+	GetArcadeStickState      uintptr
+	GetFlightStickState      uintptr
+	GetGamepadState          uintptr
+	GetRacingWheelState      uintptr
+	GetUiNavigationState     uintptr
+}
+
+func (i *iGameInputReading) GetGamepadState(state *_GameInputGamepadState) bool {
+	r, _, _ := syscall.Syscall(i.vtbl.GetGamepadState, 2, uintptr(unsafe.Pointer(i)), uintptr(unsafe.Pointer(state)), 0)
+	return byte(r) != 0
+}
+
+func (i *iGameInputReading) Release() uint32 {
+	r, _, _ := syscall.Syscall(i.vtbl.Release, 1, uintptr(unsafe.Pointer(i)), 0, 0)
+	return uint32(r)
+}
+
+type iGameInputDevice struct {
+	vtbl *iGameInputDevice_Vtbl
+}
+
+type iGameInputDevice_Vtbl struct {
+	QueryInterface uintptr
+	AddRef         uintptr
+	Release        uintptr
+
+	GetDeviceInfo                   uintptr
+	GetDeviceStatus                 uintptr
+	GetBatteryState                 uintptr
+	CreateForceFeedbackEffect       uintptr
+	IsForceFeedbackMotorPoweredOn   uintptr
+	SetForceFeedbackMotorGain       uintptr
+	SetHapticMotorState             uintptr
+	SetRumbleState                  uintptr
+	SetInputSynchronizationState    uintptr
+	SendInputSynchronizationHint    uintptr
+	PowerOff                        uintptr
+	CreateRawDeviceReport           uintptr
+	GetRawDeviceFeature             uintptr
+	SetRawDeviceFeature             uintptr
+	SendRawDeviceOutput             uintptr
+	SendRawDeviceOutputWithResponse uintptr
+	ExecuteRawDeviceIoControl       uintptr
+	AcquireExclusiveRawDeviceAccess uintptr
+	ReleaseExclusiveRawDeviceAccess uintptr
+}
+
+func (i *iGameInputDevice) AddRef() uint32 {
+	r, _, _ := syscall.Syscall(i.vtbl.AddRef, 1, uintptr(unsafe.Pointer(i)), 0, 0)
+	return uint32(r)
+}
+
+func (i *iGameInputDevice) GetDeviceInfo() *_GameInputDeviceInfo {
+	r, _, _ := syscall.Syscall(i.vtbl.GetDeviceInfo, 1, uintptr(unsafe.Pointer(i)), 0, 0)
+	// The returned info is owned by the device on the C side, and the Go runtime never moves it.
+	return (*_GameInputDeviceInfo)(unsafe.Pointer(r))
+}
+
+func (i *iGameInputDevice) Release() uint32 {
+	r, _, _ := syscall.Syscall(i.vtbl.Release, 1, uintptr(unsafe.Pointer(i)), 0, 0)
+	return uint32(r)
+}
+
+func (i *iGameInputDevice) SetRumbleState(params *_GameInputRumbleParams) {
+	syscall.Syscall(i.vtbl.SetRumbleState, 2, uintptr(unsafe.Pointer(i)), uintptr(unsafe.Pointer(params)), 0)
+}
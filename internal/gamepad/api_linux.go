@@ -31,6 +31,10 @@ const (
 
 	_BTN_MISC = 0x100
 
+	_FF_RUMBLE = 0x50
+	_FF_MAX    = 0x7f
+	_FF_CNT    = _FF_MAX + 1
+
 	_IOC_NONE  = 0
 	_IOC_WRITE = 1
 	_IOC_READ  = 2
@@ -60,6 +64,10 @@ func _IOR(typ, nr, size uint) uint {
 	return _IOC(_IOC_READ, typ, nr, size)
 }
 
+func _IOW(typ, nr, size uint) uint {
+	return _IOC(_IOC_WRITE, typ, nr, size)
+}
+
 func _EVIOCGABS(abs uint) uint {
 	return _IOR('E', 0x40+abs, uint(unsafe.Sizeof(input_absinfo{})))
 }
@@ -76,6 +84,37 @@ func _EVIOCGNAME(len uint) uint {
 	return _IOC(_IOC_READ, 'E', 0x06, len)
 }
 
+func _EVIOCSFF() uint {
+	return _IOW('E', 0x80, uint(unsafe.Sizeof(ff_effect{})))
+}
+
+type ff_replay struct {
+	length uint16
+	delay  uint16
+}
+
+type ff_rumble_effect struct {
+	strong_magnitude uint16
+	weak_magnitude   uint16
+}
+
+type ff_trigger struct {
+	button   uint16
+	interval uint16
+}
+
+type ff_effect struct {
+	typ       uint16
+	id        int16
+	direction uint16
+	trigger   ff_trigger
+	replay    ff_replay
+
+	// u is the union of the effect parameters.
+	// The largest member, ff_periodic_effect, is 24 bytes followed by a pointer, and the union is pointer-aligned.
+	u [(24 + unsafe.Sizeof(uintptr(0))) / unsafe.Sizeof(uintptr(0))]uintptr
+}
+
 type input_absinfo struct {
 	value      int32
 	minimum    int32
@@ -99,6 +138,8 @@ type input_id struct {
 	version uint16
 }
 
+// ioctl never returns an error, as r is unsigned.
+// Use ioctlErrno to handle the error.
 func ioctl(fd int, request uint, ptr unsafe.Pointer) error {
 	r, _, e := unix.Syscall(unix.SYS_IOCTL, uintptr(fd), uintptr(request), uintptr(ptr))
 	if r < 0 {
@@ -106,3 +147,12 @@ func ioctl(fd int, request uint, ptr unsafe.Pointer) error {
 	}
 	return nil
 }
+
+// ioctlErrno is like ioctl but returns an error when the system call fails.
+func ioctlErrno(fd int, request uint, ptr unsafe.Pointer) error {
+	_, _, e := unix.Syscall(unix.SYS_IOCTL, uintptr(fd), uintptr(request), uintptr(ptr))
+	if e != 0 {
+		return e
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !ebitencbackend
+// +build !ebitencbackend
+
+package gamepad
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// usb_ctl_report_desc is struct usb_ctl_report_desc in dev/usb/usb.h.
+type usb_ctl_report_desc struct {
+	ucrd_size int32
+	ucrd_data [1024]uint8
+}
+
+const (
+	_IOC_OUT   = 0x40000000
+	_IOC_IN    = 0x80000000
+	_IOC_INOUT = _IOC_IN | _IOC_OUT
+
+	_IOCPARM_MASK = 0x1fff
+)
+
+func _IOC(inout uint, group uint, num uint, len uintptr) uint {
+	return inout | (uint(len)&_IOCPARM_MASK)<<16 | group<<8 | num
+}
+
+var (
+	_USB_GET_REPORT_DESC = _IOC(_IOC_OUT, 'U', 21, unsafe.Sizeof(usb_ctl_report_desc{}))
+	_USB_GET_REPORT_ID   = _IOC(_IOC_OUT, 'U', 25, unsafe.Sizeof(int32(0)))
+)
+
+func getReportDescriptor(fd int) ([]byte, error) {
+	var d usb_ctl_report_desc
+	if _, _, e := unix.Syscall(unix.SYS_IOCTL, uintptr(fd), uintptr(_USB_GET_REPORT_DESC), uintptr(unsafe.Pointer(&d))); e != 0 {
+		return nil, e
+	}
+	if d.ucrd_size < 0 || int(d.ucrd_size) > len(d.ucrd_data) {
+		return nil, unix.EINVAL
+	}
+	return append([]byte(nil), d.ucrd_data[:d.ucrd_size]...), nil
+}
+
+func getReportID(fd int) (uint8, error) {
+	id, err := unix.IoctlGetInt(fd, _USB_GET_REPORT_ID)
+	if err != nil {
+		return 0, err
+	}
+	return uint8(id), nil
+}
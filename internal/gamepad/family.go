@@ -0,0 +1,122 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gamepad
+
+import (
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/gamepaddb"
+)
+
+// Family represents a family of gamepads that share the same button labels.
+type Family int
+
+const (
+	FamilyGeneric Family = iota
+	FamilyXbox
+	FamilyPlayStation
+	FamilyNintendo
+)
+
+const (
+	vendorMicrosoft = 0x045e
+	vendorSony      = 0x054c
+	vendorNintendo  = 0x057e
+)
+
+// Family returns the family of the gamepad detected from the vendor ID, or from the name if the vendor ID is not
+// available.
+//
+// Family is concurrent-safe.
+func (g *Gamepad) Family() Family {
+	if vendor, _, ok := g.VendorProductID(); ok {
+		switch vendor {
+		case vendorMicrosoft:
+			return FamilyXbox
+		case vendorSony:
+			return FamilyPlayStation
+		case vendorNintendo:
+			return FamilyNintendo
+		}
+	}
+
+	// The vendor ID is not available e.g. for XInput devices on Windows.
+	name := strings.ToLower(g.Name())
+	switch {
+	case strings.Contains(name, "xbox") || strings.Contains(name, "xinput"):
+		return FamilyXbox
+	case strings.Contains(name, "dualsense") || strings.Contains(name, "dualshock") || strings.Contains(name, "playstation"):
+		return FamilyPlayStation
+	case strings.Contains(name, "nintendo") || strings.Contains(name, "joy-con") || strings.Contains(name, "switch pro"):
+		return FamilyNintendo
+	}
+	return FamilyGeneric
+}
+
+var standardButtonLabels = map[Family]map[gamepaddb.StandardButton]string{
+	FamilyXbox: {
+		gamepaddb.StandardButtonRightBottom:      "A",
+		gamepaddb.StandardButtonRightRight:       "B",
+		gamepaddb.StandardButtonRightLeft:        "X",
+		gamepaddb.StandardButtonRightTop:         "Y",
+		gamepaddb.StandardButtonFrontTopLeft:     "LB",
+		gamepaddb.StandardButtonFrontTopRight:    "RB",
+		gamepaddb.StandardButtonFrontBottomLeft:  "LT",
+		gamepaddb.StandardButtonFrontBottomRight: "RT",
+		gamepaddb.StandardButtonCenterLeft:       "View",
+		gamepaddb.StandardButtonCenterRight:      "Menu",
+		gamepaddb.StandardButtonLeftStick:        "LS",
+		gamepaddb.StandardButtonRightStick:       "RS",
+		gamepaddb.StandardButtonCenterCenter:     "Xbox",
+	},
+	FamilyPlayStation: {
+		gamepaddb.StandardButtonRightBottom:      "Cross",
+		gamepaddb.StandardButtonRightRight:       "Circle",
+		gamepaddb.StandardButtonRightLeft:        "Square",
+		gamepaddb.StandardButtonRightTop:         "Triangle",
+		gamepaddb.StandardButtonFrontTopLeft:     "L1",
+		gamepaddb.StandardButtonFrontTopRight:    "R1",
+		gamepaddb.StandardButtonFrontBottomLeft:  "L2",
+		gamepaddb.StandardButtonFrontBottomRight: "R2",
+		gamepaddb.StandardButtonCenterLeft:       "Create",
+		gamepaddb.StandardButtonCenterRight:      "Options",
+		gamepaddb.StandardButtonLeftStick:        "L3",
+		gamepaddb.StandardButtonRightStick:       "R3",
+		gamepaddb.StandardButtonCenterCenter:     "PS",
+	},
+	// The standard layout is positional, so the bottom and the right buttons are labeled B and A on Nintendo's
+	// gamepads.
+	FamilyNintendo: {
+		gamepaddb.StandardButtonRightBottom:      "B",
+		gamepaddb.StandardButtonRightRight:       "A",
+		gamepaddb.StandardButtonRightLeft:        "Y",
+		gamepaddb.StandardButtonRightTop:         "X",
+		gamepaddb.StandardButtonFrontTopLeft:     "L",
+		gamepaddb.StandardButtonFrontTopRight:    "R",
+		gamepaddb.StandardButtonFrontBottomLeft:  "ZL",
+		gamepaddb.StandardButtonFrontBottomRight: "ZR",
+		gamepaddb.StandardButtonCenterLeft:       "-",
+		gamepaddb.StandardButtonCenterRight:      "+",
+		gamepaddb.StandardButtonLeftStick:        "LS",
+		gamepaddb.StandardButtonRightStick:       "RS",
+		gamepaddb.StandardButtonCenterCenter:     "Home",
+	},
+}
+
+// StandardButtonLabel returns the label printed on the standard button of the gamepads of the family.
+// StandardButtonLabel returns an empty string when the label is unknown.
+func StandardButtonLabel(family Family, button gamepaddb.StandardButton) string {
+	return standardButtonLabels[family][button]
+}
@@ -15,6 +15,9 @@
 package gamepad
 
 import (
+	"encoding/hex"
+	"regexp"
+	"strconv"
 	"sync"
 	"time"
 
@@ -181,6 +184,64 @@ func (g *Gamepad) SDLID() string {
 	return g.sdlID
 }
 
+// VendorProductID is concurrent-safe.
+func (g *Gamepad) VendorProductID() (vendor, product uint16, ok bool) {
+	// These are immutable and don't have to be protected by a mutex.
+	if vendor, product, ok := vendorProductIDFromSDLID(g.sdlID); ok {
+		return vendor, product, true
+	}
+	return vendorProductIDFromName(g.name)
+}
+
+// vendorProductIDFromSDLID returns the vendor and product IDs embedded in an SDL GUID.
+//
+// An SDL GUID has the bus type, the vendor ID, the product ID, and the version as 16-bit little endian values,
+// each of which is followed by two zero bytes. An SDL GUID in another format, e.g., a GUID generated from the
+// device name, doesn't have the IDs.
+func vendorProductIDFromSDLID(sdlID string) (vendor, product uint16, ok bool) {
+	bs, err := hex.DecodeString(sdlID)
+	if err != nil || len(bs) != 16 {
+		return 0, 0, false
+	}
+	if bs[2] != 0 || bs[3] != 0 || bs[6] != 0 || bs[7] != 0 || bs[10] != 0 || bs[11] != 0 {
+		return 0, 0, false
+	}
+	vendor = uint16(bs[4]) | uint16(bs[5])<<8
+	product = uint16(bs[8]) | uint16(bs[9])<<8
+	if vendor == 0 || product == 0 {
+		return 0, 0, false
+	}
+	return vendor, product, true
+}
+
+var (
+	// chromeGamepadIDRe matches a gamepad ID on Chrome like "Xbox 360 Controller (XInput STANDARD GAMEPAD Vendor: 045e Product: 028e)".
+	chromeGamepadIDRe = regexp.MustCompile(`Vendor: ([0-9a-fA-F]{4}) Product: ([0-9a-fA-F]{4})`)
+
+	// firefoxGamepadIDRe matches a gamepad ID on Firefox like "045e-028e-Xbox 360 Controller".
+	firefoxGamepadIDRe = regexp.MustCompile(`^([0-9a-fA-F]{1,4})-([0-9a-fA-F]{1,4})-`)
+)
+
+// vendorProductIDFromName returns the vendor and product IDs in a gamepad name, which is available on browsers.
+func vendorProductIDFromName(name string) (vendor, product uint16, ok bool) {
+	m := chromeGamepadIDRe.FindStringSubmatch(name)
+	if m == nil {
+		m = firefoxGamepadIDRe.FindStringSubmatch(name)
+	}
+	if m == nil {
+		return 0, 0, false
+	}
+	v, err := strconv.ParseUint(m[1], 16, 16)
+	if err != nil {
+		return 0, 0, false
+	}
+	p, err := strconv.ParseUint(m[2], 16, 16)
+	if err != nil {
+		return 0, 0, false
+	}
+	return uint16(v), uint16(p), true
+}
+
 // AxisCount is concurrent-safe.
 func (g *Gamepad) AxisCount() int {
 	g.m.Lock()
@@ -274,9 +335,19 @@ func (g *Gamepad) IsStandardButtonPressed(button gamepaddb.StandardButton) bool
 }
 
 // Vibrate is concurrent-safe.
-func (g *Gamepad) Vibrate(duration time.Duration, strongMagnitude float64, weakMagnitude float64) {
+func (g *Gamepad) Vibrate(duration time.Duration, strongMagnitude float64, weakMagnitude float64, leftTriggerMagnitude float64, rightTriggerMagnitude float64) {
 	g.m.Lock()
 	defer g.m.Unlock()
 
-	g.nativeGamepad.vibrate(duration, strongMagnitude, weakMagnitude)
+	g.nativeGamepad.vibrate(duration, strongMagnitude, weakMagnitude, leftTriggerMagnitude, rightTriggerMagnitude)
+}
+
+func clamp01(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
 }
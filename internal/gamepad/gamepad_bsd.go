@@ -0,0 +1,282 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build (freebsd || openbsd) && !ebitencbackend
+// +build freebsd openbsd
+// +build !ebitencbackend
+
+package gamepad
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+const dirName = "/dev"
+
+var reUHID = regexp.MustCompile(`^uhid[0-9]+$`)
+
+// scanInterval is the interval to scan the devices.
+// BSDs don't have a portable way to be notified of new device nodes, so the directory is polled.
+const scanInterval = time.Second
+
+type nativeGamepads struct {
+	lastScan time.Time
+}
+
+func (g *nativeGamepads) init(gamepads *gamepads) error {
+	return g.scan(gamepads)
+}
+
+// scan opens all the USB HID devices that are gamepads or joysticks.
+func (g *nativeGamepads) scan(gamepads *gamepads) error {
+	g.lastScan = time.Now()
+
+	ents, err := ioutil.ReadDir(dirName)
+	if err != nil {
+		return fmt.Errorf("gamepad: ReadDir(%s) failed: %w", dirName, err)
+	}
+	for _, ent := range ents {
+		if ent.IsDir() {
+			continue
+		}
+		if !reUHID.MatchString(ent.Name()) {
+			continue
+		}
+		if err := g.openGamepad(gamepads, filepath.Join(dirName, ent.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (*nativeGamepads) openGamepad(gamepads *gamepads, path string) (err error) {
+	if gamepads.find(func(gamepad *Gamepad) bool {
+		return gamepad.path == path
+	}) != nil {
+		return nil
+	}
+
+	fd, err := unix.Open(path, unix.O_RDONLY|unix.O_NONBLOCK|unix.O_CLOEXEC, 0)
+	if err != nil {
+		// The device might be used by another process, or not be accessible.
+		if err == unix.EACCES || err == unix.EBUSY || err == unix.ENOENT || err == unix.ENXIO {
+			return nil
+		}
+		return fmt.Errorf("gamepad: Open failed: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			unix.Close(fd)
+		}
+	}()
+
+	desc, err := getReportDescriptor(fd)
+	if err != nil {
+		// Not all the devices can report their descriptors. Ignore them.
+		unix.Close(fd)
+		return nil
+	}
+	report, err := parseHIDReportDescriptor(desc)
+	if err != nil {
+		unix.Close(fd)
+		return nil
+	}
+	if report == nil {
+		unix.Close(fd)
+		return nil
+	}
+
+	// A uhid device is bound to one report ID. Reading the device returns the reports without the ID.
+	rid, err := getReportID(fd)
+	if err != nil {
+		return fmt.Errorf("gamepad: ioctl for a report ID failed: %w", err)
+	}
+	var axes, buttons, hats []hidField
+	for _, f := range report.axes {
+		if f.reportID == rid {
+			axes = append(axes, f)
+		}
+	}
+	for _, f := range report.buttons {
+		if f.reportID == rid {
+			buttons = append(buttons, f)
+		}
+	}
+	for _, f := range report.hats {
+		if f.reportID == rid {
+			hats = append(hats, f)
+		}
+	}
+	if len(axes) == 0 && len(buttons) == 0 {
+		unix.Close(fd)
+		return nil
+	}
+
+	// The product name is not available in a portable way. Use a generic name.
+	name := "USB Gamepad"
+
+	// Use the same format as SDL's ID without a vendor and a product.
+	const busTypeUSB = 0x03
+	bs := []byte(name)
+	if len(bs) < 12 {
+		bs = append(bs, make([]byte, 12-len(bs))...)
+	}
+	sdlID := fmt.Sprintf("%02x%02x0000%02x%02x%02x%02x%02x%02x%02x%02x%02x%02x%02x%02x",
+		byte(busTypeUSB), byte(busTypeUSB>>8),
+		bs[0], bs[1], bs[2], bs[3], bs[4], bs[5], bs[6], bs[7], bs[8], bs[9], bs[10], bs[11])
+
+	gp := gamepads.add(name, sdlID)
+	gp.path = path
+	gp.fd = fd
+	gp.axisFields = axes
+	gp.buttonFields = buttons
+	gp.hatFields = hats
+	gp.axes = make([]float64, len(axes))
+	gp.buttons = make([]bool, len(buttons))
+	gp.hats = make([]int, len(hats))
+	runtime.SetFinalizer(gp, func(gp *Gamepad) {
+		gp.close()
+	})
+	return nil
+}
+
+func (g *nativeGamepads) update(gamepads *gamepads) error {
+	if time.Since(g.lastScan) < scanInterval {
+		return nil
+	}
+	return g.scan(gamepads)
+}
+
+func removeGamepad(gamepads *gamepads, path string) {
+	gp := gamepads.find(func(gamepad *Gamepad) bool {
+		return gamepad.path == path
+	})
+	if gp == nil {
+		return
+	}
+	gp.close()
+	gamepads.remove(func(gamepad *Gamepad) bool {
+		return gamepad == gp
+	})
+}
+
+type nativeGamepad struct {
+	fd   int
+	path string
+
+	axisFields   []hidField
+	buttonFields []hidField
+	hatFields    []hidField
+
+	axes    []float64
+	buttons []bool
+	hats    []int
+}
+
+func (g *nativeGamepad) close() {
+	if g.fd != 0 {
+		unix.Close(g.fd)
+	}
+	g.fd = 0
+}
+
+func (g *nativeGamepad) update(gamepads *gamepads) error {
+	if g.fd == 0 {
+		return nil
+	}
+
+	buf := make([]byte, 1024)
+	for {
+		// Each read returns one report.
+		n, err := unix.Read(g.fd, buf)
+		if err != nil {
+			if err == unix.EAGAIN || err == unix.EINTR {
+				break
+			}
+			// Disconnected
+			if err == unix.ENXIO || err == unix.ENODEV || err == unix.EIO {
+				removeGamepad(gamepads, g.path)
+				return nil
+			}
+			return fmt.Errorf("gamepad: Read failed: %w", err)
+		}
+		if n == 0 {
+			break
+		}
+
+		report := buf[:n]
+		for i := range g.axisFields {
+			g.axes[i] = g.axisFields[i].axisValue(report)
+		}
+		for i := range g.buttonFields {
+			g.buttons[i] = g.buttonFields[i].value(report) != 0
+		}
+		for i := range g.hatFields {
+			g.hats[i] = g.hatFields[i].hatState(report)
+		}
+	}
+	return nil
+}
+
+func (*nativeGamepad) hasOwnStandardLayoutMapping() bool {
+	return false
+}
+
+func (g *nativeGamepad) axisCount() int {
+	return len(g.axes)
+}
+
+func (g *nativeGamepad) buttonCount() int {
+	return len(g.buttons)
+}
+
+func (g *nativeGamepad) hatCount() int {
+	return len(g.hats)
+}
+
+func (g *nativeGamepad) axisValue(axis int) float64 {
+	if axis < 0 || axis >= len(g.axes) {
+		return 0
+	}
+	return g.axes[axis]
+}
+
+func (g *nativeGamepad) isButtonPressed(button int) bool {
+	if button < 0 || button >= len(g.buttons) {
+		return false
+	}
+	return g.buttons[button]
+}
+
+func (*nativeGamepad) buttonValue(button int) float64 {
+	panic("gamepad: buttonValue is not implemented")
+}
+
+func (g *nativeGamepad) hatState(hat int) int {
+	if hat < 0 || hat >= len(g.hats) {
+		return hatCentered
+	}
+	return g.hats[hat]
+}
+
+func (g *nativeGamepad) vibrate(duration time.Duration, strongMagnitude float64, weakMagnitude float64, leftTriggerMagnitude float64, rightTriggerMagnitude float64) {
+	// usbhid doesn't provide a generic way to use rumble motors.
+}
@@ -128,6 +128,6 @@ func (*nativeGamepad) hatState(hat int) int {
 	return hatCentered
 }
 
-func (g *nativeGamepad) vibrate(duration time.Duration, strongMagnitude float64, weakMagnitude float64) {
+func (g *nativeGamepad) vibrate(duration time.Duration, strongMagnitude float64, weakMagnitude float64, leftTriggerMagnitude float64, rightTriggerMagnitude float64) {
 	cbackend.VibrateGamepad(g.id, duration, strongMagnitude, weakMagnitude)
 }
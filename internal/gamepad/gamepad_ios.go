@@ -90,6 +90,6 @@ func (g *nativeGamepad) hatState(hat int) int {
 	return g.hats[hat]
 }
 
-func (g *nativeGamepad) vibrate(duration time.Duration, strongMagnitude float64, weakMagnitude float64) {
+func (g *nativeGamepad) vibrate(duration time.Duration, strongMagnitude float64, weakMagnitude float64, leftTriggerMagnitude float64, rightTriggerMagnitude float64) {
 	// TODO: Implement this (#1452)
 }
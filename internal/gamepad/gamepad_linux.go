@@ -18,8 +18,12 @@
 package gamepad
 
 import (
+	"bytes"
+	"encoding/binary"
+	"errors"
 	"fmt"
 	"io/ioutil"
+	"math"
 	"path/filepath"
 	"regexp"
 	"runtime"
@@ -37,9 +41,17 @@ func isBitSet(s []byte, bit int) bool {
 	return s[bit/8]&(1<<(bit%8)) != 0
 }
 
+// udevMonitorGroup is the netlink multicast group of the events sent by udev.
+// Unlike the kernel's group, udev sends an event after the device node is ready, e.g., its permissions are set.
+const udevMonitorGroup = 2
+
 type nativeGamepads struct {
 	inotify int
 	watch   int
+	udev    int
+
+	// udevBuf is the buffer to read udev's messages.
+	udevBuf []byte
 }
 
 func (g *nativeGamepads) init(gamepads *gamepads) error {
@@ -71,6 +83,34 @@ func (g *nativeGamepads) init(gamepads *gamepads) error {
 		g.watch = watch
 	}
 
+	// Listen to udev's events in addition to inotify. inotify might report a device before its node is accessible,
+	// while udev reports it after the node is ready.
+	// The netlink socket is not available in some sandboxes. Then inotify is used alone.
+	if fd, err := openUdevMonitor(); err == nil {
+		g.udev = fd
+		g.udevBuf = make([]byte, 8192)
+	}
+
+	return g.scan(gamepads)
+}
+
+func openUdevMonitor() (int, error) {
+	fd, err := unix.Socket(unix.AF_NETLINK, unix.SOCK_RAW|unix.SOCK_NONBLOCK|unix.SOCK_CLOEXEC, unix.NETLINK_KOBJECT_UEVENT)
+	if err != nil {
+		return 0, err
+	}
+	if err := unix.Bind(fd, &unix.SockaddrNetlink{
+		Family: unix.AF_NETLINK,
+		Groups: udevMonitorGroup,
+	}); err != nil {
+		unix.Close(fd)
+		return 0, err
+	}
+	return fd, nil
+}
+
+// scan opens all the gamepads in the directory.
+func (g *nativeGamepads) scan(gamepads *gamepads) error {
 	ents, err := ioutil.ReadDir(dirName)
 	if err != nil {
 		return fmt.Errorf("gamepad: ReadDir(%s) failed: %w", dirName, err)
@@ -97,7 +137,11 @@ func (*nativeGamepads) openGamepad(gamepads *gamepads, path string) (err error)
 		return nil
 	}
 
-	fd, err := unix.Open(path, unix.O_RDONLY|unix.O_NONBLOCK, 0)
+	// Open the device for writing as well to use force feedback.
+	fd, err := unix.Open(path, unix.O_RDWR|unix.O_NONBLOCK, 0)
+	if err == unix.EACCES {
+		fd, err = unix.Open(path, unix.O_RDONLY|unix.O_NONBLOCK, 0)
+	}
 	if err != nil {
 		if err == unix.EACCES {
 			return nil
@@ -108,15 +152,16 @@ func (*nativeGamepads) openGamepad(gamepads *gamepads, path string) (err error)
 		}
 		return fmt.Errorf("gamepad: Open failed: %w", err)
 	}
+
+	var gp *Gamepad
 	defer func() {
-		if err != nil {
-			unix.Close(fd)
-		}
+		err = cleanUpOnOpenError(gamepads, gp, fd, err)
 	}()
 
 	evBits := make([]byte, (unix.EV_CNT+7)/8)
 	keyBits := make([]byte, (_KEY_CNT+7)/8)
 	absBits := make([]byte, (_ABS_CNT+7)/8)
+	ffBits := make([]byte, (_FF_CNT+7)/8)
 	var id input_id
 	if err := ioctl(fd, _EVIOCGBIT(0, uint(len(evBits))), unsafe.Pointer(&evBits[0])); err != nil {
 		return fmt.Errorf("gamepad: ioctl for evBits failed: %w", err)
@@ -130,6 +175,14 @@ func (*nativeGamepads) openGamepad(gamepads *gamepads, path string) (err error)
 	if err := ioctl(fd, _EVIOCGID(), unsafe.Pointer(&id)); err != nil {
 		return fmt.Errorf("gamepad: ioctl for an ID failed: %w", err)
 	}
+	if isBitSet(evBits, unix.EV_FF) {
+		// Without the force feedback bits, the gamepad is still usable without rumble.
+		if err := ioctlErrno(fd, _EVIOCGBIT(unix.EV_FF, uint(len(ffBits))), unsafe.Pointer(&ffBits[0])); err != nil {
+			for i := range ffBits {
+				ffBits[i] = 0
+			}
+		}
+	}
 
 	if !isBitSet(evBits, unix.EV_KEY) {
 		unix.Close(fd)
@@ -140,12 +193,7 @@ func (*nativeGamepads) openGamepad(gamepads *gamepads, path string) (err error)
 		return nil
 	}
 
-	cname := make([]byte, 256)
-	name := "Unknown"
-	// TODO: Is it OK to ignore the error here?
-	if err := ioctl(fd, uint(_EVIOCGNAME(uint(len(name)))), unsafe.Pointer(&cname[0])); err == nil {
-		name = unix.ByteSliceToString(cname)
-	}
+	name := deviceName(fd)
 
 	var sdlID string
 	if id.vendor != 0 && id.product != 0 && id.version != 0 {
@@ -164,9 +212,11 @@ func (*nativeGamepads) openGamepad(gamepads *gamepads, path string) (err error)
 			bs[0], bs[1], bs[2], bs[3], bs[4], bs[5], bs[6], bs[7], bs[8], bs[9], bs[10], bs[11])
 	}
 
-	gp := gamepads.add(name, sdlID)
+	gp = gamepads.add(name, sdlID)
 	gp.path = path
 	gp.fd = fd
+	gp.ffEffectID = -1
+	gp.hasRumble = isBitSet(ffBits, _FF_RUMBLE)
 	runtime.SetFinalizer(gp, func(gp *Gamepad) {
 		gp.close()
 	})
@@ -211,7 +261,45 @@ func (*nativeGamepads) openGamepad(gamepads *gamepads, path string) (err error)
 	return nil
 }
 
+// cleanUpOnOpenError closes the device and removes its gamepad gp if opening the device failed with err.
+// gp can be nil when the gamepad is not added yet.
+//
+// cleanUpOnOpenError returns the error to report, which is nil if the device was disconnected while being opened.
+func cleanUpOnOpenError(gamepads *gamepads, gp *Gamepad, fd int, err error) error {
+	if err == nil {
+		return nil
+	}
+	if gp != nil {
+		gamepads.remove(func(gamepad *Gamepad) bool {
+			return gamepad == gp
+		})
+		gp.fd = 0
+	}
+	unix.Close(fd)
+	if errors.Is(err, unix.ENODEV) {
+		return nil
+	}
+	return err
+}
+
+// deviceName returns the name of the device, or "Unknown" if the name is not available.
+func deviceName(fd int) string {
+	// The size in the request must be the buffer's size. Otherwise, the name is truncated.
+	cname := make([]byte, 256)
+	// TODO: Is it OK to ignore the error here?
+	if err := ioctlErrno(fd, uint(_EVIOCGNAME(uint(len(cname)))), unsafe.Pointer(&cname[0])); err != nil {
+		return "Unknown"
+	}
+	return unix.ByteSliceToString(cname)
+}
+
 func (g *nativeGamepads) update(gamepads *gamepads) error {
+	if g.udev > 0 {
+		if err := g.updateUdev(gamepads); err != nil {
+			return err
+		}
+	}
+
 	if g.inotify <= 0 {
 		return nil
 	}
@@ -247,14 +335,7 @@ func (g *nativeGamepads) update(gamepads *gamepads) error {
 			continue
 		}
 		if e.Mask&unix.IN_DELETE != 0 {
-			if gp := gamepads.find(func(gamepad *Gamepad) bool {
-				return gamepad.path == path
-			}); gp != nil {
-				gp.close()
-				gamepads.remove(func(gamepad *Gamepad) bool {
-					return gamepad == gp
-				})
-			}
+			removeGamepad(gamepads, path)
 			continue
 		}
 	}
@@ -262,6 +343,89 @@ func (g *nativeGamepads) update(gamepads *gamepads) error {
 	return nil
 }
 
+func (g *nativeGamepads) updateUdev(gamepads *gamepads) error {
+	buf := g.udevBuf
+	for {
+		n, err := unix.Read(g.udev, buf)
+		if err != nil {
+			if err == unix.EAGAIN {
+				return nil
+			}
+			// Some events were dropped as the socket's buffer overflowed. Scan the devices again not to miss them.
+			if err == unix.ENOBUFS {
+				if err := g.scan(gamepads); err != nil {
+					return err
+				}
+				continue
+			}
+			return fmt.Errorf("gamepad: Read for udev failed: %w", err)
+		}
+
+		action, path, ok := parseUdevMessage(buf[:n])
+		if !ok {
+			continue
+		}
+		if filepath.Dir(path) != dirName || !reEvent.MatchString(filepath.Base(path)) {
+			continue
+		}
+		switch action {
+		case "add":
+			if err := g.openGamepad(gamepads, path); err != nil {
+				return err
+			}
+		case "remove":
+			removeGamepad(gamepads, path)
+		}
+	}
+}
+
+// parseUdevMessage parses a message from udev and returns its action and the device node's path.
+func parseUdevMessage(msg []byte) (action string, devName string, ok bool) {
+	// The header is struct udev_monitor_netlink_header in libudev:
+	// the prefix "libudev", the magic number in big endian, and the offset and the length of the properties in
+	// native endian, followed by the filter fields.
+	const headerSize = 40
+	if len(msg) < headerSize || string(msg[:8]) != "libudev\x00" || binary.BigEndian.Uint32(msg[8:]) != 0xfeedcafe {
+		return "", "", false
+	}
+	off := int(*(*uint32)(unsafe.Pointer(&msg[16])))
+	l := int(*(*uint32)(unsafe.Pointer(&msg[20])))
+	if off < headerSize || off > len(msg) || l > len(msg)-off {
+		return "", "", false
+	}
+
+	// The properties are null-terminated strings like "KEY=VALUE".
+	for _, prop := range bytes.Split(msg[off:off+l], []byte{0}) {
+		i := bytes.IndexByte(prop, '=')
+		if i < 0 {
+			continue
+		}
+		switch string(prop[:i]) {
+		case "ACTION":
+			action = string(prop[i+1:])
+		case "DEVNAME":
+			devName = string(prop[i+1:])
+		}
+	}
+	if action == "" || devName == "" {
+		return "", "", false
+	}
+	return action, devName, true
+}
+
+func removeGamepad(gamepads *gamepads, path string) {
+	gp := gamepads.find(func(gamepad *Gamepad) bool {
+		return gamepad.path == path
+	})
+	if gp == nil {
+		return
+	}
+	gp.close()
+	gamepads.remove(func(gamepad *Gamepad) bool {
+		return gamepad == gp
+	})
+}
+
 type nativeGamepad struct {
 	fd      int
 	path    string
@@ -277,6 +441,9 @@ type nativeGamepad struct {
 	axisCount_   int
 	buttonCount_ int
 	hatCount_    int
+
+	hasRumble  bool
+	ffEffectID int16
 }
 
 func (g *nativeGamepad) close() {
@@ -328,8 +495,7 @@ func (g *nativeGamepad) update(gamepad *gamepads) error {
 
 		switch e.typ {
 		case unix.EV_KEY:
-			idx := g.keyMap[e.code-_BTN_MISC]
-			g.buttons[idx] = e.value != 0
+			g.handleKeyEvent(int(e.code), e.value)
 		case unix.EV_ABS:
 			g.handleAbsEvent(int(e.code), e.value)
 		}
@@ -350,6 +516,15 @@ func (g *nativeGamepad) pollAbsState() error {
 	return nil
 }
 
+func (g *nativeGamepad) handleKeyEvent(code int, value int32) {
+	// Keys like a keyboard's are not buttons of a gamepad.
+	if code < _BTN_MISC || code >= _KEY_CNT {
+		return
+	}
+	idx := g.keyMap[code-_BTN_MISC]
+	g.buttons[idx] = value != 0
+}
+
 func (g *nativeGamepad) handleAbsEvent(code int, value int32) {
 	index := g.absMap[code]
 
@@ -433,6 +608,49 @@ func (g *nativeGamepad) hatState(hat int) int {
 	return g.hats[hat]
 }
 
-func (g *nativeGamepad) vibrate(duration time.Duration, strongMagnitude float64, weakMagnitude float64) {
-	// TODO: Implement this (#1452)
+func (g *nativeGamepad) vibrate(duration time.Duration, strongMagnitude float64, weakMagnitude float64, leftTriggerMagnitude float64, rightTriggerMagnitude float64) {
+	if g.fd == 0 || !g.hasRumble {
+		return
+	}
+
+	if duration <= 0 || (strongMagnitude <= 0 && weakMagnitude <= 0) {
+		if g.ffEffectID >= 0 {
+			_ = g.writeEvent(unix.EV_FF, uint16(g.ffEffectID), 0)
+		}
+		return
+	}
+
+	ms := duration / time.Millisecond
+	if ms > math.MaxUint16 {
+		ms = math.MaxUint16
+	}
+	e := ff_effect{
+		typ: _FF_RUMBLE,
+		id:  g.ffEffectID,
+		replay: ff_replay{
+			length: uint16(ms),
+		},
+	}
+	r := (*ff_rumble_effect)(unsafe.Pointer(&e.u[0]))
+	r.strong_magnitude = uint16(math.Round(clamp01(strongMagnitude) * 0xffff))
+	r.weak_magnitude = uint16(math.Round(clamp01(weakMagnitude) * 0xffff))
+
+	// Uploading an effect with an existing ID updates the effect.
+	if err := ioctlErrno(g.fd, _EVIOCSFF(), unsafe.Pointer(&e)); err != nil {
+		// The device is opened as read-only or the effect was removed. Give up vibrating.
+		g.ffEffectID = -1
+		return
+	}
+	g.ffEffectID = e.id
+	_ = g.writeEvent(unix.EV_FF, uint16(e.id), 1)
+}
+
+func (g *nativeGamepad) writeEvent(typ uint16, code uint16, value int32) error {
+	e := input_event{
+		typ:   typ,
+		code:  code,
+		value: value,
+	}
+	_, err := unix.Write(g.fd, (*[unsafe.Sizeof(input_event{})]byte)(unsafe.Pointer(&e))[:])
+	return err
 }
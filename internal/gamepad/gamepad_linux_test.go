@@ -0,0 +1,204 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !android && !ebitencbackend
+// +build !android,!ebitencbackend
+
+package gamepad
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestIoctlErrno(t *testing.T) {
+	var fds [2]int
+	if err := unix.Pipe(fds[:]); err != nil {
+		t.Fatal(err)
+	}
+	defer unix.Close(fds[0])
+	defer unix.Close(fds[1])
+
+	// A pipe is not an evdev device.
+	var id input_id
+	if err := ioctlErrno(fds[0], _EVIOCGID(), unsafe.Pointer(&id)); err != unix.ENOTTY {
+		t.Errorf("ioctlErrno for a pipe: got: %v, want: %v", err, unix.ENOTTY)
+	}
+
+	if err := ioctlErrno(-1, _EVIOCGID(), unsafe.Pointer(&id)); err != unix.EBADF {
+		t.Errorf("ioctlErrno for an invalid file descriptor: got: %v, want: %v", err, unix.EBADF)
+	}
+}
+
+func TestEVIOCGNAME(t *testing.T) {
+	for _, n := range []uint{7, 256, 1024} {
+		req := _EVIOCGNAME(n)
+		if got := (req >> _IOC_SIZESHIFT) & (1<<_IOC_SIZEBITS - 1); got != n {
+			t.Errorf("size in _EVIOCGNAME(%d): got: %d, want: %d", n, got, n)
+		}
+		if got, want := req>>_IOC_DIRSHIFT, uint(_IOC_READ); got != want {
+			t.Errorf("direction in _EVIOCGNAME(%d): got: %d, want: %d", n, got, want)
+		}
+	}
+}
+
+func TestDeviceNameUnknown(t *testing.T) {
+	var fds [2]int
+	if err := unix.Pipe(fds[:]); err != nil {
+		t.Fatal(err)
+	}
+	defer unix.Close(fds[0])
+	defer unix.Close(fds[1])
+
+	if got, want := deviceName(fds[0]), "Unknown"; got != want {
+		t.Errorf("deviceName for a pipe: got: %q, want: %q", got, want)
+	}
+}
+
+func TestHandleKeyEvent(t *testing.T) {
+	var g nativeGamepad
+	const btnSouth = 0x130
+	g.keyMap[btnSouth-_BTN_MISC] = 1
+
+	g.handleKeyEvent(btnSouth, 1)
+	if !g.buttons[1] {
+		t.Errorf("buttons[1] after pressing: got: false, want: true")
+	}
+	g.handleKeyEvent(btnSouth, 0)
+	if g.buttons[1] {
+		t.Errorf("buttons[1] after releasing: got: true, want: false")
+	}
+
+	// Out-of-range codes must be ignored without panicking.
+	for _, code := range []int{0, 0x1e, _BTN_MISC - 1, _KEY_CNT, 0xffff} {
+		g.handleKeyEvent(code, 1)
+	}
+	for i, pressed := range g.buttons {
+		if pressed {
+			t.Errorf("buttons[%d]: got: true, want: false", i)
+		}
+	}
+}
+
+func isClosed(fd int) bool {
+	_, err := unix.FcntlInt(uintptr(fd), unix.F_GETFD, 0)
+	return err == unix.EBADF
+}
+
+func TestCleanUpOnOpenError(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		wantErr bool
+	}{
+		{
+			name:    "disconnected",
+			err:     fmt.Errorf("gamepad: ioctl for keyBits failed: %w", unix.ENODEV),
+			wantErr: false,
+		},
+		{
+			name:    "other error",
+			err:     fmt.Errorf("gamepad: ioctl for keyBits failed: %w", unix.EIO),
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var fds [2]int
+			if err := unix.Pipe(fds[:]); err != nil {
+				t.Fatal(err)
+			}
+			defer unix.Close(fds[1])
+
+			var gps gamepads
+			gp := gps.add("foo", "")
+			gp.fd = fds[0]
+
+			err := cleanUpOnOpenError(&gps, gp, fds[0], tc.err)
+			if got := err != nil; got != tc.wantErr {
+				t.Errorf("error: got: %v, want error: %t", err, tc.wantErr)
+			}
+			if tc.wantErr && !errors.Is(err, unix.EIO) {
+				t.Errorf("error: got: %v, want: %v", err, unix.EIO)
+			}
+			if gps.find(func(gamepad *Gamepad) bool { return gamepad == gp }) != nil {
+				t.Errorf("the gamepad must be removed")
+			}
+			if gp.fd != 0 {
+				t.Errorf("gp.fd: got: %d, want: 0", gp.fd)
+			}
+			if !isClosed(fds[0]) {
+				t.Errorf("the file descriptor must be closed")
+			}
+		})
+	}
+}
+
+func TestCleanUpOnOpenErrorWithoutError(t *testing.T) {
+	var fds [2]int
+	if err := unix.Pipe(fds[:]); err != nil {
+		t.Fatal(err)
+	}
+	defer unix.Close(fds[0])
+	defer unix.Close(fds[1])
+
+	var gps gamepads
+	gp := gps.add("foo", "")
+	gp.fd = fds[0]
+
+	if err := cleanUpOnOpenError(&gps, gp, fds[0], nil); err != nil {
+		t.Errorf("error: got: %v, want: nil", err)
+	}
+	if gps.find(func(gamepad *Gamepad) bool { return gamepad == gp }) == nil {
+		t.Errorf("the gamepad must not be removed")
+	}
+	if isClosed(fds[0]) {
+		t.Errorf("the file descriptor must not be closed")
+	}
+}
+
+func TestOpenGamepadNotDevice(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gamepad")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "event0")
+	if err := ioutil.WriteFile(path, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var gps gamepads
+	// A regular file is skipped as it has no keys.
+	if err := gps.openGamepad(&gps, path); err != nil {
+		t.Errorf("openGamepad for a regular file: got: %v, want: nil", err)
+	}
+	if err := gps.openGamepad(&gps, filepath.Join(dir, "event1")); err != nil {
+		t.Errorf("openGamepad for a removed device: got: %v, want: nil", err)
+	}
+	for _, gp := range gps.gamepads {
+		if gp != nil {
+			t.Errorf("no gamepads must be added")
+		}
+	}
+}
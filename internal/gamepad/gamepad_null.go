@@ -12,8 +12,8 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-//go:build !darwin && !js && !linux && !windows
-// +build !darwin,!js,!linux,!windows
+//go:build !darwin && !freebsd && !js && !linux && !openbsd && !windows
+// +build !darwin,!freebsd,!js,!linux,!openbsd,!windows
 
 package gamepad
 
@@ -69,5 +69,5 @@ func (*nativeGamepad) hatState(hat int) int {
 	return hatCentered
 }
 
-func (g *nativeGamepad) vibrate(duration time.Duration, strongMagnitude float64, weakMagnitude float64) {
+func (g *nativeGamepad) vibrate(duration time.Duration, strongMagnitude float64, weakMagnitude float64, leftTriggerMagnitude float64, rightTriggerMagnitude float64) {
 }
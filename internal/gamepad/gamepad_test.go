@@ -0,0 +1,138 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gamepad
+
+import (
+	"testing"
+)
+
+func TestVendorProductIDFromSDLID(t *testing.T) {
+	tests := []struct {
+		name    string
+		sdlID   string
+		vendor  uint16
+		product uint16
+		ok      bool
+	}{
+		{
+			name:    "Xbox 360 Controller on Linux",
+			sdlID:   "030000005e0400008e02000014010000",
+			vendor:  0x045e,
+			product: 0x028e,
+			ok:      true,
+		},
+		{
+			name:    "DualShock 4 over Bluetooth",
+			sdlID:   "050000004c050000cc09000000810000",
+			vendor:  0x054c,
+			product: 0x09cc,
+			ok:      true,
+		},
+		{
+			name:  "XInput",
+			sdlID: "78696e70757401000000000000000000",
+		},
+		{
+			name:  "generated from a name",
+			sdlID: "05000000426c75657a20436972637569",
+		},
+		{
+			name:  "zero vendor",
+			sdlID: "03000000000000008e02000014010000",
+		},
+		{
+			name:  "zero product",
+			sdlID: "030000005e0400000000000014010000",
+		},
+		{
+			name:  "too short",
+			sdlID: "030000005e0400008e020000",
+		},
+		{
+			name:  "not hex",
+			sdlID: "0300000g5e0400008e02000014010000",
+		},
+		{
+			name:  "empty",
+			sdlID: "",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			vendor, product, ok := vendorProductIDFromSDLID(tc.sdlID)
+			if vendor != tc.vendor || product != tc.product || ok != tc.ok {
+				t.Errorf("got: (%04x, %04x, %t), want: (%04x, %04x, %t)", vendor, product, ok, tc.vendor, tc.product, tc.ok)
+			}
+		})
+	}
+}
+
+func TestVendorProductIDFromName(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      string
+		vendor  uint16
+		product uint16
+		ok      bool
+	}{
+		{
+			name:    "Chrome",
+			id:      "Xbox 360 Controller (XInput STANDARD GAMEPAD Vendor: 045e Product: 028e)",
+			vendor:  0x045e,
+			product: 0x028e,
+			ok:      true,
+		},
+		{
+			name:    "Chrome with upper cases",
+			id:      "Wireless Controller (STANDARD GAMEPAD Vendor: 054C Product: 09CC)",
+			vendor:  0x054c,
+			product: 0x09cc,
+			ok:      true,
+		},
+		{
+			name:    "Firefox",
+			id:      "45e-28e-Xbox 360 Wired Controller",
+			vendor:  0x045e,
+			product: 0x028e,
+			ok:      true,
+		},
+		{
+			name: "Firefox without IDs",
+			id:   "xinput",
+		},
+		{
+			name: "Firefox with a too long ID",
+			id:   "045e0-028e-Xbox 360 Controller",
+		},
+		{
+			name: "desktop name",
+			id:   "Xbox 360 Controller",
+		},
+		{
+			name: "empty",
+			id:   "",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			vendor, product, ok := vendorProductIDFromName(tc.id)
+			if vendor != tc.vendor || product != tc.product || ok != tc.ok {
+				t.Errorf("got: (%04x, %04x, %t), want: (%04x, %04x, %t)", vendor, product, ok, tc.vendor, tc.product, tc.ok)
+			}
+		})
+	}
+}
@@ -20,8 +20,10 @@ package gamepad
 import (
 	"errors"
 	"fmt"
+	"math"
 	"sort"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"syscall"
 	"time"
@@ -99,14 +101,39 @@ var xinputButtons = []uint16{
 	_XINPUT_GAMEPAD_RIGHT_THUMB,
 }
 
+// gameInputToXInputButtons maps GameInput's gamepad buttons to XInput's buttons.
+var gameInputToXInputButtons = map[uint32]uint16{
+	_GameInputGamepadA:               _XINPUT_GAMEPAD_A,
+	_GameInputGamepadB:               _XINPUT_GAMEPAD_B,
+	_GameInputGamepadX:               _XINPUT_GAMEPAD_X,
+	_GameInputGamepadY:               _XINPUT_GAMEPAD_Y,
+	_GameInputGamepadLeftShoulder:    _XINPUT_GAMEPAD_LEFT_SHOULDER,
+	_GameInputGamepadRightShoulder:   _XINPUT_GAMEPAD_RIGHT_SHOULDER,
+	_GameInputGamepadView:            _XINPUT_GAMEPAD_BACK,
+	_GameInputGamepadMenu:            _XINPUT_GAMEPAD_START,
+	_GameInputGamepadLeftThumbstick:  _XINPUT_GAMEPAD_LEFT_THUMB,
+	_GameInputGamepadRightThumbstick: _XINPUT_GAMEPAD_RIGHT_THUMB,
+	_GameInputGamepadDPadUp:          _XINPUT_GAMEPAD_DPAD_UP,
+	_GameInputGamepadDPadDown:        _XINPUT_GAMEPAD_DPAD_DOWN,
+	_GameInputGamepadDPadLeft:        _XINPUT_GAMEPAD_DPAD_LEFT,
+	_GameInputGamepadDPadRight:       _XINPUT_GAMEPAD_DPAD_RIGHT,
+}
+
 type nativeGamepads struct {
 	dinput8    windows.Handle
 	dinput8API *iDirectInput8W
 	xinput     windows.Handle
+	gameInput  *iGameInput
 
 	procDirectInput8Create    uintptr
 	procXInputGetCapabilities uintptr
 	procXInputGetState        uintptr
+	procXInputSetState        uintptr
+
+	gameInputDeviceCallback uintptr
+	gameInputCallbackToken  uint64
+	gameInputEvents         []gameInputDeviceEvent
+	gameInputEventsM        sync.Mutex
 
 	origWndProc         uintptr
 	wndProcCallback     uintptr
@@ -117,6 +144,11 @@ type nativeGamepads struct {
 	err           error
 }
 
+type gameInputDeviceEvent struct {
+	device    *iGameInputDevice
+	connected bool
+}
+
 type dinputObject struct {
 	objectType dinputObjectType
 	index      int
@@ -144,6 +176,10 @@ func (g *nativeGamepads) init(gamepads *gamepads) error {
 		g.procDirectInput8Create = p
 	}
 
+	// GameInput is preferred to XInput as GameInput has no limit of the number of gamepads and supports
+	// the trigger motors. XInput is used only when GameInput is not available.
+	g.initGameInput()
+
 	// TODO: Loading xinput1_4.dll or xinput9_1_0.dll should be enough.
 	// See https://source.chromium.org/chromium/chromium/src/+/main:device/gamepad/xinput_data_fetcher_win.cc;l=75-84;drc=643cdf61903e99f27c3d80daee67e217e9d280e0
 	for _, dll := range []string{
@@ -153,6 +189,9 @@ func (g *nativeGamepads) init(gamepads *gamepads) error {
 		"xinput1_2.dll",
 		"xinput1_1.dll",
 	} {
+		if g.gameInput != nil {
+			break
+		}
 		if h, err := windows.LoadLibrary(dll); err == nil {
 			g.xinput = h
 			{
@@ -169,6 +208,13 @@ func (g *nativeGamepads) init(gamepads *gamepads) error {
 				}
 				g.procXInputGetState = p
 			}
+			{
+				p, err := windows.GetProcAddress(h, "XInputSetState")
+				if err != nil {
+					return err
+				}
+				g.procXInputSetState = p
+			}
 			break
 		}
 	}
@@ -190,9 +236,105 @@ func (g *nativeGamepads) init(gamepads *gamepads) error {
 		}
 	}
 
+	if g.gameInput != nil {
+		g.gameInputDeviceCallback = windows.NewCallback(g.gameInputDeviceCallbackFunc)
+		// With the blocking enumeration, the callback is invoked for the already connected gamepads before
+		// RegisterDeviceCallback returns.
+		if err := g.gameInput.RegisterDeviceCallback(nil, _GameInputKindGamepad, _GameInputDeviceConnected, _GameInputBlockingEnumeration, nil, g.gameInputDeviceCallback, &g.gameInputCallbackToken); err != nil {
+			return err
+		}
+		g.processGameInputEvents(gamepads)
+	}
+
 	return nil
 }
 
+// initGameInput initializes GameInput if available. GameInput is available on Windows 10 or later with
+// the GameInput redistributable or the Gaming Services.
+func (g *nativeGamepads) initGameInput() {
+	// GameInput's device callback takes 64bit arguments, which windows.NewCallback cannot treat on 32bit
+	// Windows. GameInput is not provided for 32bit Windows anyway.
+	if unsafe.Sizeof(uintptr(0)) < 8 {
+		return
+	}
+
+	h, err := windows.LoadLibrary("GameInput.dll")
+	if err != nil {
+		return
+	}
+	p, err := windows.GetProcAddress(h, "GameInputCreate")
+	if err != nil {
+		windows.FreeLibrary(h)
+		return
+	}
+	var gameInput *iGameInput
+	// GameInputCreate fails when e.g. the GameInput service is not running. Then fall back to XInput.
+	if r, _, _ := syscall.Syscall(p, 1, uintptr(unsafe.Pointer(&gameInput)), 0, 0); uint32(r) != 0 || gameInput == nil {
+		windows.FreeLibrary(h)
+		return
+	}
+	g.gameInput = gameInput
+}
+
+// gameInputDeviceCallbackFunc is called on GameInput's thread.
+func (g *nativeGamepads) gameInputDeviceCallbackFunc(callbackToken uint64, context unsafe.Pointer, device *iGameInputDevice, timestamp uint64, currentStatus uint32, previousStatus uint32) uintptr {
+	connected := currentStatus&_GameInputDeviceConnected != 0
+	if connected == (previousStatus&_GameInputDeviceConnected != 0) {
+		return 0
+	}
+
+	// Keep the device alive until the event is processed.
+	device.AddRef()
+
+	g.gameInputEventsM.Lock()
+	defer g.gameInputEventsM.Unlock()
+	g.gameInputEvents = append(g.gameInputEvents, gameInputDeviceEvent{
+		device:    device,
+		connected: connected,
+	})
+	return 0
+}
+
+func (g *nativeGamepads) processGameInputEvents(gamepads *gamepads) {
+	g.gameInputEventsM.Lock()
+	events := g.gameInputEvents
+	g.gameInputEvents = nil
+	g.gameInputEventsM.Unlock()
+
+	for _, e := range events {
+		gp := gamepads.find(func(gamepad *Gamepad) bool {
+			return gamepad.gameInputDevice == e.device
+		})
+
+		if !e.connected {
+			if gp != nil {
+				gp.m.Lock()
+				gp.gameInputDevice.Release()
+				gp.gameInputDevice = nil
+				gp.m.Unlock()
+				gamepads.remove(func(gamepad *Gamepad) bool {
+					return gamepad == gp
+				})
+			}
+			e.device.Release()
+			continue
+		}
+
+		if gp != nil {
+			e.device.Release()
+			continue
+		}
+
+		// Use the same SDL ID as an XInput gamepad so that the standard layout mapping for XInput is used.
+		// As the state is converted to XInput's one, the mapping matches.
+		sdlID := fmt.Sprintf("78696e707574%02x000000000000000000", _XINPUT_DEVSUBTYPE_GAMEPAD)
+		gp = gamepads.add("Xbox Controller", sdlID)
+		// The reference added at the callback is owned by the gamepad.
+		gp.gameInputDevice = e.device
+		gp.gameInputRumbleMotors = e.device.GetDeviceInfo().supportedRumbleMotors
+	}
+}
+
 func (g *nativeGamepads) directInput8Create(hinst uintptr, dwVersion uint32, riidltf *windows.GUID, ppvOut **iDirectInput8W, punkOuter unsafe.Pointer) error {
 	r, _, _ := syscall.Syscall6(g.procDirectInput8Create, 5,
 		hinst, uintptr(dwVersion), uintptr(unsafe.Pointer(riidltf)), uintptr(unsafe.Pointer(ppvOut)), uintptr(punkOuter),
@@ -221,6 +363,15 @@ func (g *nativeGamepads) xinputGetState(dwUserIndex uint32, pState *xinputState)
 	return nil
 }
 
+func (g *nativeGamepads) xinputSetState(dwUserIndex uint32, pVibration *xinputVibration) error {
+	r, _, _ := syscall.Syscall(g.procXInputSetState, 2,
+		uintptr(dwUserIndex), uintptr(unsafe.Pointer(pVibration)), 0)
+	if e := syscall.Errno(r); e != windows.ERROR_SUCCESS {
+		return fmt.Errorf("gamepad: XInputSetState failed: %w", e)
+	}
+	return nil
+}
+
 func (g *nativeGamepads) detectConnection(gamepads *gamepads) error {
 	if g.dinput8 != 0 {
 		if g.enumDevicesCallback == 0 {
@@ -238,7 +389,7 @@ func (g *nativeGamepads) detectConnection(gamepads *gamepads) error {
 
 		for i := 0; i < xuserMaxCount; i++ {
 			if gamepads.find(func(g *Gamepad) bool {
-				return g.dinputDevice == nil && g.xinputIndex == i
+				return g.dinputDevice == nil && g.gameInputDevice == nil && g.xinputIndex == i
 			}) != nil {
 				continue
 			}
@@ -530,6 +681,10 @@ func (g *nativeGamepads) update(gamepads *gamepads) error {
 		atomic.StoreInt32(&g.deviceChanged, 0)
 	}
 
+	if g.gameInput != nil {
+		g.processGameInputEvents(gamepads)
+	}
+
 	return nil
 }
 
@@ -551,6 +706,11 @@ type nativeGamepad struct {
 
 	xinputIndex int
 	xinputState xinputState
+
+	gameInputDevice       *iGameInputDevice
+	gameInputRumbleMotors uint32
+
+	vibrationEnd time.Time
 }
 
 func (*nativeGamepad) hasOwnStandardLayoutMapping() bool {
@@ -561,6 +721,10 @@ func (g *nativeGamepad) usesDInput() bool {
 	return g.dinputDevice != nil
 }
 
+func (g *nativeGamepad) usesGameInput() bool {
+	return g.gameInputDevice != nil
+}
+
 func (g *nativeGamepad) update(gamepads *gamepads) (err error) {
 	var disconnected bool
 	defer func() {
@@ -657,6 +821,28 @@ func (g *nativeGamepad) update(gamepads *gamepads) (err error) {
 		return nil
 	}
 
+	if !g.vibrationEnd.IsZero() && !time.Now().Before(g.vibrationEnd) {
+		g.stopVibration()
+	}
+
+	if g.usesGameInput() {
+		var reading *iGameInputReading
+		if err := gamepads.gameInput.GetCurrentReading(_GameInputKindGamepad, g.gameInputDevice, &reading); err != nil {
+			// There might be no reading yet just after the connection. Keep the current state.
+			// A disconnection is notified by the device callback.
+			return nil
+		}
+		defer reading.Release()
+
+		var state _GameInputGamepadState
+		if !reading.GetGamepadState(&state) {
+			return nil
+		}
+		// Convert the state to XInput's one so that the same mapping as XInput is used.
+		g.xinputState.Gamepad = gameInputStateToXInputGamepad(&state)
+		return nil
+	}
+
 	var state xinputState
 	if err := gamepads.xinputGetState(uint32(g.xinputIndex), &state); err != nil {
 		if !errors.Is(err, windows.ERROR_DEVICE_NOT_CONNECTED) {
@@ -669,6 +855,30 @@ func (g *nativeGamepad) update(gamepads *gamepads) (err error) {
 	return nil
 }
 
+func gameInputStateToXInputGamepad(state *_GameInputGamepadState) xinputGamepad {
+	var buttons uint16
+	for gi, xi := range gameInputToXInputButtons {
+		if state.buttons&gi != 0 {
+			buttons |= xi
+		}
+	}
+	thumb := func(v float32) int16 {
+		return int16(math.Round(math.Max(-1, math.Min(1, float64(v)))*32767.5 - 0.5))
+	}
+	trigger := func(v float32) byte {
+		return byte(math.Round(clamp01(float64(v)) * 255))
+	}
+	return xinputGamepad{
+		wButtons:      buttons,
+		bLeftTrigger:  trigger(state.leftTrigger),
+		bRightTrigger: trigger(state.rightTrigger),
+		sThumbLX:      thumb(state.leftThumbstickX),
+		sThumbLY:      thumb(state.leftThumbstickY),
+		sThumbRX:      thumb(state.rightThumbstickX),
+		sThumbRY:      thumb(state.rightThumbstickY),
+	}
+}
+
 func (g *nativeGamepad) axisCount() int {
 	if g.usesDInput() {
 		return len(g.dinputAxes)
@@ -758,6 +968,47 @@ func (g *nativeGamepad) hatState(hat int) int {
 	return v
 }
 
-func (g *nativeGamepad) vibrate(duration time.Duration, strongMagnitude float64, weakMagnitude float64) {
-	// TODO: Implement this (#1452)
+func (g *nativeGamepad) vibrate(duration time.Duration, strongMagnitude float64, weakMagnitude float64, leftTriggerMagnitude float64, rightTriggerMagnitude float64) {
+	// TODO: Implement this for DirectInput (#1452)
+	if g.usesDInput() {
+		return
+	}
+
+	if duration <= 0 {
+		g.stopVibration()
+		return
+	}
+
+	if g.usesGameInput() {
+		// The motors that the device doesn't have are ignored.
+		g.gameInputDevice.SetRumbleState(&_GameInputRumbleParams{
+			lowFrequency:  float32(clamp01(strongMagnitude)),
+			highFrequency: float32(clamp01(weakMagnitude)),
+			leftTrigger:   float32(clamp01(leftTriggerMagnitude)),
+			rightTrigger:  float32(clamp01(rightTriggerMagnitude)),
+		})
+	} else {
+		if theGamepads.procXInputSetState == 0 {
+			return
+		}
+		// XInput's left motor is the low-frequency one. XInput doesn't support the trigger motors.
+		_ = theGamepads.xinputSetState(uint32(g.xinputIndex), &xinputVibration{
+			wLeftMotorSpeed:  uint16(math.Round(clamp01(strongMagnitude) * 0xffff)),
+			wRightMotorSpeed: uint16(math.Round(clamp01(weakMagnitude) * 0xffff)),
+		})
+	}
+	// Neither GameInput nor XInput has a duration. Stop the motors at update after the duration.
+	g.vibrationEnd = time.Now().Add(duration)
+}
+
+func (g *nativeGamepad) stopVibration() {
+	g.vibrationEnd = time.Time{}
+	if g.usesGameInput() {
+		g.gameInputDevice.SetRumbleState(&_GameInputRumbleParams{})
+		return
+	}
+	if theGamepads.procXInputSetState == 0 {
+		return
+	}
+	_ = theGamepads.xinputSetState(uint32(g.xinputIndex), &xinputVibration{})
 }
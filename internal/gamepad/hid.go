@@ -0,0 +1,300 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gamepad
+
+import (
+	"fmt"
+)
+
+// HID usage pages and usages used to recognize gamepads.
+// See the HID Usage Tables, https://usb.org/document-library/hid-usage-tables-13.
+const (
+	hidUsagePageGenericDesktop = 0x01
+	hidUsagePageButton         = 0x09
+
+	hidUsageJoystick            = 0x04
+	hidUsageGamepad             = 0x05
+	hidUsageMultiAxisController = 0x08
+	hidUsageX                   = 0x30
+	hidUsageWheel               = 0x38
+	hidUsageHatSwitch           = 0x39
+)
+
+type hidFieldKind int
+
+const (
+	hidFieldAxis hidFieldKind = iota
+	hidFieldButton
+	hidFieldHat
+)
+
+// hidField is a value in an input report.
+type hidField struct {
+	kind       hidFieldKind
+	reportID   uint8
+	offset     int // in bits, excluding the report ID.
+	size       int // in bits.
+	logicalMin int32
+	logicalMax int32
+}
+
+// hidReportDescriptor is the input part of a HID report descriptor of a gamepad.
+type hidReportDescriptor struct {
+	axes    []hidField
+	buttons []hidField
+	hats    []hidField
+}
+
+type hidGlobalState struct {
+	usagePage   uint32
+	logicalMin  int32
+	logicalMax  int32
+	reportSize  int
+	reportCount int
+	reportID    uint8
+}
+
+// parseHIDReportDescriptor parses a HID report descriptor.
+// parseHIDReportDescriptor returns nil without an error when the device is not a gamepad or a joystick.
+func parseHIDReportDescriptor(desc []byte) (*hidReportDescriptor, error) {
+	var d hidReportDescriptor
+	var found bool
+
+	var global hidGlobalState
+	var globalStack []hidGlobalState
+
+	var usages []uint32
+	var usageMin, usageMax uint32
+	var hasUsageRange bool
+
+	// gamepadDepth is the collection depth where the gamepad's application collection starts, or 0 if not in it.
+	var depth int
+	var gamepadDepth int
+	offsets := map[uint8]int{}
+
+	for len(desc) > 0 {
+		prefix := desc[0]
+		desc = desc[1:]
+
+		// Long items are not used for gamepads.
+		if prefix == 0xfe {
+			if len(desc) < 2 || len(desc) < 2+int(desc[0]) {
+				return nil, fmt.Errorf("gamepad: a long item in a HID report descriptor is truncated")
+			}
+			desc = desc[2+int(desc[0]):]
+			continue
+		}
+
+		size := int(prefix & 0x3)
+		if size == 3 {
+			size = 4
+		}
+		if len(desc) < size {
+			return nil, fmt.Errorf("gamepad: an item in a HID report descriptor is truncated")
+		}
+		var udata uint32
+		for i := 0; i < size; i++ {
+			udata |= uint32(desc[i]) << (8 * i)
+		}
+		sdata := int32(udata)
+		if size > 0 && size < 4 {
+			shift := 32 - 8*size
+			sdata = int32(udata<<shift) >> shift
+		}
+		desc = desc[size:]
+
+		usage := func(u uint32) uint32 {
+			// An extended usage includes its usage page.
+			if size == 4 {
+				return u
+			}
+			return global.usagePage<<16 | u
+		}
+
+		switch prefix & 0xfc {
+		// Main items
+		case 0x80: // Input
+			const (
+				flagConstant = 1 << 0
+				flagVariable = 1 << 1
+			)
+			if gamepadDepth > 0 && udata&flagConstant == 0 && udata&flagVariable != 0 {
+				for i := 0; i < global.reportCount; i++ {
+					var u uint32
+					switch {
+					case hasUsageRange:
+						u = usageMin + uint32(i)
+						if u > usageMax {
+							u = usageMax
+						}
+					case i < len(usages):
+						u = usages[i]
+					case len(usages) > 0:
+						u = usages[len(usages)-1]
+					default:
+						continue
+					}
+					f := hidField{
+						reportID:   global.reportID,
+						offset:     offsets[global.reportID] + i*global.reportSize,
+						size:       global.reportSize,
+						logicalMin: global.logicalMin,
+						logicalMax: global.logicalMax,
+					}
+					switch page, id := u>>16, u&0xffff; {
+					case page == hidUsagePageButton:
+						f.kind = hidFieldButton
+						d.buttons = append(d.buttons, f)
+					case page == hidUsagePageGenericDesktop && id == hidUsageHatSwitch:
+						f.kind = hidFieldHat
+						d.hats = append(d.hats, f)
+					case page == hidUsagePageGenericDesktop && id >= hidUsageX && id <= hidUsageWheel:
+						f.kind = hidFieldAxis
+						d.axes = append(d.axes, f)
+					}
+				}
+			}
+			offsets[global.reportID] += global.reportSize * global.reportCount
+		case 0xa0: // Collection
+			depth++
+			if gamepadDepth == 0 && udata == 0x01 && len(usages) > 0 {
+				switch usages[0] {
+				case hidUsagePageGenericDesktop<<16 | hidUsageJoystick,
+					hidUsagePageGenericDesktop<<16 | hidUsageGamepad,
+					hidUsagePageGenericDesktop<<16 | hidUsageMultiAxisController:
+					gamepadDepth = depth
+					found = true
+				}
+			}
+		case 0xc0: // End Collection
+			if depth == gamepadDepth {
+				gamepadDepth = 0
+			}
+			if depth > 0 {
+				depth--
+			}
+
+		// Global items
+		case 0x04: // Usage Page
+			global.usagePage = udata
+		case 0x14: // Logical Minimum
+			global.logicalMin = sdata
+		case 0x24: // Logical Maximum
+			global.logicalMax = sdata
+			// A logical maximum is unsigned when the minimum is not negative.
+			if global.logicalMin >= 0 {
+				global.logicalMax = int32(udata)
+			}
+		case 0x74: // Report Size
+			global.reportSize = int(udata)
+		case 0x84: // Report ID
+			global.reportID = uint8(udata)
+		case 0x94: // Report Count
+			global.reportCount = int(udata)
+		case 0xa4: // Push
+			globalStack = append(globalStack, global)
+		case 0xb4: // Pop
+			if len(globalStack) == 0 {
+				return nil, fmt.Errorf("gamepad: a Pop item without a Push item in a HID report descriptor")
+			}
+			global = globalStack[len(globalStack)-1]
+			globalStack = globalStack[:len(globalStack)-1]
+
+		// Local items
+		case 0x08: // Usage
+			usages = append(usages, usage(udata))
+		case 0x18: // Usage Minimum
+			usageMin = usage(udata)
+			hasUsageRange = true
+		case 0x28: // Usage Maximum
+			usageMax = usage(udata)
+			hasUsageRange = true
+		}
+
+		// Local items are valid only until the next main item.
+		if prefix&0x0c == 0x00 {
+			usages = usages[:0]
+			usageMin = 0
+			usageMax = 0
+			hasUsageRange = false
+		}
+	}
+
+	if !found {
+		return nil, nil
+	}
+	return &d, nil
+}
+
+// value returns the value of the field in the given report, excluding the report ID.
+func (f *hidField) value(report []byte) int32 {
+	var v uint32
+	for i := 0; i < f.size && i < 32; i++ {
+		bit := f.offset + i
+		if bit/8 >= len(report) {
+			break
+		}
+		if report[bit/8]&(1<<(bit%8)) != 0 {
+			v |= 1 << i
+		}
+	}
+	if f.logicalMin < 0 && f.size > 0 && f.size < 32 {
+		shift := 32 - f.size
+		return int32(v<<shift) >> shift
+	}
+	return int32(v)
+}
+
+// axisValue returns the value of the axis field in [-1, 1].
+func (f *hidField) axisValue(report []byte) float64 {
+	v := float64(f.value(report))
+	if r := float64(f.logicalMax) - float64(f.logicalMin); r != 0 {
+		v = (v - float64(f.logicalMin)) / r
+		v = v*2 - 1
+	}
+	return v
+}
+
+// hatState returns the state of the hat switch field.
+func (f *hidField) hatState(report []byte) int {
+	v := f.value(report)
+	if v < f.logicalMin || v > f.logicalMax {
+		return hatCentered
+	}
+	idx := int(v - f.logicalMin)
+	if f.logicalMax-f.logicalMin+1 == 4 {
+		// A hat switch with 4 directions.
+		idx *= 2
+	}
+	switch idx {
+	case 0:
+		return hatUp
+	case 1:
+		return hatRightUp
+	case 2:
+		return hatRight
+	case 3:
+		return hatRightDown
+	case 4:
+		return hatDown
+	case 5:
+		return hatLeftDown
+	case 6:
+		return hatLeft
+	case 7:
+		return hatLeftUp
+	}
+	return hatCentered
+}
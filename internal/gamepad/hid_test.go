@@ -0,0 +1,125 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gamepad
+
+import (
+	"testing"
+)
+
+func TestParseHIDReportDescriptor(t *testing.T) {
+	desc := []byte{
+		0x05, 0x01, // Usage Page (Generic Desktop)
+		0x09, 0x05, // Usage (Gamepad)
+		0xa1, 0x01, // Collection (Application)
+
+		0x15, 0x00, // Logical Minimum (0)
+		0x25, 0x01, // Logical Maximum (1)
+		0x75, 0x01, // Report Size (1)
+		0x95, 0x08, // Report Count (8)
+		0x05, 0x09, // Usage Page (Button)
+		0x19, 0x01, // Usage Minimum (1)
+		0x29, 0x08, // Usage Maximum (8)
+		0x81, 0x02, // Input (Data, Variable, Absolute)
+
+		0x05, 0x01, // Usage Page (Generic Desktop)
+		0x09, 0x39, // Usage (Hat Switch)
+		0x15, 0x00, // Logical Minimum (0)
+		0x25, 0x07, // Logical Maximum (7)
+		0x75, 0x04, // Report Size (4)
+		0x95, 0x01, // Report Count (1)
+		0x81, 0x42, // Input (Data, Variable, Absolute, Null State)
+		0x81, 0x03, // Input (Constant)
+
+		0x09, 0x30, // Usage (X)
+		0x09, 0x31, // Usage (Y)
+		0x15, 0x81, // Logical Minimum (-127)
+		0x25, 0x7f, // Logical Maximum (127)
+		0x75, 0x08, // Report Size (8)
+		0x95, 0x02, // Report Count (2)
+		0x81, 0x02, // Input (Data, Variable, Absolute)
+
+		0xc0, // End Collection
+	}
+
+	d, err := parseHIDReportDescriptor(desc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d == nil {
+		t.Fatal("parseHIDReportDescriptor returned nil")
+	}
+	if got, want := len(d.buttons), 8; got != want {
+		t.Fatalf("len(buttons): got: %d, want: %d", got, want)
+	}
+	if got, want := len(d.hats), 1; got != want {
+		t.Fatalf("len(hats): got: %d, want: %d", got, want)
+	}
+	if got, want := len(d.axes), 2; got != want {
+		t.Fatalf("len(axes): got: %d, want: %d", got, want)
+	}
+
+	report := []byte{0x05, 0x02, 0x81, 0x7f}
+	for i, f := range d.buttons {
+		got := f.value(report) != 0
+		want := i == 0 || i == 2
+		if got != want {
+			t.Errorf("button %d: got: %v, want: %v", i, got, want)
+		}
+	}
+	if got, want := d.hats[0].hatState(report), hatRight; got != want {
+		t.Errorf("hat: got: %d, want: %d", got, want)
+	}
+	if got, want := d.axes[0].axisValue(report), -1.0; got != want {
+		t.Errorf("axis X: got: %f, want: %f", got, want)
+	}
+	if got, want := d.axes[1].axisValue(report), 1.0; got != want {
+		t.Errorf("axis Y: got: %f, want: %f", got, want)
+	}
+
+	// A null state of the hat switch.
+	report[1] = 0x08
+	if got, want := d.hats[0].hatState(report), hatCentered; got != want {
+		t.Errorf("hat: got: %d, want: %d", got, want)
+	}
+}
+
+func TestParseHIDReportDescriptorNotGamepad(t *testing.T) {
+	desc := []byte{
+		0x05, 0x01, // Usage Page (Generic Desktop)
+		0x09, 0x02, // Usage (Mouse)
+		0xa1, 0x01, // Collection (Application)
+		0x09, 0x30, // Usage (X)
+		0x15, 0x81, // Logical Minimum (-127)
+		0x25, 0x7f, // Logical Maximum (127)
+		0x75, 0x08, // Report Size (8)
+		0x95, 0x01, // Report Count (1)
+		0x81, 0x06, // Input (Data, Variable, Relative)
+		0xc0, // End Collection
+	}
+
+	d, err := parseHIDReportDescriptor(desc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d != nil {
+		t.Errorf("parseHIDReportDescriptor: got: %v, want: nil", d)
+	}
+}
+
+func TestParseHIDReportDescriptorTruncated(t *testing.T) {
+	if _, err := parseHIDReportDescriptor([]byte{0x05, 0x01, 0x26, 0xff}); err == nil {
+		t.Errorf("parseHIDReportDescriptor must return an error for a truncated descriptor")
+	}
+}
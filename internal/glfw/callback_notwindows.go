@@ -25,6 +25,8 @@ var (
 	charModsCallbacks        = map[CharModsCallback]glfw.CharModsCallback{}
 	closeCallbacks           = map[CloseCallback]glfw.CloseCallback{}
 	framebufferSizeCallbacks = map[FramebufferSizeCallback]glfw.FramebufferSizeCallback{}
+	keyCallbacks             = map[KeyCallback]glfw.KeyCallback{}
+	mouseButtonCallbacks     = map[MouseButtonCallback]glfw.MouseButtonCallback{}
 	scrollCallbacks          = map[ScrollCallback]glfw.ScrollCallback{}
 	sizeCallbacks            = map[SizeCallback]glfw.SizeCallback{}
 )
@@ -65,6 +67,30 @@ func ToFramebufferSizeCallback(cb func(window *Window, width int, height int)) F
 	return id
 }
 
+func ToKeyCallback(cb func(window *Window, key Key, scancode int, action Action, mods ModifierKey)) KeyCallback {
+	if cb == nil {
+		return 0
+	}
+	id := KeyCallback(len(keyCallbacks) + 1)
+	var gcb glfw.KeyCallback = func(window *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
+		cb(theWindows.get(window), Key(key), scancode, Action(action), ModifierKey(mods))
+	}
+	keyCallbacks[id] = gcb
+	return id
+}
+
+func ToMouseButtonCallback(cb func(window *Window, button MouseButton, action Action, mods ModifierKey)) MouseButtonCallback {
+	if cb == nil {
+		return 0
+	}
+	id := MouseButtonCallback(len(mouseButtonCallbacks) + 1)
+	var gcb glfw.MouseButtonCallback = func(window *glfw.Window, button glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey) {
+		cb(theWindows.get(window), MouseButton(button), Action(action), ModifierKey(mods))
+	}
+	mouseButtonCallbacks[id] = gcb
+	return id
+}
+
 func ToScrollCallback(cb func(window *Window, xoff float64, yoff float64)) ScrollCallback {
 	if cb == nil {
 		return 0
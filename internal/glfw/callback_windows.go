@@ -48,6 +48,26 @@ func ToFramebufferSizeCallback(cb func(window *Window, width int, height int)) F
 	}))
 }
 
+func ToKeyCallback(cb func(window *Window, key Key, scancode int, action Action, mods ModifierKey)) KeyCallback {
+	if cb == nil {
+		return 0
+	}
+	return KeyCallback(windows.NewCallbackCDecl(func(window uintptr, key Key, scancode int, action Action, mods ModifierKey) uintptr {
+		cb(theGLFWWindows.get(window), key, scancode, action, mods)
+		return 0
+	}))
+}
+
+func ToMouseButtonCallback(cb func(window *Window, button MouseButton, action Action, mods ModifierKey)) MouseButtonCallback {
+	if cb == nil {
+		return 0
+	}
+	return MouseButtonCallback(windows.NewCallbackCDecl(func(window uintptr, button MouseButton, action Action, mods ModifierKey) uintptr {
+		cb(theGLFWWindows.get(window), button, action, mods)
+		return 0
+	}))
+}
+
 func ToScrollCallback(cb func(window *Window, xoff float64, yoff float64)) ScrollCallback {
 	if cb == nil {
 		return 0
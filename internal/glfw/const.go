@@ -90,6 +90,7 @@ const (
 	Iconified              = Hint(0x00020002)
 	Maximized              = Hint(0x00020008)
 	Resizable              = Hint(0x00020003)
+	SRGBCapable            = Hint(0x0002100E)
 	TransparentFramebuffer = Hint(0x0002000A)
 	Visible                = Hint(0x00020004)
 )
@@ -98,6 +99,7 @@ const (
 	CursorMode             = InputMode(0x00033001)
 	StickyKeysMode         = InputMode(0x00033002)
 	StickyMouseButtonsMode = InputMode(0x00033003)
+	RawMouseMotion         = InputMode(0x00033005)
 )
 
 const (
@@ -12,8 +12,11 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-//go:build ebitenexternaldll
-// +build ebitenexternaldll
+//go:build ebitenexternaldll || (!386 && !amd64)
+// +build ebitenexternaldll !386,!amd64
+
+// GLFW DLLs are embedded only for 386 and amd64. For the other architectures like arm64,
+// glfw_windows_[GOARCH].dll must be placed where the DLL search path reaches, e.g. next to the executable.
 
 package glfw
 
@@ -75,6 +75,10 @@ func (m *Monitor) GetContentScale() (float32, float32) {
 	return m.m.GetContentScale()
 }
 
+func (m *Monitor) GetName() string {
+	return m.m.GetName()
+}
+
 func (m *Monitor) GetPos() (x, y int) {
 	return m.m.GetPos()
 }
@@ -113,6 +117,10 @@ func (w *Window) GetCursorPos() (x, y float64) {
 	return w.w.GetCursorPos()
 }
 
+func (w *Window) SetCursorPos(xpos, ypos float64) {
+	w.w.SetCursorPos(xpos, ypos)
+}
+
 func (w *Window) GetInputMode(mode InputMode) int {
 	return w.w.GetInputMode(glfw.InputMode(mode))
 }
@@ -188,6 +196,16 @@ func (w *Window) SetFramebufferSizeCallback(cbfun FramebufferSizeCallback) (prev
 	return ToFramebufferSizeCallback(nil) // TODO
 }
 
+func (w *Window) SetKeyCallback(cbfun KeyCallback) (previous KeyCallback) {
+	w.w.SetKeyCallback(keyCallbacks[cbfun])
+	return ToKeyCallback(nil) // TODO
+}
+
+func (w *Window) SetMouseButtonCallback(cbfun MouseButtonCallback) (previous MouseButtonCallback) {
+	w.w.SetMouseButtonCallback(mouseButtonCallbacks[cbfun])
+	return ToMouseButtonCallback(nil) // TODO
+}
+
 func (w *Window) SetScrollCallback(cbfun ScrollCallback) (previous ScrollCallback) {
 	w.w.SetScrollCallback(scrollCallbacks[cbfun])
 	return ToScrollCallback(nil) // TODO
@@ -317,6 +335,10 @@ func GetPrimaryMonitor() *Monitor {
 	return &Monitor{m}
 }
 
+func GetKeyName(key Key, scancode int) string {
+	return glfw.GetKeyName(glfw.Key(key), scancode)
+}
+
 func Init() error {
 	return glfw.Init()
 }
@@ -333,6 +355,10 @@ func PostEmptyEvent() {
 	glfw.PostEmptyEvent()
 }
 
+func RawMouseMotionSupported() bool {
+	return glfw.RawMouseMotionSupported()
+}
+
 func SetMonitorCallback(cbfun func(monitor *Monitor, event PeripheralEvent)) {
 	var gcb func(monitor *glfw.Monitor, event glfw.PeripheralEvent)
 	if cbfun != nil {
@@ -15,8 +15,10 @@
 package glfw
 
 import (
+	"fmt"
 	"image"
 	"image/draw"
+	"math"
 	"math/bits"
 	"reflect"
 	"runtime"
@@ -87,6 +89,29 @@ func (m *Monitor) GetContentScale() (float32, float32) {
 	return sx, sy
 }
 
+func (m *Monitor) GetName() string {
+	ptr := glfwDLL.call("glfwGetMonitorName", m.m)
+	panicError()
+
+	if ptr == 0 {
+		return ""
+	}
+
+	// The string is owned by GLFW on the C side, and the Go runtime never moves it.
+	var backed [256]byte
+	as := backed[:0]
+	for i := int32(0); ; i++ {
+		b := *(*byte)(unsafe.Pointer(ptr))
+		ptr += unsafe.Sizeof(byte(0))
+		if b == 0 {
+			break
+		}
+		as = append(as, b)
+	}
+	r := string(as)
+	return r
+}
+
 func (m *Monitor) GetPos() (int, int) {
 	var x, y int32
 	glfwDLL.call("glfwGetMonitorPos", m.m, uintptr(unsafe.Pointer(&x)), uintptr(unsafe.Pointer(&y)))
@@ -141,6 +166,54 @@ func (w *Window) GetCursorPos() (x, y float64) {
 	return
 }
 
+func (w *Window) SetCursorPos(xpos, ypos float64) {
+	x, y := math.Float64bits(xpos), math.Float64bits(ypos)
+	switch runtime.GOARCH {
+	case "amd64":
+		// On amd64, the arguments are copied to the XMM registers too, so doubles can be passed as integers.
+		glfwDLL.call("glfwSetCursorPos", w.w, uintptr(x), uintptr(y))
+	case "386":
+		// On 386, a double is passed as two 32-bit values on the stack.
+		glfwDLL.call("glfwSetCursorPos", w.w, uintptr(x), uintptr(x>>32), uintptr(y), uintptr(y>>32))
+	default:
+		// On the other architectures like arm64, doubles are passed in the floating-point registers,
+		// which a syscall cannot set. Move the cursor via Win32 instead, as GLFW does.
+		w.setCursorPosWin32(xpos, ypos)
+		return
+	}
+	panicError()
+}
+
+var (
+	user32             = windows.NewLazySystemDLL("user32.dll")
+	procClientToScreen = user32.NewProc("ClientToScreen")
+	procSetCursorPos   = user32.NewProc("SetCursorPos")
+)
+
+// setCursorPosWin32 moves the cursor to the position in the window's client area without calling glfwSetCursorPos.
+//
+// In the disabled cursor mode, GLFW's virtual cursor position cannot be updated from outside, so
+// setCursorPosWin32 does nothing.
+func (w *Window) setCursorPosWin32(xpos, ypos float64) {
+	if w.GetInputMode(CursorMode) == CursorDisabled {
+		return
+	}
+
+	pos := struct {
+		x int32
+		y int32
+	}{
+		x: int32(xpos),
+		y: int32(ypos),
+	}
+	if r, _, e := procClientToScreen.Call(w.GetWin32Window(), uintptr(unsafe.Pointer(&pos))); r == 0 {
+		panic(fmt.Sprintf("glfw: ClientToScreen failed: %v", e))
+	}
+	if r, _, e := procSetCursorPos.Call(uintptr(pos.x), uintptr(pos.y)); r == 0 {
+		panic(fmt.Sprintf("glfw: SetCursorPos failed: %v", e))
+	}
+}
+
 func (w *Window) GetInputMode(mode InputMode) int {
 	r := glfwDLL.call("glfwGetInputMode", w.w, uintptr(mode))
 	panicError()
@@ -233,6 +306,18 @@ func (w *Window) SetFramebufferSizeCallback(cbfun FramebufferSizeCallback) (prev
 	return ToFramebufferSizeCallback(nil) // TODO
 }
 
+func (w *Window) SetKeyCallback(cbfun KeyCallback) (previous KeyCallback) {
+	glfwDLL.call("glfwSetKeyCallback", w.w, uintptr(cbfun))
+	panicError()
+	return ToKeyCallback(nil) // TODO
+}
+
+func (w *Window) SetMouseButtonCallback(cbfun MouseButtonCallback) (previous MouseButtonCallback) {
+	glfwDLL.call("glfwSetMouseButtonCallback", w.w, uintptr(cbfun))
+	panicError()
+	return ToMouseButtonCallback(nil) // TODO
+}
+
 func (w *Window) SetScrollCallback(cbfun ScrollCallback) (previous ScrollCallback) {
 	glfwDLL.call("glfwSetScrollCallback", w.w, uintptr(cbfun))
 	panicError()
@@ -476,6 +561,29 @@ func GetPrimaryMonitor() *Monitor {
 	return &Monitor{m}
 }
 
+func GetKeyName(key Key, scancode int) string {
+	ptr := glfwDLL.call("glfwGetKeyName", uintptr(key), uintptr(scancode))
+	panicError()
+
+	// ptr is nil for non-printable keys.
+	if ptr == 0 {
+		return ""
+	}
+
+	var backed [16]byte
+	as := backed[:0]
+	for i := int32(0); ; i++ {
+		b := *(*byte)(unsafe.Pointer(ptr))
+		ptr += unsafe.Sizeof(byte(0))
+		if b == 0 {
+			break
+		}
+		as = append(as, b)
+	}
+	r := string(as)
+	return r
+}
+
 func Init() error {
 	glfwDLL.call("glfwInit")
 	// InvalidValue can happen when specific joysticks are used. This issue
@@ -520,6 +628,12 @@ func PostEmptyEvent() {
 	panicError()
 }
 
+func RawMouseMotionSupported() bool {
+	r := glfwDLL.call("glfwRawMouseMotionSupported")
+	panicError()
+	return byte(r) == True
+}
+
 func SetMonitorCallback(cbfun func(monitor *Monitor, event PeripheralEvent)) {
 	var gcb uintptr
 	if cbfun != nil {
@@ -0,0 +1,32 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !android && !darwin && !js && !windows
+// +build !android,!darwin,!js,!windows
+
+package glfw
+
+import (
+	"unsafe"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+func (w *Window) GetX11Window() uintptr {
+	return uintptr(w.w.GetX11Window())
+}
+
+func GetX11Display() uintptr {
+	return uintptr(unsafe.Pointer(glfw.GetX11Display()))
+}
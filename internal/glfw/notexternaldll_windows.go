@@ -12,8 +12,9 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-//go:build !ebitenexternaldll
+//go:build !ebitenexternaldll && (386 || amd64)
 // +build !ebitenexternaldll
+// +build 386 amd64
 
 package glfw
 
@@ -21,6 +21,8 @@ type (
 	CharModsCallback        uintptr
 	CloseCallback           uintptr
 	FramebufferSizeCallback uintptr
+	KeyCallback             uintptr
+	MouseButtonCallback     uintptr
 	ScrollCallback          uintptr
 	SizeCallback            uintptr
 )
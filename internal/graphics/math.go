@@ -15,6 +15,10 @@
 package graphics
 
 // InternalImageSize returns a nearest appropriate size as an internal image.
+//
+// The size is not rounded up to a power of two. All the graphics drivers support non-power-of-two textures as
+// Ebiten's textures don't use mipmaps or repeating wrap modes, which is the condition for non-power-of-two textures
+// in OpenGL ES 2 and WebGL 1. The atlas textures are still powers of two due to the packing algorithm.
 func InternalImageSize(x int) int {
 	// minInternalImageSize is the minimum size of internal images (texture/framebuffer).
 	//
@@ -28,9 +32,5 @@ func InternalImageSize(x int) int {
 	if x < minInternalImageSize {
 		return minInternalImageSize
 	}
-	r := 1
-	for r < x {
-		r <<= 1
-	}
-	return r
+	return x
 }
@@ -25,9 +25,13 @@ func TestInternalImageSize(t *testing.T) {
 		expected int
 		arg      int
 	}{
-		{256, 255},
+		{16, 1},
+		{16, 15},
+		{16, 16},
+		{17, 17},
+		{255, 255},
 		{256, 256},
-		{512, 257},
+		{257, 257},
 	}
 
 	for _, testCase := range testCases {
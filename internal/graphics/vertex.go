@@ -38,11 +38,23 @@ const (
 	TextureSourceOffsetsUniformVariableIndex           = 4
 	TextureSourceRegionOriginUniformVariableIndex      = 5
 	TextureSourceRegionSizeUniformVariableIndex        = 6
+
+	// DefaultShaderUniformVariablesNum represents the number of the optional uniform variables for the default shader,
+	// which is used when no shader is specified.
+	DefaultShaderUniformVariablesNum = 1 + // the geometry matrix {a, b, c, d, tx, ty} for the destination positions
+		1 // the color scale {r, g, b, a} for the vertex colors
+
+	DefaultShaderGeoMUniformVariableIndex       = 0
+	DefaultShaderColorScaleUniformVariableIndex = 1
 )
 
 const (
 	IndicesNum     = (1 << 16) / 3 * 3 // Adjust num for triangles.
-	VertexFloatNum = 8
+	VertexFloatNum = 9
+
+	// VertexDepthIndex is the index of the depth value in a vertex.
+	// The depth value is used only when depth testing is enabled.
+	VertexDepthIndex = 8
 )
 
 var (
@@ -55,6 +67,7 @@ func QuadIndices() []uint16 {
 
 var (
 	theVerticesBackend = &verticesBackend{}
+	theIndicesBackend  = &indicesBackend{}
 )
 
 // TODO: The logic is very similar to atlas.temporaryPixels. Unify them.
@@ -122,6 +135,62 @@ func (v *verticesBackend) lockAndReset(f func() error) error {
 	return nil
 }
 
+// indicesBackend is a backend for index slices. The logic is the same as verticesBackend.
+type indicesBackend struct {
+	backend          []uint16
+	pos              int
+	notFullyUsedTime int
+
+	m sync.Mutex
+}
+
+func indicesBackendUint16Size(size int) int {
+	l := 128 * 6
+	for l < size {
+		l *= 2
+	}
+	return l
+}
+
+func (i *indicesBackend) slice(n int) []uint16 {
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	if len(i.backend) < i.pos+n {
+		i.backend = make([]uint16, max(len(i.backend)*2, indicesBackendUint16Size(n)))
+		i.pos = 0
+	}
+	s := i.backend[i.pos : i.pos+n]
+	i.pos += n
+	return s
+}
+
+func (i *indicesBackend) lockAndReset(f func() error) error {
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	if err := f(); err != nil {
+		return err
+	}
+
+	const maxNotFullyUsedTime = 60
+	if indicesBackendUint16Size(i.pos) < len(i.backend) {
+		if i.notFullyUsedTime < maxNotFullyUsedTime {
+			i.notFullyUsedTime++
+		}
+	} else {
+		i.notFullyUsedTime = 0
+	}
+
+	if i.notFullyUsedTime == maxNotFullyUsedTime && len(i.backend) > 0 {
+		i.backend = nil
+		i.notFullyUsedTime = 0
+	}
+
+	i.pos = 0
+	return nil
+}
+
 // Vertices returns a float32 slice for n vertices.
 // Vertices returns a slice that never overlaps with other slices returned this function,
 // and users can do optimization based on this fact.
@@ -129,8 +198,18 @@ func Vertices(n int) []float32 {
 	return theVerticesBackend.slice(n)
 }
 
+// Indices returns a uint16 slice for n indices.
+// Indices returns a slice that never overlaps with other slices returned this function.
+// The slice is available until the end of the current frame.
+func Indices(n int) []uint16 {
+	return theIndicesBackend.slice(n)
+}
+
+// LockAndResetVertices locks the backends of the vertices and the indices while f is called, and resets them after that.
 func LockAndResetVertices(f func() error) error {
-	return theVerticesBackend.lockAndReset(f)
+	return theVerticesBackend.lockAndReset(func() error {
+		return theIndicesBackend.lockAndReset(f)
+	})
 }
 
 // QuadVertices returns a float32 slice for a quadrangle.
@@ -156,33 +235,37 @@ func QuadVertices(sx0, sy0, sx1, sy1 float32, a, b, c, d, tx, ty float32, cr, cg
 	vs[5] = cg
 	vs[6] = cb
 	vs[7] = ca
+	vs[8] = 0
+
+	vs[9] = ax + tx
+	vs[10] = cx + ty
+	vs[11] = u1
+	vs[12] = v0
+	vs[13] = cr
+	vs[14] = cg
+	vs[15] = cb
+	vs[16] = ca
+	vs[17] = 0
+
+	vs[18] = by + tx
+	vs[19] = dy + ty
+	vs[20] = u0
+	vs[21] = v1
+	vs[22] = cr
+	vs[23] = cg
+	vs[24] = cb
+	vs[25] = ca
+	vs[26] = 0
 
-	vs[8] = ax + tx
-	vs[9] = cx + ty
-	vs[10] = u1
-	vs[11] = v0
-	vs[12] = cr
-	vs[13] = cg
-	vs[14] = cb
-	vs[15] = ca
-
-	vs[16] = by + tx
-	vs[17] = dy + ty
-	vs[18] = u0
-	vs[19] = v1
-	vs[20] = cr
-	vs[21] = cg
-	vs[22] = cb
-	vs[23] = ca
-
-	vs[24] = ax + by + tx
-	vs[25] = cx + dy + ty
-	vs[26] = u1
-	vs[27] = v1
-	vs[28] = cr
-	vs[29] = cg
-	vs[30] = cb
-	vs[31] = ca
+	vs[27] = ax + by + tx
+	vs[28] = cx + dy + ty
+	vs[29] = u1
+	vs[30] = v1
+	vs[31] = cr
+	vs[32] = cg
+	vs[33] = cb
+	vs[34] = ca
+	vs[35] = 0
 
 	return vs
 }
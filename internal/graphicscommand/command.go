@@ -18,12 +18,15 @@ import (
 	"fmt"
 	"math"
 	"strings"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2/internal/affine"
 	"github.com/hajimehoshi/ebiten/v2/internal/debug"
+	"github.com/hajimehoshi/ebiten/v2/internal/frametiming"
 	"github.com/hajimehoshi/ebiten/v2/internal/graphics"
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
 	"github.com/hajimehoshi/ebiten/v2/internal/shaderir"
+	"github.com/hajimehoshi/ebiten/v2/internal/tracing"
 )
 
 // command represents a drawing command.
@@ -41,6 +44,10 @@ type command interface {
 type size struct {
 	width  float32
 	height float32
+
+	// transformedOnGPU indicates whether the destination position is transformed by the default shader's geometry
+	// matrix. Such a position is not aligned on CPU, as the alignment would be transformed too.
+	transformedOnGPU bool
 }
 
 type drawTrianglesCommandPool struct {
@@ -89,6 +96,9 @@ type commandQueue struct {
 
 	drawTrianglesCommandPool drawTrianglesCommandPool
 
+	// execIndex is the index of the command being executed in flush, or -1 before any command is executed.
+	execIndex int
+
 	err error
 }
 
@@ -96,7 +106,7 @@ type commandQueue struct {
 var theCommandQueue = &commandQueue{}
 
 // appendVertices appends vertices to the queue.
-func (q *commandQueue) appendVertices(vertices []float32, src *Image) {
+func (q *commandQueue) appendVertices(vertices []float32, src *Image, transformedOnGPU bool) {
 	if len(q.vertices) < q.nvertices+len(vertices) {
 		n := q.nvertices + len(vertices) - len(q.vertices)
 		q.vertices = append(q.vertices, make([]float32, n)...)
@@ -119,6 +129,7 @@ func (q *commandQueue) appendVertices(vertices []float32, src *Image) {
 		idx := base + i
 		q.srcSizes[idx].width = width
 		q.srcSizes[idx].height = height
+		q.srcSizes[idx].transformedOnGPU = transformedOnGPU
 	}
 	q.nvertices += len(vertices)
 }
@@ -140,7 +151,7 @@ func mustUseDifferentVertexBuffer(nextNumVertexFloats, nextNumIndices int) bool
 }
 
 // EnqueueDrawTrianglesCommand enqueues a drawing-image command.
-func (q *commandQueue) EnqueueDrawTrianglesCommand(dst *Image, srcs [graphics.ShaderImageNum]*Image, offsets [graphics.ShaderImageNum - 1][2]float32, vertices []float32, indices []uint16, color affine.ColorM, mode graphicsdriver.CompositeMode, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, shader *Shader, uniforms []graphicsdriver.Uniform, evenOdd bool) {
+func (q *commandQueue) EnqueueDrawTrianglesCommand(dst *Image, srcs [graphics.ShaderImageNum]*Image, offsets [graphics.ShaderImageNum - 1][2]float32, vertices []float32, indices []uint16, color affine.ColorM, mode graphicsdriver.CompositeMode, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, shader *Shader, uniforms []graphicsdriver.Uniform, evenOdd bool, depthTest bool) {
 	if len(indices) > graphics.IndicesNum {
 		panic(fmt.Sprintf("graphicscommand: len(indices) must be <= graphics.IndicesNum but not at EnqueueDrawTrianglesCommand: len(indices): %d, graphics.IndicesNum: %d", len(indices), graphics.IndicesNum))
 	}
@@ -154,7 +165,7 @@ func (q *commandQueue) EnqueueDrawTrianglesCommand(dst *Image, srcs [graphics.Sh
 
 	// Assume that all the image sizes are same.
 	// Assume that the images are packed from the front in the slice srcs.
-	q.appendVertices(vertices, srcs[0])
+	q.appendVertices(vertices, srcs[0], shader == nil && len(uniforms) > 0)
 	q.appendIndices(indices, uint16(q.tmpNumVertexFloats/graphics.VertexFloatNum))
 	q.tmpNumVertexFloats += len(vertices)
 	q.tmpNumIndices += len(indices)
@@ -173,9 +184,8 @@ func (q *commandQueue) EnqueueDrawTrianglesCommand(dst *Image, srcs [graphics.Sh
 
 	// TODO: If dst is the screen, reorder the command to be the last.
 	if !split && 0 < len(q.commands) {
-		// TODO: Pass offsets and uniforms when merging considers the shader.
 		if last, ok := q.commands[len(q.commands)-1].(*drawTrianglesCommand); ok {
-			if last.CanMergeWithDrawTrianglesCommand(dst, srcs, vertices, color, mode, filter, address, dstRegion, srcRegion, shader, evenOdd) {
+			if last.CanMergeWithDrawTrianglesCommand(dst, srcs, offsets, vertices, color, mode, filter, address, dstRegion, srcRegion, shader, uniforms, evenOdd, depthTest) {
 				last.setVertices(q.lastVertices(len(vertices) + last.numVertices()))
 				last.addNumIndices(len(indices))
 				return
@@ -198,6 +208,7 @@ func (q *commandQueue) EnqueueDrawTrianglesCommand(dst *Image, srcs [graphics.Sh
 	c.shader = shader
 	c.uniforms = uniforms
 	c.evenOdd = evenOdd
+	c.depthTest = depthTest
 	q.commands = append(q.commands, c)
 }
 
@@ -215,26 +226,49 @@ func (q *commandQueue) Enqueue(command command) {
 
 // Flush flushes the command queue.
 func (q *commandQueue) Flush() (err error) {
+	t := time.Now()
 	runOnRenderingThread(func() {
+		defer tracing.StartRegion("ebiten.flush")()
+		defer func() {
+			// Annotate a panic in the graphics driver so that the bug report is actionable.
+			if r := recover(); r != nil {
+				err, ok := r.(error)
+				if !ok {
+					err = fmt.Errorf("%v", r)
+				}
+				panic(newDriverError(err, q.commands[:q.execIndex+1]))
+			}
+		}()
 		err = q.flush()
 	})
+	frametiming.Add(frametiming.PhaseGPU, time.Since(t))
 	return
 }
 
-// flush must be called the main thread.
-func (q *commandQueue) flush() error {
-	if len(q.commands) == 0 {
-		return nil
+// parallelAdjustVerticesThreshold is the number of vertices to adjust vertices in parallel.
+// Below this, the cost to dispatch the work to the worker pool is bigger than the gain.
+const parallelAdjustVerticesThreshold = 16384
+
+// adjustVertices converts the source positions from pixels to texels and adjusts the destination positions
+// if necessary for vertices before sending them to GPU.
+//
+// adjustVertices processes vertices in the worker pool in parallel when there are a lot of vertices.
+func adjustVertices(vs []float32, srcSizes []size, highPrecision bool) {
+	n := len(vs) / graphics.VertexFloatNum
+	if n < parallelAdjustVerticesThreshold || theWorkerPool.workers() <= 1 {
+		adjustVerticesInRange(vs, srcSizes, highPrecision, 0, n)
+		return
 	}
 
-	es := q.indices
-	vs := q.vertices
-	debug.Logf("Graphics commands:\n")
+	theWorkerPool.run(n, func(start, end int) {
+		adjustVerticesInRange(vs, srcSizes, highPrecision, start, end)
+	})
+}
 
-	if graphicsDriver().HasHighPrecisionFloat() {
-		n := q.nvertices / graphics.VertexFloatNum
-		for i := 0; i < n; i++ {
-			s := q.srcSizes[i]
+func adjustVerticesInRange(vs []float32, srcSizes []size, highPrecision bool, start, end int) {
+	if highPrecision {
+		for i := start; i < end; i++ {
+			s := srcSizes[i]
 
 			idx := i * graphics.VertexFloatNum
 
@@ -242,6 +276,10 @@ func (q *commandQueue) flush() error {
 			vs[idx+2] /= s.width
 			vs[idx+3] /= s.height
 
+			if s.transformedOnGPU {
+				continue
+			}
+
 			// Avoid the center of the pixel, which is problematic (#929, #1171).
 			// Instead, align the vertices with about 1/3 pixels.
 			x := vs[idx]
@@ -271,17 +309,31 @@ func (q *commandQueue) flush() error {
 				vs[idx+1] = iy + 16.0/16.0
 			}
 		}
-	} else {
-		n := q.nvertices / graphics.VertexFloatNum
-		for i := 0; i < n; i++ {
-			s := q.srcSizes[i]
+		return
+	}
 
-			// Convert pixels to texels.
-			vs[i*graphics.VertexFloatNum+2] /= s.width
-			vs[i*graphics.VertexFloatNum+3] /= s.height
-		}
+	for i := start; i < end; i++ {
+		s := srcSizes[i]
+
+		// Convert pixels to texels.
+		vs[i*graphics.VertexFloatNum+2] /= s.width
+		vs[i*graphics.VertexFloatNum+3] /= s.height
+	}
+}
+
+// flush must be called the main thread.
+func (q *commandQueue) flush() error {
+	q.execIndex = -1
+	if len(q.commands) == 0 {
+		return nil
 	}
 
+	es := q.indices
+	vs := q.vertices
+	debug.Logf("Graphics commands:\n")
+
+	adjustVertices(vs[:q.nvertices], q.srcSizes, graphicsDriver().HasHighPrecisionFloat())
+
 	graphicsDriver().Begin()
 	var present bool
 	cs := q.commands
@@ -289,6 +341,7 @@ func (q *commandQueue) flush() error {
 		nv := 0
 		ne := 0
 		nc := 0
+		var depth bool
 		for _, c := range cs {
 			if dtc, ok := c.(*drawTrianglesCommand); ok {
 				if dtc.numIndices() > graphics.IndicesNum {
@@ -302,18 +355,25 @@ func (q *commandQueue) flush() error {
 				if dtc.dst.screen {
 					present = true
 				}
+				if dtc.depthTest {
+					depth = true
+				}
 			}
 			nc++
 		}
 		if 0 < ne {
-			graphicsDriver().SetVertices(vs[:nv], es[:ne])
+			graphicsDriver().SetVertices(vs[:nv], es[:ne], depth)
 			es = es[ne:]
 			vs = vs[nv:]
 		}
 		indexOffset := 0
-		for _, c := range cs[:nc] {
+		for i, c := range cs[:nc] {
+			q.execIndex = len(q.commands) - len(cs) + i
 			if err := c.Exec(indexOffset); err != nil {
-				return err
+				if err == graphicsdriver.GraphicsNotReady {
+					return err
+				}
+				return newDriverError(err, q.commands[:q.execIndex+1])
 			}
 			debug.Logf("  %s\n", c)
 			// TODO: indexOffset should be reset if the command type is different
@@ -365,74 +425,17 @@ type drawTrianglesCommand struct {
 	shader    *Shader
 	uniforms  []graphicsdriver.Uniform
 	evenOdd   bool
+	depthTest bool
 }
 
 func (c *drawTrianglesCommand) String() string {
-	mode := ""
-	switch c.mode {
-	case graphicsdriver.CompositeModeSourceOver:
-		mode = "source-over"
-	case graphicsdriver.CompositeModeClear:
-		mode = "clear"
-	case graphicsdriver.CompositeModeCopy:
-		mode = "copy"
-	case graphicsdriver.CompositeModeDestination:
-		mode = "destination"
-	case graphicsdriver.CompositeModeDestinationOver:
-		mode = "destination-over"
-	case graphicsdriver.CompositeModeSourceIn:
-		mode = "source-in"
-	case graphicsdriver.CompositeModeDestinationIn:
-		mode = "destination-in"
-	case graphicsdriver.CompositeModeSourceOut:
-		mode = "source-out"
-	case graphicsdriver.CompositeModeDestinationOut:
-		mode = "destination-out"
-	case graphicsdriver.CompositeModeSourceAtop:
-		mode = "source-atop"
-	case graphicsdriver.CompositeModeDestinationAtop:
-		mode = "destination-atop"
-	case graphicsdriver.CompositeModeXor:
-		mode = "xor"
-	case graphicsdriver.CompositeModeLighter:
-		mode = "lighter"
-	case graphicsdriver.CompositeModeMultiply:
-		mode = "multiply"
-	default:
-		panic(fmt.Sprintf("graphicscommand: invalid composite mode: %d", c.mode))
-	}
-
 	dst := fmt.Sprintf("%d", c.dst.id)
 	if c.dst.screen {
 		dst += " (screen)"
 	}
 
 	if c.shader != nil {
-		return fmt.Sprintf("draw-triangles: dst: %s, shader, num of indices: %d, mode %s", dst, c.nindices, mode)
-	}
-
-	filter := ""
-	switch c.filter {
-	case graphicsdriver.FilterNearest:
-		filter = "nearest"
-	case graphicsdriver.FilterLinear:
-		filter = "linear"
-	case graphicsdriver.FilterScreen:
-		filter = "screen"
-	default:
-		panic(fmt.Sprintf("graphicscommand: invalid filter: %d", c.filter))
-	}
-
-	address := ""
-	switch c.address {
-	case graphicsdriver.AddressClampToZero:
-		address = "clamp_to_zero"
-	case graphicsdriver.AddressRepeat:
-		address = "repeat"
-	case graphicsdriver.AddressUnsafe:
-		address = "unsafe"
-	default:
-		panic(fmt.Sprintf("graphicscommand: invalid address: %d", c.address))
+		return fmt.Sprintf("draw-triangles: dst: %s, shader, num of indices: %d, mode %s", dst, c.nindices, c.mode)
 	}
 
 	var srcstrs [graphics.ShaderImageNum]string
@@ -449,7 +452,7 @@ func (c *drawTrianglesCommand) String() string {
 
 	r := fmt.Sprintf("(x:%d, y:%d, width:%d, height:%d)",
 		int(c.dstRegion.X), int(c.dstRegion.Y), int(c.dstRegion.Width), int(c.dstRegion.Height))
-	return fmt.Sprintf("draw-triangles: dst: %s <- src: [%s], dst region: %s, num of indices: %d, colorm: %v, mode: %s, filter: %s, address: %s, even-odd: %t", dst, strings.Join(srcstrs[:], ", "), r, c.nindices, c.color, mode, filter, address, c.evenOdd)
+	return fmt.Sprintf("draw-triangles: dst: %s <- src: [%s], dst region: %s, num of indices: %d, colorm: %v, mode: %s, filter: %s, address: %s, even-odd: %t, depth-test: %t", dst, strings.Join(srcstrs[:], ", "), r, c.nindices, c.color, c.mode, c.filter, c.address, c.evenOdd, c.depthTest)
 }
 
 // Exec executes the drawTrianglesCommand.
@@ -474,7 +477,7 @@ func (c *drawTrianglesCommand) Exec(indexOffset int) error {
 		imgs[0] = c.srcs[0].image.ID()
 	}
 
-	return graphicsDriver().DrawTriangles(c.dst.image.ID(), imgs, c.offsets, shaderID, c.nindices, indexOffset, c.mode, c.color, c.filter, c.address, c.dstRegion, c.srcRegion, c.uniforms, c.evenOdd)
+	return graphicsDriver().DrawTriangles(c.dst.image.ID(), imgs, c.offsets, shaderID, c.nindices, indexOffset, c.mode, c.color, c.filter, c.address, c.dstRegion, c.srcRegion, c.uniforms, c.evenOdd, c.depthTest)
 }
 
 func (c *drawTrianglesCommand) numVertices() int {
@@ -495,11 +498,18 @@ func (c *drawTrianglesCommand) addNumIndices(n int) {
 
 // CanMergeWithDrawTrianglesCommand returns a boolean value indicating whether the other drawTrianglesCommand can be merged
 // with the drawTrianglesCommand c.
-func (c *drawTrianglesCommand) CanMergeWithDrawTrianglesCommand(dst *Image, srcs [graphics.ShaderImageNum]*Image, vertices []float32, color affine.ColorM, mode graphicsdriver.CompositeMode, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, shader *Shader, evenOdd bool) bool {
-	// If a shader is used, commands are not merged.
-	//
-	// TODO: Merge shader commands considering uniform variables.
-	if c.shader != nil || shader != nil {
+func (c *drawTrianglesCommand) CanMergeWithDrawTrianglesCommand(dst *Image, srcs [graphics.ShaderImageNum]*Image, offsets [graphics.ShaderImageNum - 1][2]float32, vertices []float32, color affine.ColorM, mode graphicsdriver.CompositeMode, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, shader *Shader, uniforms []graphicsdriver.Uniform, evenOdd bool, depthTest bool) bool {
+	// Commands with a shader can be merged only when all the shader inputs other than vertices are the same.
+	// This is also true for the default shader's uniform variables.
+	if c.shader != shader {
+		return false
+	}
+	if shader != nil {
+		if c.offsets != offsets {
+			return false
+		}
+	}
+	if !areSameUniforms(c.uniforms, uniforms) {
 		return false
 	}
 	if c.dst != dst {
@@ -526,6 +536,10 @@ func (c *drawTrianglesCommand) CanMergeWithDrawTrianglesCommand(dst *Image, srcs
 	if c.srcRegion != srcRegion {
 		return false
 	}
+	// Triangles with depth testing can be merged regardless of their order, as the depth buffer sorts them.
+	if c.depthTest != depthTest {
+		return false
+	}
 	if c.evenOdd || evenOdd {
 		if c.evenOdd && evenOdd {
 			return !mightOverlapDstRegions(c.vertices, vertices)
@@ -535,6 +549,26 @@ func (c *drawTrianglesCommand) CanMergeWithDrawTrianglesCommand(dst *Image, srcs
 	return true
 }
 
+func areSameUniforms(a, b []graphicsdriver.Uniform) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Float32 != b[i].Float32 {
+			return false
+		}
+		if len(a[i].Float32s) != len(b[i].Float32s) {
+			return false
+		}
+		for j := range a[i].Float32s {
+			if a[i].Float32s[j] != b[i].Float32s[j] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 var (
 	posInf32 = float32(math.Inf(1))
 	negInf32 = float32(math.Inf(-1))
@@ -588,6 +622,60 @@ func (c *replacePixelsCommand) Exec(indexOffset int) error {
 	return nil
 }
 
+// clearDepthCommand represents a command to reset the depth buffer of an image.
+type clearDepthCommand struct {
+	dst *Image
+}
+
+func (c *clearDepthCommand) String() string {
+	return fmt.Sprintf("clear-depth: dst: %d", c.dst.id)
+}
+
+// Exec executes the clearDepthCommand.
+func (c *clearDepthCommand) Exec(indexOffset int) error {
+	return c.dst.image.ClearDepth()
+}
+
+// copyPixelsCommand represents a command to copy a region of an image to another image.
+type copyPixelsCommand struct {
+	dst    *Image
+	src    *Image
+	dstX   int
+	dstY   int
+	srcX   int
+	srcY   int
+	width  int
+	height int
+}
+
+func (c *copyPixelsCommand) String() string {
+	return fmt.Sprintf("copy-pixels: dst: %d (%d, %d), src: %d (%d, %d), size: (%d, %d)", c.dst.id, c.dstX, c.dstY, c.src.id, c.srcX, c.srcY, c.width, c.height)
+}
+
+// Exec executes the copyPixelsCommand.
+func (c *copyPixelsCommand) Exec(indexOffset int) error {
+	return graphicsDriver().CopyPixels(c.dst.image.ID(), c.src.image.ID(), c.dstX, c.dstY, c.srcX, c.srcY, c.width, c.height)
+}
+
+// fillRectCommand represents a command to fill a region of an image with a solid color.
+type fillRectCommand struct {
+	dst    *Image
+	x      int
+	y      int
+	width  int
+	height int
+	color  [4]float32
+}
+
+func (c *fillRectCommand) String() string {
+	return fmt.Sprintf("fill-rect: dst: %d (%d, %d), size: (%d, %d), color: %v", c.dst.id, c.x, c.y, c.width, c.height, c.color)
+}
+
+// Exec executes the fillRectCommand.
+func (c *fillRectCommand) Exec(indexOffset int) error {
+	return graphicsDriver().FillRect(c.dst.image.ID(), c.x, c.y, c.width, c.height, c.color[0], c.color[1], c.color[2], c.color[3])
+}
+
 type pixelsCommand struct {
 	result []byte
 	img    *Image
@@ -656,6 +744,63 @@ func (c *newImageCommand) Exec(indexOffset int) error {
 	return nil
 }
 
+// newCompressedImageCommand represents a command to create an image from compressed texture data.
+type newCompressedImageCommand struct {
+	result *Image
+	width  int
+	height int
+	format graphicsdriver.CompressedTextureFormat
+	data   []byte
+}
+
+func (c *newCompressedImageCommand) String() string {
+	return fmt.Sprintf("new-compressed-image: result: %d, width: %d, height: %d, format: %s", c.result.id, c.width, c.height, c.format)
+}
+
+// Exec executes a newCompressedImageCommand.
+func (c *newCompressedImageCommand) Exec(indexOffset int) error {
+	g, ok := graphicsDriver().(interface {
+		NewCompressedImage(width, height int, format graphicsdriver.CompressedTextureFormat, data []byte) (graphicsdriver.Image, error)
+	})
+	if !ok {
+		return fmt.Errorf("graphicscommand: the graphics driver doesn't support compressed textures")
+	}
+	i, err := g.NewCompressedImage(c.width, c.height, c.format, c.data)
+	if err != nil {
+		return err
+	}
+	c.result.image = i
+	return nil
+}
+
+// newNativeImageCommand represents a command to create an image wrapping a native texture.
+type newNativeImageCommand struct {
+	result  *Image
+	width   int
+	height  int
+	texture graphicsdriver.NativeTexture
+}
+
+func (c *newNativeImageCommand) String() string {
+	return fmt.Sprintf("new-native-image: result: %d, width: %d, height: %d, kind: %s", c.result.id, c.width, c.height, c.texture.Kind)
+}
+
+// Exec executes a newNativeImageCommand.
+func (c *newNativeImageCommand) Exec(indexOffset int) error {
+	g, ok := graphicsDriver().(interface {
+		NewNativeImage(width, height int, texture graphicsdriver.NativeTexture) (graphicsdriver.Image, error)
+	})
+	if !ok {
+		return fmt.Errorf("graphicscommand: the graphics driver doesn't support native textures")
+	}
+	i, err := g.NewNativeImage(c.width, c.height, c.texture)
+	if err != nil {
+		return err
+	}
+	c.result.image = i
+	return nil
+}
+
 // newScreenFramebufferImageCommand is a command to create a special image for the screen.
 type newScreenFramebufferImageCommand struct {
 	result *Image
@@ -688,6 +833,7 @@ func (c *newShaderCommand) String() string {
 func (c *newShaderCommand) Exec(indexOffset int) error {
 	var err error
 	c.result.shader, err = graphicsDriver().NewShader(c.ir)
+	frametiming.AddEvent(frametiming.EventShaderCompilation)
 	return err
 }
 
@@ -710,6 +856,22 @@ func ResetGraphicsDriverState() (err error) {
 	return nil
 }
 
+// IsCompressedTextureFormatSupported reports whether the graphics driver can create an image with the given
+// compressed texture format.
+func IsCompressedTextureFormatSupported(format graphicsdriver.CompressedTextureFormat) bool {
+	var supported bool
+	runOnRenderingThread(func() {
+		g, ok := graphicsDriver().(interface {
+			IsCompressedTextureFormatSupported(format graphicsdriver.CompressedTextureFormat) bool
+		})
+		if !ok {
+			return
+		}
+		supported = g.IsCompressedTextureFormatSupported(format)
+	})
+	return supported
+}
+
 // MaxImageSize returns the maximum size of an image.
 func MaxImageSize() int {
 	var size int
@@ -0,0 +1,171 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphicscommand
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/affine"
+	"github.com/hajimehoshi/ebiten/v2/internal/graphics"
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
+)
+
+func TestCanMergeWithDrawTrianglesCommand(t *testing.T) {
+	dst := &Image{}
+	src0 := &Image{}
+	src1 := &Image{}
+	shader0 := &Shader{}
+	shader1 := &Shader{}
+	region := graphicsdriver.Region{X: 0, Y: 0, Width: 16, Height: 16}
+
+	type input struct {
+		srcs     [graphics.ShaderImageNum]*Image
+		offsets  [graphics.ShaderImageNum - 1][2]float32
+		shader   *Shader
+		uniforms []graphicsdriver.Uniform
+	}
+	base := input{
+		srcs:     [graphics.ShaderImageNum]*Image{src0},
+		shader:   shader0,
+		uniforms: []graphicsdriver.Uniform{{Float32: 1}, {Float32s: []float32{1, 2}}},
+	}
+
+	tests := []struct {
+		name string
+		in   input
+		want bool
+	}{
+		{
+			name: "same",
+			in: input{
+				srcs:     base.srcs,
+				shader:   shader0,
+				uniforms: []graphicsdriver.Uniform{{Float32: 1}, {Float32s: []float32{1, 2}}},
+			},
+			want: true,
+		},
+		{
+			name: "different shader",
+			in: input{
+				srcs:     base.srcs,
+				shader:   shader1,
+				uniforms: base.uniforms,
+			},
+			want: false,
+		},
+		{
+			name: "default shader",
+			in: input{
+				srcs:     base.srcs,
+				uniforms: base.uniforms,
+			},
+			want: false,
+		},
+		{
+			name: "different source",
+			in: input{
+				srcs:     [graphics.ShaderImageNum]*Image{src1},
+				shader:   shader0,
+				uniforms: base.uniforms,
+			},
+			want: false,
+		},
+		{
+			name: "different offsets",
+			in: input{
+				srcs:     base.srcs,
+				offsets:  [graphics.ShaderImageNum - 1][2]float32{{1, 0}},
+				shader:   shader0,
+				uniforms: base.uniforms,
+			},
+			want: false,
+		},
+		{
+			name: "different uniform",
+			in: input{
+				srcs:     base.srcs,
+				shader:   shader0,
+				uniforms: []graphicsdriver.Uniform{{Float32: 2}, {Float32s: []float32{1, 2}}},
+			},
+			want: false,
+		},
+		{
+			name: "different uniform array",
+			in: input{
+				srcs:     base.srcs,
+				shader:   shader0,
+				uniforms: []graphicsdriver.Uniform{{Float32: 1}, {Float32s: []float32{1, 3}}},
+			},
+			want: false,
+		},
+		{
+			name: "different number of uniforms",
+			in: input{
+				srcs:     base.srcs,
+				shader:   shader0,
+				uniforms: base.uniforms[:1],
+			},
+			want: false,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			c := &drawTrianglesCommand{
+				dst:       dst,
+				srcs:      base.srcs,
+				offsets:   base.offsets,
+				color:     affine.ColorMIdentity{},
+				mode:      graphicsdriver.CompositeModeSourceOver,
+				filter:    graphicsdriver.FilterNearest,
+				address:   graphicsdriver.AddressUnsafe,
+				dstRegion: region,
+				shader:    base.shader,
+				uniforms:  base.uniforms,
+			}
+			got := c.CanMergeWithDrawTrianglesCommand(dst, tc.in.srcs, tc.in.offsets, nil, affine.ColorMIdentity{}, graphicsdriver.CompositeModeSourceOver, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, region, graphicsdriver.Region{}, tc.in.shader, tc.in.uniforms, false, false)
+			if got != tc.want {
+				t.Errorf("got: %t, want: %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCanMergeWithDrawTrianglesCommandDefaultShader(t *testing.T) {
+	dst := &Image{}
+	src := &Image{}
+	srcs := [graphics.ShaderImageNum]*Image{src}
+	region := graphicsdriver.Region{X: 0, Y: 0, Width: 16, Height: 16}
+
+	c := &drawTrianglesCommand{
+		dst:       dst,
+		srcs:      srcs,
+		color:     affine.ColorMIdentity{},
+		mode:      graphicsdriver.CompositeModeSourceOver,
+		filter:    graphicsdriver.FilterNearest,
+		address:   graphicsdriver.AddressUnsafe,
+		dstRegion: region,
+	}
+
+	// Offsets are not used by the default shader.
+	offsets := [graphics.ShaderImageNum - 1][2]float32{{1, 0}}
+	if !c.CanMergeWithDrawTrianglesCommand(dst, srcs, offsets, nil, affine.ColorMIdentity{}, graphicsdriver.CompositeModeSourceOver, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, region, graphicsdriver.Region{}, nil, nil, false, false) {
+		t.Errorf("got: false, want: true")
+	}
+	if c.CanMergeWithDrawTrianglesCommand(dst, srcs, offsets, nil, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, region, graphicsdriver.Region{}, nil, nil, false, false) {
+		t.Errorf("got: true, want: false")
+	}
+}
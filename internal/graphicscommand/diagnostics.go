@@ -0,0 +1,84 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphicscommand
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
+)
+
+// maxRecentCommands is the number of the commands reported in a driver error.
+const maxRecentCommands = 8
+
+// driverError is an error from the graphics driver annotated with the information about the driver and
+// the commands executed just before the error.
+//
+// The annotations are meant to make bug reports from end users actionable.
+type driverError struct {
+	err      error
+	info     graphicsdriver.DriverInfo
+	commands []string
+}
+
+func newDriverError(err error, commands []command) *driverError {
+	if len(commands) > maxRecentCommands {
+		commands = commands[len(commands)-maxRecentCommands:]
+	}
+	e := &driverError{
+		err:  err,
+		info: driverInfo(),
+	}
+	for _, c := range commands {
+		e.commands = append(e.commands, c.String())
+	}
+	return e
+}
+
+// driverInfo returns the driver information.
+// The driver might be in a broken state after an error, so a panic in querying is ignored.
+func driverInfo() (info graphicsdriver.DriverInfo) {
+	defer func() {
+		_ = recover()
+	}()
+	return graphicsDriver().DriverInfo()
+}
+
+func (e *driverError) Error() string {
+	var b strings.Builder
+	b.WriteString(e.err.Error())
+	fmt.Fprintf(&b, "\n\ngraphics library: %s", e.info.GraphicsLibrary)
+	if e.info.Adapter != "" {
+		fmt.Fprintf(&b, "\nadapter: %s", e.info.Adapter)
+	}
+	if e.info.DriverVersion != "" {
+		fmt.Fprintf(&b, "\ndriver version: %s", e.info.DriverVersion)
+	}
+	if e.info.FeatureLevel != "" {
+		fmt.Fprintf(&b, "\nfeature level: %s", e.info.FeatureLevel)
+	}
+	if len(e.commands) > 0 {
+		b.WriteString("\nrecent commands:")
+		for _, c := range e.commands {
+			fmt.Fprintf(&b, "\n  %s", c)
+		}
+	}
+	return b.String()
+}
+
+func (e *driverError) Unwrap() error {
+	return e.err
+}
@@ -40,6 +40,10 @@ func IsGL() bool {
 	return graphicsDriver().IsGL()
 }
 
+func GraphicsLibrary() graphicsdriver.GraphicsLibrary {
+	return graphicsDriver().GraphicsLibrary()
+}
+
 func SetVsyncEnabled(enabled bool) {
 	graphicsDriver().SetVsyncEnabled(enabled)
 }
@@ -52,6 +56,26 @@ func SetFullscreen(fullscreen bool) {
 	graphicsDriver().SetFullscreen(fullscreen)
 }
 
+// SetLinearBlendingEnabled makes the graphics driver treat images as sRGB-encoded and blend in linear space,
+// if the driver supports it.
+//
+// SetLinearBlendingEnabled must be called before InitializeGraphicsDriverState.
+func SetLinearBlendingEnabled(enabled bool) {
+	if g, ok := graphicsDriver().(interface{ SetLinearBlendingEnabled(bool) }); ok {
+		g.SetLinearBlendingEnabled(enabled)
+	}
+}
+
+// IsLinearBlendingSupported reports whether the graphics driver supports linear blending.
+//
+// IsLinearBlendingSupported might return true before InitializeGraphicsDriverState even if it is not supported.
+func IsLinearBlendingSupported() bool {
+	if g, ok := graphicsDriver().(interface{ IsLinearBlendingSupported() bool }); ok {
+		return g.IsLinearBlendingSupported()
+	}
+	return false
+}
+
 func SetWindow(window uintptr) {
 	if g, ok := graphicsDriver().(interface{ SetWindow(uintptr) }); ok {
 		g.SetWindow(window)
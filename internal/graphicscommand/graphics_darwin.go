@@ -39,6 +39,7 @@ import (
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver/metal"
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver/opengl"
+	"github.com/hajimehoshi/ebiten/v2/internal/logger"
 )
 
 var theGraphics graphicsdriver.Graphics
@@ -61,6 +62,7 @@ func graphicsDriver() graphicsdriver.Graphics {
 			theGraphics = metal.Get()
 			return
 		}
+		logger.Warn("Metal is not available; falling back to OpenGL")
 		theGraphics = opengl.Get()
 	})
 	return theGraphics
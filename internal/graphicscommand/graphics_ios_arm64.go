@@ -17,12 +17,26 @@
 
 package graphicscommand
 
+// #include <TargetConditionals.h>
+//
+// static int isSimulator(void) {
+//   return TARGET_OS_SIMULATOR;
+// }
+import "C"
+
 import (
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver/metal"
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver/opengl"
 )
 
 func graphicsDriver() graphicsdriver.Graphics {
+	// ebitenmobile's view controller uses OpenGL ES on simulators including the ones on Apple Silicon machines.
+	// Use the same graphics library.
+	if C.isSimulator() != 0 {
+		return opengl.Get()
+	}
+
 	g := metal.Get()
 	if g == nil {
 		panic("ui: Metal is not available on this iOS device")
@@ -20,6 +20,7 @@ import (
 	"os"
 	"sort"
 	"strings"
+	"sync/atomic"
 
 	"github.com/hajimehoshi/ebiten/v2/internal/affine"
 	"github.com/hajimehoshi/ebiten/v2/internal/debug"
@@ -37,6 +38,15 @@ type Image struct {
 	internalHeight int
 	screen         bool
 
+	// compressed indicates whether the image is created from a compressed texture.
+	// A compressed image can be used only as a rendering source.
+	compressed       bool
+	compressedFormat graphicsdriver.CompressedTextureFormat
+
+	// native indicates whether the image wraps a texture created outside of Ebiten.
+	// A native image can be used only as a rendering source.
+	native bool
+
 	// id is an indentifier for the image. This is used only when dummping the information.
 	//
 	// This is duplicated with graphicsdriver.Image's ID, but this id is still necessary because this image might not
@@ -48,6 +58,21 @@ type Image struct {
 
 var nextID = 1
 
+var (
+	// textureCount is the number of the textures that are not disposed, except for the screen and native textures.
+	textureCount int64
+
+	// textureBytes is the estimated memory usage of the textures, except for the screen and native textures.
+	textureBytes int64
+)
+
+// TextureStats returns the number of the textures and the estimated memory usage of them in bytes.
+//
+// The screen framebuffer is not counted.
+func TextureStats() (count int, bytes int64) {
+	return int(atomic.LoadInt64(&textureCount)), atomic.LoadInt64(&textureBytes)
+}
+
 func genNextID() int {
 	id := nextID
 	nextID++
@@ -63,6 +88,9 @@ func NewImage(width, height int) *Image {
 		height: height,
 		id:     genNextID(),
 	}
+	atomic.AddInt64(&textureCount, 1)
+	atomic.AddInt64(&textureBytes, i.byteSize())
+
 	c := &newImageCommand{
 		result: i,
 		width:  width,
@@ -72,6 +100,52 @@ func NewImage(width, height int) *Image {
 	return i
 }
 
+// NewCompressedImage returns a new image from the given compressed texture data.
+//
+// A compressed image cannot be a rendering destination, and its pixels cannot be replaced or read.
+func NewCompressedImage(width, height int, format graphicsdriver.CompressedTextureFormat, data []byte) *Image {
+	i := &Image{
+		width:            width,
+		height:           height,
+		compressed:       true,
+		compressedFormat: format,
+		id:               genNextID(),
+	}
+	atomic.AddInt64(&textureCount, 1)
+	atomic.AddInt64(&textureBytes, i.byteSize())
+
+	c := &newCompressedImageCommand{
+		result: i,
+		width:  width,
+		height: height,
+		format: format,
+		data:   data,
+	}
+	theCommandQueue.Enqueue(c)
+	return i
+}
+
+// NewNativeImage returns a new image wrapping the given native texture.
+//
+// A native image cannot be a rendering destination, and its pixels cannot be replaced.
+// The native texture is not counted in TextureStats as Ebiten doesn't own it.
+func NewNativeImage(width, height int, texture graphicsdriver.NativeTexture) *Image {
+	i := &Image{
+		width:  width,
+		height: height,
+		native: true,
+		id:     genNextID(),
+	}
+	c := &newNativeImageCommand{
+		result:  i,
+		width:   width,
+		height:  height,
+		texture: texture,
+	}
+	theCommandQueue.Enqueue(c)
+	return i
+}
+
 func NewScreenFramebufferImage(width, height int) *Image {
 	i := &Image{
 		width:  width,
@@ -101,14 +175,38 @@ func (i *Image) resolveBufferedReplacePixels() {
 }
 
 func (i *Image) Dispose() {
+	if !i.screen && !i.native {
+		atomic.AddInt64(&textureCount, -1)
+		atomic.AddInt64(&textureBytes, -i.byteSize())
+	}
+
 	c := &disposeImageCommand{
 		target: i,
 	}
 	theCommandQueue.Enqueue(c)
 }
 
+// byteSize returns the estimated memory usage of the texture in bytes.
+func (i *Image) byteSize() int64 {
+	if i.compressed {
+		return int64(i.compressedFormat.ByteSize(i.width, i.height))
+	}
+	w, h := i.InternalSize()
+	return int64(4 * w * h)
+}
+
+// IsCompressed reports whether the image is created from a compressed texture.
+func (i *Image) IsCompressed() bool {
+	return i.compressed
+}
+
+// IsNative reports whether the image wraps a native texture.
+func (i *Image) IsNative() bool {
+	return i.native
+}
+
 func (i *Image) InternalSize() (int, int) {
-	if i.screen {
+	if i.screen || i.compressed || i.native {
 		return i.width, i.height
 	}
 	if i.internalWidth == 0 {
@@ -132,6 +230,7 @@ func (i *Image) InternalSize() (int, int) {
 //   5: Color G
 //   6: Color B
 //   7: Color Y
+//   8: Depth [0.0-1.0] (used only when depthTest is true)
 //
 // src and shader are exclusive and only either is non-nil.
 //
@@ -141,7 +240,18 @@ func (i *Image) InternalSize() (int, int) {
 //
 // If the source image is not specified, i.e., src is nil and there is no image in the uniform variables, the
 // elements for the source image are not used.
-func (i *Image) DrawTriangles(srcs [graphics.ShaderImageNum]*Image, offsets [graphics.ShaderImageNum - 1][2]float32, vertices []float32, indices []uint16, clr affine.ColorM, mode graphicsdriver.CompositeMode, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, shader *Shader, uniforms []graphicsdriver.Uniform, evenOdd bool) {
+//
+// If depthTest is true, the triangles are tested with the depth buffer of the image.
+func (i *Image) DrawTriangles(srcs [graphics.ShaderImageNum]*Image, offsets [graphics.ShaderImageNum - 1][2]float32, vertices []float32, indices []uint16, clr affine.ColorM, mode graphicsdriver.CompositeMode, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, shader *Shader, uniforms []graphicsdriver.Uniform, evenOdd bool, depthTest bool) {
+	if i.compressed {
+		panic("graphicscommand: a compressed image cannot be the rendering destination")
+	}
+	if i.native {
+		panic("graphicscommand: a native image cannot be the rendering destination")
+	}
+	if depthTest && i.screen {
+		panic("graphicscommand: the screen image cannot be the rendering destination with depth testing")
+	}
 	if shader == nil {
 		// Fast path for rendering without a shader (#1355).
 		img := srcs[0]
@@ -162,7 +272,69 @@ func (i *Image) DrawTriangles(srcs [graphics.ShaderImageNum]*Image, offsets [gra
 	}
 	i.resolveBufferedReplacePixels()
 
-	theCommandQueue.EnqueueDrawTrianglesCommand(i, srcs, offsets, vertices, indices, clr, mode, filter, address, dstRegion, srcRegion, shader, uniforms, evenOdd)
+	theCommandQueue.EnqueueDrawTrianglesCommand(i, srcs, offsets, vertices, indices, clr, mode, filter, address, dstRegion, srcRegion, shader, uniforms, evenOdd, depthTest)
+}
+
+// ClearDepth resets the depth buffer of the image.
+func (i *Image) ClearDepth() {
+	if i.screen {
+		panic("graphicscommand: ClearDepth cannot be called on the screen image")
+	}
+	c := &clearDepthCommand{
+		dst: i,
+	}
+	theCommandQueue.Enqueue(c)
+}
+
+// CopyPixels copies the region (srcX, srcY)-(srcX+width, srcY+height) of src to (dstX, dstY) of the image as it is.
+//
+// The region is in the coordinates of the textures, including paddings if any.
+func (i *Image) CopyPixels(src *Image, dstX, dstY, srcX, srcY, width, height int) {
+	if i == src {
+		panic("graphicscommand: CopyPixels: the source image must be different from the receiver")
+	}
+	if i.screen || i.compressed || i.native {
+		panic("graphicscommand: the screen, compressed, or native image cannot be the destination of CopyPixels")
+	}
+	// A native texture might have a different pixel format, which a texture copy cannot convert.
+	if src.screen || src.compressed || src.native {
+		panic("graphicscommand: the screen, compressed, or native image cannot be the source of CopyPixels")
+	}
+	src.resolveBufferedReplacePixels()
+	i.resolveBufferedReplacePixels()
+
+	c := &copyPixelsCommand{
+		dst:    i,
+		src:    src,
+		dstX:   dstX,
+		dstY:   dstY,
+		srcX:   srcX,
+		srcY:   srcY,
+		width:  width,
+		height: height,
+	}
+	theCommandQueue.Enqueue(c)
+}
+
+// FillRect replaces the pixels of the region (x, y)-(x+width, y+height) of the image with the given premultiplied
+// color.
+//
+// The region is in the coordinates of the texture, including paddings if any.
+func (i *Image) FillRect(x, y, width, height int, red, green, blue, alpha float32) {
+	if i.screen || i.compressed || i.native {
+		panic("graphicscommand: FillRect cannot be called on the screen, compressed, or native image")
+	}
+	i.resolveBufferedReplacePixels()
+
+	c := &fillRectCommand{
+		dst:    i,
+		x:      x,
+		y:      y,
+		width:  width,
+		height: height,
+		color:  [4]float32{red, green, blue, alpha},
+	}
+	theCommandQueue.Enqueue(c)
 }
 
 // ReadPixels reads the image's pixels.
@@ -181,6 +353,12 @@ func (i *Image) ReadPixels(buf []byte) error {
 }
 
 func (i *Image) ReplacePixels(pixels []byte, x, y, width, height int) {
+	if i.compressed {
+		panic("graphicscommand: ReplacePixels cannot be called on a compressed image")
+	}
+	if i.native {
+		panic("graphicscommand: ReplacePixels cannot be called on a native image")
+	}
 	i.bufferedRP = append(i.bufferedRP, &graphicsdriver.ReplacePixelsArgs{
 		Pixels: pixels,
 		X:      x,
@@ -211,8 +389,8 @@ func (i *Image) IsInvalidated() bool {
 //
 // This is for testing usage.
 func (i *Image) Dump(path string, blackbg bool, rect image.Rectangle) error {
-	// Screen image cannot be dumped.
-	if i.screen {
+	// Screen, compressed and native images cannot be dumped.
+	if i.screen || i.compressed || i.native {
 		return nil
 	}
 
@@ -244,6 +422,11 @@ func (i *Image) Dump(path string, blackbg bool, rect image.Rectangle) error {
 	return nil
 }
 
+// ID returns the image's identifier. This is used only when dumping the information.
+func (i *Image) ID() int {
+	return i.id
+}
+
 func LogImagesInfo(images []*Image) {
 	sort.Slice(images, func(a, b int) bool {
 		return images[a].id < images[b].id
@@ -0,0 +1,72 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphicscommand
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
+)
+
+// NativeGraphicsHandles returns the native handles of the graphics device.
+// For Metal, device is id<MTLDevice> and commandQueue is id<MTLCommandQueue>.
+// For the other graphics libraries, both are 0.
+func NativeGraphicsHandles() (device, commandQueue uintptr) {
+	var d, q uintptr
+	runOnRenderingThread(func() {
+		if g, ok := graphicsDriver().(interface{ NativeHandles() (uintptr, uintptr) }); ok {
+			d, q = g.NativeHandles()
+		}
+	})
+	return d, q
+}
+
+// ValidateNativeTexture reports an error if the native texture cannot be wrapped as an image of the given size.
+// ValidateNativeTexture checks the texture on the rendering thread right away, unlike NewNativeImage.
+func ValidateNativeTexture(width, height int, texture graphicsdriver.NativeTexture) error {
+	var err error
+	runOnRenderingThread(func() {
+		g, ok := graphicsDriver().(interface {
+			ValidateNativeTexture(width, height int, texture graphicsdriver.NativeTexture) error
+		})
+		if !ok {
+			err = fmt.Errorf("graphicscommand: the graphics driver doesn't support native textures")
+			return
+		}
+		err = g.ValidateNativeTexture(width, height, texture)
+	})
+	return err
+}
+
+// RunWithNativeGraphics flushes the queued commands and calls f on the rendering thread.
+//
+// With Metal, Ebiten's command buffer is committed before f is called, so commands f commits later are executed after
+// Ebiten's commands.
+// With OpenGL, the OpenGL context is current in f, and Ebiten forgets the cached OpenGL state after f.
+func RunWithNativeGraphics(f func()) error {
+	if err := FlushCommands(); err != nil {
+		return err
+	}
+	runOnRenderingThread(func() {
+		if g, ok := graphicsDriver().(interface{ CommitNativeCommands() }); ok {
+			g.CommitNativeCommands()
+		}
+		f()
+		if g, ok := graphicsDriver().(interface{ InvalidateState() }); ok {
+			g.InvalidateState()
+		}
+	})
+	return nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphicscommand
+
+import (
+	"runtime"
+	"sync"
+)
+
+// workerPool is a set of long-lived goroutines that run jobs in parallel.
+//
+// The goroutines are started lazily at the first use, and live as long as the process.
+type workerPool struct {
+	jobs chan func()
+	num  int
+	once sync.Once
+}
+
+var theWorkerPool workerPool
+
+func (p *workerPool) start() {
+	p.once.Do(func() {
+		p.num = runtime.GOMAXPROCS(0)
+		p.jobs = make(chan func(), p.num)
+		for i := 0; i < p.num; i++ {
+			go func() {
+				for f := range p.jobs {
+					f()
+				}
+			}()
+		}
+	})
+}
+
+// workers returns the number of the goroutines in the pool.
+func (p *workerPool) workers() int {
+	p.start()
+	return p.num
+}
+
+// run splits the range [0, n) into chunks, calls f with each chunk in the pool, and waits for all the calls.
+func (p *workerPool) run(n int, f func(start, end int)) {
+	p.start()
+
+	var wg sync.WaitGroup
+	chunk := (n + p.num - 1) / p.num
+	for start := 0; start < n; start += chunk {
+		end := start + chunk
+		if end > n {
+			end = n
+		}
+		start := start
+		wg.Add(1)
+		p.jobs <- func() {
+			defer wg.Done()
+			f(start, end)
+		}
+	}
+	wg.Wait()
+}
@@ -0,0 +1,63 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphicscommand
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/graphics"
+)
+
+func TestWorkerPoolRun(t *testing.T) {
+	for _, n := range []int{1, 2, 7, 100, 12345} {
+		counts := make([]int, n)
+		theWorkerPool.run(n, func(start, end int) {
+			for i := start; i < end; i++ {
+				counts[i]++
+			}
+		})
+		for i, c := range counts {
+			if c != 1 {
+				t.Errorf("n: %d, counts[%d]: got: %d, want: 1", n, i, c)
+			}
+		}
+	}
+}
+
+func TestAdjustVerticesParallel(t *testing.T) {
+	const n = parallelAdjustVerticesThreshold * 2
+	vs := make([]float32, n*graphics.VertexFloatNum)
+	srcSizes := make([]size, n)
+	for i := 0; i < n; i++ {
+		idx := i * graphics.VertexFloatNum
+		vs[idx] = float32(i%100) + 0.5
+		vs[idx+1] = float32(i%50) + 0.25
+		vs[idx+2] = float32(i % 64)
+		vs[idx+3] = float32(i % 32)
+		srcSizes[i] = size{width: 64, height: 32}
+	}
+
+	for _, highPrecision := range []bool{false, true} {
+		got := append([]float32{}, vs...)
+		want := append([]float32{}, vs...)
+		adjustVertices(got, srcSizes, highPrecision)
+		adjustVerticesInRange(want, srcSizes, highPrecision, 0, n)
+		for i := range got {
+			if got[i] != want[i] {
+				t.Fatalf("highPrecision: %v, vs[%d]: got: %v, want: %v", highPrecision, i, got[i], want[i])
+			}
+		}
+	}
+}
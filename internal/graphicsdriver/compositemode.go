@@ -86,3 +86,38 @@ func (c CompositeMode) Operations() (src Operation, dst Operation) {
 		panic(fmt.Sprintf("graphics: invalid composite mode: %d", c))
 	}
 }
+
+func (c CompositeMode) String() string {
+	switch c {
+	case CompositeModeSourceOver:
+		return "source-over"
+	case CompositeModeClear:
+		return "clear"
+	case CompositeModeCopy:
+		return "copy"
+	case CompositeModeDestination:
+		return "destination"
+	case CompositeModeDestinationOver:
+		return "destination-over"
+	case CompositeModeSourceIn:
+		return "source-in"
+	case CompositeModeDestinationIn:
+		return "destination-in"
+	case CompositeModeSourceOut:
+		return "source-out"
+	case CompositeModeDestinationOut:
+		return "destination-out"
+	case CompositeModeSourceAtop:
+		return "source-atop"
+	case CompositeModeDestinationAtop:
+		return "destination-atop"
+	case CompositeModeXor:
+		return "xor"
+	case CompositeModeLighter:
+		return "lighter"
+	case CompositeModeMultiply:
+		return "multiply"
+	default:
+		panic(fmt.Sprintf("graphicsdriver: invalid composite mode: %d", c))
+	}
+}
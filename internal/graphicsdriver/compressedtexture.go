@@ -0,0 +1,94 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphicsdriver
+
+import (
+	"fmt"
+)
+
+// CompressedTextureFormat represents a block-compressed texture format.
+type CompressedTextureFormat int
+
+const (
+	CompressedTextureFormatBC1 CompressedTextureFormat = iota
+	CompressedTextureFormatBC2
+	CompressedTextureFormatBC3
+	CompressedTextureFormatBC4
+	CompressedTextureFormatBC5
+	CompressedTextureFormatBC6H
+	CompressedTextureFormatBC7
+	CompressedTextureFormatETC2RGB8
+	CompressedTextureFormatETC2RGBA8
+	CompressedTextureFormatASTC4x4
+
+	CompressedTextureFormatMax = CompressedTextureFormatASTC4x4
+)
+
+// BlockSize returns the size of a block in pixels.
+func (c CompressedTextureFormat) BlockSize() (width, height int) {
+	// All the formats use 4x4 blocks so far.
+	return 4, 4
+}
+
+// BlockByteSize returns the size of a block in bytes.
+func (c CompressedTextureFormat) BlockByteSize() int {
+	switch c {
+	case CompressedTextureFormatBC1, CompressedTextureFormatBC4, CompressedTextureFormatETC2RGB8:
+		return 8
+	case CompressedTextureFormatBC2, CompressedTextureFormatBC3, CompressedTextureFormatBC5, CompressedTextureFormatBC6H, CompressedTextureFormatBC7, CompressedTextureFormatETC2RGBA8, CompressedTextureFormatASTC4x4:
+		return 16
+	default:
+		panic(fmt.Sprintf("graphicsdriver: invalid compressed texture format: %d", c))
+	}
+}
+
+// ByteSize returns the size of a texture's data with the given size in bytes.
+func (c CompressedTextureFormat) ByteSize(width, height int) int {
+	bw, bh := c.BlockSize()
+	return ((width + bw - 1) / bw) * ((height + bh - 1) / bh) * c.BlockByteSize()
+}
+
+// BytesPerRow returns the size of a row of blocks with the given width in bytes.
+func (c CompressedTextureFormat) BytesPerRow(width int) int {
+	bw, _ := c.BlockSize()
+	return ((width + bw - 1) / bw) * c.BlockByteSize()
+}
+
+func (c CompressedTextureFormat) String() string {
+	switch c {
+	case CompressedTextureFormatBC1:
+		return "bc1"
+	case CompressedTextureFormatBC2:
+		return "bc2"
+	case CompressedTextureFormatBC3:
+		return "bc3"
+	case CompressedTextureFormatBC4:
+		return "bc4"
+	case CompressedTextureFormatBC5:
+		return "bc5"
+	case CompressedTextureFormatBC6H:
+		return "bc6h"
+	case CompressedTextureFormatBC7:
+		return "bc7"
+	case CompressedTextureFormatETC2RGB8:
+		return "etc2-rgb8"
+	case CompressedTextureFormatETC2RGBA8:
+		return "etc2-rgba8"
+	case CompressedTextureFormatASTC4x4:
+		return "astc-4x4"
+	default:
+		panic(fmt.Sprintf("graphicsdriver: invalid compressed texture format: %d", c))
+	}
+}
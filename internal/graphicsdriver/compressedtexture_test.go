@@ -0,0 +1,76 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphicsdriver_test
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
+)
+
+func TestCompressedTextureFormatByteSize(t *testing.T) {
+	tests := []struct {
+		format      graphicsdriver.CompressedTextureFormat
+		width       int
+		height      int
+		byteSize    int
+		bytesPerRow int
+	}{
+		{graphicsdriver.CompressedTextureFormatBC1, 4, 4, 8, 8},
+		{graphicsdriver.CompressedTextureFormatBC1, 16, 8, 64, 32},
+		{graphicsdriver.CompressedTextureFormatBC1, 1, 1, 8, 8},
+		{graphicsdriver.CompressedTextureFormatBC1, 5, 5, 32, 16},
+		{graphicsdriver.CompressedTextureFormatBC1, 0, 0, 0, 0},
+		{graphicsdriver.CompressedTextureFormatBC2, 4, 4, 16, 16},
+		{graphicsdriver.CompressedTextureFormatBC3, 16, 8, 128, 64},
+		{graphicsdriver.CompressedTextureFormatBC3, 7, 3, 32, 32},
+		{graphicsdriver.CompressedTextureFormatBC4, 8, 8, 32, 16},
+		{graphicsdriver.CompressedTextureFormatBC5, 8, 8, 64, 32},
+		{graphicsdriver.CompressedTextureFormatBC6H, 8, 4, 32, 32},
+		{graphicsdriver.CompressedTextureFormatBC7, 12, 12, 144, 48},
+		{graphicsdriver.CompressedTextureFormatETC2RGB8, 8, 8, 32, 16},
+		{graphicsdriver.CompressedTextureFormatETC2RGBA8, 8, 8, 64, 32},
+		{graphicsdriver.CompressedTextureFormatASTC4x4, 9, 2, 48, 48},
+	}
+	for _, tc := range tests {
+		if got := tc.format.ByteSize(tc.width, tc.height); got != tc.byteSize {
+			t.Errorf("%s.ByteSize(%d, %d): got: %d, want: %d", tc.format, tc.width, tc.height, got, tc.byteSize)
+		}
+		if got := tc.format.BytesPerRow(tc.width); got != tc.bytesPerRow {
+			t.Errorf("%s.BytesPerRow(%d): got: %d, want: %d", tc.format, tc.width, got, tc.bytesPerRow)
+		}
+	}
+}
+
+func TestCompressedTextureFormatBlockSize(t *testing.T) {
+	for f := graphicsdriver.CompressedTextureFormat(0); f <= graphicsdriver.CompressedTextureFormatMax; f++ {
+		w, h := f.BlockSize()
+		if w != 4 || h != 4 {
+			t.Errorf("%s.BlockSize(): got: (%d, %d), want: (4, 4)", f, w, h)
+		}
+		if got := f.ByteSize(w, h); got != f.BlockByteSize() {
+			t.Errorf("%s.ByteSize(%d, %d): got: %d, want: %d", f, w, h, got, f.BlockByteSize())
+		}
+	}
+}
+
+func TestCompressedTextureFormatInvalid(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("BlockByteSize with an invalid format must panic")
+		}
+	}()
+	(graphicsdriver.CompressedTextureFormatMax + 1).BlockByteSize()
+}
@@ -14,6 +14,10 @@
 
 package graphicsdriver
 
+import (
+	"fmt"
+)
+
 type Filter int
 
 const (
@@ -22,6 +26,19 @@ const (
 	FilterScreen
 )
 
+func (f Filter) String() string {
+	switch f {
+	case FilterNearest:
+		return "nearest"
+	case FilterLinear:
+		return "linear"
+	case FilterScreen:
+		return "screen"
+	default:
+		panic(fmt.Sprintf("graphicsdriver: invalid filter: %d", f))
+	}
+}
+
 type Address int
 
 const (
@@ -29,3 +46,16 @@ const (
 	AddressClampToZero
 	AddressRepeat
 )
+
+func (a Address) String() string {
+	switch a {
+	case AddressClampToZero:
+		return "clamp_to_zero"
+	case AddressRepeat:
+		return "repeat"
+	case AddressUnsafe:
+		return "unsafe"
+	default:
+		panic(fmt.Sprintf("graphicsdriver: invalid address: %d", a))
+	}
+}
@@ -16,6 +16,7 @@ package graphicsdriver
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/hajimehoshi/ebiten/v2/internal/graphics"
 	"github.com/hajimehoshi/ebiten/v2/internal/shaderir"
@@ -48,7 +49,7 @@ type Graphics interface {
 	Begin()
 	End(present bool)
 	SetTransparent(transparent bool)
-	SetVertices(vertices []float32, indices []uint16)
+	SetVertices(vertices []float32, indices []uint16, depth bool)
 	NewImage(width, height int) (Image, error)
 	NewScreenFramebufferImage(width, height int) (Image, error)
 	Initialize() error
@@ -61,6 +62,8 @@ type Graphics interface {
 	IsGL() bool
 	HasHighPrecisionFloat() bool
 	MaxImageSize() int
+	GraphicsLibrary() GraphicsLibrary
+	DriverInfo() DriverInfo
 
 	NewShader(program *shaderir.Program) (Shader, error)
 
@@ -70,7 +73,25 @@ type Graphics interface {
 	//
 	//   * float32
 	//   * []float32
-	DrawTriangles(dst ImageID, srcs [graphics.ShaderImageNum]ImageID, offsets [graphics.ShaderImageNum - 1][2]float32, shader ShaderID, indexLen int, indexOffset int, mode CompositeMode, colorM ColorM, filter Filter, address Address, dstRegion, srcRegion Region, uniforms []Uniform, evenOdd bool) error
+	//
+	// If shader is InvalidShaderID, uniforms is either empty or has graphics.DefaultShaderUniformVariablesNum
+	// items for the default shader. An empty uniforms means the identity geometry matrix and the identity color scale.
+	//
+	// If depthTest is true, the fragments are tested with and written to the depth buffer of dst, which is
+	// allocated on demand.
+	DrawTriangles(dst ImageID, srcs [graphics.ShaderImageNum]ImageID, offsets [graphics.ShaderImageNum - 1][2]float32, shader ShaderID, indexLen int, indexOffset int, mode CompositeMode, colorM ColorM, filter Filter, address Address, dstRegion, srcRegion Region, uniforms []Uniform, evenOdd bool, depthTest bool) error
+
+	// CopyPixels copies the texels of the region (srcX, srcY)-(srcX+width, srcY+height) of src to (dstX, dstY) of
+	// dst without any conversion.
+	//
+	// Neither dst nor src can be the screen or a compressed texture. dst and src must be different.
+	CopyPixels(dst, src ImageID, dstX, dstY, srcX, srcY, width, height int) error
+
+	// FillRect replaces the texels of the region (x, y)-(x+width, y+height) of dst with the given premultiplied
+	// color without blending.
+	//
+	// dst cannot be the screen or a compressed texture.
+	FillRect(dst ImageID, x, y, width, height int, red, green, blue, alpha float32) error
 }
 
 // GraphicsNotReady represents that the graphics driver is not ready for recovering from the context lost.
@@ -82,6 +103,10 @@ type Image interface {
 	IsInvalidated() bool
 	ReadPixels(buf []byte) error
 	ReplacePixels(args []*ReplacePixelsArgs)
+
+	// ClearDepth resets the depth buffer to the farthest value 1.
+	// ClearDepth does nothing if the depth buffer is not allocated yet.
+	ClearDepth() error
 }
 
 type ImageID int
@@ -101,6 +126,50 @@ const (
 	Downward
 )
 
+type GraphicsLibrary int
+
+const (
+	GraphicsLibraryUnknown GraphicsLibrary = iota
+	GraphicsLibraryOpenGL
+	GraphicsLibraryOpenGLES
+	GraphicsLibraryWebGL1
+	GraphicsLibraryWebGL2
+	GraphicsLibraryMetal
+)
+
+func (g GraphicsLibrary) String() string {
+	switch g {
+	case GraphicsLibraryUnknown:
+		return "Unknown"
+	case GraphicsLibraryOpenGL:
+		return "OpenGL"
+	case GraphicsLibraryOpenGLES:
+		return "OpenGL ES"
+	case GraphicsLibraryWebGL1:
+		return "WebGL 1"
+	case GraphicsLibraryWebGL2:
+		return "WebGL 2"
+	case GraphicsLibraryMetal:
+		return "Metal"
+	}
+	return fmt.Sprintf("GraphicsLibrary(%d)", g)
+}
+
+// DriverInfo describes the graphics adapter and driver in use.
+// Empty fields mean the information is not available.
+type DriverInfo struct {
+	GraphicsLibrary GraphicsLibrary
+
+	// Adapter is the vendor and the name of the GPU.
+	Adapter string
+
+	// DriverVersion is the version string reported by the driver.
+	DriverVersion string
+
+	// FeatureLevel is the shading language version or the feature set supported by the GPU.
+	FeatureLevel string
+}
+
 type Shader interface {
 	ID() ShaderID
 	Dispose()
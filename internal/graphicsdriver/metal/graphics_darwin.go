@@ -26,6 +26,7 @@ import (
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver/metal/ca"
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver/metal/mtl"
 	"github.com/hajimehoshi/ebiten/v2/internal/shaderir"
+	"github.com/hajimehoshi/ebiten/v2/internal/tracing"
 )
 
 // #cgo CFLAGS: -x objective-c
@@ -59,6 +60,7 @@ struct VertexIn {
   packed_float2 position;
   packed_float2 tex;
   packed_float4 color;
+  float depth;
 };
 
 struct VertexOut {
@@ -70,7 +72,10 @@ struct VertexOut {
 vertex VertexOut VertexShader(
   uint vid [[vertex_id]],
   const device VertexIn* vertices [[buffer(0)]],
-  constant float2& viewport_size [[buffer(1)]]
+  constant float2& viewport_size [[buffer(1)]],
+  constant float2x2& geo_matrix_body [[buffer(7)]],
+  constant float2& geo_matrix_translation [[buffer(8)]],
+  constant float4& color_scale [[buffer(9)]]
 ) {
   // In Metal, the NDC's Y direction (upward) and the framebuffer's Y direction (downward) don't
   // match. Then, the Y direction must be inverted.
@@ -82,11 +87,12 @@ vertex VertexOut VertexShader(
   );
 
   VertexIn in = vertices[vid];
+  float4 color = in.color * color_scale;
   VertexOut out = {
-    .position = projectionMatrix * float4(in.position, 0, 1),
+    .position = projectionMatrix * float4(geo_matrix_body * float2(in.position) + geo_matrix_translation, in.depth, 1),
     .tex = in.tex,
     // Fragment shader wants premultiplied alpha.
-    .color = float4(in.color.rgb, 1) * in.color.a,
+    .color = float4(color.rgb, 1) * color.a,
   };
 
   return out;
@@ -260,11 +266,12 @@ struct FragmentShaderImpl<useColorM, FILTER_SCREEN, address> {
 // Define Foo and FooCp macros to force macro replacement.
 // See "6.10.3.1 Argument substitution" in ISO/IEC 9899.
 
-#define FragmentShaderFunc(useColorM, filter, address) \
-  FragmentShaderFuncCp(useColorM, filter, address)
+#define FragmentShaderFunc(useColorM, filter, address, depthTest) \
+  FragmentShaderFuncCp(useColorM, filter, address, depthTest)
 
-#define FragmentShaderFuncCp(useColorM, filter, address) \
-  fragment float4 FragmentShader_##useColorM##_##filter##_##address( \
+// When depthTest is true, fully transparent fragments are discarded not to update the depth buffer.
+#define FragmentShaderFuncCp(useColorM, filter, address, depthTest) \
+  fragment float4 FragmentShader_##useColorM##_##filter##_##address##_##depthTest( \
       VertexOut v [[stage_in]], \
       texture2d<float> texture [[texture(0)]], \
       constant float2& source_size [[buffer(2)]], \
@@ -272,24 +279,41 @@ struct FragmentShaderImpl<useColorM, FILTER_SCREEN, address> {
       constant float4& color_matrix_translation [[buffer(4)]], \
       constant float& scale [[buffer(5)]], \
       constant float4& source_region [[buffer(6)]]) { \
-    return FragmentShaderImpl<useColorM, filter, address>().Do( \
+    float4 c = FragmentShaderImpl<useColorM, filter, address>().Do( \
         v, texture, source_size, color_matrix_body, color_matrix_translation, scale, source_region); \
+    if (depthTest && c.a == 0.0) { \
+      discard_fragment(); \
+    } \
+    return c; \
   }
 
-FragmentShaderFunc(0, FILTER_NEAREST, ADDRESS_CLAMP_TO_ZERO)
-FragmentShaderFunc(0, FILTER_LINEAR, ADDRESS_CLAMP_TO_ZERO)
-FragmentShaderFunc(0, FILTER_NEAREST, ADDRESS_REPEAT)
-FragmentShaderFunc(0, FILTER_LINEAR, ADDRESS_REPEAT)
-FragmentShaderFunc(0, FILTER_NEAREST, ADDRESS_UNSAFE)
-FragmentShaderFunc(0, FILTER_LINEAR, ADDRESS_UNSAFE)
-FragmentShaderFunc(1, FILTER_NEAREST, ADDRESS_CLAMP_TO_ZERO)
-FragmentShaderFunc(1, FILTER_LINEAR, ADDRESS_CLAMP_TO_ZERO)
-FragmentShaderFunc(1, FILTER_NEAREST, ADDRESS_REPEAT)
-FragmentShaderFunc(1, FILTER_LINEAR, ADDRESS_REPEAT)
-FragmentShaderFunc(1, FILTER_NEAREST, ADDRESS_UNSAFE)
-FragmentShaderFunc(1, FILTER_LINEAR, ADDRESS_UNSAFE)
-
-FragmentShaderFunc(0, FILTER_SCREEN, ADDRESS_UNSAFE)
+FragmentShaderFunc(0, FILTER_NEAREST, ADDRESS_CLAMP_TO_ZERO, 0)
+FragmentShaderFunc(0, FILTER_LINEAR, ADDRESS_CLAMP_TO_ZERO, 0)
+FragmentShaderFunc(0, FILTER_NEAREST, ADDRESS_REPEAT, 0)
+FragmentShaderFunc(0, FILTER_LINEAR, ADDRESS_REPEAT, 0)
+FragmentShaderFunc(0, FILTER_NEAREST, ADDRESS_UNSAFE, 0)
+FragmentShaderFunc(0, FILTER_LINEAR, ADDRESS_UNSAFE, 0)
+FragmentShaderFunc(1, FILTER_NEAREST, ADDRESS_CLAMP_TO_ZERO, 0)
+FragmentShaderFunc(1, FILTER_LINEAR, ADDRESS_CLAMP_TO_ZERO, 0)
+FragmentShaderFunc(1, FILTER_NEAREST, ADDRESS_REPEAT, 0)
+FragmentShaderFunc(1, FILTER_LINEAR, ADDRESS_REPEAT, 0)
+FragmentShaderFunc(1, FILTER_NEAREST, ADDRESS_UNSAFE, 0)
+FragmentShaderFunc(1, FILTER_LINEAR, ADDRESS_UNSAFE, 0)
+
+FragmentShaderFunc(0, FILTER_NEAREST, ADDRESS_CLAMP_TO_ZERO, 1)
+FragmentShaderFunc(0, FILTER_LINEAR, ADDRESS_CLAMP_TO_ZERO, 1)
+FragmentShaderFunc(0, FILTER_NEAREST, ADDRESS_REPEAT, 1)
+FragmentShaderFunc(0, FILTER_LINEAR, ADDRESS_REPEAT, 1)
+FragmentShaderFunc(0, FILTER_NEAREST, ADDRESS_UNSAFE, 1)
+FragmentShaderFunc(0, FILTER_LINEAR, ADDRESS_UNSAFE, 1)
+FragmentShaderFunc(1, FILTER_NEAREST, ADDRESS_CLAMP_TO_ZERO, 1)
+FragmentShaderFunc(1, FILTER_LINEAR, ADDRESS_CLAMP_TO_ZERO, 1)
+FragmentShaderFunc(1, FILTER_NEAREST, ADDRESS_REPEAT, 1)
+FragmentShaderFunc(1, FILTER_LINEAR, ADDRESS_REPEAT, 1)
+FragmentShaderFunc(1, FILTER_NEAREST, ADDRESS_UNSAFE, 1)
+FragmentShaderFunc(1, FILTER_LINEAR, ADDRESS_UNSAFE, 1)
+
+FragmentShaderFunc(0, FILTER_SCREEN, ADDRESS_UNSAFE, 0)
 
 #undef FragmentShaderFuncName
 `
@@ -300,6 +324,7 @@ type rpsKey struct {
 	address       graphicsdriver.Address
 	compositeMode graphicsdriver.CompositeMode
 	stencilMode   stencilMode
+	depthTest     bool
 	screen        bool
 }
 
@@ -312,6 +337,7 @@ type Graphics struct {
 	cb        mtl.CommandBuffer
 	rce       mtl.RenderCommandEncoder
 	dsss      map[stencilMode]mtl.DepthStencilState
+	depthDSS  mtl.DepthStencilState
 
 	screenDrawable ca.MetalDrawable
 
@@ -320,6 +346,7 @@ type Graphics struct {
 
 	lastDst         *Image
 	lastStencilMode stencilMode
+	lastDepthTest   bool
 
 	vb mtl.Buffer
 	ib mtl.Buffer
@@ -333,9 +360,10 @@ type Graphics struct {
 	src *Image
 	dst *Image
 
-	transparent  bool
-	maxImageSize int
-	tmpTextures  []mtl.Texture
+	transparent    bool
+	linearBlending bool
+	maxImageSize   int
+	tmpTextures    []mtl.Texture
 
 	pool unsafe.Pointer
 }
@@ -459,7 +487,8 @@ func (g *Graphics) availableBuffer(length uintptr) mtl.Buffer {
 	return newBuf
 }
 
-func (g *Graphics) SetVertices(vertices []float32, indices []uint16) {
+func (g *Graphics) SetVertices(vertices []float32, indices []uint16, depth bool) {
+	// The depth values are always uploaded as the vertex layout is fixed in the shaders.
 	vbSize := unsafe.Sizeof(vertices[0]) * uintptr(len(vertices))
 	ibSize := unsafe.Sizeof(indices[0]) * uintptr(len(indices))
 
@@ -523,7 +552,7 @@ func (g *Graphics) NewImage(width, height int) (graphicsdriver.Image, error) {
 	g.checkSize(width, height)
 	td := mtl.TextureDescriptor{
 		TextureType: mtl.TextureType2D,
-		PixelFormat: mtl.PixelFormatRGBA8UNorm,
+		PixelFormat: g.texturePixelFormat(),
 		Width:       graphics.InternalImageSize(width),
 		Height:      graphics.InternalImageSize(height),
 		StorageMode: storageMode,
@@ -541,6 +570,144 @@ func (g *Graphics) NewImage(width, height int) (graphicsdriver.Image, error) {
 	return i, nil
 }
 
+func compressedTexturePixelFormat(format graphicsdriver.CompressedTextureFormat) mtl.PixelFormat {
+	switch format {
+	case graphicsdriver.CompressedTextureFormatBC1:
+		return mtl.PixelFormatBC1RGBA
+	case graphicsdriver.CompressedTextureFormatBC2:
+		return mtl.PixelFormatBC2RGBA
+	case graphicsdriver.CompressedTextureFormatBC3:
+		return mtl.PixelFormatBC3RGBA
+	case graphicsdriver.CompressedTextureFormatBC4:
+		return mtl.PixelFormatBC4RUNorm
+	case graphicsdriver.CompressedTextureFormatBC5:
+		return mtl.PixelFormatBC5RGUNorm
+	case graphicsdriver.CompressedTextureFormatBC6H:
+		return mtl.PixelFormatBC6HRGBUFloat
+	case graphicsdriver.CompressedTextureFormatBC7:
+		return mtl.PixelFormatBC7RGBAUNorm
+	case graphicsdriver.CompressedTextureFormatETC2RGB8:
+		return mtl.PixelFormatETC2RGB8
+	case graphicsdriver.CompressedTextureFormatETC2RGBA8:
+		return mtl.PixelFormatEACRGBA8
+	case graphicsdriver.CompressedTextureFormatASTC4x4:
+		return mtl.PixelFormatASTC4x4LDR
+	default:
+		panic(fmt.Sprintf("metal: invalid compressed texture format: %d", format))
+	}
+}
+
+// IsCompressedTextureFormatSupported reports whether the given compressed texture format is available.
+func (g *Graphics) IsCompressedTextureFormatSupported(format graphicsdriver.CompressedTextureFormat) bool {
+	return isCompressedTextureFormatAvailable(format)
+}
+
+// NewCompressedImage creates an image from the given compressed texture data.
+// A compressed image can be used only as a rendering source.
+func (g *Graphics) NewCompressedImage(width, height int, format graphicsdriver.CompressedTextureFormat, data []byte) (graphicsdriver.Image, error) {
+	if !isCompressedTextureFormatAvailable(format) {
+		return nil, fmt.Errorf("metal: the compressed texture format %s is not supported", format)
+	}
+	g.checkSize(width, height)
+	td := mtl.TextureDescriptor{
+		TextureType: mtl.TextureType2D,
+		PixelFormat: compressedTexturePixelFormat(format),
+		Width:       width,
+		Height:      height,
+		StorageMode: storageMode,
+		Usage:       mtl.TextureUsageShaderRead,
+	}
+	t := g.view.getMTLDevice().MakeTexture(td)
+	t.ReplaceRegion(mtl.Region{
+		Size: mtl.Size{Width: width, Height: height, Depth: 1},
+	}, 0, unsafe.Pointer(&data[0]), format.BytesPerRow(width))
+	i := &Image{
+		id:         g.genNextImageID(),
+		graphics:   g,
+		width:      width,
+		height:     height,
+		texture:    t,
+		compressed: true,
+	}
+	g.addImage(i)
+	return i, nil
+}
+
+func (g *Graphics) newTextureWithIOSurface(width, height int, surface uintptr) (mtl.Texture, error) {
+	td := mtl.TextureDescriptor{
+		TextureType: mtl.TextureType2D,
+		PixelFormat: mtl.PixelFormatBGRA8UNorm,
+		Width:       width,
+		Height:      height,
+		StorageMode: storageMode,
+		Usage:       mtl.TextureUsageShaderRead,
+	}
+	t := g.view.getMTLDevice().MakeTextureWithIOSurface(td, unsafe.Pointer(surface), 0)
+	if t == (mtl.Texture{}) {
+		return mtl.Texture{}, fmt.Errorf("metal: creating a texture from the IOSurface failed")
+	}
+	return t, nil
+}
+
+// ValidateNativeTexture reports an error if the given Metal texture or IOSurface cannot be wrapped as an image of
+// the given size.
+func (g *Graphics) ValidateNativeTexture(width, height int, texture graphicsdriver.NativeTexture) error {
+	if texture.Handle == 0 {
+		return fmt.Errorf("metal: the native texture handle must not be nil")
+	}
+	switch texture.Kind {
+	case graphicsdriver.NativeTextureKindMetal:
+		t := mtl.NewTexture(unsafe.Pointer(texture.Handle))
+		if typ := t.TextureType(); typ != mtl.TextureType2D {
+			return fmt.Errorf("metal: the texture type must be MTLTextureType2D but %d", typ)
+		}
+		if w, h := t.Width(), t.Height(); w < width || h < height {
+			return fmt.Errorf("metal: the texture size (%d, %d) must be equal to or larger than the image size (%d, %d)", w, h, width, height)
+		}
+	case graphicsdriver.NativeTextureKindIOSurface:
+		// Creating a texture is the only way to check the IOSurface's format and size.
+		t, err := g.newTextureWithIOSurface(width, height, texture.Handle)
+		if err != nil {
+			return err
+		}
+		t.Release()
+	default:
+		return fmt.Errorf("metal: the native texture kind %s is not supported", texture.Kind)
+	}
+	return nil
+}
+
+// NewNativeImage creates an image from the given Metal texture or IOSurface.
+// A native image can be used only as a rendering source.
+func (g *Graphics) NewNativeImage(width, height int, texture graphicsdriver.NativeTexture) (graphicsdriver.Image, error) {
+	g.checkSize(width, height)
+	var t mtl.Texture
+	switch texture.Kind {
+	case graphicsdriver.NativeTextureKindMetal:
+		t = mtl.NewTexture(unsafe.Pointer(texture.Handle))
+		// The texture is owned by the creator. Retain it so that Dispose can release it.
+		t.Retain()
+	case graphicsdriver.NativeTextureKindIOSurface:
+		var err error
+		t, err = g.newTextureWithIOSurface(width, height, texture.Handle)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("metal: the native texture kind %s is not supported", texture.Kind)
+	}
+	i := &Image{
+		id:       g.genNextImageID(),
+		graphics: g,
+		width:    width,
+		height:   height,
+		texture:  t,
+		native:   true,
+	}
+	g.addImage(i)
+	return i, nil
+}
+
 func (g *Graphics) NewScreenFramebufferImage(width, height int) (graphicsdriver.Image, error) {
 	g.view.setDrawableSize(width, height)
 	i := &Image{
@@ -572,6 +739,27 @@ func (g *Graphics) SetTransparent(transparent bool) {
 	g.transparent = transparent
 }
 
+// SetLinearBlendingEnabled makes textures and the screen sRGB-encoded so that sampling decodes colors
+// to linear space and blending happens in linear space.
+//
+// SetLinearBlendingEnabled must be called before Initialize.
+func (g *Graphics) SetLinearBlendingEnabled(enabled bool) {
+	g.linearBlending = enabled
+}
+
+// IsLinearBlendingSupported reports whether linear blending works. Metal always supports sRGB pixel formats.
+func (g *Graphics) IsLinearBlendingSupported() bool {
+	return true
+}
+
+// texturePixelFormat returns the pixel format for offscreen textures.
+func (g *Graphics) texturePixelFormat() mtl.PixelFormat {
+	if g.linearBlending {
+		return mtl.PixelFormatRGBA8UNormSRGB
+	}
+	return mtl.PixelFormatRGBA8UNorm
+}
+
 func operationToBlendFactor(c graphicsdriver.Operation) mtl.BlendFactor {
 	switch c {
 	case graphicsdriver.Zero:
@@ -609,6 +797,9 @@ func (g *Graphics) Initialize() error {
 	for _, dss := range g.dsss {
 		dss.Release()
 	}
+	if g.depthDSS != (mtl.DepthStencilState{}) {
+		g.depthDSS.Release()
+	}
 	if g.dsss == nil {
 		g.dsss = map[stencilMode]mtl.DepthStencilState{}
 	}
@@ -616,6 +807,9 @@ func (g *Graphics) Initialize() error {
 	if g.transparent {
 		g.view.ml.SetOpaque(false)
 	}
+	if g.linearBlending {
+		g.view.ml.SetPixelFormat(mtl.PixelFormatBGRA8UNormSRGB)
+	}
 
 	replaces := map[string]string{
 		"{{.FilterNearest}}":      fmt.Sprintf("%d", graphicsdriver.FilterNearest),
@@ -639,7 +833,7 @@ func (g *Graphics) Initialize() error {
 		return err
 	}
 	fs, err := lib.MakeFunction(
-		fmt.Sprintf("FragmentShader_%d_%d_%d", 0, graphicsdriver.FilterScreen, graphicsdriver.AddressUnsafe))
+		fmt.Sprintf("FragmentShader_%d_%d_%d_%d", 0, graphicsdriver.FilterScreen, graphicsdriver.AddressUnsafe, 0))
 	if err != nil {
 		return err
 	}
@@ -677,51 +871,66 @@ func (g *Graphics) Initialize() error {
 							drawWithStencil,
 							noStencil,
 						} {
-							cmi := 0
-							if cm {
-								cmi = 1
-							}
-							fs, err := lib.MakeFunction(fmt.Sprintf("FragmentShader_%d_%d_%d", cmi, f, a))
-							if err != nil {
-								return err
-							}
-							rpld := mtl.RenderPipelineDescriptor{
-								VertexFunction:   vs,
-								FragmentFunction: fs,
-							}
-							if stencil != noStencil {
-								rpld.StencilAttachmentPixelFormat = mtl.PixelFormatStencil8
-							}
-
-							pix := mtl.PixelFormatRGBA8UNorm
-							if screen {
-								pix = g.view.colorPixelFormat()
-							}
-							rpld.ColorAttachments[0].PixelFormat = pix
-							rpld.ColorAttachments[0].BlendingEnabled = true
-
-							src, dst := c.Operations()
-							rpld.ColorAttachments[0].DestinationAlphaBlendFactor = operationToBlendFactor(dst)
-							rpld.ColorAttachments[0].DestinationRGBBlendFactor = operationToBlendFactor(dst)
-							rpld.ColorAttachments[0].SourceAlphaBlendFactor = operationToBlendFactor(src)
-							rpld.ColorAttachments[0].SourceRGBBlendFactor = operationToBlendFactor(src)
-							if stencil == prepareStencil {
-								rpld.ColorAttachments[0].WriteMask = mtl.ColorWriteMaskNone
-							} else {
-								rpld.ColorAttachments[0].WriteMask = mtl.ColorWriteMaskAll
-							}
-							rps, err := g.view.getMTLDevice().MakeRenderPipelineState(rpld)
-							if err != nil {
-								return err
+							for _, depth := range []bool{false, true} {
+								// The screen never has a depth buffer, and the depth test is not used with a stencil buffer.
+								if depth && (screen || stencil != noStencil) {
+									continue
+								}
+
+								cmi := 0
+								if cm {
+									cmi = 1
+								}
+								di := 0
+								if depth {
+									di = 1
+								}
+								fs, err := lib.MakeFunction(fmt.Sprintf("FragmentShader_%d_%d_%d_%d", cmi, f, a, di))
+								if err != nil {
+									return err
+								}
+								rpld := mtl.RenderPipelineDescriptor{
+									VertexFunction:   vs,
+									FragmentFunction: fs,
+								}
+								if stencil != noStencil {
+									rpld.StencilAttachmentPixelFormat = mtl.PixelFormatStencil8
+								}
+								if depth {
+									rpld.DepthAttachmentPixelFormat = mtl.PixelFormatDepth32Float
+								}
+
+								pix := g.texturePixelFormat()
+								if screen {
+									pix = g.view.colorPixelFormat()
+								}
+								rpld.ColorAttachments[0].PixelFormat = pix
+								rpld.ColorAttachments[0].BlendingEnabled = true
+
+								src, dst := c.Operations()
+								rpld.ColorAttachments[0].DestinationAlphaBlendFactor = operationToBlendFactor(dst)
+								rpld.ColorAttachments[0].DestinationRGBBlendFactor = operationToBlendFactor(dst)
+								rpld.ColorAttachments[0].SourceAlphaBlendFactor = operationToBlendFactor(src)
+								rpld.ColorAttachments[0].SourceRGBBlendFactor = operationToBlendFactor(src)
+								if stencil == prepareStencil {
+									rpld.ColorAttachments[0].WriteMask = mtl.ColorWriteMaskNone
+								} else {
+									rpld.ColorAttachments[0].WriteMask = mtl.ColorWriteMaskAll
+								}
+								rps, err := g.view.getMTLDevice().MakeRenderPipelineState(rpld)
+								if err != nil {
+									return err
+								}
+								g.rpss[rpsKey{
+									screen:        screen,
+									useColorM:     cm,
+									filter:        f,
+									address:       a,
+									compositeMode: c,
+									stencilMode:   stencil,
+									depthTest:     depth,
+								}] = rps
 							}
-							g.rpss[rpsKey{
-								screen:        screen,
-								useColorM:     cm,
-								filter:        f,
-								address:       a,
-								compositeMode: c,
-								stencilMode:   stencil,
-							}] = rps
 						}
 					}
 				}
@@ -743,6 +952,7 @@ func (g *Graphics) Initialize() error {
 			DepthStencilPassOperation: mtl.StencilOperationInvert,
 			StencilCompareFunction:    mtl.CompareFunctionAlways,
 		},
+		DepthCompareFunction: mtl.CompareFunctionAlways,
 	})
 	g.dsss[drawWithStencil] = g.view.getMTLDevice().MakeDepthStencilState(mtl.DepthStencilDescriptor{
 		BackFaceStencil: mtl.StencilDescriptor{
@@ -757,6 +967,7 @@ func (g *Graphics) Initialize() error {
 			DepthStencilPassOperation: mtl.StencilOperationKeep,
 			StencilCompareFunction:    mtl.CompareFunctionNotEqual,
 		},
+		DepthCompareFunction: mtl.CompareFunctionAlways,
 	})
 	g.dsss[noStencil] = g.view.getMTLDevice().MakeDepthStencilState(mtl.DepthStencilDescriptor{
 		BackFaceStencil: mtl.StencilDescriptor{
@@ -771,6 +982,23 @@ func (g *Graphics) Initialize() error {
 			DepthStencilPassOperation: mtl.StencilOperationKeep,
 			StencilCompareFunction:    mtl.CompareFunctionAlways,
 		},
+		DepthCompareFunction: mtl.CompareFunctionAlways,
+	})
+	g.depthDSS = g.view.getMTLDevice().MakeDepthStencilState(mtl.DepthStencilDescriptor{
+		BackFaceStencil: mtl.StencilDescriptor{
+			StencilFailureOperation:   mtl.StencilOperationKeep,
+			DepthFailureOperation:     mtl.StencilOperationKeep,
+			DepthStencilPassOperation: mtl.StencilOperationKeep,
+			StencilCompareFunction:    mtl.CompareFunctionAlways,
+		},
+		FrontFaceStencil: mtl.StencilDescriptor{
+			StencilFailureOperation:   mtl.StencilOperationKeep,
+			DepthFailureOperation:     mtl.StencilOperationKeep,
+			DepthStencilPassOperation: mtl.StencilOperationKeep,
+			StencilCompareFunction:    mtl.CompareFunctionAlways,
+		},
+		DepthCompareFunction: mtl.CompareFunctionLessEqual,
+		DepthWriteEnabled:    true,
 	})
 
 	g.cq = g.view.getMTLDevice().MakeCommandQueue()
@@ -786,15 +1014,19 @@ func (g *Graphics) flushRenderCommandEncoderIfNeeded() {
 	g.lastDst = nil
 }
 
-func (g *Graphics) draw(rps mtl.RenderPipelineState, dst *Image, dstRegion graphicsdriver.Region, srcs [graphics.ShaderImageNum]*Image, indexLen int, indexOffset int, uniforms []graphicsdriver.Uniform, stencilMode stencilMode) error {
+func (g *Graphics) draw(rps mtl.RenderPipelineState, dst *Image, dstRegion graphicsdriver.Region, srcs [graphics.ShaderImageNum]*Image, indexLen int, indexOffset int, uniforms []graphicsdriver.Uniform, stencilMode stencilMode, depthTest bool) error {
 	// When prepareing a stencil buffer, flush the current render command encoder
 	// to make sure the stencil buffer is cleared when loading.
 	// TODO: What about clearing the stencil buffer by vertices?
-	if g.lastDst != dst || (g.lastStencilMode == noStencil) != (stencilMode == noStencil) || stencilMode == prepareStencil {
+	//
+	// The attachments of a render pass must match the pipeline state, so switching the depth test also
+	// requires a new render command encoder.
+	if g.lastDst != dst || (g.lastStencilMode == noStencil) != (stencilMode == noStencil) || stencilMode == prepareStencil || g.lastDepthTest != depthTest {
 		g.flushRenderCommandEncoderIfNeeded()
 	}
 	g.lastDst = dst
 	g.lastStencilMode = stencilMode
+	g.lastDepthTest = depthTest
 
 	if g.rce == (mtl.RenderCommandEncoder{}) {
 		rpd := mtl.RenderPassDescriptor{}
@@ -823,6 +1055,19 @@ func (g *Graphics) draw(rps mtl.RenderPipelineState, dst *Image, dstRegion graph
 			rpd.StencilAttachment.Texture = dst.stencil
 		}
 
+		if depthTest {
+			dst.ensureDepth()
+			if dst.depthNeedsClear {
+				rpd.DepthAttachment.LoadAction = mtl.LoadActionClear
+				dst.depthNeedsClear = false
+			} else {
+				rpd.DepthAttachment.LoadAction = mtl.LoadActionLoad
+			}
+			rpd.DepthAttachment.StoreAction = mtl.StoreActionStore
+			rpd.DepthAttachment.Texture = dst.depth
+			rpd.DepthAttachment.ClearDepth = 1
+		}
+
 		if g.cb == (mtl.CommandBuffer{}) {
 			g.cb = g.cq.MakeCommandBuffer()
 		}
@@ -837,7 +1082,7 @@ func (g *Graphics) draw(rps mtl.RenderPipelineState, dst *Image, dstRegion graph
 		OriginY: 0,
 		Width:   float64(w),
 		Height:  float64(h),
-		ZNear:   -1,
+		ZNear:   0,
 		ZFar:    1,
 	})
 	g.rce.SetScissorRect(mtl.ScissorRect{
@@ -868,14 +1113,18 @@ func (g *Graphics) draw(rps mtl.RenderPipelineState, dst *Image, dstRegion graph
 		}
 	}
 
-	g.rce.SetDepthStencilState(g.dsss[stencilMode])
+	if depthTest {
+		g.rce.SetDepthStencilState(g.depthDSS)
+	} else {
+		g.rce.SetDepthStencilState(g.dsss[stencilMode])
+	}
 
 	g.rce.DrawIndexedPrimitives(mtl.PrimitiveTypeTriangle, indexLen, mtl.IndexTypeUInt16, g.ib, indexOffset*2)
 
 	return nil
 }
 
-func (g *Graphics) DrawTriangles(dstID graphicsdriver.ImageID, srcIDs [graphics.ShaderImageNum]graphicsdriver.ImageID, offsets [graphics.ShaderImageNum - 1][2]float32, shaderID graphicsdriver.ShaderID, indexLen int, indexOffset int, mode graphicsdriver.CompositeMode, colorM graphicsdriver.ColorM, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, uniforms []graphicsdriver.Uniform, evenOdd bool) error {
+func (g *Graphics) DrawTriangles(dstID graphicsdriver.ImageID, srcIDs [graphics.ShaderImageNum]graphicsdriver.ImageID, offsets [graphics.ShaderImageNum - 1][2]float32, shaderID graphicsdriver.ShaderID, indexLen int, indexOffset int, mode graphicsdriver.CompositeMode, colorM graphicsdriver.ColorM, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, uniforms []graphicsdriver.Uniform, evenOdd bool, depthTest bool) error {
 	dst := g.images[dstID]
 
 	if dst.screen {
@@ -905,6 +1154,7 @@ func (g *Graphics) DrawTriangles(dstID graphicsdriver.ImageID, srcIDs [graphics.
 					address:       address,
 					compositeMode: mode,
 					stencilMode:   stencil,
+					depthTest:     depthTest && stencil == noStencil,
 				}]
 			}
 		}
@@ -923,6 +1173,16 @@ func (g *Graphics) DrawTriangles(dstID graphicsdriver.ImageID, srcIDs [graphics.
 		if filter == graphicsdriver.FilterScreen {
 			scale = float32(dst.width) / float32(srcs[0].width)
 		}
+		// The geometry matrix is column-major in Metal.
+		geoMBody := []float32{1, 0, 0, 1}
+		geoMTranslation := []float32{0, 0}
+		colorScale := []float32{1, 1, 1, 1}
+		if len(uniforms) > 0 {
+			m := uniforms[graphics.DefaultShaderGeoMUniformVariableIndex].Float32s
+			geoMBody = []float32{m[0], m[2], m[1], m[3]}
+			geoMTranslation = []float32{m[4], m[5]}
+			colorScale = uniforms[graphics.DefaultShaderColorScaleUniformVariableIndex].Float32s
+		}
 		uniformVars = []graphicsdriver.Uniform{
 			{
 				Float32s: []float32{float32(w), float32(h)},
@@ -947,6 +1207,15 @@ func (g *Graphics) DrawTriangles(dstID graphicsdriver.ImageID, srcIDs [graphics.
 					srcRegion.Y + srcRegion.Height,
 				},
 			},
+			{
+				Float32s: geoMBody,
+			},
+			{
+				Float32s: geoMTranslation,
+			},
+			{
+				Float32s: colorScale,
+			},
 		}
 	} else {
 		for _, stencil := range []stencilMode{
@@ -955,7 +1224,7 @@ func (g *Graphics) DrawTriangles(dstID graphicsdriver.ImageID, srcIDs [graphics.
 			noStencil,
 		} {
 			var err error
-			rpss[stencil], err = g.shaders[shaderID].RenderPipelineState(g.view.getMTLDevice(), mode, stencil)
+			rpss[stencil], err = g.shaders[shaderID].RenderPipelineState(g.view.getMTLDevice(), g.texturePixelFormat(), mode, stencil, depthTest && stencil == noStencil)
 			if err != nil {
 				return err
 			}
@@ -1024,14 +1293,14 @@ func (g *Graphics) DrawTriangles(dstID graphicsdriver.ImageID, srcIDs [graphics.
 	}
 
 	if evenOdd {
-		if err := g.draw(rpss[prepareStencil], dst, dstRegion, srcs, indexLen, indexOffset, uniformVars, prepareStencil); err != nil {
+		if err := g.draw(rpss[prepareStencil], dst, dstRegion, srcs, indexLen, indexOffset, uniformVars, prepareStencil, false); err != nil {
 			return err
 		}
-		if err := g.draw(rpss[drawWithStencil], dst, dstRegion, srcs, indexLen, indexOffset, uniformVars, drawWithStencil); err != nil {
+		if err := g.draw(rpss[drawWithStencil], dst, dstRegion, srcs, indexLen, indexOffset, uniformVars, drawWithStencil, false); err != nil {
 			return err
 		}
 	} else {
-		if err := g.draw(rpss[noStencil], dst, dstRegion, srcs, indexLen, indexOffset, uniformVars, noStencil); err != nil {
+		if err := g.draw(rpss[noStencil], dst, dstRegion, srcs, indexLen, indexOffset, uniformVars, noStencil, depthTest); err != nil {
 			return err
 		}
 	}
@@ -1039,6 +1308,60 @@ func (g *Graphics) DrawTriangles(dstID graphicsdriver.ImageID, srcIDs [graphics.
 	return nil
 }
 
+func (g *Graphics) CopyPixels(dstID, srcID graphicsdriver.ImageID, dstX, dstY, srcX, srcY, width, height int) error {
+	dst := g.images[dstID]
+	src := g.images[srcID]
+	if dst.screen || dst.compressed {
+		panic("metal: CopyPixels cannot copy pixels to the screen or a compressed texture")
+	}
+	if src.screen || src.compressed {
+		panic("metal: CopyPixels cannot copy pixels from the screen or a compressed texture")
+	}
+
+	g.flushRenderCommandEncoderIfNeeded()
+
+	if g.cb == (mtl.CommandBuffer{}) {
+		g.cb = g.cq.MakeCommandBuffer()
+	}
+	bce := g.cb.MakeBlitCommandEncoder()
+	so := mtl.Origin{X: srcX, Y: srcY, Z: 0}
+	ss := mtl.Size{Width: width, Height: height, Depth: 1}
+	do := mtl.Origin{X: dstX, Y: dstY, Z: 0}
+	bce.CopyFromTexture(src.texture, 0, 0, so, ss, dst.texture, 0, 0, do)
+	bce.EndEncoding()
+	return nil
+}
+
+func (g *Graphics) FillRect(dstID graphicsdriver.ImageID, x, y, width, height int, red, green, blue, alpha float32) error {
+	dst := g.images[dstID]
+	if dst.screen || dst.compressed {
+		panic("metal: FillRect cannot be called on the screen or a compressed texture")
+	}
+
+	// The load action of a render pass clears the whole texture and Metal has no scissored clear.
+	// Replace the pixels of the region instead.
+	pix := make([]byte, 4*width*height)
+	clr := [4]byte{
+		byte(red*0xff + 0.5),
+		byte(green*0xff + 0.5),
+		byte(blue*0xff + 0.5),
+		byte(alpha*0xff + 0.5),
+	}
+	for i := 0; i < len(pix); i += 4 {
+		copy(pix[i:i+4], clr[:])
+	}
+	dst.ReplacePixels([]*graphicsdriver.ReplacePixelsArgs{
+		{
+			Pixels: pix,
+			X:      x,
+			Y:      y,
+			Width:  width,
+			Height: height,
+		},
+	})
+	return nil
+}
+
 func (g *Graphics) SetVsyncEnabled(enabled bool) {
 	g.view.setDisplaySyncEnabled(enabled)
 }
@@ -1071,6 +1394,10 @@ func (g *Graphics) HasHighPrecisionFloat() bool {
 	return true
 }
 
+func (g *Graphics) GraphicsLibrary() graphicsdriver.GraphicsLibrary {
+	return graphicsdriver.GraphicsLibraryMetal
+}
+
 func (g *Graphics) MaxImageSize() int {
 	if g.maxImageSize != 0 {
 		return g.maxImageSize
@@ -1105,6 +1432,49 @@ func (g *Graphics) MaxImageSize() int {
 	return g.maxImageSize
 }
 
+// NativeHandles returns the id<MTLDevice> and the id<MTLCommandQueue> pointers.
+func (g *Graphics) NativeHandles() (device, commandQueue uintptr) {
+	return uintptr(g.view.getMTLDevice().Device()), uintptr(g.cq.CommandQueue())
+}
+
+// CommitNativeCommands commits the current command buffer without presenting the screen
+// so that commands encoded by others after this are executed after Ebiten's commands.
+func (g *Graphics) CommitNativeCommands() {
+	g.flushIfNeeded(false)
+}
+
+func (g *Graphics) DriverInfo() graphicsdriver.DriverInfo {
+	d := g.view.getMTLDevice()
+	info := graphicsdriver.DriverInfo{
+		GraphicsLibrary: graphicsdriver.GraphicsLibraryMetal,
+		Adapter:         d.Name,
+	}
+	// Report the most capable feature set as the feature level.
+	for _, fs := range []struct {
+		featureSet mtl.FeatureSet
+		name       string
+	}{
+		{mtl.FeatureSet_iOS_GPUFamily5_v1, "iOS GPU family 5 v1"},
+		{mtl.FeatureSet_iOS_GPUFamily4_v1, "iOS GPU family 4 v1"},
+		{mtl.FeatureSet_iOS_GPUFamily3_v1, "iOS GPU family 3 v1"},
+		{mtl.FeatureSet_iOS_GPUFamily2_v1, "iOS GPU family 2 v1"},
+		{mtl.FeatureSet_iOS_GPUFamily1_v1, "iOS GPU family 1 v1"},
+		{mtl.FeatureSet_tvOS_GPUFamily2_v1, "tvOS GPU family 2 v1"},
+		{mtl.FeatureSet_tvOS_GPUFamily1_v1, "tvOS GPU family 1 v1"},
+		{mtl.FeatureSet_macOS_GPUFamily2_v1, "macOS GPU family 2 v1"},
+		{mtl.FeatureSet_macOS_GPUFamily1_v1, "macOS GPU family 1 v1"},
+	} {
+		if d.SupportsFeatureSet(fs.featureSet) {
+			info.FeatureLevel = fs.name
+			break
+		}
+	}
+	if d.LowPower {
+		info.Adapter += " (low power)"
+	}
+	return info
+}
+
 func (g *Graphics) NewShader(program *shaderir.Program) (graphicsdriver.Shader, error) {
 	s, err := newShader(g.view.getMTLDevice(), g.genNextShaderID(), program)
 	if err != nil {
@@ -1136,6 +1506,16 @@ type Image struct {
 	screen   bool
 	texture  mtl.Texture
 	stencil  mtl.Texture
+	depth    mtl.Texture
+
+	// depthNeedsClear indicates whether the depth buffer must be cleared at the next render pass.
+	depthNeedsClear bool
+
+	// compressed indicates whether the texture is a compressed texture, which cannot be a render target.
+	compressed bool
+
+	// native indicates whether the texture is created outside of Ebiten, which cannot be a render target.
+	native bool
 }
 
 func (i *Image) ID() graphicsdriver.ImageID {
@@ -1143,7 +1523,7 @@ func (i *Image) ID() graphicsdriver.ImageID {
 }
 
 func (i *Image) internalSize() (int, int) {
-	if i.screen {
+	if i.screen || i.compressed || i.native {
 		return i.width, i.height
 	}
 	return graphics.InternalImageSize(i.width), graphics.InternalImageSize(i.height)
@@ -1154,6 +1534,10 @@ func (i *Image) Dispose() {
 		i.stencil.Release()
 		i.stencil = mtl.Texture{}
 	}
+	if i.depth != (mtl.Texture{}) {
+		i.depth.Release()
+		i.depth = mtl.Texture{}
+	}
 	if i.texture != (mtl.Texture{}) {
 		i.texture.Release()
 		i.texture = mtl.Texture{}
@@ -1187,6 +1571,9 @@ func (i *Image) syncTexture() {
 }
 
 func (i *Image) ReadPixels(buf []byte) error {
+	if i.compressed {
+		return fmt.Errorf("metal: ReadPixels cannot be called on a compressed texture")
+	}
 	if got, want := len(buf), 4*i.width*i.height; got != want {
 		return fmt.Errorf("metal: len(buf) must be %d but %d at ReadPixels", want, got)
 	}
@@ -1201,6 +1588,10 @@ func (i *Image) ReadPixels(buf []byte) error {
 }
 
 func (i *Image) ReplacePixels(args []*graphicsdriver.ReplacePixelsArgs) {
+	if i.compressed {
+		panic("metal: ReplacePixels cannot be called on a compressed texture")
+	}
+
 	g := i.graphics
 
 	g.flushRenderCommandEncoderIfNeeded()
@@ -1233,7 +1624,7 @@ func (i *Image) ReplacePixels(args []*graphicsdriver.ReplacePixelsArgs) {
 	// The texture cannot be reused until sending the pixels finishes, then create new ones for each call.
 	td := mtl.TextureDescriptor{
 		TextureType: mtl.TextureType2D,
-		PixelFormat: mtl.PixelFormatRGBA8UNorm,
+		PixelFormat: g.texturePixelFormat(),
 		Width:       w,
 		Height:      h,
 		StorageMode: storageMode,
@@ -1266,7 +1657,10 @@ func (i *Image) mtlTexture() mtl.Texture {
 	if i.screen {
 		g := i.graphics
 		if g.screenDrawable == (ca.MetalDrawable{}) {
+			// Waiting for the next drawable is the wait for presenting.
+			endRegion := tracing.StartRegion("ebiten.present")
 			drawable := g.view.nextDrawable()
+			endRegion()
 			if drawable == (ca.MetalDrawable{}) {
 				return mtl.Texture{}
 			}
@@ -1294,3 +1688,32 @@ func (i *Image) ensureStencil() {
 	}
 	i.stencil = i.graphics.view.getMTLDevice().MakeTexture(td)
 }
+
+func (i *Image) ensureDepth() {
+	if i.depth != (mtl.Texture{}) {
+		return
+	}
+
+	td := mtl.TextureDescriptor{
+		TextureType: mtl.TextureType2D,
+		PixelFormat: mtl.PixelFormatDepth32Float,
+		Width:       graphics.InternalImageSize(i.width),
+		Height:      graphics.InternalImageSize(i.height),
+		StorageMode: mtl.StorageModePrivate,
+		Usage:       mtl.TextureUsageRenderTarget,
+	}
+	i.depth = i.graphics.view.getMTLDevice().MakeTexture(td)
+	i.depthNeedsClear = true
+}
+
+func (i *Image) ClearDepth() error {
+	if i.depth == (mtl.Texture{}) {
+		return nil
+	}
+	// The depth buffer is cleared when the next render pass with the depth test starts.
+	if i.graphics.lastDst == i {
+		i.graphics.flushRenderCommandEncoderIfNeeded()
+	}
+	i.depthNeedsClear = true
+	return nil
+}
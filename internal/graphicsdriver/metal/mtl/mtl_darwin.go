@@ -105,9 +105,24 @@ const (
 	PixelFormatRGBA8UNormSRGB PixelFormat = 71  // Ordinary format with four 8-bit normalized unsigned integer components in RGBA order with conversion between sRGB and linear space.
 	PixelFormatBGRA8UNorm     PixelFormat = 80  // Ordinary format with four 8-bit normalized unsigned integer components in BGRA order.
 	PixelFormatBGRA8UNormSRGB PixelFormat = 81  // Ordinary format with four 8-bit normalized unsigned integer components in BGRA order with conversion between sRGB and linear space.
+	PixelFormatDepth32Float   PixelFormat = 252 // A pixel format with one 32-bit floating-point component, used for a depth render target.
 	PixelFormatStencil8       PixelFormat = 253 // A pixel format with an 8-bit unsigned integer component, used for a stencil render target.
 )
 
+// Compressed pixel formats.
+const (
+	PixelFormatBC1RGBA       PixelFormat = 130 // Compressed format with four normalized unsigned integer components in RGBA order, using 8 bytes per 4x4 block.
+	PixelFormatBC2RGBA       PixelFormat = 132 // Compressed format with four normalized unsigned integer components in RGBA order, using 16 bytes per 4x4 block.
+	PixelFormatBC3RGBA       PixelFormat = 134 // Compressed format with four normalized unsigned integer components in RGBA order, using 16 bytes per 4x4 block.
+	PixelFormatBC4RUNorm     PixelFormat = 140 // Compressed format with one normalized unsigned integer component.
+	PixelFormatBC5RGUNorm    PixelFormat = 142 // Compressed format with two normalized unsigned integer components.
+	PixelFormatBC6HRGBUFloat PixelFormat = 151 // Compressed format with three unsigned floating-point components.
+	PixelFormatBC7RGBAUNorm  PixelFormat = 152 // Compressed format with four normalized unsigned integer components in RGBA order.
+	PixelFormatEACRGBA8      PixelFormat = 178 // Compressed format with four normalized unsigned integer components in RGBA order.
+	PixelFormatETC2RGB8      PixelFormat = 180 // Compressed format with three normalized unsigned integer components in RGB order.
+	PixelFormatASTC4x4LDR    PixelFormat = 204 // ASTC-compressed format with low-dynamic-range content, using 16 bytes per 4x4 block.
+)
+
 // PrimitiveType defines geometric primitive types for drawing commands.
 //
 // Reference: https://developer.apple.com/documentation/metal/mtlprimitivetype.
@@ -362,6 +377,9 @@ type RenderPipelineDescriptor struct {
 
 	// StencilAttachmentPixelFormat is the pixel format of the attachment that stores stencil data.
 	StencilAttachmentPixelFormat PixelFormat
+
+	// DepthAttachmentPixelFormat is the pixel format of the attachment that stores depth data.
+	DepthAttachmentPixelFormat PixelFormat
 }
 
 // RenderPipelineColorAttachmentDescriptor describes a color render target that specifies
@@ -392,6 +410,9 @@ type RenderPassDescriptor struct {
 
 	// StencilAttachment is state information for an attachment that stores stencil data.
 	StencilAttachment RenderPassStencilAttachment
+
+	// DepthAttachment is state information for an attachment that stores depth data.
+	DepthAttachment RenderPassDepthAttachment
 }
 
 // RenderPassColorAttachmentDescriptor describes a color render target that serves
@@ -411,6 +432,15 @@ type RenderPassStencilAttachment struct {
 	RenderPassAttachmentDescriptor
 }
 
+// RenderPassDepthAttachment describes a depth render target that serves as the output
+// destination for depth pixels generated by a render pass.
+//
+// Reference: https://developer.apple.com/documentation/metal/mtlrenderpassdepthattachmentdescriptor
+type RenderPassDepthAttachment struct {
+	RenderPassAttachmentDescriptor
+	ClearDepth float64
+}
+
 // RenderPassAttachmentDescriptor describes a render target that serves
 // as the output destination for pixels generated by a render pass.
 //
@@ -527,6 +557,7 @@ func (d Device) MakeRenderPipelineState(rpd RenderPipelineDescriptor) (RenderPip
 		ColorAttachment0SourceRGBBlendFactor:        C.uint8_t(c.SourceRGBBlendFactor),
 		ColorAttachment0WriteMask:                   C.uint8_t(c.WriteMask),
 		StencilAttachmentPixelFormat:                C.uint8_t(rpd.StencilAttachmentPixelFormat),
+		DepthAttachmentPixelFormat:                  C.uint16_t(rpd.DepthAttachmentPixelFormat),
 	}
 	rps := C.Device_MakeRenderPipelineState(d.device, descriptor)
 	if rps.RenderPipelineState == nil {
@@ -569,10 +600,31 @@ func (d Device) MakeTexture(td TextureDescriptor) Texture {
 	}
 }
 
+// MakeTextureWithIOSurface creates a texture object that shares the storage of the given IOSurfaceRef.
+//
+// Reference: https://developer.apple.com/documentation/metal/mtldevice/1433378-newtexturewithdescriptor
+func (d Device) MakeTextureWithIOSurface(td TextureDescriptor, iosurface unsafe.Pointer, plane int) Texture {
+	descriptor := C.struct_TextureDescriptor{
+		TextureType: C.uint16_t(td.TextureType),
+		PixelFormat: C.uint16_t(td.PixelFormat),
+		Width:       C.uint_t(td.Width),
+		Height:      C.uint_t(td.Height),
+		StorageMode: C.uint8_t(td.StorageMode),
+		Usage:       C.uint8_t(td.Usage),
+	}
+	return Texture{
+		texture: C.Device_MakeTextureWithIOSurface(d.device, descriptor, iosurface, C.uint_t(plane)),
+	}
+}
+
 // MakeDepthStencilState creates a new object that contains depth and stencil test state.
 //
 // Reference: https://developer.apple.com/documentation/metal/mtldevice/1433412-makedepthstencilstate
 func (d Device) MakeDepthStencilState(dsd DepthStencilDescriptor) DepthStencilState {
+	depthWriteEnabled := 0
+	if dsd.DepthWriteEnabled {
+		depthWriteEnabled = 1
+	}
 	descriptor := C.struct_DepthStencilDescriptor{
 		BackFaceStencilStencilFailureOperation:    C.uint8_t(dsd.BackFaceStencil.StencilFailureOperation),
 		BackFaceStencilDepthFailureOperation:      C.uint8_t(dsd.BackFaceStencil.DepthFailureOperation),
@@ -582,6 +634,8 @@ func (d Device) MakeDepthStencilState(dsd DepthStencilDescriptor) DepthStencilSt
 		FrontFaceStencilDepthFailureOperation:     C.uint8_t(dsd.FrontFaceStencil.DepthFailureOperation),
 		FrontFaceStencilDepthStencilPassOperation: C.uint8_t(dsd.FrontFaceStencil.DepthStencilPassOperation),
 		FrontFaceStencilStencilCompareFunction:    C.uint8_t(dsd.FrontFaceStencil.StencilCompareFunction),
+		DepthCompareFunction:                      C.uint8_t(dsd.DepthCompareFunction),
+		DepthWriteEnabled:                         C.uint8_t(depthWriteEnabled),
 	}
 	return DepthStencilState{
 		depthStencilState: C.Device_MakeDepthStencilState(d.device, descriptor),
@@ -612,6 +666,9 @@ type CommandQueue struct {
 	commandQueue unsafe.Pointer
 }
 
+// CommandQueue returns the underlying id<MTLCommandQueue> pointer.
+func (c CommandQueue) CommandQueue() unsafe.Pointer { return c.commandQueue }
+
 func (c CommandQueue) Release() {
 	C.CommandQueue_Release(c.commandQueue)
 }
@@ -692,6 +749,10 @@ func (cb CommandBuffer) MakeRenderCommandEncoder(rpd RenderPassDescriptor) Rende
 		StencilAttachmentLoadAction:  C.uint8_t(rpd.StencilAttachment.LoadAction),
 		StencilAttachmentStoreAction: C.uint8_t(rpd.StencilAttachment.StoreAction),
 		StencilAttachmentTexture:     rpd.StencilAttachment.Texture.texture,
+		DepthAttachmentLoadAction:    C.uint8_t(rpd.DepthAttachment.LoadAction),
+		DepthAttachmentStoreAction:   C.uint8_t(rpd.DepthAttachment.StoreAction),
+		DepthAttachmentTexture:       rpd.DepthAttachment.Texture.texture,
+		DepthAttachmentClearDepth:    C.double(rpd.DepthAttachment.ClearDepth),
 	}
 	return RenderCommandEncoder{CommandEncoder{C.CommandBuffer_MakeRenderCommandEncoder(cb.commandBuffer, descriptor)}}
 }
@@ -860,6 +921,10 @@ func NewTexture(texture unsafe.Pointer) Texture {
 // resource implements the Resource interface.
 func (t Texture) resource() unsafe.Pointer { return t.texture }
 
+func (t Texture) Retain() {
+	C.Texture_Retain(t.texture)
+}
+
 func (t Texture) Release() {
 	C.Texture_Release(t.texture)
 }
@@ -895,6 +960,13 @@ func (t Texture) Height() int {
 	return int(C.Texture_Height(t.texture))
 }
 
+// TextureType is the dimension and arrangement of the texture image data.
+//
+// Reference: https://developer.apple.com/documentation/metal/mtltexture/1516228-texturetype
+func (t Texture) TextureType() TextureType {
+	return TextureType(C.Texture_TextureType(t.texture))
+}
+
 // Buffer is a memory allocation for storing unformatted data
 // that is accessible to the GPU.
 //
@@ -1061,6 +1133,12 @@ type DepthStencilDescriptor struct {
 
 	// FrontFaceStencil is The stencil descriptor for front-facing primitives.
 	FrontFaceStencil StencilDescriptor
+
+	// DepthCompareFunction is the comparison that is performed between a fragment's depth value and the depth value in the attachment.
+	DepthCompareFunction CompareFunction
+
+	// DepthWriteEnabled indicates whether depth values can be written to the depth attachment.
+	DepthWriteEnabled bool
 }
 
 // StencilDescriptor is an object that defines the front-facing or back-facing stencil operations of a depth and stencil state object.
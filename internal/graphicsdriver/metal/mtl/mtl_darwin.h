@@ -47,6 +47,7 @@ struct RenderPipelineDescriptor {
   uint8_t ColorAttachment0SourceRGBBlendFactor;
   uint8_t ColorAttachment0WriteMask;
   uint8_t StencilAttachmentPixelFormat;
+  uint16_t DepthAttachmentPixelFormat;
 };
 
 struct RenderPipelineState {
@@ -69,6 +70,10 @@ struct RenderPassDescriptor {
   uint8_t StencilAttachmentLoadAction;
   uint8_t StencilAttachmentStoreAction;
   void *StencilAttachmentTexture;
+  uint8_t DepthAttachmentLoadAction;
+  uint8_t DepthAttachmentStoreAction;
+  void *DepthAttachmentTexture;
+  double DepthAttachmentClearDepth;
 };
 
 struct TextureDescriptor {
@@ -122,6 +127,8 @@ struct DepthStencilDescriptor {
   uint8_t FrontFaceStencilDepthFailureOperation;
   uint8_t FrontFaceStencilDepthStencilPassOperation;
   uint8_t FrontFaceStencilStencilCompareFunction;
+  uint8_t DepthCompareFunction;
+  uint8_t DepthWriteEnabled;
 };
 
 struct Device CreateSystemDefaultDevice();
@@ -139,6 +146,9 @@ void *Device_MakeBufferWithBytes(void *device, const void *bytes, size_t length,
 void *Device_MakeBufferWithLength(void *device, size_t length,
                                   uint16_t options);
 void *Device_MakeTexture(void *device, struct TextureDescriptor descriptor);
+void *Device_MakeTextureWithIOSurface(void *device,
+                                      struct TextureDescriptor descriptor,
+                                      void *iosurface, uint_t plane);
 void *Device_MakeDepthStencilState(void *device,
                                    struct DepthStencilDescriptor descriptor);
 
@@ -201,6 +211,7 @@ void BlitCommandEncoder_CopyFromTexture(
 
 void *Library_MakeFunction(void *library, const char *name);
 
+void Texture_Retain(void *texture);
 void Texture_Release(void *texture);
 void Texture_GetBytes(void *texture, void *pixelBytes, size_t bytesPerRow,
                       struct Region region, uint_t level);
@@ -208,6 +219,7 @@ void Texture_ReplaceRegion(void *texture, struct Region region, uint_t level,
                            void *pixelBytes, uint_t bytesPerRow);
 int Texture_Width(void *texture);
 int Texture_Height(void *texture);
+uint16_t Texture_TextureType(void *texture);
 
 size_t Buffer_Length(void *buffer);
 void Buffer_CopyToContents(void *buffer, void *data, size_t lengthInBytes);
@@ -86,6 +86,8 @@ Device_MakeRenderPipelineState(void *device,
       descriptor.ColorAttachment0WriteMask;
   renderPipelineDescriptor.stencilAttachmentPixelFormat =
       descriptor.StencilAttachmentPixelFormat;
+  renderPipelineDescriptor.depthAttachmentPixelFormat =
+      descriptor.DepthAttachmentPixelFormat;
   NSError *error;
   id<MTLRenderPipelineState> renderPipelineState = [(id<MTLDevice>)device
       newRenderPipelineStateWithDescriptor:renderPipelineDescriptor
@@ -127,6 +129,24 @@ void *Device_MakeTexture(void *device, struct TextureDescriptor descriptor) {
   return texture;
 }
 
+void *Device_MakeTextureWithIOSurface(void *device,
+                                      struct TextureDescriptor descriptor,
+                                      void *iosurface, uint_t plane) {
+  MTLTextureDescriptor *textureDescriptor = [[MTLTextureDescriptor alloc] init];
+  textureDescriptor.textureType = descriptor.TextureType;
+  textureDescriptor.pixelFormat = descriptor.PixelFormat;
+  textureDescriptor.width = descriptor.Width;
+  textureDescriptor.height = descriptor.Height;
+  textureDescriptor.storageMode = descriptor.StorageMode;
+  textureDescriptor.usage = descriptor.Usage;
+  id<MTLTexture> texture = [(id<MTLDevice>)device
+      newTextureWithDescriptor:textureDescriptor
+                     iosurface:(IOSurfaceRef)iosurface
+                         plane:plane];
+  [textureDescriptor release];
+  return texture;
+}
+
 void *Device_MakeDepthStencilState(void *device,
                                    struct DepthStencilDescriptor descriptor) {
   MTLDepthStencilDescriptor *depthStencilDescriptor =
@@ -147,6 +167,8 @@ void *Device_MakeDepthStencilState(void *device,
       descriptor.FrontFaceStencilDepthStencilPassOperation;
   depthStencilDescriptor.frontFaceStencil.stencilCompareFunction =
       descriptor.FrontFaceStencilStencilCompareFunction;
+  depthStencilDescriptor.depthCompareFunction = descriptor.DepthCompareFunction;
+  depthStencilDescriptor.depthWriteEnabled = descriptor.DepthWriteEnabled;
   id<MTLDepthStencilState> depthStencilState = [(id<MTLDevice>)device
       newDepthStencilStateWithDescriptor:depthStencilDescriptor];
   [depthStencilDescriptor release];
@@ -212,6 +234,14 @@ CommandBuffer_MakeRenderCommandEncoder(void *commandBuffer,
       descriptor.StencilAttachmentStoreAction;
   renderPassDescriptor.stencilAttachment.texture =
       (id<MTLTexture>)descriptor.StencilAttachmentTexture;
+  renderPassDescriptor.depthAttachment.loadAction =
+      descriptor.DepthAttachmentLoadAction;
+  renderPassDescriptor.depthAttachment.storeAction =
+      descriptor.DepthAttachmentStoreAction;
+  renderPassDescriptor.depthAttachment.texture =
+      (id<MTLTexture>)descriptor.DepthAttachmentTexture;
+  renderPassDescriptor.depthAttachment.clearDepth =
+      descriptor.DepthAttachmentClearDepth;
   id<MTLRenderCommandEncoder> rce = [(id<MTLCommandBuffer>)commandBuffer
       renderCommandEncoderWithDescriptor:renderPassDescriptor];
   [renderPassDescriptor release];
@@ -375,6 +405,8 @@ void *Library_MakeFunction(void *library, const char *name) {
       newFunctionWithName:[NSString stringWithUTF8String:name]];
 }
 
+void Texture_Retain(void *texture) { [(id<MTLTexture>)texture retain]; }
+
 void Texture_Release(void *texture) { [(id<MTLTexture>)texture release]; }
 
 void Texture_GetBytes(void *texture, void *pixelBytes, size_t bytesPerRow,
@@ -415,6 +447,10 @@ int Texture_Width(void *texture) { return [(id<MTLTexture>)texture width]; }
 
 int Texture_Height(void *texture) { return [(id<MTLTexture>)texture height]; }
 
+uint16_t Texture_TextureType(void *texture) {
+  return [(id<MTLTexture>)texture textureType];
+}
+
 size_t Buffer_Length(void *buffer) { return [(id<MTLBuffer>)buffer length]; }
 
 void Buffer_CopyToContents(void *buffer, void *data, size_t lengthInBytes) {
@@ -26,6 +26,7 @@ import (
 type shaderRpsKey struct {
 	compositeMode graphicsdriver.CompositeMode
 	stencilMode   stencilMode
+	depthTest     bool
 }
 
 type Shader struct {
@@ -85,10 +86,11 @@ func (s *Shader) init(device mtl.Device) error {
 	return nil
 }
 
-func (s *Shader) RenderPipelineState(device mtl.Device, compositeMode graphicsdriver.CompositeMode, stencilMode stencilMode) (mtl.RenderPipelineState, error) {
+func (s *Shader) RenderPipelineState(device mtl.Device, pixelFormat mtl.PixelFormat, compositeMode graphicsdriver.CompositeMode, stencilMode stencilMode, depthTest bool) (mtl.RenderPipelineState, error) {
 	if rps, ok := s.rpss[shaderRpsKey{
 		compositeMode: compositeMode,
 		stencilMode:   stencilMode,
+		depthTest:     depthTest,
 	}]; ok {
 		return rps, nil
 	}
@@ -100,9 +102,12 @@ func (s *Shader) RenderPipelineState(device mtl.Device, compositeMode graphicsdr
 	if stencilMode != noStencil {
 		rpld.StencilAttachmentPixelFormat = mtl.PixelFormatStencil8
 	}
+	if depthTest {
+		rpld.DepthAttachmentPixelFormat = mtl.PixelFormatDepth32Float
+	}
 
 	// TODO: For the precise pixel format, whether the render target is the screen or not must be considered.
-	rpld.ColorAttachments[0].PixelFormat = mtl.PixelFormatRGBA8UNorm
+	rpld.ColorAttachments[0].PixelFormat = pixelFormat
 	rpld.ColorAttachments[0].BlendingEnabled = true
 
 	src, dst := compositeMode.Operations()
@@ -124,6 +129,7 @@ func (s *Shader) RenderPipelineState(device mtl.Device, compositeMode graphicsdr
 	s.rpss[shaderRpsKey{
 		compositeMode: compositeMode,
 		stencilMode:   stencilMode,
+		depthTest:     depthTest,
 	}] = rps
 	return rps, nil
 }
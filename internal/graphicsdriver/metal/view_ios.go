@@ -36,6 +36,7 @@ import "C"
 import (
 	"unsafe"
 
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver/metal/mtl"
 )
 
@@ -70,3 +71,14 @@ const (
 	storageMode         = mtl.StorageModeShared
 	resourceStorageMode = mtl.ResourceStorageModeShared
 )
+
+// isCompressedTextureFormatAvailable reports whether the given compressed texture format is available.
+//
+// iOS GPUs support ETC2 and ASTC, but not BC formats.
+func isCompressedTextureFormatAvailable(format graphicsdriver.CompressedTextureFormat) bool {
+	switch format {
+	case graphicsdriver.CompressedTextureFormatETC2RGB8, graphicsdriver.CompressedTextureFormatETC2RGBA8, graphicsdriver.CompressedTextureFormatASTC4x4:
+		return true
+	}
+	return false
+}
@@ -18,6 +18,7 @@
 package metal
 
 import (
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver/metal/mtl"
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver/metal/ns"
 )
@@ -64,3 +65,14 @@ const (
 	storageMode         = mtl.StorageModeManaged
 	resourceStorageMode = mtl.ResourceStorageModeManaged
 )
+
+// isCompressedTextureFormatAvailable reports whether the given compressed texture format is available.
+//
+// macOS GPUs support BC formats. ETC2 and ASTC are available only on Apple silicon, and are not used so far.
+func isCompressedTextureFormatAvailable(format graphicsdriver.CompressedTextureFormat) bool {
+	switch format {
+	case graphicsdriver.CompressedTextureFormatBC1, graphicsdriver.CompressedTextureFormatBC2, graphicsdriver.CompressedTextureFormatBC3, graphicsdriver.CompressedTextureFormatBC4, graphicsdriver.CompressedTextureFormatBC5, graphicsdriver.CompressedTextureFormatBC6H, graphicsdriver.CompressedTextureFormatBC7:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphicsdriver
+
+import (
+	"fmt"
+)
+
+// NativeTextureKind represents the kind of a texture handle created by a native graphics API.
+type NativeTextureKind int
+
+const (
+	// NativeTextureKindOpenGL is an OpenGL texture name of GL_TEXTURE_2D.
+	NativeTextureKindOpenGL NativeTextureKind = iota
+
+	// NativeTextureKindMetal is id<MTLTexture>.
+	NativeTextureKindMetal
+
+	// NativeTextureKindIOSurface is IOSurfaceRef in the BGRA8 format.
+	NativeTextureKindIOSurface
+)
+
+func (n NativeTextureKind) String() string {
+	switch n {
+	case NativeTextureKindOpenGL:
+		return "OpenGL"
+	case NativeTextureKindMetal:
+		return "Metal"
+	case NativeTextureKindIOSurface:
+		return "IOSurface"
+	default:
+		return fmt.Sprintf("NativeTextureKind(%d)", n)
+	}
+}
+
+// NativeTexture represents a texture created outside of Ebiten.
+// The texture is owned by the creator and is never deleted by Ebiten.
+type NativeTexture struct {
+	Kind   NativeTextureKind
+	Handle uintptr
+}
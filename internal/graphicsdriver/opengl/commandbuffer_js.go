@@ -0,0 +1,687 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opengl
+
+import (
+	"math"
+	"reflect"
+	"runtime"
+	"syscall/js"
+	"unsafe"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/jsutil"
+)
+
+type glCommand int32
+
+// The values must be synced with commandBufferExecutorSource.
+const (
+	glCommandActiveTexture glCommand = iota
+	glCommandBindBuffer
+	glCommandBindFramebuffer
+	glCommandBindRenderbuffer
+	glCommandBindTexture
+	glCommandBlendFunc
+	glCommandBufferData
+	glCommandBufferSubData
+	glCommandClear
+	glCommandClearColor
+	glCommandColorMask
+	glCommandCompressedTexImage2D
+	glCommandCopyTexSubImage2D
+	glCommandDeleteBuffer
+	glCommandDeleteFramebuffer
+	glCommandDeleteProgram
+	glCommandDeleteRenderbuffer
+	glCommandDeleteTexture
+	glCommandDisable
+	glCommandDisableVertexAttribArray
+	glCommandDrawElements
+	glCommandEnable
+	glCommandEnableVertexAttribArray
+	glCommandFramebufferRenderbuffer
+	glCommandFramebufferTexture2D
+	glCommandPixelStorei
+	glCommandRenderbufferStorage
+	glCommandScissor
+	glCommandStencilFunc
+	glCommandStencilOp
+	glCommandTexImage2D
+	glCommandTexParameteri
+	glCommandTexSubImage2D
+	glCommandUniform1f
+	glCommandUniform1fv
+	glCommandUniform1i
+	glCommandUniform2fv
+	glCommandUniform3fv
+	glCommandUniform4fv
+	glCommandUniformMatrix2fv
+	glCommandUniformMatrix3fv
+	glCommandUniformMatrix4fv
+	glCommandUseProgram
+	glCommandVertexAttribPointer
+	glCommandViewport
+)
+
+// commandBufferExecutorSource is the source of a JS function that returns the object table and the executor.
+//
+// A GL object is referred to by its index in the object table. The index 0 is null.
+// Bytes and floats are encoded as their length followed by the data padded to 4 bytes.
+const commandBufferExecutorSource = `
+const objs = [null];
+let i32 = null;
+let u8 = null;
+let f32 = null;
+let i = 0;
+
+function obj() {
+  return objs[i32[i++]];
+}
+
+function bytes() {
+  const n = i32[i++];
+  const v = u8.subarray(4*i, 4*i + n);
+  i += (n + 3) >> 2;
+  return v;
+}
+
+function floats() {
+  const n = i32[i++];
+  const v = f32.subarray(i, i + n);
+  i += n;
+  return v;
+}
+
+function float32() {
+  return f32[i++];
+}
+
+function del(isObject, deleteObject) {
+  const id = i32[i++];
+  if (isObject === null || isObject.call(gl, objs[id])) {
+    deleteObject.call(gl, objs[id]);
+  }
+  objs[id] = null;
+}
+
+return {
+  objects: objs,
+  execute: function(buf, n) {
+    if (u8 === null || u8.buffer !== buf.buffer) {
+      u8 = new Uint8Array(buf.buffer);
+      f32 = new Float32Array(buf.buffer);
+    }
+    i32 = buf;
+    i = 0;
+    while (i < n) {
+      switch (i32[i++]) {
+      case 0: gl.activeTexture(i32[i++]); break;
+      case 1: gl.bindBuffer(i32[i++], obj()); break;
+      case 2: gl.bindFramebuffer(i32[i++], obj()); break;
+      case 3: gl.bindRenderbuffer(i32[i++], obj()); break;
+      case 4: gl.bindTexture(i32[i++], obj()); break;
+      case 5: gl.blendFunc(i32[i++], i32[i++]); break;
+      case 6: gl.bufferData(i32[i++], i32[i++], i32[i++]); break;
+      case 7: gl.bufferSubData(i32[i++], i32[i++], bytes()); break;
+      case 8: gl.clear(i32[i++]); break;
+      case 9: gl.clearColor(float32(), float32(), float32(), float32()); break;
+      case 10: gl.colorMask(!!i32[i++], !!i32[i++], !!i32[i++], !!i32[i++]); break;
+      case 11: gl.compressedTexImage2D(i32[i++], i32[i++], i32[i++], i32[i++], i32[i++], i32[i++], bytes()); break;
+      case 12: gl.copyTexSubImage2D(i32[i++], i32[i++], i32[i++], i32[i++], i32[i++], i32[i++], i32[i++], i32[i++]); break;
+      case 13: del(null, gl.deleteBuffer); break;
+      case 14: del(gl.isFramebuffer, gl.deleteFramebuffer); break;
+      case 15: del(gl.isProgram, gl.deleteProgram); break;
+      case 16: del(gl.isRenderbuffer, gl.deleteRenderbuffer); break;
+      case 17: del(gl.isTexture, gl.deleteTexture); break;
+      case 18: gl.disable(i32[i++]); break;
+      case 19: gl.disableVertexAttribArray(i32[i++]); break;
+      case 20: gl.drawElements(i32[i++], i32[i++], i32[i++], i32[i++]); break;
+      case 21: gl.enable(i32[i++]); break;
+      case 22: gl.enableVertexAttribArray(i32[i++]); break;
+      case 23: gl.framebufferRenderbuffer(i32[i++], i32[i++], i32[i++], obj()); break;
+      case 24: gl.framebufferTexture2D(i32[i++], i32[i++], i32[i++], obj(), i32[i++]); break;
+      case 25: gl.pixelStorei(i32[i++], i32[i++]); break;
+      case 26: gl.renderbufferStorage(i32[i++], i32[i++], i32[i++], i32[i++]); break;
+      case 27: gl.scissor(i32[i++], i32[i++], i32[i++], i32[i++]); break;
+      case 28: gl.stencilFunc(i32[i++], i32[i++], i32[i++]); break;
+      case 29: gl.stencilOp(i32[i++], i32[i++], i32[i++]); break;
+      case 30: gl.texImage2D(i32[i++], i32[i++], i32[i++], i32[i++], i32[i++], i32[i++], i32[i++], i32[i++], null); break;
+      case 31: gl.texParameteri(i32[i++], i32[i++], i32[i++]); break;
+      case 32: gl.texSubImage2D(i32[i++], i32[i++], i32[i++], i32[i++], i32[i++], i32[i++], i32[i++], i32[i++], bytes()); break;
+      case 33: gl.uniform1f(obj(), float32()); break;
+      case 34: gl.uniform1fv(obj(), floats()); break;
+      case 35: gl.uniform1i(obj(), i32[i++]); break;
+      case 36: gl.uniform2fv(obj(), floats()); break;
+      case 37: gl.uniform3fv(obj(), floats()); break;
+      case 38: gl.uniform4fv(obj(), floats()); break;
+      case 39: gl.uniformMatrix2fv(obj(), false, floats()); break;
+      case 40: gl.uniformMatrix3fv(obj(), false, floats()); break;
+      case 41: gl.uniformMatrix4fv(obj(), false, floats()); break;
+      case 42: gl.useProgram(obj()); break;
+      case 43: gl.vertexAttribPointer(i32[i++], i32[i++], i32[i++], !!i32[i++], i32[i++], i32[i++]); break;
+      case 44: gl.viewport(i32[i++], i32[i++], i32[i++], i32[i++]); break;
+      default: throw new Error('opengl: invalid GL command: ' + i32[i - 1]);
+      }
+    }
+    i32 = null;
+  },
+};
+`
+
+// commandBuffer batches GL commands and executes them at once.
+//
+// Calling a JS function via syscall/js is expensive and dominates the cost of draw-heavy games.
+// The commands are encoded into an Int32Array and a JS function executes them in one syscall/js call.
+// GL objects are referred to by integer IDs so that commands taking them can be encoded too.
+//
+// The commands are executed only at sync points, i.e., when Ebiten reads the GL state back, presents the screen,
+// or lets other code use the context by RunWithGraphics.
+// A GL function that depends on the GL state must be called after flush so that the order of the commands is kept.
+type commandBuffer struct {
+	gl       *gl
+	webGL2   bool
+	commands []int32
+
+	// executor is a JS function to execute the encoded commands.
+	// executor is undefined when the JS function cannot be created, e.g. on go2cpp.
+	executor js.Value
+
+	// jsObjects is the object table the executor refers to.
+	jsObjects js.Value
+
+	// objects is the object table. The index is an object ID.
+	objects []js.Value
+
+	// releasedIDs are the IDs of the deleted objects.
+	// The IDs cannot be reused until the commands referring to them are executed.
+	releasedIDs []int
+	freeIDs     []int
+
+	uint8Array js.Value
+	int32Array js.Value
+	byteLength int
+}
+
+func newCommandBuffer(gl *gl, v js.Value, webGL2 bool) *commandBuffer {
+	b := &commandBuffer{
+		gl:      gl,
+		webGL2:  webGL2,
+		objects: []js.Value{js.Null()},
+	}
+	if !js.Global().Get("go2cpp").Truthy() {
+		e := js.Global().Get("Function").New("gl", commandBufferExecutorSource).Invoke(v)
+		b.executor = e.Get("execute")
+		b.jsObjects = e.Get("objects")
+	}
+	return b
+}
+
+// newObject registers a GL object and returns its ID.
+// newObject returns 0 if v is null.
+func (b *commandBuffer) newObject(v js.Value) int {
+	if !v.Truthy() {
+		return 0
+	}
+	var id int
+	if n := len(b.freeIDs); n > 0 {
+		id = b.freeIDs[n-1]
+		b.freeIDs = b.freeIDs[:n-1]
+		b.objects[id] = v
+	} else {
+		id = len(b.objects)
+		b.objects = append(b.objects, v)
+	}
+	if b.executor.Truthy() {
+		b.jsObjects.SetIndex(id, v)
+	}
+	return id
+}
+
+// releaseObject marks the ID as reusable after the queued commands are executed.
+func (b *commandBuffer) releaseObject(id int) {
+	if id == 0 {
+		return
+	}
+	b.releasedIDs = append(b.releasedIDs, id)
+}
+
+func (b *commandBuffer) push(command glCommand, args ...int) {
+	b.commands = append(b.commands, int32(command))
+	for _, a := range args {
+		b.commands = append(b.commands, int32(a))
+	}
+}
+
+func (b *commandBuffer) pushFloat(v float32) {
+	b.commands = append(b.commands, int32(math.Float32bits(v)))
+}
+
+// pushBytes pushes the length of data in bytes and data padded to 4 bytes.
+func (b *commandBuffer) pushBytes(data []byte) {
+	b.commands = append(b.commands, int32(len(data)))
+	b.appendData(data)
+}
+
+// pushFloat32s pushes the length of data and data.
+func (b *commandBuffer) pushFloat32s(data []float32) {
+	b.commands = append(b.commands, int32(len(data)))
+	h := (*reflect.SliceHeader)(unsafe.Pointer(&data))
+	h.Len *= 4
+	h.Cap *= 4
+	bs := *(*[]byte)(unsafe.Pointer(h))
+	b.appendData(bs)
+	runtime.KeepAlive(data)
+}
+
+func (b *commandBuffer) appendData(data []byte) {
+	n := (len(data) + 3) / 4
+	l := len(b.commands)
+	if cap(b.commands) < l+n {
+		cs := make([]int32, l, 2*(l+n))
+		copy(cs, b.commands)
+		b.commands = cs
+	}
+	b.commands = b.commands[:l+n]
+	copy(int32sToBytes(b.commands[l:]), data)
+}
+
+func int32sToBytes(s []int32) []byte {
+	h := (*reflect.SliceHeader)(unsafe.Pointer(&s))
+	h.Len *= 4
+	h.Cap *= 4
+	bs := *(*[]byte)(unsafe.Pointer(h))
+	runtime.KeepAlive(s)
+	return bs
+}
+
+func int32sToFloat32s(s []int32) []float32 {
+	h := (*reflect.SliceHeader)(unsafe.Pointer(&s))
+	fs := *(*[]float32)(unsafe.Pointer(h))
+	runtime.KeepAlive(s)
+	return fs
+}
+
+func boolToInt(v bool) int {
+	if v {
+		return 1
+	}
+	return 0
+}
+
+func (b *commandBuffer) activeTexture(texture int) {
+	b.push(glCommandActiveTexture, texture)
+}
+
+func (b *commandBuffer) bindBuffer(target int, buffer int) {
+	b.push(glCommandBindBuffer, target, buffer)
+}
+
+func (b *commandBuffer) bindFramebuffer(target int, framebuffer int) {
+	b.push(glCommandBindFramebuffer, target, framebuffer)
+}
+
+func (b *commandBuffer) bindRenderbuffer(target int, renderbuffer int) {
+	b.push(glCommandBindRenderbuffer, target, renderbuffer)
+}
+
+func (b *commandBuffer) bindTexture(target int, texture int) {
+	b.push(glCommandBindTexture, target, texture)
+}
+
+func (b *commandBuffer) blendFunc(sfactor, dfactor int) {
+	b.push(glCommandBlendFunc, sfactor, dfactor)
+}
+
+func (b *commandBuffer) bufferData(target, size, usage int) {
+	b.push(glCommandBufferData, target, size, usage)
+}
+
+func (b *commandBuffer) bufferSubData(target, offset int, data []byte) {
+	b.push(glCommandBufferSubData, target, offset)
+	b.pushBytes(data)
+}
+
+func (b *commandBuffer) clear(mask int) {
+	b.push(glCommandClear, mask)
+}
+
+func (b *commandBuffer) clearColor(red, green, blue, alpha float32) {
+	b.push(glCommandClearColor)
+	b.pushFloat(red)
+	b.pushFloat(green)
+	b.pushFloat(blue)
+	b.pushFloat(alpha)
+}
+
+func (b *commandBuffer) colorMask(red, green, blue, alpha bool) {
+	b.push(glCommandColorMask, boolToInt(red), boolToInt(green), boolToInt(blue), boolToInt(alpha))
+}
+
+func (b *commandBuffer) compressedTexImage2D(target, level, internalFormat, width, height, border int, data []byte) {
+	b.push(glCommandCompressedTexImage2D, target, level, internalFormat, width, height, border)
+	b.pushBytes(data)
+}
+
+func (b *commandBuffer) copyTexSubImage2D(target, level, xoffset, yoffset, x, y, width, height int) {
+	b.push(glCommandCopyTexSubImage2D, target, level, xoffset, yoffset, x, y, width, height)
+}
+
+func (b *commandBuffer) deleteBuffer(buffer int) {
+	b.push(glCommandDeleteBuffer, buffer)
+	b.releaseObject(buffer)
+}
+
+func (b *commandBuffer) deleteFramebuffer(framebuffer int) {
+	b.push(glCommandDeleteFramebuffer, framebuffer)
+	b.releaseObject(framebuffer)
+}
+
+func (b *commandBuffer) deleteProgram(program int) {
+	b.push(glCommandDeleteProgram, program)
+	b.releaseObject(program)
+}
+
+func (b *commandBuffer) deleteRenderbuffer(renderbuffer int) {
+	b.push(glCommandDeleteRenderbuffer, renderbuffer)
+	b.releaseObject(renderbuffer)
+}
+
+func (b *commandBuffer) deleteTexture(texture int) {
+	b.push(glCommandDeleteTexture, texture)
+	b.releaseObject(texture)
+}
+
+func (b *commandBuffer) disable(cap int) {
+	b.push(glCommandDisable, cap)
+}
+
+func (b *commandBuffer) disableVertexAttribArray(index int) {
+	b.push(glCommandDisableVertexAttribArray, index)
+}
+
+func (b *commandBuffer) drawElements(mode, count, typ, offset int) {
+	b.push(glCommandDrawElements, mode, count, typ, offset)
+}
+
+func (b *commandBuffer) enable(cap int) {
+	b.push(glCommandEnable, cap)
+}
+
+func (b *commandBuffer) enableVertexAttribArray(index int) {
+	b.push(glCommandEnableVertexAttribArray, index)
+}
+
+func (b *commandBuffer) framebufferRenderbuffer(target, attachment, renderbufferTarget int, renderbuffer int) {
+	b.push(glCommandFramebufferRenderbuffer, target, attachment, renderbufferTarget, renderbuffer)
+}
+
+func (b *commandBuffer) framebufferTexture2D(target, attachment, textureTarget int, texture int, level int) {
+	b.push(glCommandFramebufferTexture2D, target, attachment, textureTarget, texture, level)
+}
+
+func (b *commandBuffer) pixelStorei(pname, param int) {
+	b.push(glCommandPixelStorei, pname, param)
+}
+
+func (b *commandBuffer) renderbufferStorage(target, internalFormat, width, height int) {
+	b.push(glCommandRenderbufferStorage, target, internalFormat, width, height)
+}
+
+func (b *commandBuffer) scissor(x, y, width, height int) {
+	b.push(glCommandScissor, x, y, width, height)
+}
+
+func (b *commandBuffer) stencilFunc(fn, ref, mask int) {
+	b.push(glCommandStencilFunc, fn, ref, mask)
+}
+
+func (b *commandBuffer) stencilOp(sfail, dpfail, dppass int) {
+	b.push(glCommandStencilOp, sfail, dpfail, dppass)
+}
+
+// texImage2D allocates a texture without specifying pixels.
+func (b *commandBuffer) texImage2D(target, level, internalFormat, width, height, border, format, typ int) {
+	b.push(glCommandTexImage2D, target, level, internalFormat, width, height, border, format, typ)
+}
+
+func (b *commandBuffer) texParameteri(target, pname, param int) {
+	b.push(glCommandTexParameteri, target, pname, param)
+}
+
+func (b *commandBuffer) texSubImage2D(target, level, xoffset, yoffset, width, height, format, typ int, pixels []byte) {
+	b.push(glCommandTexSubImage2D, target, level, xoffset, yoffset, width, height, format, typ)
+	b.pushBytes(pixels)
+}
+
+func (b *commandBuffer) uniform1f(location int, v float32) {
+	b.push(glCommandUniform1f, location)
+	b.pushFloat(v)
+}
+
+func (b *commandBuffer) uniform1i(location int, v int) {
+	b.push(glCommandUniform1i, location, v)
+}
+
+// uniformfv pushes a uniform command taking a float array, e.g. glCommandUniform2fv or glCommandUniformMatrix2fv.
+func (b *commandBuffer) uniformfv(command glCommand, location int, v []float32) {
+	b.push(command, location)
+	b.pushFloat32s(v)
+}
+
+func (b *commandBuffer) useProgram(program int) {
+	b.push(glCommandUseProgram, program)
+}
+
+func (b *commandBuffer) vertexAttribPointer(index, size, typ int, normalized bool, stride, offset int) {
+	b.push(glCommandVertexAttribPointer, index, size, typ, boolToInt(normalized), stride, offset)
+}
+
+func (b *commandBuffer) viewport(x, y, width, height int) {
+	b.push(glCommandViewport, x, y, width, height)
+}
+
+// flush executes all the batched commands.
+func (b *commandBuffer) flush() {
+	if len(b.commands) > 0 {
+		if b.executor.Truthy() {
+			b.flushByExecutor()
+		} else {
+			b.flushByGo()
+		}
+		b.commands = b.commands[:0]
+	}
+
+	for _, id := range b.releasedIDs {
+		b.objects[id] = js.Undefined()
+	}
+	b.freeIDs = append(b.freeIDs, b.releasedIDs...)
+	b.releasedIDs = b.releasedIDs[:0]
+}
+
+func (b *commandBuffer) flushByExecutor() {
+	byteLength := len(b.commands) * 4
+	if b.byteLength < byteLength {
+		if b.byteLength == 0 {
+			b.byteLength = 1024
+		}
+		for b.byteLength < byteLength {
+			b.byteLength *= 2
+		}
+		buf := js.Global().Get("ArrayBuffer").New(b.byteLength)
+		b.uint8Array = js.Global().Get("Uint8Array").New(buf)
+		b.int32Array = js.Global().Get("Int32Array").New(buf)
+	}
+
+	js.CopyBytesToJS(b.uint8Array, int32sToBytes(b.commands))
+
+	b.executor.Invoke(b.int32Array, len(b.commands))
+}
+
+func (b *commandBuffer) flushByGo() {
+	gl := b.gl
+	cs := b.commands
+	arg := func() int {
+		v := int(cs[0])
+		cs = cs[1:]
+		return v
+	}
+	obj := func() js.Value {
+		return b.objects[arg()]
+	}
+	float := func() float32 {
+		return math.Float32frombits(uint32(arg()))
+	}
+	bytes := func() (js.Value, int) {
+		n := arg()
+		w := (n + 3) / 4
+		arr := jsutil.TemporaryUint8ArrayFromUint8Slice(n, int32sToBytes(cs[:w])[:n])
+		cs = cs[w:]
+		return arr, n
+	}
+	floats := func() (js.Value, int) {
+		n := arg()
+		arr := jsutil.TemporaryFloat32Array(n, int32sToFloat32s(cs[:n]))
+		cs = cs[n:]
+		return arr, n
+	}
+	// On WebGL 2, the typed array is specified with its offset and length instead of subarray.
+	// The temporary array might be longer than the data.
+	bytesArgs := func() []interface{} {
+		arr, n := bytes()
+		if b.webGL2 {
+			return []interface{}{arr, 0, n}
+		}
+		return []interface{}{arr.Call("subarray", 0, n)}
+	}
+	floatsArgs := func() []interface{} {
+		arr, n := floats()
+		if b.webGL2 {
+			return []interface{}{arr, 0, n}
+		}
+		return []interface{}{arr.Call("subarray", 0, n)}
+	}
+	del := func(isObject, deleteObject js.Value) {
+		o := obj()
+		if isObject.Truthy() && !isObject.Invoke(o).Bool() {
+			return
+		}
+		deleteObject.Invoke(o)
+	}
+
+	for len(cs) > 0 {
+		switch glCommand(arg()) {
+		case glCommandActiveTexture:
+			gl.activeTexture.Invoke(arg())
+		case glCommandBindBuffer:
+			gl.bindBuffer.Invoke(arg(), obj())
+		case glCommandBindFramebuffer:
+			gl.bindFramebuffer.Invoke(arg(), obj())
+		case glCommandBindRenderbuffer:
+			gl.bindRenderbuffer.Invoke(arg(), obj())
+		case glCommandBindTexture:
+			gl.bindTexture.Invoke(arg(), obj())
+		case glCommandBlendFunc:
+			gl.blendFunc.Invoke(arg(), arg())
+		case glCommandBufferData:
+			gl.bufferData.Invoke(arg(), arg(), arg())
+		case glCommandBufferSubData:
+			gl.bufferSubData.Invoke(append([]interface{}{arg(), arg()}, bytesArgs()...)...)
+		case glCommandClear:
+			gl.clear.Invoke(arg())
+		case glCommandClearColor:
+			gl.clearColor.Invoke(float(), float(), float(), float())
+		case glCommandColorMask:
+			gl.colorMask.Invoke(arg() != 0, arg() != 0, arg() != 0, arg() != 0)
+		case glCommandCompressedTexImage2D:
+			args := []interface{}{arg(), arg(), arg(), arg(), arg(), arg()}
+			arr, n := bytes()
+			gl.compressedTexImage2D.Invoke(append(args, arr.Call("subarray", 0, n))...)
+		case glCommandCopyTexSubImage2D:
+			gl.copyTexSubImage2D.Invoke(arg(), arg(), arg(), arg(), arg(), arg(), arg(), arg())
+		case glCommandDeleteBuffer:
+			del(js.Undefined(), gl.deleteBuffer)
+		case glCommandDeleteFramebuffer:
+			del(gl.isFramebuffer, gl.deleteFramebuffer)
+		case glCommandDeleteProgram:
+			del(gl.isProgram, gl.deleteProgram)
+		case glCommandDeleteRenderbuffer:
+			del(gl.isRenderbuffer, gl.deleteRenderbuffer)
+		case glCommandDeleteTexture:
+			del(gl.isTexture, gl.deleteTexture)
+		case glCommandDisable:
+			gl.disable.Invoke(arg())
+		case glCommandDisableVertexAttribArray:
+			gl.disableVertexAttribArray.Invoke(arg())
+		case glCommandDrawElements:
+			gl.drawElements.Invoke(arg(), arg(), arg(), arg())
+		case glCommandEnable:
+			gl.enable.Invoke(arg())
+		case glCommandEnableVertexAttribArray:
+			gl.enableVertexAttribArray.Invoke(arg())
+		case glCommandFramebufferRenderbuffer:
+			gl.framebufferRenderbuffer.Invoke(arg(), arg(), arg(), obj())
+		case glCommandFramebufferTexture2D:
+			gl.framebufferTexture2D.Invoke(arg(), arg(), arg(), obj(), arg())
+		case glCommandPixelStorei:
+			gl.pixelStorei.Invoke(arg(), arg())
+		case glCommandRenderbufferStorage:
+			gl.renderbufferStorage.Invoke(arg(), arg(), arg(), arg())
+		case glCommandScissor:
+			gl.scissor.Invoke(arg(), arg(), arg(), arg())
+		case glCommandStencilFunc:
+			gl.stencilFunc.Invoke(arg(), arg(), arg())
+		case glCommandStencilOp:
+			gl.stencilOp.Invoke(arg(), arg(), arg())
+		case glCommandTexImage2D:
+			gl.texImage2D.Invoke(arg(), arg(), arg(), arg(), arg(), arg(), arg(), arg(), nil)
+		case glCommandTexParameteri:
+			gl.texParameteri.Invoke(arg(), arg(), arg())
+		case glCommandTexSubImage2D:
+			args := []interface{}{arg(), arg(), arg(), arg(), arg(), arg(), arg(), arg()}
+			arr, _ := bytes()
+			if b.webGL2 {
+				gl.texSubImage2D.Invoke(append(args, arr, 0)...)
+			} else {
+				gl.texSubImage2D.Invoke(append(args, arr)...)
+			}
+		case glCommandUniform1f:
+			gl.uniform1f.Invoke(obj(), float())
+		case glCommandUniform1fv:
+			gl.uniform1fv.Invoke(append([]interface{}{obj()}, floatsArgs()...)...)
+		case glCommandUniform1i:
+			gl.uniform1i.Invoke(obj(), arg())
+		case glCommandUniform2fv:
+			gl.uniform2fv.Invoke(append([]interface{}{obj()}, floatsArgs()...)...)
+		case glCommandUniform3fv:
+			gl.uniform3fv.Invoke(append([]interface{}{obj()}, floatsArgs()...)...)
+		case glCommandUniform4fv:
+			gl.uniform4fv.Invoke(append([]interface{}{obj()}, floatsArgs()...)...)
+		case glCommandUniformMatrix2fv:
+			gl.uniformMatrix2fv.Invoke(append([]interface{}{obj(), false}, floatsArgs()...)...)
+		case glCommandUniformMatrix3fv:
+			gl.uniformMatrix3fv.Invoke(append([]interface{}{obj(), false}, floatsArgs()...)...)
+		case glCommandUniformMatrix4fv:
+			gl.uniformMatrix4fv.Invoke(append([]interface{}{obj(), false}, floatsArgs()...)...)
+		case glCommandUseProgram:
+			gl.useProgram.Invoke(obj())
+		case glCommandVertexAttribPointer:
+			gl.vertexAttribPointer.Invoke(arg(), arg(), arg(), arg() != 0, arg(), arg())
+		case glCommandViewport:
+			gl.viewport.Invoke(arg(), arg(), arg(), arg())
+		default:
+			panic("opengl: invalid GL command")
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opengl
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
+)
+
+// Internal formats of compressed textures defined by the extensions.
+const (
+	compressedRGBAS3TCDXT1   = 0x83F1
+	compressedRGBAS3TCDXT3   = 0x83F2
+	compressedRGBAS3TCDXT5   = 0x83F3
+	compressedRedRGTC1       = 0x8DBB
+	compressedRGRGTC2        = 0x8DBD
+	compressedRGBBPTCUFloat  = 0x8E8F
+	compressedRGBABPTCUNorm  = 0x8E8C
+	compressedRGB8ETC2       = 0x9274
+	compressedRGBA8ETC2EAC   = 0x9278
+	compressedRGBAASTC4x4KHR = 0x93B0
+)
+
+func compressedTextureInternalFormat(format graphicsdriver.CompressedTextureFormat) uint32 {
+	switch format {
+	case graphicsdriver.CompressedTextureFormatBC1:
+		return compressedRGBAS3TCDXT1
+	case graphicsdriver.CompressedTextureFormatBC2:
+		return compressedRGBAS3TCDXT3
+	case graphicsdriver.CompressedTextureFormatBC3:
+		return compressedRGBAS3TCDXT5
+	case graphicsdriver.CompressedTextureFormatBC4:
+		return compressedRedRGTC1
+	case graphicsdriver.CompressedTextureFormatBC5:
+		return compressedRGRGTC2
+	case graphicsdriver.CompressedTextureFormatBC6H:
+		return compressedRGBBPTCUFloat
+	case graphicsdriver.CompressedTextureFormatBC7:
+		return compressedRGBABPTCUNorm
+	case graphicsdriver.CompressedTextureFormatETC2RGB8:
+		return compressedRGB8ETC2
+	case graphicsdriver.CompressedTextureFormatETC2RGBA8:
+		return compressedRGBA8ETC2EAC
+	case graphicsdriver.CompressedTextureFormatASTC4x4:
+		return compressedRGBAASTC4x4KHR
+	default:
+		panic(fmt.Sprintf("opengl: invalid compressed texture format: %d", format))
+	}
+}
+
+func (c *context) isCompressedTextureFormatSupported(format graphicsdriver.CompressedTextureFormat) bool {
+	c.compressedTextureFormatsOnce.Do(func() {
+		c.compressedTextureFormats = map[uint32]struct{}{}
+		for _, f := range c.compressedTextureFormatsImpl() {
+			c.compressedTextureFormats[f] = struct{}{}
+		}
+	})
+	_, ok := c.compressedTextureFormats[compressedTextureInternalFormat(format)]
+	return ok
+}
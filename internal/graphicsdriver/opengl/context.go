@@ -19,6 +19,7 @@ import (
 	"sync"
 
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
+	"github.com/hajimehoshi/ebiten/v2/internal/logger"
 )
 
 type operation int
@@ -58,6 +59,12 @@ type context struct {
 	highp              bool
 	highpOnce          sync.Once
 
+	compressedTextureFormats     map[uint32]struct{}
+	compressedTextureFormatsOnce sync.Once
+
+	driverInfo     graphicsdriver.DriverInfo
+	driverInfoOnce sync.Once
+
 	contextImpl
 }
 
@@ -117,12 +124,24 @@ func (c *context) getMaxTextureSize() int {
 	return c.maxTextureSize
 }
 
+func (c *context) getDriverInfo() graphicsdriver.DriverInfo {
+	c.driverInfoOnce.Do(func() {
+		c.driverInfo = c.driverInfoImpl()
+		c.driverInfo.GraphicsLibrary = c.graphicsLibrary()
+	})
+	return c.driverInfo
+}
+
 // highpPrecision represents an enough mantissa of float values in a shader.
 const highpPrecision = 23
 
 func (c *context) hasHighPrecisionFloat() bool {
 	c.highpOnce.Do(func() {
-		c.highp = c.getShaderPrecisionFormatPrecision() >= highpPrecision
+		p := c.getShaderPrecisionFormatPrecision()
+		c.highp = p >= highpPrecision
+		if !c.highp {
+			logger.Warn("high precision float is not available in fragment shaders; falling back to lower precision", "precision", p)
+		}
 	})
 	return c.highp
 }
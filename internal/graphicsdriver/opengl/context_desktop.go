@@ -20,9 +20,13 @@ package opengl
 import (
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
 
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver/opengl/gl"
+	"github.com/hajimehoshi/ebiten/v2/internal/logger"
 	"github.com/hajimehoshi/ebiten/v2/internal/shaderir"
 )
 
@@ -93,7 +97,16 @@ const (
 )
 
 type contextImpl struct {
-	init bool
+	init           bool
+	linearBlending bool
+
+	// linearBlendingUnsupported is 1 when linear blending is requested but GL_FRAMEBUFFER_SRGB is not available.
+	// linearBlendingUnsupported must be accessed atomically.
+	linearBlendingUnsupported int32
+}
+
+func (c *context) graphicsLibrary() graphicsdriver.GraphicsLibrary {
+	return graphicsdriver.GraphicsLibraryOpenGL
 }
 
 func (c *context) reset() error {
@@ -103,6 +116,12 @@ func (c *context) reset() error {
 			return fmt.Errorf("opengl: initializing error %v", err)
 		}
 		c.init = true
+
+		if c.linearBlending && !isFramebufferSRGBAvailable() {
+			logger.Warn("GL_FRAMEBUFFER_SRGB is not available; linear blending is disabled")
+			c.linearBlending = false
+			atomic.StoreInt32(&c.linearBlendingUnsupported, 1)
+		}
 	}
 
 	c.locationCache = newLocationCache()
@@ -113,6 +132,11 @@ func (c *context) reset() error {
 	c.lastCompositeMode = graphicsdriver.CompositeModeUnknown
 	gl.Enable(gl.BLEND)
 	gl.Enable(gl.SCISSOR_TEST)
+	gl.DepthFunc(gl.LEQUAL)
+	if c.linearBlending {
+		// Encode colors to sRGB when writing to sRGB textures and the sRGB-capable screen framebuffer.
+		gl.Enable(gl.FRAMEBUFFER_SRGB)
+	}
 
 	c.blendFunc(graphicsdriver.CompositeModeSourceOver)
 
@@ -122,6 +146,22 @@ func (c *context) reset() error {
 	return nil
 }
 
+// invalidateState forgets the cached state and restores the capabilities Ebiten assumes.
+func (c *context) invalidateState() {
+	gl.Enable(gl.BLEND)
+	gl.Enable(gl.SCISSOR_TEST)
+	gl.DepthFunc(gl.LEQUAL)
+	if c.linearBlending {
+		gl.Enable(gl.FRAMEBUFFER_SRGB)
+	}
+	c.lastTexture = invalidTexture
+	c.lastFramebuffer = invalidFramebuffer
+	c.lastRenderbuffer = 0
+	c.lastViewportWidth = 0
+	c.lastViewportHeight = 0
+	c.lastCompositeMode = graphicsdriver.CompositeModeUnknown
+}
+
 func (c *context) blendFunc(mode graphicsdriver.CompositeMode) {
 	if c.lastCompositeMode == mode {
 		return
@@ -154,10 +194,69 @@ func (c *context) newTexture(width, height int) (textureNative, error) {
 	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 4)
 	// If data is nil, this just allocates memory and the content is undefined.
 	// https://www.khronos.org/registry/OpenGL-Refpages/gl4/html/glTexImage2D.xhtml
-	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, int32(width), int32(height), 0, gl.RGBA, gl.UNSIGNED_BYTE, nil)
+	internalFormat := int32(gl.RGBA)
+	if c.linearBlending {
+		internalFormat = gl.SRGB8_ALPHA8
+	}
+	gl.TexImage2D(gl.TEXTURE_2D, 0, internalFormat, int32(width), int32(height), 0, gl.RGBA, gl.UNSIGNED_BYTE, nil)
 	return texture, nil
 }
 
+func (c *context) newCompressedTexture(width, height int, internalFormat uint32, data []byte) (textureNative, error) {
+	var t uint32
+	gl.GenTextures(1, &t)
+	if t <= 0 {
+		return 0, errors.New("opengl: creating texture failed")
+	}
+	texture := textureNative(t)
+	c.bindTexture(texture)
+
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+
+	gl.CompressedTexImage2D(gl.TEXTURE_2D, 0, internalFormat, int32(width), int32(height), 0, int32(len(data)), gl.Ptr(data))
+	return texture, nil
+}
+
+func (c *context) validateNativeTexture(handle uintptr) error {
+	if !gl.IsTexture(uint32(handle)) {
+		return fmt.Errorf("opengl: %d is not a texture", handle)
+	}
+	return nil
+}
+
+func (c *context) textureFromNative(handle uintptr) (textureNative, error) {
+	if err := c.validateNativeTexture(handle); err != nil {
+		return 0, err
+	}
+	t := textureNative(handle)
+	c.bindTexture(t)
+
+	// Ebiten's shaders filter the texels by themselves.
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+	return t, nil
+}
+
+func (c *context) compressedTextureFormatsImpl() []uint32 {
+	var n int32
+	gl.GetIntegerv(gl.NUM_COMPRESSED_TEXTURE_FORMATS, &n)
+	if n == 0 {
+		return nil
+	}
+	fs := make([]int32, n)
+	gl.GetIntegerv(gl.COMPRESSED_TEXTURE_FORMATS, &fs[0])
+	r := make([]uint32, n)
+	for i, f := range fs {
+		r[i] = uint32(f)
+	}
+	return r
+}
+
 func (c *context) bindFramebufferImpl(f framebufferNative) {
 	gl.BindFramebufferEXT(gl.FRAMEBUFFER, uint32(f))
 }
@@ -199,7 +298,7 @@ func (c *context) isTexture(t textureNative) bool {
 	panic("opengl: isTexture is not implemented")
 }
 
-func (c *context) newRenderbuffer(width, height int) (renderbufferNative, error) {
+func (c *context) newDepthStencilRenderbuffer(width, height int) (renderbufferNative, error) {
 	var r uint32
 	gl.GenRenderbuffersEXT(1, &r)
 	if r <= 0 {
@@ -253,9 +352,11 @@ func (c *context) newFramebuffer(texture textureNative) (framebufferNative, erro
 	return framebufferNative(f), nil
 }
 
-func (c *context) bindStencilBuffer(f framebufferNative, r renderbufferNative) error {
+func (c *context) bindDepthStencilBuffer(f framebufferNative, r renderbufferNative) error {
 	c.bindFramebuffer(f)
 
+	// GL_DEPTH_STENCIL_ATTACHMENT is not available with OpenGL 2.1. Attach the packed buffer to both.
+	gl.FramebufferRenderbufferEXT(gl.FRAMEBUFFER, gl.DEPTH_ATTACHMENT, gl.RENDERBUFFER, uint32(r))
 	gl.FramebufferRenderbufferEXT(gl.FRAMEBUFFER, gl.STENCIL_ATTACHMENT, gl.RENDERBUFFER, uint32(r))
 	if s := gl.CheckFramebufferStatusEXT(gl.FRAMEBUFFER); s != gl.FRAMEBUFFER_COMPLETE {
 		return errors.New(fmt.Sprintf("opengl: glFramebufferRenderbuffer failed: %d", s))
@@ -480,6 +581,39 @@ func (c *context) maxTextureSizeImpl() int {
 	return int(s)
 }
 
+func (c *context) driverInfoImpl() graphicsdriver.DriverInfo {
+	return graphicsdriver.DriverInfo{
+		Adapter:       getString(gl.VENDOR) + " " + getString(gl.RENDERER),
+		DriverVersion: getString(gl.VERSION),
+		FeatureLevel:  "GLSL " + getString(gl.SHADING_LANGUAGE_VERSION),
+	}
+}
+
+// isFramebufferSRGBAvailable reports whether GL_FRAMEBUFFER_SRGB can be enabled.
+// GL_FRAMEBUFFER_SRGB is a part of OpenGL 3.0 and is also available with the ARB or EXT extension.
+func isFramebufferSRGBAvailable() bool {
+	v := getString(gl.VERSION)
+	if i := strings.IndexByte(v, '.'); i > 0 {
+		if major, err := strconv.Atoi(v[:i]); err == nil && major >= 3 {
+			return true
+		}
+	}
+	for _, ext := range strings.Fields(getString(gl.EXTENSIONS)) {
+		if ext == "GL_ARB_framebuffer_sRGB" || ext == "GL_EXT_framebuffer_sRGB" {
+			return true
+		}
+	}
+	return false
+}
+
+func getString(name uint32) string {
+	s := gl.GetString(name)
+	if s == nil {
+		return ""
+	}
+	return gl.GoStr(s)
+}
+
 func (c *context) getShaderPrecisionFormatPrecision() int {
 	// glGetShaderPrecisionFormat is not defined at OpenGL 2.0. Assume that desktop environments always have
 	// enough highp precision.
@@ -501,6 +635,12 @@ func (c *context) texSubImage2D(t textureNative, args []*graphicsdriver.ReplaceP
 	}
 }
 
+func (c *context) copyTexSubImage2D(dst textureNative, src *framebuffer, dstX, dstY, srcX, srcY, width, height int) {
+	c.bindFramebuffer(src.native)
+	c.bindTexture(dst)
+	gl.CopyTexSubImage2D(gl.TEXTURE_2D, 0, int32(dstX), int32(dstY), int32(srcX), int32(srcY), int32(width), int32(height))
+}
+
 func (c *context) enableStencilTest() {
 	gl.Enable(gl.STENCIL_TEST)
 }
@@ -509,6 +649,23 @@ func (c *context) disableStencilTest() {
 	gl.Disable(gl.STENCIL_TEST)
 }
 
+func (c *context) enableDepthTest() {
+	gl.Enable(gl.DEPTH_TEST)
+}
+
+func (c *context) disableDepthTest() {
+	gl.Disable(gl.DEPTH_TEST)
+}
+
+func (c *context) clearDepth() {
+	gl.Clear(gl.DEPTH_BUFFER_BIT)
+}
+
+func (c *context) clearColor(red, green, blue, alpha float32) {
+	gl.ClearColor(red, green, blue, alpha)
+	gl.Clear(gl.COLOR_BUFFER_BIT)
+}
+
 func (c *context) beginStencilWithEvenOddRule() {
 	gl.Clear(gl.STENCIL_BUFFER_BIT)
 	gl.StencilFunc(gl.ALWAYS, 0x00, 0xff)
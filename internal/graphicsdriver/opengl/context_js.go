@@ -17,40 +17,54 @@ package opengl
 import (
 	"errors"
 	"fmt"
+	"reflect"
+	"runtime"
+	"sync/atomic"
 	"syscall/js"
+	"unsafe"
 
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver/opengl/gles"
 	"github.com/hajimehoshi/ebiten/v2/internal/jsutil"
+	"github.com/hajimehoshi/ebiten/v2/internal/logger"
 	"github.com/hajimehoshi/ebiten/v2/internal/shaderir"
 )
 
+// glObject is a GL object registered to the command buffer.
+// The zero value represents null.
+type glObject struct {
+	value js.Value
+
+	// id is the ID in the command buffer's object table.
+	id int
+}
+
 type (
-	textureNative      js.Value
-	renderbufferNative js.Value
-	framebufferNative  js.Value
+	textureNative      glObject
+	renderbufferNative glObject
+	framebufferNative  glObject
 	shader             js.Value
-	buffer             js.Value
-	uniformLocation    js.Value
+	buffer             glObject
+	uniformLocation    glObject
 
 	attribLocation int
 	programID      int
 	program        struct {
-		value js.Value
-		id    programID
+		object glObject
+		id     programID
 	}
 )
 
 func (t textureNative) equal(rhs textureNative) bool {
-	return js.Value(t).Equal(js.Value(rhs))
+	return t.id == rhs.id
 }
 
 func (r renderbufferNative) equal(rhs renderbufferNative) bool {
-	return js.Value(r).Equal(js.Value(rhs))
+	return r.id == rhs.id
 }
 
 func (f framebufferNative) equal(rhs framebufferNative) bool {
-	return js.Value(f).Equal(js.Value(rhs))
+	return f.id == rhs.id
 }
 
 func (s shader) equal(rhs shader) bool {
@@ -58,20 +72,20 @@ func (s shader) equal(rhs shader) bool {
 }
 
 func (b buffer) equal(rhs buffer) bool {
-	return js.Value(b).Equal(js.Value(rhs))
+	return b.id == rhs.id
 }
 
 func (u uniformLocation) equal(rhs uniformLocation) bool {
-	return js.Value(u).Equal(js.Value(rhs))
+	return u.id == rhs.id
 }
 
 func (p program) equal(rhs program) bool {
-	return p.value.Equal(rhs.value) && p.id == rhs.id
+	return p.object.id == rhs.object.id && p.id == rhs.id
 }
 
-var InvalidTexture = textureNative(js.Null())
+var InvalidTexture = textureNative{}
 
-var invalidUniform = uniformLocation(js.Null())
+var invalidUniform = uniformLocation{}
 
 func getProgramID(p program) programID {
 	return p.id
@@ -110,22 +124,48 @@ func uint8ArrayToSlice(value js.Value, length int) []byte {
 
 type contextImpl struct {
 	gl            *gl
+	commands      *commandBuffer
 	lastProgramID programID
 	webGLVersion  webGLVersion
+
+	// webGLVersionForQuery is a copy of webGLVersion to be read from any goroutine.
+	// webGLVersionForQuery must be accessed atomically.
+	webGLVersionForQuery int32
 }
 
 func (c *context) usesWebGL2() bool {
 	return c.webGLVersion == webGLVersion2
 }
 
+func (c *context) graphicsLibrary() graphicsdriver.GraphicsLibrary {
+	// graphicsLibrary can be called from any goroutine while initGL is running on the rendering thread.
+	switch webGLVersion(atomic.LoadInt32(&c.webGLVersionForQuery)) {
+	case webGLVersion1:
+		return graphicsdriver.GraphicsLibraryWebGL1
+	case webGLVersion2:
+		return graphicsdriver.GraphicsLibraryWebGL2
+	}
+	return graphicsdriver.GraphicsLibraryUnknown
+}
+
 func (c *context) initGL() error {
 	c.webGLVersion = webGLVersionUnknown
+	defer func() {
+		atomic.StoreInt32(&c.webGLVersionForQuery, int32(c.webGLVersion))
+	}()
 
 	var gl js.Value
 
-	// TODO: Define id?
+	var canvas js.Value
 	if doc := js.Global().Get("document"); doc.Truthy() {
-		canvas := doc.Call("querySelector", "canvas")
+		// TODO: Define id?
+		canvas = doc.Call("querySelector", "canvas")
+	} else {
+		// In a Web Worker, an OffscreenCanvas transferred from the main thread is used.
+		canvas = jsutil.OffscreenCanvas()
+	}
+
+	if canvas.Truthy() {
 		attr := js.Global().Get("Object").New()
 		attr.Set("alpha", true)
 		attr.Set("premultipliedAlpha", true)
@@ -140,6 +180,9 @@ func (c *context) initGL() error {
 
 		// Even though WebGL2RenderingContext exists, getting a webgl2 context might fail (#1738).
 		if !gl.Truthy() {
+			if webGL2MightBeAvailable {
+				logger.Warn("getting a WebGL 2 context failed; falling back to WebGL 1")
+			}
 			gl = canvas.Call("getContext", "webgl", attr)
 			if !gl.Truthy() {
 				gl = canvas.Call("getContext", "experimental-webgl", attr)
@@ -158,13 +201,14 @@ func (c *context) initGL() error {
 	}
 
 	c.gl = c.newGL(gl)
+	c.commands = newCommandBuffer(c.gl, gl, c.webGLVersion == webGLVersion2)
 	return nil
 }
 
 func (c *context) reset() error {
 	c.locationCache = newLocationCache()
-	c.lastTexture = textureNative(js.Null())
-	c.lastFramebuffer = framebufferNative(js.Null())
+	c.lastTexture = textureNative{}
+	c.lastFramebuffer = framebufferNative{}
 	c.lastViewportWidth = 0
 	c.lastViewportHeight = 0
 	c.lastCompositeMode = graphicsdriver.CompositeModeUnknown
@@ -179,9 +223,10 @@ func (c *context) reset() error {
 	gl := c.gl
 	gl.enable.Invoke(gles.BLEND)
 	gl.enable.Invoke(gles.SCISSOR_TEST)
+	gl.depthFunc.Invoke(gles.LEQUAL)
 	c.blendFunc(graphicsdriver.CompositeModeSourceOver)
 	f := gl.getParameter.Invoke(gles.FRAMEBUFFER_BINDING)
-	c.screenFramebuffer = framebufferNative(f)
+	c.screenFramebuffer = framebufferNative(c.newObject(f))
 
 	if !c.usesWebGL2() {
 		gl.getExtension.Invoke("OES_standard_derivatives")
@@ -189,6 +234,29 @@ func (c *context) reset() error {
 	return nil
 }
 
+// invalidateState forgets the cached state and restores the capabilities Ebiten assumes.
+func (c *context) invalidateState() {
+	// Flush the queued commands so that they are executed before the state is reset.
+	c.commands.flush()
+	c.gl.enable.Invoke(gles.BLEND)
+	c.gl.enable.Invoke(gles.SCISSOR_TEST)
+	c.gl.depthFunc.Invoke(gles.LEQUAL)
+	c.lastTexture = textureNative{}
+	c.lastFramebuffer = framebufferNative{}
+	c.lastRenderbuffer = renderbufferNative{}
+	c.lastViewportWidth = 0
+	c.lastViewportHeight = 0
+	c.lastCompositeMode = graphicsdriver.CompositeModeUnknown
+}
+
+// newObject registers a GL object to the command buffer.
+func (c *context) newObject(v js.Value) glObject {
+	return glObject{
+		value: v,
+		id:    c.commands.newObject(v),
+	}
+}
+
 func (c *context) blendFunc(mode graphicsdriver.CompositeMode) {
 	if c.lastCompositeMode == mode {
 		return
@@ -196,29 +264,28 @@ func (c *context) blendFunc(mode graphicsdriver.CompositeMode) {
 	c.lastCompositeMode = mode
 	s, d := mode.Operations()
 	s2, d2 := convertOperation(s), convertOperation(d)
-	gl := c.gl
-	gl.blendFunc.Invoke(int(s2), int(d2))
+	c.commands.blendFunc(int(s2), int(d2))
 }
 
 func (c *context) scissor(x, y, width, height int) {
-	gl := c.gl
-	gl.scissor.Invoke(x, y, width, height)
+	c.commands.scissor(x, y, width, height)
 }
 
 func (c *context) newTexture(width, height int) (textureNative, error) {
 	gl := c.gl
 	t := gl.createTexture.Invoke()
 	if !t.Truthy() {
-		return textureNative(js.Null()), errors.New("opengl: createTexture failed")
+		return textureNative{}, errors.New("opengl: createTexture failed")
 	}
-	c.bindTexture(textureNative(t))
+	texture := textureNative(c.newObject(t))
+	c.bindTexture(texture)
 
-	gl.texParameteri.Invoke(gles.TEXTURE_2D, gles.TEXTURE_MAG_FILTER, gles.NEAREST)
-	gl.texParameteri.Invoke(gles.TEXTURE_2D, gles.TEXTURE_MIN_FILTER, gles.NEAREST)
-	gl.texParameteri.Invoke(gles.TEXTURE_2D, gles.TEXTURE_WRAP_S, gles.CLAMP_TO_EDGE)
-	gl.texParameteri.Invoke(gles.TEXTURE_2D, gles.TEXTURE_WRAP_T, gles.CLAMP_TO_EDGE)
+	c.commands.texParameteri(gles.TEXTURE_2D, gles.TEXTURE_MAG_FILTER, gles.NEAREST)
+	c.commands.texParameteri(gles.TEXTURE_2D, gles.TEXTURE_MIN_FILTER, gles.NEAREST)
+	c.commands.texParameteri(gles.TEXTURE_2D, gles.TEXTURE_WRAP_S, gles.CLAMP_TO_EDGE)
+	c.commands.texParameteri(gles.TEXTURE_2D, gles.TEXTURE_WRAP_T, gles.CLAMP_TO_EDGE)
 
-	gl.pixelStorei.Invoke(gles.UNPACK_ALIGNMENT, 4)
+	c.commands.pixelStorei(gles.UNPACK_ALIGNMENT, 4)
 	// Firefox warns the usage of textures without specifying pixels (#629)
 	//
 	//     Error: WebGL warning: drawElements: This operation requires zeroing texture data. This is slow.
@@ -226,20 +293,72 @@ func (c *context) newTexture(width, height int) (textureNative, error) {
 	// In Ebiten, textures are filled with pixels laster by the filter that ignores destination, so it is fine
 	// to leave textures as uninitialized here. Rather, extra memory allocating for initialization should be
 	// avoided.
-	gl.texImage2D.Invoke(gles.TEXTURE_2D, 0, gles.RGBA, width, height, 0, gles.RGBA, gles.UNSIGNED_BYTE, nil)
+	c.commands.texImage2D(gles.TEXTURE_2D, 0, gles.RGBA, width, height, 0, gles.RGBA, gles.UNSIGNED_BYTE)
 
-	return textureNative(t), nil
+	return texture, nil
 }
 
-func (c *context) bindFramebufferImpl(f framebufferNative) {
+func (c *context) newCompressedTexture(width, height int, internalFormat uint32, data []byte) (textureNative, error) {
 	gl := c.gl
-	gl.bindFramebuffer.Invoke(gles.FRAMEBUFFER, js.Value(f))
+	t := gl.createTexture.Invoke()
+	if !t.Truthy() {
+		return textureNative{}, errors.New("opengl: createTexture failed")
+	}
+	texture := textureNative(c.newObject(t))
+	c.bindTexture(texture)
+
+	c.commands.texParameteri(gles.TEXTURE_2D, gles.TEXTURE_MAG_FILTER, gles.NEAREST)
+	c.commands.texParameteri(gles.TEXTURE_2D, gles.TEXTURE_MIN_FILTER, gles.NEAREST)
+	c.commands.texParameteri(gles.TEXTURE_2D, gles.TEXTURE_WRAP_S, gles.CLAMP_TO_EDGE)
+	c.commands.texParameteri(gles.TEXTURE_2D, gles.TEXTURE_WRAP_T, gles.CLAMP_TO_EDGE)
+
+	c.commands.compressedTexImage2D(gles.TEXTURE_2D, 0, int(internalFormat), width, height, 0, data)
+
+	return texture, nil
+}
+
+func (c *context) validateNativeTexture(handle uintptr) error {
+	// A WebGL texture is a JavaScript object and cannot be represented as an integer handle.
+	return errors.New("opengl: native textures are not supported on browsers")
+}
+
+func (c *context) textureFromNative(handle uintptr) (textureNative, error) {
+	return textureNative{}, c.validateNativeTexture(handle)
+}
+
+func (c *context) compressedTextureFormatsImpl() []uint32 {
+	gl := c.gl
+
+	// The extensions must be enabled by getExtension to use the formats.
+	var r []uint32
+	if gl.getExtension.Invoke("WEBGL_compressed_texture_s3tc").Truthy() {
+		r = append(r, compressedRGBAS3TCDXT1, compressedRGBAS3TCDXT3, compressedRGBAS3TCDXT5)
+	}
+	if gl.getExtension.Invoke("EXT_texture_compression_rgtc").Truthy() {
+		r = append(r, compressedRedRGTC1, compressedRGRGTC2)
+	}
+	if gl.getExtension.Invoke("EXT_texture_compression_bptc").Truthy() {
+		r = append(r, compressedRGBBPTCUFloat, compressedRGBABPTCUNorm)
+	}
+	if gl.getExtension.Invoke("WEBGL_compressed_texture_etc").Truthy() {
+		r = append(r, compressedRGB8ETC2, compressedRGBA8ETC2EAC)
+	}
+	if gl.getExtension.Invoke("WEBGL_compressed_texture_astc").Truthy() {
+		r = append(r, compressedRGBAASTC4x4KHR)
+	}
+	return r
+}
+
+func (c *context) bindFramebufferImpl(f framebufferNative) {
+	c.commands.bindFramebuffer(gles.FRAMEBUFFER, f.id)
 }
 
 func (c *context) framebufferPixels(buf []byte, f *framebuffer, width, height int) {
 	gl := c.gl
 
 	c.bindFramebuffer(f.native)
+	// Reading pixels is a sync point.
+	c.commands.flush()
 
 	l := 4 * width * height
 	p := jsutil.TemporaryUint8ArrayFromUint8Slice(l, nil)
@@ -251,31 +370,28 @@ func (c *context) framebufferPixelsToBuffer(f *framebuffer, buffer buffer, width
 	gl := c.gl
 
 	c.bindFramebuffer(f.native)
-	gl.bindBuffer.Invoke(gles.PIXEL_PACK_BUFFER, js.Value(buffer))
+	// Reading pixels is a sync point.
+	c.commands.flush()
+	gl.bindBuffer.Invoke(gles.PIXEL_PACK_BUFFER, buffer.value)
 	// void gl.readPixels(x, y, width, height, format, type, GLintptr offset);
 	gl.readPixels.Invoke(0, 0, width, height, gles.RGBA, gles.UNSIGNED_BYTE, 0)
 	gl.bindBuffer.Invoke(gles.PIXEL_PACK_BUFFER, nil)
 }
 
 func (c *context) activeTexture(idx int) {
-	gl := c.gl
-	gl.activeTexture.Invoke(gles.TEXTURE0 + idx)
+	c.commands.activeTexture(gles.TEXTURE0 + idx)
 }
 
 func (c *context) bindTextureImpl(t textureNative) {
-	gl := c.gl
-	gl.bindTexture.Invoke(gles.TEXTURE_2D, js.Value(t))
+	c.commands.bindTexture(gles.TEXTURE_2D, t.id)
 }
 
 func (c *context) deleteTexture(t textureNative) {
-	gl := c.gl
-	if !gl.isTexture.Invoke(js.Value(t)).Bool() {
-		return
-	}
 	if c.lastTexture.equal(t) {
-		c.lastTexture = textureNative(js.Null())
+		c.lastTexture = textureNative{}
 	}
-	gl.deleteTexture.Invoke(js.Value(t))
+	// The command buffer deletes the texture only when isTexture is true.
+	c.commands.deleteTexture(t.id)
 }
 
 func (c *context) isTexture(t textureNative) bool {
@@ -283,55 +399,56 @@ func (c *context) isTexture(t textureNative) bool {
 	panic("opengl: isTexture is not implemented")
 }
 
-func (c *context) newRenderbuffer(width, height int) (renderbufferNative, error) {
+func (c *context) newDepthStencilRenderbuffer(width, height int) (renderbufferNative, error) {
 	gl := c.gl
 	r := gl.createRenderbuffer.Invoke()
 	if !r.Truthy() {
-		return renderbufferNative(js.Null()), errors.New("opengl: createRenderbuffer failed")
+		return renderbufferNative{}, errors.New("opengl: createRenderbuffer failed")
 	}
 
-	c.bindRenderbuffer(renderbufferNative(r))
-	// TODO: Is STENCIL_INDEX8 portable?
-	// https://stackoverflow.com/questions/11084961/binding-a-stencil-render-buffer-to-a-frame-buffer-in-opengl
-	gl.renderbufferStorage.Invoke(gles.RENDERBUFFER, gles.STENCIL_INDEX8, width, height)
+	renderbuffer := renderbufferNative(c.newObject(r))
+	c.bindRenderbuffer(renderbuffer)
+	// DEPTH_STENCIL is the only packed depth-stencil format in WebGL 1, and WebGL 2 accepts it as DEPTH24_STENCIL8.
+	// https://www.khronos.org/registry/webgl/specs/latest/1.0/#6.6
+	c.commands.renderbufferStorage(gles.RENDERBUFFER, gles.DEPTH_STENCIL, width, height)
 
-	return renderbufferNative(r), nil
+	return renderbuffer, nil
 }
 
 func (c *context) bindRenderbufferImpl(r renderbufferNative) {
-	gl := c.gl
-	gl.bindRenderbuffer.Invoke(gles.RENDERBUFFER, js.Value(r))
+	c.commands.bindRenderbuffer(gles.RENDERBUFFER, r.id)
 }
 
 func (c *context) deleteRenderbuffer(r renderbufferNative) {
-	gl := c.gl
-	if !gl.isRenderbuffer.Invoke(js.Value(r)).Bool() {
-		return
-	}
 	if c.lastRenderbuffer.equal(r) {
-		c.lastRenderbuffer = renderbufferNative(js.Null())
+		c.lastRenderbuffer = renderbufferNative{}
 	}
-	gl.deleteRenderbuffer.Invoke(js.Value(r))
+	c.commands.deleteRenderbuffer(r.id)
 }
 
 func (c *context) newFramebuffer(t textureNative) (framebufferNative, error) {
 	gl := c.gl
-	f := gl.createFramebuffer.Invoke()
-	c.bindFramebuffer(framebufferNative(f))
+	f := framebufferNative(c.newObject(gl.createFramebuffer.Invoke()))
+	c.bindFramebuffer(f)
 
-	gl.framebufferTexture2D.Invoke(gles.FRAMEBUFFER, gles.COLOR_ATTACHMENT0, gles.TEXTURE_2D, js.Value(t), 0)
+	c.commands.framebufferTexture2D(gles.FRAMEBUFFER, gles.COLOR_ATTACHMENT0, gles.TEXTURE_2D, t.id, 0)
+	// Checking the status is a sync point.
+	c.commands.flush()
 	if s := gl.checkFramebufferStatus.Invoke(gles.FRAMEBUFFER); s.Int() != gles.FRAMEBUFFER_COMPLETE {
-		return framebufferNative(js.Null()), errors.New(fmt.Sprintf("opengl: creating framebuffer failed: %d", s.Int()))
+		return framebufferNative{}, errors.New(fmt.Sprintf("opengl: creating framebuffer failed: %d", s.Int()))
 	}
 
-	return framebufferNative(f), nil
+	return f, nil
 }
 
-func (c *context) bindStencilBuffer(f framebufferNative, r renderbufferNative) error {
+func (c *context) bindDepthStencilBuffer(f framebufferNative, r renderbufferNative) error {
 	gl := c.gl
 	c.bindFramebuffer(f)
 
-	gl.framebufferRenderbuffer.Invoke(gles.FRAMEBUFFER, gles.STENCIL_ATTACHMENT, gles.RENDERBUFFER, js.Value(r))
+	// WebGL doesn't allow to attach the same renderbuffer to both DEPTH_ATTACHMENT and STENCIL_ATTACHMENT.
+	c.commands.framebufferRenderbuffer(gles.FRAMEBUFFER, gles.DEPTH_STENCIL_ATTACHMENT, gles.RENDERBUFFER, r.id)
+	// Checking the status is a sync point.
+	c.commands.flush()
 	if s := gl.checkFramebufferStatus.Invoke(gles.FRAMEBUFFER); s.Int() != gles.FRAMEBUFFER_COMPLETE {
 		return errors.New(fmt.Sprintf("opengl: framebufferRenderbuffer failed: %d", s.Int()))
 	}
@@ -339,24 +456,19 @@ func (c *context) bindStencilBuffer(f framebufferNative, r renderbufferNative) e
 }
 
 func (c *context) setViewportImpl(width, height int) {
-	gl := c.gl
-	gl.viewport.Invoke(0, 0, width, height)
+	c.commands.viewport(0, 0, width, height)
 }
 
 func (c *context) deleteFramebuffer(f framebufferNative) {
-	gl := c.gl
-	if !gl.isFramebuffer.Invoke(js.Value(f)).Bool() {
-		return
-	}
 	// If a framebuffer to be deleted is bound, a newly bound framebuffer
 	// will be a default framebuffer.
 	// https://www.khronos.org/opengles/sdk/docs/man/xhtml/glDeleteFramebuffers.xml
 	if c.lastFramebuffer.equal(f) {
-		c.lastFramebuffer = framebufferNative(js.Null())
+		c.lastFramebuffer = framebufferNative{}
 		c.lastViewportWidth = 0
 		c.lastViewportHeight = 0
 	}
-	gl.deleteFramebuffer.Invoke(js.Value(f))
+	c.commands.deleteFramebuffer(f.id)
 }
 
 func (c *context) newVertexShader(source string) (shader, error) {
@@ -413,53 +525,49 @@ func (c *context) newProgram(shaders []shader, attributes []string) (program, er
 	id := c.lastProgramID
 	c.lastProgramID++
 	return program{
-		value: v,
-		id:    id,
+		object: c.newObject(v),
+		id:     id,
 	}, nil
 }
 
 func (c *context) useProgram(p program) {
-	gl := c.gl
-	gl.useProgram.Invoke(p.value)
+	c.commands.useProgram(p.object.id)
 }
 
 func (c *context) deleteProgram(p program) {
+	// Uniform locations are not GL objects to delete, but their IDs are no longer used.
+	for _, l := range c.locationCache.uniformLocationCache[p.id] {
+		c.commands.releaseObject(l.id)
+	}
 	c.locationCache.deleteProgram(p)
 
-	gl := c.gl
-	if !gl.isProgram.Invoke(p.value).Bool() {
-		return
-	}
-	gl.deleteProgram.Invoke(p.value)
+	c.commands.deleteProgram(p.object.id)
 }
 
 func (c *context) getUniformLocationImpl(p program, location string) uniformLocation {
 	gl := c.gl
-	return uniformLocation(gl.getUniformLocation.Invoke(p.value, location))
+	return uniformLocation(c.newObject(gl.getUniformLocation.Invoke(p.object.value, location)))
 }
 
 func (c *context) uniformInt(p program, location string, v int) bool {
-	gl := c.gl
 	l := c.locationCache.GetUniformLocation(c, p, location)
 	if l.equal(invalidUniform) {
 		return false
 	}
-	gl.uniform1i.Invoke(js.Value(l), v)
+	c.commands.uniform1i(l.id, v)
 	return true
 }
 
 func (c *context) uniformFloat(p program, location string, v float32) bool {
-	gl := c.gl
 	l := c.locationCache.GetUniformLocation(c, p, location)
 	if l.equal(invalidUniform) {
 		return false
 	}
-	gl.uniform1f.Invoke(js.Value(l), v)
+	c.commands.uniform1f(l.id, v)
 	return true
 }
 
 func (c *context) uniformFloats(p program, location string, v []float32, typ shaderir.Type) bool {
-	gl := c.gl
 	l := c.locationCache.GetUniformLocation(c, p, location)
 	if l.equal(invalidUniform) {
 		return false
@@ -470,129 +578,90 @@ func (c *context) uniformFloats(p program, location string, v []float32, typ sha
 		base = typ.Sub[0].Main
 	}
 
-	arr := jsutil.TemporaryFloat32Array(len(v), v)
-
+	var command glCommand
 	switch base {
 	case shaderir.Float:
-		if c.usesWebGL2() {
-			gl.uniform1fv.Invoke(js.Value(l), arr, 0, len(v))
-		} else {
-			gl.uniform1fv.Invoke(js.Value(l), arr.Call("subarray", 0, len(v)))
-		}
+		command = glCommandUniform1fv
 	case shaderir.Vec2:
-		if c.usesWebGL2() {
-			gl.uniform2fv.Invoke(js.Value(l), arr, 0, len(v))
-		} else {
-			gl.uniform2fv.Invoke(js.Value(l), arr.Call("subarray", 0, len(v)))
-		}
+		command = glCommandUniform2fv
 	case shaderir.Vec3:
-		if c.usesWebGL2() {
-			gl.uniform3fv.Invoke(js.Value(l), arr, 0, len(v))
-		} else {
-			gl.uniform3fv.Invoke(js.Value(l), arr.Call("subarray", 0, len(v)))
-		}
+		command = glCommandUniform3fv
 	case shaderir.Vec4:
-		if c.usesWebGL2() {
-			gl.uniform4fv.Invoke(js.Value(l), arr, 0, len(v))
-		} else {
-			gl.uniform4fv.Invoke(js.Value(l), arr.Call("subarray", 0, len(v)))
-		}
+		command = glCommandUniform4fv
 	case shaderir.Mat2:
-		if c.usesWebGL2() {
-			gl.uniformMatrix2fv.Invoke(js.Value(l), false, arr, 0, len(v))
-		} else {
-			gl.uniformMatrix2fv.Invoke(js.Value(l), false, arr.Call("subarray", 0, len(v)))
-		}
+		command = glCommandUniformMatrix2fv
 	case shaderir.Mat3:
-		if c.usesWebGL2() {
-			gl.uniformMatrix3fv.Invoke(js.Value(l), false, arr, 0, len(v))
-		} else {
-			gl.uniformMatrix3fv.Invoke(js.Value(l), false, arr.Call("subarray", 0, len(v)))
-		}
+		command = glCommandUniformMatrix3fv
 	case shaderir.Mat4:
-		if c.usesWebGL2() {
-			gl.uniformMatrix4fv.Invoke(js.Value(l), false, arr, 0, len(v))
-		} else {
-			gl.uniformMatrix4fv.Invoke(js.Value(l), false, arr.Call("subarray", 0, len(v)))
-		}
+		command = glCommandUniformMatrix4fv
 	default:
 		panic(fmt.Sprintf("opengl: unexpected type: %s", typ.String()))
 	}
+	c.commands.uniformfv(command, l.id, v)
 
 	return true
 }
 
 func (c *context) vertexAttribPointer(index int, size int, stride int, offset int) {
-	gl := c.gl
-	gl.vertexAttribPointer.Invoke(index, size, gles.FLOAT, false, stride, offset)
+	c.commands.vertexAttribPointer(index, size, gles.FLOAT, false, stride, offset)
 }
 
 func (c *context) enableVertexAttribArray(index int) {
-	gl := c.gl
-	gl.enableVertexAttribArray.Invoke(index)
+	c.commands.enableVertexAttribArray(index)
 }
 
 func (c *context) disableVertexAttribArray(index int) {
-	gl := c.gl
-	gl.disableVertexAttribArray.Invoke(index)
+	c.commands.disableVertexAttribArray(index)
 }
 
 func (c *context) newArrayBuffer(size int) buffer {
 	gl := c.gl
-	b := gl.createBuffer.Invoke()
-	gl.bindBuffer.Invoke(gles.ARRAY_BUFFER, js.Value(b))
-	gl.bufferData.Invoke(gles.ARRAY_BUFFER, size, gles.DYNAMIC_DRAW)
-	return buffer(b)
+	b := buffer(c.newObject(gl.createBuffer.Invoke()))
+	c.commands.bindBuffer(gles.ARRAY_BUFFER, b.id)
+	c.commands.bufferData(gles.ARRAY_BUFFER, size, gles.DYNAMIC_DRAW)
+	return b
 }
 
 func (c *context) newElementArrayBuffer(size int) buffer {
 	gl := c.gl
-	b := gl.createBuffer.Invoke()
-	gl.bindBuffer.Invoke(gles.ELEMENT_ARRAY_BUFFER, js.Value(b))
-	gl.bufferData.Invoke(gles.ELEMENT_ARRAY_BUFFER, size, gles.DYNAMIC_DRAW)
-	return buffer(b)
+	b := buffer(c.newObject(gl.createBuffer.Invoke()))
+	c.commands.bindBuffer(gles.ELEMENT_ARRAY_BUFFER, b.id)
+	c.commands.bufferData(gles.ELEMENT_ARRAY_BUFFER, size, gles.DYNAMIC_DRAW)
+	return b
 }
 
 func (c *context) bindArrayBuffer(b buffer) {
-	gl := c.gl
-	gl.bindBuffer.Invoke(gles.ARRAY_BUFFER, js.Value(b))
+	c.commands.bindBuffer(gles.ARRAY_BUFFER, b.id)
 }
 
 func (c *context) bindElementArrayBuffer(b buffer) {
-	gl := c.gl
-	gl.bindBuffer.Invoke(gles.ELEMENT_ARRAY_BUFFER, js.Value(b))
+	c.commands.bindBuffer(gles.ELEMENT_ARRAY_BUFFER, b.id)
 }
 
 func (c *context) arrayBufferSubData(data []float32) {
-	gl := c.gl
-	l := len(data) * 4
-	arr := jsutil.TemporaryUint8ArrayFromFloat32Slice(l, data)
-	if c.usesWebGL2() {
-		gl.bufferSubData.Invoke(gles.ARRAY_BUFFER, 0, arr, 0, l)
-	} else {
-		gl.bufferSubData.Invoke(gles.ARRAY_BUFFER, 0, arr.Call("subarray", 0, l))
-	}
+	h := (*reflect.SliceHeader)(unsafe.Pointer(&data))
+	h.Len *= 4
+	h.Cap *= 4
+	bs := *(*[]byte)(unsafe.Pointer(h))
+	c.commands.bufferSubData(gles.ARRAY_BUFFER, 0, bs)
+	runtime.KeepAlive(data)
 }
 
 func (c *context) elementArrayBufferSubData(data []uint16) {
-	gl := c.gl
-	l := len(data) * 2
-	arr := jsutil.TemporaryUint8ArrayFromUint16Slice(l, data)
-	if c.usesWebGL2() {
-		gl.bufferSubData.Invoke(gles.ELEMENT_ARRAY_BUFFER, 0, arr, 0, l)
-	} else {
-		gl.bufferSubData.Invoke(gles.ELEMENT_ARRAY_BUFFER, 0, arr.Call("subarray", 0, l))
-	}
+	h := (*reflect.SliceHeader)(unsafe.Pointer(&data))
+	h.Len *= 2
+	h.Cap *= 2
+	bs := *(*[]byte)(unsafe.Pointer(h))
+	c.commands.bufferSubData(gles.ELEMENT_ARRAY_BUFFER, 0, bs)
+	runtime.KeepAlive(data)
 }
 
 func (c *context) deleteBuffer(b buffer) {
-	gl := c.gl
-	gl.deleteBuffer.Invoke(js.Value(b))
+	c.commands.deleteBuffer(b.id)
 }
 
 func (c *context) drawElements(len int, offsetInBytes int) {
-	gl := c.gl
-	gl.drawElements.Invoke(gles.TRIANGLES, len, gles.UNSIGNED_SHORT, offsetInBytes)
+	c.commands.drawElements(gles.TRIANGLES, len, gles.UNSIGNED_SHORT, offsetInBytes)
 }
 
 func (c *context) maxTextureSizeImpl() int {
@@ -600,12 +669,40 @@ func (c *context) maxTextureSizeImpl() int {
 	return gl.getParameter.Invoke(gles.MAX_TEXTURE_SIZE).Int()
 }
 
+func (c *context) driverInfoImpl() graphicsdriver.DriverInfo {
+	gl := c.gl
+
+	vendor, renderer := gles.VENDOR, gles.RENDERER
+	// Browsers mask the actual GPU names unless WEBGL_debug_renderer_info is available.
+	if gl.getExtension.Invoke("WEBGL_debug_renderer_info").Truthy() {
+		const (
+			unmaskedVendorWebGL   = 0x9245
+			unmaskedRendererWebGL = 0x9246
+		)
+		vendor, renderer = unmaskedVendorWebGL, unmaskedRendererWebGL
+	}
+	str := func(name int) string {
+		v := gl.getParameter.Invoke(name)
+		if v.Type() != js.TypeString {
+			return ""
+		}
+		return v.String()
+	}
+	return graphicsdriver.DriverInfo{
+		Adapter:       str(vendor) + " " + str(renderer),
+		DriverVersion: str(gles.VERSION),
+		FeatureLevel:  str(gles.SHADING_LANGUAGE_VERSION),
+	}
+}
+
 func (c *context) getShaderPrecisionFormatPrecision() int {
 	gl := c.gl
 	return gl.getShaderPrecisionFormat.Invoke(gles.FRAGMENT_SHADER, gles.HIGH_FLOAT).Get("precision").Int()
 }
 
 func (c *context) flush() {
+	// Presenting the screen is a sync point.
+	c.commands.flush()
 	gl := c.gl
 	gl.flush.Invoke()
 }
@@ -621,44 +718,51 @@ func (c *context) canUsePBO() bool {
 
 func (c *context) texSubImage2D(t textureNative, args []*graphicsdriver.ReplacePixelsArgs) {
 	c.bindTexture(t)
-	gl := c.gl
 	for _, a := range args {
-		arr := jsutil.TemporaryUint8ArrayFromUint8Slice(len(a.Pixels), a.Pixels)
-		if c.usesWebGL2() {
-			// void texSubImage2D(GLenum target, GLint level, GLint xoffset, GLint yoffset,
-			//                    GLsizei width, GLsizei height,
-			//                    GLenum format, GLenum type, ArrayBufferView pixels, srcOffset);
-			gl.texSubImage2D.Invoke(gles.TEXTURE_2D, 0, a.X, a.Y, a.Width, a.Height, gles.RGBA, gles.UNSIGNED_BYTE, arr, 0)
-		} else {
-			// void texSubImage2D(GLenum target, GLint level, GLint xoffset, GLint yoffset,
-			//                    GLsizei width, GLsizei height,
-			//                    GLenum format, GLenum type, ArrayBufferView? pixels);
-			gl.texSubImage2D.Invoke(gles.TEXTURE_2D, 0, a.X, a.Y, a.Width, a.Height, gles.RGBA, gles.UNSIGNED_BYTE, arr)
-		}
+		c.commands.texSubImage2D(gles.TEXTURE_2D, 0, a.X, a.Y, a.Width, a.Height, gles.RGBA, gles.UNSIGNED_BYTE, a.Pixels)
 	}
 }
 
+func (c *context) copyTexSubImage2D(dst textureNative, src *framebuffer, dstX, dstY, srcX, srcY, width, height int) {
+	c.bindFramebuffer(src.native)
+	c.bindTexture(dst)
+	c.commands.copyTexSubImage2D(gles.TEXTURE_2D, 0, dstX, dstY, srcX, srcY, width, height)
+}
+
 func (c *context) enableStencilTest() {
-	gl := c.gl
-	gl.enable.Invoke(gles.STENCIL_TEST)
+	c.commands.enable(gles.STENCIL_TEST)
 }
 
 func (c *context) disableStencilTest() {
-	gl := c.gl
-	gl.disable.Invoke(gles.STENCIL_TEST)
+	c.commands.disable(gles.STENCIL_TEST)
+}
+
+func (c *context) enableDepthTest() {
+	c.commands.enable(gles.DEPTH_TEST)
+}
+
+func (c *context) disableDepthTest() {
+	c.commands.disable(gles.DEPTH_TEST)
+}
+
+func (c *context) clearDepth() {
+	c.commands.clear(gles.DEPTH_BUFFER_BIT)
+}
+
+func (c *context) clearColor(red, green, blue, alpha float32) {
+	c.commands.clearColor(red, green, blue, alpha)
+	c.commands.clear(gles.COLOR_BUFFER_BIT)
 }
 
 func (c *context) beginStencilWithEvenOddRule() {
-	gl := c.gl
-	gl.clear.Invoke(gles.STENCIL_BUFFER_BIT)
-	gl.stencilFunc.Invoke(gles.ALWAYS, 0x00, 0xff)
-	gl.stencilOp.Invoke(gles.KEEP, gles.KEEP, gles.INVERT)
-	gl.colorMask.Invoke(false, false, false, false)
+	c.commands.clear(gles.STENCIL_BUFFER_BIT)
+	c.commands.stencilFunc(gles.ALWAYS, 0x00, 0xff)
+	c.commands.stencilOp(gles.KEEP, gles.KEEP, gles.INVERT)
+	c.commands.colorMask(false, false, false, false)
 }
 
 func (c *context) endStencilWithEvenOddRule() {
-	gl := c.gl
-	gl.stencilFunc.Invoke(gles.NOTEQUAL, 0x00, 0xff)
-	gl.stencilOp.Invoke(gles.KEEP, gles.KEEP, gles.KEEP)
-	gl.colorMask.Invoke(true, true, true, true)
+	c.commands.stencilFunc(gles.NOTEQUAL, 0x00, 0xff)
+	c.commands.stencilOp(gles.KEEP, gles.KEEP, gles.KEEP)
+	c.commands.colorMask(true, true, true, true)
 }
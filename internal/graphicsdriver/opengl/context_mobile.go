@@ -96,6 +96,10 @@ type contextImpl struct {
 	ctx gles.Context
 }
 
+func (c *context) graphicsLibrary() graphicsdriver.GraphicsLibrary {
+	return graphicsdriver.GraphicsLibraryOpenGLES
+}
+
 func (c *context) reset() error {
 	c.locationCache = newLocationCache()
 	c.lastTexture = invalidTexture
@@ -105,6 +109,7 @@ func (c *context) reset() error {
 	c.lastCompositeMode = graphicsdriver.CompositeModeUnknown
 	c.ctx.Enable(gles.BLEND)
 	c.ctx.Enable(gles.SCISSOR_TEST)
+	c.ctx.DepthFunc(gles.LEQUAL)
 	c.blendFunc(graphicsdriver.CompositeModeSourceOver)
 	f := make([]int32, 1)
 	c.ctx.GetIntegerv(f, gles.FRAMEBUFFER_BINDING)
@@ -113,6 +118,19 @@ func (c *context) reset() error {
 	return nil
 }
 
+// invalidateState forgets the cached state and restores the capabilities Ebiten assumes.
+func (c *context) invalidateState() {
+	c.ctx.Enable(gles.BLEND)
+	c.ctx.Enable(gles.SCISSOR_TEST)
+	c.ctx.DepthFunc(gles.LEQUAL)
+	c.lastTexture = invalidTexture
+	c.lastFramebuffer = invalidFramebuffer
+	c.lastRenderbuffer = 0
+	c.lastViewportWidth = 0
+	c.lastViewportHeight = 0
+	c.lastCompositeMode = graphicsdriver.CompositeModeUnknown
+}
+
 func (c *context) blendFunc(mode graphicsdriver.CompositeMode) {
 	if c.lastCompositeMode == mode {
 		return
@@ -144,6 +162,59 @@ func (c *context) newTexture(width, height int) (textureNative, error) {
 	return textureNative(t), nil
 }
 
+func (c *context) newCompressedTexture(width, height int, internalFormat uint32, data []byte) (textureNative, error) {
+	t := c.ctx.GenTextures(1)[0]
+	if t <= 0 {
+		return 0, errors.New("opengl: creating texture failed")
+	}
+	c.bindTexture(textureNative(t))
+
+	c.ctx.TexParameteri(gles.TEXTURE_2D, gles.TEXTURE_MAG_FILTER, gles.NEAREST)
+	c.ctx.TexParameteri(gles.TEXTURE_2D, gles.TEXTURE_MIN_FILTER, gles.NEAREST)
+	c.ctx.TexParameteri(gles.TEXTURE_2D, gles.TEXTURE_WRAP_S, gles.CLAMP_TO_EDGE)
+	c.ctx.TexParameteri(gles.TEXTURE_2D, gles.TEXTURE_WRAP_T, gles.CLAMP_TO_EDGE)
+	c.ctx.CompressedTexImage2D(gles.TEXTURE_2D, 0, internalFormat, int32(width), int32(height), data)
+
+	return textureNative(t), nil
+}
+
+func (c *context) validateNativeTexture(handle uintptr) error {
+	if !c.ctx.IsTexture(uint32(handle)) {
+		return fmt.Errorf("opengl: %d is not a texture", handle)
+	}
+	return nil
+}
+
+func (c *context) textureFromNative(handle uintptr) (textureNative, error) {
+	if err := c.validateNativeTexture(handle); err != nil {
+		return 0, err
+	}
+	t := textureNative(handle)
+	c.bindTexture(t)
+
+	// Ebiten's shaders filter the texels by themselves.
+	c.ctx.TexParameteri(gles.TEXTURE_2D, gles.TEXTURE_MAG_FILTER, gles.NEAREST)
+	c.ctx.TexParameteri(gles.TEXTURE_2D, gles.TEXTURE_MIN_FILTER, gles.NEAREST)
+	c.ctx.TexParameteri(gles.TEXTURE_2D, gles.TEXTURE_WRAP_S, gles.CLAMP_TO_EDGE)
+	c.ctx.TexParameteri(gles.TEXTURE_2D, gles.TEXTURE_WRAP_T, gles.CLAMP_TO_EDGE)
+	return t, nil
+}
+
+func (c *context) compressedTextureFormatsImpl() []uint32 {
+	n := make([]int32, 1)
+	c.ctx.GetIntegerv(n, gles.NUM_COMPRESSED_TEXTURE_FORMATS)
+	if n[0] == 0 {
+		return nil
+	}
+	fs := make([]int32, n[0])
+	c.ctx.GetIntegerv(fs, gles.COMPRESSED_TEXTURE_FORMATS)
+	r := make([]uint32, n[0])
+	for i, f := range fs {
+		r[i] = uint32(f)
+	}
+	return r
+}
+
 func (c *context) bindFramebufferImpl(f framebufferNative) {
 	c.ctx.BindFramebuffer(gles.FRAMEBUFFER, uint32(f))
 }
@@ -188,7 +259,7 @@ func (c *context) isTexture(t textureNative) bool {
 	return c.ctx.IsTexture(uint32(t))
 }
 
-func (c *context) newRenderbuffer(width, height int) (renderbufferNative, error) {
+func (c *context) newDepthStencilRenderbuffer(width, height int) (renderbufferNative, error) {
 	r := c.ctx.GenRenderbuffers(1)[0]
 	if r <= 0 {
 		return 0, errors.New("opengl: creating renderbuffer failed")
@@ -197,7 +268,8 @@ func (c *context) newRenderbuffer(width, height int) (renderbufferNative, error)
 	renderbuffer := renderbufferNative(r)
 	c.bindRenderbuffer(renderbuffer)
 
-	c.ctx.RenderbufferStorage(gles.RENDERBUFFER, gles.STENCIL_INDEX8, int32(width), int32(height))
+	// GL_DEPTH24_STENCIL8 is available with OpenGL ES 3 and OpenGL ES 2 with GL_OES_packed_depth_stencil.
+	c.ctx.RenderbufferStorage(gles.RENDERBUFFER, gles.DEPTH24_STENCIL8, int32(width), int32(height))
 
 	return renderbuffer, nil
 }
@@ -237,9 +309,11 @@ func (c *context) newFramebuffer(texture textureNative) (framebufferNative, erro
 	return framebufferNative(f), nil
 }
 
-func (c *context) bindStencilBuffer(f framebufferNative, r renderbufferNative) error {
+func (c *context) bindDepthStencilBuffer(f framebufferNative, r renderbufferNative) error {
 	c.bindFramebuffer(f)
 
+	// GL_DEPTH_STENCIL_ATTACHMENT is not available with OpenGL ES 2. Attach the packed buffer to both.
+	c.ctx.FramebufferRenderbuffer(gles.FRAMEBUFFER, gles.DEPTH_ATTACHMENT, gles.RENDERBUFFER, uint32(r))
 	c.ctx.FramebufferRenderbuffer(gles.FRAMEBUFFER, gles.STENCIL_ATTACHMENT, gles.RENDERBUFFER, uint32(r))
 	if s := c.ctx.CheckFramebufferStatus(gles.FRAMEBUFFER); s != gles.FRAMEBUFFER_COMPLETE {
 		return errors.New(fmt.Sprintf("opengl: glFramebufferRenderbuffer failed: %d", s))
@@ -443,6 +517,14 @@ func (c *context) maxTextureSizeImpl() int {
 	return int(v[0])
 }
 
+func (c *context) driverInfoImpl() graphicsdriver.DriverInfo {
+	return graphicsdriver.DriverInfo{
+		Adapter:       c.ctx.GetString(gles.VENDOR) + " " + c.ctx.GetString(gles.RENDERER),
+		DriverVersion: c.ctx.GetString(gles.VERSION),
+		FeatureLevel:  c.ctx.GetString(gles.SHADING_LANGUAGE_VERSION),
+	}
+}
+
 func (c *context) getShaderPrecisionFormatPrecision() int {
 	_, _, p := c.ctx.GetShaderPrecisionFormat(gles.FRAGMENT_SHADER, gles.HIGH_FLOAT)
 	return p
@@ -469,6 +551,12 @@ func (c *context) texSubImage2D(t textureNative, args []*graphicsdriver.ReplaceP
 	}
 }
 
+func (c *context) copyTexSubImage2D(dst textureNative, src *framebuffer, dstX, dstY, srcX, srcY, width, height int) {
+	c.bindFramebuffer(src.native)
+	c.bindTexture(dst)
+	c.ctx.CopyTexSubImage2D(gles.TEXTURE_2D, 0, int32(dstX), int32(dstY), int32(srcX), int32(srcY), int32(width), int32(height))
+}
+
 func (c *context) enableStencilTest() {
 	c.ctx.Enable(gles.STENCIL_TEST)
 }
@@ -477,6 +565,23 @@ func (c *context) disableStencilTest() {
 	c.ctx.Disable(gles.STENCIL_TEST)
 }
 
+func (c *context) enableDepthTest() {
+	c.ctx.Enable(gles.DEPTH_TEST)
+}
+
+func (c *context) disableDepthTest() {
+	c.ctx.Disable(gles.DEPTH_TEST)
+}
+
+func (c *context) clearDepth() {
+	c.ctx.Clear(gles.DEPTH_BUFFER_BIT)
+}
+
+func (c *context) clearColor(red, green, blue, alpha float32) {
+	c.ctx.ClearColor(red, green, blue, alpha)
+	c.ctx.Clear(gles.COLOR_BUFFER_BIT)
+}
+
 func (c *context) beginStencilWithEvenOddRule() {
 	c.ctx.Clear(gles.STENCIL_BUFFER_BIT)
 	c.ctx.StencilFunc(gles.ALWAYS, 0x00, 0xff)
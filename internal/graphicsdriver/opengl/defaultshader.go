@@ -61,7 +61,7 @@ func vertexShaderStr() string {
 	return src
 }
 
-func fragmentShaderStr(useColorM bool, filter graphicsdriver.Filter, address graphicsdriver.Address) string {
+func fragmentShaderStr(useColorM bool, filter graphicsdriver.Filter, address graphicsdriver.Address, depthTest bool) string {
 	replaces := map[string]string{
 		"{{.AddressClampToZero}}": fmt.Sprintf("%d", graphicsdriver.AddressClampToZero),
 		"{{.AddressRepeat}}":      fmt.Sprintf("%d", graphicsdriver.AddressRepeat),
@@ -78,6 +78,10 @@ func fragmentShaderStr(useColorM bool, filter graphicsdriver.Filter, address gra
 		defs = append(defs, "#define USE_COLOR_MATRIX")
 	}
 
+	if depthTest {
+		defs = append(defs, "#define DEPTH_TEST")
+	}
+
 	switch filter {
 	case graphicsdriver.FilterNearest:
 		defs = append(defs, "#define FILTER_NEAREST")
@@ -109,9 +113,13 @@ func fragmentShaderStr(useColorM bool, filter graphicsdriver.Filter, address gra
 const (
 	shaderStrVertex = `
 uniform vec2 viewport_size;
+uniform mat2 geo_matrix_body;
+uniform vec2 geo_matrix_translation;
+uniform vec4 color_scale;
 attribute vec2 A0;
 attribute vec2 A1;
 attribute vec4 A2;
+attribute float A3;
 varying vec2 varying_tex;
 varying vec4 varying_color_scale;
 
@@ -119,7 +127,8 @@ void main(void) {
   varying_tex = A1;
 
   // Fragment shader wants premultiplied alpha.
-  varying_color_scale = vec4(A2.rgb, 1) * A2.a;
+  vec4 color = A2 * color_scale;
+  varying_color_scale = vec4(color.rgb, 1) * color.a;
 
   mat4 projection_matrix = mat4(
     vec4(2.0 / viewport_size.x, 0, 0, 0),
@@ -127,7 +136,7 @@ void main(void) {
     vec4(0, 0, 1, 0),
     vec4(-1, -1, 0, 1)
   );
-  gl_Position = projection_matrix * vec4(A0, 0, 1);
+  gl_Position = projection_matrix * vec4(geo_matrix_body * A0 + geo_matrix_translation, A3, 1);
 }
 `
 	shaderStrFragment = `
@@ -281,6 +290,13 @@ void main(void) {
   // No clamping needed as the color matrix shader is used then.
 # endif  // defined(USE_COLOR_MATRIX)
 
+# if defined(DEPTH_TEST)
+  // Fully transparent pixels must not update the depth buffer.
+  if (color.a == 0.0) {
+    discard;
+  }
+# endif  // defined(DEPTH_TEST)
+
   gl_FragColor = color;
 
 #endif  // defined(FILTER_SCREEN)
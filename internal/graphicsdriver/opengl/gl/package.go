@@ -29,8 +29,12 @@ const (
 	BLEND                = 0x0BE2
 	CLAMP_TO_EDGE        = 0x812F
 	COLOR_ATTACHMENT0    = 0x8CE0
+	COLOR_BUFFER_BIT     = 0x4000
 	COMPILE_STATUS       = 0x8B81
 	DEPTH24_STENCIL8     = 0x88F0
+	DEPTH_ATTACHMENT     = 0x8D00
+	DEPTH_BUFFER_BIT     = 0x0100
+	DEPTH_TEST           = 0x0B71
 	DYNAMIC_DRAW         = 0x88E8
 	ELEMENT_ARRAY_BUFFER = 0x8893
 	FALSE                = 0
@@ -39,9 +43,11 @@ const (
 	FRAMEBUFFER          = 0x8D40
 	FRAMEBUFFER_BINDING  = 0x8CA6
 	FRAMEBUFFER_COMPLETE = 0x8CD5
+	FRAMEBUFFER_SRGB     = 0x8DB9
 	INFO_LOG_LENGTH      = 0x8B84
 	INVERT               = 0x150A
 	KEEP                 = 0x1E00
+	LEQUAL               = 0x0203
 	LINK_STATUS          = 0x8B82
 	MAX_TEXTURE_SIZE     = 0x0D33
 	NEAREST              = 0x2600
@@ -53,6 +59,7 @@ const (
 	RENDERBUFFER         = 0x8D41
 	RGBA                 = 0x1908
 	SHORT                = 0x1402
+	SRGB8_ALPHA8         = 0x8C43
 	STENCIL_ATTACHMENT   = 0x8D20
 	STENCIL_BUFFER_BIT   = 0x0400
 	STENCIL_TEST         = 0x0B90
@@ -73,6 +80,19 @@ const (
 	WRITE_ONLY           = 0x88B9
 )
 
+const (
+	COMPRESSED_TEXTURE_FORMATS     = 0x86A3
+	NUM_COMPRESSED_TEXTURE_FORMATS = 0x86A2
+)
+
+const (
+	EXTENSIONS               = 0x1F03
+	RENDERER                 = 0x1F01
+	SHADING_LANGUAGE_VERSION = 0x8B8C
+	VENDOR                   = 0x1F00
+	VERSION                  = 0x1F02
+)
+
 // Init initializes the OpenGL bindings by loading the function pointers (for
 // each OpenGL function) from the active OpenGL context.
 //
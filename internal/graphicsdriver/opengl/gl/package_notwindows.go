@@ -108,8 +108,11 @@ package gl
 // typedef void  (APIENTRYP GPBUFFERSUBDATA)(GLenum  target, GLintptr  offset, GLsizeiptr  size, const void * data);
 // typedef GLenum  (APIENTRYP GPCHECKFRAMEBUFFERSTATUSEXT)(GLenum  target);
 // typedef void  (APIENTRYP GPCLEAR)(GLbitfield  mask);
+// typedef void  (APIENTRYP GPCLEARCOLOR)(GLfloat  red, GLfloat  green, GLfloat  blue, GLfloat  alpha);
 // typedef void  (APIENTRYP GPCOLORMASK)(GLboolean  red, GLboolean  green, GLboolean  blue, GLboolean  alpha);
 // typedef void  (APIENTRYP GPCOMPILESHADER)(GLuint  shader);
+// typedef void  (APIENTRYP GPCOMPRESSEDTEXIMAGE2D)(GLenum  target, GLint  level, GLenum  internalformat, GLsizei  width, GLsizei  height, GLint  border, GLsizei  imageSize, const void * data);
+// typedef void  (APIENTRYP GPCOPYTEXSUBIMAGE2D)(GLenum  target, GLint  level, GLint  xoffset, GLint  yoffset, GLint  x, GLint  y, GLsizei  width, GLsizei  height);
 // typedef GLuint  (APIENTRYP GPCREATEPROGRAM)();
 // typedef GLuint  (APIENTRYP GPCREATESHADER)(GLenum  type);
 // typedef void  (APIENTRYP GPDELETEBUFFERS)(GLsizei  n, const GLuint * buffers);
@@ -118,6 +121,7 @@ package gl
 // typedef void  (APIENTRYP GPDELETERENDERBUFFERSEXT)(GLsizei  n, const GLuint * renderbuffers);
 // typedef void  (APIENTRYP GPDELETESHADER)(GLuint  shader);
 // typedef void  (APIENTRYP GPDELETETEXTURES)(GLsizei  n, const GLuint * textures);
+// typedef void  (APIENTRYP GPDEPTHFUNC)(GLenum  func);
 // typedef void  (APIENTRYP GPDISABLE)(GLenum  cap);
 // typedef void  (APIENTRYP GPDISABLEVERTEXATTRIBARRAY)(GLuint  index);
 // typedef void  (APIENTRYP GPDRAWELEMENTS)(GLenum  mode, GLsizei  count, GLenum  type, const uintptr_t indices);
@@ -144,6 +148,7 @@ package gl
 // typedef void  (APIENTRYP GPGETPROGRAMIV)(GLuint  program, GLenum  pname, GLint * params);
 // typedef void  (APIENTRYP GPGETSHADERINFOLOG)(GLuint  shader, GLsizei  bufSize, GLsizei * length, GLchar * infoLog);
 // typedef void  (APIENTRYP GPGETSHADERIV)(GLuint  shader, GLenum  pname, GLint * params);
+// typedef const GLubyte * (APIENTRYP GPGETSTRING)(GLenum  name);
 // typedef void  (APIENTRYP GPGETTRANSFORMFEEDBACKI64_V)(GLuint  xfb, GLenum  pname, GLuint  index, GLint64 * param);
 // typedef void  (APIENTRYP GPGETTRANSFORMFEEDBACKI_V)(GLuint  xfb, GLenum  pname, GLuint  index, GLint * param);
 // typedef GLint  (APIENTRYP GPGETUNIFORMLOCATION)(GLuint  program, const GLchar * name);
@@ -214,12 +219,21 @@ package gl
 // static void  glowClear(GPCLEAR fnptr, GLbitfield  mask) {
 //   (*fnptr)(mask);
 // }
+// static void  glowClearColor(GPCLEARCOLOR fnptr, GLfloat  red, GLfloat  green, GLfloat  blue, GLfloat  alpha) {
+//   (*fnptr)(red, green, blue, alpha);
+// }
 // static void  glowColorMask(GPCOLORMASK fnptr, GLboolean  red, GLboolean  green, GLboolean  blue, GLboolean  alpha) {
 //   (*fnptr)(red, green, blue, alpha);
 // }
 // static void  glowCompileShader(GPCOMPILESHADER fnptr, GLuint  shader) {
 //   (*fnptr)(shader);
 // }
+// static void  glowCompressedTexImage2D(GPCOMPRESSEDTEXIMAGE2D fnptr, GLenum  target, GLint  level, GLenum  internalformat, GLsizei  width, GLsizei  height, GLint  border, GLsizei  imageSize, const void * data) {
+//   (*fnptr)(target, level, internalformat, width, height, border, imageSize, data);
+// }
+// static void  glowCopyTexSubImage2D(GPCOPYTEXSUBIMAGE2D fnptr, GLenum  target, GLint  level, GLint  xoffset, GLint  yoffset, GLint  x, GLint  y, GLsizei  width, GLsizei  height) {
+//   (*fnptr)(target, level, xoffset, yoffset, x, y, width, height);
+// }
 // static GLuint  glowCreateProgram(GPCREATEPROGRAM fnptr) {
 //   return (*fnptr)();
 // }
@@ -244,6 +258,9 @@ package gl
 // static void  glowDeleteTextures(GPDELETETEXTURES fnptr, GLsizei  n, const GLuint * textures) {
 //   (*fnptr)(n, textures);
 // }
+// static void  glowDepthFunc(GPDEPTHFUNC fnptr, GLenum  func) {
+//   (*fnptr)(func);
+// }
 // static void  glowDisable(GPDISABLE fnptr, GLenum  cap) {
 //   (*fnptr)(cap);
 // }
@@ -322,6 +339,9 @@ package gl
 // static void  glowGetShaderiv(GPGETSHADERIV fnptr, GLuint  shader, GLenum  pname, GLint * params) {
 //   (*fnptr)(shader, pname, params);
 // }
+// static const GLubyte * glowGetString(GPGETSTRING fnptr, GLenum  name) {
+//   return (*fnptr)(name);
+// }
 // static void  glowGetTransformFeedbacki64_v(GPGETTRANSFORMFEEDBACKI64_V fnptr, GLuint  xfb, GLenum  pname, GLuint  index, GLint64 * param) {
 //   (*fnptr)(xfb, pname, index, param);
 // }
@@ -441,8 +461,11 @@ var (
 	gpBufferSubData               C.GPBUFFERSUBDATA
 	gpCheckFramebufferStatusEXT   C.GPCHECKFRAMEBUFFERSTATUSEXT
 	gpClear                       C.GPCLEAR
+	gpClearColor                  C.GPCLEARCOLOR
 	gpColorMask                   C.GPCOLORMASK
 	gpCompileShader               C.GPCOMPILESHADER
+	gpCompressedTexImage2D        C.GPCOMPRESSEDTEXIMAGE2D
+	gpCopyTexSubImage2D           C.GPCOPYTEXSUBIMAGE2D
 	gpCreateProgram               C.GPCREATEPROGRAM
 	gpCreateShader                C.GPCREATESHADER
 	gpDeleteBuffers               C.GPDELETEBUFFERS
@@ -451,6 +474,7 @@ var (
 	gpDeleteRenderbuffersEXT      C.GPDELETERENDERBUFFERSEXT
 	gpDeleteShader                C.GPDELETESHADER
 	gpDeleteTextures              C.GPDELETETEXTURES
+	gpDepthFunc                   C.GPDEPTHFUNC
 	gpDisable                     C.GPDISABLE
 	gpDisableVertexAttribArray    C.GPDISABLEVERTEXATTRIBARRAY
 	gpDrawElements                C.GPDRAWELEMENTS
@@ -477,6 +501,7 @@ var (
 	gpGetProgramiv                C.GPGETPROGRAMIV
 	gpGetShaderInfoLog            C.GPGETSHADERINFOLOG
 	gpGetShaderiv                 C.GPGETSHADERIV
+	gpGetString                   C.GPGETSTRING
 	gpGetTransformFeedbacki64_v   C.GPGETTRANSFORMFEEDBACKI64_V
 	gpGetTransformFeedbacki_v     C.GPGETTRANSFORMFEEDBACKI_V
 	gpGetUniformLocation          C.GPGETUNIFORMLOCATION
@@ -568,6 +593,10 @@ func Clear(mask uint32) {
 	C.glowClear(gpClear, (C.GLbitfield)(mask))
 }
 
+func ClearColor(red float32, green float32, blue float32, alpha float32) {
+	C.glowClearColor(gpClearColor, (C.GLfloat)(red), (C.GLfloat)(green), (C.GLfloat)(blue), (C.GLfloat)(alpha))
+}
+
 func ColorMask(red bool, green bool, blue bool, alpha bool) {
 	C.glowColorMask(gpColorMask, (C.GLboolean)(boolToInt(red)), (C.GLboolean)(boolToInt(green)), (C.GLboolean)(boolToInt(blue)), (C.GLboolean)(boolToInt(alpha)))
 }
@@ -576,6 +605,14 @@ func CompileShader(shader uint32) {
 	C.glowCompileShader(gpCompileShader, (C.GLuint)(shader))
 }
 
+func CompressedTexImage2D(target uint32, level int32, internalformat uint32, width int32, height int32, border int32, imageSize int32, data unsafe.Pointer) {
+	C.glowCompressedTexImage2D(gpCompressedTexImage2D, (C.GLenum)(target), (C.GLint)(level), (C.GLenum)(internalformat), (C.GLsizei)(width), (C.GLsizei)(height), (C.GLint)(border), (C.GLsizei)(imageSize), data)
+}
+
+func CopyTexSubImage2D(target uint32, level int32, xoffset int32, yoffset int32, x int32, y int32, width int32, height int32) {
+	C.glowCopyTexSubImage2D(gpCopyTexSubImage2D, (C.GLenum)(target), (C.GLint)(level), (C.GLint)(xoffset), (C.GLint)(yoffset), (C.GLint)(x), (C.GLint)(y), (C.GLsizei)(width), (C.GLsizei)(height))
+}
+
 func CreateProgram() uint32 {
 	ret := C.glowCreateProgram(gpCreateProgram)
 	return (uint32)(ret)
@@ -610,6 +647,10 @@ func DeleteTextures(n int32, textures *uint32) {
 	C.glowDeleteTextures(gpDeleteTextures, (C.GLsizei)(n), (*C.GLuint)(unsafe.Pointer(textures)))
 }
 
+func DepthFunc(xfunc uint32) {
+	C.glowDepthFunc(gpDepthFunc, (C.GLenum)(xfunc))
+}
+
 func Disable(cap uint32) {
 	C.glowDisable(gpDisable, (C.GLenum)(cap))
 }
@@ -710,6 +751,11 @@ func GetShaderiv(shader uint32, pname uint32, params *int32) {
 	C.glowGetShaderiv(gpGetShaderiv, (C.GLuint)(shader), (C.GLenum)(pname), (*C.GLint)(unsafe.Pointer(params)))
 }
 
+func GetString(name uint32) *uint8 {
+	ret := C.glowGetString(gpGetString, (C.GLenum)(name))
+	return (*uint8)(ret)
+}
+
 func GetTransformFeedbacki64_v(xfb uint32, pname uint32, index uint32, param *int64) {
 	C.glowGetTransformFeedbacki64_v(gpGetTransformFeedbacki64_v, (C.GLuint)(xfb), (C.GLenum)(pname), (C.GLuint)(index), (*C.GLint64)(unsafe.Pointer(param)))
 }
@@ -888,6 +934,10 @@ func InitWithProcAddrFunc(getProcAddr func(name string) unsafe.Pointer) error {
 	if gpClear == nil {
 		return errors.New("glClear")
 	}
+	gpClearColor = (C.GPCLEARCOLOR)(getProcAddr("glClearColor"))
+	if gpClearColor == nil {
+		return errors.New("glClearColor")
+	}
 	gpColorMask = (C.GPCOLORMASK)(getProcAddr("glColorMask"))
 	if gpColorMask == nil {
 		return errors.New("glColorMask")
@@ -896,6 +946,14 @@ func InitWithProcAddrFunc(getProcAddr func(name string) unsafe.Pointer) error {
 	if gpCompileShader == nil {
 		return errors.New("glCompileShader")
 	}
+	gpCompressedTexImage2D = (C.GPCOMPRESSEDTEXIMAGE2D)(getProcAddr("glCompressedTexImage2D"))
+	if gpCompressedTexImage2D == nil {
+		return errors.New("glCompressedTexImage2D")
+	}
+	gpCopyTexSubImage2D = (C.GPCOPYTEXSUBIMAGE2D)(getProcAddr("glCopyTexSubImage2D"))
+	if gpCopyTexSubImage2D == nil {
+		return errors.New("glCopyTexSubImage2D")
+	}
 	gpCreateProgram = (C.GPCREATEPROGRAM)(getProcAddr("glCreateProgram"))
 	if gpCreateProgram == nil {
 		return errors.New("glCreateProgram")
@@ -922,6 +980,10 @@ func InitWithProcAddrFunc(getProcAddr func(name string) unsafe.Pointer) error {
 	if gpDeleteTextures == nil {
 		return errors.New("glDeleteTextures")
 	}
+	gpDepthFunc = (C.GPDEPTHFUNC)(getProcAddr("glDepthFunc"))
+	if gpDepthFunc == nil {
+		return errors.New("glDepthFunc")
+	}
 	gpDisable = (C.GPDISABLE)(getProcAddr("glDisable"))
 	if gpDisable == nil {
 		return errors.New("glDisable")
@@ -993,6 +1055,10 @@ func InitWithProcAddrFunc(getProcAddr func(name string) unsafe.Pointer) error {
 	if gpGetShaderiv == nil {
 		return errors.New("glGetShaderiv")
 	}
+	gpGetString = (C.GPGETSTRING)(getProcAddr("glGetString"))
+	if gpGetString == nil {
+		return errors.New("glGetString")
+	}
 	gpGetTransformFeedbacki64_v = (C.GPGETTRANSFORMFEEDBACKI64_V)(getProcAddr("glGetTransformFeedbacki64_v"))
 	gpGetTransformFeedbacki_v = (C.GPGETTRANSFORMFEEDBACKI_V)(getProcAddr("glGetTransformFeedbacki_v"))
 	gpGetUniformLocation = (C.GPGETUNIFORMLOCATION)(getProcAddr("glGetUniformLocation"))
@@ -22,8 +22,11 @@ var (
 	gpBufferSubData               uintptr
 	gpCheckFramebufferStatusEXT   uintptr
 	gpClear                       uintptr
+	gpClearColor                  uintptr
 	gpColorMask                   uintptr
 	gpCompileShader               uintptr
+	gpCompressedTexImage2D        uintptr
+	gpCopyTexSubImage2D           uintptr
 	gpCreateProgram               uintptr
 	gpCreateShader                uintptr
 	gpDeleteBuffers               uintptr
@@ -32,6 +35,7 @@ var (
 	gpDeleteRenderbuffersEXT      uintptr
 	gpDeleteShader                uintptr
 	gpDeleteTextures              uintptr
+	gpDepthFunc                   uintptr
 	gpDisable                     uintptr
 	gpDisableVertexAttribArray    uintptr
 	gpDrawElements                uintptr
@@ -58,6 +62,7 @@ var (
 	gpGetProgramiv                uintptr
 	gpGetShaderInfoLog            uintptr
 	gpGetShaderiv                 uintptr
+	gpGetString                   uintptr
 	gpGetTransformFeedbacki64_v   uintptr
 	gpGetTransformFeedbacki_v     uintptr
 	gpGetUniformLocation          uintptr
@@ -149,6 +154,10 @@ func Clear(mask uint32) {
 	syscall.Syscall(gpClear, 1, uintptr(mask), 0, 0)
 }
 
+func ClearColor(red float32, green float32, blue float32, alpha float32) {
+	syscall.Syscall6(gpClearColor, 4, uintptr(math.Float32bits(red)), uintptr(math.Float32bits(green)), uintptr(math.Float32bits(blue)), uintptr(math.Float32bits(alpha)), 0, 0)
+}
+
 func ColorMask(red bool, green bool, blue bool, alpha bool) {
 	syscall.Syscall6(gpColorMask, 4, boolToUintptr(red), boolToUintptr(green), boolToUintptr(blue), boolToUintptr(alpha), 0, 0)
 }
@@ -157,6 +166,14 @@ func CompileShader(shader uint32) {
 	syscall.Syscall(gpCompileShader, 1, uintptr(shader), 0, 0)
 }
 
+func CompressedTexImage2D(target uint32, level int32, internalformat uint32, width int32, height int32, border int32, imageSize int32, data unsafe.Pointer) {
+	syscall.Syscall9(gpCompressedTexImage2D, 8, uintptr(target), uintptr(level), uintptr(internalformat), uintptr(width), uintptr(height), uintptr(border), uintptr(imageSize), uintptr(data), 0)
+}
+
+func CopyTexSubImage2D(target uint32, level int32, xoffset int32, yoffset int32, x int32, y int32, width int32, height int32) {
+	syscall.Syscall9(gpCopyTexSubImage2D, 8, uintptr(target), uintptr(level), uintptr(xoffset), uintptr(yoffset), uintptr(x), uintptr(y), uintptr(width), uintptr(height), 0)
+}
+
 func CreateProgram() uint32 {
 	ret, _, _ := syscall.Syscall(gpCreateProgram, 0, 0, 0, 0)
 	return (uint32)(ret)
@@ -191,6 +208,10 @@ func DeleteTextures(n int32, textures *uint32) {
 	syscall.Syscall(gpDeleteTextures, 2, uintptr(n), uintptr(unsafe.Pointer(textures)), 0)
 }
 
+func DepthFunc(xfunc uint32) {
+	syscall.Syscall(gpDepthFunc, 1, uintptr(xfunc), 0, 0)
+}
+
 func Disable(cap uint32) {
 	syscall.Syscall(gpDisable, 1, uintptr(cap), 0, 0)
 }
@@ -291,6 +312,11 @@ func GetShaderiv(shader uint32, pname uint32, params *int32) {
 	syscall.Syscall(gpGetShaderiv, 3, uintptr(shader), uintptr(pname), uintptr(unsafe.Pointer(params)))
 }
 
+func GetString(name uint32) *uint8 {
+	ret, _, _ := syscall.Syscall(gpGetString, 1, uintptr(name), 0, 0)
+	return *(**uint8)(unsafe.Pointer(&ret))
+}
+
 func GetTransformFeedbacki64_v(xfb uint32, pname uint32, index uint32, param *int64) {
 	syscall.Syscall6(gpGetTransformFeedbacki64_v, 4, uintptr(xfb), uintptr(pname), uintptr(index), uintptr(unsafe.Pointer(param)), 0, 0)
 }
@@ -469,6 +495,10 @@ func InitWithProcAddrFunc(getProcAddr func(name string) uintptr) error {
 	if gpClear == 0 {
 		return errors.New("glClear")
 	}
+	gpClearColor = getProcAddr("glClearColor")
+	if gpClearColor == 0 {
+		return errors.New("glClearColor")
+	}
 	gpColorMask = getProcAddr("glColorMask")
 	if gpColorMask == 0 {
 		return errors.New("glColorMask")
@@ -477,6 +507,14 @@ func InitWithProcAddrFunc(getProcAddr func(name string) uintptr) error {
 	if gpCompileShader == 0 {
 		return errors.New("glCompileShader")
 	}
+	gpCompressedTexImage2D = getProcAddr("glCompressedTexImage2D")
+	if gpCompressedTexImage2D == 0 {
+		return errors.New("glCompressedTexImage2D")
+	}
+	gpCopyTexSubImage2D = getProcAddr("glCopyTexSubImage2D")
+	if gpCopyTexSubImage2D == 0 {
+		return errors.New("glCopyTexSubImage2D")
+	}
 	gpCreateProgram = getProcAddr("glCreateProgram")
 	if gpCreateProgram == 0 {
 		return errors.New("glCreateProgram")
@@ -503,6 +541,10 @@ func InitWithProcAddrFunc(getProcAddr func(name string) uintptr) error {
 	if gpDeleteTextures == 0 {
 		return errors.New("glDeleteTextures")
 	}
+	gpDepthFunc = getProcAddr("glDepthFunc")
+	if gpDepthFunc == 0 {
+		return errors.New("glDepthFunc")
+	}
 	gpDisable = getProcAddr("glDisable")
 	if gpDisable == 0 {
 		return errors.New("glDisable")
@@ -574,6 +616,10 @@ func InitWithProcAddrFunc(getProcAddr func(name string) uintptr) error {
 	if gpGetShaderiv == 0 {
 		return errors.New("glGetShaderiv")
 	}
+	gpGetString = getProcAddr("glGetString")
+	if gpGetString == 0 {
+		return errors.New("glGetString")
+	}
 	gpGetTransformFeedbacki64_v = getProcAddr("glGetTransformFeedbacki64_v")
 	gpGetTransformFeedbacki_v = getProcAddr("glGetTransformFeedbacki_v")
 	gpGetUniformLocation = getProcAddr("glGetUniformLocation")
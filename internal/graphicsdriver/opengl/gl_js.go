@@ -31,8 +31,11 @@ type gl struct {
 	bufferSubData            js.Value
 	checkFramebufferStatus   js.Value
 	clear                    js.Value
+	clearColor               js.Value
 	colorMask                js.Value
 	compileShader            js.Value
+	compressedTexImage2D     js.Value
+	copyTexSubImage2D        js.Value
 	createBuffer             js.Value
 	createFramebuffer        js.Value
 	createProgram            js.Value
@@ -45,6 +48,7 @@ type gl struct {
 	deleteRenderbuffer       js.Value
 	deleteShader             js.Value
 	deleteTexture            js.Value
+	depthFunc                js.Value
 	disable                  js.Value
 	disableVertexAttribArray js.Value
 	drawElements             js.Value
@@ -109,8 +113,11 @@ func (c *context) newGL(v js.Value) *gl {
 		bufferSubData:            v.Get("bufferSubData").Call("bind", v),
 		checkFramebufferStatus:   v.Get("checkFramebufferStatus").Call("bind", v),
 		clear:                    v.Get("clear").Call("bind", v),
+		clearColor:               v.Get("clearColor").Call("bind", v),
 		colorMask:                v.Get("colorMask").Call("bind", v),
 		compileShader:            v.Get("compileShader").Call("bind", v),
+		compressedTexImage2D:     v.Get("compressedTexImage2D").Call("bind", v),
+		copyTexSubImage2D:        v.Get("copyTexSubImage2D").Call("bind", v),
 		createBuffer:             v.Get("createBuffer").Call("bind", v),
 		createFramebuffer:        v.Get("createFramebuffer").Call("bind", v),
 		createProgram:            v.Get("createProgram").Call("bind", v),
@@ -123,6 +130,7 @@ func (c *context) newGL(v js.Value) *gl {
 		deleteRenderbuffer:       v.Get("deleteRenderbuffer").Call("bind", v),
 		deleteShader:             v.Get("deleteShader").Call("bind", v),
 		deleteTexture:            v.Get("deleteTexture").Call("bind", v),
+		depthFunc:                v.Get("depthFunc").Call("bind", v),
 		disable:                  v.Get("disable").Call("bind", v),
 		disableVertexAttribArray: v.Get("disableVertexAttribArray").Call("bind", v),
 		drawElements:             v.Get("drawElements").Call("bind", v),
@@ -131,6 +139,7 @@ func (c *context) newGL(v js.Value) *gl {
 		framebufferRenderbuffer:  v.Get("framebufferRenderbuffer").Call("bind", v),
 		framebufferTexture2D:     v.Get("framebufferTexture2D").Call("bind", v),
 		flush:                    v.Get("flush").Call("bind", v),
+		getExtension:             v.Get("getExtension").Call("bind", v),
 		getParameter:             v.Get("getParameter").Call("bind", v),
 		getProgramInfoLog:        v.Get("getProgramInfoLog").Call("bind", v),
 		getProgramParameter:      v.Get("getProgramParameter").Call("bind", v),
@@ -169,9 +178,7 @@ func (c *context) newGL(v js.Value) *gl {
 		viewport:                 v.Get("viewport").Call("bind", v),
 	}
 	if c.usesWebGL2() {
-		g.getExtension = v.Get("getBufferSubData").Call("bind", v)
-	} else {
-		g.getExtension = v.Get("getExtension").Call("bind", v)
+		g.getBufferSubData = v.Get("getBufferSubData").Call("bind", v)
 	}
 	return g
 }
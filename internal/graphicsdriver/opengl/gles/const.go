@@ -29,7 +29,13 @@ const (
 	BLEND                = 0x0BE2
 	CLAMP_TO_EDGE        = 0x812F
 	COLOR_ATTACHMENT0    = 0x8CE0
+	COLOR_BUFFER_BIT     = 0x4000
 	COMPILE_STATUS       = 0x8B81
+	DEPTH24_STENCIL8     = 0x88F0
+	DEPTH_ATTACHMENT     = 0x8D00
+	DEPTH_BUFFER_BIT     = 0x0100
+	DEPTH_STENCIL        = 0x84F9
+	DEPTH_TEST           = 0x0B71
 	DYNAMIC_DRAW         = 0x88E8
 	ELEMENT_ARRAY_BUFFER = 0x8893
 	FALSE                = 0
@@ -42,6 +48,7 @@ const (
 	INFO_LOG_LENGTH      = 0x8B84
 	INVERT               = 0x150A
 	KEEP                 = 0x1E00
+	LEQUAL               = 0x0203
 	LINK_STATUS          = 0x8B82
 	MAX_TEXTURE_SIZE     = 0x0D33
 	NEAREST              = 0x2600
@@ -73,3 +80,19 @@ const (
 	VERTEX_SHADER        = 0x8B31
 	WRITE_ONLY           = 0x88B9
 )
+
+const (
+	DEPTH_STENCIL_ATTACHMENT = 0x821A
+)
+
+const (
+	COMPRESSED_TEXTURE_FORMATS     = 0x86A3
+	NUM_COMPRESSED_TEXTURE_FORMATS = 0x86A2
+)
+
+const (
+	RENDERER                 = 0x1F01
+	SHADING_LANGUAGE_VERSION = 0x8B8C
+	VENDOR                   = 0x1F00
+	VERSION                  = 0x1F02
+)
@@ -99,6 +99,10 @@ func (DefaultContext) Clear(mask uint32) {
 	C.glClear(C.GLbitfield(mask))
 }
 
+func (DefaultContext) ClearColor(red, green, blue, alpha float32) {
+	C.glClearColor(C.GLfloat(red), C.GLfloat(green), C.GLfloat(blue), C.GLfloat(alpha))
+}
+
 func (DefaultContext) ColorMask(red, green, blue, alpha bool) {
 	C.glColorMask(glBool(red), glBool(green), glBool(blue), glBool(alpha))
 }
@@ -107,6 +111,14 @@ func (DefaultContext) CompileShader(shader uint32) {
 	C.glCompileShader(C.GLuint(shader))
 }
 
+func (DefaultContext) CompressedTexImage2D(target uint32, level int32, internalformat uint32, width int32, height int32, data []byte) {
+	C.glCompressedTexImage2D(C.GLenum(target), C.GLint(level), C.GLenum(internalformat), C.GLsizei(width), C.GLsizei(height), 0 /* border */, C.GLsizei(len(data)), unsafe.Pointer(&data[0]))
+}
+
+func (DefaultContext) CopyTexSubImage2D(target uint32, level int32, xoffset int32, yoffset int32, x int32, y int32, width int32, height int32) {
+	C.glCopyTexSubImage2D(C.GLenum(target), C.GLint(level), C.GLint(xoffset), C.GLint(yoffset), C.GLint(x), C.GLint(y), C.GLsizei(width), C.GLsizei(height))
+}
+
 func (DefaultContext) CreateProgram() uint32 {
 	return uint32(C.glCreateProgram())
 }
@@ -139,6 +151,10 @@ func (DefaultContext) DeleteTextures(textures []uint32) {
 	C.glDeleteTextures(C.GLsizei(len(textures)), (*C.GLuint)(unsafe.Pointer(&textures[0])))
 }
 
+func (DefaultContext) DepthFunc(func_ uint32) {
+	C.glDepthFunc(C.GLenum(func_))
+}
+
 func (DefaultContext) Disable(cap uint32) {
 	C.glDisable(C.GLenum(cap))
 }
@@ -244,6 +260,10 @@ func (DefaultContext) GetShaderPrecisionFormat(shadertype uint32, precisiontype
 	return int(r[0]), int(r[1]), int(p)
 }
 
+func (DefaultContext) GetString(name uint32) string {
+	return C.GoString((*C.char)(unsafe.Pointer(C.glGetString(C.GLenum(name)))))
+}
+
 func (DefaultContext) GetUniformLocation(program uint32, name string) int32 {
 	s, free := cString(name)
 	defer free()
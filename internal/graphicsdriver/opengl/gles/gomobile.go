@@ -91,6 +91,10 @@ func (g *GomobileContext) Clear(mask uint32) {
 	g.ctx.Clear(gl.Enum(mask))
 }
 
+func (g *GomobileContext) ClearColor(red, green, blue, alpha float32) {
+	g.ctx.ClearColor(red, green, blue, alpha)
+}
+
 func (g *GomobileContext) ColorMask(red, green, blue, alpha bool) {
 	g.ctx.ColorMask(red, green, blue, alpha)
 }
@@ -99,6 +103,14 @@ func (g *GomobileContext) CompileShader(shader uint32) {
 	g.ctx.CompileShader(gl.Shader{Value: shader})
 }
 
+func (g *GomobileContext) CompressedTexImage2D(target uint32, level int32, internalformat uint32, width int32, height int32, data []byte) {
+	g.ctx.CompressedTexImage2D(gl.Enum(target), int(level), gl.Enum(internalformat), int(width), int(height), 0, data)
+}
+
+func (g *GomobileContext) CopyTexSubImage2D(target uint32, level int32, xoffset int32, yoffset int32, x int32, y int32, width int32, height int32) {
+	g.ctx.CopyTexSubImage2D(gl.Enum(target), int(level), int(xoffset), int(yoffset), int(x), int(y), int(width), int(height))
+}
+
 func (g *GomobileContext) CreateProgram() uint32 {
 	return g.ctx.CreateProgram().Value
 }
@@ -139,6 +151,10 @@ func (g *GomobileContext) DeleteTextures(textures []uint32) {
 	}
 }
 
+func (g *GomobileContext) DepthFunc(func_ uint32) {
+	g.ctx.DepthFunc(gl.Enum(func_))
+}
+
 func (g *GomobileContext) Disable(cap uint32) {
 	g.ctx.Disable(gl.Enum(cap))
 }
@@ -231,6 +247,10 @@ func (g *GomobileContext) GetShaderPrecisionFormat(shadertype uint32, precisiont
 	return g.ctx.GetShaderPrecisionFormat(gl.Enum(shadertype), gl.Enum(precisiontype))
 }
 
+func (g *GomobileContext) GetString(name uint32) string {
+	return g.ctx.GetString(gl.Enum(name))
+}
+
 func (g *GomobileContext) GetUniformLocation(program uint32, name string) int32 {
 	return g.ctx.GetUniformLocation(gmProgram(program), name).Value
 }
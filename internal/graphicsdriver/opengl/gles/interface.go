@@ -30,8 +30,11 @@ type Context interface {
 	BufferSubData(target uint32, offset int, data []byte)
 	CheckFramebufferStatus(target uint32) uint32
 	Clear(mask uint32)
+	ClearColor(red, green, blue, alpha float32)
 	ColorMask(red, green, blue, alpha bool)
 	CompileShader(shader uint32)
+	CompressedTexImage2D(target uint32, level int32, internalformat uint32, width int32, height int32, data []byte)
+	CopyTexSubImage2D(target uint32, level int32, xoffset int32, yoffset int32, x int32, y int32, width int32, height int32)
 	CreateProgram() uint32
 	CreateShader(xtype uint32) uint32
 	DeleteBuffers(buffers []uint32)
@@ -40,6 +43,7 @@ type Context interface {
 	DeleteRenderbuffers(renderbuffer []uint32)
 	DeleteShader(shader uint32)
 	DeleteTextures(textures []uint32)
+	DepthFunc(func_ uint32)
 	Disable(cap uint32)
 	DisableVertexAttribArray(index uint32)
 	DrawElements(mode uint32, count int32, xtype uint32, offset int)
@@ -59,6 +63,7 @@ type Context interface {
 	GetShaderiv(dst []int32, shader uint32, pname uint32)
 	GetShaderInfoLog(shader uint32) string
 	GetShaderPrecisionFormat(shadertype uint32, precisiontype uint32) (rangeLow, rangeHigh, precision int)
+	GetString(name uint32) string
 	GetUniformLocation(program uint32, name string) int32
 	IsFramebuffer(framebuffer uint32) bool
 	IsProgram(program uint32) bool
@@ -51,6 +51,9 @@ type Graphics struct {
 
 	uniformVars []uniformVariable
 
+	// verticesWithoutDepth is a buffer to hold the vertices without depth values.
+	verticesWithoutDepth []float32
+
 	// activatedTextures is a set of activated textures.
 	// textureNative cannot be a map key unfortunately.
 	activatedTextures []activatedTexture
@@ -115,6 +118,69 @@ func (g *Graphics) NewImage(width, height int) (graphicsdriver.Image, error) {
 	return i, nil
 }
 
+// NewCompressedImage creates an image from the given compressed texture data.
+// A compressed image can be used only as a rendering source.
+func (g *Graphics) NewCompressedImage(width, height int, format graphicsdriver.CompressedTextureFormat, data []byte) (graphicsdriver.Image, error) {
+	if !g.context.isCompressedTextureFormatSupported(format) {
+		return nil, fmt.Errorf("opengl: the compressed texture format %s is not supported", format)
+	}
+	i := &Image{
+		id:         g.genNextImageID(),
+		graphics:   g,
+		width:      width,
+		height:     height,
+		compressed: true,
+	}
+	g.checkSize(width, height)
+	t, err := g.context.newCompressedTexture(width, height, compressedTextureInternalFormat(format), data)
+	if err != nil {
+		return nil, err
+	}
+	i.texture = t
+	g.addImage(i)
+	return i, nil
+}
+
+// ValidateNativeTexture reports an error if the given OpenGL texture cannot be wrapped as an image of the given size.
+// OpenGL ES cannot query the size of a texture, so only the texture name and the maximum size are checked.
+func (g *Graphics) ValidateNativeTexture(width, height int, texture graphicsdriver.NativeTexture) error {
+	if texture.Kind != graphicsdriver.NativeTextureKindOpenGL {
+		return fmt.Errorf("opengl: the native texture kind %s is not supported", texture.Kind)
+	}
+	if m := g.context.getMaxTextureSize(); width > m || height > m {
+		return fmt.Errorf("opengl: the native texture size (%d, %d) must be less than or equal to %d", width, height, m)
+	}
+	return g.context.validateNativeTexture(texture.Handle)
+}
+
+// NewNativeImage creates an image wrapping the given OpenGL texture.
+// A native image can be used only as a rendering source, and its texture is not deleted at Dispose.
+func (g *Graphics) NewNativeImage(width, height int, texture graphicsdriver.NativeTexture) (graphicsdriver.Image, error) {
+	if texture.Kind != graphicsdriver.NativeTextureKindOpenGL {
+		return nil, fmt.Errorf("opengl: the native texture kind %s is not supported", texture.Kind)
+	}
+	g.checkSize(width, height)
+	t, err := g.context.textureFromNative(texture.Handle)
+	if err != nil {
+		return nil, err
+	}
+	i := &Image{
+		id:       g.genNextImageID(),
+		graphics: g,
+		texture:  t,
+		width:    width,
+		height:   height,
+		native:   true,
+	}
+	g.addImage(i)
+	return i, nil
+}
+
+// IsCompressedTextureFormatSupported reports whether the given compressed texture format is available.
+func (g *Graphics) IsCompressedTextureFormatSupported(format graphicsdriver.CompressedTextureFormat) bool {
+	return g.context.isCompressedTextureFormatSupported(format)
+}
+
 func (g *Graphics) NewScreenFramebufferImage(width, height int) (graphicsdriver.Image, error) {
 	g.checkSize(width, height)
 	i := &Image{
@@ -151,7 +217,20 @@ func (g *Graphics) Reset() error {
 	return g.state.reset(&g.context)
 }
 
-func (g *Graphics) SetVertices(vertices []float32, indices []uint16) {
+func (g *Graphics) SetVertices(vertices []float32, indices []uint16, depth bool) {
+	if !depth {
+		// Omit the depth values when no draw call uses them in order to reduce the size to upload.
+		g.verticesWithoutDepth = theArrayBufferLayout.omitDepth(g.verticesWithoutDepth[:0], vertices)
+		vertices = g.verticesWithoutDepth
+	}
+	if g.state.arrayBufferDepth != depth {
+		// The array buffer layout is enabled at the first useProgram after resetting.
+		if !g.state.lastProgram.equal(zeroProgram) {
+			theArrayBufferLayout.enable(&g.context, depth)
+		}
+		g.state.arrayBufferDepth = depth
+	}
+
 	// Note that the vertices passed to BufferSubData is not under GC management
 	// in opengl package due to unsafe-way.
 	// See BufferSubData in context_mobile.go.
@@ -171,7 +250,7 @@ func (g *Graphics) uniformVariableName(idx int) string {
 	return name
 }
 
-func (g *Graphics) DrawTriangles(dstID graphicsdriver.ImageID, srcIDs [graphics.ShaderImageNum]graphicsdriver.ImageID, offsets [graphics.ShaderImageNum - 1][2]float32, shaderID graphicsdriver.ShaderID, indexLen int, indexOffset int, mode graphicsdriver.CompositeMode, colorM graphicsdriver.ColorM, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, uniforms []graphicsdriver.Uniform, evenOdd bool) error {
+func (g *Graphics) DrawTriangles(dstID graphicsdriver.ImageID, srcIDs [graphics.ShaderImageNum]graphicsdriver.ImageID, offsets [graphics.ShaderImageNum - 1][2]float32, shaderID graphicsdriver.ShaderID, indexLen int, indexOffset int, mode graphicsdriver.CompositeMode, colorM graphicsdriver.ColorM, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, uniforms []graphicsdriver.Uniform, evenOdd bool, depthTest bool) error {
 	destination := g.images[dstID]
 
 	g.drawCalled = true
@@ -179,6 +258,13 @@ func (g *Graphics) DrawTriangles(dstID graphicsdriver.ImageID, srcIDs [graphics.
 	if err := destination.setViewport(); err != nil {
 		return err
 	}
+	// Ensure the depth-stencil buffer before setting the scissor, as allocating the buffer clears the depth with the
+	// whole region.
+	if evenOdd || depthTest {
+		if err := destination.ensureDepthStencilBuffer(); err != nil {
+			return err
+		}
+	}
 	g.context.scissor(
 		int(dstRegion.X),
 		int(dstRegion.Y),
@@ -193,8 +279,20 @@ func (g *Graphics) DrawTriangles(dstID graphicsdriver.ImageID, srcIDs [graphics.
 			useColorM: !colorM.IsIdentity(),
 			filter:    filter,
 			address:   address,
+			depthTest: depthTest,
 		}]
 
+		// The geometry matrix is column-major in GLSL.
+		geoMBody := []float32{1, 0, 0, 1}
+		geoMTranslation := []float32{0, 0}
+		colorScale := []float32{1, 1, 1, 1}
+		if len(uniforms) > 0 {
+			m := uniforms[graphics.DefaultShaderGeoMUniformVariableIndex].Float32s
+			geoMBody = []float32{m[0], m[2], m[1], m[3]}
+			geoMTranslation = []float32{m[4], m[5]}
+			colorScale = uniforms[graphics.DefaultShaderColorScaleUniformVariableIndex].Float32s
+		}
+
 		dw, dh := destination.framebufferSize()
 		g.uniformVars = append(g.uniformVars, uniformVariable{
 			name: "viewport_size",
@@ -202,6 +300,24 @@ func (g *Graphics) DrawTriangles(dstID graphicsdriver.ImageID, srcIDs [graphics.
 				Float32s: []float32{float32(dw), float32(dh)},
 			},
 			typ: shaderir.Type{Main: shaderir.Vec2},
+		}, uniformVariable{
+			name: "geo_matrix_body",
+			value: graphicsdriver.Uniform{
+				Float32s: geoMBody,
+			},
+			typ: shaderir.Type{Main: shaderir.Mat2},
+		}, uniformVariable{
+			name: "geo_matrix_translation",
+			value: graphicsdriver.Uniform{
+				Float32s: geoMTranslation,
+			},
+			typ: shaderir.Type{Main: shaderir.Vec2},
+		}, uniformVariable{
+			name: "color_scale",
+			value: graphicsdriver.Uniform{
+				Float32s: colorScale,
+			},
+			typ: shaderir.Type{Main: shaderir.Vec4},
 		}, uniformVariable{
 			name: "source_region",
 			value: graphicsdriver.Uniform{
@@ -356,10 +472,10 @@ func (g *Graphics) DrawTriangles(dstID graphicsdriver.ImageID, srcIDs [graphics.
 	}
 	g.uniformVars = g.uniformVars[:0]
 
+	if depthTest {
+		g.context.enableDepthTest()
+	}
 	if evenOdd {
-		if err := destination.ensureStencilBuffer(); err != nil {
-			return err
-		}
 		g.context.enableStencilTest()
 		g.context.beginStencilWithEvenOddRule()
 		g.context.drawElements(indexLen, indexOffset*2)
@@ -369,7 +485,45 @@ func (g *Graphics) DrawTriangles(dstID graphicsdriver.ImageID, srcIDs [graphics.
 	if evenOdd {
 		g.context.disableStencilTest()
 	}
+	if depthTest {
+		g.context.disableDepthTest()
+	}
+
+	return nil
+}
+
+func (g *Graphics) CopyPixels(dstID, srcID graphicsdriver.ImageID, dstX, dstY, srcX, srcY, width, height int) error {
+	dst := g.images[dstID]
+	src := g.images[srcID]
+	if dst.screen || dst.compressed {
+		panic("opengl: CopyPixels cannot copy pixels to the screen or a compressed texture")
+	}
+	// The framebuffer of the screen is upside down and a compressed texture cannot be bound to a framebuffer.
+	if src.screen || src.compressed {
+		panic("opengl: CopyPixels cannot copy pixels from the screen or a compressed texture")
+	}
+
+	if err := src.ensureFramebuffer(); err != nil {
+		return err
+	}
+	// The scissor test doesn't affect glCopyTexSubImage2D.
+	g.context.copyTexSubImage2D(dst.texture, src.framebuffer, dstX, dstY, srcX, srcY, width, height)
+	return nil
+}
+
+func (g *Graphics) FillRect(dstID graphicsdriver.ImageID, x, y, width, height int, red, green, blue, alpha float32) error {
+	dst := g.images[dstID]
+	// The framebuffer of the screen is upside down and the scissor region would have to be flipped.
+	if dst.screen || dst.compressed {
+		panic("opengl: FillRect cannot be called on the screen or a compressed texture")
+	}
 
+	if err := dst.setViewport(); err != nil {
+		return err
+	}
+	// glClear is limited to the scissor region.
+	g.context.scissor(x, y, width, height)
+	g.context.clearColor(red, green, blue, alpha)
 	return nil
 }
 
@@ -405,6 +559,33 @@ func (g *Graphics) HasHighPrecisionFloat() bool {
 	return g.context.hasHighPrecisionFloat()
 }
 
+func (g *Graphics) GraphicsLibrary() graphicsdriver.GraphicsLibrary {
+	return g.context.graphicsLibrary()
+}
+
+// InvalidateState forgets the cached OpenGL state, e.g., after other code used the same OpenGL context.
+// InvalidateState must be called on the rendering thread.
+func (g *Graphics) InvalidateState() {
+	g.context.invalidateState()
+	g.state.lastProgram = zeroProgram
+	for key := range g.state.lastUniforms {
+		delete(g.state.lastUniforms, key)
+	}
+	g.state.lastActiveTexture = 0
+
+	// SetVertices uploads the vertices to the bound buffers before the next useProgram binds them.
+	// Rebind Ebiten's buffers and the attribute layout now, as the other code might have bound its own buffers.
+	if !g.state.arrayBuffer.equal(zeroBuffer) {
+		g.context.bindArrayBuffer(g.state.arrayBuffer)
+		g.context.bindElementArrayBuffer(g.state.elementArrayBuffer)
+		theArrayBufferLayout.enable(&g.context, g.state.arrayBufferDepth)
+	}
+}
+
+func (g *Graphics) DriverInfo() graphicsdriver.DriverInfo {
+	return g.context.getDriverInfo()
+}
+
 func (g *Graphics) MaxImageSize() int {
 	return g.context.getMaxTextureSize()
 }
@@ -0,0 +1,39 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !android && !ios && !js
+// +build !android,!ios,!js
+
+package opengl
+
+import (
+	"sync/atomic"
+)
+
+// SetLinearBlendingEnabled makes textures and the screen framebuffer sRGB-encoded so that sampling decodes
+// colors to linear space and blending happens in linear space.
+//
+// SetLinearBlendingEnabled must be called before Initialize.
+func (g *Graphics) SetLinearBlendingEnabled(enabled bool) {
+	g.context.linearBlending = enabled
+}
+
+// IsLinearBlendingSupported reports whether linear blending works with the current OpenGL context.
+//
+// IsLinearBlendingSupported returns true before Initialize.
+//
+// IsLinearBlendingSupported is concurrent-safe.
+func (g *Graphics) IsLinearBlendingSupported() bool {
+	return atomic.LoadInt32(&g.context.linearBlendingUnsupported) == 0
+}
@@ -15,6 +15,8 @@
 package opengl
 
 import (
+	"errors"
+
 	"github.com/hajimehoshi/ebiten/v2/internal/graphics"
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
 )
@@ -23,11 +25,21 @@ type Image struct {
 	id          graphicsdriver.ImageID
 	graphics    *Graphics
 	texture     textureNative
-	stencil     renderbufferNative
 	framebuffer *framebuffer
 	width       int
 	height      int
 	screen      bool
+
+	// depthStencil is a packed depth-stencil renderbuffer, used both for the even-odd fill rule and depth testing.
+	// Separate depth and stencil attachments are not supported on many environments like OpenGL ES 2 and WebGL 1.
+	depthStencil renderbufferNative
+
+	// compressed indicates whether the texture is a compressed texture, which cannot be a render target.
+	compressed bool
+
+	// native indicates whether the texture is created outside of Ebiten.
+	// A native texture is not deleted at Dispose.
+	native bool
 }
 
 func (i *Image) ID() graphicsdriver.ImageID {
@@ -42,11 +54,11 @@ func (i *Image) Dispose() {
 	if i.framebuffer != nil {
 		i.framebuffer.delete(&i.graphics.context)
 	}
-	if !i.texture.equal(*new(textureNative)) {
+	if !i.texture.equal(*new(textureNative)) && !i.native {
 		i.graphics.context.deleteTexture(i.texture)
 	}
-	if !i.stencil.equal(*new(renderbufferNative)) {
-		i.graphics.context.deleteRenderbuffer(i.stencil)
+	if !i.depthStencil.equal(*new(renderbufferNative)) {
+		i.graphics.context.deleteRenderbuffer(i.depthStencil)
 	}
 
 	i.graphics.removeImage(i)
@@ -61,6 +73,9 @@ func (i *Image) setViewport() error {
 }
 
 func (i *Image) ReadPixels(buf []byte) error {
+	if i.compressed {
+		return errors.New("opengl: ReadPixels cannot be called on a compressed texture")
+	}
 	if err := i.ensureFramebuffer(); err != nil {
 		return err
 	}
@@ -76,6 +91,10 @@ func (i *Image) framebufferSize() (int, int) {
 		// Edge can't treat a bigger viewport than the drawing area (#71).
 		return i.width, i.height
 	}
+	if i.compressed || i.native {
+		// Compressed and native textures have their exact sizes.
+		return i.width, i.height
+	}
 	return graphics.InternalImageSize(i.width), graphics.InternalImageSize(i.height)
 }
 
@@ -97,8 +116,8 @@ func (i *Image) ensureFramebuffer() error {
 	return nil
 }
 
-func (i *Image) ensureStencilBuffer() error {
-	if !i.stencil.equal(*new(renderbufferNative)) {
+func (i *Image) ensureDepthStencilBuffer() error {
+	if !i.depthStencil.equal(*new(renderbufferNative)) {
 		return nil
 	}
 
@@ -106,22 +125,47 @@ func (i *Image) ensureStencilBuffer() error {
 		return err
 	}
 
-	r, err := i.graphics.context.newRenderbuffer(i.framebufferSize())
+	r, err := i.graphics.context.newDepthStencilRenderbuffer(i.framebufferSize())
 	if err != nil {
 		return err
 	}
-	i.stencil = r
+	i.depthStencil = r
 
-	if err := i.graphics.context.bindStencilBuffer(i.framebuffer.native, i.stencil); err != nil {
+	if err := i.graphics.context.bindDepthStencilBuffer(i.framebuffer.native, i.depthStencil); err != nil {
 		return err
 	}
+
+	// The content of a new renderbuffer is undefined.
+	// The stencil is cleared at every draw with the even-odd rule, but the depth is not.
+	i.clearDepth()
 	return nil
 }
 
+func (i *Image) ClearDepth() error {
+	if i.depthStencil.equal(*new(renderbufferNative)) {
+		return nil
+	}
+	if err := i.setViewport(); err != nil {
+		return err
+	}
+	i.clearDepth()
+	return nil
+}
+
+func (i *Image) clearDepth() {
+	// The scissor test affects glClear. Clear the whole buffer.
+	w, h := i.framebufferSize()
+	i.graphics.context.scissor(0, 0, w, h)
+	i.graphics.context.clearDepth()
+}
+
 func (i *Image) ReplacePixels(args []*graphicsdriver.ReplacePixelsArgs) {
 	if i.screen {
 		panic("opengl: ReplacePixels cannot be called on the screen, that doesn't have a texture")
 	}
+	if i.compressed {
+		panic("opengl: ReplacePixels cannot be called on a compressed texture")
+	}
 	if len(args) == 0 {
 		return
 	}
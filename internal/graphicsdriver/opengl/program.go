@@ -30,6 +30,10 @@ type arrayBufferLayoutPart struct {
 	// TODO: This struct should belong to a program and know it.
 	name string
 	num  int
+
+	// depth indicates whether the part is the depth value.
+	// The depth part is omitted when no draw call uses depth testing.
+	depth bool
 }
 
 // arrayBufferLayout is an array buffer layout.
@@ -67,14 +71,48 @@ func (a *arrayBufferLayout) newArrayBuffer(context *context) buffer {
 	return context.newArrayBuffer(a.totalBytes() * graphics.IndicesNum)
 }
 
-// enable starts using the array buffer.
-func (a *arrayBufferLayout) enable(context *context) {
-	for i := range a.parts {
-		context.enableVertexAttribArray(i)
+// totalBytesWithoutDepth returns the size in bytes for one element of the array buffer without the depth part.
+func (a *arrayBufferLayout) totalBytesWithoutDepth() int {
+	t := a.totalBytes()
+	for _, p := range a.parts {
+		if p.depth {
+			t -= floatSizeInBytes * p.num
+		}
 	}
+	return t
+}
+
+// omitDepth appends the vertices without the depth part to dst and returns the result.
+func (a *arrayBufferLayout) omitDepth(dst []float32, vertices []float32) []float32 {
+	n := a.totalBytes() / floatSizeInBytes
+	for i := 0; i < len(vertices); i += n {
+		offset := 0
+		for _, p := range a.parts {
+			if !p.depth {
+				dst = append(dst, vertices[i+offset:i+offset+p.num]...)
+			}
+			offset += p.num
+		}
+	}
+	return dst
+}
+
+// enable starts using the array buffer.
+//
+// If depth is false, the array buffer doesn't have the depth part and the attribute for the depth is disabled.
+// Then the attribute value is always 0.
+func (a *arrayBufferLayout) enable(context *context, depth bool) {
 	total := a.totalBytes()
+	if !depth {
+		total = a.totalBytesWithoutDepth()
+	}
 	offset := 0
 	for i, p := range a.parts {
+		if p.depth && !depth {
+			context.disableVertexAttribArray(i)
+			continue
+		}
+		context.enableVertexAttribArray(i)
 		context.vertexAttribPointer(i, p.num, total, offset)
 		offset += floatSizeInBytes * p.num
 	}
@@ -104,6 +142,11 @@ var theArrayBufferLayout = arrayBufferLayout{
 			name: "A2",
 			num:  4,
 		},
+		{
+			name:  "A3",
+			num:   1,
+			depth: true,
+		},
 	},
 }
 
@@ -118,6 +161,7 @@ type programKey struct {
 	useColorM bool
 	filter    graphicsdriver.Filter
 	address   graphicsdriver.Address
+	depthTest bool
 }
 
 // openGLState is a state for
@@ -134,6 +178,9 @@ type openGLState struct {
 	lastProgram       program
 	lastUniforms      map[string]graphicsdriver.Uniform
 	lastActiveTexture int
+
+	// arrayBufferDepth indicates whether the vertices in the array buffer have the depth values.
+	arrayBufferDepth bool
 }
 
 var (
@@ -193,26 +240,34 @@ func (s *openGLState) reset(context *context) error {
 				graphicsdriver.FilterLinear,
 				graphicsdriver.FilterScreen,
 			} {
-				shaderFragmentColorMatrixNative, err := context.newFragmentShader(fragmentShaderStr(c, f, a))
-				if err != nil {
-					panic(fmt.Sprintf("graphics: shader compiling error:\n%s", err))
-				}
-				defer context.deleteShader(shaderFragmentColorMatrixNative)
-
-				program, err := context.newProgram([]shader{
-					shaderVertexModelviewNative,
-					shaderFragmentColorMatrixNative,
-				}, theArrayBufferLayout.names())
-
-				if err != nil {
-					return err
+				for _, d := range []bool{false, true} {
+					// FilterScreen is used only for the screen, which never has a depth buffer.
+					if d && f == graphicsdriver.FilterScreen {
+						continue
+					}
+
+					shaderFragmentColorMatrixNative, err := context.newFragmentShader(fragmentShaderStr(c, f, a, d))
+					if err != nil {
+						panic(fmt.Sprintf("graphics: shader compiling error:\n%s", err))
+					}
+					defer context.deleteShader(shaderFragmentColorMatrixNative)
+
+					program, err := context.newProgram([]shader{
+						shaderVertexModelviewNative,
+						shaderFragmentColorMatrixNative,
+					}, theArrayBufferLayout.names())
+
+					if err != nil {
+						return err
+					}
+
+					s.programs[programKey{
+						useColorM: c,
+						filter:    f,
+						address:   a,
+						depthTest: d,
+					}] = program
 				}
-
-				s.programs[programKey{
-					useColorM: c,
-					filter:    f,
-					address:   a,
-				}] = program
 			}
 		}
 	}
@@ -268,7 +323,7 @@ func (g *Graphics) useProgram(program program, uniforms []uniformVariable, textu
 	if !g.state.lastProgram.equal(program) {
 		g.context.useProgram(program)
 		if g.state.lastProgram.equal(zeroProgram) {
-			theArrayBufferLayout.enable(&g.context)
+			theArrayBufferLayout.enable(&g.context, g.state.arrayBufferDepth)
 			g.context.bindArrayBuffer(g.state.arrayBuffer)
 			g.context.bindElementArrayBuffer(g.state.elementArrayBuffer)
 		}
@@ -15,7 +15,10 @@
 package hooks
 
 import (
+	"fmt"
 	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
 )
 
 var m sync.Mutex
@@ -42,6 +45,53 @@ func RunBeforeUpdateHooks() error {
 	return nil
 }
 
+// TickStateHook is a pair of functions to save and restore a package's state of a tick, e.g., for rollback netcode.
+type TickStateHook struct {
+	Save    func() ([]byte, error)
+	Restore func(state []byte) error
+}
+
+var tickStateHooks []TickStateHook
+
+// AppendTickStateHook appends a hook to save and restore a state of a tick.
+func AppendTickStateHook(h TickStateHook) {
+	m.Lock()
+	tickStateHooks = append(tickStateHooks, h)
+	m.Unlock()
+}
+
+// SaveTickStates returns the states of the hooks in the order of the hooks.
+func SaveTickStates() ([][]byte, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	states := make([][]byte, 0, len(tickStateHooks))
+	for _, h := range tickStateHooks {
+		s, err := h.Save()
+		if err != nil {
+			return nil, err
+		}
+		states = append(states, s)
+	}
+	return states, nil
+}
+
+// RestoreTickStates restores the states returned by SaveTickStates.
+func RestoreTickStates(states [][]byte) error {
+	m.Lock()
+	defer m.Unlock()
+
+	if len(states) != len(tickStateHooks) {
+		return fmt.Errorf("hooks: the number of the tick states must be %d but %d", len(tickStateHooks), len(states))
+	}
+	for i, h := range tickStateHooks {
+		if err := h.Restore(states[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 var (
 	audioSuspended bool
 	onSuspendAudio func() error
@@ -85,3 +135,24 @@ func ResumeAudio() error {
 	}
 	return nil
 }
+
+var onNewImageFromNativeTexture func(texture graphicsdriver.NativeTexture, width, height int) interface{}
+
+// OnNewImageFromNativeTexture sets the function to create an *ebiten.Image from a native texture.
+// This is set by package ebiten, as other packages cannot construct an *ebiten.Image by themselves.
+func OnNewImageFromNativeTexture(f func(texture graphicsdriver.NativeTexture, width, height int) interface{}) {
+	m.Lock()
+	onNewImageFromNativeTexture = f
+	m.Unlock()
+}
+
+// NewImageFromNativeTexture returns an *ebiten.Image wrapping the given native texture as interface{}.
+func NewImageFromNativeTexture(texture graphicsdriver.NativeTexture, width, height int) interface{} {
+	m.Lock()
+	f := onNewImageFromNativeTexture
+	m.Unlock()
+	if f == nil {
+		panic("hooks: OnNewImageFromNativeTexture must be called before NewImageFromNativeTexture")
+	}
+	return f(texture, width, height)
+}
@@ -0,0 +1,33 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsutil
+
+import (
+	"syscall/js"
+)
+
+var offscreenCanvas js.Value
+
+// SetOffscreenCanvas sets the OffscreenCanvas transferred from the main thread.
+// This is used when Ebiten runs in a Web Worker.
+func SetOffscreenCanvas(canvas js.Value) {
+	offscreenCanvas = canvas
+}
+
+// OffscreenCanvas returns the OffscreenCanvas set by SetOffscreenCanvas.
+// OffscreenCanvas returns undefined when SetOffscreenCanvas has not been called.
+func OffscreenCanvas() js.Value {
+	return offscreenCanvas
+}
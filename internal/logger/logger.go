@@ -0,0 +1,109 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package logger routes Ebiten's internal log messages to a logger set by the user.
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/debug"
+)
+
+// Level is the severity of a log message.
+//
+// The values are the same as log/slog's levels so that a Level can be converted to slog.Level directly.
+type Level int
+
+const (
+	LevelDebug Level = -4
+	LevelInfo  Level = 0
+	LevelWarn  Level = 4
+	LevelError Level = 8
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelWarn:
+		return "WARN"
+	case LevelError:
+		return "ERROR"
+	}
+	return fmt.Sprintf("Level(%d)", l)
+}
+
+// Logger receives log messages.
+//
+// keysAndValues is a list of alternating keys and values. Keys are strings.
+type Logger interface {
+	Log(level Level, message string, keysAndValues ...interface{})
+}
+
+type loggerHolder struct {
+	logger Logger
+}
+
+var theLogger atomic.Value
+
+// SetLogger sets the logger. A nil logger discards messages.
+//
+// SetLogger is concurrent-safe.
+func SetLogger(logger Logger) {
+	theLogger.Store(loggerHolder{logger: logger})
+}
+
+// Log sends a message to the current logger.
+//
+// When no logger is set, the message is printed only with the ebitendebug build tag.
+//
+// Log is concurrent-safe.
+func Log(level Level, message string, keysAndValues ...interface{}) {
+	h, _ := theLogger.Load().(loggerHolder)
+	if h.logger != nil {
+		h.logger.Log(level, message, keysAndValues...)
+		return
+	}
+	if debug.IsDebug {
+		debug.Logf("%s\n", format(level, message, keysAndValues))
+	}
+}
+
+func format(level Level, message string, keysAndValues []interface{}) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s", level, message)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		if i+1 < len(keysAndValues) {
+			fmt.Fprintf(&b, " %v=%v", keysAndValues[i], keysAndValues[i+1])
+		} else {
+			fmt.Fprintf(&b, " %v", keysAndValues[i])
+		}
+	}
+	return b.String()
+}
+
+// Info logs a message at LevelInfo.
+func Info(message string, keysAndValues ...interface{}) {
+	Log(LevelInfo, message, keysAndValues...)
+}
+
+// Warn logs a message at LevelWarn.
+func Warn(message string, keysAndValues ...interface{}) {
+	Log(LevelWarn, message, keysAndValues...)
+}
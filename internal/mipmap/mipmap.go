@@ -16,6 +16,7 @@ package mipmap
 
 import (
 	"fmt"
+	"io"
 	"math"
 
 	"github.com/hajimehoshi/ebiten/v2/internal/affine"
@@ -39,6 +40,7 @@ type Mipmap struct {
 	width    int
 	height   int
 	volatile bool
+	native   bool
 	orig     *buffered.Image
 	imgs     map[int]*buffered.Image
 }
@@ -59,6 +61,35 @@ func NewScreenFramebufferMipmap(width, height int) *Mipmap {
 	}
 }
 
+// NewFromCompressedTexture creates a mipmap whose level 0 image is a compressed texture.
+//
+// The mipmap images of higher levels are regular images rendered from the compressed texture.
+func NewFromCompressedTexture(width, height int, format graphicsdriver.CompressedTextureFormat, data []byte) *Mipmap {
+	return &Mipmap{
+		width:  width,
+		height: height,
+		orig:   buffered.NewCompressedImage(width, height, format, data),
+	}
+}
+
+// NewFromNativeTexture creates a mipmap whose level 0 image wraps a native texture.
+//
+// As the content of a native texture can be changed outside of Ebiten, the mipmap images of higher levels are not
+// created.
+func NewFromNativeTexture(width, height int, texture graphicsdriver.NativeTexture) *Mipmap {
+	return &Mipmap{
+		width:  width,
+		height: height,
+		native: true,
+		orig:   buffered.NewNativeImage(width, height, texture),
+	}
+}
+
+// IsCompressedTextureFormatSupported reports whether the given compressed texture format is supported.
+func IsCompressedTextureFormatSupported(format graphicsdriver.CompressedTextureFormat) bool {
+	return buffered.IsCompressedTextureFormatSupported(format)
+}
+
 func (m *Mipmap) SetIndependent(independent bool) {
 	m.orig.SetIndependent(independent)
 }
@@ -75,8 +106,8 @@ func (m *Mipmap) SetVolatile(volatile bool) {
 	m.orig.SetVolatile(volatile)
 }
 
-func (m *Mipmap) DumpScreenshot(name string, blackbg bool) error {
-	return m.orig.DumpScreenshot(name, blackbg)
+func (m *Mipmap) DumpHistory(w io.Writer) error {
+	return m.orig.DumpHistory(w)
 }
 
 func (m *Mipmap) ReplacePixels(pix []byte, x, y, width, height int) error {
@@ -87,18 +118,38 @@ func (m *Mipmap) ReplacePixels(pix []byte, x, y, width, height int) error {
 	return nil
 }
 
+// CopyPixels copies the region (srcX, srcY)-(srcX+width, srcY+height) of src to (dstX, dstY) of the mipmap as it
+// is.
+func (m *Mipmap) CopyPixels(src *Mipmap, dstX, dstY, srcX, srcY, width, height int) {
+	m.orig.CopyPixels(src.orig, dstX, dstY, srcX, srcY, width, height)
+	m.disposeMipmaps()
+}
+
+// FillRect replaces the pixels of the region (x, y)-(x+width, y+height) of the mipmap with the given premultiplied
+// color.
+func (m *Mipmap) FillRect(x, y, width, height int, red, green, blue, alpha float32) {
+	m.orig.FillRect(x, y, width, height, red, green, blue, alpha)
+	m.disposeMipmaps()
+}
+
 func (m *Mipmap) Pixels(x, y, width, height int) ([]byte, error) {
 	return m.orig.Pixels(x, y, width, height)
 }
 
-func (m *Mipmap) DrawTriangles(srcs [graphics.ShaderImageNum]*Mipmap, vertices []float32, indices []uint16, colorm affine.ColorM, mode graphicsdriver.CompositeMode, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, subimageOffsets [graphics.ShaderImageNum - 1][2]float32, shader *Shader, uniforms []graphicsdriver.Uniform, evenOdd bool, canSkipMipmap bool) {
+func (m *Mipmap) DrawTriangles(srcs [graphics.ShaderImageNum]*Mipmap, vertices []float32, indices []uint16, colorm affine.ColorM, mode graphicsdriver.CompositeMode, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, subimageOffsets [graphics.ShaderImageNum - 1][2]float32, shader *Shader, uniforms []graphicsdriver.Uniform, evenOdd bool, depthTest bool, canSkipMipmap bool) {
 	if len(indices) == 0 {
 		return
 	}
 
 	level := 0
 	// TODO: Do we need to check all the sources' states of being volatile?
-	if !canSkipMipmap && srcs[0] != nil && !srcs[0].volatile && filter != graphicsdriver.FilterScreen {
+	if !canSkipMipmap && srcs[0] != nil && !srcs[0].volatile && !srcs[0].native && filter != graphicsdriver.FilterScreen {
+		// The default shader with the uniform variables transforms the destination positions on GPU.
+		// Only the linear part matters to the distances.
+		var geoM []float32
+		if shader == nil && len(uniforms) > 0 {
+			geoM = uniforms[graphics.DefaultShaderGeoMUniformVariableIndex].Float32s
+		}
 		level = math.MaxInt32
 		for i := 0; i < len(indices)/3; i++ {
 			const n = graphics.VertexFloatNum
@@ -114,6 +165,11 @@ func (m *Mipmap) DrawTriangles(srcs [graphics.ShaderImageNum]*Mipmap, vertices [
 			dy2 := vertices[n*indices[3*i+2]+1]
 			sx2 := vertices[n*indices[3*i+2]+2]
 			sy2 := vertices[n*indices[3*i+2]+3]
+			if geoM != nil {
+				dx0, dy0 = geoM[0]*dx0+geoM[1]*dy0, geoM[2]*dx0+geoM[3]*dy0
+				dx1, dy1 = geoM[0]*dx1+geoM[1]*dy1, geoM[2]*dx1+geoM[3]*dy1
+				dx2, dy2 = geoM[0]*dx2+geoM[1]*dy2, geoM[2]*dx2+geoM[3]*dy2
+			}
 			if l := mipmapLevelFromDistance(dx0, dy0, dx1, dy1, sx0, sy0, sx1, sy1, filter); level > l {
 				level = l
 			}
@@ -154,10 +210,15 @@ func (m *Mipmap) DrawTriangles(srcs [graphics.ShaderImageNum]*Mipmap, vertices [
 		imgs[i] = src.orig
 	}
 
-	m.orig.DrawTriangles(imgs, vertices, indices, colorm, mode, filter, address, dstRegion, srcRegion, subimageOffsets, s, uniforms, evenOdd)
+	m.orig.DrawTriangles(imgs, vertices, indices, colorm, mode, filter, address, dstRegion, srcRegion, subimageOffsets, s, uniforms, evenOdd, depthTest)
 	m.disposeMipmaps()
 }
 
+// ClearDepth resets the depth buffer of the image.
+func (m *Mipmap) ClearDepth() {
+	m.orig.ClearDepth()
+}
+
 func (m *Mipmap) setImg(level int, img *buffered.Image) {
 	if m.imgs == nil {
 		m.imgs = map[int]*buffered.Image{}
@@ -223,7 +284,7 @@ func (m *Mipmap) level(level int) *buffered.Image {
 		Width:  float32(w2),
 		Height: float32(h2),
 	}
-	s.DrawTriangles([graphics.ShaderImageNum]*buffered.Image{src}, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, filter, graphicsdriver.AddressUnsafe, dstRegion, graphicsdriver.Region{}, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, false)
+	s.DrawTriangles([graphics.ShaderImageNum]*buffered.Image{src}, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, filter, graphicsdriver.AddressUnsafe, dstRegion, graphicsdriver.Region{}, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, false, false)
 	m.setImg(level, s)
 
 	return m.imgs[level]
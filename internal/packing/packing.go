@@ -147,6 +147,21 @@ func (p *Page) Size() int {
 	return p.size
 }
 
+// UsedArea returns the total area of the allocated nodes in pixels.
+func (p *Page) UsedArea() int {
+	return p.root.usedArea()
+}
+
+func (n *Node) usedArea() int {
+	if n == nil {
+		return 0
+	}
+	if n.used {
+		return n.width * n.height
+	}
+	return n.child0.usedArea() + n.child1.usedArea()
+}
+
 func (p *Page) SetMaxSize(size int) {
 	if p.maxSize > size {
 		panic("packing: maxSize cannot be decreased")
@@ -359,3 +359,21 @@ func TestExtendWithoutAllocation(t *testing.T) {
 		t.Errorf("p.Size(): got: %d, want: %d", got, want)
 	}
 }
+
+func TestUsedArea(t *testing.T) {
+	p := packing.NewPage(1024, 1024)
+	if got, want := p.UsedArea(), 0; got != want {
+		t.Errorf("p.UsedArea(): got: %d, want: %d", got, want)
+	}
+
+	n0 := p.Alloc(100, 200)
+	p.Alloc(30, 40)
+	if got, want := p.UsedArea(), 100*200+30*40; got != want {
+		t.Errorf("p.UsedArea(): got: %d, want: %d", got, want)
+	}
+
+	p.Free(n0)
+	if got, want := p.UsedArea(), 30*40; got != want {
+		t.Errorf("p.UsedArea(): got: %d, want: %d", got, want)
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build ignore
+// +build ignore
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+type Game struct{}
+
+func (g *Game) Update() error {
+	return nil
+}
+
+func (g *Game) Draw(screen *ebiten.Image) {
+}
+
+func (g *Game) Layout(width, height int) (int, int) {
+	return width, height
+}
+
+func init() {
+	// StartGameWithoutMainLoop and UpdateGameWithoutMainLoop must be called on the main thread.
+	runtime.LockOSThread()
+}
+
+func run() error {
+	if err := ebiten.StartGameWithoutMainLoop(&Game{}); err != nil {
+		return err
+	}
+	for i := 0; i < 3; i++ {
+		if err := ebiten.UpdateGameWithoutMainLoop(); err != nil {
+			return err
+		}
+
+		// The window functions must not block between frames.
+		ebiten.SetWindowTitle(fmt.Sprintf("Frame %d", i))
+		ebiten.SetWindowSize(320+i, 240+i)
+		if w, h := ebiten.WindowSize(); w != 320+i || h != 240+i {
+			return fmt.Errorf("frame: %d, got: (%d, %d), want: (%d, %d)", i, w, h, 320+i, 240+i)
+		}
+	}
+	return nil
+}
+
+func main() {
+	// If a window function blocks forever, the process never ends. Fail instead.
+	time.AfterFunc(10*time.Second, func() {
+		fmt.Fprintln(os.Stderr, "timeout: a window function blocked between frames")
+		os.Exit(1)
+	})
+
+	if err := run(); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,276 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package qoi implements a decoder and an encoder for the QOI ("Quite OK Image") format.
+//
+// The specification is at https://qoiformat.org/qoi-specification.pdf.
+package qoi
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+)
+
+const (
+	magic      = "qoif"
+	headerSize = 14
+
+	opIndex = 0x00
+	opDiff  = 0x40
+	opLuma  = 0x80
+	opRun   = 0xc0
+	opRGB   = 0xfe
+	opRGBA  = 0xff
+	opMask  = 0xc0
+
+	// maxPixels is the limit of the number of pixels, the same as the reference implementation.
+	maxPixels = 400000000
+)
+
+var endMarker = [8]byte{0, 0, 0, 0, 0, 0, 0, 1}
+
+type pixel struct {
+	r, g, b, a uint8
+}
+
+func (p pixel) hash() int {
+	return (int(p.r)*3 + int(p.g)*5 + int(p.b)*7 + int(p.a)*11) % 64
+}
+
+func readHeader(r io.Reader) (width, height int, err error) {
+	var buf [headerSize]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return 0, 0, err
+	}
+	if string(buf[:4]) != magic {
+		return 0, 0, errors.New("qoi: invalid magic")
+	}
+	w := binary.BigEndian.Uint32(buf[4:])
+	h := binary.BigEndian.Uint32(buf[8:])
+	if w == 0 || h == 0 || uint64(w)*uint64(h) > maxPixels {
+		return 0, 0, fmt.Errorf("qoi: invalid size: %d x %d", w, h)
+	}
+	if c := buf[12]; c != 3 && c != 4 {
+		return 0, 0, fmt.Errorf("qoi: invalid number of channels: %d", c)
+	}
+	if c := buf[13]; c > 1 {
+		return 0, 0, fmt.Errorf("qoi: invalid colorspace: %d", c)
+	}
+	return int(w), int(h), nil
+}
+
+// DecodeConfig returns the color model and dimensions of a QOI image without decoding the entire image.
+func DecodeConfig(r io.Reader) (image.Config, error) {
+	w, h, err := readHeader(r)
+	if err != nil {
+		return image.Config{}, err
+	}
+	return image.Config{
+		ColorModel: color.NRGBAModel,
+		Width:      w,
+		Height:     h,
+	}, nil
+}
+
+// Decode reads a QOI image from r and returns it as an *image.NRGBA.
+//
+// The colorspace in the header is informative and doesn't affect the result.
+func Decode(r io.Reader) (image.Image, error) {
+	w, h, err := readHeader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	br, ok := r.(io.ByteReader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	readByte := func() (byte, error) {
+		b, err := br.ReadByte()
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return b, err
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	var index [64]pixel
+	px := pixel{a: 0xff}
+	var run int
+	for i := 0; i < len(img.Pix); i += 4 {
+		if run > 0 {
+			run--
+		} else {
+			b, err := readByte()
+			if err != nil {
+				return nil, err
+			}
+			switch {
+			case b == opRGB:
+				var buf [3]byte
+				for j := range buf {
+					if buf[j], err = readByte(); err != nil {
+						return nil, err
+					}
+				}
+				px.r, px.g, px.b = buf[0], buf[1], buf[2]
+			case b == opRGBA:
+				var buf [4]byte
+				for j := range buf {
+					if buf[j], err = readByte(); err != nil {
+						return nil, err
+					}
+				}
+				px = pixel{buf[0], buf[1], buf[2], buf[3]}
+			case b&opMask == opIndex:
+				px = index[b]
+			case b&opMask == opDiff:
+				px.r += (b>>4)&0x03 - 2
+				px.g += (b>>2)&0x03 - 2
+				px.b += b&0x03 - 2
+			case b&opMask == opLuma:
+				b2, err := readByte()
+				if err != nil {
+					return nil, err
+				}
+				dg := b&0x3f - 32
+				px.r += dg - 8 + (b2>>4)&0x0f
+				px.g += dg
+				px.b += dg - 8 + b2&0x0f
+			case b&opMask == opRun:
+				run = int(b & 0x3f)
+			}
+			index[px.hash()] = px
+		}
+		img.Pix[i] = px.r
+		img.Pix[i+1] = px.g
+		img.Pix[i+2] = px.b
+		img.Pix[i+3] = px.a
+	}
+	return img, nil
+}
+
+// Encode writes img to w in the QOI format.
+//
+// The image is encoded with 4 channels and the sRGB colorspace.
+func Encode(w io.Writer, img image.Image) error {
+	b := img.Bounds()
+	width, height := b.Dx(), b.Dy()
+	if width == 0 || height == 0 || uint64(width)*uint64(height) > maxPixels {
+		return fmt.Errorf("qoi: invalid size: %d x %d", width, height)
+	}
+
+	bw := bufio.NewWriter(w)
+
+	var header [headerSize]byte
+	copy(header[:], magic)
+	binary.BigEndian.PutUint32(header[4:], uint32(width))
+	binary.BigEndian.PutUint32(header[8:], uint32(height))
+	header[12] = 4
+	header[13] = 0
+	if _, err := bw.Write(header[:]); err != nil {
+		return err
+	}
+
+	var index [64]pixel
+	prev := pixel{a: 0xff}
+	var run int
+	n := width * height
+	for i := 0; i < n; i++ {
+		px := pixelAt(img, b.Min.X+i%width, b.Min.Y+i/width)
+		if px == prev {
+			run++
+			if run == 62 || i == n-1 {
+				_ = bw.WriteByte(opRun | byte(run-1))
+				run = 0
+			}
+			continue
+		}
+		if run > 0 {
+			_ = bw.WriteByte(opRun | byte(run-1))
+			run = 0
+		}
+
+		h := px.hash()
+		if index[h] == px {
+			_ = bw.WriteByte(opIndex | byte(h))
+			prev = px
+			continue
+		}
+		index[h] = px
+
+		if px.a != prev.a {
+			_, _ = bw.Write([]byte{opRGBA, px.r, px.g, px.b, px.a})
+			prev = px
+			continue
+		}
+
+		dr := int8(px.r - prev.r)
+		dg := int8(px.g - prev.g)
+		db := int8(px.b - prev.b)
+		drdg := dr - dg
+		dbdg := db - dg
+		switch {
+		case -2 <= dr && dr <= 1 && -2 <= dg && dg <= 1 && -2 <= db && db <= 1:
+			_ = bw.WriteByte(opDiff | byte(dr+2)<<4 | byte(dg+2)<<2 | byte(db+2))
+		case -32 <= dg && dg <= 31 && -8 <= drdg && drdg <= 7 && -8 <= dbdg && dbdg <= 7:
+			_, _ = bw.Write([]byte{opLuma | byte(dg+32), byte(drdg+8)<<4 | byte(dbdg+8)})
+		default:
+			_, _ = bw.Write([]byte{opRGB, px.r, px.g, px.b})
+		}
+		prev = px
+	}
+
+	// Errors from the writes above are sticky in bufio.Writer and are reported here.
+	if _, err := bw.Write(endMarker[:]); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+func pixelAt(img image.Image, x, y int) pixel {
+	switch img := img.(type) {
+	case *image.NRGBA:
+		i := img.PixOffset(x, y)
+		return pixel{img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3]}
+	case *image.RGBA:
+		i := img.PixOffset(x, y)
+		return unpremultiply(img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3])
+	}
+	c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+	return pixel{c.R, c.G, c.B, c.A}
+}
+
+func unpremultiply(r, g, b, a uint8) pixel {
+	switch a {
+	case 0:
+		return pixel{}
+	case 0xff:
+		return pixel{r, g, b, a}
+	}
+	return pixel{
+		r: uint8(uint32(r) * 0xff / uint32(a)),
+		g: uint8(uint32(g) * 0xff / uint32(a)),
+		b: uint8(uint32(b) * 0xff / uint32(a)),
+		a: a,
+	}
+}
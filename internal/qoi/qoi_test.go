@@ -0,0 +1,110 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package qoi_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/qoi"
+)
+
+func TestRoundTrip(t *testing.T) {
+	const (
+		w = 37
+		h = 23
+	)
+	src := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			var c color.NRGBA
+			switch {
+			case j < 4:
+				// Runs
+				c = color.NRGBA{0x10, 0x20, 0x30, 0xff}
+			case j < 8:
+				// Small differences
+				c = color.NRGBA{uint8(i), uint8(i + 1), uint8(i * 2), 0xff}
+			case j < 12:
+				// Repeated colors for the index
+				c = color.NRGBA{uint8(i%3) * 80, 0, uint8(i%3) * 40, 0xff}
+			default:
+				c = color.NRGBA{uint8(i * 37), uint8(j * 91), uint8(i * j), uint8(i * 7)}
+			}
+			src.SetNRGBA(i, j, c)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := qoi.Encode(&buf, src); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := qoi.DecodeConfig(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Width != w || cfg.Height != h {
+		t.Errorf("got: %d x %d, want: %d x %d", cfg.Width, cfg.Height, w, h)
+	}
+
+	got, err := qoi.Decode(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got.(*image.NRGBA).Pix, src.Pix) {
+		t.Errorf("the decoded pixels don't match")
+	}
+}
+
+func TestDecodeKnown(t *testing.T) {
+	// A 2x2 image: an RGB pixel, a run of it, an RGBA pixel, and an index reference to the first pixel.
+	data := []byte{
+		'q', 'o', 'i', 'f', 0, 0, 0, 2, 0, 0, 0, 2, 4, 0,
+		0xfe, 0x11, 0x22, 0x33,
+		0xc0,
+		0xff, 0x44, 0x55, 0x66, 0x77,
+		0x00 | byte((0x11*3+0x22*5+0x33*7+0xff*11)%64),
+		0, 0, 0, 0, 0, 0, 0, 1,
+	}
+	img, err := qoi.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []byte{
+		0x11, 0x22, 0x33, 0xff, 0x11, 0x22, 0x33, 0xff,
+		0x44, 0x55, 0x66, 0x77, 0x11, 0x22, 0x33, 0xff,
+	}
+	if got := img.(*image.NRGBA).Pix; !bytes.Equal(got, want) {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+}
+
+func TestDecodeTruncated(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	for i := 0; i < len(src.Pix); i += 4 {
+		copy(src.Pix[i:], []byte{1, 2, 3, 4})
+	}
+	var buf bytes.Buffer
+	if err := qoi.Encode(&buf, src); err != nil {
+		t.Fatal(err)
+	}
+	// Cut the data in the middle of an RGBA operation.
+	if _, err := qoi.Decode(bytes.NewReader(buf.Bytes()[:16])); err == nil {
+		t.Errorf("Decode must fail with truncated data")
+	}
+}
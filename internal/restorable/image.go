@@ -17,6 +17,10 @@ package restorable
 import (
 	"fmt"
 	"image"
+	"io"
+	"math"
+	"strings"
+	"sync/atomic"
 
 	"github.com/hajimehoshi/ebiten/v2/internal/affine"
 	"github.com/hajimehoshi/ebiten/v2/internal/graphics"
@@ -78,6 +82,10 @@ type drawTrianglesHistoryItem struct {
 	shader    *Shader
 	uniforms  []graphicsdriver.Uniform
 	evenOdd   bool
+	depthTest bool
+
+	// clearDepth indicates whether the item clears the depth buffer instead of drawing triangles.
+	clearDepth bool
 }
 
 // Image represents an image that can be restored when GL context is lost.
@@ -104,6 +112,28 @@ type Image struct {
 
 	// priority indicates whether the image is restored in high priority when context-lost happens.
 	priority bool
+
+	// compressed indicates whether the image is created from a compressed texture.
+	// A compressed image can be used only as a rendering source.
+	compressed       bool
+	compressedFormat graphicsdriver.CompressedTextureFormat
+
+	// compressedData is the compressed texture data to restore the image.
+	// compressedData is nil when restoring is not needed.
+	compressedData []byte
+
+	// native indicates whether the image wraps a texture created outside of Ebiten.
+	// A native image can be used only as a rendering source.
+	// The content of a native image can be changed outside of Ebiten at any time, and cannot be restored.
+	native bool
+
+	// depthWritten indicates whether the depth buffer might have been updated since it was cleared last time.
+	depthWritten bool
+
+	// depthLost indicates whether the depth buffer cannot be reproduced by replaying drawTrianglesHistory.
+	// The depth buffer is not read back from GPU, so the depth buffer is lost when the history is cleared after
+	// depth testing. ClearDepth makes the depth buffer reproducible again.
+	depthLost bool
 }
 
 var emptyImage *Image
@@ -123,6 +153,7 @@ func ensureEmptyImage() *Image {
 		height:   h,
 		priority: true,
 	}
+	atomic.AddInt64(&allocatedTextureCount, 1)
 	pix := make([]byte, 4*w*h)
 	for i := range pix {
 		pix[i] = 0xff
@@ -152,6 +183,53 @@ func NewImage(width, height int) *Image {
 	}
 	clearImage(i.image)
 	theImages.add(i)
+	atomic.AddInt64(&allocatedTextureCount, 1)
+	return i
+}
+
+// NewCompressedImage creates an image from the given compressed texture data.
+//
+// A compressed image cannot be a rendering destination, and its pixels cannot be replaced.
+//
+// Note that Dispose is not called automatically.
+func NewCompressedImage(width, height int, format graphicsdriver.CompressedTextureFormat, data []byte) *Image {
+	if !graphicsDriverInitialized {
+		panic("restorable: graphics driver must be ready at NewCompressedImage but not")
+	}
+
+	i := &Image{
+		image:            graphicscommand.NewCompressedImage(width, height, format, data),
+		width:            width,
+		height:           height,
+		compressed:       true,
+		compressedFormat: format,
+	}
+	if NeedsRestoring() {
+		i.compressedData = data
+	}
+	theImages.add(i)
+	atomic.AddInt64(&allocatedTextureCount, 1)
+	return i
+}
+
+// NewNativeImage creates an image wrapping the given native texture.
+//
+// A native image cannot be a rendering destination, and its pixels cannot be replaced.
+// When the context is lost, a native image is restored as a cleared image.
+//
+// Note that Dispose is not called automatically.
+func NewNativeImage(width, height int, texture graphicsdriver.NativeTexture) *Image {
+	if !graphicsDriverInitialized {
+		panic("restorable: graphics driver must be ready at NewNativeImage but not")
+	}
+
+	i := &Image{
+		image:  graphicscommand.NewNativeImage(width, height, texture),
+		width:  width,
+		height: height,
+		native: true,
+	}
+	theImages.add(i)
 	return i
 }
 
@@ -199,7 +277,7 @@ func (i *Image) Extend(width, height int) *Image {
 		Width:  float32(sw),
 		Height: float32(sh),
 	}
-	newImg.DrawTriangles(srcs, offsets, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false)
+	newImg.DrawTriangles(srcs, offsets, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false, false)
 
 	// Overwrite the history as if the image newImg is created only by ReplacePixels. Now drawTrianglesHistory
 	// and basePixels cannot be mixed.
@@ -232,10 +310,10 @@ func NewScreenFramebufferImage(width, height int) *Image {
 // quadVertices returns vertices to render a quad. These values are passed to graphicscommand.Image.
 func quadVertices(dx0, dy0, dx1, dy1, sx0, sy0, sx1, sy1, cr, cg, cb, ca float32) []float32 {
 	return []float32{
-		dx0, dy0, sx0, sy0, cr, cg, cb, ca,
-		dx1, dy0, sx1, sy0, cr, cg, cb, ca,
-		dx0, dy1, sx0, sy1, cr, cg, cb, ca,
-		dx1, dy1, sx1, sy1, cr, cg, cb, ca,
+		dx0, dy0, sx0, sy0, cr, cg, cb, ca, 0,
+		dx1, dy0, sx1, sy0, cr, cg, cb, ca, 0,
+		dx0, dy1, sx0, sy1, cr, cg, cb, ca, 0,
+		dx1, dy1, sx1, sy1, cr, cg, cb, ca, 0,
 	}
 }
 
@@ -262,7 +340,7 @@ func clearImage(i *graphicscommand.Image) {
 		Width:  float32(dw),
 		Height: float32(dh),
 	}
-	i.DrawTriangles(srcs, offsets, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeClear, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dstRegion, graphicsdriver.Region{}, nil, nil, false)
+	i.DrawTriangles(srcs, offsets, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeClear, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dstRegion, graphicsdriver.Region{}, nil, nil, false, false)
 }
 
 // BasePixelsForTesting returns the image's basePixels for testing.
@@ -270,6 +348,18 @@ func (i *Image) BasePixelsForTesting() *Pixels {
 	return &i.basePixels
 }
 
+// ReadPixelsFromGPUForTesting reads the pixels of the image's texture for testing.
+//
+// Unlike At, ReadPixelsFromGPUForTesting doesn't use basePixels, so this reports whether the texture is restored.
+func (i *Image) ReadPixelsFromGPUForTesting(pix []byte) error {
+	return i.image.ReadPixels(pix)
+}
+
+// DrawTrianglesHistoryLenForTesting returns the length of the image's drawing history for testing.
+func (i *Image) DrawTrianglesHistoryLenForTesting() int {
+	return len(i.drawTrianglesHistory)
+}
+
 // makeStale makes the image stale.
 func (i *Image) makeStale() {
 	i.basePixels = Pixels{}
@@ -291,6 +381,12 @@ func (i *Image) ClearPixels(x, y, width, height int) {
 //
 // ReplacePixels for a part is forbidden if the image is rendered with DrawTriangles or Fill.
 func (i *Image) ReplacePixels(pixels []byte, x, y, width, height int) {
+	if i.compressed {
+		panic("restorable: ReplacePixels cannot be called on a compressed image")
+	}
+	if i.native {
+		panic("restorable: ReplacePixels cannot be called on a native image")
+	}
 	if width <= 0 || height <= 0 {
 		panic("restorable: width/height must be positive")
 	}
@@ -365,13 +461,27 @@ func (i *Image) ReplacePixels(pixels []byte, x, y, width, height int) {
 //   5: Color G
 //   6: Color B
 //   7: Color Y
-func (i *Image) DrawTriangles(srcs [graphics.ShaderImageNum]*Image, offsets [graphics.ShaderImageNum - 1][2]float32, vertices []float32, indices []uint16, colorm affine.ColorM, mode graphicsdriver.CompositeMode, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, shader *Shader, uniforms []graphicsdriver.Uniform, evenOdd bool) {
+func (i *Image) DrawTriangles(srcs [graphics.ShaderImageNum]*Image, offsets [graphics.ShaderImageNum - 1][2]float32, vertices []float32, indices []uint16, colorm affine.ColorM, mode graphicsdriver.CompositeMode, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, shader *Shader, uniforms []graphicsdriver.Uniform, evenOdd bool, depthTest bool) {
 	if i.priority {
 		panic("restorable: DrawTriangles cannot be called on a priority image")
 	}
+	if i.compressed {
+		panic("restorable: DrawTriangles cannot be called on a compressed image")
+	}
+	if i.native {
+		panic("restorable: DrawTriangles cannot be called on a native image")
+	}
 	if len(vertices) == 0 {
 		return
 	}
+
+	// Triangles without area change no pixels, e.g., the ones to warm up rendering pipelines.
+	// Such a draw doesn't have to be recorded in the history, while the draw command is still sent.
+	if !hasArea(vertices, indices) {
+		i.drawTrianglesWithoutHistory(srcs, offsets, vertices, indices, colorm, mode, filter, address, dstRegion, srcRegion, shader, uniforms, evenOdd, depthTest)
+		return
+	}
+
 	theImages.makeStaleIfDependingOn(i)
 
 	// TODO: Add tests to confirm this logic.
@@ -380,18 +490,27 @@ func (i *Image) DrawTriangles(srcs [graphics.ShaderImageNum]*Image, offsets [gra
 		if src == nil {
 			continue
 		}
-		if src.stale || src.volatile {
+		// A native image's content can be changed outside of Ebiten, so the history cannot be replayed.
+		if src.stale || src.volatile || src.native {
 			srcstale = true
 			break
 		}
 	}
 
-	if srcstale || i.screen || !NeedsRestoring() || i.volatile {
+	// The history with depth testing can be replayed only when the depth buffer is reproducible from the history.
+	if srcstale || (depthTest && i.depthLost) || i.screen || !NeedsRestoring() || i.volatile {
 		i.makeStale()
 	} else {
-		i.appendDrawTrianglesHistory(srcs, offsets, vertices, indices, colorm, mode, filter, address, dstRegion, srcRegion, shader, uniforms, evenOdd)
+		i.appendDrawTrianglesHistory(srcs, offsets, vertices, indices, colorm, mode, filter, address, dstRegion, srcRegion, shader, uniforms, evenOdd, depthTest)
 	}
+	if depthTest {
+		i.depthWritten = true
+	}
+
+	i.drawTrianglesWithoutHistory(srcs, offsets, vertices, indices, colorm, mode, filter, address, dstRegion, srcRegion, shader, uniforms, evenOdd, depthTest)
+}
 
+func (i *Image) drawTrianglesWithoutHistory(srcs [graphics.ShaderImageNum]*Image, offsets [graphics.ShaderImageNum - 1][2]float32, vertices []float32, indices []uint16, colorm affine.ColorM, mode graphicsdriver.CompositeMode, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, shader *Shader, uniforms []graphicsdriver.Uniform, evenOdd bool, depthTest bool) {
 	var s *graphicscommand.Shader
 	var imgs [graphics.ShaderImageNum]*graphicscommand.Image
 	if shader == nil {
@@ -406,11 +525,135 @@ func (i *Image) DrawTriangles(srcs [graphics.ShaderImageNum]*Image, offsets [gra
 		}
 		s = shader.shader
 	}
-	i.image.DrawTriangles(imgs, offsets, vertices, indices, colorm, mode, filter, address, dstRegion, srcRegion, s, uniforms, evenOdd)
+	i.image.DrawTriangles(imgs, offsets, vertices, indices, colorm, mode, filter, address, dstRegion, srcRegion, s, uniforms, evenOdd, depthTest)
+}
+
+// hasArea reports whether any of the triangles has a non-zero area in the destination.
+func hasArea(vertices []float32, indices []uint16) bool {
+	const n = graphics.VertexFloatNum
+	for t := 0; t+2 < len(indices); t += 3 {
+		i0, i1, i2 := int(indices[t])*n, int(indices[t+1])*n, int(indices[t+2])*n
+		x0, y0 := vertices[i0], vertices[i0+1]
+		x1, y1 := vertices[i1], vertices[i1+1]
+		x2, y2 := vertices[i2], vertices[i2+1]
+		if (x1-x0)*(y2-y0)-(x2-x0)*(y1-y0) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// CopyPixels copies the region (srcX, srcY)-(srcX+width, srcY+height) of src to (dstX, dstY) of the image as it is.
+//
+// The copy is recorded in the history as an equivalent draw with the copy mode and the nearest filter.
+func (i *Image) CopyPixels(src *Image, dstX, dstY, srcX, srcY, width, height int) {
+	if i.priority {
+		panic("restorable: CopyPixels cannot be called on a priority image")
+	}
+	if i.compressed {
+		panic("restorable: CopyPixels cannot be called on a compressed image")
+	}
+	if i.native {
+		panic("restorable: CopyPixels cannot be called on a native image")
+	}
+	if width <= 0 || height <= 0 {
+		return
+	}
+	theImages.makeStaleIfDependingOn(i)
+
+	if src.stale || src.volatile || src.native || i.screen || !NeedsRestoring() || i.volatile {
+		i.makeStale()
+	} else {
+		dx, dy := float32(dstX), float32(dstY)
+		sx, sy := float32(srcX), float32(srcY)
+		w, h := float32(width), float32(height)
+		vs := quadVertices(dx, dy, dx+w, dy+h, sx, sy, sx+w, sy+h, 1, 1, 1, 1)
+		is := graphics.QuadIndices()
+		dr := graphicsdriver.Region{
+			X:      dx,
+			Y:      dy,
+			Width:  w,
+			Height: h,
+		}
+		srcs := [graphics.ShaderImageNum]*Image{src}
+		i.appendDrawTrianglesHistory(srcs, [graphics.ShaderImageNum - 1][2]float32{}, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false, false)
+	}
+
+	i.image.CopyPixels(src.image, dstX, dstY, srcX, srcY, width, height)
+}
+
+// FillRect replaces the pixels of the region (x, y)-(x+width, y+height) of the image with the given premultiplied
+// color.
+//
+// The fill is recorded in the history as an equivalent draw of the empty image with the copy mode.
+func (i *Image) FillRect(x, y, width, height int, red, green, blue, alpha float32) {
+	if i.priority {
+		panic("restorable: FillRect cannot be called on a priority image")
+	}
+	if i.compressed {
+		panic("restorable: FillRect cannot be called on a compressed image")
+	}
+	if i.native {
+		panic("restorable: FillRect cannot be called on a native image")
+	}
+	if width <= 0 || height <= 0 {
+		return
+	}
+	theImages.makeStaleIfDependingOn(i)
+
+	if i.screen || !NeedsRestoring() || i.volatile {
+		i.makeStale()
+	} else {
+		// Vertex colors are not premultiplied.
+		var cr, cg, cb float32
+		if alpha > 0 {
+			cr, cg, cb = red/alpha, green/alpha, blue/alpha
+		}
+		emptyImage := ensureEmptyImage()
+		dx, dy := float32(x), float32(y)
+		w, h := float32(width), float32(height)
+		sw, sh := emptyImage.width, emptyImage.height
+		vs := quadVertices(dx, dy, dx+w, dy+h, 1, 1, float32(sw-1), float32(sh-1), cr, cg, cb, alpha)
+		is := graphics.QuadIndices()
+		dr := graphicsdriver.Region{
+			X:      dx,
+			Y:      dy,
+			Width:  w,
+			Height: h,
+		}
+		srcs := [graphics.ShaderImageNum]*Image{emptyImage}
+		i.appendDrawTrianglesHistory(srcs, [graphics.ShaderImageNum - 1][2]float32{}, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false, false)
+	}
+
+	i.image.FillRect(x, y, width, height, red, green, blue, alpha)
+}
+
+// ClearDepth resets the depth buffer of the image.
+func (i *Image) ClearDepth() {
+	if i.priority {
+		panic("restorable: ClearDepth cannot be called on a priority image")
+	}
+	if i.compressed {
+		panic("restorable: ClearDepth cannot be called on a compressed image")
+	}
+	if i.native {
+		panic("restorable: ClearDepth cannot be called on a native image")
+	}
+	i.image.ClearDepth()
+
+	// A restored image starts with a cleared depth buffer, so clearing the depth buffer doesn't have to be
+	// recorded unless there are other items in the history.
+	if len(i.drawTrianglesHistory) > 0 && !i.stale && !i.volatile && !i.screen && NeedsRestoring() {
+		i.drawTrianglesHistory = append(i.drawTrianglesHistory, &drawTrianglesHistoryItem{
+			clearDepth: true,
+		})
+	}
+	i.depthWritten = false
+	i.depthLost = false
 }
 
 // appendDrawTrianglesHistory appends a draw-image history item to the image.
-func (i *Image) appendDrawTrianglesHistory(srcs [graphics.ShaderImageNum]*Image, offsets [graphics.ShaderImageNum - 1][2]float32, vertices []float32, indices []uint16, colorm affine.ColorM, mode graphicsdriver.CompositeMode, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, shader *Shader, uniforms []graphicsdriver.Uniform, evenOdd bool) {
+func (i *Image) appendDrawTrianglesHistory(srcs [graphics.ShaderImageNum]*Image, offsets [graphics.ShaderImageNum - 1][2]float32, vertices []float32, indices []uint16, colorm affine.ColorM, mode graphicsdriver.CompositeMode, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, shader *Shader, uniforms []graphicsdriver.Uniform, evenOdd bool, depthTest bool) {
 	if i.stale || i.volatile || i.screen {
 		return
 	}
@@ -443,11 +686,22 @@ func (i *Image) appendDrawTrianglesHistory(srcs [graphics.ShaderImageNum]*Image,
 		shader:    shader,
 		uniforms:  uniforms,
 		evenOdd:   evenOdd,
+		depthTest: depthTest,
 	}
 	i.drawTrianglesHistory = append(i.drawTrianglesHistory, item)
 }
 
 func (i *Image) readPixelsFromGPUIfNeeded() error {
+	if i.native {
+		// The content might be changed outside of Ebiten. Read the pixels every time.
+		return i.readCompressedPixelsFromGPU()
+	}
+	if i.compressed {
+		if i.basePixels.rectToPixels == nil {
+			return i.readCompressedPixelsFromGPU()
+		}
+		return nil
+	}
 	if len(i.drawTrianglesHistory) > 0 || i.stale {
 		if err := graphicscommand.FlushCommands(); err != nil {
 			return err
@@ -508,6 +762,36 @@ func (i *Image) readPixelsFromGPU() error {
 	return nil
 }
 
+// readCompressedPixelsFromGPU reads the pixels of the compressed or native image from GPU.
+//
+// A compressed texture cannot be read directly. The image is rendered to a temporary image to read the pixels.
+// A native texture is read in the same way as its format is unknown.
+func (i *Image) readCompressedPixelsFromGPU() error {
+	w, h := float32(i.width), float32(i.height)
+	img := graphicscommand.NewImage(i.width, i.height)
+	defer img.Dispose()
+
+	srcs := [graphics.ShaderImageNum]*graphicscommand.Image{i.image}
+	var offsets [graphics.ShaderImageNum - 1][2]float32
+	vs := quadVertices(0, 0, w, h, 0, 0, w, h, 1, 1, 1, 1)
+	is := graphics.QuadIndices()
+	dr := graphicsdriver.Region{
+		X:      0,
+		Y:      0,
+		Width:  w,
+		Height: h,
+	}
+	img.DrawTriangles(srcs, offsets, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false, false)
+
+	pix := make([]byte, 4*i.width*i.height)
+	if err := img.ReadPixels(pix); err != nil {
+		return err
+	}
+	i.basePixels = Pixels{}
+	i.basePixels.AddOrReplace(pix, 0, 0, i.width, i.height)
+	return nil
+}
+
 // resolveStale resolves the image's 'stale' state.
 func (i *Image) resolveStale() error {
 	if !NeedsRestoring() {
@@ -587,6 +871,19 @@ func (i *Image) restore() error {
 		i.stale = false
 		return nil
 	}
+	if i.compressed {
+		if i.compressedData == nil {
+			panic("restorable: the compressed data must be kept when restoring")
+		}
+		i.image = graphicscommand.NewCompressedImage(w, h, i.compressedFormat, i.compressedData)
+		return nil
+	}
+	if i.native {
+		// The native texture belongs to the lost context and cannot be recreated.
+		i.image = graphicscommand.NewImage(w, h)
+		clearImage(i.image)
+		return nil
+	}
 	if i.volatile {
 		i.image = graphicscommand.NewImage(w, h)
 		clearImage(i.image)
@@ -606,6 +903,11 @@ func (i *Image) restore() error {
 	i.basePixels.Apply(gimg)
 
 	for _, c := range i.drawTrianglesHistory {
+		if c.clearDepth {
+			gimg.ClearDepth()
+			continue
+		}
+
 		var s *graphicscommand.Shader
 		if c.shader != nil {
 			s = c.shader.shader
@@ -621,7 +923,7 @@ func (i *Image) restore() error {
 			}
 			imgs[i] = img.image
 		}
-		gimg.DrawTriangles(imgs, c.offsets, c.vertices, c.indices, c.colorm, c.mode, c.filter, c.address, c.dstRegion, c.srcRegion, s, c.uniforms, c.evenOdd)
+		gimg.DrawTriangles(imgs, c.offsets, c.vertices, c.indices, c.colorm, c.mode, c.filter, c.address, c.dstRegion, c.srcRegion, s, c.uniforms, c.evenOdd, c.depthTest)
 	}
 
 	if len(i.drawTrianglesHistory) > 0 {
@@ -647,6 +949,7 @@ func (i *Image) Dispose() {
 	i.image.Dispose()
 	i.image = nil
 	i.basePixels = Pixels{}
+	i.compressedData = nil
 	i.clearDrawTrianglesHistory()
 	i.stale = false
 }
@@ -666,10 +969,95 @@ func (i *Image) Dump(path string, blackbg bool, rect image.Rectangle) error {
 	return i.image.Dump(path, blackbg, rect)
 }
 
+// DumpHistory writes the draw-triangles history of the image in a human-readable format to w.
+//
+// Only the history items whose destination region overlaps with rect are written.
+func (i *Image) DumpHistory(w io.Writer, rect image.Rectangle) error {
+	id := fmt.Sprintf("%d", i.image.ID())
+	if i.screen {
+		id += " (screen)"
+	}
+	if _, err := fmt.Fprintf(w, "image %s: (%d, %d), region: %v\n", id, i.width, i.height, rect); err != nil {
+		return err
+	}
+
+	switch {
+	case i.screen:
+		_, err := fmt.Fprintln(w, "  the screen doesn't record its history")
+		return err
+	case i.volatile:
+		_, err := fmt.Fprintln(w, "  a volatile image doesn't record its history")
+		return err
+	case !NeedsRestoring():
+		_, err := fmt.Fprintln(w, "  the history is not recorded as restoring is not needed in this environment")
+		return err
+	case i.stale:
+		_, err := fmt.Fprintln(w, "  the image is stale: the history was discarded (e.g. drawn from a stale or volatile image, or too many draw calls)")
+		return err
+	}
+
+	if i.basePixels.rectToPixels != nil {
+		if _, err := fmt.Fprintln(w, "  base pixels: set by ReplacePixels"); err != nil {
+			return err
+		}
+	} else {
+		if _, err := fmt.Fprintln(w, "  base pixels: none (cleared)"); err != nil {
+			return err
+		}
+	}
+
+	for idx, c := range i.drawTrianglesHistory {
+		if c.clearDepth {
+			if _, err := fmt.Fprintf(w, "  #%d: clear depth\n", idx); err != nil {
+				return err
+			}
+			continue
+		}
+
+		dr := image.Rect(int(c.dstRegion.X), int(c.dstRegion.Y), int(c.dstRegion.X+c.dstRegion.Width), int(c.dstRegion.Y+c.dstRegion.Height))
+		if !dr.Overlaps(rect) {
+			continue
+		}
+
+		var srcstrs []string
+		for _, src := range c.images {
+			if src == nil {
+				continue
+			}
+			srcstrs = append(srcstrs, fmt.Sprintf("%d", src.image.ID()))
+		}
+
+		// Calculate the bounds of the vertices.
+		dminX, dminY, dmaxX, dmaxY := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+		sminX, sminY, smaxX, smaxY := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+		for j := 0; j < len(c.vertices)/graphics.VertexFloatNum; j++ {
+			v := c.vertices[j*graphics.VertexFloatNum : (j+1)*graphics.VertexFloatNum]
+			dminX, dminY = math.Min(dminX, float64(v[0])), math.Min(dminY, float64(v[1]))
+			dmaxX, dmaxY = math.Max(dmaxX, float64(v[0])), math.Max(dmaxY, float64(v[1]))
+			sminX, sminY = math.Min(sminX, float64(v[2])), math.Min(sminY, float64(v[3]))
+			smaxX, smaxY = math.Max(smaxX, float64(v[2])), math.Max(smaxY, float64(v[3]))
+		}
+
+		shader := "default"
+		if c.shader != nil {
+			shader = "custom"
+		}
+
+		if _, err := fmt.Fprintf(w, "  #%d: src: [%s], dst bounds: (%g, %g)-(%g, %g), src bounds: (%g, %g)-(%g, %g), num of indices: %d, colorm: %s, mode: %s, filter: %s, address: %s, shader: %s, even-odd: %t, depth-test: %t\n",
+			idx, strings.Join(srcstrs, ", "), dminX, dminY, dmaxX, dmaxY, sminX, sminY, smaxX, smaxY, len(c.indices), affine.ColorMString(c.colorm), c.mode, c.filter, c.address, shader, c.evenOdd, c.depthTest); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (i *Image) clearDrawTrianglesHistory() {
 	// Clear the items explicitly, or the references might remain (#1803).
 	for idx := range i.drawTrianglesHistory {
 		i.drawTrianglesHistory[idx] = nil
 	}
 	i.drawTrianglesHistory = i.drawTrianglesHistory[:0]
+
+	// The depth buffer is not a part of basePixels.
+	i.depthLost = i.depthWritten
 }
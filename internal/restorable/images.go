@@ -17,10 +17,12 @@ package restorable
 import (
 	"image"
 	"path/filepath"
+	"sync/atomic"
 
 	"github.com/hajimehoshi/ebiten/v2/internal/debug"
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicscommand"
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
+	"github.com/hajimehoshi/ebiten/v2/internal/logger"
 )
 
 // forceRestoring reports whether restoring forcely happens or not.
@@ -41,10 +43,14 @@ func EnableRestoringForTesting() {
 
 // images is a set of Image objects.
 type images struct {
-	images      map[*Image]struct{}
-	shaders     map[*Shader]struct{}
-	lastTarget  *Image
-	contextLost bool
+	images     map[*Image]struct{}
+	shaders    map[*Shader]struct{}
+	lastTarget *Image
+
+	// contextLost is 1 when the context lost is notified and the images are not restored yet.
+	// contextLost is accessed atomically since OnContextLost might be called from a different thread,
+	// e.g., the GL thread on Android.
+	contextLost int32
 }
 
 // theImages represents the images for the current process.
@@ -88,7 +94,8 @@ func RestoreIfNeeded() error {
 		var r bool
 
 		if canDetectContextLostExplicitly {
-			r = theImages.contextLost
+			// Reset the flag before restoring so that a context lost notified during restoring is not missed.
+			r = atomic.SwapInt32(&theImages.contextLost, 0) != 0
 		} else {
 			// As isInvalidated() is expensive, call this only for one image.
 			// This assumes that if there is one image that is invalidated, all images are invalidated.
@@ -111,14 +118,23 @@ func RestoreIfNeeded() error {
 		}
 	}
 
+	logger.Warn("graphics context was lost")
 	err := graphicscommand.ResetGraphicsDriverState()
+	if err == nil {
+		err = theImages.restore()
+	}
+	if err != nil && canDetectContextLostExplicitly {
+		// Restoring is not completed. Try again next time.
+		atomic.StoreInt32(&theImages.contextLost, 1)
+		logger.Warn("restoring graphics context failed; retrying at the next frame", "error", err)
+	}
 	if err == graphicsdriver.GraphicsNotReady {
 		return nil
 	}
-	if err != nil {
-		return err
+	if err == nil {
+		logger.Info("graphics context was restored", "images", len(theImages.images))
 	}
-	return theImages.restore()
+	return err
 }
 
 // DumpImages dumps all the current images to the specified directory.
@@ -272,8 +288,6 @@ func (i *images) restore() error {
 		}
 	}
 
-	i.contextLost = false
-
 	return nil
 }
 
@@ -290,10 +304,52 @@ func MaxImageSize() int {
 	return graphicscommand.MaxImageSize()
 }
 
+// IsCompressedTextureFormatSupported reports whether the given compressed texture format is supported.
+func IsCompressedTextureFormatSupported(format graphicsdriver.CompressedTextureFormat) bool {
+	return graphicscommand.IsCompressedTextureFormatSupported(format)
+}
+
+// allocatedTextureCount is the number of the images allocated since the program started.
+//
+// Textures recreated for restoring and temporary textures for reading pixels are not counted.
+// allocatedTextureCount must be accessed atomically.
+var allocatedTextureCount int64
+
+// TextureStats returns the number of the textures, the estimated memory usage of them in bytes,
+// and the number of the textures allocated since the program started.
+//
+// Textures allocated internally for restoring are not counted as allocated textures.
+func TextureStats() (count int, bytes int64, allocated int) {
+	count, bytes = graphicscommand.TextureStats()
+	return count, bytes, int(atomic.LoadInt64(&allocatedTextureCount))
+}
+
 // OnContextLost is called when the context lost is detected in an explicit way.
+//
+// OnContextLost can be called from any thread, and can be called multiple times before the images are restored.
 func OnContextLost() {
 	if !canDetectContextLostExplicitly {
 		panic("restorable: OnContextLost cannot be called in this environment")
 	}
-	theImages.contextLost = true
+	atomic.StoreInt32(&theImages.contextLost, 1)
+}
+
+// LoseContextForTesting emulates losing the graphics context for testing.
+//
+// The textures of all the images are cleared, as the contents of textures are lost with the context.
+// The empty image is kept as it is needed to clear the textures.
+func LoseContextForTesting() error {
+	for img := range theImages.images {
+		if img.screen || img == emptyImage {
+			continue
+		}
+		clearImage(img.image)
+	}
+	if err := graphicscommand.FlushCommands(); err != nil {
+		return err
+	}
+	if canDetectContextLostExplicitly {
+		OnContextLost()
+	}
+	return nil
 }
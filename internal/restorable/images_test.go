@@ -15,8 +15,10 @@
 package restorable_test
 
 import (
+	"bytes"
 	"image"
 	"image/color"
+	"strings"
 	"testing"
 
 	"github.com/hajimehoshi/ebiten/v2/internal/affine"
@@ -108,10 +110,10 @@ func quadVertices(sw, sh, x, y int) []float32 {
 	sx1 := float32(sw)
 	sy1 := float32(sh)
 	return []float32{
-		dx0, dy0, sx0, sy0, 1, 1, 1, 1,
-		dx1, dy0, sx1, sy0, 1, 1, 1, 1,
-		dx0, dy1, sx0, sy1, 1, 1, 1, 1,
-		dx1, dy1, sx1, sy1, 1, 1, 1, 1,
+		dx0, dy0, sx0, sy0, 1, 1, 1, 1, 0,
+		dx1, dy0, sx1, sy0, 1, 1, 1, 1, 0,
+		dx0, dy1, sx0, sy1, 1, 1, 1, 1, 0,
+		dx1, dy1, sx1, sy1, 1, 1, 1, 1, 0,
 	}
 }
 
@@ -138,7 +140,7 @@ func TestRestoreChain(t *testing.T) {
 			Width:  1,
 			Height: 1,
 		}
-		imgs[i+1].DrawTriangles([graphics.ShaderImageNum]*restorable.Image{imgs[i]}, [graphics.ShaderImageNum - 1][2]float32{}, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false)
+		imgs[i+1].DrawTriangles([graphics.ShaderImageNum]*restorable.Image{imgs[i]}, [graphics.ShaderImageNum - 1][2]float32{}, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false, false)
 	}
 	if err := restorable.ResolveStaleImages(); err != nil {
 		t.Fatal(err)
@@ -155,6 +157,67 @@ func TestRestoreChain(t *testing.T) {
 	}
 }
 
+func TestRestoreRepeatedly(t *testing.T) {
+	// Emulate the surface being destroyed and recreated many times, e.g., on Android.
+	const (
+		num      = 10
+		restores = 20
+	)
+	imgs := []*restorable.Image{}
+	for i := 0; i < num; i++ {
+		img := restorable.NewImage(1, 1)
+		imgs = append(imgs, img)
+	}
+	defer func() {
+		for _, img := range imgs {
+			img.Dispose()
+		}
+	}()
+	for n := 0; n < restores; n++ {
+		clr := color.RGBA{byte(n * 8), 0x00, 0x00, 0xff}
+		imgs[0].ReplacePixels([]byte{clr.R, clr.G, clr.B, clr.A}, 0, 0, 1, 1)
+		for i := 0; i < num-1; i++ {
+			vs := quadVertices(1, 1, 0, 0)
+			is := graphics.QuadIndices()
+			dr := graphicsdriver.Region{
+				X:      0,
+				Y:      0,
+				Width:  1,
+				Height: 1,
+			}
+			imgs[i+1].DrawTriangles([graphics.ShaderImageNum]*restorable.Image{imgs[i]}, [graphics.ShaderImageNum - 1][2]float32{}, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false, false)
+		}
+		if err := restorable.ResolveStaleImages(); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := restorable.LoseContextForTesting(); err != nil {
+			t.Fatal(err)
+		}
+		pix := make([]byte, 4)
+		if err := imgs[0].ReadPixelsFromGPUForTesting(pix); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := (color.RGBA{pix[0], pix[1], pix[2], pix[3]}), (color.RGBA{}); got != want {
+			t.Fatalf("restore %d: the texture must be lost: got %v, want %v", n, got, want)
+		}
+
+		if err := restorable.RestoreIfNeeded(); err != nil {
+			t.Fatal(err)
+		}
+		want := clr
+		for i, img := range imgs {
+			if err := img.ReadPixelsFromGPUForTesting(pix); err != nil {
+				t.Fatal(err)
+			}
+			got := color.RGBA{pix[0], pix[1], pix[2], pix[3]}
+			if !sameColors(got, want, 1) {
+				t.Errorf("restore %d: image %d: got %v, want %v", n, i, got, want)
+			}
+		}
+	}
+}
+
 func TestRestoreChain2(t *testing.T) {
 	const (
 		num = 10
@@ -186,10 +249,10 @@ func TestRestoreChain2(t *testing.T) {
 		Width:  w,
 		Height: h,
 	}
-	imgs[8].DrawTriangles([graphics.ShaderImageNum]*restorable.Image{imgs[7]}, [graphics.ShaderImageNum - 1][2]float32{}, quadVertices(w, h, 0, 0), is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false)
-	imgs[9].DrawTriangles([graphics.ShaderImageNum]*restorable.Image{imgs[8]}, [graphics.ShaderImageNum - 1][2]float32{}, quadVertices(w, h, 0, 0), is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false)
+	imgs[8].DrawTriangles([graphics.ShaderImageNum]*restorable.Image{imgs[7]}, [graphics.ShaderImageNum - 1][2]float32{}, quadVertices(w, h, 0, 0), is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false, false)
+	imgs[9].DrawTriangles([graphics.ShaderImageNum]*restorable.Image{imgs[8]}, [graphics.ShaderImageNum - 1][2]float32{}, quadVertices(w, h, 0, 0), is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false, false)
 	for i := 0; i < 7; i++ {
-		imgs[i+1].DrawTriangles([graphics.ShaderImageNum]*restorable.Image{imgs[i]}, [graphics.ShaderImageNum - 1][2]float32{}, quadVertices(w, h, 0, 0), is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false)
+		imgs[i+1].DrawTriangles([graphics.ShaderImageNum]*restorable.Image{imgs[i]}, [graphics.ShaderImageNum - 1][2]float32{}, quadVertices(w, h, 0, 0), is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false, false)
 	}
 
 	if err := restorable.ResolveStaleImages(); err != nil {
@@ -235,10 +298,10 @@ func TestRestoreOverrideSource(t *testing.T) {
 		Width:  w,
 		Height: h,
 	}
-	img2.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{img1}, [graphics.ShaderImageNum - 1][2]float32{}, quadVertices(w, h, 0, 0), is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeSourceOver, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false)
-	img3.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{img2}, [graphics.ShaderImageNum - 1][2]float32{}, quadVertices(w, h, 0, 0), is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeSourceOver, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false)
+	img2.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{img1}, [graphics.ShaderImageNum - 1][2]float32{}, quadVertices(w, h, 0, 0), is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeSourceOver, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false, false)
+	img3.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{img2}, [graphics.ShaderImageNum - 1][2]float32{}, quadVertices(w, h, 0, 0), is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeSourceOver, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false, false)
 	img0.ReplacePixels([]byte{clr1.R, clr1.G, clr1.B, clr1.A}, 0, 0, w, h)
-	img1.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{img0}, [graphics.ShaderImageNum - 1][2]float32{}, quadVertices(w, h, 0, 0), is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeSourceOver, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false)
+	img1.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{img0}, [graphics.ShaderImageNum - 1][2]float32{}, quadVertices(w, h, 0, 0), is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeSourceOver, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false, false)
 	if err := restorable.ResolveStaleImages(); err != nil {
 		t.Fatal(err)
 	}
@@ -324,23 +387,23 @@ func TestRestoreComplexGraph(t *testing.T) {
 		Height: h,
 	}
 	var offsets [graphics.ShaderImageNum - 1][2]float32
-	img3.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{img0}, offsets, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeSourceOver, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false)
+	img3.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{img0}, offsets, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeSourceOver, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false, false)
 	vs = quadVertices(w, h, 1, 0)
-	img3.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{img1}, offsets, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeSourceOver, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false)
+	img3.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{img1}, offsets, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeSourceOver, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false, false)
 	vs = quadVertices(w, h, 1, 0)
-	img4.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{img1}, offsets, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeSourceOver, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false)
+	img4.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{img1}, offsets, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeSourceOver, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false, false)
 	vs = quadVertices(w, h, 2, 0)
-	img4.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{img2}, offsets, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeSourceOver, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false)
+	img4.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{img2}, offsets, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeSourceOver, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false, false)
 	vs = quadVertices(w, h, 0, 0)
-	img5.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{img3}, offsets, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeSourceOver, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false)
+	img5.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{img3}, offsets, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeSourceOver, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false, false)
 	vs = quadVertices(w, h, 0, 0)
-	img6.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{img3}, offsets, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeSourceOver, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false)
+	img6.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{img3}, offsets, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeSourceOver, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false, false)
 	vs = quadVertices(w, h, 1, 0)
-	img6.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{img4}, offsets, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeSourceOver, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false)
+	img6.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{img4}, offsets, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeSourceOver, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false, false)
 	vs = quadVertices(w, h, 0, 0)
-	img7.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{img2}, offsets, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeSourceOver, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false)
+	img7.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{img2}, offsets, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeSourceOver, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false, false)
 	vs = quadVertices(w, h, 2, 0)
-	img7.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{img3}, offsets, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeSourceOver, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false)
+	img7.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{img3}, offsets, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeSourceOver, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false, false)
 	if err := restorable.ResolveStaleImages(); err != nil {
 		t.Fatal(err)
 	}
@@ -438,8 +501,8 @@ func TestRestoreRecursive(t *testing.T) {
 		Width:  w,
 		Height: h,
 	}
-	img1.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{img0}, [graphics.ShaderImageNum - 1][2]float32{}, quadVertices(w, h, 1, 0), is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeSourceOver, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false)
-	img0.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{img1}, [graphics.ShaderImageNum - 1][2]float32{}, quadVertices(w, h, 1, 0), is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeSourceOver, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false)
+	img1.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{img0}, [graphics.ShaderImageNum - 1][2]float32{}, quadVertices(w, h, 1, 0), is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeSourceOver, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false, false)
+	img0.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{img1}, [graphics.ShaderImageNum - 1][2]float32{}, quadVertices(w, h, 1, 0), is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeSourceOver, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false, false)
 	if err := restorable.ResolveStaleImages(); err != nil {
 		t.Fatal(err)
 	}
@@ -539,7 +602,7 @@ func TestDrawTrianglesAndReplacePixels(t *testing.T) {
 		Width:  2,
 		Height: 1,
 	}
-	img1.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{img0}, [graphics.ShaderImageNum - 1][2]float32{}, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false)
+	img1.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{img0}, [graphics.ShaderImageNum - 1][2]float32{}, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false, false)
 	img1.ReplacePixels([]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, 0, 0, 2, 1)
 
 	if err := restorable.ResolveStaleImages(); err != nil {
@@ -582,8 +645,8 @@ func TestDispose(t *testing.T) {
 		Width:  1,
 		Height: 1,
 	}
-	img1.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{img2}, [graphics.ShaderImageNum - 1][2]float32{}, quadVertices(1, 1, 0, 0), is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false)
-	img0.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{img1}, [graphics.ShaderImageNum - 1][2]float32{}, quadVertices(1, 1, 0, 0), is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false)
+	img1.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{img2}, [graphics.ShaderImageNum - 1][2]float32{}, quadVertices(1, 1, 0, 0), is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false, false)
+	img0.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{img1}, [graphics.ShaderImageNum - 1][2]float32{}, quadVertices(1, 1, 0, 0), is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false, false)
 	img1.Dispose()
 
 	if err := restorable.ResolveStaleImages(); err != nil {
@@ -697,7 +760,7 @@ func TestReplacePixelsOnly(t *testing.T) {
 		Width:  1,
 		Height: 1,
 	}
-	img1.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{img0}, [graphics.ShaderImageNum - 1][2]float32{}, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false)
+	img1.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{img0}, [graphics.ShaderImageNum - 1][2]float32{}, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false, false)
 	img0.ReplacePixels([]byte{5, 6, 7, 8}, 0, 0, 1, 1)
 
 	// BasePixelsForTesting is available without GPU accessing.
@@ -757,7 +820,7 @@ func TestReadPixelsFromVolatileImage(t *testing.T) {
 		Width:  w,
 		Height: h,
 	}
-	dst.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{src}, [graphics.ShaderImageNum - 1][2]float32{}, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false)
+	dst.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{src}, [graphics.ShaderImageNum - 1][2]float32{}, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false, false)
 
 	// Read the pixels. If the implementation is correct, dst tries to read its pixels from GPU due to being
 	// stale.
@@ -784,7 +847,7 @@ func TestAllowReplacePixelsAfterDrawTriangles(t *testing.T) {
 		Width:  w,
 		Height: h,
 	}
-	dst.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{src}, [graphics.ShaderImageNum - 1][2]float32{}, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeSourceOver, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false)
+	dst.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{src}, [graphics.ShaderImageNum - 1][2]float32{}, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeSourceOver, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false, false)
 	dst.ReplacePixels(make([]byte, 4*w*h), 0, 0, w, h)
 	// ReplacePixels for a whole image doesn't panic.
 }
@@ -808,7 +871,7 @@ func TestDisallowReplacePixelsForPartAfterDrawTriangles(t *testing.T) {
 		Width:  w,
 		Height: h,
 	}
-	dst.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{src}, [graphics.ShaderImageNum - 1][2]float32{}, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeSourceOver, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false)
+	dst.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{src}, [graphics.ShaderImageNum - 1][2]float32{}, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeSourceOver, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false, false)
 	dst.ReplacePixels(make([]byte, 4), 0, 0, 1, 1)
 }
 
@@ -885,7 +948,7 @@ func TestMutateSlices(t *testing.T) {
 		Width:  w,
 		Height: h,
 	}
-	dst.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{src}, [graphics.ShaderImageNum - 1][2]float32{}, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeSourceOver, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false)
+	dst.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{src}, [graphics.ShaderImageNum - 1][2]float32{}, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeSourceOver, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false, false)
 	for i := range vs {
 		vs[i] = 0
 	}
@@ -917,3 +980,244 @@ func TestMutateSlices(t *testing.T) {
 		}
 	}
 }
+
+func TestDumpHistory(t *testing.T) {
+	src := restorable.NewImage(1, 1)
+	defer src.Dispose()
+	dst := restorable.NewImage(16, 16)
+	defer dst.Dispose()
+
+	src.ReplacePixels([]byte{0xff, 0xff, 0xff, 0xff}, 0, 0, 1, 1)
+	vs := quadVertices(1, 1, 4, 8)
+	is := graphics.QuadIndices()
+	dr := graphicsdriver.Region{
+		X:      0,
+		Y:      0,
+		Width:  16,
+		Height: 16,
+	}
+	dst.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{src}, [graphics.ShaderImageNum - 1][2]float32{}, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false, false)
+
+	var buf bytes.Buffer
+	if err := dst.DumpHistory(&buf, image.Rect(0, 0, 16, 16)); err != nil {
+		t.Fatal(err)
+	}
+	got := buf.String()
+	for _, want := range []string{"#0:", "dst bounds: (4, 8)-(5, 9)", "mode: copy", "filter: nearest"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("DumpHistory: got %q; want to contain %q", got, want)
+		}
+	}
+
+	// A region not overlapping with the draw call should not have history items.
+	buf.Reset()
+	if err := dst.DumpHistory(&buf, image.Rect(16, 16, 32, 32)); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); strings.Contains(got, "#0:") {
+		t.Errorf("DumpHistory: got %q; want not to contain a history item", got)
+	}
+}
+
+func TestRestoreDepthTest(t *testing.T) {
+	const w, h = 4, 4
+
+	src0 := restorable.NewImage(w, h)
+	defer src0.Dispose()
+	src1 := restorable.NewImage(w, h)
+	defer src1.Dispose()
+	dst := restorable.NewImage(w, h)
+	defer dst.Dispose()
+
+	pix0 := make([]byte, 4*w*h)
+	pix1 := make([]byte, 4*w*h)
+	for i := 0; i < w*h; i++ {
+		pix0[4*i] = 0xff
+		pix0[4*i+3] = 0xff
+		pix1[4*i+1] = 0xff
+		pix1[4*i+3] = 0xff
+	}
+	src0.ReplacePixels(pix0, 0, 0, w, h)
+	src1.ReplacePixels(pix1, 0, 0, w, h)
+
+	dr := graphicsdriver.Region{
+		X:      0,
+		Y:      0,
+		Width:  w,
+		Height: h,
+	}
+	draw := func(src *restorable.Image, depth float32) {
+		vs := quadVertices(w, h, 0, 0)
+		for i := 0; i < len(vs)/graphics.VertexFloatNum; i++ {
+			vs[i*graphics.VertexFloatNum+8] = depth
+		}
+		is := graphics.QuadIndices()
+		dst.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{src}, [graphics.ShaderImageNum - 1][2]float32{}, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false, true)
+	}
+
+	for i := 0; i < 2; i++ {
+		// The second iteration confirms the depth buffer is reproducible after the image is resolved.
+		dst.ClearDepth()
+
+		// The second draw call is behind the first one and is not rendered.
+		draw(src0, 0.25)
+		draw(src1, 0.5)
+
+		var buf bytes.Buffer
+		if err := dst.DumpHistory(&buf, image.Rect(0, 0, w, h)); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := buf.String(), "depth-test: true"; !strings.Contains(got, want) {
+			t.Errorf("DumpHistory: got %q; want to contain %q", got, want)
+		}
+
+		if err := restorable.ResolveStaleImages(); err != nil {
+			t.Fatal(err)
+		}
+		if err := restorable.RestoreIfNeeded(); err != nil {
+			t.Fatal(err)
+		}
+
+		want := color.RGBA{0xff, 0, 0, 0xff}
+		for j := 0; j < h; j++ {
+			for i := 0; i < w; i++ {
+				got := pixelsToColor(dst.BasePixelsForTesting(), i, j)
+				if !sameColors(got, want, 1) {
+					t.Errorf("(%d, %d): got %v, want %v", i, j, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestRestoreCopyPixels(t *testing.T) {
+	const w, h = 4, 4
+
+	src := restorable.NewImage(w, h)
+	defer src.Dispose()
+	dst := restorable.NewImage(w, h)
+	defer dst.Dispose()
+
+	pix := make([]byte, 4*w*h)
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			idx := 4 * (i + w*j)
+			pix[idx] = byte(0x10 * i)
+			pix[idx+1] = byte(0x10 * j)
+			pix[idx+3] = 0x80
+		}
+	}
+	src.ReplacePixels(pix, 0, 0, w, h)
+
+	// Copy the upper-left 2x2 pixels to the lower-right.
+	dst.CopyPixels(src, 2, 2, 0, 0, 2, 2)
+
+	if err := restorable.ResolveStaleImages(); err != nil {
+		t.Fatal(err)
+	}
+	if err := restorable.RestoreIfNeeded(); err != nil {
+		t.Fatal(err)
+	}
+
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := pixelsToColor(dst.BasePixelsForTesting(), i, j)
+			var want color.RGBA
+			if i >= 2 && j >= 2 {
+				want = color.RGBA{byte(0x10 * (i - 2)), byte(0x10 * (j - 2)), 0, 0x80}
+			}
+			if !sameColors(got, want, 0) {
+				t.Errorf("(%d, %d): got %v, want %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestRestoreFillRect(t *testing.T) {
+	const w, h = 4, 4
+
+	img := restorable.NewImage(w, h)
+	defer img.Dispose()
+
+	img.FillRect(1, 1, 2, 3, 0.5, 0, 0, 0.5)
+
+	if err := restorable.ResolveStaleImages(); err != nil {
+		t.Fatal(err)
+	}
+	if err := restorable.RestoreIfNeeded(); err != nil {
+		t.Fatal(err)
+	}
+
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := pixelsToColor(img.BasePixelsForTesting(), i, j)
+			var want color.RGBA
+			if 1 <= i && i < 3 && 1 <= j {
+				want = color.RGBA{0x80, 0, 0, 0x80}
+			}
+			if !sameColors(got, want, 1) {
+				t.Errorf("(%d, %d): got %v, want %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestTextureStatsRestore(t *testing.T) {
+	img := restorable.NewImage(16, 16)
+	defer img.Dispose()
+
+	if err := restorable.ResolveStaleImages(); err != nil {
+		t.Fatal(err)
+	}
+	count0, bytes0, allocated0 := restorable.TextureStats()
+
+	if err := restorable.RestoreIfNeeded(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Restoring recreates the textures, but this must not be counted as new allocations.
+	count1, bytes1, allocated1 := restorable.TextureStats()
+	if count1 != count0 {
+		t.Errorf("count: got: %d, want: %d", count1, count0)
+	}
+	if bytes1 != bytes0 {
+		t.Errorf("bytes: got: %d, want: %d", bytes1, bytes0)
+	}
+	if allocated1 != allocated0 {
+		t.Errorf("allocated: got: %d, want: %d", allocated1, allocated0)
+	}
+}
+
+func TestDrawTrianglesWithoutArea(t *testing.T) {
+	const w, h = 4, 4
+
+	src := restorable.NewImage(w, h)
+	defer src.Dispose()
+	dst := restorable.NewImage(w, h)
+	defer dst.Dispose()
+
+	if err := restorable.ResolveStaleImages(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A degenerate quad whose vertices are all at the same position.
+	vs := quadVertices(0, 0, 1, 1)
+	is := graphics.QuadIndices()
+	dr := graphicsdriver.Region{
+		X:      0,
+		Y:      0,
+		Width:  w,
+		Height: h,
+	}
+	for _, mode := range []graphicsdriver.CompositeMode{graphicsdriver.CompositeModeSourceOver, graphicsdriver.CompositeModeCopy, graphicsdriver.CompositeModeClear} {
+		dst.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{src}, [graphics.ShaderImageNum - 1][2]float32{}, vs, is, affine.ColorMIdentity{}, mode, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false, false)
+	}
+	if got, want := dst.DrawTrianglesHistoryLenForTesting(), 0; got != want {
+		t.Errorf("history length: got: %d, want: %d", got, want)
+	}
+
+	dst.DrawTriangles([graphics.ShaderImageNum]*restorable.Image{src}, [graphics.ShaderImageNum - 1][2]float32{}, quadVertices(w, h, 0, 0), is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeSourceOver, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false, false)
+	if got, want := dst.DrawTrianglesHistoryLenForTesting(), 1; got != want {
+		t.Errorf("history length: got: %d, want: %d", got, want)
+	}
+}
@@ -0,0 +1,314 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shader
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+)
+
+// noiseFuncNames is the names of the builtin noise functions.
+// The implementations must be the same as the package exp/noise's.
+var noiseFuncNames = map[string]struct{}{
+	"hash2":    {},
+	"hash3":    {},
+	"perlin2":  {},
+	"simplex2": {},
+	"simplex3": {},
+}
+
+// noiseSrc is the Kage source of the builtin noise functions.
+// The helper functions' names start with __ so that they don't conflict with user functions.
+const noiseSrc = `package main
+
+func __noiseMod289(x float) float {
+	r := x - floor(x*(1.0/289.0))*289
+	if r < 0 {
+		r += 289
+	}
+	if r >= 289 {
+		r -= 289
+	}
+	return r
+}
+
+func __noisePermute(x float) float {
+	return __noiseMod289((x*34 + 1) * x)
+}
+
+func __noiseCell2(i, j float) float {
+	return __noisePermute(__noiseMod289(__noisePermute(i) + j))
+}
+
+func __noiseCell3(i, j, k float) float {
+	return __noisePermute(__noiseMod289(__noiseCell2(i, j) + k))
+}
+
+func __noiseGrad2(h, x, y float) float {
+	k := h - floor(h*0.25)*4
+	gx := x
+	if k >= 2 {
+		gx = -x
+	}
+	gy := y
+	if k-floor(k*0.5)*2 >= 1 {
+		gy = -y
+	}
+	return gx + gy
+}
+
+func __noiseGrad3(h, x, y, z float) float {
+	k := h - floor(h*0.0625)*16
+	u := y
+	if k < 8 {
+		u = x
+	}
+	v := z
+	if k < 4 {
+		v = y
+	} else if k == 12 || k == 14 {
+		v = x
+	}
+	if k-floor(k*0.5)*2 >= 1 {
+		u = -u
+	}
+	k2 := floor(k * 0.5)
+	if k2-floor(k2*0.5)*2 >= 1 {
+		v = -v
+	}
+	return u + v
+}
+
+func __noiseFade(t float) float {
+	return t * t * t * (t*(t*6-15) + 10)
+}
+
+func hash2(p vec2) float {
+	i := __noiseMod289(floor(p.x))
+	j := __noiseMod289(floor(p.y))
+	a := __noiseCell2(i, j)
+	b := __noiseCell2(__noiseMod289(j+113), i)
+	return (a*289 + b) * (1.0 / 83521.0)
+}
+
+func hash3(p vec3) float {
+	i := __noiseMod289(floor(p.x))
+	j := __noiseMod289(floor(p.y))
+	k := __noiseMod289(floor(p.z))
+	a := __noiseCell3(i, j, k)
+	b := __noiseCell3(__noiseMod289(k+113), i, j)
+	return (a*289 + b) * (1.0 / 83521.0)
+}
+
+func perlin2(p vec2) float {
+	fx := floor(p.x)
+	fy := floor(p.y)
+	dx := p.x - fx
+	dy := p.y - fy
+	i0 := __noiseMod289(fx)
+	i1 := __noiseMod289(fx + 1)
+	j0 := __noiseMod289(fy)
+	j1 := __noiseMod289(fy + 1)
+
+	n00 := __noiseGrad2(__noiseCell2(i0, j0), dx, dy)
+	n10 := __noiseGrad2(__noiseCell2(i1, j0), dx-1, dy)
+	n01 := __noiseGrad2(__noiseCell2(i0, j1), dx, dy-1)
+	n11 := __noiseGrad2(__noiseCell2(i1, j1), dx-1, dy-1)
+
+	u := __noiseFade(dx)
+	v := __noiseFade(dy)
+	nx0 := n00 + (n10-n00)*u
+	nx1 := n01 + (n11-n01)*u
+	return nx0 + (nx1-nx0)*v
+}
+
+func simplex2(p vec2) float {
+	s := (p.x + p.y) * 0.36602540
+	i := floor(p.x + s)
+	j := floor(p.y + s)
+	t := (i + j) * 0.21132487
+	x0 := p.x - (i - t)
+	y0 := p.y - (j - t)
+
+	i1 := 0.0
+	j1 := 0.0
+	if x0 > y0 {
+		i1 = 1
+	} else {
+		j1 = 1
+	}
+
+	x1 := x0 - i1 + 0.21132487
+	y1 := y0 - j1 + 0.21132487
+	x2 := x0 - 1 + 2*0.21132487
+	y2 := y0 - 1 + 2*0.21132487
+
+	ii := __noiseMod289(i)
+	jj := __noiseMod289(j)
+
+	n := 0.0
+	t0 := 0.5 - x0*x0 - y0*y0
+	if t0 > 0 {
+		n += t0 * t0 * t0 * t0 * __noiseGrad2(__noiseCell2(ii, jj), x0, y0)
+	}
+	t1 := 0.5 - x1*x1 - y1*y1
+	if t1 > 0 {
+		n += t1 * t1 * t1 * t1 * __noiseGrad2(__noiseCell2(__noiseMod289(ii+i1), __noiseMod289(jj+j1)), x1, y1)
+	}
+	t2 := 0.5 - x2*x2 - y2*y2
+	if t2 > 0 {
+		n += t2 * t2 * t2 * t2 * __noiseGrad2(__noiseCell2(__noiseMod289(ii+1), __noiseMod289(jj+1)), x2, y2)
+	}
+	return n * 70
+}
+
+func simplex3(p vec3) float {
+	s := (p.x + p.y + p.z) * 0.33333333
+	i := floor(p.x + s)
+	j := floor(p.y + s)
+	k := floor(p.z + s)
+	t := (i + j + k) * 0.16666667
+	x0 := p.x - (i - t)
+	y0 := p.y - (j - t)
+	z0 := p.z - (k - t)
+
+	i1 := 0.0
+	j1 := 0.0
+	k1 := 0.0
+	i2 := 0.0
+	j2 := 0.0
+	k2 := 0.0
+	if x0 >= y0 {
+		if y0 >= z0 {
+			i1 = 1
+			i2 = 1
+			j2 = 1
+		} else if x0 >= z0 {
+			i1 = 1
+			i2 = 1
+			k2 = 1
+		} else {
+			k1 = 1
+			i2 = 1
+			k2 = 1
+		}
+	} else {
+		if y0 < z0 {
+			k1 = 1
+			j2 = 1
+			k2 = 1
+		} else if x0 < z0 {
+			j1 = 1
+			j2 = 1
+			k2 = 1
+		} else {
+			j1 = 1
+			i2 = 1
+			j2 = 1
+		}
+	}
+
+	x1 := x0 - i1 + 0.16666667
+	y1 := y0 - j1 + 0.16666667
+	z1 := z0 - k1 + 0.16666667
+	x2 := x0 - i2 + 2*0.16666667
+	y2 := y0 - j2 + 2*0.16666667
+	z2 := z0 - k2 + 2*0.16666667
+	x3 := x0 - 1 + 3*0.16666667
+	y3 := y0 - 1 + 3*0.16666667
+	z3 := z0 - 1 + 3*0.16666667
+
+	ii := __noiseMod289(i)
+	jj := __noiseMod289(j)
+	kk := __noiseMod289(k)
+
+	n := 0.0
+	t0 := 0.6 - x0*x0 - y0*y0 - z0*z0
+	if t0 > 0 {
+		n += t0 * t0 * t0 * t0 * __noiseGrad3(__noiseCell3(ii, jj, kk), x0, y0, z0)
+	}
+	t1 := 0.6 - x1*x1 - y1*y1 - z1*z1
+	if t1 > 0 {
+		n += t1 * t1 * t1 * t1 * __noiseGrad3(__noiseCell3(__noiseMod289(ii+i1), __noiseMod289(jj+j1), __noiseMod289(kk+k1)), x1, y1, z1)
+	}
+	t2 := 0.6 - x2*x2 - y2*y2 - z2*z2
+	if t2 > 0 {
+		n += t2 * t2 * t2 * t2 * __noiseGrad3(__noiseCell3(__noiseMod289(ii+i2), __noiseMod289(jj+j2), __noiseMod289(kk+k2)), x2, y2, z2)
+	}
+	t3 := 0.6 - x3*x3 - y3*y3 - z3*z3
+	if t3 > 0 {
+		n += t3 * t3 * t3 * t3 * __noiseGrad3(__noiseCell3(__noiseMod289(ii+1), __noiseMod289(jj+1), __noiseMod289(kk+1)), x3, y3, z3)
+	}
+	return n * 32
+}
+`
+
+// addNoiseFuncs returns a file with the builtin noise functions' declarations if f calls any of them.
+//
+// A noise function declared in f is not added, and then the user's function is used.
+func addNoiseFuncs(fs *token.FileSet, f *ast.File) (*ast.File, error) {
+	declared := map[string]struct{}{}
+	for _, d := range f.Decls {
+		if fd, ok := d.(*ast.FuncDecl); ok {
+			declared[fd.Name.Name] = struct{}{}
+		}
+	}
+
+	var used bool
+	ast.Inspect(f, func(n ast.Node) bool {
+		if used {
+			return false
+		}
+		c, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		id, ok := c.Fun.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		if _, ok := noiseFuncNames[id.Name]; !ok {
+			return true
+		}
+		if _, ok := declared[id.Name]; ok {
+			return true
+		}
+		used = true
+		return false
+	})
+	if !used {
+		return f, nil
+	}
+
+	nf, err := parser.ParseFile(fs, "noise", noiseSrc, parser.AllErrors)
+	if err != nil {
+		return nil, err
+	}
+
+	newF := *f
+	newF.Decls = append([]ast.Decl{}, f.Decls...)
+	for _, d := range nf.Decls {
+		fd, ok := d.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		if _, ok := declared[fd.Name.Name]; ok {
+			continue
+		}
+		newF.Decls = append(newF.Decls, fd)
+	}
+	return &newF, nil
+}
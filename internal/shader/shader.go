@@ -188,6 +188,11 @@ func Compile(fs *token.FileSet, f *ast.File, vertexEntry, fragmentEntry string,
 		fragmentEntry: fragmentEntry,
 	}
 	s.global.ir = &shaderir.Block{}
+
+	f, err := addNoiseFuncs(fs, f)
+	if err != nil {
+		return nil, err
+	}
 	s.parse(f)
 
 	if len(s.errs) > 0 {
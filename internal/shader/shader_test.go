@@ -188,3 +188,68 @@ func TestCompile(t *testing.T) {
 		})
 	}
 }
+
+func TestNoiseFuncs(t *testing.T) {
+	const header = `package main
+
+func Vertex(position vec2) vec4 {
+	return vec4(position, 0, 1)
+}
+`
+	cases := []struct {
+		Name    string
+		Src     string
+		NumFunc int
+	}{
+		{
+			Name: "unused",
+			Src: header + `
+func Fragment(position vec4) vec4 {
+	return vec4(position.xy, 0, 1)
+}
+`,
+			NumFunc: 0,
+		},
+		{
+			Name: "all",
+			Src: header + `
+func Fragment(position vec4) vec4 {
+	return vec4(hash2(position.xy), hash3(position.xyz), perlin2(position.xy), simplex2(position.xy)+simplex3(position.xyz))
+}
+`,
+			NumFunc: 12,
+		},
+		{
+			Name: "user-defined",
+			Src: header + `
+func hash2(p vec2) float {
+	return 0
+}
+
+func Fragment(position vec4) vec4 {
+	return vec4(hash2(position.xy), perlin2(position.xy), 0, 1)
+}
+`,
+			NumFunc: 12,
+		},
+	}
+
+	for _, c := range cases {
+		fset := token.NewFileSet()
+		f, err := parser.ParseFile(fset, "", c.Src, parser.AllErrors)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s, err := shader.Compile(fset, f, "Vertex", "Fragment", 0)
+		if err != nil {
+			t.Errorf("%s: %v", c.Name, err)
+			continue
+		}
+		if got, want := len(s.Funcs), c.NumFunc; got != want {
+			t.Errorf("%s: len(s.Funcs): got: %d, want: %d", c.Name, got, want)
+		}
+		// Just check that Compile doesn't cause panic.
+		glsl.Compile(s, glsl.GLSLVersionDefault)
+		metal.Compile(s, "Vertex", "Fragment")
+	}
+}
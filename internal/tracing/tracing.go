@@ -0,0 +1,96 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tracing emits runtime/trace tasks and regions for the engine internals, and optionally pprof labels.
+package tracing
+
+import (
+	"context"
+	"os"
+	"runtime/pprof"
+	"runtime/trace"
+	"sync"
+	"unsafe"
+)
+
+// runtimeGetProfLabel and runtimeSetProfLabel are the runtime functions behind pprof.SetGoroutineLabels.
+// runtime/pprof has no API to get the current labels of a goroutine, so go:linkname is used to save and restore them.
+
+//go:linkname runtimeGetProfLabel runtime/pprof.runtime_getProfLabel
+func runtimeGetProfLabel() unsafe.Pointer
+
+//go:linkname runtimeSetProfLabel runtime/pprof.runtime_setProfLabel
+func runtimeSetProfLabel(labels unsafe.Pointer)
+
+var pprofLabelsEnabled = os.Getenv("EBITEN_PPROF_LABELS") == "1"
+
+var (
+	frameCtx  = context.Background()
+	frameTask *trace.Task
+	m         sync.Mutex
+)
+
+func noop() {}
+
+// BeginFrame ends the task of the previous frame, and starts a task for a new frame.
+// The regions started until the next BeginFrame belong to the task.
+func BeginFrame() {
+	m.Lock()
+	defer m.Unlock()
+
+	if frameTask != nil {
+		frameTask.End()
+		frameTask = nil
+	}
+	frameCtx = context.Background()
+
+	if !trace.IsEnabled() {
+		return
+	}
+	frameCtx, frameTask = trace.NewTask(context.Background(), "ebiten.frame")
+}
+
+// StartRegion starts a region with the name, and returns a function to end the region.
+// The region and the end function must be on the same goroutine.
+//
+// If the environment variable EBITEN_PPROF_LABELS is 1, the goroutine has the pprof label "ebiten" with the name
+// during the region. The function to end the region restores the pprof labels the goroutine had before the region.
+func StartRegion(name string) func() {
+	tracing := trace.IsEnabled()
+	if !tracing && !pprofLabelsEnabled {
+		return noop
+	}
+
+	m.Lock()
+	ctx := frameCtx
+	m.Unlock()
+
+	var r *trace.Region
+	if tracing {
+		r = trace.StartRegion(ctx, name)
+	}
+	var prevLabels unsafe.Pointer
+	if pprofLabelsEnabled {
+		prevLabels = runtimeGetProfLabel()
+		pprof.SetGoroutineLabels(pprof.WithLabels(ctx, pprof.Labels("ebiten", name)))
+	}
+	return func() {
+		if r != nil {
+			r.End()
+		}
+		if pprofLabelsEnabled {
+			runtimeSetProfLabel(prevLabels)
+		}
+	}
+}
@@ -18,22 +18,29 @@ import (
 	"math"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2/internal/buffered"
 	"github.com/hajimehoshi/ebiten/v2/internal/clock"
 	"github.com/hajimehoshi/ebiten/v2/internal/debug"
+	"github.com/hajimehoshi/ebiten/v2/internal/frametiming"
 	graphicspkg "github.com/hajimehoshi/ebiten/v2/internal/graphics"
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicscommand"
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
 	"github.com/hajimehoshi/ebiten/v2/internal/hooks"
+	"github.com/hajimehoshi/ebiten/v2/internal/tracing"
 )
 
 const DefaultTPS = 60
 
 type Game interface {
-	Layout(outsideWidth, outsideHeight float64, deviceScaleFactor float64) (int, int)
+	Layout(outsideWidth, outsideHeight float64, deviceScaleFactor float64) (int, int, error)
 	Update() error
 	Draw(screenScale float64, offsetX, offsetY float64, needsClearingScreen bool, framebufferYDirection graphicsdriver.YDirection, screenClearedEveryFrame, filterEnabled bool) error
+	Suspend()
+	Resume()
+	RefreshRateChanged(hz float64)
+	KeyboardLayoutChanged()
 }
 
 type contextImpl struct {
@@ -41,6 +48,14 @@ type contextImpl struct {
 
 	updateCalled bool
 
+	suspended int32
+
+	// refreshRate is the refresh rate last notified to the game.
+	refreshRate float64
+
+	// keyboardLayoutCount is the count of keyboard layout changes last notified to the game.
+	keyboardLayoutCount int32
+
 	// The following members must be protected by the mutex m.
 	outsideWidth  float64
 	outsideHeight float64
@@ -56,9 +71,21 @@ func newContextImpl(game Game) *contextImpl {
 	}
 }
 
+// RunningGame returns the game passed to Run, or nil if no game has started.
+//
+// RunningGame must be called from the game's Update or Draw.
+func (u *UserInterface) RunningGame() Game {
+	if u.context == nil {
+		return nil
+	}
+	return u.context.game
+}
+
 func (c *contextImpl) updateFrame(outsideWidth, outsideHeight float64, deviceScaleFactor float64) error {
 	// TODO: If updateCount is 0 and vsync is disabled, swapping buffers can be skipped.
-	return c.updateFrameImpl(clock.Update(theGlobalState.maxTPS()), outsideWidth, outsideHeight, deviceScaleFactor)
+	num, den := theGlobalState.maxTPSRational()
+	count := clock.Update(num, den, theGlobalState.maxUpdatesPerFrame(), theGlobalState.frameSkipPolicy() == FrameSkipPolicySlowMotion)
+	return c.updateFrameImpl(count, outsideWidth, outsideHeight, deviceScaleFactor)
 }
 
 func (c *contextImpl) forceUpdateFrame(outsideWidth, outsideHeight float64, deviceScaleFactor float64) error {
@@ -69,6 +96,10 @@ func (c *contextImpl) updateFrameImpl(updateCount int, outsideWidth, outsideHeig
 	if err := theGlobalState.err(); err != nil {
 		return err
 	}
+	// Check the termination regardless of the update count. The previous frame is already flushed here.
+	if theGlobalState.isTerminationRequested() {
+		return RegularTermination
+	}
 
 	// The given outside size can be 0 e.g. just after restoring from the fullscreen mode on Windows (#1589)
 	// Just ignore such cases. Otherwise, creating a zero-sized framebuffer causes a panic.
@@ -77,16 +108,27 @@ func (c *contextImpl) updateFrameImpl(updateCount int, outsideWidth, outsideHeig
 	}
 
 	// ForceUpdate can be invoked even if the context is not initialized yet (#1591).
-	if w, h := c.layoutGame(outsideWidth, outsideHeight, deviceScaleFactor); w == 0 || h == 0 {
+	w, h, err := c.layoutGame(outsideWidth, outsideHeight, deviceScaleFactor)
+	if err != nil {
+		return err
+	}
+	if w == 0 || h == 0 {
 		return nil
 	}
 
 	debug.Logf("----\n")
 
+	// Record the previous frame including the time to present it.
+	frametiming.Commit()
+	tracing.BeginFrame()
+
 	if err := buffered.BeginFrame(); err != nil {
 		return err
 	}
 
+	// Notify a spike after BeginFrame so that the handler can use images.
+	frametiming.NotifySpike()
+
 	// Ensure that Update is called once before Draw so that Update can be used for initialization.
 	if !c.updateCalled && updateCount == 0 {
 		updateCount = 1
@@ -94,22 +136,46 @@ func (c *contextImpl) updateFrameImpl(updateCount int, outsideWidth, outsideHeig
 	}
 	debug.Logf("Update count per frame: %d\n", updateCount)
 
+	if r := theGlobalState.refreshRate(); r != c.refreshRate {
+		c.refreshRate = r
+		c.game.RefreshRateChanged(r)
+	}
+	if n := theGlobalState.keyboardLayoutCount(); n != c.keyboardLayoutCount {
+		c.keyboardLayoutCount = n
+		c.game.KeyboardLayoutChanged()
+	}
+
 	// Update the game.
+	// The time to flush commands, e.g. by At, is counted in PhaseGPU, so exclude it from the other phases.
+	t := time.Now()
+	gpu := frametiming.Current(frametiming.PhaseGPU)
+	endRegion := tracing.StartRegion("ebiten.update")
 	for i := 0; i < updateCount; i++ {
 		if err := hooks.RunBeforeUpdateHooks(); err != nil {
+			endRegion()
 			return err
 		}
 		if err := c.game.Update(); err != nil {
+			endRegion()
 			return err
 		}
 		Get().resetForTick()
 	}
+	endRegion()
+	frametiming.Add(frametiming.PhaseUpdate, time.Since(t)-(frametiming.Current(frametiming.PhaseGPU)-gpu))
+	frametiming.AddUpdateCount(updateCount)
 
 	// Draw the game.
+	t = time.Now()
+	gpu = frametiming.Current(frametiming.PhaseGPU)
 	screenScale, offsetX, offsetY := c.screenScaleAndOffsets(deviceScaleFactor)
-	if err := graphicscommand.Draw(c.game, screenScale, offsetX, offsetY, theGlobalState.isScreenClearedEveryFrame(), theGlobalState.isScreenFilterEnabled()); err != nil {
+	endRegion = tracing.StartRegion("ebiten.draw")
+	err = graphicscommand.Draw(c.game, screenScale, offsetX, offsetY, theGlobalState.isScreenClearedEveryFrame(), theGlobalState.isScreenFilterEnabled())
+	endRegion()
+	if err != nil {
 		return err
 	}
+	frametiming.Add(frametiming.PhaseDraw, time.Since(t)-(frametiming.Current(frametiming.PhaseGPU)-gpu))
 
 	// All the vertices data are consumed at the end of the frame, and the data backend can be
 	// available after that. Until then, lock the vertices backend.
@@ -121,16 +187,40 @@ func (c *contextImpl) updateFrameImpl(updateCount int, outsideWidth, outsideHeig
 	})
 }
 
-func (c *contextImpl) layoutGame(outsideWidth, outsideHeight float64, deviceScaleFactor float64) (int, int) {
+func (c *contextImpl) layoutGame(outsideWidth, outsideHeight float64, deviceScaleFactor float64) (int, int, error) {
 	c.m.Lock()
 	defer c.m.Unlock()
 
+	w, h, err := c.game.Layout(outsideWidth, outsideHeight, deviceScaleFactor)
+	if err != nil {
+		return 0, 0, err
+	}
 	c.outsideWidth = outsideWidth
 	c.outsideHeight = outsideHeight
-	w, h := c.game.Layout(outsideWidth, outsideHeight, deviceScaleFactor)
 	c.screenWidth = w
 	c.screenHeight = h
-	return w, h
+	return w, h, nil
+}
+
+func (c *contextImpl) isSuspended() bool {
+	return atomic.LoadInt32(&c.suspended) != 0
+}
+
+// setSuspended notifies the game that the application is suspended or resumed.
+// setSuspended does nothing when the state is not changed.
+func (c *contextImpl) setSuspended(suspended bool) {
+	var v int32
+	if suspended {
+		v = 1
+	}
+	if atomic.SwapInt32(&c.suspended, v) == v {
+		return
+	}
+	if suspended {
+		c.game.Suspend()
+	} else {
+		c.game.Resume()
+	}
 }
 
 func (c *contextImpl) adjustPosition(x, y float64, deviceScaleFactor float64) (float64, float64) {
@@ -143,6 +233,25 @@ func (c *contextImpl) adjustPosition(x, y float64, deviceScaleFactor float64) (f
 	return (x*deviceScaleFactor - ox) / s, (y*deviceScaleFactor - oy) / s
 }
 
+// unadjustPosition converts the position in the game screen coordinates to the outside coordinates.
+// unadjustPosition is the inverse of adjustPosition.
+func (c *contextImpl) unadjustPosition(x, y float64, deviceScaleFactor float64) (float64, float64) {
+	s, ox, oy := c.screenScaleAndOffsets(deviceScaleFactor)
+	if s == 0 {
+		return math.NaN(), math.NaN()
+	}
+	return (x*s + ox) / deviceScaleFactor, (y*s + oy) / deviceScaleFactor
+}
+
+// adjustLength converts the length l in the outside coordinates to the game screen coordinates.
+func (c *contextImpl) adjustLength(l float64, deviceScaleFactor float64) float64 {
+	s, _, _ := c.screenScaleAndOffsets(deviceScaleFactor)
+	if s == 0 {
+		return 0
+	}
+	return l * deviceScaleFactor / s
+}
+
 func (c *contextImpl) screenScaleAndOffsets(deviceScaleFactor float64) (float64, float64, float64) {
 	c.m.Lock()
 	defer c.m.Unlock()
@@ -162,7 +271,7 @@ func (c *contextImpl) screenScaleAndOffsets(deviceScaleFactor float64) (float64,
 }
 
 var theGlobalState = globalState{
-	maxTPS_:                    DefaultTPS,
+	maxTPS_:                    packTPS(DefaultTPS, 1),
 	isScreenClearedEveryFrame_: 1,
 	screenFilterEnabled_:       1,
 }
@@ -170,11 +279,21 @@ var theGlobalState = globalState{
 // globalState represents a global state in this package.
 // This is available even before the game loop starts.
 type globalState struct {
+	// refreshRate_ is the first member to be 64-bit aligned for atomic operations on 32-bit machines.
+	refreshRate_ uint64
+
+	// maxTPS_ is the numerator and the denominator of TPS packed into 64 bits.
+	maxTPS_ uint64
+
 	err_                       atomic.Value
 	fpsMode_                   int32
-	maxTPS_                    int32
+	maxUpdatesPerFrame_        int32
+	frameSkipPolicy_           int32
 	isScreenClearedEveryFrame_ int32
 	screenFilterEnabled_       int32
+	windowBehavior_            int32
+	keyboardLayoutCount_       int32
+	terminationRequested_      int32
 }
 
 func (g *globalState) err() error {
@@ -200,18 +319,71 @@ func (g *globalState) setFPSMode(fpsMode FPSModeType) {
 	atomic.StoreInt32(&g.fpsMode_, int32(fpsMode))
 }
 
-func (g *globalState) maxTPS() int {
+func (g *globalState) maxUpdatesPerFrame() int {
+	return int(atomic.LoadInt32(&g.maxUpdatesPerFrame_))
+}
+
+func (g *globalState) setMaxUpdatesPerFrame(n int) {
+	if n < 0 {
+		panic("ebiten: the max updates per frame must be >= 0")
+	}
+	if n > math.MaxInt32 {
+		n = math.MaxInt32
+	}
+	atomic.StoreInt32(&g.maxUpdatesPerFrame_, int32(n))
+}
+
+func (g *globalState) frameSkipPolicy() FrameSkipPolicyType {
+	return FrameSkipPolicyType(atomic.LoadInt32(&g.frameSkipPolicy_))
+}
+
+func (g *globalState) setFrameSkipPolicy(policy FrameSkipPolicyType) {
+	atomic.StoreInt32(&g.frameSkipPolicy_, int32(policy))
+}
+
+func packTPS(num, den int64) uint64 {
+	return uint64(uint32(int32(num)))<<32 | uint64(uint32(den))
+}
+
+func (g *globalState) maxTPSRational() (num, den int64) {
 	if g.fpsMode() == FPSModeVsyncOffMinimum {
-		return clock.SyncWithFPS
+		return clock.SyncWithFPS, 1
 	}
-	return int(atomic.LoadInt32(&g.maxTPS_))
+	v := atomic.LoadUint64(&g.maxTPS_)
+	return int64(int32(uint32(v >> 32))), int64(uint32(v))
+}
+
+func (g *globalState) maxTPS() int {
+	num, den := g.maxTPSRational()
+	if num <= 0 {
+		return int(num)
+	}
+	return int((num + den/2) / den)
+}
+
+func (g *globalState) setMaxTPSRational(num, den int64) {
+	if num == clock.SyncWithFPS && den == 1 {
+		atomic.StoreUint64(&g.maxTPS_, packTPS(num, den))
+		return
+	}
+	if num < 0 {
+		panic("ebiten: tps must be >= 0 or SyncWithFPS")
+	}
+	if den <= 0 {
+		panic("ebiten: the denominator of tps must be > 0")
+	}
+	num, den = clock.ReduceTPS(num, den)
+	atomic.StoreUint64(&g.maxTPS_, packTPS(num, den))
 }
 
 func (g *globalState) setMaxTPS(tps int) {
 	if tps < 0 && tps != clock.SyncWithFPS {
 		panic("ebiten: tps must be >= 0 or SyncWithFPS")
 	}
-	atomic.StoreInt32(&g.maxTPS_, int32(tps))
+	if tps > clock.MaxTPSNumerator {
+		tps = clock.MaxTPSNumerator
+	}
+	g.setMaxTPSRational(int64(tps), 1)
 }
 
 func (g *globalState) isScreenClearedEveryFrame() bool {
@@ -238,6 +410,40 @@ func (g *globalState) setScreenFilterEnabled(enabled bool) {
 	atomic.StoreInt32(&g.screenFilterEnabled_, v)
 }
 
+func (g *globalState) refreshRate() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&g.refreshRate_))
+}
+
+func (g *globalState) setRefreshRate(hz float64) {
+	atomic.StoreUint64(&g.refreshRate_, math.Float64bits(hz))
+}
+
+func (g *globalState) windowBehavior() WindowBehavior {
+	return WindowBehavior(atomic.LoadInt32(&g.windowBehavior_))
+}
+
+func (g *globalState) setWindowBehavior(behavior WindowBehavior) {
+	atomic.StoreInt32(&g.windowBehavior_, int32(behavior))
+}
+
+func (g *globalState) keyboardLayoutCount() int32 {
+	return atomic.LoadInt32(&g.keyboardLayoutCount_)
+}
+
+// notifyKeyboardLayoutChange records that the keyboard layout is changed.
+// The game is notified before the next Update.
+func (g *globalState) notifyKeyboardLayoutChange() {
+	atomic.AddInt32(&g.keyboardLayoutCount_, 1)
+}
+
+func (g *globalState) isTerminationRequested() bool {
+	return atomic.LoadInt32(&g.terminationRequested_) != 0
+}
+
+func (g *globalState) requestTermination() {
+	atomic.StoreInt32(&g.terminationRequested_, 1)
+}
+
 func SetError(err error) {
 	theGlobalState.setError(err)
 }
@@ -259,6 +465,31 @@ func SetMaxTPS(tps int) {
 	theGlobalState.setMaxTPS(tps)
 }
 
+func MaxTPSRational() (num, den int) {
+	n, d := theGlobalState.maxTPSRational()
+	return int(n), int(d)
+}
+
+func SetMaxTPSRational(num, den int) {
+	theGlobalState.setMaxTPSRational(int64(num), int64(den))
+}
+
+func MaxUpdatesPerFrame() int {
+	return theGlobalState.maxUpdatesPerFrame()
+}
+
+func SetMaxUpdatesPerFrame(n int) {
+	theGlobalState.setMaxUpdatesPerFrame(n)
+}
+
+func FrameSkipPolicy() FrameSkipPolicyType {
+	return theGlobalState.frameSkipPolicy()
+}
+
+func SetFrameSkipPolicy(policy FrameSkipPolicyType) {
+	theGlobalState.setFrameSkipPolicy(policy)
+}
+
 func IsScreenClearedEveryFrame() bool {
 	return theGlobalState.isScreenClearedEveryFrame()
 }
@@ -274,3 +505,25 @@ func IsScreenFilterEnabled() bool {
 func SetScreenFilterEnabled(enabled bool) {
 	theGlobalState.setScreenFilterEnabled(enabled)
 }
+
+func GetWindowBehavior() WindowBehavior {
+	return theGlobalState.windowBehavior()
+}
+
+func SetWindowBehavior(behavior WindowBehavior) {
+	theGlobalState.setWindowBehavior(behavior)
+}
+
+// RequestTermination requests to terminate the main loop regularly.
+// The main loop is terminated before the next frame starts, i.e., after the commands of the current frame are flushed.
+//
+// RequestTermination is concurrent-safe.
+func RequestTermination() {
+	theGlobalState.requestTermination()
+}
+
+// RefreshRate returns the refresh rate of the display the game is shown on in Hz.
+// RefreshRate returns 0 when the refresh rate is not known yet.
+func RefreshRate() float64 {
+	return theGlobalState.refreshRate()
+}
@@ -0,0 +1,76 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/gamepad"
+)
+
+// gamepadEventDetector detects gamepad button presses and releases by comparing the button states between updates.
+//
+// Gamepads are polled, so the timestamps of the events are the times of the polling.
+type gamepadEventDetector struct {
+	ids     []gamepad.ID
+	buttons map[gamepad.ID][]bool
+}
+
+// appendEvents appends the gamepad button events since the previous call to events.
+// appendEvents must be called after gamepad.Update.
+func (g *gamepadEventDetector) appendEvents(events []InputEvent, timestamp time.Duration) []InputEvent {
+	if g.buttons == nil {
+		g.buttons = map[gamepad.ID][]bool{}
+	}
+
+	g.ids = gamepad.AppendGamepadIDs(g.ids[:0])
+	for _, id := range g.ids {
+		gp := gamepad.Get(id)
+		if gp == nil {
+			continue
+		}
+		prev := g.buttons[id]
+		n := gp.ButtonCount()
+		for len(prev) < n {
+			prev = append(prev, false)
+		}
+		for b := 0; b < n; b++ {
+			pressed := gp.Button(b)
+			if pressed == prev[b] {
+				continue
+			}
+			t := InputEventTypeGamepadButtonUp
+			if pressed {
+				t = InputEventTypeGamepadButtonDown
+			}
+			events = append(events, InputEvent{
+				Type:          t,
+				GamepadID:     id,
+				GamepadButton: gamepad.Button(b),
+				Timestamp:     timestamp,
+			})
+			prev[b] = pressed
+		}
+		g.buttons[id] = prev
+	}
+
+	// Forget disconnected gamepads. Their IDs might be reused.
+	for id := range g.buttons {
+		if gamepad.Get(id) == nil {
+			delete(g.buttons, id)
+		}
+	}
+	return events
+}
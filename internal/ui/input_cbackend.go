@@ -51,6 +51,10 @@ func (i *Input) AppendInputChars(runes []rune) []rune {
 	return nil
 }
 
+func (i *Input) AppendInputEvents(events []InputEvent) []InputEvent {
+	return events
+}
+
 func (i *Input) AppendTouchIDs(touchIDs []TouchID) []TouchID {
 	i.m.Lock()
 	defer i.m.Unlock()
@@ -65,6 +69,9 @@ func (i *Input) CursorPosition() (x, y int) {
 	return 0, 0
 }
 
+func (i *Input) SetCursorPosition(x, y int) {
+}
+
 func (i *Input) IsKeyPressed(key Key) bool {
 	return false
 }
@@ -85,6 +92,14 @@ func (i *Input) TouchPosition(id TouchID) (x, y int) {
 	return 0, 0
 }
 
+func (i *Input) TouchPressure(id TouchID) float64 {
+	return 0
+}
+
+func (i *Input) TouchRadius(id TouchID) (rx, ry float64) {
+	return 0, 0
+}
+
 func (i *Input) Wheel() (xoff, yoff float64) {
 	return 0, 0
 }
@@ -20,6 +20,7 @@ package ui
 import (
 	"math"
 	"sync"
+	"time"
 	"unicode"
 
 	"github.com/hajimehoshi/ebiten/v2/internal/gamepad"
@@ -36,9 +37,40 @@ type Input struct {
 	cursorY            int
 	touches            map[TouchID]pos // TODO: Implement this (#417)
 	runeBuffer         []rune
+	events             []InputEvent
+	gamepadEvents      gamepadEventDetector
+	keyNames           string
+	keyNamesBuf        []byte
 	ui                 *UserInterface
 }
 
+// keyboardLayoutKeys are the printable keys whose names are compared to detect keyboard layout changes.
+// These keys are chosen to cover the differences between common layouts like QWERTY, QWERTZ, AZERTY and Dvorak,
+// and non-Latin layouts.
+var keyboardLayoutKeys = []glfw.Key{
+	glfw.KeyQ,
+	glfw.KeyW,
+	glfw.KeyY,
+	glfw.KeyZ,
+	glfw.KeyA,
+	glfw.KeyM,
+	glfw.KeyE,
+	glfw.Key2,
+	glfw.KeyMinus,
+	glfw.KeyEqual,
+	glfw.KeyLeftBracket,
+	glfw.KeyRightBracket,
+	glfw.KeySemicolon,
+	glfw.KeyApostrophe,
+	glfw.KeyBackslash,
+	glfw.KeyGraveAccent,
+	glfw.KeyComma,
+	glfw.KeySlash,
+}
+
+// inputEventOrigin is the origin of InputEvent's timestamps.
+var inputEventOrigin = time.Now()
+
 type pos struct {
 	X int
 	Y int
@@ -54,6 +86,28 @@ func (i *Input) CursorPosition() (x, y int) {
 	return i.cursorX, i.cursorY
 }
 
+func (i *Input) SetCursorPosition(x, y int) {
+	if !i.ui.isRunning() {
+		return
+	}
+
+	i.ui.t.Call(func() {
+		m := i.ui.currentMonitor()
+		s := i.ui.deviceScaleFactor(m)
+		cx, cy := i.ui.context.unadjustPosition(float64(x), float64(y), s)
+		// unadjustPosition can return NaN at the initialization.
+		if math.IsNaN(cx) || math.IsNaN(cy) {
+			return
+		}
+		i.ui.window.SetCursorPos(i.ui.dipToGLFWPixel(cx, m), i.ui.dipToGLFWPixel(cy, m))
+
+		// Reflect the position immediately without waiting for the next update.
+		i.ui.m.Lock()
+		defer i.ui.m.Unlock()
+		i.cursorX, i.cursorY = x, y
+	})
+}
+
 func (i *Input) AppendTouchIDs(touchIDs []TouchID) []TouchID {
 	if !i.ui.isRunning() {
 		return nil
@@ -82,6 +136,16 @@ func (i *Input) TouchPosition(id TouchID) (x, y int) {
 	return 0, 0
 }
 
+func (i *Input) TouchPressure(id TouchID) float64 {
+	// TODO: Implement this with touches (#417).
+	return 0
+}
+
+func (i *Input) TouchRadius(id TouchID) (rx, ry float64) {
+	// TODO: Implement this with touches (#417).
+	return 0, 0
+}
+
 func (i *Input) AppendInputChars(runes []rune) []rune {
 	if !i.ui.isRunning() {
 		return nil
@@ -92,6 +156,16 @@ func (i *Input) AppendInputChars(runes []rune) []rune {
 	return append(runes, i.runeBuffer...)
 }
 
+func (i *Input) AppendInputEvents(events []InputEvent) []InputEvent {
+	if !i.ui.isRunning() {
+		return events
+	}
+
+	i.ui.m.RLock()
+	defer i.ui.m.RUnlock()
+	return append(events, i.events...)
+}
+
 func (i *Input) resetForTick() {
 	if !i.ui.isRunning() {
 		return
@@ -100,6 +174,7 @@ func (i *Input) resetForTick() {
 	i.ui.m.Lock()
 	defer i.ui.m.Unlock()
 	i.runeBuffer = i.runeBuffer[:0]
+	i.events = i.events[:0]
 	i.scrollX, i.scrollY = 0, 0
 }
 
@@ -154,6 +229,24 @@ var glfwMouseButtonToMouseButton = map[glfw.MouseButton]MouseButton{
 	glfw.MouseButtonMiddle: MouseButtonMiddle,
 }
 
+// updateKeyboardLayout detects a keyboard layout change by comparing the names of the printable keys.
+//
+// updateKeyboardLayout must be called from the main thread.
+func (i *Input) updateKeyboardLayout() {
+	i.keyNamesBuf = i.keyNamesBuf[:0]
+	for _, k := range keyboardLayoutKeys {
+		i.keyNamesBuf = append(i.keyNamesBuf, glfw.GetKeyName(k, 0)...)
+		i.keyNamesBuf = append(i.keyNamesBuf, 0)
+	}
+	if string(i.keyNamesBuf) == i.keyNames {
+		return
+	}
+	if i.keyNames != "" {
+		theGlobalState.notifyKeyboardLayoutChange()
+	}
+	i.keyNames = string(i.keyNamesBuf)
+}
+
 // update must be called from the main thread.
 func (i *Input) update(window *glfw.Window, context *contextImpl) error {
 	i.ui.m.Lock()
@@ -177,6 +270,60 @@ func (i *Input) update(window *glfw.Window, context *contextImpl) error {
 			i.scrollX = xoff
 			i.scrollY = yoff
 		}))
+		window.SetKeyCallback(glfw.ToKeyCallback(func(w *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
+			// As this function is called from GLFW callbacks, the current thread is main.
+			if key == glfw.KeyEnter && action == glfw.Press && mods&glfw.ModAlt != 0 &&
+				theGlobalState.windowBehavior()&WindowBehaviorAltEnterFullscreen != 0 {
+				// Toggling fullscreen polls events, which is not allowed in a callback.
+				i.ui.fullscreenToggleRequested = true
+			}
+
+			k, ok := glfwKeyToUIKey[key]
+			if !ok {
+				return
+			}
+			var t InputEventType
+			switch action {
+			case glfw.Press:
+				t = InputEventTypeKeyDown
+			case glfw.Release:
+				t = InputEventTypeKeyUp
+			default:
+				return
+			}
+
+			i.ui.m.Lock()
+			defer i.ui.m.Unlock()
+			i.events = append(i.events, InputEvent{
+				Type:      t,
+				Key:       k,
+				Timestamp: time.Since(inputEventOrigin),
+			})
+		}))
+		window.SetMouseButtonCallback(glfw.ToMouseButtonCallback(func(w *glfw.Window, button glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey) {
+			// As this function is called from GLFW callbacks, the current thread is main.
+			b, ok := glfwMouseButtonToMouseButton[button]
+			if !ok {
+				return
+			}
+			var t InputEventType
+			switch action {
+			case glfw.Press:
+				t = InputEventTypeMouseButtonDown
+			case glfw.Release:
+				t = InputEventTypeMouseButtonUp
+			default:
+				return
+			}
+
+			i.ui.m.Lock()
+			defer i.ui.m.Unlock()
+			i.events = append(i.events, InputEvent{
+				Type:        t,
+				MouseButton: b,
+				Timestamp:   time.Since(inputEventOrigin),
+			})
+		}))
 	})
 	if i.keyPressed == nil {
 		i.keyPressed = map[glfw.Key]bool{}
@@ -204,5 +351,6 @@ func (i *Input) update(window *glfw.Window, context *contextImpl) error {
 	}
 
 	gamepad.Update()
+	i.events = i.gamepadEvents.appendEvents(i.events, time.Since(inputEventOrigin))
 	return nil
 }
@@ -16,6 +16,7 @@ package ui
 
 import (
 	"syscall/js"
+	"time"
 	"unicode"
 )
 
@@ -32,11 +33,17 @@ var (
 	stringTouchmove  = js.ValueOf("touchmove")
 )
 
-var jsKeys []js.Value
+var (
+	jsKeys []js.Value
+
+	// jsCodeToUIKeyMap maps a KeyboardEvent's code to a Key.
+	jsCodeToUIKeyMap = map[string]Key{}
+)
 
 func init() {
-	for _, k := range uiKeyToJSKey {
+	for key, k := range uiKeyToJSKey {
 		jsKeys = append(jsKeys, k)
+		jsCodeToUIKeyMap[k.String()] = key
 	}
 }
 
@@ -56,6 +63,13 @@ type pos struct {
 	Y int
 }
 
+type touch struct {
+	pos
+	Pressure float64
+	RadiusX  float64
+	RadiusY  float64
+}
+
 type Input struct {
 	keyPressed         map[int]bool
 	keyPressedEdge     map[int]bool
@@ -66,8 +80,10 @@ type Input struct {
 	origCursorY        int
 	wheelX             float64
 	wheelY             float64
-	touches            map[TouchID]pos
+	touches            map[TouchID]touch
 	runeBuffer         []rune
+	events             []InputEvent
+	gamepadEvents      gamepadEventDetector
 	ui                 *UserInterface
 }
 
@@ -79,6 +95,10 @@ func (i *Input) CursorPosition() (x, y int) {
 	return int(xf), int(yf)
 }
 
+func (i *Input) SetCursorPosition(x, y int) {
+	// Browsers don't allow to move the cursor.
+}
+
 func (i *Input) AppendTouchIDs(touchIDs []TouchID) []TouchID {
 	for id := range i.touches {
 		touchIDs = append(touchIDs, id)
@@ -97,12 +117,33 @@ func (i *Input) TouchPosition(id TouchID) (x, y int) {
 	return 0, 0
 }
 
+func (i *Input) TouchPressure(id TouchID) float64 {
+	if t, ok := i.touches[id]; ok {
+		return t.Pressure
+	}
+	return 0
+}
+
+func (i *Input) TouchRadius(id TouchID) (rx, ry float64) {
+	t, ok := i.touches[id]
+	if !ok {
+		return 0, 0
+	}
+	d := i.ui.DeviceScaleFactor()
+	return i.ui.context.adjustLength(t.RadiusX, d), i.ui.context.adjustLength(t.RadiusY, d)
+}
+
 func (i *Input) AppendInputChars(runes []rune) []rune {
 	return append(runes, i.runeBuffer...)
 }
 
+func (i *Input) AppendInputEvents(events []InputEvent) []InputEvent {
+	return append(events, i.events...)
+}
+
 func (i *Input) resetForTick() {
 	i.runeBuffer = nil
+	i.events = i.events[:0]
 	i.wheelX = 0
 	i.wheelY = 0
 }
@@ -207,9 +248,12 @@ func (i *Input) updateFromEvent(e js.Value) {
 				edgeKeyCodeToUIKey[code] == KeyArrowRight ||
 				edgeKeyCodeToUIKey[code] == KeyBackspace ||
 				edgeKeyCodeToUIKey[code] == KeyTab {
-				e.Call("preventDefault")
+				preventDefault(e)
 			}
 			i.keyDownEdge(code)
+			if k, ok := edgeKeyCodeToUIKey[code]; ok && !e.Get("repeat").Truthy() {
+				i.appendEvent(e, InputEvent{Type: InputEventTypeKeyDown, Key: k})
+			}
 			return
 		}
 		if c.Equal(uiKeyToJSKey[KeyArrowUp]) ||
@@ -218,9 +262,12 @@ func (i *Input) updateFromEvent(e js.Value) {
 			c.Equal(uiKeyToJSKey[KeyArrowRight]) ||
 			c.Equal(uiKeyToJSKey[KeyBackspace]) ||
 			c.Equal(uiKeyToJSKey[KeyTab]) {
-			e.Call("preventDefault")
+			preventDefault(e)
 		}
 		i.keyDown(c)
+		if k, ok := jsCodeToUIKey(c); ok && !e.Get("repeat").Truthy() {
+			i.appendEvent(e, InputEvent{Type: InputEventTypeKeyDown, Key: k})
+		}
 	case t.Equal(stringKeypress):
 		if r := rune(e.Get("charCode").Int()); unicode.IsPrint(r) {
 			i.runeBuffer = append(i.runeBuffer, r)
@@ -230,16 +277,29 @@ func (i *Input) updateFromEvent(e js.Value) {
 			// Assume that UA is Edge.
 			code := e.Get("keyCode").Int()
 			i.keyUpEdge(code)
+			if k, ok := edgeKeyCodeToUIKey[code]; ok {
+				i.appendEvent(e, InputEvent{Type: InputEventTypeKeyUp, Key: k})
+			}
 			return
 		}
-		i.keyUp(e.Get("code"))
+		c := e.Get("code")
+		i.keyUp(c)
+		if k, ok := jsCodeToUIKey(c); ok {
+			i.appendEvent(e, InputEvent{Type: InputEventTypeKeyUp, Key: k})
+		}
 	case t.Equal(stringMousedown):
 		button := e.Get("button").Int()
 		i.mouseDown(button)
+		if b, ok := codeToMouseButton[button]; ok {
+			i.appendEvent(e, InputEvent{Type: InputEventTypeMouseButtonDown, MouseButton: b})
+		}
 		i.setMouseCursorFromEvent(e)
 	case t.Equal(stringMouseup):
 		button := e.Get("button").Int()
 		i.mouseUp(button)
+		if b, ok := codeToMouseButton[button]; ok {
+			i.appendEvent(e, InputEvent{Type: InputEventTypeMouseButtonUp, MouseButton: b})
+		}
 		i.setMouseCursorFromEvent(e)
 	case t.Equal(stringMousemove):
 		i.setMouseCursorFromEvent(e)
@@ -249,11 +309,75 @@ func (i *Input) updateFromEvent(e js.Value) {
 		i.wheelY = -e.Get("deltaY").Float()
 	case t.Equal(stringTouchstart) || t.Equal(stringTouchend) || t.Equal(stringTouchmove):
 		i.updateTouchesFromEvent(e)
+		switch {
+		case t.Equal(stringTouchstart):
+			i.appendTouchEvents(e, InputEventTypeTouchDown)
+		case t.Equal(stringTouchend):
+			i.appendTouchEvents(e, InputEventTypeTouchUp)
+		}
 	}
 
 	i.ui.forceUpdateOnMinimumFPSMode()
 }
 
+func jsCodeToUIKey(code js.Value) (Key, bool) {
+	if code.Type() != js.TypeString {
+		return 0, false
+	}
+	k, ok := jsCodeToUIKeyMap[code.String()]
+	return k, ok
+}
+
+var performance = js.Global().Get("performance")
+
+// floatOrZero returns the number v, or 0 if v is not a number.
+func floatOrZero(v js.Value) float64 {
+	if v.Type() != js.TypeNumber {
+		return 0
+	}
+	return v.Float()
+}
+
+// appendEvent appends an input event with the timestamp of the JS event e.
+// The timestamp is based on performance.now().
+func (i *Input) appendEvent(e js.Value, event InputEvent) {
+	ts := e.Get("timeStamp")
+	if ts.Type() != js.TypeNumber {
+		ts = performance.Call("now")
+	}
+	event.Timestamp = time.Duration(ts.Float() * float64(time.Millisecond))
+	i.events = append(i.events, event)
+}
+
+// appendTouchEvents appends input events of type typ for the changed touches of the JS event e.
+func (i *Input) appendTouchEvents(e js.Value, typ InputEventType) {
+	j := e.Get("changedTouches")
+	if !j.Truthy() {
+		return
+	}
+	for idx := 0; idx < j.Length(); idx++ {
+		// Use Index instead of TouchList.item as touches proxied from the main thread are an array.
+		id := TouchID(j.Index(idx).Get("identifier").Int())
+		i.appendEvent(e, InputEvent{Type: typ, TouchID: id})
+	}
+}
+
+// updateGamepadEvents appends gamepad button events.
+// updateGamepadEvents must be called after gamepad.Update.
+func (i *Input) updateGamepadEvents() {
+	ts := time.Duration(performance.Call("now").Float() * float64(time.Millisecond))
+	i.events = i.gamepadEvents.appendEvents(i.events, ts)
+}
+
+// preventDefault calls e.preventDefault.
+// In a Web Worker, e is a plain object proxied from the main thread and this does nothing.
+func preventDefault(e js.Value) {
+	if isWorker {
+		return
+	}
+	e.Call("preventDefault")
+}
+
 func (i *Input) setMouseCursorFromEvent(e js.Value) {
 	if i.ui.cursorMode == CursorModeCaptured {
 		x, y := e.Get("clientX").Int(), e.Get("clientY").Int()
@@ -279,14 +403,22 @@ func (in *Input) updateTouchesFromEvent(e js.Value) {
 		delete(in.touches, k)
 	}
 	for i := 0; i < j.Length(); i++ {
-		jj := j.Call("item", i)
+		// Use Index instead of TouchList.item as touches proxied from the main thread are an array.
+		jj := j.Index(i)
 		id := TouchID(jj.Get("identifier").Int())
 		if in.touches == nil {
-			in.touches = map[TouchID]pos{}
+			in.touches = map[TouchID]touch{}
 		}
-		in.touches[id] = pos{
-			X: jj.Get("clientX").Int(),
-			Y: jj.Get("clientY").Int(),
+		in.touches[id] = touch{
+			pos: pos{
+				X: jj.Get("clientX").Int(),
+				Y: jj.Get("clientY").Int(),
+			},
+			// These properties might not be available, e.g., on old browsers or in touches proxied from the main
+			// thread.
+			Pressure: floatOrZero(jj.Get("force")),
+			RadiusX:  floatOrZero(jj.Get("radiusX")),
+			RadiusY:  floatOrZero(jj.Get("radiusY")),
 		}
 	}
 }
@@ -305,11 +437,13 @@ func (i *Input) updateForGo2Cpp() {
 		x := go2cpp.Call("getTouchX", idx)
 		y := go2cpp.Call("getTouchY", idx)
 		if i.touches == nil {
-			i.touches = map[TouchID]pos{}
+			i.touches = map[TouchID]touch{}
 		}
-		i.touches[TouchID(id.Int())] = pos{
-			X: x.Int(),
-			Y: y.Int(),
+		i.touches[TouchID(id.Int())] = touch{
+			pos: pos{
+				X: x.Int(),
+				Y: y.Int(),
+			},
 		}
 	}
 }
@@ -18,17 +18,29 @@
 
 package ui
 
+import (
+	"time"
+)
+
 type Input struct {
-	keys    map[Key]struct{}
-	runes   []rune
-	touches []Touch
-	ui      *UserInterface
+	keys          map[Key]struct{}
+	runes         []rune
+	touches       []Touch
+	events        []InputEvent
+	gamepadEvents gamepadEventDetector
+	ui            *UserInterface
 }
 
+// inputEventOrigin is the origin of InputEvent's timestamps.
+var inputEventOrigin = time.Now()
+
 func (i *Input) CursorPosition() (x, y int) {
 	return 0, 0
 }
 
+func (i *Input) SetCursorPosition(x, y int) {
+}
+
 func (i *Input) AppendTouchIDs(touchIDs []TouchID) []TouchID {
 	i.ui.m.RLock()
 	defer i.ui.m.RUnlock()
@@ -51,12 +63,43 @@ func (i *Input) TouchPosition(id TouchID) (x, y int) {
 	return 0, 0
 }
 
+func (i *Input) TouchPressure(id TouchID) float64 {
+	i.ui.m.RLock()
+	defer i.ui.m.RUnlock()
+
+	for _, t := range i.touches {
+		if t.ID == id {
+			return t.Pressure
+		}
+	}
+	return 0
+}
+
+func (i *Input) TouchRadius(id TouchID) (rx, ry float64) {
+	i.ui.m.RLock()
+	defer i.ui.m.RUnlock()
+
+	for _, t := range i.touches {
+		if t.ID == id {
+			s := deviceScale()
+			return i.ui.context.adjustLength(t.RadiusX, s), i.ui.context.adjustLength(t.RadiusY, s)
+		}
+	}
+	return 0, 0
+}
+
 func (i *Input) AppendInputChars(runes []rune) []rune {
 	i.ui.m.Lock()
 	defer i.ui.m.Unlock()
 	return append(runes, i.runes...)
 }
 
+func (i *Input) AppendInputEvents(events []InputEvent) []InputEvent {
+	i.ui.m.RLock()
+	defer i.ui.m.RUnlock()
+	return append(events, i.events...)
+}
+
 func (i *Input) IsKeyPressed(key Key) bool {
 	i.ui.m.RLock()
 	defer i.ui.m.RUnlock()
@@ -77,6 +120,29 @@ func (i *Input) update(keys map[Key]struct{}, runes []rune, touches []Touch) {
 	i.ui.m.Lock()
 	defer i.ui.m.Unlock()
 
+	// The platforms give only the current states, so infer the events by comparing the states.
+	ts := time.Since(inputEventOrigin)
+	for k := range i.keys {
+		if _, ok := keys[k]; !ok {
+			i.events = append(i.events, InputEvent{Type: InputEventTypeKeyUp, Key: k, Timestamp: ts})
+		}
+	}
+	for k := range keys {
+		if _, ok := i.keys[k]; !ok {
+			i.events = append(i.events, InputEvent{Type: InputEventTypeKeyDown, Key: k, Timestamp: ts})
+		}
+	}
+	for _, t := range i.touches {
+		if !containsTouchID(touches, t.ID) {
+			i.events = append(i.events, InputEvent{Type: InputEventTypeTouchUp, TouchID: t.ID, Timestamp: ts})
+		}
+	}
+	for _, t := range touches {
+		if !containsTouchID(i.touches, t.ID) {
+			i.events = append(i.events, InputEvent{Type: InputEventTypeTouchDown, TouchID: t.ID, Timestamp: ts})
+		}
+	}
+
 	if i.keys == nil {
 		i.keys = map[Key]struct{}{}
 	}
@@ -94,6 +160,26 @@ func (i *Input) update(keys map[Key]struct{}, runes []rune, touches []Touch) {
 	i.touches = append(i.touches, touches...)
 }
 
+func containsTouchID(touches []Touch, id TouchID) bool {
+	for _, t := range touches {
+		if t.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// updateGamepadEvents appends gamepad button events.
+// updateGamepadEvents must be called after gamepad.Update.
+func (i *Input) updateGamepadEvents() {
+	i.ui.m.Lock()
+	defer i.ui.m.Unlock()
+	i.events = i.gamepadEvents.appendEvents(i.events, time.Since(inputEventOrigin))
+}
+
 func (i *Input) resetForTick() {
+	i.ui.m.Lock()
+	defer i.ui.m.Unlock()
 	i.runes = nil
+	i.events = i.events[:0]
 }
@@ -0,0 +1,36 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+// NativeWindowKind represents the kind of a native window.
+type NativeWindowKind int
+
+const (
+	NativeWindowKindNone NativeWindowKind = iota
+	NativeWindowKindWin32
+	NativeWindowKindCocoa
+	NativeWindowKindX11
+)
+
+// NativeWindow represents the handles of a native window.
+type NativeWindow struct {
+	Kind NativeWindowKind
+
+	// Window is HWND for Win32, NSWindow* for Cocoa, and Window for X11.
+	Window uintptr
+
+	// Display is Display* for X11, and 0 for the others.
+	Display uintptr
+}
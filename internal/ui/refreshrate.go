@@ -0,0 +1,67 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build android || ios || js
+// +build android ios js
+
+package ui
+
+import (
+	"math"
+	"sort"
+)
+
+const refreshRateSampleNum = 60
+
+// refreshRateEstimator estimates the refresh rate of the display from the timestamps of frames that are driven by
+// the display, like requestAnimationFrame callbacks or CADisplayLink.
+type refreshRateEstimator struct {
+	last      float64
+	intervals [refreshRateSampleNum]float64
+	count     int
+}
+
+// update records the timestamp t in seconds of a new frame and updates the global refresh rate when enough
+// samples are collected.
+func (r *refreshRateEstimator) update(t float64) {
+	last := r.last
+	r.last = t
+	if last == 0 {
+		return
+	}
+
+	d := t - last
+	// Ignore too long intervals, which happen e.g. when the application is suspended.
+	if d <= 0 || d > 0.25 {
+		return
+	}
+	r.intervals[r.count] = d
+	r.count++
+	if r.count < len(r.intervals) {
+		return
+	}
+	r.count = 0
+
+	// Use the median to ignore dropped frames.
+	intervals := r.intervals[:]
+	sort.Float64s(intervals)
+	hz := math.Round(1 / intervals[len(intervals)/2])
+	theGlobalState.setRefreshRate(hz)
+}
+
+// reset discards the recorded timestamps.
+func (r *refreshRateEstimator) reset() {
+	r.last = 0
+	r.count = 0
+}
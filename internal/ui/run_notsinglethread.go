@@ -18,6 +18,7 @@
 package ui
 
 import (
+	"github.com/hajimehoshi/ebiten/v2/internal/glfw"
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicscommand"
 	"github.com/hajimehoshi/ebiten/v2/internal/thread"
 )
@@ -55,6 +56,63 @@ func (u *UserInterface) Run(game Game) error {
 	return <-ch
 }
 
+// StartWithoutMainLoop initializes the game without running the main loop.
+// After StartWithoutMainLoop, UpdateWithoutMainLoop must be called every frame.
+//
+// StartWithoutMainLoop must be called from the main thread.
+func (u *UserInterface) StartWithoutMainLoop(game Game) error {
+	u.context = newContextImpl(game)
+
+	// Between frames, the caller is on the main thread and nothing loops u.t.
+	// Use a no-op thread so that functions like SetWindowTitle run directly instead of blocking forever.
+	u.t = thread.NewNoopThread()
+	graphicscommand.SetRenderingThread(u.t)
+	u.frameThread = thread.NewOSThread()
+
+	u.setRunning(true)
+
+	// This is called from the main thread. Then, u.init can be called directly.
+	if err := u.init(); err != nil {
+		u.setRunning(false)
+		return err
+	}
+	return nil
+}
+
+// UpdateWithoutMainLoop proceeds the game started by StartWithoutMainLoop by one frame.
+// When UpdateWithoutMainLoop returns an error, the window is destroyed and the game must not be updated any longer.
+//
+// UpdateWithoutMainLoop must be called from the main thread.
+func (u *UserInterface) UpdateWithoutMainLoop() error {
+	// Run the thread loop on this main thread until the frame ends, as the mobile implementation does.
+	// As this function is called from the main thread, u.t should never be accessed and can be updated here.
+	t := u.t
+	u.t = u.frameThread
+	graphicscommand.SetRenderingThread(u.t)
+	defer func() {
+		u.t = t
+		graphicscommand.SetRenderingThread(t)
+	}()
+
+	ch := make(chan error, 1)
+	go func() {
+		defer u.frameThread.Stop()
+
+		err := u.updateGame()
+		if err != nil {
+			u.frameThread.Call(glfw.Terminate)
+		}
+		ch <- err
+	}()
+	u.frameThread.Loop()
+
+	err := <-ch
+	if err != nil {
+		u.setRunning(false)
+	}
+	return err
+}
+
 // runOnAnotherThreadFromMainThread is called from the main thread, and calls f on a new goroutine (thread).
 // runOnAnotherThreadFromMainThread creates a new nested main thread and runs the run loop.
 // u.t is updated to the new thread until runOnAnotherThreadFromMainThread is called.
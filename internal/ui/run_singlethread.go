@@ -18,6 +18,7 @@
 package ui
 
 import (
+	"github.com/hajimehoshi/ebiten/v2/internal/glfw"
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicscommand"
 	"github.com/hajimehoshi/ebiten/v2/internal/thread"
 )
@@ -43,6 +44,30 @@ func (u *UserInterface) Run(game Game) error {
 	return nil
 }
 
+func (u *UserInterface) StartWithoutMainLoop(game Game) error {
+	u.context = newContextImpl(game)
+
+	u.t = thread.NewNoopThread()
+	graphicscommand.SetRenderingThread(u.t)
+
+	u.setRunning(true)
+
+	if err := u.init(); err != nil {
+		u.setRunning(false)
+		return err
+	}
+	return nil
+}
+
+func (u *UserInterface) UpdateWithoutMainLoop() error {
+	if err := u.updateGame(); err != nil {
+		glfw.Terminate()
+		u.setRunning(false)
+		return err
+	}
+	return nil
+}
+
 func (u *UserInterface) runOnAnotherThreadFromMainThread(f func()) {
 	f()
 }
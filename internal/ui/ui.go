@@ -16,6 +16,9 @@ package ui
 
 import (
 	"errors"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/gamepad"
 )
 
 type MouseButton int
@@ -28,6 +31,32 @@ const (
 
 type TouchID int
 
+type InputEventType int
+
+const (
+	InputEventTypeKeyDown InputEventType = iota
+	InputEventTypeKeyUp
+	InputEventTypeMouseButtonDown
+	InputEventTypeMouseButtonUp
+	InputEventTypeTouchDown
+	InputEventTypeTouchUp
+	InputEventTypeGamepadButtonDown
+	InputEventTypeGamepadButtonUp
+)
+
+// InputEvent is an input event with its timestamp.
+//
+// Timestamp is a monotonic time from an unspecified origin.
+type InputEvent struct {
+	Type          InputEventType
+	Key           Key
+	MouseButton   MouseButton
+	TouchID       TouchID
+	GamepadID     gamepad.ID
+	GamepadButton gamepad.Button
+	Timestamp     time.Duration
+}
+
 // RegularTermination represents a regular termination.
 // Run can return this error, and if this error is received,
 // the game loop should be terminated as soon as possible.
@@ -41,6 +70,13 @@ const (
 	FPSModeVsyncOffMinimum
 )
 
+type FrameSkipPolicyType int
+
+const (
+	FrameSkipPolicyCatchUp FrameSkipPolicyType = iota
+	FrameSkipPolicySlowMotion
+)
+
 type CursorMode int
 
 const (
@@ -67,3 +103,27 @@ const (
 	WindowResizingModeOnlyFullscreenEnabled
 	WindowResizingModeEnabled
 )
+
+type WindowBehavior int
+
+const (
+	WindowBehaviorAltEnterFullscreen WindowBehavior = 1 << iota
+)
+
+type OcclusionState int
+
+const (
+	OcclusionStateVisible OcclusionState = iota
+	OcclusionStateOccluded
+	OcclusionStateMinimized
+)
+
+type WindowGeometry struct {
+	Monitor    string
+	X          int
+	Y          int
+	Width      int
+	Height     int
+	Maximized  bool
+	Fullscreen bool
+}
@@ -41,25 +41,47 @@ func Get() *UserInterface {
 }
 
 func (u *UserInterface) Run(game Game) error {
-	u.context = newContextImpl(game)
-	cbackend.InitializeGame()
+	if err := u.StartWithoutMainLoop(game); err != nil {
+		return err
+	}
 	for {
-		cbackend.BeginFrame()
-		u.input.update(u.context)
-
-		w, h := cbackend.ScreenSize()
-		if err := u.context.updateFrame(float64(w), float64(h), deviceScaleFactor); err != nil {
+		if err := u.UpdateWithoutMainLoop(); err != nil {
 			return err
 		}
+	}
+}
 
-		cbackend.EndFrame()
+func (u *UserInterface) StartWithoutMainLoop(game Game) error {
+	u.context = newContextImpl(game)
+	cbackend.InitializeGame()
+	return nil
+}
+
+func (u *UserInterface) UpdateWithoutMainLoop() error {
+	cbackend.BeginFrame()
+	u.input.update(u.context)
+
+	w, h := cbackend.ScreenSize()
+	if err := u.context.updateFrame(float64(w), float64(h), deviceScaleFactor); err != nil {
+		return err
 	}
+
+	cbackend.EndFrame()
+	return nil
 }
 
 func (*UserInterface) DeviceScaleFactor() float64 {
 	return deviceScaleFactor
 }
 
+func (*UserInterface) SafeAreaInsets() (left, top, right, bottom float64) {
+	return 0, 0, 0, 0
+}
+
+func (*UserInterface) OcclusionState() OcclusionState {
+	return OcclusionStateVisible
+}
+
 func (*UserInterface) IsFocused() bool {
 	return true
 }
@@ -78,6 +100,10 @@ func (*UserInterface) CursorMode() CursorMode {
 func (*UserInterface) SetCursorMode(mode CursorMode) {
 }
 
+func (*UserInterface) NativeWindow() NativeWindow {
+	return NativeWindow{}
+}
+
 func (*UserInterface) CursorShape() CursorShape {
 	return CursorShapeDefault
 }
@@ -112,6 +138,13 @@ func (*UserInterface) IsScreenTransparent() bool {
 func (*UserInterface) SetScreenTransparent(transparent bool) {
 }
 
+func (*UserInterface) SetLinearBlendingEnabled(enabled bool) {
+}
+
+func (*UserInterface) IsLinearBlendingEnabled() bool {
+	return false
+}
+
 func (*UserInterface) SetInitFocused(focused bool) {
 }
 
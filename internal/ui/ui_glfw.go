@@ -27,10 +27,12 @@ import (
 	"time"
 
 	"github.com/hajimehoshi/ebiten/v2/internal/devicescale"
+	"github.com/hajimehoshi/ebiten/v2/internal/frametiming"
 	"github.com/hajimehoshi/ebiten/v2/internal/glfw"
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicscommand"
 	"github.com/hajimehoshi/ebiten/v2/internal/hooks"
 	"github.com/hajimehoshi/ebiten/v2/internal/thread"
+	"github.com/hajimehoshi/ebiten/v2/internal/tracing"
 )
 
 func driverCursorModeToGLFWCursorMode(mode CursorMode) int {
@@ -62,6 +64,10 @@ type UserInterface struct {
 	maxWindowWidthInDIP  int
 	maxWindowHeightInDIP int
 
+	// windowAspectRatioNumer and windowAspectRatioDenom are 0 when the aspect ratio is not limited.
+	windowAspectRatioNumer int
+	windowAspectRatioDenom int
+
 	running              uint32
 	origPosX             int
 	origPosY             int
@@ -76,6 +82,13 @@ type UserInterface struct {
 	// setSizeCallbackEnabled must be accessed from the main thread.
 	setSizeCallbackEnabled bool
 
+	// borderlessFullscreen and origDecorated must be accessed from the main thread.
+	borderlessFullscreen bool
+	origDecorated        bool
+
+	// fullscreenToggleRequested must be accessed from the main thread.
+	fullscreenToggleRequested bool
+
 	// err must be accessed from the main thread.
 	err error
 
@@ -97,6 +110,7 @@ type UserInterface struct {
 	initWindowFloating       bool
 	initWindowMaximized      bool
 	initScreenTransparent    bool
+	initLinearBlending       bool
 	initFocused              bool
 
 	fpsModeInited bool
@@ -112,6 +126,11 @@ type UserInterface struct {
 
 	// t is the main thread == the rendering thread.
 	t thread.Thread
+
+	// frameThread is the thread looped on the main thread during UpdateWithoutMainLoop.
+	// Between frames, t is a no-op thread instead, as the caller is on the main thread.
+	frameThread *thread.OSThread
+
 	m sync.RWMutex
 }
 
@@ -185,6 +204,7 @@ func initialize() error {
 	v := m.GetVideoMode()
 	theUI.initFullscreenWidthInDIP = int(theUI.dipFromGLFWMonitorPixel(float64(v.Width), m))
 	theUI.initFullscreenHeightInDIP = int(theUI.dipFromGLFWMonitorPixel(float64(v.Height), m))
+	theGlobalState.setRefreshRate(float64(v.RefreshRate))
 
 	// Create system cursors. These cursors are destroyed at glfw.Terminate().
 	glfwSystemCursors[CursorShapeDefault] = nil
@@ -250,6 +270,21 @@ func getMonitorFromPosition(wx, wy int) *monitor {
 	return nil
 }
 
+// monitorByName returns a monitor with the given name, or returns nil if the monitor is not found.
+//
+// monitorByName must be called on the main thread.
+func monitorByName(name string) *glfw.Monitor {
+	if name == "" {
+		return nil
+	}
+	for _, m := range ensureMonitors() {
+		if m.m.GetName() == name {
+			return m.m
+		}
+	}
+	return nil
+}
+
 func (u *UserInterface) isRunning() bool {
 	return atomic.LoadUint32(&u.running) != 0
 }
@@ -281,6 +316,26 @@ func (u *UserInterface) setWindowSizeLimitsInDIP(minw, minh, maxw, maxh int) boo
 	return true
 }
 
+func (u *UserInterface) getWindowAspectRatio() (numer, denom int) {
+	u.m.RLock()
+	defer u.m.RUnlock()
+	return u.windowAspectRatioNumer, u.windowAspectRatioDenom
+}
+
+func (u *UserInterface) setWindowAspectRatio(numer, denom int) bool {
+	if numer <= 0 || denom <= 0 {
+		numer, denom = 0, 0
+	}
+	u.m.Lock()
+	defer u.m.Unlock()
+	if u.windowAspectRatioNumer == numer && u.windowAspectRatioDenom == denom {
+		return false
+	}
+	u.windowAspectRatioNumer = numer
+	u.windowAspectRatioDenom = denom
+	return true
+}
+
 func (u *UserInterface) isInitFullscreen() bool {
 	u.m.RLock()
 	v := u.initFullscreen
@@ -361,6 +416,19 @@ func (u *UserInterface) setInitScreenTransparent(transparent bool) {
 	u.m.Unlock()
 }
 
+func (u *UserInterface) isInitLinearBlendingEnabled() bool {
+	u.m.RLock()
+	v := u.initLinearBlending
+	u.m.RUnlock()
+	return v
+}
+
+func (u *UserInterface) setInitLinearBlendingEnabled(enabled bool) {
+	u.m.Lock()
+	u.initLinearBlending = enabled
+	u.m.Unlock()
+}
+
 func (u *UserInterface) getIconImages() []image.Image {
 	u.m.RLock()
 	i := u.iconImages
@@ -484,7 +552,7 @@ func (u *UserInterface) isFullscreen() bool {
 	if !u.isRunning() {
 		panic("ui: isFullscreen can't be called before the main loop starts")
 	}
-	return u.window.GetMonitor() != nil || u.isNativeFullscreen()
+	return u.window.GetMonitor() != nil || u.isNativeFullscreen() || u.borderlessFullscreen
 }
 
 func (u *UserInterface) IsFullscreen() bool {
@@ -518,6 +586,23 @@ func (u *UserInterface) SetFullscreen(fullscreen bool) {
 	})
 }
 
+func (u *UserInterface) OcclusionState() OcclusionState {
+	if !u.isRunning() {
+		return OcclusionStateVisible
+	}
+	s := OcclusionStateVisible
+	u.t.Call(func() {
+		if u.window.GetAttrib(glfw.Iconified) == glfw.True {
+			s = OcclusionStateMinimized
+			return
+		}
+		if u.isWindowOccludedByOS() {
+			s = OcclusionStateOccluded
+		}
+	})
+	return s
+}
+
 func (u *UserInterface) IsFocused() bool {
 	if !u.isRunning() {
 		return false
@@ -564,6 +649,23 @@ func (u *UserInterface) ScheduleFrame() {
 	glfw.PostEmptyEvent()
 }
 
+// NativeWindow returns the handles of the native window.
+// NativeWindow returns a zero value before the window is created.
+func (u *UserInterface) NativeWindow() NativeWindow {
+	if !u.isRunning() {
+		return NativeWindow{}
+	}
+
+	var w NativeWindow
+	u.t.Call(func() {
+		if u.window == nil {
+			return
+		}
+		w = u.nativeWindowHandles()
+	})
+	return w
+}
+
 func (u *UserInterface) CursorMode() CursorMode {
 	if !u.isRunning() {
 		return u.getInitCursorMode()
@@ -594,10 +696,25 @@ func (u *UserInterface) SetCursorMode(mode CursorMode) {
 		return
 	}
 	u.t.Call(func() {
-		u.window.SetInputMode(glfw.CursorMode, driverCursorModeToGLFWCursorMode(mode))
+		u.setGLFWCursorMode(mode)
 	})
 }
 
+// setGLFWCursorMode must be called from the main thread.
+func (u *UserInterface) setGLFWCursorMode(mode CursorMode) {
+	u.window.SetInputMode(glfw.CursorMode, driverCursorModeToGLFWCursorMode(mode))
+
+	// Use unaccelerated motion for a captured cursor where available, e.g., XInput2 on X11,
+	// the relative pointer protocol on Wayland, and raw input on Windows.
+	if glfw.RawMouseMotionSupported() {
+		v := glfw.False
+		if mode == CursorModeCaptured {
+			v = glfw.True
+		}
+		u.window.SetInputMode(glfw.RawMouseMotion, v)
+	}
+}
+
 func (u *UserInterface) CursorShape() CursorShape {
 	return u.getCursorShape()
 }
@@ -627,6 +744,10 @@ func (u *UserInterface) DeviceScaleFactor() float64 {
 	return f
 }
 
+func (u *UserInterface) SafeAreaInsets() (left, top, right, bottom float64) {
+	return 0, 0, 0, 0
+}
+
 // deviceScaleFactor must be called from the main thread.
 func (u *UserInterface) deviceScaleFactor(monitor *glfw.Monitor) float64 {
 	// It is rare, but monitor can be nil when glfw.GetPrimaryMonitor returns nil.
@@ -672,7 +793,7 @@ func (u *UserInterface) createWindow(width, height int) error {
 		u.window.MakeContextCurrent()
 	}
 
-	u.window.SetInputMode(glfw.CursorMode, driverCursorModeToGLFWCursorMode(u.getInitCursorMode()))
+	u.setGLFWCursorMode(u.getInitCursorMode())
 	u.window.SetCursor(glfwSystemCursors[u.getCursorShape()])
 	u.window.SetTitle(u.title)
 	// Icons are set after every frame. They don't have to be cared here.
@@ -839,6 +960,11 @@ func (u *UserInterface) init() error {
 	glfw.WindowHint(glfw.TransparentFramebuffer, transparent)
 	graphicscommand.SetTransparent(u.isInitScreenTransparent())
 
+	if u.isInitLinearBlendingEnabled() && graphicscommand.IsGL() {
+		glfw.WindowHint(glfw.SRGBCapable, glfw.True)
+	}
+	graphicscommand.SetLinearBlendingEnabled(u.isInitLinearBlendingEnabled())
+
 	// Before creating a window, set it unresizable no matter what u.isInitWindowResizable() is (#1987).
 	// Making the window resizable here doesn't work correctly when switching to enable resizing.
 	resizable := glfw.False
@@ -980,7 +1106,13 @@ func (u *UserInterface) update() (float64, float64, error) {
 		return 0, 0, err
 	}
 
-	for !u.isRunnableOnUnfocused() && u.window.GetAttrib(glfw.Focused) == 0 && !u.window.ShouldClose() {
+	if u.fullscreenToggleRequested {
+		u.fullscreenToggleRequested = false
+		u.setWindowSizeInDIP(u.windowWidthInDIP, u.windowHeightInDIP, !u.isFullscreen())
+	}
+
+	for !u.isRunnableOnUnfocused() && u.window.GetAttrib(glfw.Focused) == 0 && !u.window.ShouldClose() && !theGlobalState.isTerminationRequested() {
+		u.setGameSuspended(true)
 		if err := hooks.SuspendAudio(); err != nil {
 			return 0, 0, err
 		}
@@ -992,109 +1124,157 @@ func (u *UserInterface) update() (float64, float64, error) {
 		return 0, 0, err
 	}
 
+	// An iconified window is treated as suspended even if the game is runnable on unfocused.
+	u.setGameSuspended(u.window.GetAttrib(glfw.Iconified) == glfw.True)
+
 	return outsideWidth, outsideHeight, nil
 }
 
+// setGameSuspended notifies the game that the application is suspended or resumed.
+//
+// setGameSuspended must be called from the main thread.
+func (u *UserInterface) setGameSuspended(suspended bool) {
+	if u.context.isSuspended() == suspended {
+		return
+	}
+
+	// In the game's OnSuspend or OnResume, u.t.Call might be called.
+	// In order to call it safely, use runOnAnotherThreadFromMainThread.
+	u.runOnAnotherThreadFromMainThread(func() {
+		u.context.setSuspended(suspended)
+	})
+}
+
 func (u *UserInterface) loop() error {
 	defer u.t.Call(glfw.Terminate)
 
 	for {
-		var unfocused bool
-
-		// On Windows, the focusing state might be always false (#987).
-		// On Windows, even if a window is in another workspace, vsync seems to work.
-		// Then let's assume the window is always 'focused' as a workaround.
-		if runtime.GOOS != "windows" {
-			unfocused = u.window.GetAttrib(glfw.Focused) == glfw.False
+		if err := u.updateGame(); err != nil {
+			return err
 		}
+	}
+}
 
-		var t1, t2 time.Time
+// updateGame proceeds the game by one frame.
+func (u *UserInterface) updateGame() error {
+	var unfocused bool
 
-		if unfocused {
-			t1 = time.Now()
-		}
+	// On Windows, the focusing state might be always false (#987).
+	// On Windows, even if a window is in another workspace, vsync seems to work.
+	// Then let's assume the window is always 'focused' as a workaround.
+	if runtime.GOOS != "windows" {
+		unfocused = u.window.GetAttrib(glfw.Focused) == glfw.False
+	}
 
-		var outsideWidth, outsideHeight float64
-		var deviceScaleFactor float64
-		var err error
-		if u.t.Call(func() {
-			outsideWidth, outsideHeight, err = u.update()
-			deviceScaleFactor = u.deviceScaleFactor(u.currentMonitor())
-		}); err != nil {
-			return err
-		}
+	var t1, t2 time.Time
 
-		if err := u.context.updateFrame(outsideWidth, outsideHeight, deviceScaleFactor); err != nil {
-			return err
+	if unfocused {
+		t1 = time.Now()
+	}
+
+	var outsideWidth, outsideHeight float64
+	var deviceScaleFactor float64
+	var err error
+	if u.t.Call(func() {
+		outsideWidth, outsideHeight, err = u.update()
+		m := u.currentMonitor()
+		deviceScaleFactor = u.deviceScaleFactor(m)
+		// The refresh rate can be changed by moving the window to another monitor or by changing the video mode.
+		if m != nil {
+			if v := m.GetVideoMode(); v != nil {
+				theGlobalState.setRefreshRate(float64(v.RefreshRate))
+			}
 		}
+		u.input.updateKeyboardLayout()
+	}); err != nil {
+		return err
+	}
 
-		// Create icon images in a different goroutine (#1478).
-		// In the fullscreen mode, SetIcon fails (#1578).
-		if imgs := u.getIconImages(); len(imgs) > 0 && !u.isFullscreen() {
-			u.setIconImages(nil)
-
-			// Convert the icons in the different goroutine, as (*ebiten.Image).At cannot be invoked
-			// from this goroutine. At works only in between BeginFrame and EndFrame.
-			go func() {
-				newImgs := make([]image.Image, len(imgs))
-				for i, img := range imgs {
-					// TODO: If img is not *ebiten.Image, this converting is not necessary.
-					// However, this package cannot refer *ebiten.Image due to the package
-					// dependencies.
-
-					b := img.Bounds()
-					rgba := image.NewRGBA(b)
-					for j := b.Min.Y; j < b.Max.Y; j++ {
-						for i := b.Min.X; i < b.Max.X; i++ {
-							rgba.Set(i, j, img.At(i, j))
-						}
+	if err := u.context.updateFrame(outsideWidth, outsideHeight, deviceScaleFactor); err != nil {
+		return err
+	}
+
+	// Create icon images in a different goroutine (#1478).
+	// In the fullscreen mode, SetIcon fails (#1578).
+	if imgs := u.getIconImages(); len(imgs) > 0 && !u.isFullscreen() {
+		u.setIconImages(nil)
+
+		// Convert the icons in the different goroutine, as (*ebiten.Image).At cannot be invoked
+		// from this goroutine. At works only in between BeginFrame and EndFrame.
+		// Capture the thread now, as u.t is swapped between frames in UpdateWithoutMainLoop.
+		t := u.t
+		go func() {
+			newImgs := make([]image.Image, len(imgs))
+			for i, img := range imgs {
+				// TODO: If img is not *ebiten.Image, this converting is not necessary.
+				// However, this package cannot refer *ebiten.Image due to the package
+				// dependencies.
+
+				b := img.Bounds()
+				rgba := image.NewRGBA(b)
+				for j := b.Min.Y; j < b.Max.Y; j++ {
+					for i := b.Min.X; i < b.Max.X; i++ {
+						rgba.Set(i, j, img.At(i, j))
 					}
-					newImgs[i] = rgba
 				}
+				newImgs[i] = rgba
+			}
 
-				u.t.Call(func() {
-					// In the fullscreen mode, reset the icon images and try again later.
-					if u.isFullscreen() {
-						u.setIconImages(imgs)
-						return
-					}
-					u.window.SetIcon(newImgs)
-				})
-			}()
-		}
+			t.Call(func() {
+				// In the fullscreen mode, reset the icon images and try again later.
+				if u.isFullscreen() {
+					u.setIconImages(imgs)
+					return
+				}
+				u.window.SetIcon(newImgs)
+			})
+		}()
+	}
 
-		// swapBuffers also checks IsGL, so this condition is redundant.
-		// However, (*thread).Call is not good for performance due to channels.
-		// Let's avoid this whenever possible (#1367).
-		if graphicscommand.IsGL() {
-			u.t.Call(u.swapBuffers)
-		}
+	// swapBuffers also checks IsGL, so this condition is redundant.
+	// However, (*thread).Call is not good for performance due to channels.
+	// Let's avoid this whenever possible (#1367).
+	if graphicscommand.IsGL() {
+		u.t.Call(u.swapBuffers)
+	}
 
-		if unfocused {
-			t2 = time.Now()
-		}
+	if unfocused {
+		t2 = time.Now()
+	}
 
-		// When a window is not focused, SwapBuffers might return immediately and CPU might be busy.
-		// Mitigate this by sleeping (#982).
-		if unfocused {
-			d := t2.Sub(t1)
-			const wait = time.Second / 60
-			if d < wait {
-				time.Sleep(wait - d)
-			}
+	// When a window is not focused, SwapBuffers might return immediately and CPU might be busy.
+	// Mitigate this by sleeping (#982).
+	if unfocused {
+		d := t2.Sub(t1)
+		const wait = time.Second / 60
+		if d < wait {
+			time.Sleep(wait - d)
 		}
 	}
+
+	return nil
 }
 
 // swapBuffers must be called from the main thread.
 func (u *UserInterface) swapBuffers() {
 	if graphicscommand.IsGL() {
+		t := time.Now()
+		endRegion := tracing.StartRegion("ebiten.present")
 		u.window.SwapBuffers()
+		endRegion()
+		frametiming.Add(frametiming.PhasePresentWait, time.Since(t))
 	}
 }
 
 // updateWindowSizeLimits must be called from the main thread.
 func (u *UserInterface) updateWindowSizeLimits() {
+	// The window in borderless fullscreen must cover the monitor regardless of the limits.
+	if u.borderlessFullscreen {
+		u.window.SetSizeLimits(glfw.DontCare, glfw.DontCare, glfw.DontCare, glfw.DontCare)
+		u.window.SetAspectRatio(glfw.DontCare, glfw.DontCare)
+		return
+	}
+
 	m := u.currentMonitor()
 	minw, minh, maxw, maxh := u.getWindowSizeLimitsInDIP()
 
@@ -1120,6 +1300,13 @@ func (u *UserInterface) updateWindowSizeLimits() {
 		maxh = int(u.dipToGLFWPixel(float64(maxh), m))
 	}
 	u.window.SetSizeLimits(minw, minh, maxw, maxh)
+
+	// The aspect ratio is applied when the user resizes the window.
+	if n, d := u.getWindowAspectRatio(); n > 0 && d > 0 {
+		u.window.SetAspectRatio(n, d)
+	} else {
+		u.window.SetAspectRatio(glfw.DontCare, glfw.DontCare)
+	}
 }
 
 // adjustWindowSizeBasedOnSizeLimitsInDIP adjust the size based on the window size limits.
@@ -1212,6 +1399,8 @@ func (u *UserInterface) setWindowSizeInDIPImpl(width, height int, fullscreen boo
 
 		if u.isNativeFullscreenAvailable() {
 			u.setNativeFullscreen(fullscreen)
+		} else if u.isBorderlessFullscreenAvailable() {
+			u.setBorderlessFullscreen(true)
 		} else {
 			m := u.currentMonitor()
 			v := m.GetVideoMode()
@@ -1225,12 +1414,17 @@ func (u *UserInterface) setWindowSizeInDIPImpl(width, height int, fullscreen boo
 			}
 		}
 	} else {
+		if u.isNativeFullscreenAvailable() && u.isNativeFullscreen() {
+			u.setNativeFullscreen(false)
+		} else if u.borderlessFullscreen {
+			u.setBorderlessFullscreen(false)
+		}
+
+		// The minimum width depends on the window decoration, so compute it after the decoration is restored.
 		if mw := u.minimumWindowWidth(); width < mw {
 			width = mw
 		}
-		if u.isNativeFullscreenAvailable() && u.isNativeFullscreen() {
-			u.setNativeFullscreen(false)
-		} else if !u.isNativeFullscreenAvailable() && u.window.GetMonitor() != nil {
+		if !u.isNativeFullscreenAvailable() && u.window.GetMonitor() != nil {
 			ww := int(u.dipToGLFWPixel(float64(width), u.currentMonitor()))
 			wh := int(u.dipToGLFWPixel(float64(height), u.currentMonitor()))
 			u.window.SetMonitor(nil, 0, 0, ww, wh, 0)
@@ -1264,6 +1458,40 @@ func (u *UserInterface) setWindowSizeInDIPImpl(width, height int, fullscreen boo
 	}
 }
 
+// setBorderlessFullscreen makes the window cover the current monitor without decorations, or restores the window.
+//
+// Unlike SetMonitor, this changes neither the video mode nor the exclusiveness of the window,
+// then the framebuffer and the swap chain are kept and toggling fullscreen doesn't cause a hitch or a black frame.
+// The position and the size of the window are restored by the caller.
+//
+// setBorderlessFullscreen must be called from the main thread.
+func (u *UserInterface) setBorderlessFullscreen(fullscreen bool) {
+	if !fullscreen {
+		u.borderlessFullscreen = false
+		if u.origDecorated {
+			u.setWindowDecorated(true)
+		}
+		return
+	}
+
+	m := u.currentMonitor()
+	x, y := m.GetPos()
+	v := m.GetVideoMode()
+
+	u.origDecorated = u.window.GetAttrib(glfw.Decorated) == glfw.True
+	u.borderlessFullscreen = true
+	u.updateWindowSizeLimits()
+	if u.origDecorated {
+		u.setWindowDecorated(false)
+	}
+	u.window.SetPos(x, y)
+	if w, h := u.window.GetSize(); w != v.Width || h != v.Height {
+		u.waitForFramebufferSizeCallback(u.window, func() {
+			u.window.SetSize(v.Width, v.Height)
+		})
+	}
+}
+
 // updateVsync must be called on the main thread.
 func (u *UserInterface) updateVsync() {
 	if graphicscommand.IsGL() {
@@ -1339,6 +1567,18 @@ func (u *UserInterface) IsScreenTransparent() bool {
 	return val
 }
 
+func (u *UserInterface) SetLinearBlendingEnabled(enabled bool) {
+	if !u.isRunning() {
+		u.setInitLinearBlendingEnabled(enabled)
+		return
+	}
+	panic("ui: SetLinearBlendingEnabled can't be called after the main loop starts")
+}
+
+func (u *UserInterface) IsLinearBlendingEnabled() bool {
+	return u.isInitLinearBlendingEnabled() && graphicscommand.IsLinearBlendingSupported()
+}
+
 func (u *UserInterface) resetForTick() {
 	u.input.resetForTick()
 
@@ -1513,6 +1753,26 @@ func (u *UserInterface) setWindowResizingMode(mode WindowResizingMode) {
 // x and y are the position in device-independent pixels.
 //
 // setWindowPositionInDIP must be called from the main thread.
+// windowPositionInDIP returns the window position relative to the current monitor.
+// In fullscreen, windowPositionInDIP returns the position before the fullscreen.
+//
+// windowPositionInDIP must be called from the main thread.
+func (u *UserInterface) windowPositionInDIP() (int, int) {
+	var wx, wy int
+	if u.isFullscreen() && !u.isNativeFullscreenAvailable() {
+		wx, wy = u.origPos()
+	} else {
+		wx, wy = u.window.GetPos()
+	}
+	m := u.currentMonitor()
+	mx, my := m.GetPos()
+	wx -= mx
+	wy -= my
+	xf := u.dipFromGLFWPixel(float64(wx), m)
+	yf := u.dipFromGLFWPixel(float64(wy), m)
+	return int(xf), int(yf)
+}
+
 func (u *UserInterface) setWindowPositionInDIP(x, y int, monitor *glfw.Monitor) {
 	if u.setSizeCallbackEnabled {
 		u.setSizeCallbackEnabled = false
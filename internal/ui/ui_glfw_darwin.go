@@ -215,6 +215,11 @@ package ui
 //   *y = (int)(location.y);
 // }
 //
+// static bool isWindowOccluded(uintptr_t windowPtr) {
+//   NSWindow* window = (NSWindow*)windowPtr;
+//   return ([window occlusionState] & NSWindowOcclusionStateVisible) == 0;
+// }
+//
 // static void setAllowFullscreen(uintptr_t windowPtr, bool allowFullscreen) {
 //   NSWindow* window = (NSWindow*)windowPtr;
 //   if (allowFullscreen) {
@@ -296,6 +301,14 @@ func (u *UserInterface) nativeWindow() uintptr {
 	return u.window.GetCocoaWindow()
 }
 
+// nativeWindowHandles must be called from the main thread.
+func (u *UserInterface) nativeWindowHandles() NativeWindow {
+	return NativeWindow{
+		Kind:   NativeWindowKindCocoa,
+		Window: u.window.GetCocoaWindow(),
+	}
+}
+
 func (u *UserInterface) isNativeFullscreen() bool {
 	return bool(C.isNativeFullscreen(C.uintptr_t(u.window.GetCocoaWindow())))
 }
@@ -310,6 +323,16 @@ func (u *UserInterface) isNativeFullscreenAvailable() bool {
 	return true
 }
 
+// isWindowOccludedByOS must be called from the main thread.
+func (u *UserInterface) isWindowOccludedByOS() bool {
+	return bool(C.isWindowOccluded(C.uintptr_t(u.window.GetCocoaWindow())))
+}
+
+func (u *UserInterface) isBorderlessFullscreenAvailable() bool {
+	// The native fullscreen is used instead.
+	return false
+}
+
 func (u *UserInterface) setNativeFullscreen(fullscreen bool) {
 	// Toggling fullscreen might ignore events like keyUp. Ensure that events are fired.
 	glfw.WaitEventsTimeout(0.1)
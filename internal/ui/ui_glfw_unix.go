@@ -165,8 +165,17 @@ func monitorFromWindowByOS(_ *glfw.Window) *glfw.Monitor {
 }
 
 func (u *UserInterface) nativeWindow() uintptr {
-	// TODO: Implement this.
-	return 0
+	return u.window.GetX11Window()
+}
+
+// nativeWindowHandles must be called from the main thread.
+func (u *UserInterface) nativeWindowHandles() NativeWindow {
+	// GLFW uses X11 on Linux and BSDs, and XWayland on Wayland sessions.
+	return NativeWindow{
+		Kind:    NativeWindowKindX11,
+		Window:  u.window.GetX11Window(),
+		Display: glfw.GetX11Display(),
+	}
 }
 
 func (u *UserInterface) isNativeFullscreen() bool {
@@ -182,6 +191,17 @@ func (u *UserInterface) isNativeFullscreenAvailable() bool {
 	return false
 }
 
+// isWindowOccludedByOS must be called from the main thread.
+func (u *UserInterface) isWindowOccludedByOS() bool {
+	// TODO: Detect the occlusion e.g. by VisibilityNotify events on X11.
+	return false
+}
+
+func (u *UserInterface) isBorderlessFullscreenAvailable() bool {
+	// SetMonitor with the current video mode already uses _NET_WM_STATE_FULLSCREEN without a mode switch.
+	return false
+}
+
 func (u *UserInterface) setNativeFullscreen(fullscreen bool) {
 	panic(fmt.Sprintf("ui: setNativeFullscreen is not implemented in this environment: %s", runtime.GOOS))
 }
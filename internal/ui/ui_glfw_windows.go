@@ -30,6 +30,7 @@ import (
 const (
 	smCyCaption             = 4
 	monitorDefaultToNearest = 2
+	dwmwaCloaked            = 14
 )
 
 type rect struct {
@@ -57,6 +58,9 @@ var (
 	procMonitorFromWindow = user32.NewProc("MonitorFromWindow")
 	procGetMonitorInfoW   = user32.NewProc("GetMonitorInfoW")
 	procGetCursorPos      = user32.NewProc("GetCursorPos")
+
+	dwmapi                    = windows.NewLazySystemDLL("dwmapi.dll")
+	procDwmGetWindowAttribute = dwmapi.NewProc("DwmGetWindowAttribute")
 )
 
 func getSystemMetrics(nIndex int) (int32, error) {
@@ -97,6 +101,17 @@ func getCursorPos() (int32, int32, error) {
 	return pt.x, pt.y, nil
 }
 
+func dwmGetWindowAttribute(hwnd windows.HWND, dwAttribute uint32, pvAttribute unsafe.Pointer, cbAttribute uint32) error {
+	if err := procDwmGetWindowAttribute.Find(); err != nil {
+		return err
+	}
+	r, _, _ := procDwmGetWindowAttribute.Call(uintptr(hwnd), uintptr(dwAttribute), uintptr(pvAttribute), uintptr(cbAttribute))
+	if r != uintptr(windows.S_OK) {
+		return fmt.Errorf("ui: DwmGetWindowAttribute failed: HRESULT(%d)", r)
+	}
+	return nil
+}
+
 // clearVideoModeScaleCache must be called from the main thread.
 func clearVideoModeScaleCache() {}
 
@@ -183,6 +198,14 @@ func (u *UserInterface) nativeWindow() uintptr {
 	return u.window.GetWin32Window()
 }
 
+// nativeWindowHandles must be called from the main thread.
+func (u *UserInterface) nativeWindowHandles() NativeWindow {
+	return NativeWindow{
+		Kind:   NativeWindowKindWin32,
+		Window: u.window.GetWin32Window(),
+	}
+}
+
 func (u *UserInterface) isNativeFullscreen() bool {
 	return false
 }
@@ -196,6 +219,22 @@ func (u *UserInterface) isNativeFullscreenAvailable() bool {
 	return false
 }
 
+// isWindowOccludedByOS must be called from the main thread.
+func (u *UserInterface) isWindowOccludedByOS() bool {
+	// A cloaked window is not shown e.g. when the window is in another virtual desktop.
+	// As the OpenGL driver doesn't use DXGI, the occlusion by other windows is not detected.
+	var cloaked uint32
+	if err := dwmGetWindowAttribute(windows.HWND(u.window.GetWin32Window()), dwmwaCloaked, unsafe.Pointer(&cloaked), uint32(unsafe.Sizeof(cloaked))); err != nil {
+		return false
+	}
+	return cloaked != 0
+}
+
+func (u *UserInterface) isBorderlessFullscreenAvailable() bool {
+	// Exclusive fullscreen by SetMonitor causes a display mode switch and a black frame on Windows.
+	return true
+}
+
 func (u *UserInterface) setNativeFullscreen(fullscreen bool) {
 	panic(fmt.Sprintf("ui: setNativeFullscreen is not implemented in this environment: %s", runtime.GOOS))
 }
@@ -15,6 +15,7 @@
 package ui
 
 import (
+	"strings"
 	"syscall/js"
 	"time"
 
@@ -57,8 +58,17 @@ type UserInterface struct {
 	cursorShape         CursorShape
 	onceUpdateCalled    bool
 
+	// fullscreenRequested and pointerLockRequested are requests waiting for the next user gesture.
+	fullscreenRequested  bool
+	pointerLockRequested bool
+
 	lastDeviceScaleFactor float64
 
+	// keyboardLayout is the serialized keyboard layout map of navigator.keyboard.
+	keyboardLayout string
+
+	refreshRateEstimator refreshRateEstimator
+
 	context *contextImpl
 	input   Input
 }
@@ -82,6 +92,8 @@ var (
 	canvas                js.Value
 	requestAnimationFrame = js.Global().Get("requestAnimationFrame")
 	setTimeout            = js.Global().Get("setTimeout")
+	navigator             = js.Global().Get("navigator")
+	safeAreaProbe         js.Value
 	go2cpp                = js.Global().Get("go2cpp")
 )
 
@@ -91,7 +103,7 @@ var (
 )
 
 func init() {
-	if go2cpp.Truthy() {
+	if go2cpp.Truthy() || !document.Truthy() {
 		return
 	}
 	documentHasFocus = document.Get("hasFocus").Call("bind", document)
@@ -99,6 +111,9 @@ func init() {
 }
 
 func (u *UserInterface) ScreenSizeInFullscreen() (int, int) {
+	if isWorker {
+		return int(theWorkerState.width), int(theWorkerState.height)
+	}
 	return window.Get("innerWidth").Int(), window.Get("innerHeight").Int()
 }
 
@@ -109,15 +124,16 @@ func (u *UserInterface) SetFullscreen(fullscreen bool) {
 	if !document.Truthy() {
 		return
 	}
+	u.fullscreenRequested = false
 	if fullscreen == document.Get("fullscreenElement").Truthy() {
 		return
 	}
 	if fullscreen {
-		f := canvas.Get("requestFullscreen")
-		if !f.Truthy() {
-			f = canvas.Get("webkitRequestFullscreen")
+		if !hasTransientUserActivation() {
+			u.fullscreenRequested = true
+			return
 		}
-		f.Call("bind", canvas).Invoke()
+		requestFullscreen()
 		return
 	}
 	f := document.Get("exitFullscreen")
@@ -127,6 +143,42 @@ func (u *UserInterface) SetFullscreen(fullscreen bool) {
 	f.Call("bind", document).Invoke()
 }
 
+func requestFullscreen() {
+	f := canvas.Get("requestFullscreen")
+	if !f.Truthy() {
+		f = canvas.Get("webkitRequestFullscreen")
+	}
+	f.Call("bind", canvas).Invoke()
+}
+
+// hasTransientUserActivation reports whether the page is being activated by a user gesture.
+// Some APIs like requestFullscreen and requestPointerLock fail without a user gesture.
+func hasTransientUserActivation() bool {
+	a := navigator.Get("userActivation")
+	if !a.Truthy() {
+		// The state is unknown. Assume that the page is activated and let the browser decide.
+		return true
+	}
+	return a.Get("isActive").Bool()
+}
+
+// processUserGestureRequests executes the requests that have been waiting for a user gesture.
+// processUserGestureRequests must be called in a user gesture event handler.
+func (u *UserInterface) processUserGestureRequests() {
+	if u.fullscreenRequested {
+		u.fullscreenRequested = false
+		if !document.Get("fullscreenElement").Truthy() {
+			requestFullscreen()
+		}
+	}
+	if u.pointerLockRequested {
+		u.pointerLockRequested = false
+		if u.cursorMode == CursorModeCaptured {
+			canvas.Call("requestPointerLock")
+		}
+	}
+}
+
 func (u *UserInterface) IsFullscreen() bool {
 	if !document.Truthy() {
 		return false
@@ -137,6 +189,14 @@ func (u *UserInterface) IsFullscreen() bool {
 	return true
 }
 
+func (u *UserInterface) OcclusionState() OcclusionState {
+	// The page visibility doesn't distinguish a minimized browser window from a background tab.
+	if !go2cpp.Truthy() && (isWorker && theWorkerState.hidden || !isWorker && document.Truthy() && documentHidden.Invoke().Bool()) {
+		return OcclusionStateOccluded
+	}
+	return OcclusionStateVisible
+}
+
 func (u *UserInterface) IsFocused() bool {
 	return u.isFocused()
 }
@@ -165,7 +225,7 @@ func (u *UserInterface) CursorMode() CursorMode {
 }
 
 func (u *UserInterface) SetCursorMode(mode CursorMode) {
-	if !canvas.Truthy() {
+	if !canvas.Truthy() || !document.Truthy() {
 		return
 	}
 	if u.cursorMode == mode {
@@ -173,6 +233,7 @@ func (u *UserInterface) SetCursorMode(mode CursorMode) {
 	}
 	// Remember the previous cursor mode in the case when the pointer lock exits by pressing ESC.
 	u.cursorPrevMode = u.cursorMode
+	u.pointerLockRequested = false
 	if u.cursorMode == CursorModeCaptured {
 		document.Call("exitPointerLock")
 	}
@@ -183,6 +244,10 @@ func (u *UserInterface) SetCursorMode(mode CursorMode) {
 	case CursorModeHidden:
 		canvas.Get("style").Set("cursor", stringNone)
 	case CursorModeCaptured:
+		if !hasTransientUserActivation() {
+			u.pointerLockRequested = true
+			return
+		}
 		canvas.Call("requestPointerLock")
 	}
 }
@@ -194,6 +259,10 @@ func (u *UserInterface) recoverCursorMode() {
 	u.SetCursorMode(u.cursorPrevMode)
 }
 
+func (u *UserInterface) NativeWindow() NativeWindow {
+	return NativeWindow{}
+}
+
 func (u *UserInterface) CursorShape() CursorShape {
 	if !canvas.Truthy() {
 		return CursorShapeDefault
@@ -202,7 +271,7 @@ func (u *UserInterface) CursorShape() CursorShape {
 }
 
 func (u *UserInterface) SetCursorShape(shape CursorShape) {
-	if !canvas.Truthy() {
+	if !canvas.Truthy() || !document.Truthy() {
 		return
 	}
 	if u.cursorShape == shape {
@@ -216,9 +285,23 @@ func (u *UserInterface) SetCursorShape(shape CursorShape) {
 }
 
 func (u *UserInterface) DeviceScaleFactor() float64 {
+	if isWorker {
+		return theWorkerState.deviceScaleFactor
+	}
 	return devicescale.GetAt(0, 0)
 }
 
+func (u *UserInterface) SafeAreaInsets() (left, top, right, bottom float64) {
+	if !safeAreaProbe.Truthy() {
+		return 0, 0, 0, 0
+	}
+	style := window.Call("getComputedStyle", safeAreaProbe)
+	parse := func(name string) float64 {
+		return js.Global().Call("parseFloat", style.Get(name)).Float()
+	}
+	return parse("paddingLeft"), parse("paddingTop"), parse("paddingRight"), parse("paddingBottom")
+}
+
 func (u *UserInterface) outsideSize() (float64, float64) {
 	switch {
 	case document.Truthy():
@@ -230,6 +313,8 @@ func (u *UserInterface) outsideSize() (float64, float64) {
 		w := go2cpp.Get("screenWidth").Float()
 		h := go2cpp.Get("screenHeight").Float()
 		return w, h
+	case isWorker:
+		return theWorkerState.width, theWorkerState.height
 	default:
 		// Node.js
 		return 640, 480
@@ -247,6 +332,12 @@ func (u *UserInterface) isFocused() bool {
 	if go2cpp.Truthy() {
 		return true
 	}
+	if isWorker {
+		return theWorkerState.focused && !theWorkerState.hidden
+	}
+	if !document.Truthy() {
+		return true
+	}
 
 	if !documentHasFocus.Invoke().Bool() {
 		return false
@@ -257,7 +348,28 @@ func (u *UserInterface) isFocused() bool {
 	return true
 }
 
+// isGameSuspended reports whether the game should be notified as suspended.
+// In addition to suspended(), a hidden document (e.g. a background tab) is treated as suspended.
+func (u *UserInterface) isGameSuspended() bool {
+	if u.suspended() {
+		return true
+	}
+	if go2cpp.Truthy() {
+		return false
+	}
+	if isWorker {
+		return theWorkerState.hidden
+	}
+	if !document.Truthy() {
+		return false
+	}
+	return documentHidden.Invoke().Bool()
+}
+
 func (u *UserInterface) update() error {
+	if u.context != nil {
+		u.context.setSuspended(u.isGameSuspended())
+	}
 	if u.suspended() {
 		return hooks.SuspendAudio()
 	}
@@ -274,6 +386,7 @@ func (u *UserInterface) updateImpl(force bool) error {
 	}
 
 	gamepad.Update()
+	u.input.updateGamepadEvents()
 	u.input.updateForGo2Cpp()
 
 	a := u.DeviceScaleFactor()
@@ -341,6 +454,12 @@ func (u *UserInterface) loop(game Game) <-chan error {
 
 	// TODO: Should cf be released after the game ends?
 	cf = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		// A requestAnimationFrame callback takes the timestamp of the frame in milliseconds.
+		if u.fpsMode == FPSModeVsyncOn && len(args) > 0 {
+			u.refreshRateEstimator.update(args[0].Float() / 1000)
+		} else {
+			u.refreshRateEstimator.reset()
+		}
 		// f can be blocked but callbacks must not be blocked. Create a goroutine (#1161).
 		go f()
 		return nil
@@ -372,6 +491,7 @@ func (u *UserInterface) loop(game Game) <-chan error {
 		for {
 			select {
 			case <-t.C:
+				u.context.setSuspended(u.isGameSuspended())
 				if u.suspended() {
 					if err := hooks.SuspendAudio(); err != nil {
 						errCh <- err
@@ -411,10 +531,14 @@ func init() {
 
 	// Adjust the initial scale to 1.
 	// https://developer.mozilla.org/en/docs/Mozilla/Mobile/Viewport_meta_tag
-	meta := document.Call("createElement", "meta")
-	meta.Set("name", "viewport")
-	meta.Set("content", "width=device-width, initial-scale=1")
-	document.Get("head").Call("appendChild", meta)
+	// If the page already has its own viewport setting, e.g. with viewport-fit=cover to cover the display cutouts,
+	// respect it.
+	if !document.Call("querySelector", `meta[name="viewport"]`).Truthy() {
+		meta := document.Call("createElement", "meta")
+		meta.Set("name", "viewport")
+		meta.Set("content", "width=device-width, initial-scale=1")
+		document.Get("head").Call("appendChild", meta)
+	}
 
 	canvas = document.Call("createElement", "canvas")
 	canvas.Set("width", 16)
@@ -445,6 +569,18 @@ func init() {
 
 	setCanvasEventHandlers(canvas)
 
+	// safeAreaProbe is an invisible element to get the safe area insets via CSS.
+	safeAreaProbe = document.Call("createElement", "div")
+	probeStyle := safeAreaProbe.Get("style")
+	probeStyle.Set("position", "fixed")
+	probeStyle.Set("visibility", "hidden")
+	probeStyle.Set("pointerEvents", "none")
+	probeStyle.Set("paddingLeft", "env(safe-area-inset-left)")
+	probeStyle.Set("paddingTop", "env(safe-area-inset-top)")
+	probeStyle.Set("paddingRight", "env(safe-area-inset-right)")
+	probeStyle.Set("paddingBottom", "env(safe-area-inset-bottom)")
+	document.Get("body").Call("appendChild", safeAreaProbe)
+
 	// Pointer Lock
 	document.Call("addEventListener", "pointerlockchange", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
 		if document.Get("pointerLockElement").Truthy() {
@@ -471,9 +607,63 @@ func init() {
 		js.Global().Get("console").Call("error", "webkitfullscreenerror event is fired. 'sandbox=\"fullscreen\"' might be required at an iframe. This function on browsers must be called as a result of a gestural interaction or orientation change.")
 		return nil
 	}))
+	// Browsers don't fire the 'layoutchange' event of navigator.keyboard.
+	// Instead, query the keyboard layout map at the start, and on focus and keydown events.
+	theUI.updateKeyboardLayout()
+}
+
+// updateKeyboardLayout detects a keyboard layout change by comparing the keyboard layout maps.
+//
+// navigator.keyboard.getLayoutMap is available only on some browsers like Chrome.
+// The result is given asynchronously, and a change is notified at the callback.
+func (u *UserInterface) updateKeyboardLayout() {
+	k := navigator.Get("keyboard")
+	if !k.Truthy() || !k.Get("getLayoutMap").Truthy() {
+		return
+	}
+
+	var then, catch js.Func
+	then = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		defer then.Release()
+		defer catch.Release()
+
+		var b strings.Builder
+		f := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			// The arguments are the value and the key.
+			b.WriteString(args[1].String())
+			b.WriteByte(0)
+			b.WriteString(args[0].String())
+			b.WriteByte(0)
+			return nil
+		})
+		args[0].Call("forEach", f)
+		f.Release()
+
+		layout := b.String()
+		if layout == u.keyboardLayout {
+			return nil
+		}
+		if u.keyboardLayout != "" {
+			theGlobalState.notifyKeyboardLayoutChange()
+		}
+		u.keyboardLayout = layout
+		return nil
+	})
+	catch = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		// getLayoutMap is rejected e.g. in a cross-origin iframe. Ignore this.
+		defer then.Release()
+		defer catch.Release()
+		return nil
+	})
+	k.Call("getLayoutMap").Call("then", then).Call("catch", catch)
 }
 
 func setWindowEventHandlers(v js.Value) {
+	v.Call("addEventListener", "focus", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		// The keyboard layout might be changed while the window is not focused.
+		theUI.updateKeyboardLayout()
+		return nil
+	}))
 	v.Call("addEventListener", "resize", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
 		theUI.updateScreenSize()
 		if err := theUI.updateImpl(true); err != nil {
@@ -492,6 +682,14 @@ func setCanvasEventHandlers(v js.Value) {
 		e := args[0]
 		// Don't 'preventDefault' on keydown events or keypress events wouldn't work (#715).
 		theUI.input.updateFromEvent(e)
+		theUI.updateKeyboardLayout()
+
+		if theGlobalState.windowBehavior()&WindowBehaviorAltEnterFullscreen != 0 &&
+			e.Get("altKey").Truthy() && e.Get("code").Equal(uiKeyToJSKey[KeyEnter]) && !e.Get("repeat").Truthy() {
+			// The request is processed at processUserGestureRequests.
+			theUI.SetFullscreen(!theUI.IsFullscreen())
+		}
+		theUI.processUserGestureRequests()
 		return nil
 	}))
 	v.Call("addEventListener", "keypress", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
@@ -504,6 +702,7 @@ func setCanvasEventHandlers(v js.Value) {
 		e := args[0]
 		e.Call("preventDefault")
 		theUI.input.updateFromEvent(e)
+		theUI.processUserGestureRequests()
 		return nil
 	}))
 
@@ -515,12 +714,14 @@ func setCanvasEventHandlers(v js.Value) {
 		e := args[0]
 		e.Call("preventDefault")
 		theUI.input.updateFromEvent(e)
+		theUI.processUserGestureRequests()
 		return nil
 	}))
 	v.Call("addEventListener", "mouseup", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
 		e := args[0]
 		e.Call("preventDefault")
 		theUI.input.updateFromEvent(e)
+		theUI.processUserGestureRequests()
 		return nil
 	}))
 	v.Call("addEventListener", "mousemove", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
@@ -550,6 +751,7 @@ func setCanvasEventHandlers(v js.Value) {
 		e := args[0]
 		e.Call("preventDefault")
 		theUI.input.updateFromEvent(e)
+		theUI.processUserGestureRequests()
 		return nil
 	}))
 	v.Call("addEventListener", "touchmove", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
@@ -583,18 +785,34 @@ func (u *UserInterface) forceUpdateOnMinimumFPSMode() {
 }
 
 func (u *UserInterface) Run(game Game) error {
-	if u.initFocused && window.Truthy() {
-		// Do not focus the canvas when the current document is in an iframe.
-		// Otherwise, the parent page tries to focus the iframe on every loading, which is annoying (#1373).
-		isInIframe := !window.Get("location").Equal(window.Get("parent").Get("location"))
-		if !isInIframe {
-			canvas.Call("focus")
-		}
-	}
+	u.focusCanvasIfNeeded()
 	u.running = true
 	return <-u.loop(game)
 }
 
+func (u *UserInterface) StartWithoutMainLoop(game Game) error {
+	u.focusCanvasIfNeeded()
+	u.running = true
+	u.context = newContextImpl(game)
+	return nil
+}
+
+func (u *UserInterface) UpdateWithoutMainLoop() error {
+	return u.update()
+}
+
+func (u *UserInterface) focusCanvasIfNeeded() {
+	if !u.initFocused || !window.Truthy() {
+		return
+	}
+	// Do not focus the canvas when the current document is in an iframe.
+	// Otherwise, the parent page tries to focus the iframe on every loading, which is annoying (#1373).
+	isInIframe := !window.Get("location").Equal(window.Get("parent").Get("location"))
+	if !isInIframe {
+		canvas.Call("focus")
+	}
+}
+
 func (u *UserInterface) updateScreenSize() {
 	switch {
 	case document.Truthy():
@@ -603,6 +821,12 @@ func (u *UserInterface) updateScreenSize() {
 		bh := int(body.Get("clientHeight").Float() * u.DeviceScaleFactor())
 		canvas.Set("width", bw)
 		canvas.Set("height", bh)
+	case isWorker:
+		if !canvas.Truthy() {
+			return
+		}
+		canvas.Set("width", int(theWorkerState.width*u.DeviceScaleFactor()))
+		canvas.Set("height", int(theWorkerState.height*u.DeviceScaleFactor()))
 	case go2cpp.Truthy():
 		// TODO: Implement this
 	}
@@ -612,6 +836,9 @@ func (u *UserInterface) SetScreenTransparent(transparent bool) {
 	if u.running {
 		panic("ui: SetScreenTransparent can't be called after the main loop starts")
 	}
+	if !document.Truthy() {
+		return
+	}
 
 	bodyStyle := document.Get("body").Get("style")
 	if transparent {
@@ -622,10 +849,24 @@ func (u *UserInterface) SetScreenTransparent(transparent bool) {
 }
 
 func (u *UserInterface) IsScreenTransparent() bool {
+	if !document.Truthy() {
+		return false
+	}
 	bodyStyle := document.Get("body").Get("style")
 	return bodyStyle.Get("backgroundColor").Equal(stringTransparent)
 }
 
+func (u *UserInterface) SetLinearBlendingEnabled(enabled bool) {
+	if u.running {
+		panic("ui: SetLinearBlendingEnabled can't be called after the main loop starts")
+	}
+	// Do nothing. WebGL doesn't have a way to make the default framebuffer sRGB-encoded.
+}
+
+func (u *UserInterface) IsLinearBlendingEnabled() bool {
+	return false
+}
+
 func (u *UserInterface) resetForTick() {
 	u.input.resetForTick()
 }
@@ -20,9 +20,11 @@ package ui
 
 import (
 	"fmt"
+	"runtime"
 	"runtime/debug"
 	"sync"
 	"sync/atomic"
+	"time"
 	"unicode"
 
 	"golang.org/x/mobile/app"
@@ -83,6 +85,7 @@ func (u *UserInterface) Update() error {
 	}
 
 	gamepad.Update()
+	u.input.updateGamepadEvents()
 
 	renderCh <- struct{}{}
 	go func() {
@@ -97,6 +100,11 @@ type UserInterface struct {
 	outsideWidth  float64
 	outsideHeight float64
 
+	safeAreaInsetLeft   float64
+	safeAreaInsetTop    float64
+	safeAreaInsetRight  float64
+	safeAreaInsetBottom float64
+
 	foreground int32
 	errCh      chan error
 
@@ -113,6 +121,8 @@ type UserInterface struct {
 	fpsMode         FPSModeType
 	renderRequester RenderRequester
 
+	refreshRateEstimator refreshRateEstimator
+
 	t *thread.OSThread
 
 	m sync.RWMutex
@@ -228,6 +238,11 @@ func (u *UserInterface) SetForeground(foreground bool) error {
 	}
 	atomic.StoreInt32(&u.foreground, v)
 
+	// context can be nil when the game is not set yet.
+	if u.context != nil {
+		u.context.setSuspended(!foreground)
+	}
+
 	if foreground {
 		return hooks.ResumeAudio()
 	} else {
@@ -259,6 +274,19 @@ func (u *UserInterface) runWithoutMainLoop(game Game) {
 	}()
 }
 
+func (u *UserInterface) StartWithoutMainLoop(game Game) error {
+	u.runWithoutMainLoop(game)
+	return nil
+}
+
+func (u *UserInterface) UpdateWithoutMainLoop() error {
+	// Lock the OS thread since graphics functions (GL) must be called on this thread.
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	return u.Update()
+}
+
 func (u *UserInterface) run(game Game, mainloop bool) (err error) {
 	// Convert the panic to a regular error so that Java/Objective-C layer can treat this easily e.g., for
 	// Crashlytics. A panic is treated as SIGABRT, and there is no way to handle this on Java/Objective-C layer
@@ -319,6 +347,13 @@ func (u *UserInterface) update() error {
 		renderEndCh <- struct{}{}
 	}()
 
+	// Rendering is driven by the display (e.g. CADisplayLink on iOS) unless the explicit rendering mode is used.
+	if theGlobalState.fpsMode() == FPSModeVsyncOn {
+		u.refreshRateEstimator.update(float64(time.Now().UnixNano()) / float64(time.Second))
+	} else {
+		u.refreshRateEstimator.reset()
+	}
+
 	w, h := u.outsideSize()
 	if err := u.context.updateFrame(w, h, deviceScale()); err != nil {
 		return err
@@ -344,6 +379,24 @@ func (u *UserInterface) SetOutsideSize(outsideWidth, outsideHeight float64) {
 	u.m.Unlock()
 }
 
+// SetSafeAreaInsets is called from mobile/ebitenmobileview.
+//
+// SetSafeAreaInsets is concurrent safe.
+func (u *UserInterface) SetSafeAreaInsets(left, top, right, bottom float64) {
+	u.m.Lock()
+	defer u.m.Unlock()
+	u.safeAreaInsetLeft = left
+	u.safeAreaInsetTop = top
+	u.safeAreaInsetRight = right
+	u.safeAreaInsetBottom = bottom
+}
+
+func (u *UserInterface) SafeAreaInsets() (left, top, right, bottom float64) {
+	u.m.RLock()
+	defer u.m.RUnlock()
+	return u.safeAreaInsetLeft, u.safeAreaInsetTop, u.safeAreaInsetRight, u.safeAreaInsetBottom
+}
+
 func (u *UserInterface) setGBuildSize(widthPx, heightPx int) {
 	u.m.Lock()
 	u.gbuildWidthPx = widthPx
@@ -368,6 +421,10 @@ func (u *UserInterface) SetCursorMode(mode CursorMode) {
 	// Do nothing
 }
 
+func (u *UserInterface) NativeWindow() NativeWindow {
+	return NativeWindow{}
+}
+
 func (u *UserInterface) CursorShape() CursorShape {
 	return CursorShapeDefault
 }
@@ -384,6 +441,13 @@ func (u *UserInterface) SetFullscreen(fullscreen bool) {
 	// Do nothing
 }
 
+func (u *UserInterface) OcclusionState() OcclusionState {
+	if atomic.LoadInt32(&u.foreground) == 0 {
+		return OcclusionStateOccluded
+	}
+	return OcclusionStateVisible
+}
+
 func (u *UserInterface) IsFocused() bool {
 	return atomic.LoadInt32(&u.foreground) != 0
 }
@@ -420,6 +484,14 @@ func (u *UserInterface) IsScreenTransparent() bool {
 	return false
 }
 
+func (u *UserInterface) SetLinearBlendingEnabled(enabled bool) {
+	// Do nothing
+}
+
+func (u *UserInterface) IsLinearBlendingEnabled() bool {
+	return false
+}
+
 func (u *UserInterface) resetForTick() {
 	u.input.resetForTick()
 }
@@ -440,6 +512,14 @@ type Touch struct {
 	ID TouchID
 	X  int
 	Y  int
+
+	// Pressure is the normalized pressure in [0, 1], or 0 if the pressure is not available.
+	Pressure float64
+
+	// RadiusX and RadiusY are the radii of the contact ellipse in device-independent pixels,
+	// or 0 if they are not available.
+	RadiusX float64
+	RadiusY float64
 }
 
 func (u *UserInterface) UpdateInput(keys map[Key]struct{}, runes []rune, touches []Touch) {
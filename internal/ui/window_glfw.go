@@ -33,6 +33,10 @@ func (w *Window) IsDecorated() bool {
 	}
 	v := false
 	w.ui.t.Call(func() {
+		if w.ui.borderlessFullscreen {
+			v = w.ui.origDecorated
+			return
+		}
 		v = w.ui.window.GetAttrib(glfw.Decorated) == glfw.True
 	})
 	return v
@@ -48,6 +52,11 @@ func (w *Window) SetDecorated(decorated bool) {
 		if w.ui.isNativeFullscreen() {
 			return
 		}
+		// The decoration is restored when leaving borderless fullscreen.
+		if w.ui.borderlessFullscreen {
+			w.ui.origDecorated = decorated
+			return
+		}
 
 		w.ui.setWindowDecorated(decorated)
 	})
@@ -167,19 +176,7 @@ func (w *Window) Position() (int, int) {
 	}
 	x, y := 0, 0
 	w.ui.t.Call(func() {
-		var wx, wy int
-		if w.ui.isFullscreen() && !w.ui.isNativeFullscreenAvailable() {
-			wx, wy = w.ui.origPos()
-		} else {
-			wx, wy = w.ui.window.GetPos()
-		}
-		m := w.ui.currentMonitor()
-		mx, my := m.GetPos()
-		wx -= mx
-		wy -= my
-		xf := w.ui.dipFromGLFWPixel(float64(wx), m)
-		yf := w.ui.dipFromGLFWPixel(float64(wy), m)
-		x, y = int(xf), int(yf)
+		x, y = w.ui.windowPositionInDIP()
 	})
 	return x, y
 }
@@ -194,6 +191,58 @@ func (w *Window) SetPosition(x, y int) {
 	})
 }
 
+func (w *Window) Geometry() WindowGeometry {
+	if !w.ui.isRunning() {
+		panic("ui: WindowGeometry can't be called before the main loop starts")
+	}
+
+	var g WindowGeometry
+	w.ui.t.Call(func() {
+		g.Monitor = w.ui.currentMonitor().GetName()
+		g.X, g.Y = w.ui.windowPositionInDIP()
+		g.Width = w.ui.windowWidthInDIP
+		g.Height = w.ui.windowHeightInDIP
+		g.Maximized = w.ui.window.GetAttrib(glfw.Maximized) == glfw.True
+		g.Fullscreen = w.ui.isFullscreen()
+	})
+	return g
+}
+
+func (w *Window) SetGeometry(g WindowGeometry) {
+	if !w.ui.isRunning() {
+		// The monitor is ignored as the initial monitor is already determined.
+		w.ui.setInitWindowPositionInDIP(g.X, g.Y)
+		w.ui.setInitWindowSizeInDIP(g.Width, g.Height)
+		w.ui.setInitWindowMaximized(g.Maximized && w.ResizingMode() == WindowResizingModeEnabled)
+		w.ui.setInitFullscreen(g.Fullscreen)
+		return
+	}
+
+	w.ui.t.Call(func() {
+		if w.ui.isFullscreen() {
+			w.ui.setWindowSizeInDIP(w.ui.windowWidthInDIP, w.ui.windowHeightInDIP, false)
+		}
+		if w.ui.window.GetAttrib(glfw.Maximized) == glfw.True || w.ui.window.GetAttrib(glfw.Iconified) == glfw.True {
+			w.ui.restoreWindow()
+		}
+
+		m := monitorByName(g.Monitor)
+		if m == nil {
+			m = w.ui.currentMonitor()
+		}
+		// Set the position before the size so that the size is converted with the new monitor's scale.
+		w.ui.setWindowPositionInDIP(g.X, g.Y, m)
+		w.ui.setWindowSizeInDIP(g.Width, g.Height, false)
+
+		if g.Maximized && w.ui.windowResizingMode == WindowResizingModeEnabled {
+			w.ui.maximizeWindow()
+		}
+		if g.Fullscreen {
+			w.ui.setWindowSizeInDIP(g.Width, g.Height, true)
+		}
+	})
+}
+
 func (w *Window) Size() (int, int) {
 	if !w.ui.isRunning() {
 		ww, wh := w.ui.getInitWindowSizeInDIP()
@@ -238,6 +287,21 @@ func (w *Window) SetSizeLimits(minw, minh, maxw, maxh int) {
 	w.ui.t.Call(w.ui.updateWindowSizeLimits)
 }
 
+func (w *Window) AspectRatio() (numer, denom int) {
+	return w.ui.getWindowAspectRatio()
+}
+
+func (w *Window) SetAspectRatio(numer, denom int) {
+	if !w.ui.setWindowAspectRatio(numer, denom) {
+		return
+	}
+	if !w.ui.isRunning() {
+		return
+	}
+
+	w.ui.t.Call(w.ui.updateWindowSizeLimits)
+}
+
 func (w *Window) SetIcon(iconImages []image.Image) {
 	// The icons are actually set at (*UserInterface).loop.
 	w.ui.setIconImages(iconImages)
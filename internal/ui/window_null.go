@@ -51,6 +51,13 @@ func (*Window) Size() (int, int) {
 func (*Window) SetSize(width, height int) {
 }
 
+func (*Window) Geometry() WindowGeometry {
+	return WindowGeometry{}
+}
+
+func (*Window) SetGeometry(g WindowGeometry) {
+}
+
 func (*Window) SizeLimits() (minw, minh, maxw, maxh int) {
 	return -1, -1, -1, -1
 }
@@ -58,6 +65,13 @@ func (*Window) SizeLimits() (minw, minh, maxw, maxh int) {
 func (*Window) SetSizeLimits(minw, minh, maxw, maxh int) {
 }
 
+func (*Window) AspectRatio() (numer, denom int) {
+	return 0, 0
+}
+
+func (*Window) SetAspectRatio(numer, denom int) {
+}
+
 func (*Window) IsFloating() bool {
 	return false
 }
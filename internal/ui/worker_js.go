@@ -0,0 +1,110 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"syscall/js"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/jsutil"
+)
+
+// isWorker reports whether Ebiten runs in a Web Worker.
+//
+// In a Web Worker, the game renders to an OffscreenCanvas transferred from the main thread, and the main thread
+// proxies input events and the page state by postMessage. See the package document of ebiten for the messages.
+var isWorker = !document.Truthy() && js.Global().Get("WorkerGlobalScope").Truthy()
+
+var (
+	stringCanvas = js.ValueOf("canvas")
+	stringResize = js.ValueOf("resize")
+	stringFocus  = js.ValueOf("focus")
+	stringEvent  = js.ValueOf("event")
+)
+
+type workerState struct {
+	width             float64
+	height            float64
+	deviceScaleFactor float64
+	focused           bool
+	hidden            bool
+}
+
+var theWorkerState = workerState{
+	width:             640,
+	height:            480,
+	deviceScaleFactor: 1,
+	focused:           true,
+}
+
+func (w *workerState) setSize(data js.Value) {
+	if v := data.Get("width"); v.Type() == js.TypeNumber {
+		w.width = v.Float()
+	}
+	if v := data.Get("height"); v.Type() == js.TypeNumber {
+		w.height = v.Float()
+	}
+	if v := data.Get("devicePixelRatio"); v.Type() == js.TypeNumber && v.Float() > 0 {
+		w.deviceScaleFactor = v.Float()
+	}
+}
+
+func init() {
+	if !isWorker {
+		return
+	}
+
+	// requestAnimationFrame might not be available in a Web Worker.
+	if !requestAnimationFrame.Truthy() {
+		requestAnimationFrame = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			setTimeout.Invoke(args[0], 1000/60)
+			return nil
+		}).Value
+	}
+
+	// Wait for the OffscreenCanvas as the graphics driver requires it.
+	ch := make(chan struct{})
+	js.Global().Call("addEventListener", "message", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		data := args[0].Get("data")
+		if data.Type() != js.TypeObject {
+			return nil
+		}
+
+		// Avoid using js.Value.String() for the same reason as Input.updateFromEvent (#1437).
+		switch t := data.Get("ebiten"); {
+		case t.Equal(stringCanvas):
+			if canvas.Truthy() {
+				return nil
+			}
+			canvas = data.Get("canvas")
+			jsutil.SetOffscreenCanvas(canvas)
+			theWorkerState.setSize(data)
+			theUI.updateScreenSize()
+			close(ch)
+		case t.Equal(stringResize):
+			theWorkerState.setSize(data)
+			theUI.updateScreenSize()
+			if err := theUI.updateImpl(true); err != nil {
+				panic(err)
+			}
+		case t.Equal(stringFocus):
+			theWorkerState.focused = data.Get("focused").Bool()
+			theWorkerState.hidden = data.Get("hidden").Bool()
+		case t.Equal(stringEvent):
+			theUI.input.updateFromEvent(data.Get("event"))
+		}
+		return nil
+	}))
+	<-ch
+}
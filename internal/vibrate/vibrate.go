@@ -0,0 +1,34 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vibrate
+
+import (
+	"time"
+)
+
+// offOnDurations returns the durations of alternating pauses and vibrations, starting with a pause.
+// A step with zero or negative magnitude is treated as a pause. Adjacent steps in the same state are merged.
+func offOnDurations(durations []time.Duration, magnitudes []float64) []time.Duration {
+	ds := []time.Duration{0}
+	on := false
+	for i, d := range durations {
+		if (magnitudes[i] > 0) != on {
+			on = !on
+			ds = append(ds, 0)
+		}
+		ds[len(ds)-1] += d
+	}
+	return ds
+}
@@ -30,24 +30,7 @@ import (
 
 #include <android/log.h>
 
-// Basically same as:
-//
-//     Vibrator v = (Vibrator)getSystemService(Context.VIBRATOR_SERVICE);
-//     if (Build.VERSION.SDK_INT >= 26) {
-//       v.vibrate(VibrationEffect.createOneShot(milliseconds, magnitude * 255))
-//     } else {
-//       v.vibrate(millisecond)
-//     }
-//
-// Note that this requires a manifest setting:
-//
-//     <uses-permission android:name="android.permission.VIBRATE"/>
-//
-static void vibrateOneShot(uintptr_t java_vm, uintptr_t jni_env, uintptr_t ctx, int64_t milliseconds, double magnitude) {
-  JavaVM* vm = (JavaVM*)java_vm;
-  JNIEnv* env = (JNIEnv*)jni_env;
-  jobject context = (jobject)ctx;
-
+static int getAPILevel(JNIEnv* env) {
   static int apiLevel = 0;
   if (!apiLevel) {
     const jclass android_os_Build_VERSION = (*env)->FindClass(env, "android/os/Build$VERSION");
@@ -58,9 +41,11 @@ static void vibrateOneShot(uintptr_t java_vm, uintptr_t jni_env, uintptr_t ctx,
 
     (*env)->DeleteLocalRef(env, android_os_Build_VERSION);
   }
+  return apiLevel;
+}
 
+static jobject getVibrator(JNIEnv* env, jobject context) {
   const jclass android_content_Context = (*env)->FindClass(env, "android/content/Context");
-  const jclass android_os_Vibrator = (*env)->FindClass(env, "android/os/Vibrator");
 
   const jobject android_context_Context_VIBRATOR_SERVICE =
       (*env)->GetStaticObjectField(
@@ -73,6 +58,34 @@ static void vibrateOneShot(uintptr_t java_vm, uintptr_t jni_env, uintptr_t ctx,
           (*env)->GetMethodID(env, android_content_Context, "getSystemService", "(Ljava/lang/String;)Ljava/lang/Object;"),
           android_context_Context_VIBRATOR_SERVICE);
 
+  (*env)->DeleteLocalRef(env, android_content_Context);
+  (*env)->DeleteLocalRef(env, android_context_Context_VIBRATOR_SERVICE);
+
+  return vibrator;
+}
+
+// Basically same as:
+//
+//     Vibrator v = (Vibrator)getSystemService(Context.VIBRATOR_SERVICE);
+//     if (Build.VERSION.SDK_INT >= 26) {
+//       v.vibrate(VibrationEffect.createOneShot(milliseconds, magnitude * 255))
+//     } else {
+//       v.vibrate(millisecond)
+//     }
+//
+// Note that this requires a manifest setting:
+//
+//     <uses-permission android:name="android.permission.VIBRATE"/>
+//
+static void vibrateOneShot(uintptr_t java_vm, uintptr_t jni_env, uintptr_t ctx, int64_t milliseconds, double magnitude) {
+  JavaVM* vm = (JavaVM*)java_vm;
+  JNIEnv* env = (JNIEnv*)jni_env;
+  jobject context = (jobject)ctx;
+
+  const int apiLevel = getAPILevel(env);
+  const jclass android_os_Vibrator = (*env)->FindClass(env, "android/os/Vibrator");
+  const jobject vibrator = getVibrator(env, context);
+
   if (apiLevel >= 26) {
     const jclass android_os_VibrationEffect = (*env)->FindClass(env, "android/os/VibrationEffect");
 
@@ -97,10 +110,65 @@ static void vibrateOneShot(uintptr_t java_vm, uintptr_t jni_env, uintptr_t ctx,
         milliseconds);
   }
 
-  (*env)->DeleteLocalRef(env, android_content_Context);
   (*env)->DeleteLocalRef(env, android_os_Vibrator);
+  (*env)->DeleteLocalRef(env, vibrator);
+}
 
-  (*env)->DeleteLocalRef(env, android_context_Context_VIBRATOR_SERVICE);
+// Basically same as:
+//
+//     Vibrator v = (Vibrator)getSystemService(Context.VIBRATOR_SERVICE);
+//     if (Build.VERSION.SDK_INT >= 26) {
+//       v.vibrate(VibrationEffect.createWaveform(timings, amplitudes, -1))
+//     } else {
+//       v.vibrate(offOnTimings, -1)
+//     }
+//
+static void vibrateWaveform(uintptr_t java_vm, uintptr_t jni_env, uintptr_t ctx, int64_t* timings, int* amplitudes, int n, int64_t* offOnTimings, int offOnN) {
+  JavaVM* vm = (JavaVM*)java_vm;
+  JNIEnv* env = (JNIEnv*)jni_env;
+  jobject context = (jobject)ctx;
+
+  const int apiLevel = getAPILevel(env);
+  const jclass android_os_Vibrator = (*env)->FindClass(env, "android/os/Vibrator");
+  const jobject vibrator = getVibrator(env, context);
+
+  if (apiLevel >= 26) {
+    const jclass android_os_VibrationEffect = (*env)->FindClass(env, "android/os/VibrationEffect");
+
+    const jlongArray jtimings = (*env)->NewLongArray(env, n);
+    (*env)->SetLongArrayRegion(env, jtimings, 0, n, (const jlong*)timings);
+    const jintArray jamplitudes = (*env)->NewIntArray(env, n);
+    (*env)->SetIntArrayRegion(env, jamplitudes, 0, n, (const jint*)amplitudes);
+
+    const jobject vibrationEffect =
+        (*env)->CallStaticObjectMethod(
+            env, android_os_VibrationEffect,
+            (*env)->GetStaticMethodID(env, android_os_VibrationEffect, "createWaveform", "([J[II)Landroid/os/VibrationEffect;"),
+            jtimings, jamplitudes, -1);
+
+    (*env)->CallVoidMethod(
+        env, vibrator,
+        (*env)->GetMethodID(env, android_os_Vibrator, "vibrate", "(Landroid/os/VibrationEffect;)V"),
+        vibrationEffect);
+
+    (*env)->DeleteLocalRef(env, android_os_VibrationEffect);
+
+    (*env)->DeleteLocalRef(env, jtimings);
+    (*env)->DeleteLocalRef(env, jamplitudes);
+    (*env)->DeleteLocalRef(env, vibrationEffect);
+  } else {
+    const jlongArray jtimings = (*env)->NewLongArray(env, offOnN);
+    (*env)->SetLongArrayRegion(env, jtimings, 0, offOnN, (const jlong*)offOnTimings);
+
+    (*env)->CallVoidMethod(
+        env, vibrator,
+        (*env)->GetMethodID(env, android_os_Vibrator, "vibrate", "([JI)V"),
+        jtimings, -1);
+
+    (*env)->DeleteLocalRef(env, jtimings);
+  }
+
+  (*env)->DeleteLocalRef(env, android_os_Vibrator);
   (*env)->DeleteLocalRef(env, vibrator);
 }
 
@@ -116,3 +184,44 @@ func Vibrate(duration time.Duration, magnitude float64) {
 		})
 	}()
 }
+
+func VibratePattern(durations []time.Duration, magnitudes []float64) {
+	if len(durations) == 0 {
+		return
+	}
+
+	var total C.int64_t
+	timings := make([]C.int64_t, len(durations))
+	amplitudes := make([]C.int, len(durations))
+	for i, d := range durations {
+		timings[i] = C.int64_t(d / time.Millisecond)
+		total += timings[i]
+		if m := magnitudes[i]; m > 0 {
+			a := int(m * 255)
+			if a < 1 {
+				a = 1
+			}
+			if a > 255 {
+				a = 255
+			}
+			amplitudes[i] = C.int(a)
+		}
+	}
+	// VibrationEffect.createWaveform throws an exception when the total duration is zero.
+	if total == 0 {
+		return
+	}
+
+	ds := offOnDurations(durations, magnitudes)
+	offOnTimings := make([]C.int64_t, len(ds))
+	for i, d := range ds {
+		offOnTimings[i] = C.int64_t(d / time.Millisecond)
+	}
+
+	go func() {
+		_ = app.RunOnJVM(func(vm, env, ctx uintptr) error {
+			C.vibrateWaveform(C.uintptr_t(vm), C.uintptr_t(env), C.uintptr_t(ctx), &timings[0], &amplitudes[0], C.int(len(timings)), &offOnTimings[0], C.int(len(offOnTimings)))
+			return nil
+		})
+	}()
+}
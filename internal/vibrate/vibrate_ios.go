@@ -23,6 +23,7 @@ package vibrate
 // #import <AVFoundation/AVFoundation.h>
 // #import <CoreHaptics/CoreHaptics.h>
 // #include <dispatch/dispatch.h>
+// #include <stdlib.h>
 //
 // static id initializeHapticEngine(void) {
 //   if (@available(iOS 13.0, *)) {
@@ -51,14 +52,19 @@ package vibrate
 //   return nil;
 // }
 //
+// static id hapticEngine(void) {
+//   static BOOL initializeHapticEngineCalled = NO;
+//   static id engine = nil;
+//   if (!initializeHapticEngineCalled) {
+//     engine = initializeHapticEngine();
+//     initializeHapticEngineCalled = YES;
+//   }
+//   return engine;
+// }
+//
 // static void vibrateOnMainThread(double duration, double intensity) {
 //   if (@available(iOS 13.0, *)) {
-//     static BOOL initializeHapticEngineCalled = NO;
-//     static CHHapticEngine* engine = nil;
-//     if (!initializeHapticEngineCalled) {
-//       engine = (CHHapticEngine*)initializeHapticEngine();
-//       initializeHapticEngineCalled = YES;
-//     }
+//     CHHapticEngine* engine = (CHHapticEngine*)hapticEngine();
 //     if (!engine) {
 //       return;
 //     }
@@ -108,10 +114,77 @@ package vibrate
 //     vibrateOnMainThread(duration, intensity);
 //   });
 // }
+//
+// static void vibratePatternOnMainThread(double* durations, double* intensities, int n) {
+//   if (@available(iOS 13.0, *)) {
+//     CHHapticEngine* engine = (CHHapticEngine*)hapticEngine();
+//     if (!engine) {
+//       return;
+//     }
+//     @autoreleasepool {
+//       NSMutableArray* events = [NSMutableArray arrayWithCapacity:n];
+//       double time = 0;
+//       for (int i = 0; i < n; i++) {
+//         if (intensities[i] > 0 && durations[i] > 0) {
+//           [events addObject:@{
+//             (id<NSCopying>)(CHHapticPatternKeyEvent): @{
+//               (id<NSCopying>)(CHHapticPatternKeyEventType):CHHapticEventTypeHapticContinuous,
+//               (id<NSCopying>)(CHHapticPatternKeyTime):[NSNumber numberWithDouble:time],
+//               (id<NSCopying>)(CHHapticPatternKeyEventDuration):[NSNumber numberWithDouble:durations[i]],
+//               (id<NSCopying>)(CHHapticPatternKeyEventParameters):@[
+//                 @{
+//                   (id<NSCopying>)(CHHapticPatternKeyParameterID): CHHapticEventParameterIDHapticIntensity,
+//                   (id<NSCopying>)(CHHapticPatternKeyParameterValue): [NSNumber numberWithDouble:intensities[i]],
+//                 },
+//               ],
+//             },
+//           }];
+//         }
+//         time += durations[i];
+//       }
+//       if ([events count] == 0) {
+//         return;
+//       }
+//
+//       NSDictionary* hapticDict = @{
+//         (id<NSCopying>)(CHHapticPatternKeyPattern): events,
+//       };
+//
+//       NSError* error = nil;
+//       CHHapticPattern* pattern = [[CHHapticPattern alloc] initWithDictionary:hapticDict
+//                                                                        error:&error];
+//       if (error) {
+//         return;
+//       }
+//
+//       id<CHHapticPatternPlayer> player = [engine createPlayerWithPattern:pattern
+//                                                                    error:&error];
+//       if (error) {
+//         return;
+//       }
+//
+//       [player startAtTime:0 error:&error];
+//       if (error) {
+//         NSLog(@"CHHapticPatternPlayer::startAtTime failed: %@", [error localizedDescription]);
+//         return;
+//       }
+//     }
+//   }
+// }
+//
+// // vibratePattern takes the ownership of durations and intensities, which must be allocated by malloc.
+// static void vibratePattern(double* durations, double* intensities, int n) {
+//   dispatch_async(dispatch_get_main_queue(), ^{
+//     vibratePatternOnMainThread(durations, intensities, n);
+//     free(durations);
+//     free(intensities);
+//   });
+// }
 import "C"
 
 import (
 	"time"
+	"unsafe"
 )
 
 func Vibrate(duration time.Duration, magnitude float64) {
@@ -119,3 +192,18 @@ func Vibrate(duration time.Duration, magnitude float64) {
 		C.vibrate(C.double(float64(duration)/float64(time.Second)), C.double(magnitude))
 	}()
 }
+
+func VibratePattern(durations []time.Duration, magnitudes []float64) {
+	if len(durations) == 0 {
+		return
+	}
+
+	n := len(durations)
+	ds := (*[1 << 30]C.double)(C.malloc(C.size_t(n) * C.size_t(unsafe.Sizeof(C.double(0)))))[:n:n]
+	is := (*[1 << 30]C.double)(C.malloc(C.size_t(n) * C.size_t(unsafe.Sizeof(C.double(0)))))[:n:n]
+	for i := range durations {
+		ds[i] = C.double(float64(durations[i]) / float64(time.Second))
+		is[i] = C.double(magnitudes[i])
+	}
+	C.vibratePattern(&ds[0], &is[0], C.int(n))
+}
@@ -26,3 +26,20 @@ func Vibrate(duration time.Duration, magnitude float64) {
 		js.Global().Get("navigator").Call("vibrate", float64(duration/time.Millisecond))
 	}
 }
+
+func VibratePattern(durations []time.Duration, magnitudes []float64) {
+	// magnitudes are ignored except for distinguishing pauses.
+
+	if !js.Global().Get("navigator").Get("vibrate").Truthy() {
+		return
+	}
+
+	// navigator.vibrate takes alternating vibrations and pauses, starting with a vibration.
+	ds := offOnDurations(durations, magnitudes)
+	pattern := make([]interface{}, 0, len(ds)+1)
+	pattern = append(pattern, 0)
+	for _, d := range ds {
+		pattern = append(pattern, float64(d/time.Millisecond))
+	}
+	js.Global().Get("navigator").Call("vibrate", pattern)
+}
@@ -0,0 +1,230 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+)
+
+// largeImageTileSize is the size of a tile of LargeImage.
+//
+// The maximum texture size depends on the graphics driver and is not available until the driver is initialized.
+// 2048 is small enough for all the supported environments.
+const largeImageTileSize = 2048
+
+// LargeImage represents a rectangle set of pixels that can be larger than the maximum size of an Image.
+//
+// LargeImage consists of multiple images as tiles internally, and drawing functions split draw calls
+// for each tile automatically.
+type LargeImage struct {
+	width  int
+	height int
+	cols   int
+	tiles  []*Image
+}
+
+// NewLargeImage returns an empty large image.
+//
+// If width or height is less than 1, NewLargeImage panics.
+//
+// NewLargeImage panics if RunGame already finishes.
+func NewLargeImage(width, height int) *LargeImage {
+	if width <= 0 {
+		panic(fmt.Sprintf("ebiten: width at NewLargeImage must be positive but %d", width))
+	}
+	if height <= 0 {
+		panic(fmt.Sprintf("ebiten: height at NewLargeImage must be positive but %d", height))
+	}
+
+	cols := (width-1)/largeImageTileSize + 1
+	rows := (height-1)/largeImageTileSize + 1
+	l := &LargeImage{
+		width:  width,
+		height: height,
+		cols:   cols,
+		tiles:  make([]*Image, 0, cols*rows),
+	}
+	for j := 0; j < rows; j++ {
+		for i := 0; i < cols; i++ {
+			w := largeImageTileSize
+			if i == cols-1 {
+				w = width - i*largeImageTileSize
+			}
+			h := largeImageTileSize
+			if j == rows-1 {
+				h = height - j*largeImageTileSize
+			}
+			l.tiles = append(l.tiles, NewImage(w, h))
+		}
+	}
+	return l
+}
+
+// Size returns the size of the large image.
+func (l *LargeImage) Size() (width, height int) {
+	return l.width, l.height
+}
+
+// Bounds returns the bounds of the large image.
+func (l *LargeImage) Bounds() image.Rectangle {
+	return image.Rect(0, 0, l.width, l.height)
+}
+
+// tileBounds returns the bounds of the idx-th tile in the large image's coordinates.
+func (l *LargeImage) tileBounds(idx int) image.Rectangle {
+	x := (idx % l.cols) * largeImageTileSize
+	y := (idx / l.cols) * largeImageTileSize
+	w, h := l.tiles[idx].Size()
+	return image.Rect(x, y, x+w, y+h)
+}
+
+// Clear resets the pixels of the large image into 0.
+//
+// When the large image is disposed, Clear does nothing.
+func (l *LargeImage) Clear() {
+	for _, t := range l.tiles {
+		t.Clear()
+	}
+}
+
+// Fill fills the large image with a solid color.
+//
+// When the large image is disposed, Fill does nothing.
+func (l *LargeImage) Fill(clr color.Color) {
+	for _, t := range l.tiles {
+		t.Fill(clr)
+	}
+}
+
+// At returns the color of the large image at (x, y).
+//
+// At loads pixels from GPU to system memory if necessary, which means that At can be slow.
+//
+// At always returns a transparent color if the large image is disposed.
+//
+// At can't be called outside the main loop (ebiten.Run's updating function) starts.
+func (l *LargeImage) At(x, y int) color.Color {
+	if !image.Pt(x, y).In(l.Bounds()) {
+		return color.RGBA{}
+	}
+	idx := (y/largeImageTileSize)*l.cols + x/largeImageTileSize
+	b := l.tileBounds(idx)
+	return l.tiles[idx].At(x-b.Min.X, y-b.Min.Y)
+}
+
+// ReplacePixels replaces the pixels of the large image with p.
+//
+// The given p must represent RGBA pre-multiplied alpha values.
+// len(pix) must equal to 4 * (width of the large image) * (height of the large image).
+//
+// ReplacePixels works on a similar way to the Image's ReplacePixels.
+//
+// When the large image is disposed, ReplacePixels does nothing.
+func (l *LargeImage) ReplacePixels(pixels []byte) {
+	if len(pixels) != 4*l.width*l.height {
+		panic(fmt.Sprintf("ebiten: len(pixels) must be %d but %d at ReplacePixels", 4*l.width*l.height, len(pixels)))
+	}
+	for idx, t := range l.tiles {
+		b := l.tileBounds(idx)
+		bs := make([]byte, 4*b.Dx()*b.Dy())
+		for j := 0; j < b.Dy(); j++ {
+			srcIdx := 4 * ((b.Min.Y+j)*l.width + b.Min.X)
+			copy(bs[4*b.Dx()*j:4*b.Dx()*(j+1)], pixels[srcIdx:srcIdx+4*b.Dx()])
+		}
+		t.ReplacePixels(bs)
+	}
+}
+
+// DrawImage draws the given image on the large image.
+//
+// DrawImage works on a similar way to the Image's DrawImage.
+// The draw call is split for each tile that the given image might be rendered on.
+//
+// When the large image is disposed, DrawImage does nothing.
+// When the given image img is disposed, DrawImage panics.
+func (l *LargeImage) DrawImage(img *Image, options *DrawImageOptions) {
+	if options == nil {
+		options = &DrawImageOptions{}
+	}
+	// A sub-image is rendered at the origin regardless of its bounds' position.
+	sb := img.Bounds()
+	r := transformedBounds(image.Rect(0, 0, sb.Dx(), sb.Dy()), options.GeoM)
+	for idx, t := range l.tiles {
+		b := l.tileBounds(idx)
+		if !r.Overlaps(b) {
+			continue
+		}
+		op := *options
+		op.GeoM.Translate(float64(-b.Min.X), float64(-b.Min.Y))
+		t.DrawImage(img, &op)
+	}
+}
+
+// DrawTo draws the large image on the given image dst.
+//
+// The options are applied as if the whole large image were drawn by dst.DrawImage(l, options).
+// Tiles that are not rendered on dst are skipped.
+//
+// With FilterLinear, seams might be visible at the borders of the internal tiles.
+//
+// When the large image is disposed, DrawTo does nothing.
+func (l *LargeImage) DrawTo(dst *Image, options *DrawImageOptions) {
+	if options == nil {
+		options = &DrawImageOptions{}
+	}
+	for idx, t := range l.tiles {
+		b := l.tileBounds(idx)
+		op := *options
+		op.GeoM.Reset()
+		op.GeoM.Translate(float64(b.Min.X), float64(b.Min.Y))
+		op.GeoM.Concat(options.GeoM)
+		if !transformedBounds(t.Bounds(), op.GeoM).Overlaps(dst.Bounds()) {
+			continue
+		}
+		dst.DrawImage(t, &op)
+	}
+}
+
+// Dispose disposes the large image data.
+// After disposing, most of large image functions do nothing and returns meaningless values.
+//
+// Calling Dispose is not mandatory. GC automatically collects internal resources that no objects refer to.
+// However, calling Dispose explicitly is helpful if memory usage matters.
+//
+// When the large image is disposed, Dipose does nothing.
+func (l *LargeImage) Dispose() {
+	for _, t := range l.tiles {
+		t.Dispose()
+	}
+}
+
+// transformedBounds returns the bounding box of the rectangle r transformed by the geometry matrix g.
+//
+// The result has a margin of a pixel to cover pixels touched by filtering.
+func transformedBounds(r image.Rectangle, g GeoM) image.Rectangle {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, p := range []image.Point{r.Min, {r.Max.X, r.Min.Y}, {r.Min.X, r.Max.Y}, r.Max} {
+		x, y := g.Apply(float64(p.X), float64(p.Y))
+		minX = math.Min(minX, x)
+		minY = math.Min(minY, y)
+		maxX = math.Max(maxX, x)
+		maxY = math.Max(maxY, y)
+	}
+	return image.Rect(int(math.Floor(minX))-1, int(math.Floor(minY))-1, int(math.Ceil(maxX))+1, int(math.Ceil(maxY))+1)
+}
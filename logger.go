@@ -0,0 +1,49 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"github.com/hajimehoshi/ebiten/v2/internal/logger"
+)
+
+// LogLevel represents the severity of a log message from Ebiten.
+//
+// The values are the same as the levels of log/slog, so a LogLevel can be converted to slog.Level as it is.
+type LogLevel = logger.Level
+
+// LogLevels
+const (
+	LogLevelDebug LogLevel = logger.LevelDebug
+	LogLevelInfo  LogLevel = logger.LevelInfo
+	LogLevelWarn  LogLevel = logger.LevelWarn
+	LogLevelError LogLevel = logger.LevelError
+)
+
+// Logger receives Ebiten's internal log messages, e.g., when a fallback is taken or the graphics context is restored.
+//
+// keysAndValues is a list of alternating string keys and values, in the same manner as log/slog.
+// A *slog.Logger can be adapted by a method calling its Log method with slog.Level(level).
+//
+// Log might be called from any goroutine.
+type Logger = logger.Logger
+
+// SetLogger sets the logger to receive Ebiten's internal log messages.
+//
+// If l is nil, the messages are discarded. This is the default.
+//
+// SetLogger is concurrent-safe.
+func SetLogger(l Logger) {
+	logger.SetLogger(l)
+}
@@ -22,8 +22,11 @@ import (
 )
 
 type position struct {
-	x int
-	y int
+	x        int
+	y        int
+	pressure float64
+	radiusX  float64
+	radiusY  float64
 }
 
 var (
@@ -40,9 +43,12 @@ func updateInput() {
 	touchSlice = touchSlice[:0]
 	for id, position := range touches {
 		touchSlice = append(touchSlice, ui.Touch{
-			ID: id,
-			X:  position.x,
-			Y:  position.y,
+			ID:       id,
+			X:        position.x,
+			Y:        position.y,
+			Pressure: position.pressure,
+			RadiusX:  position.radiusX,
+			RadiusY:  position.radiusY,
 		})
 	}
 
@@ -180,10 +180,17 @@ var androidAxisIDToHatID2 = map[int]int{
 	axisHatY: 1,
 }
 
-func UpdateTouchesOnAndroid(action int, id int, x, y int) {
+func UpdateTouchesOnAndroid(action int, id int, x, y int, pressure float64, radiusX, radiusY float64) {
 	switch action {
 	case 0x00, 0x05, 0x02: // ACTION_DOWN, ACTION_POINTER_DOWN, ACTION_MOVE
-		touches[ui.TouchID(id)] = position{x, y}
+		// The pressure on Android can exceed 1 depending on the calibration of the device.
+		touches[ui.TouchID(id)] = position{
+			x:        x,
+			y:        y,
+			pressure: math.Min(math.Max(pressure, 0), 1),
+			radiusX:  radiusX,
+			radiusY:  radiusY,
+		}
 		updateInput()
 	case 0x01, 0x06: // ACTION_UP, ACTION_POINTER_UP
 		delete(touches, ui.TouchID(id))
@@ -228,6 +235,10 @@ func OnKeyUpOnAndroid(keyCode int, source int, deviceID int) {
 	}
 }
 
+func OnBackPressedOnAndroid() bool {
+	return theState.handleBack()
+}
+
 func OnGamepadAxesOrHatsChanged(deviceID int, axisID int, value float32) {
 	if axis, ok := androidAxisIDToAxisID[axisID]; ok {
 		gamepad.UpdateAndroidGamepadAxis(deviceID, axis, float64(value))
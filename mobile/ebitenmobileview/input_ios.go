@@ -46,11 +46,18 @@ func getIDFromPtr(ptr int64) int {
 	return id
 }
 
-func UpdateTouchesOnIOS(phase int, ptr int64, x, y int) {
+func UpdateTouchesOnIOS(phase int, ptr int64, x, y int, pressure float64, radius float64) {
 	switch phase {
 	case C.UITouchPhaseBegan, C.UITouchPhaseMoved, C.UITouchPhaseStationary:
 		id := getIDFromPtr(ptr)
-		touches[ui.TouchID(id)] = position{x, y}
+		// UITouch gives only the major radius.
+		touches[ui.TouchID(id)] = position{
+			x:        x,
+			y:        y,
+			pressure: pressure,
+			radiusX:  radius,
+			radiusY:  radius,
+		}
 		updateInput()
 	case C.UITouchPhaseEnded, C.UITouchPhaseCancelled:
 		id := getIDFromPtr(ptr)
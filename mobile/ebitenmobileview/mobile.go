@@ -28,6 +28,7 @@ import "C"
 
 import (
 	"runtime"
+	"sync"
 	"sync/atomic"
 
 	"github.com/hajimehoshi/ebiten/v2"
@@ -40,6 +41,9 @@ var theState state
 
 type state struct {
 	running int32
+
+	backHandler func() bool
+	m           sync.Mutex
 }
 
 func (s *state) isRunning() bool {
@@ -50,6 +54,24 @@ func (s *state) run() {
 	atomic.StoreInt32(&s.running, 1)
 }
 
+func (s *state) setBackHandler(handler func() bool) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.backHandler = handler
+}
+
+func (s *state) handleBack() bool {
+	s.m.Lock()
+	h := s.backHandler
+	s.m.Unlock()
+
+	if h == nil {
+		// Consume the event as before when no handler is set.
+		return true
+	}
+	return h()
+}
+
 func SetGame(game ebiten.Game) {
 	if theState.isRunning() {
 		panic("ebitenmobileview: SetGame cannot be called twice or more")
@@ -62,6 +84,10 @@ func Layout(viewWidth, viewHeight float64) {
 	ui.Get().SetOutsideSize(viewWidth, viewHeight)
 }
 
+func SetSafeAreaInsets(left, top, right, bottom float64) {
+	ui.Get().SetSafeAreaInsets(left, top, right, bottom)
+}
+
 func Update() error {
 	// Lock the OS thread since graphics functions (GL) must be called on this thread.
 	runtime.LockOSThread()
@@ -97,6 +123,10 @@ type RenderRequester interface {
 	RequestRenderIfNeeded()
 }
 
+func SetBackHandler(handler func() bool) {
+	theState.setBackHandler(handler)
+}
+
 func SetRenderRequester(renderRequester RenderRequester) {
 	ui.Get().SetRenderRequester(renderRequester)
 }
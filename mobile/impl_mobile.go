@@ -25,3 +25,7 @@ import (
 func setGame(game ebiten.Game) {
 	ebitenmobileview.SetGame(game)
 }
+
+func setBackHandler(handler func() bool) {
+	ebitenmobileview.SetBackHandler(handler)
+}
@@ -24,3 +24,7 @@ import (
 func setGame(game ebiten.Game) {
 	panic("mobile: setGame is not implemented in this environment")
 }
+
+func setBackHandler(handler func() bool) {
+	panic("mobile: setBackHandler is not implemented in this environment")
+}
@@ -17,6 +17,13 @@
 // This package is used when you use `ebitenmobile bind`.
 //
 // For usage, see https://ebiten.org/documents/mobile.html.
+//
+// On iOS, Metal is used as the graphics library except for simulators, where OpenGL ES is used.
+// OpenGL ES can be forced by the build tag `ebitengl`, e.g. `ebitenmobile bind -tags=ebitengl`.
+//
+// On iOS, the screen is refreshed at the maximum refresh rate of the display, e.g. 120 Hz on ProMotion displays.
+// On iPhone, `CADisableMinimumFrameDurationOnPhone` must be true in the app's Info.plist to exceed 60 Hz.
+// The game's Update is still called based on TPS. See also ebiten.SetMaxTPS.
 package mobile
 
 import (
@@ -31,3 +38,19 @@ import (
 func SetGame(game ebiten.Game) {
 	setGame(game)
 }
+
+// SetBackHandler sets a function that is called when the back button is pressed or the back gesture is performed
+// on Android.
+//
+// If handler returns true, the event is consumed by the game. Otherwise, the event is passed through to the system,
+// and e.g. the activity finishes. If handler is nil, the event is consumed and nothing happens, which is the default.
+//
+// handler is called on a thread different from the game's Update. Be careful that handler must be concurrent-safe.
+//
+// With the predictive back gesture (android:enableOnBackInvokedCallback), the back key event is not delivered.
+// Call EbitenView's onBackPressed from your OnBackInvokedCallback instead.
+//
+// SetBackHandler does nothing on iOS.
+func SetBackHandler(handler func() bool) {
+	setBackHandler(handler)
+}
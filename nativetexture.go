@@ -0,0 +1,53 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
+	"github.com/hajimehoshi/ebiten/v2/internal/hooks"
+	"github.com/hajimehoshi/ebiten/v2/internal/mipmap"
+)
+
+func init() {
+	hooks.OnNewImageFromNativeTexture(func(texture graphicsdriver.NativeTexture, width, height int) interface{} {
+		return newImageFromNativeTexture(texture, width, height)
+	})
+}
+
+// newImageFromNativeTexture creates a new image wrapping the given native texture.
+//
+// This is exposed by the package exp/native.
+func newImageFromNativeTexture(texture graphicsdriver.NativeTexture, width, height int) *Image {
+	if isRunGameEnded() {
+		panic(fmt.Sprintf("ebiten: NewImageFromNativeTexture cannot be called after RunGame finishes"))
+	}
+	if width <= 0 {
+		panic(fmt.Sprintf("ebiten: width at NewImageFromNativeTexture must be positive but %d", width))
+	}
+	if height <= 0 {
+		panic(fmt.Sprintf("ebiten: height at NewImageFromNativeTexture must be positive but %d", height))
+	}
+
+	i := &Image{
+		mipmap: mipmap.NewFromNativeTexture(width, height, texture),
+		bounds: image.Rect(0, 0, width, height),
+		native: true,
+	}
+	i.addr = i
+	return i
+}
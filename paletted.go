@@ -0,0 +1,190 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/affine"
+	"github.com/hajimehoshi/ebiten/v2/internal/graphics"
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
+	"github.com/hajimehoshi/ebiten/v2/internal/mipmap"
+)
+
+// NewIndexImage creates a new image holding the palette indices of the given paletted image (source).
+//
+// The index of each pixel is stored to the red, green, and blue channels, and the alpha channel is always 0xff.
+// The created image is used as a source of DrawPalettedImage.
+//
+// If source's width or height is less than 1 or more than device-dependent maximum size, NewIndexImage panics.
+//
+// NewIndexImage panics if RunGame already finishes.
+func NewIndexImage(source *image.Paletted) *Image {
+	b := source.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= 0 {
+		panic(fmt.Sprintf("ebiten: source width at NewIndexImage must be positive but %d", w))
+	}
+	if h <= 0 {
+		panic(fmt.Sprintf("ebiten: source height at NewIndexImage must be positive but %d", h))
+	}
+
+	pix := make([]byte, 4*w*h)
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			idx := source.ColorIndexAt(b.Min.X+i, b.Min.Y+j)
+			pix[4*(j*w+i)] = idx
+			pix[4*(j*w+i)+1] = idx
+			pix[4*(j*w+i)+2] = idx
+			pix[4*(j*w+i)+3] = 0xff
+		}
+	}
+	img := NewImage(w, h)
+	img.ReplacePixels(pix)
+	return img
+}
+
+// NewPaletteImage creates a new image holding the given palette's colors in a row.
+//
+// The created image's width is len(palette) and the height is 1.
+// The created image is used as a palette of DrawPalettedImage.
+//
+// If len(palette) is 0 or more than 256, NewPaletteImage panics.
+//
+// NewPaletteImage panics if RunGame already finishes.
+func NewPaletteImage(palette color.Palette) *Image {
+	if len(palette) == 0 || len(palette) > 256 {
+		panic(fmt.Sprintf("ebiten: len(palette) at NewPaletteImage must be in [1, 256] but %d", len(palette)))
+	}
+
+	pix := make([]byte, 4*len(palette))
+	for i, c := range palette {
+		rgba := color.RGBAModel.Convert(c).(color.RGBA)
+		pix[4*i] = rgba.R
+		pix[4*i+1] = rgba.G
+		pix[4*i+2] = rgba.B
+		pix[4*i+3] = rgba.A
+	}
+	img := NewImage(len(palette), 1)
+	img.ReplacePixels(pix)
+	return img
+}
+
+// DrawPalettedImageOptions represents options for DrawPalettedImage.
+type DrawPalettedImageOptions struct {
+	// GeoM is a geometry matrix to draw.
+	// The default (zero) value is identity, which draws the image at (0, 0).
+	GeoM GeoM
+
+	// CompositeMode is a composite mode to draw.
+	// The default (zero) value is regular alpha blending.
+	CompositeMode CompositeMode
+}
+
+// DrawPalettedImage draws the given index image on the image i with colors looked up from the given palette image.
+//
+// The red channel of each pixel of indices is regarded as an index of the palette, and the pixel at (index, 0)
+// of palette is rendered. Index images and palette images are usually created by NewIndexImage and
+// NewPaletteImage. Palette swapping is done by drawing the same index image with different palette images.
+//
+// The index image is sampled with the nearest filter. If an index is out of the palette's width,
+// the result is undefined.
+//
+// DrawPalettedImage doesn't work correctly when linear blending is enabled by SetLinearBlendingEnabled,
+// as the indices are decoded from sRGB.
+//
+// When the image i is disposed, DrawPalettedImage does nothing.
+// When the given image indices or palette is disposed, DrawPalettedImage panics.
+func (i *Image) DrawPalettedImage(indices *Image, palette *Image, options *DrawPalettedImageOptions) {
+	i.copyCheck()
+
+	if indices.isDisposed() {
+		panic("ebiten: the given index image to DrawPalettedImage must not be disposed")
+	}
+	if palette.isDisposed() {
+		panic("ebiten: the given palette image to DrawPalettedImage must not be disposed")
+	}
+	if i.isDisposed() {
+		return
+	}
+
+	dstBounds := i.Bounds()
+	dstRegion := graphicsdriver.Region{
+		X:      float32(dstBounds.Min.X),
+		Y:      float32(dstBounds.Min.Y),
+		Width:  float32(dstBounds.Dx()),
+		Height: float32(dstBounds.Dy()),
+	}
+
+	if options == nil {
+		options = &DrawPalettedImageOptions{}
+	}
+
+	mode := graphicsdriver.CompositeMode(options.CompositeMode)
+
+	b := indices.Bounds()
+	sx0 := float32(b.Min.X)
+	sy0 := float32(b.Min.Y)
+	sx1 := float32(b.Max.X)
+	sy1 := float32(b.Max.Y)
+	a, b2, c, d, tx, ty := options.GeoM.elements32()
+	vs := graphics.QuadVertices(sx0, sy0, sx1, sy1, a, b2, c, d, tx, ty, 1, 1, 1, 1)
+	is := graphics.QuadIndices()
+
+	sr := graphicsdriver.Region{
+		X:      sx0,
+		Y:      sy0,
+		Width:  float32(b.Dx()),
+		Height: float32(b.Dy()),
+	}
+
+	// Unlike DrawRectShader, the palette image can have a different size from the index image.
+	// The shader calculates the palette's texels from the offset.
+	var offsets [graphics.ShaderImageNum - 1][2]float32
+	pb := palette.Bounds()
+	offsets[0][0] = -sx0 + float32(pb.Min.X)
+	offsets[0][1] = -sy0 + float32(pb.Min.Y)
+
+	srcs := [graphics.ShaderImageNum]*mipmap.Mipmap{indices.mipmap, palette.mipmap}
+	shader := palettedShader()
+	i.mipmap.DrawTriangles(srcs, vs, is, affine.ColorMIdentity{}, mode, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dstRegion, sr, offsets, shader.shader, shader.convertUniforms(nil, nil), false, false, canSkipMipmap(options.GeoM, graphicsdriver.FilterNearest))
+}
+
+var (
+	thePalettedShader     *Shader
+	thePalettedShaderOnce sync.Once
+)
+
+// palettedShader returns a shader to look up colors of the 1st image from the indices of the 0th image.
+func palettedShader() *Shader {
+	thePalettedShaderOnce.Do(func() {
+		s, err := NewShader([]byte(`package main
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	origin, _ := imageSrcRegionOnTexture()
+	index := floor(imageSrc0UnsafeAt(texCoord).r*255 + 0.5)
+	return imageSrc1UnsafeAt(origin + vec2(index+0.5, 0.5)/imageSrcTextureSize())
+}
+`))
+		if err != nil {
+			panic(fmt.Sprintf("ebiten: compiling the paletted shader failed: %v", err))
+		}
+		thePalettedShader = s
+	})
+	return thePalettedShader
+}
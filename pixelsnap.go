@@ -0,0 +1,55 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"math"
+	"sync/atomic"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/graphics"
+)
+
+var pixelSnapping int32
+
+// SetPixelSnapping sets the default of pixel snapping for DrawImage.
+//
+// If enabled, DrawImage snaps the vertex positions to the destination pixels as if DrawImageOptions.PixelSnap is true.
+// This is useful for pixel-art games that move a camera smoothly.
+//
+// The default state is false.
+//
+// SetPixelSnapping is concurrent-safe.
+func SetPixelSnapping(enabled bool) {
+	v := int32(0)
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&pixelSnapping, v)
+}
+
+// IsPixelSnapping returns true if pixel snapping is enabled by default.
+//
+// IsPixelSnapping is concurrent-safe.
+func IsPixelSnapping() bool {
+	return atomic.LoadInt32(&pixelSnapping) != 0
+}
+
+// snapVertices rounds the destination positions of the vertices to the nearest pixels.
+func snapVertices(vs []float32) {
+	for i := 0; i < len(vs); i += graphics.VertexFloatNum {
+		vs[i] = float32(math.Floor(float64(vs[i]) + 0.5))
+		vs[i+1] = float32(math.Floor(float64(vs[i+1]) + 0.5))
+	}
+}
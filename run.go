@@ -15,9 +15,11 @@
 package ebiten
 
 import (
+	"context"
 	"sync/atomic"
 
 	"github.com/hajimehoshi/ebiten/v2/internal/clock"
+	"github.com/hajimehoshi/ebiten/v2/internal/hooks"
 	"github.com/hajimehoshi/ebiten/v2/internal/ui"
 )
 
@@ -59,9 +61,103 @@ type Game interface {
 	Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int)
 }
 
+// DrawErrorer is an optional interface for Game to report an error at drawing.
+//
+// If a game implements DrawErrorer, DrawWithError is called instead of Draw.
+// When DrawWithError returns a non-nil error, the game loop is terminated and RunGame returns the same error.
+type DrawErrorer interface {
+	DrawWithError(screen *Image) error
+}
+
+// LayoutErrorer is an optional interface for Game to report an error at layouting.
+//
+// If a game implements LayoutErrorer, LayoutWithError is called instead of Layout.
+// When LayoutWithError returns a non-nil error, the game loop is terminated and RunGame returns the same error.
+// Otherwise, LayoutWithError must return positive numbers as Layout does.
+type LayoutErrorer interface {
+	LayoutWithError(outsideWidth, outsideHeight int) (screenWidth, screenHeight int, err error)
+}
+
+func drawGame(game Game, screen *Image) error {
+	if d, ok := game.(DrawErrorer); ok {
+		return d.DrawWithError(screen)
+	}
+	game.Draw(screen)
+	return nil
+}
+
+func layoutGame(game Game, outsideWidth, outsideHeight int) (int, int, error) {
+	if l, ok := game.(LayoutErrorer); ok {
+		return l.LayoutWithError(outsideWidth, outsideHeight)
+	}
+	w, h := game.Layout(outsideWidth, outsideHeight)
+	return w, h, nil
+}
+
+// Suspender is an optional interface for Game to be notified when the game is suspended.
+//
+// OnSuspend is called when the window is minimized on desktops, when the application goes to background on mobiles,
+// or when the tab becomes invisible on browsers.
+// OnSuspend is also called when the window loses focus and IsRunnableOnUnfocused is false.
+//
+// While the game is suspended, Update might not be called.
+// OnSuspend is useful to pause audio players or timers that don't depend on ticks consistently.
+//
+// On mobiles, OnSuspend might be called on a different goroutine from Update and Draw.
+type Suspender interface {
+	OnSuspend()
+}
+
+// Resumer is an optional interface for Game to be notified when the game is resumed after being suspended.
+//
+// OnResume is called only after OnSuspend is called.
+//
+// On mobiles, OnResume might be called on a different goroutine from Update and Draw.
+type Resumer interface {
+	OnResume()
+}
+
+// RefreshRateObserver is an optional interface for Game to be notified when the refresh rate of the display changes.
+//
+// OnRefreshRateChange is called with the new refresh rate in Hz before Update, e.g., when the window moves to
+// another monitor or when the video mode is changed.
+// OnRefreshRateChange is useful to adapt animation smoothing to the display.
+type RefreshRateObserver interface {
+	OnRefreshRateChange(hz float64)
+}
+
+// KeyboardLayoutObserver is an optional interface for Game to be notified when the keyboard layout is changed.
+//
+// OnKeyboardLayoutChange is called before Update when the user switches the keyboard layout or the input source of
+// the OS. OnKeyboardLayoutChange is useful to update key labels shown in the game.
+// As Key represents a physical key position, the results of IsKeyPressed don't depend on the keyboard layout.
+//
+// On desktops, a change is detected by comparing the names of printable keys given by the OS every frame.
+// On browsers, a change is notified only when the browser supports navigator.keyboard.getLayoutMap, and is detected
+// when the canvas gets a key or the window gets focused.
+// On mobiles, OnKeyboardLayoutChange is never called.
+type KeyboardLayoutObserver interface {
+	OnKeyboardLayoutChange()
+}
+
 // DefaultTPS represents a default ticks per second, that represents how many times game updating happens in a second.
 const DefaultTPS = ui.DefaultTPS
 
+// MonitorRefreshRate returns the refresh rate of the display that the game is shown on in Hz.
+//
+// On desktops, the refresh rate is the one of the current monitor's video mode.
+// On browsers and mobiles, the refresh rate is measured from the intervals of the frames driven by the display,
+// and MonitorRefreshRate returns 0 until the measurement finishes or when FPSMode is not FPSModeVsyncOn.
+// With a variable refresh rate display, the returned value is the maximum refresh rate on desktops,
+// or the measured one on browsers and mobiles.
+//
+// To be notified when the refresh rate changes, implement RefreshRateObserver.
+//
+// MonitorRefreshRate is concurrent-safe.
+func MonitorRefreshRate() float64 {
+	return ui.RefreshRate()
+}
+
 // CurrentFPS returns the current number of FPS (frames per second), that represents
 // how many swapping buffer happens per second.
 //
@@ -94,6 +190,31 @@ func IsScreenClearedEveryFrame() bool {
 	return ui.IsScreenClearedEveryFrame()
 }
 
+// LastScreen returns an image holding the result of the last Draw call.
+//
+// The image is the logical screen passed to Draw, before it is scaled and rendered to the window.
+// The image size is the screen size returned by Layout.
+// LastScreen is useful for post-processing and transition effects that sample the previous frame.
+//
+// The returned image is a copy of the screen.
+// You can read its pixels or use it as a source image, but must not draw to it or dispose it.
+// The returned image is replaced when the screen size changes, so call LastScreen every time instead of keeping the image.
+//
+// The screen is copied only in frames where LastScreen is called, so LastScreen costs nothing when unused.
+// If LastScreen is called in Draw and was not called in the previous frame or the preceding Update,
+// the screen being drawn has already replaced the last result, and the returned image is not up to date.
+// Call LastScreen in Update in such cases.
+// LastScreen returns nil before the game starts.
+//
+// LastScreen must be called from Update or Draw.
+func LastScreen() *Image {
+	g, ok := ui.Get().RunningGame().(*gameForUI)
+	if !ok {
+		return nil
+	}
+	return g.lastScreenImage()
+}
+
 // SetScreenFilterEnabled enables/disables the use of the "screen" filter Ebiten uses.
 //
 // The "screen" filter is a box filter from game to display resolution.
@@ -131,18 +252,55 @@ func (i *imageDumperGame) Update() error {
 }
 
 func (i *imageDumperGame) Draw(screen *Image) {
+	if err := i.DrawWithError(screen); err != nil {
+		i.err = err
+	}
+}
+
+func (i *imageDumperGame) DrawWithError(screen *Image) error {
 	if i.err != nil {
-		return
+		return nil
 	}
 
-	i.game.Draw(screen)
+	if err := drawGame(i.game, screen); err != nil {
+		return err
+	}
 	i.err = i.d.dump(screen)
+	return nil
+}
+
+func (i *imageDumperGame) OnSuspend() {
+	if s, ok := i.game.(Suspender); ok {
+		s.OnSuspend()
+	}
+}
+
+func (i *imageDumperGame) OnResume() {
+	if r, ok := i.game.(Resumer); ok {
+		r.OnResume()
+	}
+}
+
+func (i *imageDumperGame) OnRefreshRateChange(hz float64) {
+	if o, ok := i.game.(RefreshRateObserver); ok {
+		o.OnRefreshRateChange(hz)
+	}
+}
+
+func (i *imageDumperGame) OnKeyboardLayoutChange() {
+	if o, ok := i.game.(KeyboardLayoutObserver); ok {
+		o.OnKeyboardLayoutChange()
+	}
 }
 
 func (i *imageDumperGame) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
 	return i.game.Layout(outsideWidth, outsideHeight)
 }
 
+func (i *imageDumperGame) LayoutWithError(outsideWidth, outsideHeight int) (screenWidth, screenHeight int, err error) {
+	return layoutGame(i.game, outsideWidth, outsideHeight)
+}
+
 // RunGame starts the main loop and runs the game.
 // game's Update function is called every tick to update the game logic.
 // game's Draw function is called every frame to draw the screen.
@@ -161,6 +319,7 @@ func (i *imageDumperGame) Layout(outsideWidth, outsideHeight int) (screenWidth,
 //
 // RunGame returns error when 1) error happens in the underlying graphics driver, 2) audio error happens or
 // 3) f returns error. In the case of 3), RunGame returns the same error.
+// If game implements DrawErrorer or LayoutErrorer, errors from them are treated in the same way as 3).
 //
 // The size unit is device-independent pixel.
 //
@@ -181,6 +340,96 @@ func RunGame(game Game) error {
 	return nil
 }
 
+// RunGameWithContext starts the main loop and runs the game as RunGame does,
+// but terminates the main loop when ctx is canceled.
+//
+// When ctx is canceled, the game loop is terminated before the next frame starts,
+// i.e., after the commands of the current frame are flushed.
+// This works even while Update is not called, e.g., when the window is unfocused.
+// Audio is suspended at the termination.
+// In this case, RunGameWithContext returns nil.
+//
+// RunGameWithContext is useful when a game is embedded in a larger application or in tests.
+//
+// The same restrictions as RunGame are applied to RunGameWithContext.
+// For example, RunGameWithContext must be called on the main thread, and
+// either RunGame or RunGameWithContext can be called only once in one process.
+func RunGameWithContext(ctx context.Context, game Game) error {
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			ui.RequestTermination()
+			// The main loop might wait for events e.g. in FPSModeVsyncOffMinimum. Wake the loop up.
+			ScheduleFrame()
+		case <-done:
+		}
+	}()
+
+	if err := RunGame(game); err != nil {
+		return err
+	}
+	if ctx.Err() != nil {
+		if err := hooks.SuspendAudio(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Termination is a special error which indicates Game termination without error.
+//
+// If Update returns Termination, RunGame terminates the game loop and returns nil.
+// UpdateGameWithoutMainLoop returns Termination when the game is terminated regularly, e.g., when the window is closed.
+var Termination = ui.RegularTermination
+
+// StartGameWithoutMainLoop starts the game without running the main loop.
+// This is useful when the caller has its own main loop, e.g., when a game is embedded in another UI framework.
+//
+// After StartGameWithoutMainLoop succeeds, UpdateGameWithoutMainLoop must be called every frame by the caller.
+//
+// As long as StartGameWithoutMainLoop and UpdateGameWithoutMainLoop are called on the main thread,
+// the thread affinity required by the graphics drivers is handled internally.
+// With the ebitensinglethread build tag, Ebiten doesn't use any other threads for the graphics drivers.
+//
+// Between two UpdateGameWithoutMainLoop calls, functions like SetWindowTitle and (*Image).ReadPixels
+// run directly on the calling thread, so call them on the main thread.
+//
+// Either RunGame, RunGameWithContext or StartGameWithoutMainLoop can be called only once in one process.
+//
+// On mobiles, use the mobile package instead.
+func StartGameWithoutMainLoop(game Game) error {
+	initializeWindowPositionIfNeeded(WindowSize())
+	g := newGameForUI(&imageDumperGame{
+		game: game,
+	})
+	if err := ui.Get().StartWithoutMainLoop(g); err != nil {
+		atomic.StoreInt32(&isRunGameEnded_, 1)
+		return err
+	}
+	return nil
+}
+
+// UpdateGameWithoutMainLoop proceeds the game started by StartGameWithoutMainLoop by one frame.
+//
+// In one frame, UpdateGameWithoutMainLoop processes the window events and the inputs,
+// calls game's Update zero or more times based on TPS, calls game's Draw, and presents the screen.
+// UpdateGameWithoutMainLoop might block until the next vsync, depending on the FPS mode.
+//
+// UpdateGameWithoutMainLoop returns Termination when the game is terminated regularly,
+// and returns other errors in the same cases as RunGame.
+// After UpdateGameWithoutMainLoop returns an error, the game must not be updated any longer.
+//
+// UpdateGameWithoutMainLoop must be called on the main thread.
+func UpdateGameWithoutMainLoop() error {
+	if err := ui.Get().UpdateWithoutMainLoop(); err != nil {
+		atomic.StoreInt32(&isRunGameEnded_, 1)
+		return err
+	}
+	return nil
+}
+
 func isRunGameEnded() bool {
 	return atomic.LoadInt32(&isRunGameEnded_) != 0
 }
@@ -204,6 +453,28 @@ func ScreenSizeInFullscreen() (int, int) {
 	return ui.Get().ScreenSizeInFullscreen()
 }
 
+// SafeAreaInsets returns the insets of the safe area in device-independent pixels, i.e., the same unit as the
+// outside size given to Layout.
+//
+// The safe area is the area not covered by display cutouts like a notch, rounded corners, the home indicator, or
+// system bars. Draw important content like HUD elements inside the safe area.
+// The insets might change, e.g., when the device is rotated.
+//
+// On desktops, SafeAreaInsets always returns zeros.
+//
+// On browsers, SafeAreaInsets returns the values of the CSS env(safe-area-inset-*).
+// The values are non-zero only when the page covers the display cutouts with viewport-fit=cover.
+// Ebiten doesn't specify viewport-fit=cover by default. To use it, put the page's own viewport meta element like
+// <meta name="viewport" content="width=device-width, initial-scale=1, viewport-fit=cover">.
+// Ebiten doesn't add its viewport meta element when the page has one.
+//
+// On mobiles, SafeAreaInsets returns zeros with gomobile-build.
+//
+// SafeAreaInsets is concurrent-safe.
+func SafeAreaInsets() (left, top, right, bottom float64) {
+	return ui.Get().SafeAreaInsets()
+}
+
 // CursorMode returns the current cursor mode.
 //
 // CursorMode returns CursorModeHidden on mobiles.
@@ -219,6 +490,8 @@ func CursorMode() CursorModeType {
 // CursorModeCaptured hides the system cursor and locks it to the window.
 //
 // CursorModeCaptured also works on browsers.
+// As capturing the cursor requires a user gesture on browsers, capturing might be deferred until the next user
+// gesture like a key press, a mouse click or a touch on the canvas.
 // When the user exits the captured mode not by SetCursorMode but by the UI (e.g., pressing ESC),
 // the previous cursor mode is set automatically.
 //
@@ -262,7 +535,9 @@ func IsFullscreen() bool {
 // On desktops, Ebiten uses 'windowed' fullscreen mode, which doesn't change
 // your monitor's resolution.
 //
-// On browsers, triggering fullscreen requires a user gesture otherwise SetFullscreen does nothing but leave an error message in console.
+// On browsers, triggering fullscreen requires a user gesture.
+// If SetFullscreen(true) is called without a user gesture, the request is deferred and executed at the next user
+// gesture like a key press, a mouse click or a touch on the canvas.
 // This behaviour varies across browser implementations, your mileage may vary.
 //
 // SetFullscreen does nothing on mobiles.
@@ -285,6 +560,39 @@ func IsFocused() bool {
 	return ui.Get().IsFocused()
 }
 
+// WindowOcclusionStateType represents whether the window is visible to the user.
+type WindowOcclusionStateType = ui.OcclusionState
+
+// WindowOcclusionStateTypes
+const (
+	// WindowOcclusionStateVisible indicates that the window is, or might be, visible.
+	WindowOcclusionStateVisible WindowOcclusionStateType = WindowOcclusionStateType(ui.OcclusionStateVisible)
+
+	// WindowOcclusionStateOccluded indicates that the window is not visible though it is not minimized,
+	// e.g., the window is covered by other windows or in another virtual desktop, or the browser tab is in background.
+	WindowOcclusionStateOccluded WindowOcclusionStateType = WindowOcclusionStateType(ui.OcclusionStateOccluded)
+
+	// WindowOcclusionStateMinimized indicates that the window is minimized.
+	WindowOcclusionStateMinimized WindowOcclusionStateType = WindowOcclusionStateType(ui.OcclusionStateMinimized)
+)
+
+// WindowOcclusionState returns whether the window is visible to the user.
+//
+// Unlike IsFocused, WindowOcclusionState reports whether the rendering result can be seen, and is useful to
+// throttle rendering.
+//
+// The detection depends on the environment.
+// On macOS, a window covered by other windows is reported as occluded.
+// On Windows, only a window in another virtual desktop is reported as occluded.
+// On Linux and UNIX, a window is never reported as occluded.
+// On browsers, a hidden page like a background tab or a minimized browser window is reported as occluded.
+// On mobiles, an application in background is reported as occluded.
+//
+// WindowOcclusionState is concurrent-safe.
+func WindowOcclusionState() WindowOcclusionStateType {
+	return WindowOcclusionStateType(ui.Get().OcclusionState())
+}
+
 // IsRunnableOnUnfocused returns a boolean value indicating whether
 // the game runs even in background.
 //
@@ -398,6 +706,9 @@ func ScheduleFrame() {
 
 // MaxTPS returns the current maximum TPS.
 //
+// If the maximum TPS is not an integer, MaxTPS returns the rounded value.
+// Use MaxTPSRational to get the exact value.
+//
 // MaxTPS is concurrent-safe.
 func MaxTPS() int {
 	return ui.MaxTPS()
@@ -413,6 +724,21 @@ func CurrentTPS() float64 {
 	return clock.CurrentTPS()
 }
 
+// TickProgress returns the progress of the next pending tick (Update call) in [0, 1), measured at the current frame.
+//
+// When FPS is higher than TPS, e.g., when a game runs on a 144 Hz display with 60 TPS, Draw is called more often
+// than Update. TickProgress is useful to interpolate the game state between the last Update and the next Update
+// in Draw for smooth rendering.
+//
+// TickProgress is updated at the beginning of each frame, before Update is called.
+//
+// If TPS is SyncWithFPS, TickProgress always returns 0.
+//
+// TickProgress is concurrent-safe.
+func TickProgress() float64 {
+	return clock.TickProgress()
+}
+
 // SyncWithFPS is a special TPS value that means TPS syncs with FPS.
 const SyncWithFPS = clock.SyncWithFPS
 
@@ -433,6 +759,96 @@ func SetMaxTPS(tps int) {
 	ui.SetMaxTPS(tps)
 }
 
+// MaxTPSRational returns the current maximum TPS as a reduced rational number num/den.
+//
+// If TPS is SyncWithFPS, MaxTPSRational returns (SyncWithFPS, 1).
+//
+// MaxTPSRational is concurrent-safe.
+func MaxTPSRational() (num, den int) {
+	return ui.MaxTPSRational()
+}
+
+// SetMaxTPSRational sets the maximum TPS to the rational number num/den, e.g., 60000/1001 for 59.94 TPS.
+//
+// The logical game time advances exactly by den/num seconds per tick, so the game time doesn't drift
+// from the system clock in the long run even when a tick is not an integer nanoseconds.
+//
+// After reduced, num must be <= 1000000000 and den must be <= 1000000, or SetMaxTPSRational panics.
+// If num is negative or den is not positive, SetMaxTPSRational panics.
+// (SyncWithFPS, 1) is also accepted, and is the same as SetMaxTPS(SyncWithFPS).
+//
+// SetMaxTPSRational is concurrent-safe.
+func SetMaxTPSRational(num, den int) {
+	ui.SetMaxTPSRational(num, den)
+}
+
+// SetMaxTPSFloat sets the maximum TPS to the rational number closest to tps, e.g., 59.94 or 120.000004798.
+//
+// The rational number's denominator is at most 1000000.
+// Use MaxTPSRational to get the actual value.
+//
+// If tps is not positive, the game is not updated, as SetMaxTPS(0) does.
+//
+// SetMaxTPSFloat is concurrent-safe.
+func SetMaxTPSFloat(tps float64) {
+	num, den := clock.RationalTPS(tps)
+	ui.SetMaxTPSRational(int(num), int(den))
+}
+
+// MaxUpdatesPerFrame returns the maximum number of Update calls per frame.
+// 0 means that there is no limit.
+//
+// MaxUpdatesPerFrame is concurrent-safe.
+func MaxUpdatesPerFrame() int {
+	return ui.MaxUpdatesPerFrame()
+}
+
+// SetMaxUpdatesPerFrame sets the maximum number of Update calls per frame.
+//
+// When a frame takes longer than a tick, e.g. because Draw is heavy, Ebiten calls Update multiple times
+// in the next frame to catch up with the system clock.
+// SetMaxUpdatesPerFrame limits this so that a slow frame doesn't cause even more Update calls.
+// How the ticks exceeding the limit are treated depends on the frame skip policy. See SetFrameSkipPolicy.
+//
+// If n is 0, there is no limit. This is the default value.
+// If n is negative, SetMaxUpdatesPerFrame panics.
+//
+// SetMaxUpdatesPerFrame is concurrent-safe.
+func SetMaxUpdatesPerFrame(n int) {
+	ui.SetMaxUpdatesPerFrame(n)
+}
+
+// FrameSkipPolicyType is a type of policies for the ticks exceeding the limit by SetMaxUpdatesPerFrame.
+type FrameSkipPolicyType = ui.FrameSkipPolicyType
+
+const (
+	// FrameSkipPolicyCatchUp indicates that the ticks exceeding the limit are carried over to the following frames.
+	// The game time eventually catches up with the system time as long as the game is fast enough on average.
+	// FrameSkipPolicyCatchUp is the default policy.
+	FrameSkipPolicyCatchUp FrameSkipPolicyType = ui.FrameSkipPolicyCatchUp
+
+	// FrameSkipPolicySlowMotion indicates that the ticks exceeding the limit are discarded.
+	// The game time falls behind the system time, and the game looks slowed down while frames are slow.
+	FrameSkipPolicySlowMotion FrameSkipPolicyType = ui.FrameSkipPolicySlowMotion
+)
+
+// FrameSkipPolicy returns the current frame skip policy.
+//
+// FrameSkipPolicy is concurrent-safe.
+func FrameSkipPolicy() FrameSkipPolicyType {
+	return ui.FrameSkipPolicy()
+}
+
+// SetFrameSkipPolicy sets the policy for the ticks exceeding the limit by SetMaxUpdatesPerFrame.
+// The default policy is FrameSkipPolicyCatchUp.
+//
+// SetFrameSkipPolicy has no effect when MaxUpdatesPerFrame is 0.
+//
+// SetFrameSkipPolicy is concurrent-safe.
+func SetFrameSkipPolicy(policy FrameSkipPolicyType) {
+	ui.SetFrameSkipPolicy(policy)
+}
+
 // IsScreenTransparent reports whether the window is transparent.
 //
 // IsScreenTransparent is concurrent-safe.
@@ -451,6 +867,39 @@ func SetScreenTransparent(transparent bool) {
 	ui.Get().SetScreenTransparent(transparent)
 }
 
+// IsLinearBlendingEnabled reports whether linear blending is enabled.
+//
+// IsLinearBlendingEnabled returns false when the graphics driver turns out not to support linear blending,
+// e.g., OpenGL older than 3.0 without the GL_ARB_framebuffer_sRGB extension.
+// As this is detected when the graphics driver is initialized, IsLinearBlendingEnabled should be called
+// after the game starts to know the actual state.
+//
+// IsLinearBlendingEnabled always returns false on browsers and mobiles.
+//
+// IsLinearBlendingEnabled is concurrent-safe.
+func IsLinearBlendingEnabled() bool {
+	return ui.Get().IsLinearBlendingEnabled()
+}
+
+// SetLinearBlendingEnabled sets whether images are treated as sRGB-encoded and blended in linear space.
+//
+// By default, Ebiten blends colors as they are, i.e. in sRGB space, which makes edges of semi-transparent
+// sprites darker than expected. When linear blending is enabled, pixels are decoded to linear space
+// when sampled and encoded back to sRGB when written, so alpha blending is physically correct.
+// Pixels passed to ReplacePixels and returned by At are still sRGB-encoded.
+//
+// Note that color matrices and custom shaders operate on linear colors when linear blending is enabled.
+//
+// SetLinearBlendingEnabled panics if SetLinearBlendingEnabled is called after the main loop.
+//
+// SetLinearBlendingEnabled works only on desktops with Metal or OpenGL.
+// SetLinearBlendingEnabled does nothing on browsers and mobiles.
+//
+// SetLinearBlendingEnabled is concurrent-safe.
+func SetLinearBlendingEnabled(enabled bool) {
+	ui.Get().SetLinearBlendingEnabled(enabled)
+}
+
 // SetInitFocused sets whether the application is focused on show.
 // The default value is true, i.e., the application is focused.
 // Note that the application does not proceed if this is not focused by default.
@@ -0,0 +1,61 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten_test
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+type observerGame struct {
+	refreshRate          float64
+	keyboardLayoutChange int
+}
+
+func (g *observerGame) Update() error {
+	return nil
+}
+
+func (g *observerGame) Draw(screen *ebiten.Image) {
+}
+
+func (g *observerGame) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return outsideWidth, outsideHeight
+}
+
+func (g *observerGame) OnRefreshRateChange(hz float64) {
+	g.refreshRate = hz
+}
+
+func (g *observerGame) OnKeyboardLayoutChange() {
+	g.keyboardLayoutChange++
+}
+
+func TestRefreshRateObserver(t *testing.T) {
+	g := &observerGame{}
+	ebiten.NewGameForUIForTesting(g).RefreshRateChanged(144)
+	if got, want := g.refreshRate, 144.0; got != want {
+		t.Errorf("refresh rate: got %v, want %v", got, want)
+	}
+}
+
+func TestKeyboardLayoutObserver(t *testing.T) {
+	g := &observerGame{}
+	ebiten.NewGameForUIForTesting(g).KeyboardLayoutChanged()
+	if got, want := g.keyboardLayoutChange, 1; got != want {
+		t.Errorf("keyboard layout changes: got %d, want %d", got, want)
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"fmt"
+	"os"
+	"syscall/js"
+)
+
+func saveScreenshot(name string, data []byte, mimeType string) error {
+	document := js.Global().Get("document")
+	if !document.Truthy() {
+		// In a Web Worker, there is no document to download a file.
+		if _, err := fmt.Fprintf(os.Stderr, "Screenshots are not available in a Web Worker: %s\n", name); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	arr := js.Global().Get("Uint8Array").New(len(data))
+	js.CopyBytesToJS(arr, data)
+	blob := js.Global().Get("Blob").New([]interface{}{arr}, map[string]interface{}{
+		"type": mimeType,
+	})
+	url := js.Global().Get("URL").Call("createObjectURL", blob)
+	defer js.Global().Get("URL").Call("revokeObjectURL", url)
+
+	a := document.Call("createElement", "a")
+	a.Set("href", url)
+	a.Set("download", name)
+	a.Get("style").Set("display", "none")
+	document.Get("body").Call("appendChild", a)
+	a.Call("click")
+	document.Get("body").Call("removeChild", a)
+
+	if _, err := fmt.Fprintf(os.Stderr, "Downloaded screenshot: %s\n", name); err != nil {
+		return err
+	}
+	return nil
+}
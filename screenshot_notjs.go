@@ -0,0 +1,35 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+// +build !js
+
+package ebiten
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+func saveScreenshot(name string, data []byte, mimeType string) error {
+	if err := ioutil.WriteFile(name, data, 0644); err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(os.Stderr, "Saved screenshot: %s\n", name); err != nil {
+		return err
+	}
+	return nil
+}
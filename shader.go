@@ -17,9 +17,11 @@ package ebiten
 import (
 	"bytes"
 	"fmt"
+	"go/ast"
 	"go/parser"
 	"go/token"
 	"strings"
+	"sync"
 
 	"github.com/hajimehoshi/ebiten/v2/internal/graphics"
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicscommand"
@@ -29,10 +31,47 @@ import (
 	"github.com/hajimehoshi/ebiten/v2/internal/shaderir"
 )
 
-var shaderSuffix string
+var (
+	// shaderSuffix is the source appended to all the shaders.
+	shaderSuffix string
+
+	// shaderSuffixWithSourceRegions is the same as shaderSuffix except that imageSrcNAt considers the source images'
+	// sizes, offsets and address modes given to DrawRectShader.
+	// As the additional uniform variables and calculations have costs, shaders with this suffix are used only when
+	// necessary.
+	shaderSuffixWithSourceRegions string
+
+	// prevDstFuncSrc is the source of imagePrevDstAt. prevDstFuncSrc is added only to the shaders calling it.
+	prevDstFuncSrc string
+)
 
 func init() {
-	shaderSuffix = `
+	shaderSuffix = newShaderSuffix(false)
+	shaderSuffixWithSourceRegions = newShaderSuffix(true)
+
+	// The last source image slot is used for a copy of the destination image when imagePrevDstAt is used.
+	// The offset of the copy is in the 0th source texture's texels, or in pixels when there is no 0th source image.
+	prevDstFuncSrc = fmt.Sprintf(`
+// imagePrevDstAt returns the destination image's color at pos before the current draw call.
+// pos is the position in pixels of the destination texture, which is the same unit as Fragment's position.
+func imagePrevDstAt(pos vec2) vec4 {
+	origin := __textureSourceOffsets[%[1]d] + __textureSourceRegionOrigin
+	if __textureSizes[0].x > 0 {
+		origin *= __textureSizes[0]
+	}
+	p := pos - __textureDestinationRegionOrigin*__imageDstTextureSize
+	size := __textureDestinationRegionSize * __imageDstTextureSize
+	return texture2D(__t%[2]d, (origin+p)/__textureSizes[%[2]d]) *
+		step(0, p.x) *
+		(1 - step(size.x, p.x)) *
+		step(0, p.y) *
+		(1 - step(size.y, p.y))
+}
+`, prevDstImageIndex-1, prevDstImageIndex)
+}
+
+func newShaderSuffix(sourceRegions bool) string {
+	shaderSuffix := `
 var __imageDstTextureSize vec2
 
 // imageSrcTextureSize returns the destination image's texture size in pixels.
@@ -83,11 +122,31 @@ func imageSrcRegionOnTexture() (vec2, vec2) {
 }
 `, graphics.ShaderImageNum, graphics.ShaderImageNum-1)
 
+	if sourceRegions {
+		// These uniform variables are for the source images' regions and address modes, and set by this package.
+		shaderSuffix += fmt.Sprintf(`
+// The source images' sizes in pixels.
+var __sourceSizes [%[1]d]vec2
+
+// The source images' offsets in pixels relative to the 0th image's offset.
+var __sourceShifts [%[2]d]vec2
+
+// The source images' address modes.
+var __sourceAddresses [%[1]d]float
+`, graphics.ShaderImageNum, graphics.ShaderImageNum-1)
+	}
+
 	for i := 0; i < graphics.ShaderImageNum; i++ {
 		pos := "pos"
+		// The position in pixels of the target texture.
+		pixel := "pos * __textureSizes[0]"
+		// The origin of the target image in pixels of the target texture.
+		origin := "__textureSourceRegionOrigin * __textureSizes[0]"
 		if i >= 1 {
 			// Convert the position in texture0's texels to the target texture texels.
 			pos = fmt.Sprintf("(pos + __textureSourceOffsets[%d]) * __textureSizes[0] / __textureSizes[%d]", i-1, i)
+			pixel = fmt.Sprintf("(pos + __textureSourceOffsets[%d]) * __textureSizes[0]", i-1)
+			origin = fmt.Sprintf("(__textureSourceRegionOrigin + __textureSourceOffsets[%[1]d]) * __textureSizes[0] - __sourceShifts[%[1]d]", i-1)
 		}
 		// __t%d is a special variable for a texture variable.
 		shaderSuffix += fmt.Sprintf(`
@@ -95,7 +154,10 @@ func imageSrc%[1]dUnsafeAt(pos vec2) vec4 {
 	// pos is the position in texels of the source texture (= 0th image's texture).
 	return texture2D(__t%[1]d, %[2]s)
 }
+`, i, pos)
 
+		if !sourceRegions {
+			shaderSuffix += fmt.Sprintf(`
 func imageSrc%[1]dAt(pos vec2) vec4 {
 	// pos is the position in texels of the source texture (= 0th image's texture).
 	return texture2D(__t%[1]d, %[2]s) *
@@ -105,8 +167,36 @@ func imageSrc%[1]dAt(pos vec2) vec4 {
 		(1 - step(__textureSourceRegionOrigin.y + __textureSourceRegionSize.y, pos.y))
 }
 `, i, pos)
+			continue
+		}
+
+		shaderSuffix += fmt.Sprintf(`
+func imageSrc%[1]dAt(pos vec2) vec4 {
+	// pos is the position in texels of the source texture (= 0th image's texture).
+	size := __sourceSizes[%[1]d]
+	p := %[2]s
+	origin := %[3]s
+	l := p - origin
+	if __sourceAddresses[%[1]d] == %[4]d {
+		return texture2D(__t%[1]d, (origin + mod(l, size)) / __textureSizes[%[1]d])
 	}
+	return texture2D(__t%[1]d, p / __textureSizes[%[1]d]) *
+		step(0, l.x) *
+		(1 - step(size.x, l.x)) *
+		step(0, l.y) *
+		(1 - step(size.y, l.y))
 }
+`, i, pixel, origin, graphicsdriver.AddressRepeat)
+	}
+
+	return shaderSuffix
+}
+
+// prevDstImageIndex is the index of the source image slot for imagePrevDstAt.
+const prevDstImageIndex = graphics.ShaderImageNum - 1
+
+// prevDstFuncName is the name of the builtin function to read the destination before drawing.
+const prevDstFuncName = "imagePrevDstAt"
 
 // Shader represents a compiled shader program.
 //
@@ -115,37 +205,82 @@ type Shader struct {
 	shader       *mipmap.Shader
 	uniformNames []string
 	uniformTypes []shaderir.Type
+
+	// usesPrevDst reports whether the shader calls the builtin imagePrevDstAt.
+	usesPrevDst bool
+
+	// src is the source of the shader without the suffix.
+	src []byte
+
+	// withSourceRegions is the variant of the shader with shaderSuffixWithSourceRegions.
+	// withSourceRegions is compiled at the first use.
+	withSourceRegions     *Shader
+	withSourceRegionsOnce sync.Once
 }
 
 // NewShader compiles a shader program in the shading language Kage, and retruns the result.
 //
 // If the compilation fails, NewShader returns an error.
 //
+// In addition to the GLSL-like builtin functions, Kage has the noise functions
+// hash2, hash3, perlin2, simplex2 and simplex3, which are the equivalents of the package exp/noise's functions.
+//
+// Kage also has imagePrevDstAt(pos vec2) vec4, which returns the destination image's color at pos before the draw call.
+// pos is in the same unit as Fragment's position.
+// imagePrevDstAt is useful for feedback effects like motion trails without managing two images by yourself.
+// When a shader calls imagePrevDstAt, Ebiten copies the whole destination image before each draw with the shader,
+// regardless of the drawn region, and the last source image slot (Images[3]) is reserved for the copy.
+// A shader declaring its own function named imagePrevDstAt uses it instead of the builtin one.
+//
 // For the details about the shader, see https://ebiten.org/documents/shader.html.
 func NewShader(src []byte) (*Shader, error) {
+	s, err := compileShader(src, shaderSuffix)
+	if err != nil {
+		return nil, err
+	}
+	s.src = make([]byte, len(src))
+	copy(s.src, src)
+	return s, nil
+}
+
+// sourceRegionsVariant returns the variant of the shader whose imageSrcNAt considers the source images' sizes,
+// offsets and address modes.
+func (s *Shader) sourceRegionsVariant() *Shader {
+	s.withSourceRegionsOnce.Do(func() {
+		v, err := compileShader(s.src, shaderSuffixWithSourceRegions)
+		if err != nil {
+			// The source was already compiled successfully with the other suffix.
+			panic(fmt.Sprintf("ebiten: compiling the shader with the source regions failed: %v", err))
+		}
+		s.withSourceRegions = v
+	})
+	return s.withSourceRegions
+}
+
+func compileShader(src []byte, suffix string) (*Shader, error) {
 	var buf bytes.Buffer
 	buf.Write(src)
-	buf.WriteString(shaderSuffix)
+	buf.WriteString(suffix)
 	if graphicscommand.NeedsInvertY() {
 		buf.WriteString(`
-func __vertex(position vec2, texCoord vec2, color vec4) (vec4, vec2, vec4) {
+func __vertex(position vec2, texCoord vec2, color vec4, depth float) (vec4, vec2, vec4) {
 	return mat4(
 		2/__imageDstTextureSize.x, 0, 0, 0,
 		0, -2/__imageDstTextureSize.y, 0, 0,
 		0, 0, 1, 0,
 		-1, 1, 0, 1,
-	) * vec4(position, 0, 1), texCoord, color
+	) * vec4(position, depth, 1), texCoord, color
 }
 `)
 	} else {
 		buf.WriteString(`
-func __vertex(position vec2, texCoord vec2, color vec4) (vec4, vec2, vec4) {
+func __vertex(position vec2, texCoord vec2, color vec4, depth float) (vec4, vec2, vec4) {
 	return mat4(
 		2/__imageDstTextureSize.x, 0, 0, 0,
 		0, 2/__imageDstTextureSize.y, 0, 0,
 		0, 0, 1, 0,
 		-1, -1, 0, 1,
-	) * vec4(position, 0, 1), texCoord, color
+	) * vec4(position, depth, 1), texCoord, color
 }
 `)
 	}
@@ -155,6 +290,10 @@ func __vertex(position vec2, texCoord vec2, color vec4) (vec4, vec2, vec4) {
 	if err != nil {
 		return nil, err
 	}
+	f, usesPrevDst, err := addPrevDstFunc(fs, f)
+	if err != nil {
+		return nil, err
+	}
 
 	const (
 		vert = "__vertex"
@@ -176,17 +315,90 @@ func __vertex(position vec2, texCoord vec2, color vec4) (vec4, vec2, vec4) {
 		shader:       mipmap.NewShader(s),
 		uniformNames: s.UniformNames,
 		uniformTypes: s.Uniforms,
+		usesPrevDst:  usesPrevDst,
 	}, nil
 }
 
+// addPrevDstFunc returns a file with imagePrevDstAt's declaration if f calls it, and reports whether it is added.
+//
+// If f declares imagePrevDstAt, the function is not added, and then the user's function is used.
+func addPrevDstFunc(fs *token.FileSet, f *ast.File) (*ast.File, bool, error) {
+	for _, d := range f.Decls {
+		if fd, ok := d.(*ast.FuncDecl); ok && fd.Name.Name == prevDstFuncName {
+			return f, false, nil
+		}
+	}
+
+	var used bool
+	ast.Inspect(f, func(n ast.Node) bool {
+		if used {
+			return false
+		}
+		c, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		if id, ok := c.Fun.(*ast.Ident); ok && id.Name == prevDstFuncName {
+			used = true
+			return false
+		}
+		return true
+	})
+	if !used {
+		return f, false, nil
+	}
+
+	pf, err := parser.ParseFile(fs, "prevdst", prevDstFuncSrc, parser.AllErrors)
+	if err != nil {
+		return nil, false, err
+	}
+	newF := *f
+	newF.Decls = append(append([]ast.Decl{}, f.Decls...), pf.Decls...)
+	return &newF, true, nil
+}
+
 // Dispose disposes the shader program.
 // After disposing, the shader is no longer available.
 func (s *Shader) Dispose() {
 	s.shader.MarkDisposed()
 	s.shader = nil
+	if s.withSourceRegions != nil {
+		s.withSourceRegions.Dispose()
+	}
+}
+
+// sourceUniforms represents the uniform variables for the source images' regions and address modes in
+// shaderSuffixWithSourceRegions.
+//
+// The values are flattened so that the uniform values can refer to them without allocations.
+type sourceUniforms struct {
+	sizes     [2 * graphics.ShaderImageNum]float32
+	shifts    [2 * (graphics.ShaderImageNum - 1)]float32
+	addresses [graphics.ShaderImageNum]float32
 }
 
-func (s *Shader) convertUniforms(uniforms map[string]interface{}) []graphicsdriver.Uniform {
+// zeroSourceUniforms is the source uniforms used when no source is specified. zeroSourceUniforms must not be modified.
+var zeroSourceUniforms sourceUniforms
+
+func (s *sourceUniforms) uniform(name string) graphicsdriver.Uniform {
+	if s == nil {
+		s = &zeroSourceUniforms
+	}
+	switch name {
+	case "__sourceSizes":
+		return graphicsdriver.Uniform{Float32s: s.sizes[:]}
+	case "__sourceShifts":
+		return graphicsdriver.Uniform{Float32s: s.shifts[:]}
+	case "__sourceAddresses":
+		return graphicsdriver.Uniform{Float32s: s.addresses[:]}
+	default:
+		panic(fmt.Sprintf("ebiten: unexpected uniform variable name: %s", name))
+	}
+}
+
+// convertUniforms converts the uniform variables into the slice for the graphics driver.
+// sources is used only for the variant from sourceRegionsVariant, and can be nil.
+func (s *Shader) convertUniforms(uniforms map[string]interface{}, sources *sourceUniforms) []graphicsdriver.Uniform {
 	type index struct {
 		resultIndex        int
 		shaderUniformIndex int
@@ -195,7 +407,9 @@ func (s *Shader) convertUniforms(uniforms map[string]interface{}) []graphicsdriv
 	names := map[string]index{}
 	var idx int
 	for i, n := range s.uniformNames {
-		if strings.HasPrefix(n, "__") {
+		// The preserved uniform variables are set by the graphics driver.
+		// The other variables including ones starting with __ are set here.
+		if i < graphics.PreservedUniformVariablesNum {
 			continue
 		}
 		names[n] = index{
@@ -207,6 +421,10 @@ func (s *Shader) convertUniforms(uniforms map[string]interface{}) []graphicsdriv
 
 	us := make([]graphicsdriver.Uniform, len(names))
 	for name, idx := range names {
+		if strings.HasPrefix(name, "__") {
+			us[idx.resultIndex] = sources.uniform(name)
+			continue
+		}
 		if v, ok := uniforms[name]; ok {
 			switch v := v.(type) {
 			case float32:
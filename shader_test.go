@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/exp/noise"
 )
 
 func TestShaderFill(t *testing.T) {
@@ -1677,3 +1678,214 @@ func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
 		}
 	}
 }
+
+func TestShaderPrevDst(t *testing.T) {
+	const w, h = 16, 16
+
+	s, err := ebiten.NewShader([]byte(`package main
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	return imagePrevDstAt(position.xy).gbra
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, withSrc := range []bool{false, true} {
+		withSrc := withSrc
+		t.Run(fmt.Sprintf("withSrc=%t", withSrc), func(t *testing.T) {
+			base := ebiten.NewImage(w*2, h*2)
+			dst := base.SubImage(image.Rect(w/2, h/2, w/2+w, h/2+h)).(*ebiten.Image)
+			pix := make([]byte, 4*w*h)
+			for j := 0; j < h; j++ {
+				for i := 0; i < w; i++ {
+					idx := 4 * (i + j*w)
+					pix[idx] = byte(i * 0x10)
+					pix[idx+1] = byte(j * 0x10)
+					pix[idx+2] = 0x80
+					pix[idx+3] = 0xff
+				}
+			}
+			dst.ReplacePixels(pix)
+
+			op := &ebiten.DrawRectShaderOptions{}
+			op.CompositeMode = ebiten.CompositeModeCopy
+			if withSrc {
+				op.Images[0] = ebiten.NewImage(w, h)
+			}
+			dst.DrawRectShader(w, h, s, op)
+
+			for j := 0; j < h; j++ {
+				for i := 0; i < w; i++ {
+					got := dst.At(w/2+i, h/2+j).(color.RGBA)
+					want := color.RGBA{byte(j * 0x10), 0x80, byte(i * 0x10), 0xff}
+					if got != want {
+						t.Errorf("dst.At(%d, %d): got: %v, want: %v", w/2+i, h/2+j, got, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestShaderUserDeclaredPrevDst(t *testing.T) {
+	const w, h = 16, 16
+
+	s, err := ebiten.NewShader([]byte(`package main
+
+func imagePrevDstAt(pos vec2) vec4 {
+	return vec4(1, 0, 0, 1)
+}
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	return imagePrevDstAt(position.xy)
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dst := ebiten.NewImage(w, h)
+	dst.Fill(color.RGBA{0, 0xff, 0, 0xff})
+	op := &ebiten.DrawRectShaderOptions{}
+	op.CompositeMode = ebiten.CompositeModeCopy
+	dst.DrawRectShader(w, h, s, op)
+
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := dst.At(i, j).(color.RGBA)
+			want := color.RGBA{0xff, 0, 0, 0xff}
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestShaderSourceOffsetsAndAddresses(t *testing.T) {
+	const w, h = 8, 8
+	const sw, sh = 2, 2
+
+	s, err := ebiten.NewShader([]byte(`package main
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	return imageSrc1At(texCoord)
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src0 := ebiten.NewImage(w, h)
+	src1 := ebiten.NewImage(sw, sh)
+	pix := make([]byte, 4*sw*sh)
+	for j := 0; j < sh; j++ {
+		for i := 0; i < sw; i++ {
+			idx := 4 * (i + j*sw)
+			pix[idx] = byte(0x40 * (i + 1))
+			pix[idx+1] = byte(0x40 * (j + 1))
+			pix[idx+3] = 0xff
+		}
+	}
+	src1.ReplacePixels(pix)
+
+	for _, address := range []ebiten.Address{ebiten.AddressClampToZero, ebiten.AddressRepeat} {
+		address := address
+		t.Run(fmt.Sprintf("address=%d", address), func(t *testing.T) {
+			dst := ebiten.NewImage(w, h)
+			op := &ebiten.DrawRectShaderOptions{}
+			op.Images[0] = src0
+			op.Images[1] = src1
+			op.SourceOffsets[1] = image.Pt(1, 0)
+			op.SourceAddresses[1] = address
+			dst.DrawRectShader(w, h, s, op)
+
+			for j := 0; j < h; j++ {
+				for i := 0; i < w; i++ {
+					got := dst.At(i, j).(color.RGBA)
+					var want color.RGBA
+					x, y := i+1, j
+					if address == ebiten.AddressRepeat {
+						x, y = x%sw, y%sh
+					}
+					if x < sw && y < sh {
+						want = color.RGBA{byte(0x40 * (x + 1)), byte(0x40 * (y + 1)), 0, 0xff}
+					}
+					if got != want {
+						t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestShaderSourceOffsetsWithoutImage0(t *testing.T) {
+	s, err := ebiten.NewShader([]byte(`package main
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	return imageSrc1At(texCoord)
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dst := ebiten.NewImage(16, 16)
+	for _, f := range []func(op *ebiten.DrawRectShaderOptions){
+		func(op *ebiten.DrawRectShaderOptions) {
+			op.SourceOffsets[1] = image.Pt(1, 0)
+		},
+		func(op *ebiten.DrawRectShaderOptions) {
+			op.SourceAddresses[1] = ebiten.AddressRepeat
+		},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("DrawRectShader with SourceOffsets or SourceAddresses but without Images[0] must panic")
+				}
+			}()
+			op := &ebiten.DrawRectShaderOptions{}
+			op.Images[1] = ebiten.NewImage(16, 16)
+			f(op)
+			dst.DrawRectShader(16, 16, s, op)
+		}()
+	}
+}
+
+func TestShaderNoise(t *testing.T) {
+	const w, h = 16, 16
+
+	dst := ebiten.NewImage(w, h)
+	s, err := ebiten.NewShader([]byte(`package main
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	p := position.xy + vec2(-0.5, 7.5)
+	return vec4(hash2(p.x, p.y), perlin2(p.x/4, p.y/4)*0.5+0.5, hash3(p.x, p.y, 3), 1)
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dst.DrawRectShader(w, h, s, nil)
+
+	// The noise functions' results might differ slightly due to floating-point rounding on GPUs.
+	near := func(got uint8, want float32) bool {
+		d := float32(got) - want*0xff
+		return -2 <= d && d <= 2
+	}
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			x, y := float32(i), float32(j)+8
+			got := dst.At(i, j).(color.RGBA)
+			wantR := noise.Hash2(x, y)
+			wantG := noise.Perlin2(x/4, y/4)*0.5 + 0.5
+			wantB := noise.Hash3(x, y, 3)
+			if !near(got.R, wantR) || !near(got.G, wantG) || !near(got.B, wantB) || got.A != 0xff {
+				t.Errorf("dst.At(%d, %d): got: %v, want: (%v, %v, %v, 1)", i, j, got, wantR, wantG, wantB)
+			}
+		}
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package text
+
+import (
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// NewOutlineFace returns a font.Face for the OpenType font f.
+//
+// The returned face works as the face returned by opentype.NewFace,
+// and also provides the glyph outlines for AppendGlyphPath.
+func NewOutlineFace(f *opentype.Font, opts *opentype.FaceOptions) (font.Face, error) {
+	face, err := opentype.NewFace(f, opts)
+	if err != nil {
+		return nil, err
+	}
+	size, dpi := 12.0, 72.0
+	if opts != nil {
+		size, dpi = opts.Size, opts.DPI
+	}
+	return &outlineFace{
+		Face: face,
+		font: f,
+		ppem: fixed.Int26_6(0.5 + (size * dpi * 64 / 72)),
+	}, nil
+}
+
+type outlineFace struct {
+	font.Face
+
+	font *opentype.Font
+	ppem fixed.Int26_6
+	buf  sfnt.Buffer
+	m    sync.Mutex
+}
+
+func (f *outlineFace) appendGlyphPath(path *vector.Path, r rune, x, y float32) bool {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	idx, err := f.font.GlyphIndex(&f.buf, r)
+	if err != nil || idx == 0 {
+		return false
+	}
+	segs, err := f.font.LoadGlyph(&f.buf, idx, f.ppem, nil)
+	if err != nil {
+		return false
+	}
+
+	// The coordinates of segments are relative to the dot, and the Y axis increases down.
+	var start, cur fixed.Point26_6
+	closeContour := func() {
+		if cur != start {
+			path.LineTo(x+fixed26_6ToFloat32(start.X), y+fixed26_6ToFloat32(start.Y))
+		}
+	}
+	for i, seg := range segs {
+		a := seg.Args
+		switch seg.Op {
+		case sfnt.SegmentOpMoveTo:
+			if i > 0 {
+				closeContour()
+			}
+			path.MoveTo(x+fixed26_6ToFloat32(a[0].X), y+fixed26_6ToFloat32(a[0].Y))
+			start, cur = a[0], a[0]
+		case sfnt.SegmentOpLineTo:
+			path.LineTo(x+fixed26_6ToFloat32(a[0].X), y+fixed26_6ToFloat32(a[0].Y))
+			cur = a[0]
+		case sfnt.SegmentOpQuadTo:
+			path.QuadTo(x+fixed26_6ToFloat32(a[0].X), y+fixed26_6ToFloat32(a[0].Y),
+				x+fixed26_6ToFloat32(a[1].X), y+fixed26_6ToFloat32(a[1].Y))
+			cur = a[1]
+		case sfnt.SegmentOpCubeTo:
+			path.CubicTo(x+fixed26_6ToFloat32(a[0].X), y+fixed26_6ToFloat32(a[0].Y),
+				x+fixed26_6ToFloat32(a[1].X), y+fixed26_6ToFloat32(a[1].Y),
+				x+fixed26_6ToFloat32(a[2].X), y+fixed26_6ToFloat32(a[2].Y))
+			cur = a[2]
+		}
+	}
+	if len(segs) > 0 {
+		closeContour()
+	}
+	return true
+}
+
+func fixed26_6ToFloat32(x fixed.Int26_6) float32 {
+	return float32(x) / (1 << 6)
+}
+
+// AppendGlyphPath appends the outline of the glyph for r to path.
+//
+// The dot of the glyph, the origin on the baseline, is placed at (x, y).
+// Each contour of the glyph is added as a closed sub-path, so the path can be filled or stroked.
+//
+// face must be created by NewOutlineFace, or be a face wrapping such a face like FaceWithLineHeight's.
+// AppendGlyphPath returns false and doesn't modify path if face doesn't provide outlines or doesn't have the glyph.
+//
+// To append a text's outlines, advance x by face's GlyphAdvance and Kern for each rune.
+func AppendGlyphPath(path *vector.Path, face font.Face, r rune, x, y float32) bool {
+	if f, ok := face.(faceWithLineHeight); ok {
+		face = f.face
+	}
+	f, ok := face.(*outlineFace)
+	if !ok {
+		return false
+	}
+	return f.appendGlyphPath(path, r, x, y)
+}
@@ -21,11 +21,14 @@ import (
 
 	"github.com/hajimehoshi/bitmapfont/v2"
 	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
 	"golang.org/x/image/math/fixed"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	t "github.com/hajimehoshi/ebiten/v2/internal/testing"
 	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
 )
 
 func TestMain(m *testing.M) {
@@ -146,3 +149,42 @@ func TestTextOverlap(t *testing.T) {
 		}
 	}
 }
+
+func TestAppendGlyphPath(t *testing.T) {
+	f, err := opentype.Parse(goregular.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	face, err := text.NewOutlineFace(f, &opentype.FaceOptions{
+		Size: 32,
+		DPI:  72,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var path vector.Path
+	if !text.AppendGlyphPath(&path, face, 'A', 10, 40) {
+		t.Fatalf("AppendGlyphPath: got: false, want: true")
+	}
+	vs, is := path.AppendVerticesAndIndicesForFilling(nil, nil)
+	if len(vs) == 0 || len(is) == 0 {
+		t.Fatalf("len(vs): %d, len(is): %d, want: non-zero", len(vs), len(is))
+	}
+	for _, v := range vs {
+		// The glyph is above the baseline and right to the dot.
+		if v.DstX < 10 || v.DstX > 10+32 || v.DstY < 40-32 || v.DstY > 40 {
+			t.Errorf("vertex (%f, %f) is out of the expected bounds", v.DstX, v.DstY)
+		}
+	}
+
+	// A face wrapped by FaceWithLineHeight also provides outlines.
+	if !text.AppendGlyphPath(&path, text.FaceWithLineHeight(face, 40), 'B', 0, 0) {
+		t.Errorf("AppendGlyphPath with FaceWithLineHeight: got: false, want: true")
+	}
+
+	var path2 vector.Path
+	if text.AppendGlyphPath(&path2, &testFace{}, 'A', 0, 0) {
+		t.Errorf("AppendGlyphPath with a face without outlines: got: true, want: false")
+	}
+}
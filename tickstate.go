@@ -0,0 +1,207 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"bytes"
+	"encoding/gob"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/clock"
+	"github.com/hajimehoshi/ebiten/v2/internal/fakeinput"
+	"github.com/hajimehoshi/ebiten/v2/internal/hooks"
+	"github.com/hajimehoshi/ebiten/v2/internal/ui"
+)
+
+// CurrentTick returns the current tick number, i.e., the number of Update calls before the current Update.
+//
+// CurrentTick returns 0 in the first Update.
+//
+// CurrentTick is concurrent-safe.
+func CurrentTick() int64 {
+	return clock.CurrentTick()
+}
+
+// TickState is a snapshot of the states visible to the game in a tick, for rollback netcode.
+//
+// TickState includes the tick number, the input states like keys, mouse buttons, touches, and gamepads, and the
+// states in the utility packages like inpututil's press durations.
+// Ebiten doesn't have a random number generator, so the game must save its own random number generators if any.
+type TickState struct {
+	tick   int64
+	input  fakeinput.State
+	extras [][]byte
+}
+
+// tickStateData is a serializable form of TickState.
+type tickStateData struct {
+	Tick   int64
+	Input  fakeinput.State
+	Extras [][]byte
+}
+
+// SaveTickState returns the snapshot of the current tick's states.
+//
+// SaveTickState must be called from Update.
+// A typical rollback netcode implementation saves the tick state and the game state in every Update, and then
+// resimulates the ticks from a saved tick by Resimulate when the predicted inputs of the remote players turn
+// out to be wrong.
+func SaveTickState() (*TickState, error) {
+	extras, err := hooks.SaveTickStates()
+	if err != nil {
+		return nil, err
+	}
+	return &TickState{
+		tick:   CurrentTick(),
+		input:  currentInputState(),
+		extras: extras,
+	}, nil
+}
+
+// Tick returns the tick number of the state.
+func (s *TickState) Tick() int64 {
+	return s.tick
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+//
+// The encoded state can be decoded only by the same version of the same program, as it includes the states of
+// the imported packages.
+func (s *TickState) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&tickStateData{
+		Tick:   s.tick,
+		Input:  s.input,
+		Extras: s.extras,
+	}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (s *TickState) UnmarshalBinary(data []byte) error {
+	var d tickStateData
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&d); err != nil {
+		return err
+	}
+	s.tick = d.Tick
+	s.input = d.Input
+	s.extras = d.Extras
+	return nil
+}
+
+// Resimulate calls update for each state in order, with the states restored.
+//
+// While update is called, CurrentTick, the input functions like IsKeyPressed, and the utility functions like
+// inpututil.IsKeyJustPressed return the values as of the state's tick.
+// After Resimulate returns, the states are back to the current tick's.
+//
+// Resimulate is useful to re-run the ticks in one Update for rollback netcode.
+// The game must restore its own state before calling Resimulate.
+//
+// Resimulate must be called from Update. If update returns an error, Resimulate stops and returns the error.
+func Resimulate(states []*TickState, update func() error) (err error) {
+	current, err := SaveTickState()
+	if err != nil {
+		return err
+	}
+
+	fakeEnabled := fakeinput.IsEnabled()
+	fakeState := fakeinput.CurrentState()
+	if !fakeEnabled {
+		fakeinput.Enable()
+	}
+	defer func() {
+		if fakeEnabled {
+			fakeinput.SetState(&fakeState)
+		} else {
+			fakeinput.Disable()
+		}
+		clock.SetTick(current.tick)
+		if err1 := hooks.RestoreTickStates(current.extras); err1 != nil && err == nil {
+			err = err1
+		}
+	}()
+
+	for _, s := range states {
+		fakeinput.SetState(&s.input)
+		clock.SetTick(s.tick)
+		if err := hooks.RestoreTickStates(s.extras); err != nil {
+			return err
+		}
+		if err := update(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// currentInputState returns the current input states visible to the game.
+func currentInputState() fakeinput.State {
+	var s fakeinput.State
+
+	for k := ui.Key(0); k < ui.KeyReserved0; k++ {
+		if isUIKeyPressed(k) {
+			s.Keys = append(s.Keys, k)
+		}
+	}
+	for b := MouseButtonLeft; b <= MouseButtonMiddle; b++ {
+		if IsMouseButtonPressed(b) {
+			s.MouseButtons = append(s.MouseButtons, b)
+		}
+	}
+	s.CursorX, s.CursorY = CursorPosition()
+	s.WheelX, s.WheelY = Wheel()
+	s.Chars = AppendInputChars(nil)
+
+	for _, id := range AppendTouchIDs(nil) {
+		x, y := TouchPosition(id)
+		s.Touches = append(s.Touches, fakeinput.Touch{
+			ID: id,
+			X:  x,
+			Y:  y,
+		})
+	}
+
+	for _, id := range AppendGamepadIDs(nil) {
+		g := fakeinput.Gamepad{
+			ID:      id,
+			Axes:    make([]float64, GamepadAxisNum(id)),
+			Buttons: make([]bool, GamepadButtonNum(id)),
+		}
+		for a := range g.Axes {
+			g.Axes[a] = GamepadAxisValue(id, a)
+		}
+		for b := range g.Buttons {
+			g.Buttons[b] = IsGamepadButtonPressed(id, GamepadButton(b))
+		}
+		if IsStandardGamepadLayoutAvailable(id) {
+			g.StandardLayoutAvailable = true
+			g.StandardAxes = make([]float64, StandardGamepadAxisMax+1)
+			for a := range g.StandardAxes {
+				g.StandardAxes[a] = StandardGamepadAxisValue(id, StandardGamepadAxis(a))
+			}
+			g.StandardButtonValues = make([]float64, StandardGamepadButtonMax+1)
+			g.StandardButtonsPressed = make([]bool, StandardGamepadButtonMax+1)
+			for b := range g.StandardButtonValues {
+				g.StandardButtonValues[b] = StandardGamepadButtonValue(id, StandardGamepadButton(b))
+				g.StandardButtonsPressed[b] = IsStandardGamepadButtonPressed(id, StandardGamepadButton(b))
+			}
+		}
+		s.Gamepads = append(s.Gamepads, g)
+	}
+
+	return s
+}
@@ -0,0 +1,149 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/internal/fakeinput"
+	"github.com/hajimehoshi/ebiten/v2/internal/ui"
+)
+
+// saveFakeTickState returns the tick state with the given fake input states.
+func saveFakeTickState(t *testing.T, s *fakeinput.State) *ebiten.TickState {
+	fakeinput.Enable()
+	defer fakeinput.Disable()
+	fakeinput.SetState(s)
+
+	state, err := ebiten.SaveTickState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return state
+}
+
+func TestTickStateMarshalBinary(t *testing.T) {
+	s := saveFakeTickState(t, &fakeinput.State{
+		Keys:         []ui.Key{ui.KeyA},
+		MouseButtons: []ui.MouseButton{ui.MouseButtonLeft},
+		CursorX:      12,
+		CursorY:      34,
+	})
+
+	data, err := s.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded ebiten.TickState
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := decoded.Tick(), s.Tick(); got != want {
+		t.Errorf("Tick: got: %d, want: %d", got, want)
+	}
+
+	var called bool
+	if err := ebiten.Resimulate([]*ebiten.TickState{&decoded}, func() error {
+		called = true
+		if !ebiten.IsKeyPressed(ebiten.KeyA) {
+			t.Errorf("IsKeyPressed(KeyA): got: false, want: true")
+		}
+		if ebiten.IsKeyPressed(ebiten.KeyB) {
+			t.Errorf("IsKeyPressed(KeyB): got: true, want: false")
+		}
+		if !ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
+			t.Errorf("IsMouseButtonPressed(MouseButtonLeft): got: false, want: true")
+		}
+		if x, y := ebiten.CursorPosition(); x != 12 || y != 34 {
+			t.Errorf("CursorPosition: got: (%d, %d), want: (12, 34)", x, y)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if !called {
+		t.Errorf("update must be called")
+	}
+}
+
+func TestTickStateUnmarshalBinaryInvalid(t *testing.T) {
+	var s ebiten.TickState
+	if err := s.UnmarshalBinary([]byte("invalid")); err == nil {
+		t.Errorf("UnmarshalBinary with invalid data must return an error")
+	}
+}
+
+func TestResimulate(t *testing.T) {
+	s0 := saveFakeTickState(t, &fakeinput.State{Keys: []ui.Key{ui.KeyA}})
+	s1 := saveFakeTickState(t, &fakeinput.State{Keys: []ui.Key{ui.KeyB}})
+	tick := ebiten.CurrentTick()
+
+	var n int
+	if err := ebiten.Resimulate([]*ebiten.TickState{s0, s1}, func() error {
+		if got, want := ebiten.CurrentTick(), tick; got != want {
+			t.Errorf("CurrentTick: got: %d, want: %d", got, want)
+		}
+		switch n {
+		case 0:
+			if !ebiten.IsKeyPressed(ebiten.KeyA) || ebiten.IsKeyPressed(ebiten.KeyB) {
+				t.Errorf("the first state: got: unexpected key states")
+			}
+		case 1:
+			if ebiten.IsKeyPressed(ebiten.KeyA) || !ebiten.IsKeyPressed(ebiten.KeyB) {
+				t.Errorf("the second state: got: unexpected key states")
+			}
+		}
+		n++
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("the number of update calls: got: %d, want: 2", n)
+	}
+
+	// The states must be back to the current tick's.
+	if fakeinput.IsEnabled() {
+		t.Errorf("fakeinput.IsEnabled after Resimulate: got: true, want: false")
+	}
+	if got, want := ebiten.CurrentTick(), tick; got != want {
+		t.Errorf("CurrentTick after Resimulate: got: %d, want: %d", got, want)
+	}
+}
+
+func TestResimulateError(t *testing.T) {
+	s := saveFakeTickState(t, &fakeinput.State{})
+	errTest := errors.New("test")
+
+	var n int
+	err := ebiten.Resimulate([]*ebiten.TickState{s, s, s}, func() error {
+		n++
+		if n == 2 {
+			return errTest
+		}
+		return nil
+	})
+	if err != errTest {
+		t.Errorf("Resimulate: got: %v, want: %v", err, errTest)
+	}
+	if n != 2 {
+		t.Errorf("the number of update calls: got: %d, want: 2", n)
+	}
+	if fakeinput.IsEnabled() {
+		t.Errorf("fakeinput.IsEnabled after Resimulate: got: true, want: false")
+	}
+}
@@ -0,0 +1,291 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vector
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+var (
+	whiteImage    = ebiten.NewImage(3, 3)
+	whiteSubImage = whiteImage.SubImage(image.Rect(1, 1, 2, 2)).(*ebiten.Image)
+)
+
+func init() {
+	whiteImage.Fill(color.White)
+}
+
+// theVertices and theIndices are reused buffers for the shape functions.
+// DrawTriangles doesn't retain the given slices, then reusing them is safe.
+var (
+	theVertices []ebiten.Vertex
+	theIndices  []uint16
+	theBuffersM sync.Mutex
+)
+
+func colorToStraightAlpha(clr color.Color) (r, g, b, a float32) {
+	cr, cg, cb, ca := clr.RGBA()
+	if ca == 0 {
+		return 0, 0, 0, 0
+	}
+	return float32(cr) / float32(ca), float32(cg) / float32(ca), float32(cb) / float32(ca), float32(ca) / 0xffff
+}
+
+// featherWidths returns the half widths of the opaque part and the entire part, and the alpha of the opaque part,
+// for a stroke with the half width hw.
+//
+// With anti-aliasing, the alpha fades out linearly in the 1 pixel width around the edges.
+// A stroke thinner than 1 pixel is rendered as a fainter stroke so that the total coverage is kept.
+func featherWidths(hw float32, antialias bool) (inner, outer, alpha float32) {
+	if !antialias {
+		return hw, hw, 1
+	}
+	const f = 0.5
+	if hw < f {
+		return 0, hw + f, 2 * hw / (hw + f)
+	}
+	return hw - f, hw + f, 1
+}
+
+func appendVertex(vs []ebiten.Vertex, x, y float32, r, g, b, a float32) []ebiten.Vertex {
+	return append(vs, ebiten.Vertex{
+		DstX:   x,
+		DstY:   y,
+		SrcX:   1,
+		SrcY:   1,
+		ColorR: r,
+		ColorG: g,
+		ColorB: b,
+		ColorA: a,
+	})
+}
+
+// appendStripIndices appends the indices of quads between the ring of n vertices starting at base0 and
+// the ring of n vertices starting at base1.
+// If closed is true, the last vertices are connected to the first vertices.
+func appendStripIndices(is []uint16, base0, base1 uint16, n int, closed bool) []uint16 {
+	m := n - 1
+	if closed {
+		m = n
+	}
+	for i := 0; i < m; i++ {
+		i0 := uint16(i)
+		i1 := uint16((i + 1) % n)
+		is = append(is, base0+i0, base0+i1, base1+i0, base0+i1, base1+i0, base1+i1)
+	}
+	return is
+}
+
+func drawTriangles(dst *ebiten.Image, vs []ebiten.Vertex, is []uint16) {
+	dst.DrawTriangles(vs, is, whiteSubImage, nil)
+}
+
+// StrokeLine strokes a line segment from (x0, y0) to (x1, y1) with the specified width and color.
+//
+// If antialias is true, the edges are feathered so that the line doesn't look jagged.
+// The ends of the line are flat and don't extend beyond the end points.
+func StrokeLine(dst *ebiten.Image, x0, y0, x1, y1 float32, strokeWidth float32, clr color.Color, antialias bool) {
+	dx, dy := x1-x0, y1-y0
+	l := float32(math.Hypot(float64(dx), float64(dy)))
+	if l == 0 || strokeWidth <= 0 {
+		return
+	}
+	ux, uy := dx/l, dy/l
+	nx, ny := -uy, ux
+	mx, my := (x0+x1)/2, (y0+y1)/2
+
+	cr, cg, cb, ca := colorToStraightAlpha(clr)
+
+	// Both the width and the length are feathered, so the vertices make a 4x4 grid.
+	wi, wo, wa := featherWidths(strokeWidth/2, antialias)
+	li, lo, la := featherWidths(l/2, antialias)
+	across := [...]float32{-wo, -wi, wi, wo}
+	acrossAlpha := [...]float32{0, wa, wa, 0}
+	along := [...]float32{-lo, -li, li, lo}
+	alongAlpha := [...]float32{0, la, la, 0}
+
+	theBuffersM.Lock()
+	defer theBuffersM.Unlock()
+
+	vs := theVertices[:0]
+	is := theIndices[:0]
+	for i, s := range along {
+		for j, t := range across {
+			x := mx + ux*s + nx*t
+			y := my + uy*s + ny*t
+			vs = appendVertex(vs, x, y, cr, cg, cb, ca*alongAlpha[i]*acrossAlpha[j])
+		}
+	}
+	for i := 0; i < len(along)-1; i++ {
+		is = appendStripIndices(is, uint16(i*len(across)), uint16((i+1)*len(across)), len(across), false)
+	}
+
+	drawTriangles(dst, vs, is)
+	theVertices, theIndices = vs, is
+}
+
+// StrokeRect strokes the outline of the rectangle (x, y, width, height) with the specified width and color.
+//
+// The outline is at the center of the stroke, and the corners are sharp.
+//
+// If antialias is true, the edges are feathered so that the rectangle doesn't look jagged when it is not
+// aligned with pixels.
+func StrokeRect(dst *ebiten.Image, x, y, width, height float32, strokeWidth float32, clr color.Color, antialias bool) {
+	if width <= 0 || height <= 0 || strokeWidth <= 0 {
+		return
+	}
+
+	cr, cg, cb, ca := colorToStraightAlpha(clr)
+
+	wi, wo, wa := featherWidths(strokeWidth/2, antialias)
+	// Do not let the inner edges cross each other.
+	maxInset := math32Min(width, height) / 2
+	offsets := [...]float32{-math32Min(wo, maxInset), -math32Min(wi, maxInset), wi, wo}
+	alphas := [...]float32{0, wa, wa, 0}
+
+	theBuffersM.Lock()
+	defer theBuffersM.Unlock()
+
+	vs := theVertices[:0]
+	is := theIndices[:0]
+	for k, o := range offsets {
+		a := ca * alphas[k]
+		vs = appendVertex(vs, x-o, y-o, cr, cg, cb, a)
+		vs = appendVertex(vs, x+width+o, y-o, cr, cg, cb, a)
+		vs = appendVertex(vs, x+width+o, y+height+o, cr, cg, cb, a)
+		vs = appendVertex(vs, x-o, y+height+o, cr, cg, cb, a)
+	}
+	for k := 0; k < len(offsets)-1; k++ {
+		is = appendStripIndices(is, uint16(4*k), uint16(4*(k+1)), 4, true)
+	}
+
+	drawTriangles(dst, vs, is)
+	theVertices, theIndices = vs, is
+}
+
+func math32Min(a, b float32) float32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// circleSegmentCount returns the number of the segments to approximate a circle with the radius r.
+func circleSegmentCount(r float32) int {
+	// Each segment is about 2 pixels long.
+	n := int(math.Ceil(math.Pi * float64(r)))
+	if n < 16 {
+		n = 16
+	}
+	if n > 2048 {
+		n = 2048
+	}
+	return n
+}
+
+func appendRing(vs []ebiten.Vertex, cx, cy, r float32, n int, cr, cg, cb, ca float32) []ebiten.Vertex {
+	for i := 0; i < n; i++ {
+		theta := 2 * math.Pi * float64(i) / float64(n)
+		x := cx + r*float32(math.Cos(theta))
+		y := cy + r*float32(math.Sin(theta))
+		vs = appendVertex(vs, x, y, cr, cg, cb, ca)
+	}
+	return vs
+}
+
+// StrokeCircle strokes a circle with the center (cx, cy), the radius r, the specified width and color.
+//
+// If antialias is true, the edges are feathered so that the circle doesn't look jagged.
+func StrokeCircle(dst *ebiten.Image, cx, cy, r float32, strokeWidth float32, clr color.Color, antialias bool) {
+	if r <= 0 || strokeWidth <= 0 {
+		return
+	}
+
+	cr, cg, cb, ca := colorToStraightAlpha(clr)
+
+	wi, wo, wa := featherWidths(strokeWidth/2, antialias)
+	radii := [...]float32{r - wo, r - wi, r + wi, r + wo}
+	for i := range radii {
+		if radii[i] < 0 {
+			radii[i] = 0
+		}
+	}
+	alphas := [...]float32{0, wa, wa, 0}
+	n := circleSegmentCount(r + wo)
+
+	theBuffersM.Lock()
+	defer theBuffersM.Unlock()
+
+	vs := theVertices[:0]
+	is := theIndices[:0]
+	for k, rr := range radii {
+		vs = appendRing(vs, cx, cy, rr, n, cr, cg, cb, ca*alphas[k])
+	}
+	for k := 0; k < len(radii)-1; k++ {
+		is = appendStripIndices(is, uint16(n*k), uint16(n*(k+1)), n, true)
+	}
+
+	drawTriangles(dst, vs, is)
+	theVertices, theIndices = vs, is
+}
+
+// FillCircle fills a circle with the center (cx, cy), the radius r and the specified color.
+//
+// If antialias is true, the edge is feathered so that the circle doesn't look jagged.
+func FillCircle(dst *ebiten.Image, cx, cy, r float32, clr color.Color, antialias bool) {
+	if r <= 0 {
+		return
+	}
+
+	cr, cg, cb, ca := colorToStraightAlpha(clr)
+
+	ri, ro, ra := featherWidths(r, antialias)
+	n := circleSegmentCount(ro)
+
+	theBuffersM.Lock()
+	defer theBuffersM.Unlock()
+
+	vs := theVertices[:0]
+	is := theIndices[:0]
+	vs = appendVertex(vs, cx, cy, cr, cg, cb, ca*ra)
+	vs = appendRing(vs, cx, cy, ri, n, cr, cg, cb, ca*ra)
+	vs = appendRing(vs, cx, cy, ro, n, cr, cg, cb, 0)
+	for i := 0; i < n; i++ {
+		is = append(is, 0, uint16(1+i), uint16(1+(i+1)%n))
+	}
+	is = appendStripIndices(is, 1, uint16(1+n), n, true)
+
+	drawTriangles(dst, vs, is)
+	theVertices, theIndices = vs, is
+}
+
+// FillPathWithGradient fills the path with the gradient g by the EvenOdd fill rule.
+//
+// The gradient is evaluated on GPU, so no gradient texture is allocated.
+func FillPathWithGradient(dst *ebiten.Image, path *Path, g *ebiten.Gradient) {
+	theBuffersM.Lock()
+	defer theBuffersM.Unlock()
+
+	vs, is := path.AppendVerticesAndIndicesForFilling(theVertices[:0], theIndices[:0])
+	dst.DrawTrianglesGradient(vs, is, g, &ebiten.DrawTrianglesGradientOptions{
+		FillRule: ebiten.EvenOdd,
+	})
+	theVertices, theIndices = vs, is
+}
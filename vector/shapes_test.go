@@ -0,0 +1,132 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vector
+
+import (
+	"image/color"
+	"math"
+	"testing"
+)
+
+func TestFeatherWidths(t *testing.T) {
+	tests := []struct {
+		hw        float32
+		antialias bool
+		inner     float32
+		outer     float32
+		alpha     float32
+	}{
+		{hw: 2, antialias: false, inner: 2, outer: 2, alpha: 1},
+		{hw: 0.25, antialias: false, inner: 0.25, outer: 0.25, alpha: 1},
+		{hw: 2, antialias: true, inner: 1.5, outer: 2.5, alpha: 1},
+		{hw: 0.5, antialias: true, inner: 0, outer: 1, alpha: 1},
+		{hw: 0.25, antialias: true, inner: 0, outer: 0.75, alpha: 2.0 / 3.0},
+	}
+	for _, tc := range tests {
+		inner, outer, alpha := featherWidths(tc.hw, tc.antialias)
+		if inner != tc.inner || outer != tc.outer || math.Abs(float64(alpha-tc.alpha)) > 1e-6 {
+			t.Errorf("featherWidths(%f, %t): got: (%f, %f, %f), want: (%f, %f, %f)", tc.hw, tc.antialias, inner, outer, alpha, tc.inner, tc.outer, tc.alpha)
+		}
+	}
+}
+
+func TestFeatherWidthsCoverage(t *testing.T) {
+	// The total coverage of a stroke must be kept regardless of anti-aliasing.
+	for _, hw := range []float32{0.1, 0.25, 0.5, 1, 3} {
+		inner, outer, alpha := featherWidths(hw, true)
+		// The alpha is constant in the opaque part and fades out linearly in the feathered part.
+		got := alpha * (inner + outer)
+		if want := 2 * hw; math.Abs(float64(got-want)) > 1e-6 {
+			t.Errorf("coverage for %f: got: %f, want: %f", hw, got, want)
+		}
+	}
+}
+
+func TestAppendStripIndices(t *testing.T) {
+	if got, want := appendStripIndices(nil, 0, 3, 3, false), []uint16{0, 1, 3, 1, 3, 4, 1, 2, 4, 2, 4, 5}; !areSameIndices(got, want) {
+		t.Errorf("open strip: got: %v, want: %v", got, want)
+	}
+	if got, want := appendStripIndices(nil, 0, 3, 3, true), []uint16{0, 1, 3, 1, 3, 4, 1, 2, 4, 2, 4, 5, 2, 0, 5, 0, 5, 3}; !areSameIndices(got, want) {
+		t.Errorf("closed strip: got: %v, want: %v", got, want)
+	}
+	if got, want := appendStripIndices([]uint16{9}, 4, 8, 2, false), []uint16{9, 4, 5, 8, 5, 8, 9}; !areSameIndices(got, want) {
+		t.Errorf("appended strip: got: %v, want: %v", got, want)
+	}
+}
+
+func areSameIndices(a, b []uint16) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestCircleSegmentCount(t *testing.T) {
+	tests := []struct {
+		r    float32
+		want int
+	}{
+		{r: 0.5, want: 16},
+		{r: 5, want: 16},
+		{r: 10, want: 32},
+		{r: 100, want: 315},
+		{r: 10000, want: 2048},
+	}
+	for _, tc := range tests {
+		if got := circleSegmentCount(tc.r); got != tc.want {
+			t.Errorf("circleSegmentCount(%f): got: %d, want: %d", tc.r, got, tc.want)
+		}
+	}
+}
+
+func TestAppendRing(t *testing.T) {
+	const n = 8
+	vs := appendRing(nil, 10, 20, 5, n, 1, 0.5, 0.25, 1)
+	if len(vs) != n {
+		t.Fatalf("len(vs): got: %d, want: %d", len(vs), n)
+	}
+	for i, v := range vs {
+		d := math.Hypot(float64(v.DstX-10), float64(v.DstY-20))
+		if math.Abs(d-5) > 1e-4 {
+			t.Errorf("distance of vertex %d: got: %f, want: 5", i, d)
+		}
+		if v.ColorR != 1 || v.ColorG != 0.5 || v.ColorB != 0.25 || v.ColorA != 1 {
+			t.Errorf("color of vertex %d: got: (%f, %f, %f, %f), want: (1, 0.5, 0.25, 1)", i, v.ColorR, v.ColorG, v.ColorB, v.ColorA)
+		}
+	}
+}
+
+func TestColorToStraightAlpha(t *testing.T) {
+	tests := []struct {
+		c          color.Color
+		r, g, b, a float32
+	}{
+		{c: color.RGBA{0, 0, 0, 0}, r: 0, g: 0, b: 0, a: 0},
+		{c: color.White, r: 1, g: 1, b: 1, a: 1},
+		{c: color.RGBA{0x80, 0, 0x40, 0x80}, r: 1, g: 0, b: 0.5, a: float32(0x8080) / 0xffff},
+		{c: color.NRGBA{0xff, 0, 0, 0x80}, r: 1, g: 0, b: 0, a: float32(0x8080) / 0xffff},
+	}
+	for _, tc := range tests {
+		r, g, b, a := colorToStraightAlpha(tc.c)
+		if math.Abs(float64(r-tc.r)) > 1e-2 || math.Abs(float64(g-tc.g)) > 1e-2 || math.Abs(float64(b-tc.b)) > 1e-2 || math.Abs(float64(a-tc.a)) > 1e-6 {
+			t.Errorf("colorToStraightAlpha(%v): got: (%f, %f, %f, %f), want: (%f, %f, %f, %f)", tc.c, r, g, b, a, tc.r, tc.g, tc.b, tc.a)
+		}
+	}
+}
@@ -54,6 +54,31 @@ func Vibrate(options *VibrateOptions) {
 	vibrate.Vibrate(options.Duration, options.Magnitude)
 }
 
+// VibratePatternOptions represents the options for a device vibration pattern.
+type VibratePatternOptions struct {
+	// Steps is the sequence of the vibrations.
+	// A step with zero Magnitude represents a pause.
+	Steps []VibrateOptions
+}
+
+// VibratePattern vibrates the device with the specified pattern.
+//
+// The same restrictions as Vibrate are applied.
+// In addition, on browsers, the magnitudes are ignored and a step with non-zero magnitude vibrates at the
+// device's default strength.
+// On Android, when the API Level is older than 26, the magnitudes are ignored in the same way.
+//
+// VibratePattern is concurrent-safe.
+func VibratePattern(options *VibratePatternOptions) {
+	durations := make([]time.Duration, len(options.Steps))
+	magnitudes := make([]float64, len(options.Steps))
+	for i, s := range options.Steps {
+		durations[i] = s.Duration
+		magnitudes[i] = s.Magnitude
+	}
+	vibrate.VibratePattern(durations, magnitudes)
+}
+
 // VibrateGamepadOptions represents the options for gamepad vibration.
 type VibrateGamepadOptions struct {
 	// Duration is the time duration of the effect.
@@ -66,6 +91,20 @@ type VibrateGamepadOptions struct {
 	// StrongMagnitude is the rumble intensity of a high-frequency rumble motor.
 	// The value is in between 0 and 1.
 	WeakMagnitude float64
+
+	// LeftTriggerMagnitude is the rumble intensity of the motor in the left trigger.
+	// The value is in between 0 and 1.
+	//
+	// Trigger motors are available only on some gamepads like Xbox One controllers via GameInput on Windows.
+	// LeftTriggerMagnitude is ignored on the other gamepads.
+	LeftTriggerMagnitude float64
+
+	// RightTriggerMagnitude is the rumble intensity of the motor in the right trigger.
+	// The value is in between 0 and 1.
+	//
+	// Trigger motors are available only on some gamepads like Xbox One controllers via GameInput on Windows.
+	// RightTriggerMagnitude is ignored on the other gamepads.
+	RightTriggerMagnitude float64
 }
 
 // VibrateGamepad vibrates the specified gamepad with the specified options.
@@ -76,5 +115,5 @@ func VibrateGamepad(gamepadID GamepadID, options *VibrateGamepadOptions) {
 	if g == nil {
 		return
 	}
-	g.Vibrate(options.Duration, options.StrongMagnitude, options.WeakMagnitude)
+	g.Vibrate(options.Duration, options.StrongMagnitude, options.WeakMagnitude, options.LeftTriggerMagnitude, options.RightTriggerMagnitude)
 }
@@ -42,6 +42,34 @@ const (
 	WindowResizingModeEnabled WindowResizingModeType = WindowResizingModeType(ui.WindowResizingModeEnabled)
 )
 
+// WindowBehaviorType represents flags of default behaviors of the window, which are disabled by default.
+//
+// On Windows, F10 and Alt never activate the window menu, and media keys are always passed to the system,
+// so there are no flags for them.
+type WindowBehaviorType = ui.WindowBehavior
+
+// WindowBehaviorTypes
+const (
+	// WindowBehaviorAltEnterFullscreen indicates that pressing Alt+Enter toggles fullscreen on desktops and browsers.
+	WindowBehaviorAltEnterFullscreen WindowBehaviorType = WindowBehaviorType(ui.WindowBehaviorAltEnterFullscreen)
+)
+
+// WindowBehavior returns the current flags of the default behaviors of the window.
+//
+// WindowBehavior is concurrent-safe.
+func WindowBehavior() WindowBehaviorType {
+	return WindowBehaviorType(ui.GetWindowBehavior())
+}
+
+// SetWindowBehavior sets the flags of the default behaviors of the window.
+// The flags can be combined with the OR operator, like WindowBehaviorAltEnterFullscreen.
+// The default value is 0, which enables none of the behaviors.
+//
+// SetWindowBehavior is concurrent-safe.
+func SetWindowBehavior(behavior WindowBehaviorType) {
+	ui.SetWindowBehavior(ui.WindowBehavior(behavior))
+}
+
 // IsWindowDecorated reports whether the window is decorated.
 //
 // IsWindowDecorated is concurrent-safe.
@@ -176,6 +204,79 @@ func initializeWindowPositionIfNeeded(width, height int) {
 	}
 }
 
+// WindowGeometryState represents the placement of the window.
+//
+// WindowGeometryState can be serialized e.g. with encoding/json, and is useful to remember the window layout
+// between sessions.
+type WindowGeometryState struct {
+	// Monitor is the name of the monitor the window is on.
+	Monitor string
+
+	// X and Y are the window position relative to the left-upper corner of the monitor.
+	// The unit is device-independent pixels.
+	X int
+	Y int
+
+	// Width and Height are the window size.
+	// The unit is device-independent pixels.
+	// In fullscreen mode, Width and Height are the original window size.
+	Width  int
+	Height int
+
+	// Maximized indicates whether the window is maximized.
+	Maximized bool
+
+	// Fullscreen indicates whether the window is fullscreen.
+	Fullscreen bool
+}
+
+// WindowGeometry returns the current placement of the window.
+//
+// WindowGeometry panics if the main loop does not start yet.
+//
+// WindowGeometry returns the zero value on browsers and mobiles.
+//
+// WindowGeometry is concurrent-safe.
+func WindowGeometry() WindowGeometryState {
+	g := ui.Get().Window().Geometry()
+	return WindowGeometryState{
+		Monitor:    g.Monitor,
+		X:          g.X,
+		Y:          g.Y,
+		Width:      g.Width,
+		Height:     g.Height,
+		Maximized:  g.Maximized,
+		Fullscreen: g.Fullscreen,
+	}
+}
+
+// RestoreWindowGeometry restores the placement of the window from g.
+//
+// If the monitor g.Monitor is not found, the window is placed on the current monitor.
+// Before the main loop starts, g.Monitor is ignored and the window is placed on the initial monitor.
+//
+// g.Maximized is ignored when the window is not resizable (WindowResizingModeEnabled).
+//
+// RestoreWindowGeometry panics if g.Width or g.Height is not positive.
+//
+// RestoreWindowGeometry does nothing on browsers and mobiles.
+//
+// RestoreWindowGeometry is concurrent-safe.
+func RestoreWindowGeometry(g WindowGeometryState) {
+	if g.Width <= 0 || g.Height <= 0 {
+		panic("ebiten: g.Width and g.Height must be positive")
+	}
+	ui.Get().Window().SetGeometry(ui.WindowGeometry{
+		Monitor:    g.Monitor,
+		X:          g.X,
+		Y:          g.Y,
+		Width:      g.Width,
+		Height:     g.Height,
+		Maximized:  g.Maximized,
+		Fullscreen: g.Fullscreen,
+	})
+}
+
 // WindowSize returns the window size on desktops.
 // WindowSize returns (0, 0) on other environments.
 //
@@ -217,6 +318,25 @@ func SetWindowSizeLimits(minw, minh, maxw, maxh int) {
 	ui.Get().Window().SetSizeLimits(minw, minh, maxw, maxh)
 }
 
+// WindowAspectRatio returns the aspect ratio of the window size kept while the user resizes the window on desktops.
+// WindowAspectRatio returns (0, 0) when the aspect ratio is not limited.
+//
+// WindowAspectRatio is concurrent-safe.
+func WindowAspectRatio() (width, height int) {
+	return ui.Get().Window().AspectRatio()
+}
+
+// SetWindowAspectRatio sets the aspect ratio of the window size kept while the user resizes the window on desktops.
+// For example, SetWindowAspectRatio(16, 9) keeps the window size 16:9.
+// A non-positive value indicates the aspect ratio is not limited.
+//
+// The aspect ratio is applied only to resizing by the user. SetWindowSize and fullscreen are not affected.
+//
+// SetWindowAspectRatio is concurrent-safe.
+func SetWindowAspectRatio(width, height int) {
+	ui.Get().Window().SetAspectRatio(width, height)
+}
+
 // IsWindowFloating reports whether the window is always shown above all the other windows.
 //
 // IsWindowFloating returns false on browsers and mobiles.